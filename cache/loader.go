@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loadGroup collapses concurrent misses on the same key into a single load. Its keys are scoped to the loaded
+// type by flightKey, so callers that read one cache key as different types never share a result.
+var loadGroup singleflight.Group
+
+// flightKey is the loadGroup key for loading a T into key
+func flightKey[T any](key string) string {
+	return reflect.TypeFor[T]().String() + "|" + key
+}
+
+// loadedAs returns a shared load's result as a T, and an error rather than the zero value when it is another type
+func loadedAs[T any](key string, result interface{}) (T, error) {
+	var zero T
+	if result == nil {
+		return zero, nil
+	}
+	value, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("cache: load of key '%s' returned %T, want %s", key, result, reflect.TypeFor[T]())
+	}
+	return value, nil
+}
+
+// GetOrLoad returns the JSON value cached under key, or calls load, caches its result for ttl and returns it.
+// The bool reports whether the value came from the cache. Concurrent misses on a key share one load, run with
+// the context of the first caller, so the loaded value may be handed to several callers and must not be mutated.
+// Cache failures are logged and treated as misses; only load errors are returned.
+func GetOrLoad[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, bool, error) {
+	if value, ok := getJSON[T](ctx, c, key); ok {
+		return value, true, nil
+	}
+
+	result, err, _ := loadGroup.Do(flightKey[T](key), func() (interface{}, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			log.Printf("Cache ERROR: Failed to encode value for key '%s': %v", key, err)
+			return value, nil
+		}
+		// errors are logged by Set, the caller still gets the loaded value
+		_ = c.Set(ctx, key, data, ttl)
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	value, err := loadedAs[T](key, result)
+	return value, false, err
+}
+
+func getJSON[T any](ctx context.Context, c Cache, key string) (T, bool) {
+	var value T
+	cached, err := c.Get(ctx, key)
+	if err != nil || cached == nil {
+		return value, false
+	}
+	cachedStr, ok := cached.(string)
+	if !ok {
+		log.Printf("Cache ERROR: Unexpected type %T for key '%s'", cached, key)
+		return value, false
+	}
+	if err := json.Unmarshal([]byte(cachedStr), &value); err != nil {
+		log.Printf("Cache ERROR: Failed to decode key '%s': %v", key, err)
+		return value, false
+	}
+	return value, true
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrLockNotAcquired is returned by AcquireLock when another owner holds the lock
+var ErrLockNotAcquired = errors.New("lock is held by another owner")
+
+// releaseScript deletes the lock only if it still carries our token, so an owner whose lock expired
+// can never release a lock that has since been taken by someone else
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock is a held distributed lock; it expires on its own after the TTL given to AcquireLock
+type Lock struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+// AcquireLock takes the lock at key for ttl using SET NX PX with a random owner token.
+// It returns ErrLockNotAcquired when the lock is already held.
+func (r *RedisCache) AcquireLock(ctx context.Context, key string, ttl time.Duration) (_ *Lock, err error) {
+	defer observe(key, time.Now(), &err)
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %v", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	ok, err := r.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to acquire lock '%s': %v", key, err)
+		return nil, fmt.Errorf("failed to acquire lock %s: %v", key, err)
+	}
+	if !ok {
+		return nil, ErrLockNotAcquired
+	}
+	return &Lock{client: r.client, key: key, token: token}, nil
+}
+
+// Release frees the lock if this owner still holds it
+func (l *Lock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err(); err != nil {
+		log.Printf("Cache ERROR: Failed to release lock '%s': %v", l.key, err)
+		return fmt.Errorf("failed to release lock %s: %v", l.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	"xcode/metrics"
+)
+
+// Metrics are labelled by key family, the part of the key before the first ':' (e.g. "problems_list", "stats")
+var (
+	hitsTotal       = metrics.NewCounterVec("cache_hits_total", "family")
+	missesTotal     = metrics.NewCounterVec("cache_misses_total", "family")
+	localHitsTotal  = metrics.NewCounterVec("cache_l1_hits_total", "family")
+	errorsTotal     = metrics.NewCounterVec("cache_errors_total", "family")
+	operationsTotal = metrics.NewCounterVec("cache_operations_total", "family")
+
+	// latencySeconds is the duration of each Redis round trip, from 100µs up since most take well under 5ms
+	latencySeconds = metrics.NewHistogramVec("cache_latency_seconds",
+		[]float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}, "family")
+)
+
+// pipelineFamily labels pipelines, which usually span several families
+const pipelineFamily = "pipeline"
+
+func keyFamily(key string) string {
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// observe records one Redis round trip; it is deferred with a pointer to the named error result
+func observe(key string, start time.Time, err *error) {
+	family := keyFamily(key)
+	operationsTotal.Add(family, 1)
+	latencySeconds.ObserveDuration(start, family)
+	if *err != nil {
+		errorsTotal.Add(family, 1)
+	}
+}
+
+func recordLookup(key string, hit bool) {
+	if hit {
+		hitsTotal.Add(keyFamily(key), 1)
+	} else {
+		missesTotal.Add(keyFamily(key), 1)
+	}
+}
+
+// LookupCounts returns the cache hits, in memory or in Redis, and misses of this process since it started
+func LookupCounts() (hits, misses int64) {
+	return hitsTotal.Sum() + localHitsTotal.Sum(), missesTotal.Sum()
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// tokenBucketScript refills every bucket in KEYS for the time passed since its last call, then takes one token from
+// each of them, or from none when any is empty, so a request refused by one bucket does not drain the others.
+// It returns {1, 0} when the tokens were taken and {0, ms until every bucket has a token} otherwise.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local tokens = {}
+local wait = 0
+for i, key in ipairs(KEYS) do
+	local state = redis.call("HMGET", key, "tokens", "ts")
+	local available = tonumber(state[1]) or burst
+	local ts = tonumber(state[2]) or now
+	available = math.min(burst, available + math.max(0, now - ts) / 1000 * rate)
+	if available < 1 then
+		wait = math.max(wait, math.ceil((1 - available) / rate * 1000))
+	end
+	tokens[i] = available
+end
+local allowed = 0
+if wait == 0 then
+	allowed = 1
+end
+for i, key in ipairs(KEYS) do
+	if allowed == 1 then
+		tokens[i] = tokens[i] - 1
+	end
+	redis.call("HSET", key, "tokens", tostring(tokens[i]), "ts", now)
+	redis.call("PEXPIRE", key, math.ceil(burst / rate * 1000) + 1000)
+end
+return {allowed, wait}
+`)
+
+// TakeToken takes one token from the bucket at key, which holds up to burst tokens and refills at rate per second.
+// When the bucket is empty it reports how long until the next token is available.
+func (r *RedisCache) TakeToken(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	return r.TakeTokens(ctx, []string{key}, rate, burst)
+}
+
+// TakeTokens is TakeToken for several buckets at once: a token is taken from each of them only when all of them
+// have one, and otherwise it reports how long until they all do
+func (r *RedisCache) TakeTokens(ctx context.Context, keys []string, rate float64, burst int) (_ bool, _ time.Duration, err error) {
+	if len(keys) == 0 {
+		return true, 0, nil
+	}
+	defer observe(keys[0], time.Now(), &err)
+	result, err := tokenBucketScript.Run(ctx, r.client, keys, rate, burst, time.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to take token from buckets %v: %v", keys, err)
+		return false, 0, fmt.Errorf("failed to take token from buckets %v: %v", keys, err)
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -2,72 +2,273 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"time"
 
+	"xcode/tracing"
+
 	"github.com/go-redis/redis/v8"
 )
 
+// Cache is the key/value store the service caches responses and leaderboard reads in.
+// Every call takes the caller's context so a cancelled request stops waiting on Redis.
+type Cache interface {
+	Get(ctx context.Context, key string) (interface{}, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+	DeletePattern(ctx context.Context, pattern string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error)
+	ZCard(ctx context.Context, key string) (int64, error)
+	HMGet(ctx context.Context, key string, fields ...string) ([]interface{}, error)
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) error
+	SAdd(ctx context.Context, key string, members ...interface{}) error
+	SPopN(ctx context.Context, key string, count int64) ([]string, error)
+	AcquireLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error)
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+	TakeTokens(ctx context.Context, keys []string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+var _ Cache = (*RedisCache)(nil)
+
+// deletePatternBatch is the SCAN page size and the number of keys deleted per pipeline
+const deletePatternBatch = 500
+
 type RedisCache struct {
 	client *redis.Client
 }
 
-func NewRedisCache(addr, password string, db int) *RedisCache {
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
+// Options configures the Redis connection behind the cache
+type Options struct {
+	Addr     string
+	Password string
+	// PasswordFunc, when set, replaces Password and is asked again for every new connection, so a rotated password
+	// is picked up without a restart
+	PasswordFunc func() string
+	DB           int
+	TLS          bool
+	PoolSize     int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// MaxRetries is how often a command is retried on network errors, backing off between MinRetryBackoff and MaxRetryBackoff
+	MaxRetries      int
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+const (
+	startupPingAttempts = 5
+	startupPingBackoff  = 500 * time.Millisecond
+)
+
+// NewRedisCache connects to Redis and fails if it cannot be reached after a few attempts,
+// so a misconfigured address or password surfaces at startup rather than on the first request
+func NewRedisCache(opts Options) (*RedisCache, error) {
+	redisOpts := &redis.Options{
+		Addr:            opts.Addr,
+		Password:        opts.Password,
+		DB:              opts.DB,
+		PoolSize:        opts.PoolSize,
+		DialTimeout:     opts.DialTimeout,
+		ReadTimeout:     opts.ReadTimeout,
+		WriteTimeout:    opts.WriteTimeout,
+		MaxRetries:      opts.MaxRetries,
+		MinRetryBackoff: opts.MinRetryBackoff,
+		MaxRetryBackoff: opts.MaxRetryBackoff,
+	}
+	if opts.PasswordFunc != nil {
+		redisOpts.Password = ""
+		redisOpts.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+			if password := opts.PasswordFunc(); password != "" {
+				return cn.Auth(ctx, password).Err()
+			}
+			return nil
+		}
+	}
+	if opts.TLS {
+		redisOpts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	r := &RedisCache{client: redis.NewClient(redisOpts)}
+	r.client.AddHook(tracing.RedisHook{})
+
+	backoff := startupPingBackoff
+	var err error
+	for attempt := 1; attempt <= startupPingAttempts; attempt++ {
+		if err = r.Ping(context.Background()); err == nil {
+			return r, nil
+		}
+		log.Printf("Cache: Redis not reachable at %s (attempt %d/%d): %v", opts.Addr, attempt, startupPingAttempts, err)
+		if attempt < startupPingAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	r.client.Close()
+	return nil, err
+}
+
+// Ping reports whether Redis is reachable
+func (r *RedisCache) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %v", err)
+	}
+	return nil
+}
 
-	return &RedisCache{client: client}
+// Close releases the connection pool
+func (r *RedisCache) Close() error {
+	return r.client.Close()
 }
 
-func (r *RedisCache) Set(key string, value interface{}, expiration time.Duration) error {
-	log.Printf("Cache: Setting key '%s' with expiration %v", key, expiration)
-	err := r.client.Set(context.Background(), key, value, expiration).Err()
+func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) (err error) {
+	defer observe(key, time.Now(), &err)
+	err = r.client.Set(ctx, key, value, expiration).Err()
 	if err != nil {
 		log.Printf("Cache ERROR: Failed to set key '%s': %v", key, err)
 		return fmt.Errorf("failed to set key %s in cache: %v", key, err)
 	}
-	log.Printf("Cache: Successfully set key '%s'", key)
 	return nil
 }
 
-func (r *RedisCache) Get(key string) (interface{}, error) {
-	log.Printf("Cache: Attempting to get key '%s'", key)
-	val, err := r.client.Get(context.Background(), key).Result()
+func (r *RedisCache) Get(ctx context.Context, key string) (_ interface{}, err error) {
+	defer observe(key, time.Now(), &err)
+	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		log.Printf("Cache MISS: Key '%s' not found", key)
+		recordLookup(key, false)
 		return nil, nil
 	}
 	if err != nil {
 		log.Printf("Cache ERROR: Failed to get key '%s': %v", key, err)
 		return nil, fmt.Errorf("failed to get key %s from cache: %v", key, err)
 	}
-	log.Printf("Cache HIT: Successfully retrieved key '%s'", key)
+	recordLookup(key, true)
 	return val, nil
 }
 
-func (r *RedisCache) Delete(key string) error {
-	log.Printf("Cache: Attempting to delete key '%s'", key)
-	err := r.client.Del(context.Background(), key).Err()
+func (r *RedisCache) Delete(ctx context.Context, key string) (err error) {
+	defer observe(key, time.Now(), &err)
+	err = r.client.Del(ctx, key).Err()
 	if err != nil {
 		log.Printf("Cache ERROR: Failed to delete key '%s': %v", key, err)
 		return fmt.Errorf("failed to delete key %s from cache: %v", key, err)
 	}
-	log.Printf("Cache: Successfully deleted key '%s'", key)
 	return nil
 }
 
-func (r *RedisCache) Exists(key string) (bool, error) {
-	log.Printf("Cache: Checking existence of key '%s'", key)
-	result, err := r.client.Exists(context.Background(), key).Result()
+// DeletePattern deletes every key matching a glob pattern such as "problems_list:*".
+// Keys are found with SCAN so Redis is never blocked the way KEYS would block it.
+func (r *RedisCache) DeletePattern(ctx context.Context, pattern string) (err error) {
+	defer observe(pattern, time.Now(), &err)
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, deletePatternBatch).Result()
+		if err != nil {
+			log.Printf("Cache ERROR: Failed to scan keys matching '%s': %v", pattern, err)
+			return fmt.Errorf("failed to scan keys matching %s: %v", pattern, err)
+		}
+		if len(keys) > 0 {
+			_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.Del(ctx, key)
+				}
+				return nil
+			})
+			if err != nil {
+				log.Printf("Cache ERROR: Failed to delete keys matching '%s': %v", pattern, err)
+				return fmt.Errorf("failed to delete keys matching %s: %v", pattern, err)
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+func (r *RedisCache) Exists(ctx context.Context, key string) (_ bool, err error) {
+	defer observe(key, time.Now(), &err)
+	result, err := r.client.Exists(ctx, key).Result()
 	if err != nil {
 		log.Printf("Cache ERROR: Failed to check existence of key '%s': %v", key, err)
 		return false, fmt.Errorf("failed to check existence of key %s in cache: %v", key, err)
 	}
 	exists := result > 0
-	log.Printf("Cache: Key '%s' exists: %v", key, exists)
 	return exists, nil
 }
+
+func (r *RedisCache) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) (_ []redis.Z, err error) {
+	defer observe(key, time.Now(), &err)
+	members, err := r.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to fetch range of sorted set '%s': %v", key, err)
+		return nil, fmt.Errorf("failed to fetch range of sorted set %s: %v", key, err)
+	}
+	return members, nil
+}
+
+func (r *RedisCache) ZCard(ctx context.Context, key string) (_ int64, err error) {
+	defer observe(key, time.Now(), &err)
+	count, err := r.client.ZCard(ctx, key).Result()
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to count members of sorted set '%s': %v", key, err)
+		return 0, fmt.Errorf("failed to count members of sorted set %s: %v", key, err)
+	}
+	return count, nil
+}
+
+func (r *RedisCache) HMGet(ctx context.Context, key string, fields ...string) (_ []interface{}, err error) {
+	defer observe(key, time.Now(), &err)
+	values, err := r.client.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to fetch fields of hash '%s': %v", key, err)
+		return nil, fmt.Errorf("failed to fetch fields of hash %s: %v", key, err)
+	}
+	return values, nil
+}
+
+// Pipelined queues the commands added by fn and sends them to Redis in a single round trip
+func (r *RedisCache) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) (err error) {
+	defer observe(pipelineFamily, time.Now(), &err)
+	_, err = r.client.Pipelined(ctx, fn)
+	if err != nil {
+		log.Printf("Cache ERROR: Pipeline failed: %v", err)
+		return fmt.Errorf("failed to execute pipeline: %v", err)
+	}
+	return nil
+}
+
+// SetNX sets the key only if it does not exist yet and reports whether it was set
+func (r *RedisCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (_ bool, err error) {
+	defer observe(key, time.Now(), &err)
+	ok, err := r.client.SetNX(ctx, key, value, expiration).Result()
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to claim key '%s': %v", key, err)
+		return false, fmt.Errorf("failed to claim key %s in cache: %v", key, err)
+	}
+	return ok, nil
+}
+
+func (r *RedisCache) SAdd(ctx context.Context, key string, members ...interface{}) (err error) {
+	defer observe(key, time.Now(), &err)
+	if err := r.client.SAdd(ctx, key, members...).Err(); err != nil {
+		log.Printf("Cache ERROR: Failed to add members to set '%s': %v", key, err)
+		return fmt.Errorf("failed to add members to set %s: %v", key, err)
+	}
+	return nil
+}
+
+// SPopN removes and returns up to count random members of a set
+func (r *RedisCache) SPopN(ctx context.Context, key string, count int64) (_ []string, err error) {
+	defer observe(key, time.Now(), &err)
+	members, err := r.client.SPopN(ctx, key, count).Result()
+	if err != nil && err != redis.Nil {
+		log.Printf("Cache ERROR: Failed to pop members from set '%s': %v", key, err)
+		return nil, fmt.Errorf("failed to pop members from set %s: %v", key, err)
+	}
+	return members, nil
+}
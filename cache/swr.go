@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// swrRefreshTimeout bounds a background refresh and is also how long the cross-replica refresh lock is held
+const swrRefreshTimeout = 10 * time.Second
+
+// swrEntry is what GetOrLoadStale stores: the value plus the moment it stops being fresh.
+// The Redis key itself lives for ttl+stale so the stale copy can still be served while it is refreshed.
+type swrEntry struct {
+	Value      json.RawMessage `json:"value"`
+	FreshUntil time.Time       `json:"freshUntil"`
+}
+
+// GetOrLoadStale is GetOrLoad with stale-while-revalidate: a value is fresh for ttl, and for a further stale window it
+// is still returned immediately while a single background refresh (one per key across all replicas) reloads it.
+// Only a complete miss makes the caller wait for load. The bool reports whether the value came from the cache.
+func GetOrLoadStale[T any](ctx context.Context, c Cache, key string, ttl, stale time.Duration, load func(ctx context.Context) (T, error)) (T, bool, error) {
+	var value T
+	if entry, ok := getSWREntry(ctx, c, key); ok {
+		if err := json.Unmarshal(entry.Value, &value); err == nil {
+			if time.Now().After(entry.FreshUntil) {
+				go refreshStale(c, key, ttl, stale, load)
+			}
+			return value, true, nil
+		}
+		log.Printf("Cache ERROR: Failed to decode key '%s'", key)
+	}
+
+	result, err, _ := loadGroup.Do(flightKey[T](key), func() (interface{}, error) {
+		return loadAndStoreStale(ctx, c, key, ttl, stale, load)
+	})
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	value, err = loadedAs[T](key, result)
+	return value, false, err
+}
+
+func refreshStale[T any](c Cache, key string, ttl, stale time.Duration, load func(ctx context.Context) (T, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), swrRefreshTimeout)
+	defer cancel()
+
+	// the lock is left to expire: once the refresh lands the entry is fresh again and nobody asks for another one
+	claimed, err := c.SetNX(ctx, key+":refreshing", "1", swrRefreshTimeout)
+	if err != nil || !claimed {
+		return
+	}
+	if _, err, _ := loadGroup.Do(flightKey[T](key), func() (interface{}, error) {
+		return loadAndStoreStale(ctx, c, key, ttl, stale, load)
+	}); err != nil {
+		log.Printf("Cache ERROR: Background refresh of key '%s' failed: %v", key, err)
+	}
+}
+
+func loadAndStoreStale[T any](ctx context.Context, c Cache, key string, ttl, stale time.Duration, load func(ctx context.Context) (T, error)) (interface{}, error) {
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to encode value for key '%s': %v", key, err)
+		return value, nil
+	}
+	data, err := json.Marshal(swrEntry{Value: raw, FreshUntil: time.Now().Add(ttl)})
+	if err != nil {
+		log.Printf("Cache ERROR: Failed to encode value for key '%s': %v", key, err)
+		return value, nil
+	}
+	// errors are logged by Set, the caller still gets the loaded value
+	_ = c.Set(ctx, key, data, ttl+stale)
+	return value, nil
+}
+
+// getSWREntry treats anything that is not a stale-while-revalidate envelope, such as a value written by GetOrLoad, as a miss
+func getSWREntry(ctx context.Context, c Cache, key string) (swrEntry, bool) {
+	entry, ok := getJSON[swrEntry](ctx, c, key)
+	if !ok || len(entry.Value) == 0 || entry.FreshUntil.IsZero() {
+		return swrEntry{}, false
+	}
+	return entry, true
+}
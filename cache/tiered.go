@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// invalidationChannel carries "<instanceID>|<op>|<key or pattern>" messages between service replicas
+const invalidationChannel = "cache:l1:invalidate"
+
+const (
+	invalidateKey     = "key"
+	invalidatePattern = "pattern"
+)
+
+// TieredCache keeps a short-lived in-process copy (L1) of hot keys in front of Redis (L2).
+// Only keys starting with one of the configured prefixes are held in L1; everything else goes straight to Redis.
+// Writes and deletes are published on a Redis channel so other replicas drop their L1 copies.
+type TieredCache struct {
+	*RedisCache
+	local      *lru
+	localTTL   time.Duration
+	prefixes   []string
+	instanceID string
+	cancel     context.CancelFunc
+}
+
+var _ Cache = (*TieredCache)(nil)
+
+func NewTieredCache(remote *RedisCache, size int, localTTL time.Duration, prefixes ...string) *TieredCache {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &TieredCache{
+		RedisCache: remote,
+		local:      newLRU(size),
+		localTTL:   localTTL,
+		prefixes:   prefixes,
+		instanceID: uuid.New().String(),
+		cancel:     cancel,
+	}
+	go t.listenForInvalidations(ctx)
+	return t
+}
+
+// Close stops listening for invalidations from other replicas
+func (t *TieredCache) Close() {
+	t.cancel()
+}
+
+func (t *TieredCache) Get(ctx context.Context, key string) (interface{}, error) {
+	if !t.isLocal(key) {
+		return t.RedisCache.Get(ctx, key)
+	}
+	if value, ok := t.local.get(key); ok {
+		localHitsTotal.Add(keyFamily(key), 1)
+		return value, nil
+	}
+	value, err := t.RedisCache.Get(ctx, key)
+	if err != nil || value == nil {
+		return value, err
+	}
+	if str, ok := value.(string); ok {
+		t.local.set(key, str, t.localTTL)
+	}
+	return value, nil
+}
+
+func (t *TieredCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if err := t.RedisCache.Set(ctx, key, value, expiration); err != nil {
+		return err
+	}
+	if !t.isLocal(key) {
+		return nil
+	}
+	t.publishInvalidation(ctx, invalidateKey, key)
+
+	localTTL := t.localTTL
+	if expiration > 0 && expiration < localTTL {
+		localTTL = expiration
+	}
+	switch v := value.(type) {
+	case []byte:
+		t.local.set(key, string(v), localTTL)
+	case string:
+		t.local.set(key, v, localTTL)
+	default:
+		t.local.delete(key)
+	}
+	return nil
+}
+
+func (t *TieredCache) Delete(ctx context.Context, key string) error {
+	if t.isLocal(key) {
+		t.local.delete(key)
+		t.publishInvalidation(ctx, invalidateKey, key)
+	}
+	return t.RedisCache.Delete(ctx, key)
+}
+
+func (t *TieredCache) DeletePattern(ctx context.Context, pattern string) error {
+	t.local.deleteMatching(pattern)
+	t.publishInvalidation(ctx, invalidatePattern, pattern)
+	return t.RedisCache.DeletePattern(ctx, pattern)
+}
+
+func (t *TieredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if t.isLocal(key) {
+		if _, ok := t.local.get(key); ok {
+			return true, nil
+		}
+	}
+	return t.RedisCache.Exists(ctx, key)
+}
+
+func (t *TieredCache) isLocal(key string) bool {
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// publishInvalidation is best effort; a replica that misses it serves its copy for at most the L1 TTL
+func (t *TieredCache) publishInvalidation(ctx context.Context, op, target string) {
+	message := t.instanceID + "|" + op + "|" + target
+	if err := t.client.Publish(ctx, invalidationChannel, message).Err(); err != nil {
+		log.Printf("Cache ERROR: Failed to publish invalidation for '%s': %v", target, err)
+	}
+}
+
+func (t *TieredCache) listenForInvalidations(ctx context.Context) {
+	pubsub := t.client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			parts := strings.SplitN(msg.Payload, "|", 3)
+			if len(parts) != 3 || parts[0] == t.instanceID {
+				continue
+			}
+			switch parts[1] {
+			case invalidateKey:
+				t.local.delete(parts[2])
+			case invalidatePattern:
+				t.local.deleteMatching(parts[2])
+			}
+		}
+	}
+}
@@ -1,22 +1,44 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 	"xcode/cache"
 	configs "xcode/config"
+	"xcode/gateway"
+	"xcode/grpctls"
+	"xcode/interceptor"
+	"xcode/metrics"
+	"xcode/model"
 	"xcode/mongoconn"
 	"xcode/natsclient"
 	"xcode/repository"
+	"xcode/secrets"
 	"xcode/service"
+	"xcode/tracing"
+	"xcode/userclient"
 
 	problemService "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
 	redisboard "github.com/lijuuu/RedisBoard"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	zap_betterstack "xcode/logger"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 )
 
 //TODO - Use Zap_BetterStack logger  throughtout this file -add TraceID as well --partiallydone, avoiding repo layer to reduce amount of logs
@@ -25,20 +47,19 @@ import (
 
 func main() {
 
-	natsClient, err := natsclient.NewNatsClient(configs.LoadConfig().NATSURL)
+	config, err := configs.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to create NATS client: %v", err)
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	log.Printf("Configuration: %s", config.Dump())
 
-	config := configs.LoadConfig()
-
-	// Initialize Zap logger based on environment
-	var logger *zap.Logger
-	if config.Environment == "development" {
-		logger, err = zap.NewDevelopment()
-	} else {
-		logger, err = zap.NewProduction()
+	// Initialize Zap logger based on environment; its level is shared with the log streamer and changeable at runtime
+	logLevel, err := zap.ParseAtomicLevel(config.LogLevel)
+	if err != nil {
+		log.Printf("Invalid log level %q, using info", config.LogLevel)
+		logLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 	}
+	logger, err := zap_betterstack.NewLogger(config.Environment, logLevel)
 	if err != nil {
 		panic("Failed to initialize Zap logger: " + err.Error())
 	}
@@ -50,20 +71,140 @@ func main() {
 		config.Environment,
 		config.BetterStackUploadURL,
 		logger,
+		zap_betterstack.Options{
+			Level: logLevel,
+			Sampling: zap_betterstack.SamplingConfig{
+				First:      config.LogSampleFirst,
+				Thereafter: config.LogSampleThereafter,
+				Tick:       config.LogSampleTick,
+			},
+			Shipping: zap_betterstack.ShippingConfig{
+				QueueSize:     config.LogShipQueueSize,
+				BatchSize:     config.LogShipBatchSize,
+				FlushInterval: config.LogShipFlushInterval,
+				MaxRetries:    config.LogShipMaxRetries,
+			},
+		},
 	)
+	// registered early so it runs late, after everything that still logs while shutting down
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := logStreamer.Close(flushCtx); err != nil {
+			log.Printf("Failed to ship remaining logs: %v", err)
+		}
+	}()
+
+	// SIGHUP reloads the configuration once the service is up, see reloadConfig below; caught from here on so an
+	// early one does not terminate the process
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		ServiceName: "problems-service",
+		Endpoint:    config.OTLPEndpoint,
+		Insecure:    config.OTLPInsecure,
+		SampleRatio: config.TraceSampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	// registered first so it runs last, after the deferred closes whose spans it still has to export
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
 
-	redisCacheClient := cache.NewRedisCache(config.RedisURL, "", 0)
+	natsClient, err := natsclient.NewNatsClient(config.NATSURL, natsclient.Options{
+		MaxReconnects:    config.NATSMaxReconnects,
+		ReconnectWait:    config.NATSReconnectWait,
+		MaxReconnectWait: config.NATSMaxReconnectWait,
+		ConnectTimeout:   config.NATSConnectTimeout,
+	}, logStreamer)
+	if err != nil {
+		log.Fatalf("Failed to create NATS client: %v", err)
+	}
+	defer natsClient.Close()
+	metrics.RegisterHealthCheck("nats", natsClient.Healthy)
 
-	mongoclientInstance := mongoconn.ConnectDB()
+	// the cache picks up a Redis password rotated in the secret store on its next connection
+	var redisPassword atomic.Pointer[string]
+	redisPassword.Store(&config.RedisPassword)
+
+	// DB stays 0: RedisBoard always uses DB 0 and the leaderboard sync writes board keys through this client
+	redisCacheClient, err := cache.NewRedisCache(cache.Options{
+		Addr:            config.RedisURL,
+		PasswordFunc:    func() string { return *redisPassword.Load() },
+		TLS:             config.RedisTLS,
+		PoolSize:        config.RedisPoolSize,
+		DialTimeout:     config.RedisDialTimeout,
+		ReadTimeout:     config.RedisReadTimeout,
+		WriteTimeout:    config.RedisWriteTimeout,
+		MaxRetries:      config.RedisMaxRetries,
+		MinRetryBackoff: 8 * time.Millisecond,
+		MaxRetryBackoff: 512 * time.Millisecond,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisCacheClient.Close()
+
+	if config.SecretsProvider != nil {
+		secretsCtx, stopSecrets := context.WithCancel(context.Background())
+		defer stopSecrets()
+		go secrets.Watch(secretsCtx, config.SecretsProvider, config.SecretsRefreshInterval, config.SecretValues,
+			func(changed map[string]string) {
+				for key, value := range changed {
+					switch key {
+					case "REDISPASSWORD":
+						redisPassword.Store(&value)
+					case "BETTERSTACKSOURCETOKEN":
+						logStreamer.SetSourceToken(value)
+					case "MONGODBURL":
+						// the MongoDB client and RedisBoard keep the credentials they connected with
+						log.Printf("MONGODBURL changed in %s, restart to use it", config.SecretsProvider.Name())
+						continue
+					default:
+						continue
+					}
+					log.Printf("Applied %s rotated in %s", key, config.SecretsProvider.Name())
+				}
+			},
+			func(err error) {
+				log.Printf("Failed to refresh secrets: %v", err)
+			})
+	}
+
+	// Hot read paths (problems and problem lists) are served from an in-process copy for up to CacheL1TTL
+	tieredCache := cache.NewTieredCache(redisCacheClient, config.CacheL1Size, config.CacheL1TTL,
+		"problem:", "problem_slug:", "problem_slug_id:", "problems_list:", "problem_id_list:", "language_supports:")
+	defer tieredCache.Close()
+
+	mongoclientInstance, err := connectMongo(config, logStreamer)
+	if err != nil {
+		log.Fatalf("Could not connect to MongoDB: %v", err)
+	}
+	log.Printf("Connected to MongoDB")
+	defer func() {
+		disconnectCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := mongoclientInstance.Disconnect(disconnectCtx); err != nil {
+			log.Printf("Failed to disconnect from MongoDB: %v", err)
+		}
+	}()
 
 	// Initialize RedisBoard Leaderboard
 	lbConfig := redisboard.Config{
-		Namespace:   "user_Leaderboard_Unique", // namespace must be unique; avoid using similar prefixes in other parts of Redis, as this may lead to accidental key deletion when ForceClear is called
-		K:           10,
-		MaxUsers:    1_000_000,
-		MaxEntities: 200,
+		Namespace:   config.LeaderboardNamespace, // namespace must be unique; avoid using similar prefixes in other parts of Redis, as this may lead to accidental key deletion when ForceClear is called
+		K:           config.LeaderboardTopK,
+		MaxUsers:    config.LeaderboardMaxUsers,
+		MaxEntities: config.LeaderboardMaxEntities,
 		FloatScores: true,
 		RedisAddr:   config.RedisURL,
+		RedisPass:   config.RedisPassword,
 	}
 	lb, err := redisboard.New(lbConfig)
 	if err != nil {
@@ -71,11 +212,94 @@ func main() {
 	}
 	defer lb.Close()
 
-	repoInstance := repository.NewRepository(mongoclientInstance, lb, logStreamer)
+	// Seasonal boards use their own prefix so ForceClear on the all-time namespace never touches them
+	periodLBs := make(map[string]*redisboard.Leaderboard)
+	for _, period := range []string{model.LeaderboardPeriodWeekly, model.LeaderboardPeriodMonthly} {
+		periodConfig := lbConfig
+		periodConfig.Namespace = service.PeriodLeaderboardNamespace(period, config.LeaderboardNamespace)
+		periodLB, err := redisboard.New(periodConfig)
+		if err != nil {
+			log.Fatalf("Failed to initialize %s leaderboard: %v", period, err)
+		}
+		defer periodLB.Close()
+		periodLBs[period] = periodLB
+	}
+
+	// organizations are the entities of their own board, so they neither count against nor mix with countries
+	orgConfig := lbConfig
+	orgConfig.Namespace = service.OrganizationLeaderboardNamespace(config.LeaderboardNamespace)
+	orgConfig.MaxEntities = config.LeaderboardMaxOrganizations
+	orgLB, err := redisboard.New(orgConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize organization leaderboard: %v", err)
+	}
+	defer orgLB.Close()
+
+	repoInstance := repository.NewRepository(mongoclientInstance, lb, repository.Options{
+		Timeouts: repository.Timeouts{
+			Read:      config.Mongo.ReadTimeout,
+			Write:     config.Mongo.WriteTimeout,
+			Aggregate: config.Mongo.AggregateTimeout,
+		},
+		Scores: repository.ScoreTable{
+			Easy:   config.Scores.Easy,
+			Medium: config.Scores.Medium,
+			Hard:   config.Scores.Hard,
+		},
+		TestCaseLimits: repository.TestCaseLimits{
+			Run:    config.MaxRunTestCases,
+			Submit: config.MaxSubmitTestCases,
+		},
+	}, logStreamer)
+	if _, err := repoInstance.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Failed to ensure indexes: %v", err)
+	}
+
+	userClient, err := userclient.NewUserClient(config.UserGRPCHost + ":" + config.UserGRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to create user service client: %v", err)
+	}
+	defer userClient.Close()
+
+	// submission events fall back to inline processing when JetStream is not enabled on the broker
+	jetStream, err := natsclient.NewJetStream(natsClient)
+	if err != nil {
+		log.Printf("JetStream unavailable, submission events disabled: %v", err)
+		jetStream = nil
+	}
+
+	serviceInstance := service.NewService(repoInstance, natsClient, jetStream, tieredCache, config.CacheTTL, config.Engine, lb, periodLBs, config.LeaderboardNamespace, userClient, logStreamer)
+
+	serviceInstance.EnableCacheWarming(config.CacheWarmPages, config.CacheWarmPageSize)
+	serviceInstance.SetFeatureFlags(config.Environment, config.Features)
+	serviceInstance.SetPremiumRoles(config.PremiumRoles)
+	serviceInstance.EnforceProblemAuthorization(config.EnforceProblemAuthorization)
+	serviceInstance.EnableOrganizationLeaderboard(orgLB)
+	if err := serviceInstance.EnableScoreDecay(config.ScoreDecay); err != nil {
+		log.Fatalf("Invalid SCOREDECAY: %v", err)
+	}
+	if config.SoftDeletePurge {
+		serviceInstance.EnableSoftDeletePurge(config.SoftDeleteRetention, config.SoftDeleteArchive)
+	}
+
+	cronJobs := serviceInstance.StartCronJob(config.Cron) //NON Blocking cron for periodically syncing leaderboards.
+
+	eventConsumers, err := serviceInstance.StartSubmissionEventConsumers(context.Background())
+	if err != nil {
+		log.Printf("Failed to start submission event consumers: %v", err)
+	}
+
+	stopOutboxRelay := serviceInstance.StartOutboxRelay(config.OutboxRelayInterval)
+	stopWebhookDispatcher := serviceInstance.StartWebhookDispatcher(config.WebhookDispatchInterval)
+
+	userEvents, err := serviceInstance.StartUserEventSubscriptions()
+	if err != nil {
+		log.Printf("Failed to subscribe to user events: %v", err)
+	}
 
-	serviceInstance := service.NewService(*repoInstance, natsClient, *redisCacheClient, lb, logStreamer)
+	go serviceInstance.WarmProblemListCaches(context.Background())
 
-	serviceInstance.StartCronJob() //NON Blocking cron for periodically syncing leaderboards.
+	go metrics.Serve(":" + config.MetricsPort)
 
 	// Start gRPC server
 	lis, err := net.Listen("tcp", ":"+config.ProblemService)
@@ -83,12 +307,201 @@ func main() {
 		log.Fatalf("Failed to listen on port %s: %v", config.ProblemService, err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// code runs are expensive, everything else only needs protection from runaway clients. The limiter is installed
+	// even when disabled, so a reload can turn it on.
+	rateLimiter := interceptor.NewRateLimiter(redisCacheClient, interceptor.RateLimit{}, nil, nil, 0, logStreamer)
+	applyRateLimits(rateLimiter, config.RateLimit)
+
+	// the settings in configs.Reloadable change without a restart, on SIGHUP and when the .env file changes
+	running := config.Reloadable()
+	var reloadMu sync.Mutex
+	reloadConfig := func(trigger string) {
+		reloadMu.Lock()
+		defer reloadMu.Unlock()
+		current, err := configs.LoadReloadable()
+		if err != nil {
+			log.Printf("Ignoring configuration reload on %s: %v", trigger, err)
+			return
+		}
+		if slices.Contains(current.Changed(running), "rateLimit") {
+			applyRateLimits(rateLimiter, current.RateLimit)
+		}
+		if err := serviceInstance.ApplyReloadedConfig(context.Background(), trigger, running, current); err != nil {
+			log.Printf("Failed to apply configuration reload on %s: %v", trigger, err)
+			return
+		}
+		running = current
+	}
+	go func() {
+		for range hangups {
+			reloadConfig("SIGHUP")
+		}
+	}()
+	if config.ConfigWatch {
+		watchCtx, stopWatch := context.WithCancel(context.Background())
+		defer stopWatch()
+		go configs.WatchEnvFile(watchCtx, config.ConfigWatchInterval, func() {
+			reloadConfig(".env changed")
+		})
+	}
+
+	unaryInterceptors := interceptor.Unary(logStreamer, rateLimiter, serviceInstance, config.SlowRPCThreshold)
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+	}
+	if config.GRPCTLSCertFile != "" {
+		certReloader, err := grpctls.NewReloader(grpctls.Options{
+			CertFile:       config.GRPCTLSCertFile,
+			KeyFile:        config.GRPCTLSKeyFile,
+			ClientCAFile:   config.GRPCTLSClientCAFile,
+			ReloadInterval: config.GRPCTLSReloadInterval,
+		}, logStreamer)
+		if err != nil {
+			log.Fatalf("Failed to load gRPC TLS certificates: %v", err)
+		}
+		defer certReloader.Close()
+		go certReloader.Watch()
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certReloader.TLSConfig())))
+		log.Printf("gRPC TLS enabled (mutual TLS: %t)", config.GRPCTLSClientCAFile != "")
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	problemService.RegisterProblemsServiceServer(grpcServer, serviceInstance)
+	interceptor.InitializeMetrics(grpcServer)
+	if config.GRPCReflection {
+		reflection.Register(grpcServer)
+	}
+
+	go func() {
+		log.Printf("ProblemService gRPC server running on port %s", config.ProblemService) //50055
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve gRPC server: %v", err)
+		}
+	}()
+
+	// the REST facade calls the same handlers through the same interceptors, it only changes the encoding
+	var restServer *http.Server
+	if config.RESTGatewayPort != "" {
+		restGateway, err := gateway.New(serviceInstance, interceptor.Chain(unaryInterceptors...),
+			gateway.AnyOf(gateway.SharedSecret(config.RESTGatewaySecret), gateway.APIKeys(serviceInstance)))
+		if err != nil {
+			log.Fatalf("Failed to create REST gateway: %v", err)
+		}
+		// the admin API has no proto definition, so it is only served here, at /v1/admin/<Method>
+		restGateway.Register("admin", &service.AdminServiceDesc, serviceInstance)
+		restServer = &http.Server{Addr: ":" + config.RESTGatewayPort, Handler: restGateway}
+		go func() {
+			log.Printf("REST gateway running on port %s", config.RESTGatewayPort)
+			if err := restServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("REST gateway stopped: %v", err)
+			}
+		}()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-signals
+	log.Printf("Received %s, draining for up to %s", sig, config.ShutdownDrainTimeout)
+
+	// one deadline for the whole drain; whatever is still running when it passes is cut off
+	drainCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownDrainTimeout)
+	defer cancel()
+
+	// stop taking RPCs and let the running ones, including in-flight submissions, finish
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-drainCtx.Done():
+		log.Printf("gRPC server did not drain in time, closing remaining connections")
+		grpcServer.Stop()
+	}
+
+	if restServer != nil {
+		if err := restServer.Shutdown(drainCtx); err != nil {
+			log.Printf("REST gateway did not drain in time: %v", err)
+		}
+	}
+
+	select {
+	case <-cronJobs.Stop().Done():
+	case <-drainCtx.Done():
+		log.Printf("Cron jobs did not finish in time")
+	}
 
-	log.Printf("ProblemService gRPC server running on port %s", config.ProblemService) //50055
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve gRPC server: %v", err)
+	if eventConsumers != nil {
+		eventConsumers.Stop()
+	}
+	if userEvents != nil {
+		if err := userEvents.Unsubscribe(); err != nil {
+			log.Printf("Failed to unsubscribe from user events: %v", err)
+		}
+	}
+
+	if err := serviceInstance.WaitForBackgroundWork(drainCtx); err != nil {
+		log.Printf("Background work did not finish in time: %v", err)
 	}
 
+	// the relay finishes its batch in progress; events still pending go out from another replica or the next start
+	stopOutboxRelay()
+	// deliveries still due are sent by another replica or the next start
+	stopWebhookDispatcher()
+
+	if err := natsClient.Drain(drainCtx); err != nil {
+		log.Printf("Failed to drain NATS: %v", err)
+	}
+
+	// the deferred closes release Redis, MongoDB, the leaderboards and the user service client
+	log.Printf("ProblemService stopped")
+}
+
+// connectMongo retries the initial connection with exponential backoff, so the service survives starting before
+// MongoDB is reachable, e.g. in docker compose
+func connectMongo(config configs.Config, logStreamer *zap_betterstack.BetterStackLogStreamer) (*mongo.Client, error) {
+	opts := mongoconn.Options{
+		URI:                    config.MongoDBURL,
+		MaxPoolSize:            uint64(config.Mongo.MaxPoolSize),
+		MinPoolSize:            uint64(config.Mongo.MinPoolSize),
+		ConnectTimeout:         config.Mongo.ConnectTimeout,
+		ServerSelectionTimeout: config.Mongo.ServerSelectionTimeout,
+		SocketTimeout:          config.Mongo.SocketTimeout,
+		WriteConcern:           config.Mongo.WriteConcern,
+		ReadConcern:            config.Mongo.ReadConcern,
+		SlowQueryThreshold:     config.SlowQueryThreshold,
+		Logger:                 logStreamer,
+	}
+
+	backoff := config.Mongo.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), config.Mongo.ServerSelectionTimeout+config.Mongo.ConnectTimeout)
+		client, err := mongoconn.ConnectDB(ctx, opts)
+		cancel()
+		if err == nil {
+			return client, nil
+		}
+		if attempt >= config.Mongo.ConnectAttempts {
+			return nil, err
+		}
+		log.Printf("MongoDB connection attempt %d/%d failed, retrying in %s: %v", attempt, config.Mongo.ConnectAttempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// applyRateLimits sets the limits of limiter from config; a disabled config sets zero limits, which let every
+// request through
+func applyRateLimits(limiter *interceptor.RateLimiter, config configs.RateLimitConfig) {
+	if !config.Enabled {
+		limiter.Update(interceptor.RateLimit{}, nil, nil, config.TrustedProxyHops)
+		return
+	}
+	limiter.Update(interceptor.RateLimit{Rate: config.ReadRate, Burst: config.ReadBurst},
+		map[string]interceptor.RateLimit{
+			problemService.ProblemsService_RunUserCodeProblem_FullMethodName: {Rate: config.RunRate, Burst: config.RunBurst},
+		},
+		config.Whitelist, config.TrustedProxyHops)
 }
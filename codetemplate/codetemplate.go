@@ -0,0 +1,155 @@
+// Package codetemplate fills the validation templates of problems with a user's code and the test cases.
+//
+// A template marks where values go with placeholders such as {FUNCTION_PLACEHOLDER}. Render replaces them in a single
+// pass, so placeholder text inside a value (user code, a test case input) is never replaced itself. Values that end
+// up inside a string literal are escaped for the literal they are in, which Render reads from the characters right
+// before the placeholder, e.g. "{TESTCASE_PLACEHOLDER}" in Python or R"({TESTCASE_PLACEHOLDER})" in C++.
+package codetemplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"xcode/utils"
+)
+
+// The placeholders Render knows; any other text in braces is left alone, templates are full of braces
+const (
+	TestCases = "{TESTCASE_PLACEHOLDER}"
+	Function  = "{FUNCTION_PLACEHOLDER}"
+	Imports   = "{IMPORTS_PLACEHOLDER}"
+	ClassName = "{CLASS_NAME_PLACEHOLDER}"
+)
+
+// DefaultClassName fills ClassName when the user's code declares no class
+const DefaultClassName = "Solution"
+
+// Values are what the placeholders are replaced with
+type Values struct {
+	TestCases string // the test cases as JSON, usually placed inside a string literal
+	Function  string // the user's code, inserted as is
+	Imports   string // import lines, inserted as is
+	ClassName string // must be an identifier
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Render returns template with every placeholder replaced. It fails when the template has no Function placeholder,
+// when ClassName is used but not an identifier, or when a value cannot be put into the literal around it.
+func Render(template, language string, values Values) (string, error) {
+	if !strings.Contains(template, Function) {
+		return "", fmt.Errorf("template has no %s", Function)
+	}
+	if strings.Contains(template, ClassName) && !identifier.MatchString(values.ClassName) {
+		return "", fmt.Errorf("class name %q is not an identifier", values.ClassName)
+	}
+	replacements := map[string]string{
+		TestCases: values.TestCases,
+		Function:  values.Function,
+		Imports:   values.Imports,
+		ClassName: values.ClassName,
+	}
+
+	var out strings.Builder
+	out.Grow(len(template) + len(values.Function) + len(values.TestCases))
+	rest := template
+	for {
+		at, placeholder := nextPlaceholder(rest)
+		if at < 0 {
+			out.WriteString(rest)
+			return out.String(), nil
+		}
+		out.WriteString(rest[:at])
+		value := replacements[placeholder]
+		if placeholder == TestCases {
+			escaped, err := escapeForLiteral(language, out.String(), value)
+			if err != nil {
+				return "", fmt.Errorf("cannot insert %s: %w", placeholder, err)
+			}
+			value = escaped
+		}
+		out.WriteString(value)
+		rest = rest[at+len(placeholder):]
+	}
+}
+
+// nextPlaceholder finds the first known placeholder in s
+func nextPlaceholder(s string) (int, string) {
+	at, found := -1, ""
+	for _, placeholder := range []string{TestCases, Function, Imports, ClassName} {
+		if i := strings.Index(s, placeholder); i >= 0 && (at < 0 || i < at) {
+			at, found = i, placeholder
+		}
+	}
+	return at, found
+}
+
+// rawStringOpening matches the opening of a C++ raw string literal at the end of the text, R"delimiter(
+var rawStringOpening = regexp.MustCompile(`R"([^()\\\s"]{0,16})\($`)
+
+// escapeForLiteral escapes value for the literal opened at the end of before. Outside of any literal the value is
+// inserted as is; JSON is already valid source in JavaScript and most other languages.
+func escapeForLiteral(language, before, value string) (string, error) {
+	language = utils.NormalizeLanguage(language)
+	if m := rawStringOpening.FindStringSubmatch(before); m != nil {
+		// end the raw string before its closing sequence, spell the sequence as an ordinary literal and reopen;
+		// adjacent literals are concatenated
+		closing := ")" + m[1] + `"`
+		reopen := closing + ` ")` + m[1] + `\"" R"` + m[1] + "("
+		return strings.ReplaceAll(value, closing, reopen), nil
+	}
+
+	switch {
+	case strings.HasSuffix(before, "`"):
+		if language == "go" {
+			// Go raw strings cannot contain a backquote at all, so it is spliced in from an interpreted string
+			return strings.ReplaceAll(value, "`", "` + \"`\" + `"), nil
+		}
+		// JavaScript template literal
+		return escapeQuoted(value, '`', "${"), nil
+	case strings.HasSuffix(before, `"`):
+		return escapeQuoted(value, '"'), nil
+	case strings.HasSuffix(before, "'"):
+		if language == "go" || language == "cpp" {
+			return "", fmt.Errorf("a %s character literal cannot hold it", language)
+		}
+		return escapeQuoted(value, '\''), nil
+	}
+	return value, nil
+}
+
+// escapeQuoted escapes value for a literal delimited by quote: backslashes, the quote, line breaks and any of the
+// extra sequences that would otherwise be interpreted
+func escapeQuoted(value string, quote rune, extra ...string) string {
+	var out strings.Builder
+	out.Grow(len(value) + len(value)/8)
+	for i, r := range value {
+		switch {
+		case r == '\\' || r == quote:
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\n':
+			out.WriteString(`\n`)
+		case r == '\r':
+			out.WriteString(`\r`)
+		case r == '\u2028' || r == '\u2029':
+			// line terminators in JavaScript string literals
+			fmt.Fprintf(&out, `\u%04x`, r)
+		default:
+			escaped := false
+			for _, sequence := range extra {
+				if strings.HasPrefix(value[i:], sequence) {
+					out.WriteByte('\\')
+					out.WriteRune(r)
+					escaped = true
+					break
+				}
+			}
+			if !escaped {
+				out.WriteRune(r)
+			}
+		}
+	}
+	return out.String()
+}
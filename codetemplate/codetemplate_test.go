@@ -0,0 +1,241 @@
+package codetemplate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		language string
+		values   Values
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "python double quotes cannot be closed",
+			template: `tests = json.loads("{TESTCASE_PLACEHOLDER}")` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "python",
+			values:   Values{TestCases: `{"in":"x\"); os.system('id') #"}`, Function: "def f(): pass"},
+			want:     `tests = json.loads("{\"in\":\"x\\\"); os.system('id') #\"}")` + "\ndef f(): pass",
+		},
+		{
+			name:     "python single quotes",
+			template: `tests = '{TESTCASE_PLACEHOLDER}'` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "python",
+			values:   Values{TestCases: `["it's", "a\\b"]`},
+			want:     `tests = '["it\'s", "a\\\\b"]'` + "\n",
+		},
+		{
+			name:     "line breaks are escaped",
+			template: `tests = "{TESTCASE_PLACEHOLDER}"` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "python",
+			values:   Values{TestCases: "a\nb\r\"c"},
+			want:     `tests = "a\nb\r\"c"` + "\n",
+		},
+		{
+			name:     "javascript template literal cannot interpolate",
+			template: "const tests = `{TESTCASE_PLACEHOLDER}`;\n{FUNCTION_PLACEHOLDER}",
+			language: "javascript",
+			values:   Values{TestCases: "a`${process.exit(1)}\\"},
+			want:     "const tests = `a\\`\\${process.exit(1)}\\\\`;\n",
+		},
+		{
+			name:     "javascript line terminators",
+			template: `const tests = "{TESTCASE_PLACEHOLDER}";` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "js",
+			values:   Values{TestCases: "a\u2028b\u2029c"},
+			want:     `const tests = "a\u2028b\u2029c";` + "\n",
+		},
+		{
+			name:     "go raw string cannot be closed",
+			template: "var tests = `{TESTCASE_PLACEHOLDER}`\n{FUNCTION_PLACEHOLDER}",
+			language: "go",
+			values:   Values{TestCases: "x`; os.Exit(1); `"},
+			want:     "var tests = `x` + \"`\" + `; os.Exit(1); ` + \"`\" + ``\n",
+		},
+		{
+			name:     "go interpreted string",
+			template: `var tests = "{TESTCASE_PLACEHOLDER}"` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "golang",
+			values:   Values{TestCases: `"\`},
+			want:     `var tests = "\"\\"` + "\n",
+		},
+		{
+			name:     "go character literal is refused",
+			template: "var r = '{TESTCASE_PLACEHOLDER}'\n{FUNCTION_PLACEHOLDER}",
+			language: "go",
+			values:   Values{TestCases: "a"},
+			wantErr:  true,
+		},
+		{
+			name:     "cpp raw string cannot be closed",
+			template: `string tests = R"({TESTCASE_PLACEHOLDER})";` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "cpp",
+			values:   Values{TestCases: `a)"; system("id"); R"(`},
+			want:     `string tests = R"(a)" ")\"" R"(; system("id"); R"()";` + "\n",
+		},
+		{
+			name:     "cpp raw string with a delimiter",
+			template: `string tests = R"json({TESTCASE_PLACEHOLDER})json";` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "c++",
+			values:   Values{TestCases: `)" is fine, )json" is not`},
+			want:     `string tests = R"json()" is fine, )json" ")json\"" R"json( is not)json";` + "\n",
+		},
+		{
+			name:     "cpp raw string keeps backslashes",
+			template: `string tests = R"({TESTCASE_PLACEHOLDER})";` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "cpp",
+			values:   Values{TestCases: `a\"b\\c`},
+			want:     `string tests = R"(a\"b\\c)";` + "\n",
+		},
+		{
+			name:     "cpp character literal is refused",
+			template: "char c = '{TESTCASE_PLACEHOLDER}';\n{FUNCTION_PLACEHOLDER}",
+			language: "cpp",
+			values:   Values{TestCases: "a"},
+			wantErr:  true,
+		},
+		{
+			name:     "java unicode escapes stay escaped",
+			template: `String tests = "{TESTCASE_PLACEHOLDER}";` + "\n{FUNCTION_PLACEHOLDER}",
+			language: "java",
+			values:   Values{TestCases: `\u0022); System.exit(1); //`},
+			want:     `String tests = "\\u0022); System.exit(1); //";` + "\n",
+		},
+		{
+			name:     "placeholder text in values is not replaced",
+			template: `tests = "{TESTCASE_PLACEHOLDER}"` + "\n{FUNCTION_PLACEHOLDER}\n{IMPORTS_PLACEHOLDER}",
+			language: "python",
+			values: Values{
+				TestCases: "{FUNCTION_PLACEHOLDER}",
+				Function:  `print("{TESTCASE_PLACEHOLDER}") # {IMPORTS_PLACEHOLDER}`,
+				Imports:   "import os # {CLASS_NAME_PLACEHOLDER}",
+			},
+			want: `tests = "{FUNCTION_PLACEHOLDER}"` + "\n" + `print("{TESTCASE_PLACEHOLDER}") # {IMPORTS_PLACEHOLDER}` + "\nimport os # {CLASS_NAME_PLACEHOLDER}",
+		},
+		{
+			name:     "user code is inserted as is",
+			template: "{IMPORTS_PLACEHOLDER}\nclass {CLASS_NAME_PLACEHOLDER} {}\n{FUNCTION_PLACEHOLDER}",
+			language: "java",
+			values:   Values{Imports: "import java.util.*;", ClassName: "Main", Function: `String s = "\"";`},
+			want:     "import java.util.*;\nclass Main {}\n" + `String s = "\"";`,
+		},
+		{
+			name:     "other braces are left alone",
+			template: "func main() { {FUNCTION_PLACEHOLDER} {OTHER} }",
+			language: "go",
+			values:   Values{Function: "f()"},
+			want:     "func main() { f() {OTHER} }",
+		},
+		{
+			name:     "class name must be an identifier",
+			template: "class {CLASS_NAME_PLACEHOLDER} {}\n{FUNCTION_PLACEHOLDER}",
+			language: "java",
+			values:   Values{ClassName: "Main {} static { System.exit(1); } class X"},
+			wantErr:  true,
+		},
+		{
+			name:     "template without a function placeholder",
+			template: `tests = "{TESTCASE_PLACEHOLDER}"`,
+			language: "python",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.template, tt.language, tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Render = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeForLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		before   string
+		value    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "outside any literal", language: "js", before: "const tests = ", value: `[{"a":"\""}]`, want: `[{"a":"\""}]`},
+		{name: "double quotes", language: "python", before: `x = "`, value: `"\`, want: `\"\\`},
+		{name: "single quotes", language: "js", before: `x = '`, value: `'\`, want: `\'\\`},
+		{name: "template literal", language: "js", before: "x = `", value: "`${a}$b", want: "\\`\\${a}$b"},
+		{name: "go raw string", language: "go", before: "x := `", value: "``", want: "` + \"`\" + `` + \"`\" + `"},
+		{name: "go character literal", language: "go", before: "x := '", value: "a", wantErr: true},
+		{name: "cpp character literal", language: "cpp", before: "x = '", value: "a", wantErr: true},
+		{name: "cpp raw string", language: "cpp", before: `x = R"(`, value: `)")"`, want: `)" ")\"" R"()" ")\"" R"(`},
+		{name: "cpp raw string with delimiter", language: "cpp", before: `x = R"d(`, value: `)")d"`, want: `)")d" ")d\"" R"d(`},
+		{name: "quote not at the end", language: "python", before: `x = "a" + `, value: `"`, want: `"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := escapeForLiteral(tt.language, tt.before, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("escapeForLiteral = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("escapeForLiteral: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("escapeForLiteral = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderKeepsLiteralClosed checks that however a value tries to end its literal, everything after the template's
+// opening quote up to its closing quote stays one literal: no unescaped quote appears in between
+func TestRenderKeepsLiteralClosed(t *testing.T) {
+	values := []string{`"`, `\"`, `\\"`, `\`, "\n\"", `"); exit(1); ("`, `{TESTCASE_PLACEHOLDER}"`}
+	for _, language := range []string{"python", "js", "java", "go", "cpp"} {
+		for _, value := range values {
+			got, err := Render(`x = "{TESTCASE_PLACEHOLDER}";{FUNCTION_PLACEHOLDER}`, language, Values{TestCases: value})
+			if err != nil {
+				t.Fatalf("%s %q: Render: %v", language, value, err)
+			}
+			literal := strings.TrimSuffix(strings.TrimPrefix(got, `x = "`), `";`)
+			if unescapedQuote(literal) {
+				t.Errorf("%s %q: literal %s is closed early", language, value, literal)
+			}
+		}
+	}
+}
+
+// unescapedQuote reports whether s has a double quote not preceded by an odd number of backslashes
+func unescapedQuote(s string) bool {
+	backslashes := 0
+	for _, r := range s {
+		switch r {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			if backslashes%2 == 0 {
+				return true
+			}
+		case '\n':
+			return true
+		}
+		backslashes = 0
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package codetemplate
+
+import (
+	"regexp"
+	"strings"
+
+	"xcode/utils"
+)
+
+// importPrefixes start the lines SplitImports treats as imports, by normalized language
+var importPrefixes = map[string][]string{
+	"go":     {"import "},
+	"python": {"import ", "from "},
+	"js":     {"import ", "const ", "let ", "var "}, // the declarations only when they require()
+	"cpp":    {"#include", "using namespace "},
+	"java":   {"import "},
+}
+
+// SplitImports takes the imports a user wrote at the top of their code, e.g. import "sort" in Go, so a template with
+// an Imports placeholder can put them where the language allows them. Blank lines between imports are kept with
+// them; the first other line ends the imports. Code in a language without known imports is returned as is.
+func SplitImports(language, code string) (imports, rest string) {
+	language = utils.NormalizeLanguage(language)
+	prefixes, ok := importPrefixes[language]
+	if !ok {
+		return "", code
+	}
+
+	lines := strings.SplitAfter(code, "\n")
+	end, inBlock := 0, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			// inside a Go import ( ... ) block
+			inBlock = trimmed != ")"
+		case trimmed == "":
+		case isImport(language, trimmed, prefixes):
+			inBlock = language == "go" && strings.HasSuffix(trimmed, "(")
+		default:
+			return strings.Join(lines[:end], ""), strings.Join(lines[end:], "")
+		}
+		if trimmed != "" {
+			end = i + 1
+		}
+	}
+	if inBlock {
+		// an unterminated block is left for the compiler to report where the user wrote it
+		return "", code
+	}
+	return strings.Join(lines[:end], ""), strings.Join(lines[end:], "")
+}
+
+func isImport(language, line string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		if language == "js" && prefix != "import " {
+			return strings.Contains(line, "require(")
+		}
+		return true
+	}
+	return false
+}
+
+var classDeclaration = regexp.MustCompile(`(?m)^\s*(?:public\s+|export\s+)?class\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ClassNameOf returns the name of the first class declared at the start of a line in code, or DefaultClassName
+func ClassNameOf(code string) string {
+	if m := classDeclaration.FindStringSubmatch(code); m != nil {
+		return m[1]
+	}
+	return DefaultClassName
+}
@@ -1,45 +1,425 @@
 package configs
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"xcode/secrets"
 
 	"github.com/joho/godotenv"
 )
 
+// CacheTTLConfig holds how long each family of cached responses lives in Redis
+type CacheTTLConfig struct {
+	Problem     time.Duration // single problems and their language supports
+	List        time.Duration // paginated problem lists and submission lists
+	Stats       time.Duration // per-user problem statistics
+	Leaderboard time.Duration // leaderboard aggregates such as entity stats
+
+	// ListStale is how long past List a heavy list may still be served while it refreshes in the background
+	ListStale time.Duration
+
+	LanguageStats time.Duration // per-user submission stats by language
+	Profile       time.Duration // display profiles fetched from the user service
+	Entitlement   time.Duration // whether a user may open premium problems, looked up in the user service
+
+	// ExecutionResult is how long the engine's verdict on a run is reused for byte-identical resubmissions of the
+	// same problem and language, 0 disables it
+	ExecutionResult time.Duration
+}
+
+// CronConfig holds the schedules of the periodic jobs in robfig/cron syntax, e.g. "@every 1h" or
+// "CRON_TZ=UTC 5 0 * * *". Season rollovers are not configurable, they have to run when a season ends.
+type CronConfig struct {
+	LeaderboardSync         string // incremental leaderboard sync
+	LeaderboardSnapshot     string // daily standings snapshot
+	DraftFlush              string // editor drafts from Redis to MongoDB
+	LeaderboardOutbox       string // retry of leaderboard updates that missed Redis
+	SoftDeletePurge         string // removal of problems past SoftDeleteRetention
+	ContestFinalize         string // official standings and ratings of ended contests
+	DifficultyRecalibration string // empirical difficulty of every problem from its submissions
+	FirstSuccessCheck       string // cross-check of submissions against first successes, repairing missing ones
+}
+
+// ScoreConfig is the leaderboard score of a first success per problem difficulty. Run RecalculateScores after a
+// change to apply it to earlier first successes.
+type ScoreConfig struct {
+	Easy   int
+	Medium int
+	Hard   int
+}
+
+// ScoreDecayConfig makes scores on one leaderboard decay once their user has solved nothing for Grace: from then on
+// a score halves every HalfLife, but never drops below MinFactor of itself
+type ScoreDecayConfig struct {
+	Grace     time.Duration
+	HalfLife  time.Duration
+	MinFactor float64
+}
+
+// MongoConfig tunes the MongoDB client; the connection string is MongoDBURL
+type MongoConfig struct {
+	MaxPoolSize            int
+	MinPoolSize            int
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	SocketTimeout          time.Duration
+	WriteConcern           string // "majority" or a number of nodes
+	ReadConcern            string // read concern level, e.g. "local" or "majority"
+
+	// repository operations without an earlier deadline from their caller are cut off after these, see
+	// repository.Timeouts
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	AggregateTimeout time.Duration
+
+	// startup tries to connect ConnectAttempts times, waiting ConnectBackoff before the second try and doubling it
+	ConnectAttempts int
+	ConnectBackoff  time.Duration
+}
+
+// EngineConfig controls how problems.execute.request is sent to the execution engine
+type EngineConfig struct {
+	Attempts       int           // total attempts, including the first
+	AttemptTimeout time.Duration // upper bound per attempt, shortened when the caller's deadline is closer
+	Backoff        time.Duration // wait before the second attempt, doubled afterwards and jittered
+	MaxConcurrency int           // execution requests in flight at once, further requests queue
+	// ValidationParallelism is how many languages of one problem are validated at once
+	ValidationParallelism int
+	// ContractVersion is the compiler contract version requests are sent with (see model.CompilerContractV1).
+	// Limits and execution profiles only reach the engine from v2 on; under v1 SetExecutionProfile is refused.
+	ContractVersion int
+	TimeLimit       time.Duration // per run, sent from contract v2 on
+	MemoryLimitMB   int           // per run, sent from contract v2 on
+}
+
+// RateLimitConfig controls the per-user and per-IP request limits of the gRPC server
+type RateLimitConfig struct {
+	Enabled   bool
+	RunRate   float64 // RunUserCodeProblem requests per second
+	RunBurst  int
+	ReadRate  float64 // requests per second for every other RPC
+	ReadBurst int
+	Whitelist []string // user IDs and client IPs that are never limited
+	// TrustedProxyHops is how many proxies in front of the service append to x-forwarded-for; the client IP is the
+	// entry that many from the right. 0 ignores the header and limits by peer address.
+	TrustedProxyHops int
+}
+
 type Config struct {
 	APIGATEWAYPORT string
+	UserGRPCHost   string
 	UserGRPCPort   string
 	MongoDBURL     string
 	ProblemService string
+	MetricsPort    string
 	NATSURL        string
-	RedisURL       string
+
+	NATSMaxReconnects    int
+	NATSReconnectWait    time.Duration
+	NATSMaxReconnectWait time.Duration
+	NATSConnectTimeout   time.Duration
+
+	Mongo MongoConfig
+
+	Engine EngineConfig
+
+	RateLimit RateLimitConfig
+
+	// RESTGatewayPort serves the JSON/REST facade of the gRPC API; empty disables it
+	RESTGatewayPort string
+	// RESTGatewaySecret is the shared secret the API gateway sends in x-gateway-secret; the REST facade only
+	// trusts x-user-id and x-user-entitlements from callers presenting it or an active API key
+	RESTGatewaySecret string
+	// GRPCReflection registers the reflection service so tools like grpcurl can list and call RPCs
+	GRPCReflection bool
+
+	// the gRPC listener serves TLS when a certificate is set, and requires client certificates when a client CA is set
+	GRPCTLSCertFile       string
+	GRPCTLSKeyFile        string
+	GRPCTLSClientCAFile   string
+	GRPCTLSReloadInterval time.Duration
+
+	// OTLPEndpoint is the OpenTelemetry collector spans are exported to over gRPC; empty disables exporting
+	OTLPEndpoint     string
+	OTLPInsecure     bool
+	TraceSampleRatio float64
+
+	// MongoDB commands and RPC handlers slower than these are logged and counted as slow
+	SlowQueryThreshold time.Duration
+	SlowRPCThreshold   time.Duration
+
+	// problems soft deleted longer than SoftDeleteRetention ago are removed daily, and copied to problems_archive
+	// first when SoftDeleteArchive is set
+	SoftDeletePurge     bool
+	SoftDeleteRetention time.Duration
+	SoftDeleteArchive   bool
+
+	// users whose role in the user service is one of PremiumRoles may open premium problems; nil leaves the
+	// service's default of premium and admin
+	PremiumRoles []string
+
+	// EnforceProblemAuthorization rejects problem changes by callers who are neither a maintainer of the problem,
+	// an admin nor an admin API key. Off by default: such changes are only logged, with "enforced": false, so
+	// clients can move to x-user-id or admin API keys before it is turned on.
+	EnforceProblemAuthorization bool
+
+	// OutboxRelayInterval is how often pending outbox events are published when no request wakes the relay sooner
+	OutboxRelayInterval time.Duration
+
+	// WebhookDispatchInterval is how often due webhook deliveries are sent when no new event wakes the dispatcher
+	WebhookDispatchInterval time.Duration
+
+	// ShutdownDrainTimeout bounds how long in-flight RPCs, jobs and events get to finish on SIGTERM
+	ShutdownDrainTimeout time.Duration
+
+	RedisURL string
+
+	RedisPassword     string
+	RedisTLS          bool
+	RedisPoolSize     int
+	RedisDialTimeout  time.Duration
+	RedisReadTimeout  time.Duration
+	RedisWriteTimeout time.Duration
+	RedisMaxRetries   int
+
+	LeaderboardNamespace string
+	// every RedisBoard keeps its top LeaderboardTopK and holds up to LeaderboardMaxUsers users in
+	// LeaderboardMaxEntities entities; the organization board holds up to LeaderboardMaxOrganizations instead
+	LeaderboardTopK             int
+	LeaderboardMaxUsers         int
+	LeaderboardMaxEntities      int
+	LeaderboardMaxOrganizations int
+
+	Scores ScoreConfig
+	// ScoreDecay holds the decay of each leaderboard that has one, keyed by period ("all", "weekly" or "monthly");
+	// boards without an entry keep their scores
+	ScoreDecay map[string]ScoreDecayConfig
+	// a problem has at most MaxRunTestCases run and MaxSubmitTestCases submit test cases
+	MaxRunTestCases    int
+	MaxSubmitTestCases int
+
+	Cron CronConfig
+
+	Features FeatureFlags
+
+	CacheTTL    CacheTTLConfig
+	CacheL1Size int           // entries kept in the in-process cache in front of Redis
+	CacheL1TTL  time.Duration // how long an in-process entry may be served without going back to Redis
+
+	CacheWarmPages    int // first pages of the problem lists to precompute, 0 disables warming
+	CacheWarmPageSize int
+
+	// LogLevel is the minimum level logged (debug, info, warn, error)
+	LogLevel string
+	// repeated info messages are kept LogSampleFirst times per LogSampleTick, then every LogSampleThereafter-th;
+	// LogSampleFirst 0 disables sampling
+	LogSampleFirst      int
+	LogSampleThereafter int
+	LogSampleTick       time.Duration
+
+	// logs are shipped to Better Stack in batches from a bounded queue; entries beyond it are dropped
+	LogShipQueueSize     int
+	LogShipBatchSize     int
+	LogShipFlushInterval time.Duration
+	LogShipMaxRetries    int
 
 	Environment            string
 	BetterStackSourceToken string
 	BetterStackUploadURL   string
+
+	// MONGODBURL, REDISPASSWORD and BETTERSTACKSOURCETOKEN are read from this secret store when it is set, taking
+	// precedence over the environment, and read again every SecretsRefreshInterval
+	Secrets                secrets.Options
+	SecretsRefreshInterval time.Duration
+	// SecretsProvider is the store configured by Secrets, nil without one, and SecretValues what it held at startup
+	SecretsProvider secrets.Provider  `json:"-"`
+	SecretValues    map[string]string `json:"-"`
+
+	// the settings in Reloadable are read again on SIGHUP and, with ConfigWatch, whenever the .env file changes,
+	// checked every ConfigWatchInterval
+	ConfigWatch         bool
+	ConfigWatchInterval time.Duration
 }
 
-func LoadConfig() Config {
-	err := godotenv.Load()
+// LoadConfig reads the configuration from the environment, with a .env file in the working directory taking effect
+// where present; containers usually have none. Every missing required value and every value that does not parse is
+// reported in the returned error rather than replaced by its default, so a misconfigured deployment fails to start.
+// Outside development the addresses of MongoDB, NATS, Redis and the user service have no defaults.
+func LoadConfig() (Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("failed to load .env file: %w", err)
+	}
+
+	secretOpts := secrets.Options{
+		Provider:    getEnv("SECRETSPROVIDER", ""),
+		VaultAddr:   getEnv("VAULTADDR", ""),
+		VaultToken:  getEnv("VAULTTOKEN", ""),
+		VaultPath:   getEnv("VAULTSECRETPATH", ""),
+		AWSRegion:   getEnv("AWSREGION", ""),
+		AWSSecretID: getEnv("AWSSECRETID", ""),
+	}
+	provider, err := secrets.NewProvider(secretOpts)
 	if err != nil {
-		log.Fatal("Error loading .env file", err)
+		return Config{}, fmt.Errorf("invalid secrets configuration: %w", err)
+	}
+	var secretValues map[string]string
+	if provider != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), secrets.FetchTimeout)
+		secretValues, err = provider.Fetch(ctx)
+		cancel()
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read secrets from %s: %w", provider.Name(), err)
+		}
 	}
+
+	environment := getEnv("ENVIRONMENT", "development")
+	l := &loader{development: environment == "development", secrets: secretValues}
+	reloadable := l.reloadable()
 	config := Config{
 		APIGATEWAYPORT: getEnv("APIGATEWAYPORT", "7000"),
+		UserGRPCHost:   l.requiredEnv("USERGRPCHOST", "localhost"),
 		UserGRPCPort:   getEnv("USERGRPCPORT", "50051"),
-		MongoDBURL:     getEnv("MONGODBURL", "mongodb://localhost:27017"),
+		MongoDBURL:     l.requiredEnv("MONGODBURL", "mongodb://localhost:27017"),
 		ProblemService: getEnv("PROBLEMSERVICE", "50055"),
-		NATSURL:        getEnv("NATSURL", "nats://localhost:4222"),
-		RedisURL:       getEnv("REDISURL", "localhost:6379"),
+		MetricsPort:    getEnv("METRICSPORT", "9100"),
+		NATSURL:        l.requiredEnv("NATSURL", "nats://localhost:4222"),
+
+		NATSMaxReconnects:    l.getNonNegativeIntEnv("NATSMAXRECONNECTS", 0),
+		NATSReconnectWait:    l.getDurationEnv("NATSRECONNECTWAIT", 500*time.Millisecond),
+		NATSMaxReconnectWait: l.getDurationEnv("NATSMAXRECONNECTWAIT", 30*time.Second),
+		NATSConnectTimeout:   l.getDurationEnv("NATSCONNECTTIMEOUT", 5*time.Second),
+
+		Mongo: MongoConfig{
+			MaxPoolSize:            l.getIntEnv("MONGOMAXPOOLSIZE", 100),
+			MinPoolSize:            l.getNonNegativeIntEnv("MONGOMINPOOLSIZE", 5),
+			ConnectTimeout:         l.getDurationEnv("MONGOCONNECTTIMEOUT", 10*time.Second),
+			ServerSelectionTimeout: l.getDurationEnv("MONGOSERVERSELECTIONTIMEOUT", 10*time.Second),
+			SocketTimeout:          l.getDurationEnv("MONGOSOCKETTIMEOUT", 30*time.Second),
+			WriteConcern:           getEnv("MONGOWRITECONCERN", "majority"),
+			ReadConcern:            getEnv("MONGOREADCONCERN", "local"),
+			ReadTimeout:            l.getDurationEnv("MONGOREADTIMEOUT", 5*time.Second),
+			WriteTimeout:           l.getDurationEnv("MONGOWRITETIMEOUT", 10*time.Second),
+			AggregateTimeout:       l.getDurationEnv("MONGOAGGREGATETIMEOUT", 20*time.Second),
+			ConnectAttempts:        l.getIntEnv("MONGOCONNECTATTEMPTS", 5),
+			ConnectBackoff:         l.getDurationEnv("MONGOCONNECTBACKOFF", time.Second),
+		},
+
+		Engine: EngineConfig{
+			Attempts:       l.getIntEnv("ENGINEREQUESTATTEMPTS", 3),
+			AttemptTimeout: l.getDurationEnv("ENGINEATTEMPTTIMEOUT", 10*time.Second),
+			Backoff:        l.getDurationEnv("ENGINERETRYBACKOFF", 200*time.Millisecond),
+			MaxConcurrency: l.getIntEnv("ENGINEMAXCONCURRENCY", 32),
+
+			ValidationParallelism: l.getIntEnv("ENGINEVALIDATIONPARALLELISM", 4),
+
+			ContractVersion: l.getIntEnv("ENGINECONTRACTVERSION", 1),
+			TimeLimit:       l.getDurationEnv("ENGINETIMELIMIT", 5*time.Second),
+			MemoryLimitMB:   l.getIntEnv("ENGINEMEMORYLIMITMB", 256),
+		},
+
+		RESTGatewayPort:   getEnv("RESTGATEWAYPORT", ""),
+		RESTGatewaySecret: getEnv("RESTGATEWAYSECRET", ""),
+		GRPCReflection:    l.getBoolEnv("GRPCREFLECTION", true),
+
+		RateLimit: reloadable.RateLimit,
+
+		GRPCTLSCertFile:       getEnv("GRPCTLSCERTFILE", ""),
+		GRPCTLSKeyFile:        getEnv("GRPCTLSKEYFILE", ""),
+		GRPCTLSClientCAFile:   getEnv("GRPCTLSCLIENTCAFILE", ""),
+		GRPCTLSReloadInterval: l.getDurationEnv("GRPCTLSRELOADINTERVAL", time.Minute),
+
+		OTLPEndpoint:     getEnv("OTLPENDPOINT", ""),
+		OTLPInsecure:     l.getBoolEnv("OTLPINSECURE", true),
+		TraceSampleRatio: l.getFloatEnv("TRACESAMPLERATIO", 1),
+
+		SlowQueryThreshold: l.getDurationEnv("SLOWQUERYTHRESHOLD", 200*time.Millisecond),
+		SlowRPCThreshold:   l.getDurationEnv("SLOWRPCTHRESHOLD", time.Second),
 
-		Environment:            getEnv("ENVIRONMENT", "development"),
-		BetterStackSourceToken: getEnv("BETTERSTACKSOURCETOKEN", ""),
+		SoftDeletePurge:     l.getBoolEnv("SOFTDELETEPURGE", true),
+		SoftDeleteRetention: l.getDurationEnv("SOFTDELETERETENTION", 90*24*time.Hour),
+		SoftDeleteArchive:   l.getBoolEnv("SOFTDELETEARCHIVE", true),
+
+		PremiumRoles: l.getListEnv("PREMIUMROLES"),
+
+		EnforceProblemAuthorization: l.getBoolEnv("ENFORCEPROBLEMAUTHORIZATION", false),
+
+		OutboxRelayInterval: l.getDurationEnv("OUTBOXRELAYINTERVAL", time.Second),
+
+		WebhookDispatchInterval: l.getDurationEnv("WEBHOOKDISPATCHINTERVAL", 5*time.Second),
+
+		ShutdownDrainTimeout: l.getDurationEnv("SHUTDOWNDRAINTIMEOUT", 30*time.Second),
+
+		RedisURL: l.requiredEnv("REDISURL", "localhost:6379"),
+
+		RedisPassword:     l.secretEnv("REDISPASSWORD", ""),
+		RedisTLS:          l.getBoolEnv("REDISTLS", false),
+		RedisPoolSize:     l.getIntEnv("REDISPOOLSIZE", 20),
+		RedisDialTimeout:  l.getDurationEnv("REDISDIALTIMEOUT", 5*time.Second),
+		RedisReadTimeout:  l.getDurationEnv("REDISREADTIMEOUT", 3*time.Second),
+		RedisWriteTimeout: l.getDurationEnv("REDISWRITETIMEOUT", 3*time.Second),
+		RedisMaxRetries:   l.getIntEnv("REDISMAXRETRIES", 3),
+
+		LeaderboardNamespace:        getEnv("LEADERBOARDNAMESPACE", "user_Leaderboard_Unique"),
+		LeaderboardTopK:             l.getIntEnv("LEADERBOARDTOPK", 10),
+		LeaderboardMaxUsers:         l.getIntEnv("LEADERBOARDMAXUSERS", 1_000_000),
+		LeaderboardMaxEntities:      l.getIntEnv("LEADERBOARDMAXENTITIES", 200),
+		LeaderboardMaxOrganizations: l.getIntEnv("LEADERBOARDMAXORGANIZATIONS", 10_000),
+
+		Scores: ScoreConfig{
+			Easy:   l.getIntEnv("SCOREEASY", 2),
+			Medium: l.getIntEnv("SCOREMEDIUM", 4),
+			Hard:   l.getIntEnv("SCOREHARD", 6),
+		},
+		ScoreDecay:         l.getScoreDecayEnv("SCOREDECAY"),
+		MaxRunTestCases:    l.getIntEnv("MAXRUNTESTCASES", 3),
+		MaxSubmitTestCases: l.getIntEnv("MAXSUBMITTESTCASES", 100),
+
+		Cron: reloadable.Cron,
+
+		Features: reloadable.Features,
+
+		CacheTTL:    reloadable.CacheTTL,
+		CacheL1Size: l.getIntEnv("CACHEL1SIZE", 1000),
+		CacheL1TTL:  l.getDurationEnv("CACHEL1TTL", 2*time.Second),
+
+		CacheWarmPages:    l.getNonNegativeIntEnv("CACHEWARMPAGES", 0),
+		CacheWarmPageSize: l.getIntEnv("CACHEWARMPAGESIZE", 10),
+
+		LogLevel:            reloadable.LogLevel,
+		LogSampleFirst:      l.getNonNegativeIntEnv("LOGSAMPLEFIRST", 20),
+		LogSampleThereafter: l.getIntEnv("LOGSAMPLETHEREAFTER", 100),
+		LogSampleTick:       l.getDurationEnv("LOGSAMPLETICK", time.Second),
+
+		LogShipQueueSize:     l.getIntEnv("LOGSHIPQUEUESIZE", 10000),
+		LogShipBatchSize:     l.getIntEnv("LOGSHIPBATCHSIZE", 100),
+		LogShipFlushInterval: l.getDurationEnv("LOGSHIPFLUSHINTERVAL", time.Second),
+		LogShipMaxRetries:    l.getNonNegativeIntEnv("LOGSHIPMAXRETRIES", 3),
+
+		Environment:            environment,
+		BetterStackSourceToken: l.secretEnv("BETTERSTACKSOURCETOKEN", ""),
 		BetterStackUploadURL:   getEnv("BETTERSTACKUPLOADURL", ""),
+
+		Secrets:                secretOpts,
+		SecretsRefreshInterval: l.getDurationEnv("SECRETSREFRESHINTERVAL", 5*time.Minute),
+		SecretsProvider:        provider,
+		SecretValues:           secretValues,
+
+		ConfigWatch:         l.getBoolEnv("CONFIGWATCH", true),
+		ConfigWatchInterval: l.getDurationEnv("CONFIGWATCHINTERVAL", 10*time.Second),
 	}
 
-	// fmt.Println(config)
-	return config
+	l.errs = append(l.errs, config.validate()...)
+	if len(l.errs) > 0 {
+		return Config{}, fmt.Errorf("invalid configuration: %w", errors.Join(l.errs...))
+	}
+	return config, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -48,3 +428,145 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// loader reads typed values from the environment and collects the invalid ones, so all of them are reported at once
+type loader struct {
+	development bool              // required values fall back to their development defaults
+	secrets     map[string]string // values from the secret store, preferred over the environment
+	errs        []error
+}
+
+func (l *loader) invalid(key, value, want string) {
+	l.errs = append(l.errs, fmt.Errorf("%s=%q is not %s", key, value, want))
+}
+
+// requiredEnv is a value that must be set outside development; developmentDefault points at a local setup
+func (l *loader) requiredEnv(key, developmentDefault string) string {
+	if value := l.secretEnv(key, ""); value != "" {
+		return value
+	}
+	if !l.development {
+		l.errs = append(l.errs, fmt.Errorf("%s is required", key))
+	}
+	return developmentDefault
+}
+
+// secretEnv prefers the secret store's value of key over the environment's
+func (l *loader) secretEnv(key, defaultValue string) string {
+	if value, ok := l.secrets[key]; ok && value != "" {
+		return value
+	}
+	return getEnv(key, defaultValue)
+}
+
+// getDurationEnv reads a positive Go duration such as "30s" or "5m"
+func (l *loader) getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		l.invalid(key, value, "a positive duration")
+		return defaultValue
+	}
+	return duration
+}
+
+func (l *loader) getIntEnv(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		l.invalid(key, value, "a positive integer")
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) getNonNegativeIntEnv(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		l.invalid(key, value, "a non-negative integer")
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) getFloatEnv(key string, defaultValue float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f <= 0 {
+		l.invalid(key, value, "a positive number")
+		return defaultValue
+	}
+	return f
+}
+
+// getBoolEnv accepts the forms strconv.ParseBool does, e.g. true, false, 1 and 0
+func (l *loader) getBoolEnv(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid(key, value, "a boolean")
+		return defaultValue
+	}
+	return b
+}
+
+// getListEnv reads a comma separated list, nil when unset
+func (l *loader) getListEnv(key string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getScoreDecayEnv reads a comma separated list of period:grace:halfLife[:minFactor] entries, e.g.
+// "all:720h:2160h:0.2", nil when unset. minFactor defaults to 0, letting scores decay towards nothing.
+func (l *loader) getScoreDecayEnv(key string) map[string]ScoreDecayConfig {
+	items := l.getListEnv(key)
+	if len(items) == 0 {
+		return nil
+	}
+	decay := make(map[string]ScoreDecayConfig, len(items))
+	for _, item := range items {
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 && len(parts) != 4 {
+			l.invalid(key, item, "period:grace:halfLife[:minFactor]")
+			continue
+		}
+		grace, graceErr := time.ParseDuration(parts[1])
+		halfLife, halfLifeErr := time.ParseDuration(parts[2])
+		minFactor := 0.0
+		var minFactorErr error
+		if len(parts) == 4 {
+			minFactor, minFactorErr = strconv.ParseFloat(parts[3], 64)
+		}
+		if graceErr != nil || grace < 0 || halfLifeErr != nil || halfLife <= 0 || minFactorErr != nil || minFactor < 0 || minFactor > 1 {
+			l.invalid(key, item, "period:grace:halfLife[:minFactor] with a non-negative grace, a positive half-life and a minFactor from 0 to 1")
+			continue
+		}
+		decay[parts[0]] = ScoreDecayConfig{Grace: grace, HalfLife: halfLife, MinFactor: minFactor}
+	}
+	return decay
+}
@@ -0,0 +1,39 @@
+package configs
+
+// FeatureFlags turns features that are still being rolled out on and off. Each ENVIRONMENT starts from its profile in
+// featureProfiles and FEATURE<NAME>, e.g. FEATURENEWSCORING, overrides a single flag. Flags are reloadable.
+type FeatureFlags struct {
+	AsyncSubmissions bool `json:"asyncSubmissions"` // submissions are acknowledged before they are judged
+	PlagiarismChecks bool `json:"plagiarismChecks"` // accepted submissions are compared against earlier ones
+	NewScoring       bool `json:"newScoring"`       // scores use the revised difficulty weights
+}
+
+// featureProfiles are the defaults per ENVIRONMENT; environments not listed get the production profile, so an
+// unfinished feature is never switched on by a typo
+var featureProfiles = map[string]FeatureFlags{
+	"development": {AsyncSubmissions: true, PlagiarismChecks: true, NewScoring: true},
+	"staging":     {AsyncSubmissions: true, PlagiarismChecks: true},
+	"production":  {},
+}
+
+// Map returns the flags by their JSON name, for clients that check flags they know and ignore the rest
+func (f FeatureFlags) Map() map[string]bool {
+	return map[string]bool{
+		"asyncSubmissions": f.AsyncSubmissions,
+		"plagiarismChecks": f.PlagiarismChecks,
+		"newScoring":       f.NewScoring,
+	}
+}
+
+// featureFlags reads the flags for environment, starting from its profile
+func (l *loader) featureFlags(environment string) FeatureFlags {
+	profile, ok := featureProfiles[environment]
+	if !ok {
+		profile = featureProfiles["production"]
+	}
+	return FeatureFlags{
+		AsyncSubmissions: l.getBoolEnv("FEATUREASYNCSUBMISSIONS", profile.AsyncSubmissions),
+		PlagiarismChecks: l.getBoolEnv("FEATUREPLAGIARISMCHECKS", profile.PlagiarismChecks),
+		NewScoring:       l.getBoolEnv("FEATURENEWSCORING", profile.NewScoring),
+	}
+}
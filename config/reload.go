@@ -0,0 +1,188 @@
+package configs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	cron "github.com/robfig/cron/v3"
+	"go.uber.org/zap/zapcore"
+)
+
+// envFile is the file LoadConfig and LoadReloadable read besides the environment
+const envFile = ".env"
+
+// startupEnv holds the names of the variables the process was started with. They take precedence over the .env file,
+// also when it is read again, so a reload never replaces a value set by the deployment.
+var startupEnv = func() map[string]bool {
+	names := map[string]bool{}
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		names[name] = true
+	}
+	return names
+}()
+
+// Reloadable is the part of the configuration that is safe to change while the service runs
+type Reloadable struct {
+	LogLevel  string
+	CacheTTL  CacheTTLConfig
+	RateLimit RateLimitConfig
+	Cron      CronConfig
+	Features  FeatureFlags
+}
+
+// reloadable reads the settings of Reloadable, shared by LoadConfig and LoadReloadable so both apply the same
+// defaults
+func (l *loader) reloadable() Reloadable {
+	return Reloadable{
+		LogLevel: getEnv("LOGLEVEL", "info"),
+		CacheTTL: CacheTTLConfig{
+			Problem:     l.getDurationEnv("CACHETTLPROBLEM", 5*time.Second),
+			List:        l.getDurationEnv("CACHETTLLIST", 5*time.Second),
+			Stats:       l.getDurationEnv("CACHETTLSTATS", 5*time.Second),
+			Leaderboard: l.getDurationEnv("CACHETTLLEADERBOARD", 5*time.Minute),
+			ListStale:   l.getDurationEnv("CACHESTALELIST", 30*time.Second),
+
+			LanguageStats: l.getDurationEnv("CACHETTLLANGUAGESTATS", 10*time.Minute),
+			Profile:       l.getDurationEnv("CACHETTLPROFILE", 10*time.Minute),
+			Entitlement:   l.getDurationEnv("CACHETTLENTITLEMENT", 5*time.Minute),
+
+			ExecutionResult: l.getDurationEnv("CACHETTLEXECUTIONRESULT", 10*time.Minute),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:   l.getBoolEnv("RATELIMITENABLED", true),
+			RunRate:   l.getFloatEnv("RATELIMITRUNRATE", 0.5),
+			RunBurst:  l.getIntEnv("RATELIMITRUNBURST", 5),
+			ReadRate:  l.getFloatEnv("RATELIMITREADRATE", 50),
+			ReadBurst: l.getIntEnv("RATELIMITREADBURST", 100),
+			Whitelist: l.getListEnv("RATELIMITWHITELIST"),
+
+			TrustedProxyHops: l.getIntEnv("RATELIMITTRUSTEDPROXYHOPS", 1),
+		},
+		Cron: CronConfig{
+			LeaderboardSync:         getEnv("CRONLEADERBOARDSYNC", "@every 1h"),
+			LeaderboardSnapshot:     getEnv("CRONLEADERBOARDSNAPSHOT", "CRON_TZ=UTC 5 0 * * *"),
+			DraftFlush:              getEnv("CRONDRAFTFLUSH", "@every 5m"),
+			LeaderboardOutbox:       getEnv("CRONLEADERBOARDOUTBOX", "@every 1m"),
+			SoftDeletePurge:         getEnv("CRONSOFTDELETEPURGE", "CRON_TZ=UTC 30 3 * * *"),
+			ContestFinalize:         getEnv("CRONCONTESTFINALIZE", "@every 1m"),
+			DifficultyRecalibration: getEnv("CRONDIFFICULTYRECALIBRATION", "CRON_TZ=UTC 0 4 * * *"),
+			FirstSuccessCheck:       getEnv("CRONFIRSTSUCCESSCHECK", "CRON_TZ=UTC 30 4 * * *"),
+		},
+		Features: l.featureFlags(getEnv("ENVIRONMENT", "development")),
+	}
+}
+
+// Reloadable returns the settings of c that LoadReloadable can change later
+func (c Config) Reloadable() Reloadable {
+	return Reloadable{LogLevel: c.LogLevel, CacheTTL: c.CacheTTL, RateLimit: c.RateLimit, Cron: c.Cron, Features: c.Features}
+}
+
+// LoadReloadable reads the settings of Reloadable again, picking up edits to the .env file. A variable removed from
+// the file keeps the value it had; as at startup, variables set in the environment win over the file. Like
+// LoadConfig it fails on any value that does not parse or cannot work, so a bad edit leaves the running settings
+// alone.
+func LoadReloadable() (Reloadable, error) {
+	values, err := godotenv.Read(envFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Reloadable{}, fmt.Errorf("failed to read .env file: %w", err)
+	}
+	for key, value := range values {
+		if startupEnv[key] {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return Reloadable{}, fmt.Errorf("failed to set %s from .env file: %w", key, err)
+		}
+	}
+
+	l := &loader{}
+	reloadable := l.reloadable()
+	l.errs = append(l.errs, reloadable.validate()...)
+	if len(l.errs) > 0 {
+		return Reloadable{}, fmt.Errorf("invalid configuration: %w", errors.Join(l.errs...))
+	}
+	return reloadable, nil
+}
+
+// validate checks the reloadable values that parsed but cannot work
+func (r Reloadable) validate() []error {
+	var errs []error
+	if _, err := zapcore.ParseLevel(r.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("LOGLEVEL=%q is not a log level", r.LogLevel))
+	}
+	schedules := []struct{ key, value string }{
+		{"CRONLEADERBOARDSYNC", r.Cron.LeaderboardSync},
+		{"CRONLEADERBOARDSNAPSHOT", r.Cron.LeaderboardSnapshot},
+		{"CRONDRAFTFLUSH", r.Cron.DraftFlush},
+		{"CRONLEADERBOARDOUTBOX", r.Cron.LeaderboardOutbox},
+		{"CRONSOFTDELETEPURGE", r.Cron.SoftDeletePurge},
+		{"CRONCONTESTFINALIZE", r.Cron.ContestFinalize},
+		{"CRONDIFFICULTYRECALIBRATION", r.Cron.DifficultyRecalibration},
+		{"CRONFIRSTSUCCESSCHECK", r.Cron.FirstSuccessCheck},
+	}
+	for _, schedule := range schedules {
+		if _, err := cron.ParseStandard(schedule.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q is not a cron schedule: %w", schedule.key, schedule.value, err))
+		}
+	}
+	if r.RateLimit.TrustedProxyHops < 0 {
+		errs = append(errs, fmt.Errorf("RATELIMITTRUSTEDPROXYHOPS %d must not be negative", r.RateLimit.TrustedProxyHops))
+	}
+	return errs
+}
+
+// Changed names the groups of settings (logLevel, cacheTTL, rateLimit, cron, features) that differ from previous
+func (r Reloadable) Changed(previous Reloadable) []string {
+	var changed []string
+	if r.LogLevel != previous.LogLevel {
+		changed = append(changed, "logLevel")
+	}
+	if r.CacheTTL != previous.CacheTTL {
+		changed = append(changed, "cacheTTL")
+	}
+	if !reflect.DeepEqual(r.RateLimit, previous.RateLimit) {
+		changed = append(changed, "rateLimit")
+	}
+	if r.Cron != previous.Cron {
+		changed = append(changed, "cron")
+	}
+	if r.Features != previous.Features {
+		changed = append(changed, "features")
+	}
+	return changed
+}
+
+// WatchEnvFile calls onChange whenever the modification time of the .env file changes, including when it is
+// created or removed, checking every interval until ctx is done
+func WatchEnvFile(ctx context.Context, interval time.Duration, onChange func()) {
+	modified := func() time.Time {
+		info, err := os.Stat(envFile)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	last := modified()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if current := modified(); !current.Equal(last) {
+				last = current
+				onChange()
+			}
+		}
+	}
+}
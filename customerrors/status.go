@@ -0,0 +1,57 @@
+package customerrors
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain identifies this service in the ErrorInfo of every error it returns
+const ErrorDomain = "problems.xcode"
+
+// Status returns a gRPC error whose message is meant for people and whose ErrorInfo carries the machine readable
+// errorType as its reason, plus any further details such as a BadRequest or RetryInfo
+func Status(code codes.Code, errorType, message string, metadata map[string]string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, message)
+	all := append([]protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   errorType,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	}}, details...)
+	withDetails, err := st.WithDetails(all...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// BadRequest marks every field as violating the same rule, e.g. BadRequest("is required", "problem_id")
+func BadRequest(description string, fields ...string) *errdetails.BadRequest {
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: description,
+		})
+	}
+	return badRequest
+}
+
+// RetryAfter tells the caller how long to wait before retrying
+func RetryAfter(delay time.Duration) *errdetails.RetryInfo {
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}
+}
+
+// ErrorType returns the reason of the error's ErrorInfo, or "" when it has none
+func ErrorType(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package customerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The sentinels every layer wraps its failures in, so the handler can tell a missing problem from a broken database
+// without matching on messages. Check them with errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("invalid request")
+	ErrDownstream = errors.New("downstream failure")
+)
+
+// NotFound reports that the thing the caller asked for does not exist, e.g. NotFound("problem %s", id)
+func NotFound(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrNotFound, fmt.Sprintf(format, args...))
+}
+
+// Conflict reports that the request clashes with the current state, e.g. a duplicate title
+func Conflict(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrConflict, fmt.Sprintf(format, args...))
+}
+
+// Validation reports that the request itself is wrong and retrying it unchanged cannot succeed
+func Validation(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, args...))
+}
+
+// Downstream reports that a dependency such as MongoDB, Redis or the execution engine failed while serving the request
+func Downstream(dependency string, err error) error {
+	return fmt.Errorf("%w: %s: %w", ErrDownstream, dependency, err)
+}
+
+// Code maps an error onto its gRPC code. This is the one place the taxonomy meets the transport; errors that already
+// carry a gRPC status keep their code.
+func Code(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, ErrConflict):
+		return codes.AlreadyExists
+	case errors.Is(err, ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, ErrDownstream):
+		return codes.Unavailable
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	return codes.Internal
+}
+
+// Type returns the errorType reported in the ErrorInfo for err
+func Type(err error) string {
+	switch Code(err) {
+	case codes.NotFound:
+		return "NOT_FOUND"
+	case codes.AlreadyExists:
+		return "CONFLICT"
+	case codes.InvalidArgument:
+		return "VALIDATION_ERROR"
+	case codes.DeadlineExceeded:
+		return "TIMEOUT"
+	case codes.Canceled:
+		return "CANCELED"
+	case codes.Unavailable:
+		return "DOWNSTREAM_ERROR"
+	}
+	return "INTERNAL_ERROR"
+}
+
+// PublicMessage returns the text of errors written for the caller (not found, conflict and validation) and fallback for
+// everything else, so driver and network errors never reach the client verbatim
+func PublicMessage(err error, fallback string) string {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) || errors.Is(err, ErrValidation) {
+		return err.Error()
+	}
+	return fallback
+}
@@ -0,0 +1,270 @@
+// Package gateway exposes the ProblemsService as JSON over HTTP, so internal tools and webhooks can call it
+// without a gRPC client. Every RPC is served at POST /v1/<Method> and runs through the same interceptors as gRPC;
+// services without a proto definition, such as the admin API, can be added under their own path with Register.
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/interceptor"
+
+	"github.com/google/uuid"
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	pathPrefix   = "/v1/"
+	maxBodyBytes = 4 << 20
+)
+
+// SecretHeader carries the shared secret the API gateway proves itself with
+const SecretHeader = "x-gateway-secret"
+
+// forwardedHeaders are copied from the HTTP request into the incoming gRPC metadata
+var forwardedHeaders = []string{interceptor.TraceIDHeader, "x-forwarded-for", "idempotency-key", "authorization", "accept-language", interceptor.APIKeyHeader}
+
+// identityHeaders say who the caller is and what they may do, so they are only copied from authenticated callers
+var identityHeaders = []string{"x-user-id", "x-user-entitlements"}
+
+// Authenticator reports whether r comes from a caller trusted to assert a user's identity
+type Authenticator func(r *http.Request) bool
+
+// SharedSecret trusts requests carrying secret in x-gateway-secret; an empty secret trusts none
+func SharedSecret(secret string) Authenticator {
+	return func(r *http.Request) bool {
+		value := r.Header.Get(SecretHeader)
+		return secret != "" && value != "" && subtle.ConstantTimeCompare([]byte(value), []byte(secret)) == 1
+	}
+}
+
+// APIKeys trusts requests carrying an active API key in x-api-key
+func APIKeys(keys interceptor.APIKeyValidator) Authenticator {
+	return func(r *http.Request) bool {
+		secret := strings.TrimSpace(r.Header.Get(interceptor.APIKeyHeader))
+		if secret == "" || keys == nil {
+			return false
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		key, err := keys.ValidateAPIKey(ctx, secret)
+		return err == nil && key != nil && key.Active(time.Now())
+	}
+}
+
+// AnyOf trusts requests any of authenticators trusts
+func AnyOf(authenticators ...Authenticator) Authenticator {
+	return func(r *http.Request) bool {
+		for _, authenticate := range authenticators {
+			if authenticate != nil && authenticate(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Gateway translates JSON requests into calls on the service's generated method handlers
+type Gateway struct {
+	methods      map[string]route // by path below /v1/
+	unary        grpc.UnaryServerInterceptor
+	authenticate Authenticator
+	openAPI      []byte
+}
+
+// New serves srv; unary is applied to every call, usually interceptor.Chain of the server's interceptors. The
+// x-user-id and x-user-entitlements headers are dropped unless authenticate trusts the request; a nil
+// authenticate trusts none.
+func New(srv pb.ProblemsServiceServer, unary grpc.UnaryServerInterceptor, authenticate Authenticator) (*Gateway, error) {
+	openAPI, err := openAPIDocument()
+	if err != nil {
+		return nil, err
+	}
+	g := &Gateway{methods: map[string]route{}, unary: unary, authenticate: authenticate, openAPI: openAPI}
+	g.Register("", &pb.ProblemsService_ServiceDesc, srv)
+	return g, nil
+}
+
+// route is a method handler and the server it is called on
+type route struct {
+	srv    any
+	method grpc.MethodDesc
+}
+
+// Register serves the unary methods of desc on srv at POST /v1/<prefix>/<Method>, or /v1/<Method> for an empty
+// prefix. Requests and responses that are not proto messages are read and written with encoding/json.
+func (g *Gateway) Register(prefix string, desc *grpc.ServiceDesc, srv any) {
+	if prefix != "" {
+		prefix += "/"
+	}
+	for _, method := range desc.Methods {
+		g.methods[prefix+method.MethodName] = route{srv: srv, method: method}
+	}
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/openapi.json" && r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(g.openAPI)
+		return
+	}
+
+	route, ok := g.methods[strings.TrimPrefix(r.URL.Path, pathPrefix)]
+	if !strings.HasPrefix(r.URL.Path, pathPrefix) || !ok {
+		writeError(w, "", status.Error(codes.NotFound, "unknown method"))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, "", status.Error(codes.Unimplemented, "only POST is supported"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, "", status.Errorf(codes.InvalidArgument, "failed to read request body: %v", err))
+		return
+	}
+
+	md := metadata.MD{}
+	for _, header := range forwardedHeaders {
+		if value := r.Header.Get(header); value != "" {
+			md.Set(header, value)
+		}
+	}
+	// like any proxy the gateway appends the address it was called from, which the rate limiter counts hops from
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			host = forwarded[0] + ", " + host
+		}
+		md.Set("x-forwarded-for", host)
+	}
+	if g.authenticate != nil && g.authenticate(r) {
+		for _, header := range identityHeaders {
+			if value := r.Header.Get(header); value != "" {
+				md.Set(header, value)
+			}
+		}
+	}
+	// the gRPC side cannot return headers to an HTTP caller, so the trace ID is fixed here and echoed back
+	traceID := r.Header.Get(interceptor.TraceIDHeader)
+	if traceID == "" {
+		traceID = uuid.New().String()
+		md.Set(interceptor.TraceIDHeader, traceID)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: remoteAddr(r.RemoteAddr)})
+
+	resp, err := route.method.Handler(route.srv, ctx, decoder(body), g.unary)
+	if err != nil {
+		writeError(w, traceID, err)
+		return
+	}
+	out, err := encode(resp)
+	if err != nil {
+		writeError(w, traceID, status.Errorf(codes.Internal, "failed to encode response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(interceptor.TraceIDHeader, traceID)
+	w.Write(out)
+}
+
+// decoder fills the handler's request from the JSON body; an empty body leaves every field unset
+func decoder(body []byte) func(any) error {
+	return func(v any) error {
+		if len(body) == 0 {
+			return nil
+		}
+		var err error
+		if message, ok := v.(proto.Message); ok {
+			err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, message)
+		} else {
+			err = json.Unmarshal(body, v)
+		}
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+		}
+		return nil
+	}
+}
+
+// encode writes a handler's response as JSON, with protojson for proto messages
+func encode(resp any) ([]byte, error) {
+	if message, ok := resp.(proto.Message); ok {
+		return protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(message)
+	}
+	return json.Marshal(resp)
+}
+
+type errorBody struct {
+	Code      string `json:"code"`
+	ErrorType string `json:"errorType,omitempty"`
+	Message   string `json:"message"`
+	TraceID   string `json:"traceId,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, traceID string, err error) {
+	st := status.Convert(err)
+	w.Header().Set("Content-Type", "application/json")
+	if traceID != "" {
+		w.Header().Set(interceptor.TraceIDHeader, traceID)
+	}
+	w.WriteHeader(httpStatus(st.Code()))
+	json.NewEncoder(w).Encode(errorBody{
+		Code:      st.Code().String(),
+		ErrorType: customerrors.ErrorType(err),
+		Message:   st.Message(),
+		TraceID:   traceID,
+	})
+}
+
+// httpStatus maps gRPC codes the way grpc-gateway does, so callers see familiar statuses
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// remoteAddr lets the HTTP client address stand in for the gRPC peer, which the rate limiter keys on
+type remoteAddr string
+
+func (a remoteAddr) Network() string { return "tcp" }
+func (a remoteAddr) String() string  { return string(a) }
@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// recordingServer keeps the metadata GetProblem was called with
+type recordingServer struct {
+	pb.UnimplementedProblemsServiceServer
+	md metadata.MD
+}
+
+func (s *recordingServer) GetProblem(ctx context.Context, _ *pb.GetProblemRequest) (*pb.GetProblemResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	return &pb.GetProblemResponse{}, nil
+}
+
+type staticKeys map[string]*model.APIKey
+
+func (k staticKeys) ValidateAPIKey(_ context.Context, secret string) (*model.APIKey, error) {
+	if key, ok := k[secret]; ok {
+		return key, nil
+	}
+	return nil, customerrors.NotFound("api key")
+}
+
+func passThrough(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(ctx, req)
+}
+
+func TestIdentityHeadersRequireAuthentication(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour)
+	keys := staticKeys{
+		"live-key":    {Scopes: []string{model.APIKeyScopeReadProblems}},
+		"revoked-key": {Scopes: []string{model.APIKeyScopeReadProblems}, RevokedAt: &revokedAt},
+	}
+	authenticate := AnyOf(SharedSecret("gateway-secret"), APIKeys(keys))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		trusted bool
+	}{
+		{name: "no credentials", trusted: false},
+		{name: "wrong shared secret", headers: map[string]string{SecretHeader: "guess"}, trusted: false},
+		{name: "unknown api key", headers: map[string]string{"x-api-key": "guess"}, trusted: false},
+		{name: "revoked api key", headers: map[string]string{"x-api-key": "revoked-key"}, trusted: false},
+		{name: "shared secret", headers: map[string]string{SecretHeader: "gateway-secret"}, trusted: true},
+		{name: "active api key", headers: map[string]string{"x-api-key": "live-key"}, trusted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &recordingServer{}
+			g, err := New(srv, passThrough, authenticate)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/v1/GetProblem", strings.NewReader(`{}`))
+			r.Header.Set("x-user-id", "admin-user")
+			r.Header.Set("x-user-entitlements", "premium")
+			r.Header.Set("accept-language", "en")
+			for header, value := range tt.headers {
+				r.Header.Set(header, value)
+			}
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+
+			for _, header := range identityHeaders {
+				got := srv.md.Get(header)
+				if tt.trusted && len(got) != 1 {
+					t.Errorf("%s = %v, want it forwarded", header, got)
+				}
+				if !tt.trusted && len(got) != 0 {
+					t.Errorf("%s = %v, want the spoofed value dropped", header, got)
+				}
+			}
+			if got := srv.md.Get("accept-language"); len(got) != 1 || got[0] != "en" {
+				t.Errorf("accept-language = %v, want en", got)
+			}
+		})
+	}
+}
+
+func TestIdentityHeadersDroppedWithoutAuthenticator(t *testing.T) {
+	srv := &recordingServer{}
+	g, err := New(srv, passThrough, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/v1/GetProblem", strings.NewReader(`{}`))
+	r.Header.Set("x-user-id", "admin-user")
+	g.ServeHTTP(httptest.NewRecorder(), r)
+	if got := srv.md.Get("x-user-id"); len(got) != 0 {
+		t.Errorf("x-user-id = %v, want it dropped", got)
+	}
+}
+
+func TestSharedSecretEmptyTrustsNone(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/GetProblem", nil)
+	if SharedSecret("")(r) {
+		t.Error("empty secret trusted a request without x-gateway-secret")
+	}
+}
+
+func TestAPIKeysValidatorError(t *testing.T) {
+	failing := validatorFunc(func(context.Context, string) (*model.APIKey, error) {
+		return nil, errors.New("mongo down")
+	})
+	r := httptest.NewRequest(http.MethodPost, "/v1/GetProblem", nil)
+	r.Header.Set("x-api-key", "live-key")
+	if APIKeys(failing)(r) {
+		t.Error("a key that failed validation was trusted")
+	}
+}
+
+type validatorFunc func(context.Context, string) (*model.APIKey, error)
+
+func (f validatorFunc) ValidateAPIKey(ctx context.Context, secret string) (*model.APIKey, error) {
+	return f(ctx, secret)
+}
+
+func TestForwardedForEndsWithCaller(t *testing.T) {
+	srv := &recordingServer{}
+	g, err := New(srv, passThrough, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/v1/GetProblem", strings.NewReader(`{}`))
+	r.RemoteAddr = "198.51.100.4:5000"
+	r.Header.Set("x-forwarded-for", "6.6.6.6")
+	g.ServeHTTP(httptest.NewRecorder(), r)
+	if got := srv.md.Get("x-forwarded-for"); len(got) != 1 || got[0] != "6.6.6.6, 198.51.100.4" {
+		t.Errorf("x-forwarded-for = %v, want the caller appended", got)
+	}
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPIDocument describes every RPC of the gateway as an OpenAPI 3 document, generated from the proto descriptors
+// so it always matches the compiled service
+func openAPIDocument() ([]byte, error) {
+	service := pb.File_ProblemsService_problemsservice_proto.Services().ByName("ProblemsService")
+	if service == nil {
+		return nil, fmt.Errorf("ProblemsService descriptor not found")
+	}
+
+	schemas := map[string]any{}
+	paths := map[string]any{}
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			continue
+		}
+		paths[pathPrefix+string(method.Name())] = map[string]any{
+			"post": map[string]any{
+				"operationId": string(method.Name()),
+				"tags":        []string{string(service.Name())},
+				"requestBody": map[string]any{
+					"required": true,
+					"content":  jsonContent(messageSchema(method.Input(), schemas)),
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "OK",
+						"content":     jsonContent(messageSchema(method.Output(), schemas)),
+					},
+					"default": map[string]any{
+						"description": "gRPC error mapped to an HTTP status",
+						"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+					},
+				},
+			},
+		}
+	}
+	schemas["Error"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"code":      map[string]any{"type": "string"},
+			"errorType": map[string]any{"type": "string"},
+			"message":   map[string]any{"type": "string"},
+			"traceId":   map[string]any{"type": "string"},
+		},
+	}
+
+	return json.Marshal(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "ProblemsService REST gateway",
+			"version": "v1",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	})
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// messageSchema registers md and every message it references in schemas and returns a reference to it
+func messageSchema(md protoreflect.MessageDescriptor, schemas map[string]any) map[string]any {
+	name := schemaName(md)
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := schemas[name]; ok {
+		return ref
+	}
+	properties := map[string]any{}
+	schema := map[string]any{"type": "object", "properties": properties}
+	// registered before the fields so recursive messages terminate
+	schemas[name] = schema
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		properties[fd.JSONName()] = fieldSchema(fd, schemas)
+	}
+	return ref
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	if fd.IsMap() {
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": singularSchema(fd.MapValue(), schemas),
+		}
+	}
+	if fd.IsList() {
+		return map[string]any{"type": "array", "items": singularSchema(fd, schemas)}
+	}
+	return singularSchema(fd, schemas)
+}
+
+// singularSchema follows the protojson encoding, which is why 64-bit integers are strings
+func singularSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageSchema(fd.Message(), schemas)
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// schemaName drops the proto package, keeping nested messages qualified by their parent
+func schemaName(md protoreflect.MessageDescriptor) string {
+	return strings.TrimPrefix(string(md.FullName()), string(md.ParentFile().Package())+".")
+}
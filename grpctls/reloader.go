@@ -0,0 +1,139 @@
+// Package grpctls builds the TLS configuration of the gRPC listener and reloads certificates when their files change
+package grpctls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	zap_betterstack "xcode/logger"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Options points at PEM files on disk; ClientCAFile is optional and turns on mutual TLS
+type Options struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string        // when set, clients must present a certificate signed by one of these CAs
+	ReloadInterval time.Duration // how often the files are checked for changes
+}
+
+// Reloader serves the most recently loaded certificates; new handshakes pick up a reload, open connections keep theirs
+type Reloader struct {
+	opts    Options
+	current atomic.Pointer[tls.Config]
+	modTime time.Time
+	logger  *zap_betterstack.BetterStackLogStreamer
+	stop    chan struct{}
+}
+
+// NewReloader loads the certificates once and fails when they cannot be used
+func NewReloader(opts Options, logger *zap_betterstack.BetterStackLogStreamer) (*Reloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("tls certificate and key files are required")
+	}
+	r := &Reloader{opts: opts, logger: logger, stop: make(chan struct{})}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig is the configuration to hand to the server credentials
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Watch checks the files every ReloadInterval until Close; a failed reload keeps the previous certificates
+func (r *Reloader) Watch() {
+	if r.opts.ReloadInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.opts.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			modTime, err := r.latestModTime()
+			if err != nil || !modTime.After(r.modTime) {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				r.logger.Log(zapcore.ErrorLevel, "", "Failed to reload TLS certificates, keeping the previous ones", map[string]any{
+					"method":    "Watch",
+					"certFile":  r.opts.CertFile,
+					"errorType": "TLS_ERROR",
+				}, "GRPC", err)
+				continue
+			}
+			r.logger.Log(zapcore.InfoLevel, "", "Reloaded TLS certificates", map[string]any{
+				"method":   "Watch",
+				"certFile": r.opts.CertFile,
+			}, "GRPC", nil)
+		}
+	}
+}
+
+func (r *Reloader) Close() {
+	close(r.stop)
+}
+
+func (r *Reloader) reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load tls key pair: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if r.opts.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("no certificates found in client ca file %s", r.opts.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	r.current.Store(config)
+	r.modTime = modTime
+	return nil
+}
+
+// latestModTime is the newest modification time of the configured files, so replacing any one triggers a reload
+func (r *Reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
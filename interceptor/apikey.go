@@ -0,0 +1,113 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	zap_betterstack "xcode/logger"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyHeader carries the secret of an API key
+const APIKeyHeader = "x-api-key"
+
+// APIKeyValidator looks up the key a secret belongs to, returning a customerrors.NotFound error for unknown secrets
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, secret string) (*model.APIKey, error)
+}
+
+// methodScopes lists the scope an API key needs for each RPC; the admin service needs model.APIKeyScopeAdmin and
+// every other RPC not listed needs model.APIKeyScopeReadProblems
+var methodScopes = map[string]string{
+	pb.ProblemsService_CreateProblem_FullMethodName:                     model.APIKeyScopeAdmin,
+	pb.ProblemsService_UpdateProblem_FullMethodName:                     model.APIKeyScopeAdmin,
+	pb.ProblemsService_DeleteProblem_FullMethodName:                     model.APIKeyScopeAdmin,
+	pb.ProblemsService_AddTestCases_FullMethodName:                      model.APIKeyScopeAdmin,
+	pb.ProblemsService_DeleteTestCase_FullMethodName:                    model.APIKeyScopeAdmin,
+	pb.ProblemsService_AddLanguageSupport_FullMethodName:                model.APIKeyScopeAdmin,
+	pb.ProblemsService_UpdateLanguageSupport_FullMethodName:             model.APIKeyScopeAdmin,
+	pb.ProblemsService_RemoveLanguageSupport_FullMethodName:             model.APIKeyScopeAdmin,
+	pb.ProblemsService_FullValidationByProblemID_FullMethodName:         model.APIKeyScopeAdmin,
+	pb.ProblemsService_ForceChangeUserEntityInSubmission_FullMethodName: model.APIKeyScopeAdmin,
+	pb.ProblemsService_RunUserCodeProblem_FullMethodName:                model.APIKeyScopeSubmit,
+	pb.ProblemsService_CreateChallenge_FullMethodName:                   model.APIKeyScopeSubmit,
+	pb.ProblemsService_JoinChallenge_FullMethodName:                     model.APIKeyScopeSubmit,
+	pb.ProblemsService_StartChallenge_FullMethodName:                    model.APIKeyScopeSubmit,
+	pb.ProblemsService_EndChallenge_FullMethodName:                      model.APIKeyScopeSubmit,
+}
+
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the API key the request was authenticated with, nil when it carried none
+func APIKeyFromContext(ctx context.Context) *model.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*model.APIKey)
+	return key
+}
+
+// APIKeyAuth checks the API key of requests that carry one in x-api-key: unknown, revoked and expired keys are
+// rejected with Unauthenticated, keys without the method's scope with PermissionDenied. Requests without a key
+// pass through unchanged, as callers behind the API gateway are identified by it.
+func APIKeyAuth(keys APIKeyValidator, logger *zap_betterstack.BetterStackLogStreamer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		secret := apiKeyFromMetadata(ctx)
+		if secret == "" {
+			return handler(ctx, req)
+		}
+
+		key, err := keys.ValidateAPIKey(ctx, secret)
+		if err != nil && !errors.Is(err, customerrors.ErrNotFound) {
+			logger.Log(zapcore.ErrorLevel, TraceIDFromContext(ctx), "Failed to validate API key", map[string]any{
+				"method":    info.FullMethod,
+				"errorType": customerrors.Type(err),
+			}, "GRPC", err)
+			return nil, customerrors.Status(codes.Unavailable, "AUTH_UNAVAILABLE", "failed to validate API key", nil)
+		}
+		if key == nil || !key.Active(time.Now()) {
+			logger.Log(zapcore.WarnLevel, TraceIDFromContext(ctx), "Rejected invalid API key", map[string]any{
+				"method":    info.FullMethod,
+				"errorType": "UNAUTHENTICATED",
+			}, "GRPC", nil)
+			return nil, customerrors.Status(codes.Unauthenticated, "UNAUTHENTICATED", "invalid, revoked or expired API key", nil)
+		}
+
+		scope := methodScope(info.FullMethod)
+		if !key.HasScope(scope) {
+			logger.Log(zapcore.WarnLevel, TraceIDFromContext(ctx), "API key lacks scope", map[string]any{
+				"method":    info.FullMethod,
+				"keyId":     key.ID.Hex(),
+				"scope":     scope,
+				"errorType": "PERMISSION_DENIED",
+			}, "GRPC", nil)
+			return nil, customerrors.Status(codes.PermissionDenied, "PERMISSION_DENIED", "API key lacks the "+scope+" scope", nil)
+		}
+		return handler(context.WithValue(ctx, apiKeyContextKey{}, key), req)
+	}
+}
+
+func methodScope(fullMethod string) string {
+	if scope, ok := methodScopes[fullMethod]; ok {
+		return scope
+	}
+	if strings.HasPrefix(fullMethod, "/"+model.AdminServiceName+"/") {
+		return model.APIKeyScopeAdmin
+	}
+	return model.APIKeyScopeReadProblems
+}
+
+func apiKeyFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(APIKeyHeader); len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type staticKeys map[string]*model.APIKey
+
+func (k staticKeys) ValidateAPIKey(_ context.Context, secret string) (*model.APIKey, error) {
+	if key, ok := k[secret]; ok {
+		return key, nil
+	}
+	return nil, customerrors.NotFound("api key")
+}
+
+func TestAPIKeyAuthScopes(t *testing.T) {
+	keys := staticKeys{
+		"read-key":   {Scopes: []string{model.APIKeyScopeReadProblems}},
+		"submit-key": {Scopes: []string{model.APIKeyScopeSubmit}},
+		"admin-key":  {Scopes: []string{model.APIKeyScopeAdmin}},
+	}
+	tests := []struct {
+		key    string
+		method string
+		want   codes.Code
+	}{
+		{key: "read-key", method: pb.ProblemsService_GetProblem_FullMethodName, want: codes.OK},
+		{key: "read-key", method: pb.ProblemsService_ListProblems_FullMethodName, want: codes.OK},
+		{key: "read-key", method: pb.ProblemsService_RunUserCodeProblem_FullMethodName, want: codes.PermissionDenied},
+		{key: "read-key", method: pb.ProblemsService_CreateProblem_FullMethodName, want: codes.PermissionDenied},
+		{key: "submit-key", method: pb.ProblemsService_RunUserCodeProblem_FullMethodName, want: codes.OK},
+		{key: "submit-key", method: pb.ProblemsService_GetProblem_FullMethodName, want: codes.PermissionDenied},
+		{key: "submit-key", method: pb.ProblemsService_UpdateProblem_FullMethodName, want: codes.PermissionDenied},
+		{key: "admin-key", method: pb.ProblemsService_GetProblem_FullMethodName, want: codes.OK},
+		{key: "admin-key", method: pb.ProblemsService_DeleteProblem_FullMethodName, want: codes.OK},
+		{key: "read-key", method: "/" + model.AdminServiceName + "/ListAPIKeys", want: codes.PermissionDenied},
+		{key: "submit-key", method: "/" + model.AdminServiceName + "/CreateAPIKey", want: codes.PermissionDenied},
+		{key: "admin-key", method: "/" + model.AdminServiceName + "/CreateAPIKey", want: codes.OK},
+		{key: "unknown-key", method: pb.ProblemsService_GetProblem_FullMethodName, want: codes.Unauthenticated},
+	}
+	auth := APIKeyAuth(keys, testLogger())
+	for _, tt := range tests {
+		t.Run(tt.key+tt.method, func(t *testing.T) {
+			ctx := incoming("10.0.0.2:443", APIKeyHeader, tt.key)
+			_, err := auth(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, _ any) (any, error) {
+				if APIKeyFromContext(ctx) == nil {
+					t.Error("handler ran without the API key in its context")
+				}
+				return "ok", nil
+			})
+			if status.Code(err) != tt.want {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
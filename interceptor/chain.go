@@ -0,0 +1,51 @@
+// Package interceptor holds the gRPC server interceptors shared by every RPC
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	zap_betterstack "xcode/logger"
+
+	"google.golang.org/grpc"
+)
+
+// Unary returns the interceptors every RPC passes through: trace ID, metrics, access log, slow RPC detection, panic
+// recovery, the rate limiter, API key checks and request validation, in that order, so panics, rejected and invalid
+// requests still carry the trace ID, are counted and get an access log entry. A nil limiter disables rate limiting,
+// nil keys disable API keys.
+func Unary(logger *zap_betterstack.BetterStackLogStreamer, limiter *RateLimiter, keys APIKeyValidator, slowRPCThreshold time.Duration) []grpc.UnaryServerInterceptor {
+	interceptors := []grpc.UnaryServerInterceptor{
+		TraceID(),
+		Metrics(),
+		AccessLog(logger),
+		SlowRPC(logger, slowRPCThreshold),
+		Recovery(logger),
+	}
+	if limiter != nil {
+		interceptors = append(interceptors, limiter.Unary())
+	}
+	if keys != nil {
+		interceptors = append(interceptors, APIKeyAuth(keys, logger))
+	}
+	return append(interceptors, Validation())
+}
+
+// UnaryChain installs Unary on a gRPC server
+func UnaryChain(logger *zap_betterstack.BetterStackLogStreamer, limiter *RateLimiter, keys APIKeyValidator, slowRPCThreshold time.Duration) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(Unary(logger, limiter, keys, slowRPCThreshold)...)
+}
+
+// Chain composes interceptors into one, for code that calls the generated handlers outside the gRPC server
+func Chain(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
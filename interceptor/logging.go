@@ -0,0 +1,37 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	zap_betterstack "xcode/logger"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AccessLog writes one structured entry per RPC with its outcome and latency
+func AccessLog(logger *zap_betterstack.BetterStackLogStreamer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		attributes := map[string]any{
+			"method":     info.FullMethod,
+			"code":       code.String(),
+			"durationMs": time.Since(start).Milliseconds(),
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			attributes["peer"] = p.Addr.String()
+		}
+		level := zapcore.InfoLevel
+		if err != nil {
+			level = zapcore.WarnLevel
+		}
+		logger.Log(level, TraceIDFromContext(ctx), "RPC completed", attributes, "GRPC", err)
+		return resp, err
+	}
+}
@@ -0,0 +1,29 @@
+package interceptor
+
+import (
+	"xcode/metrics"
+
+	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+// serverMetrics counts started and handled RPCs by method and code and times them, which gives throughput, error
+// rate and latency per RPC
+var serverMetrics = grpcprom.NewServerMetrics(
+	grpcprom.WithServerHandlingTimeHistogram(grpcprom.WithHistogramBuckets(metrics.DefaultBuckets)),
+)
+
+func init() {
+	prometheus.MustRegister(serverMetrics)
+}
+
+// Metrics records every RPC in the gRPC server metrics, including rejected and panicking ones
+func Metrics() grpc.UnaryServerInterceptor {
+	return serverMetrics.UnaryServerInterceptor()
+}
+
+// InitializeMetrics exports a zero series for every method of server, so rates exist before the first call
+func InitializeMetrics(server *grpc.Server) {
+	serverMetrics.InitializeMetrics(server)
+}
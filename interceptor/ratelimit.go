@@ -0,0 +1,196 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"xcode/customerrors"
+	zap_betterstack "xcode/logger"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// RetryAfterTrailer tells a rate limited caller how many milliseconds to wait before retrying
+	RetryAfterTrailer = "retry-after-ms"
+
+	userIDHeader       = "x-user-id"
+	forwardedForHeader = "x-forwarded-for"
+	userIDField        = protoreflect.Name("user_id")
+)
+
+// TokenBucket takes one token from each of several shared buckets, or none when any is empty, see
+// cache.RedisCache.TakeTokens
+type TokenBucket interface {
+	TakeTokens(ctx context.Context, keys []string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// RateLimit allows Burst requests at once and Rate requests per second after that
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter keeps one bucket per method and user and one per method and client IP. A request has to get a token
+// from both, and takes none when either is empty. Whitelisted user IDs and IPs are never limited.
+type RateLimiter struct {
+	buckets TokenBucket
+	limits  atomic.Pointer[rateLimits]
+	logger  *zap_betterstack.BetterStackLogStreamer
+}
+
+// rateLimits is replaced as a whole by Update, so a request never sees half of a change
+type rateLimits struct {
+	fallback    RateLimit
+	methods     map[string]RateLimit
+	whitelist   map[string]struct{}
+	trustedHops int
+}
+
+// NewRateLimiter limits every method to fallback unless methods has its own limit
+func NewRateLimiter(buckets TokenBucket, fallback RateLimit, methods map[string]RateLimit, whitelist []string, trustedHops int, logger *zap_betterstack.BetterStackLogStreamer) *RateLimiter {
+	l := &RateLimiter{buckets: buckets, logger: logger}
+	l.Update(fallback, methods, whitelist, trustedHops)
+	return l
+}
+
+// Update replaces the limits, the whitelist and the number of proxies trusted to append to x-forwarded-for (see
+// clientIP) for the requests that follow. Zero limits turn limiting off; buckets already in Redis keep their tokens
+// and refill at the new rate.
+func (l *RateLimiter) Update(fallback RateLimit, methods map[string]RateLimit, whitelist []string, trustedHops int) {
+	allowed := make(map[string]struct{}, len(whitelist))
+	for _, entry := range whitelist {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			allowed[entry] = struct{}{}
+		}
+	}
+	l.limits.Store(&rateLimits{fallback: fallback, methods: methods, whitelist: allowed, trustedHops: trustedHops})
+}
+
+// Unary rejects requests over the limit with ResourceExhausted and a retry-after-ms trailer.
+// When Redis is unreachable requests are let through; losing the limiter beats losing the service.
+func (l *RateLimiter) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		limits := l.limits.Load()
+		limit, ok := limits.methods[info.FullMethod]
+		if !ok {
+			limit = limits.fallback
+		}
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			return handler(ctx, req)
+		}
+
+		userID := requestUserID(ctx, req)
+		ip, isClient := clientIP(ctx, limits.trustedHops)
+		if limits.whitelisted(userID) || (isClient && limits.whitelisted(ip)) {
+			return handler(ctx, req)
+		}
+
+		var keys []string
+		if userID != "" {
+			keys = append(keys, fmt.Sprintf("ratelimit:%s:user:%s", info.FullMethod, userID))
+		}
+		// a proxy's own address would put every user behind it in one bucket, so it only limits anonymous callers
+		if ip != "" && (isClient || userID == "") {
+			keys = append(keys, fmt.Sprintf("ratelimit:%s:ip:%s", info.FullMethod, ip))
+		}
+		if len(keys) == 0 {
+			return handler(ctx, req)
+		}
+		allowed, retryAfter, err := l.buckets.TakeTokens(ctx, keys, limit.Rate, limit.Burst)
+		if err != nil {
+			l.logger.Log(zapcore.ErrorLevel, TraceIDFromContext(ctx), "Rate limiter unavailable, allowing request", map[string]any{
+				"method":    info.FullMethod,
+				"errorType": "CACHE_ERROR",
+			}, "GRPC", err)
+			return handler(ctx, req)
+		}
+		if !allowed {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterTrailer, strconv.FormatInt(retryAfter.Milliseconds(), 10)))
+			l.logger.Log(zapcore.WarnLevel, TraceIDFromContext(ctx), "Request rate limited", map[string]any{
+				"method":     info.FullMethod,
+				"userId":     userID,
+				"ip":         ip,
+				"retryAfter": retryAfter.Milliseconds(),
+				"errorType":  "RATE_LIMITED",
+			}, "GRPC", nil)
+			return nil, customerrors.Status(codes.ResourceExhausted, "RATE_LIMITED",
+				fmt.Sprintf("too many requests, retry in %dms", retryAfter.Milliseconds()), nil,
+				customerrors.RetryAfter(retryAfter))
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *rateLimits) whitelisted(id string) bool {
+	if id == "" {
+		return false
+	}
+	_, ok := l.whitelist[id]
+	return ok
+}
+
+// UserIDFromMetadata returns the caller's user ID from the x-user-id metadata set by the API gateway, or ""
+func UserIDFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(userIDHeader); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// requestUserID prefers the gateway's x-user-id metadata over the user_id field of the request
+func requestUserID(ctx context.Context, req any) string {
+	if userID := UserIDFromMetadata(ctx); userID != "" {
+		return userID
+	}
+	if m, ok := req.(proto.Message); ok {
+		msg := m.ProtoReflect()
+		if fd := msg.Descriptor().Fields().ByName(userIDField); fd != nil && fd.Kind() == protoreflect.StringKind {
+			return msg.Get(fd).String()
+		}
+	}
+	return ""
+}
+
+// clientIP returns the caller's address as seen by the trustedHops proxies in front of the service. Each proxy
+// appends the address it was called from to x-forwarded-for, so the trustedHops-th entry from the right is the
+// last one the client could not write. Without trusted proxies the peer address is the client's; with them but
+// without the header it is a proxy's, which isClient reports.
+func clientIP(ctx context.Context, trustedHops int) (ip string, isClient bool) {
+	if trustedHops > 0 {
+		var entries []string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, value := range md.Get(forwardedForHeader) {
+				for _, entry := range strings.Split(value, ",") {
+					if entry = strings.TrimSpace(entry); entry != "" {
+						entries = append(entries, entry)
+					}
+				}
+			}
+		}
+		if len(entries) > 0 {
+			return entries[max(0, len(entries)-trustedHops)], true
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host := p.Addr.String()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		return host, trustedHops == 0
+	}
+	return "", false
+}
@@ -0,0 +1,174 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	zap_betterstack "xcode/logger"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/problems.ProblemsService/GetProblem"
+
+// memoryBuckets hands out tokens that never refill, with TakeTokens' all-or-nothing semantics
+type memoryBuckets struct {
+	tokens map[string]int
+	calls  [][]string
+}
+
+func (b *memoryBuckets) TakeTokens(_ context.Context, keys []string, _ float64, burst int) (bool, time.Duration, error) {
+	b.calls = append(b.calls, keys)
+	for _, key := range keys {
+		if _, ok := b.tokens[key]; !ok {
+			b.tokens[key] = burst
+		}
+		if b.tokens[key] < 1 {
+			return false, time.Second, nil
+		}
+	}
+	for _, key := range keys {
+		b.tokens[key]--
+	}
+	return true, 0, nil
+}
+
+func testLogger() *zap_betterstack.BetterStackLogStreamer {
+	return zap_betterstack.NewBetterStackLogStreamer("", "test", "", zap.NewNop(), zap_betterstack.Options{
+		Level: zap.NewAtomicLevelAt(zapcore.FatalLevel),
+	})
+}
+
+func incoming(peerAddr string, pairs ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	addr, _ := net.ResolveTCPAddr("tcp", peerAddr)
+	return peer.NewContext(ctx, &peer.Peer{Addr: addr})
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		hops         int
+		wantIP       string
+		wantIsClient bool
+	}{
+		{
+			name:         "spoofed left-most entry is ignored",
+			ctx:          incoming("10.0.0.2:443", "x-forwarded-for", "6.6.6.6, 203.0.113.7"),
+			hops:         1,
+			wantIP:       "203.0.113.7",
+			wantIsClient: true,
+		},
+		{
+			name:         "two trusted proxies",
+			ctx:          incoming("10.0.0.2:443", "x-forwarded-for", "6.6.6.6, 203.0.113.7, 10.0.0.9"),
+			hops:         2,
+			wantIP:       "203.0.113.7",
+			wantIsClient: true,
+		},
+		{
+			name:         "header shorter than the proxy chain",
+			ctx:          incoming("10.0.0.2:443", "x-forwarded-for", "203.0.113.7"),
+			hops:         3,
+			wantIP:       "203.0.113.7",
+			wantIsClient: true,
+		},
+		{
+			name:         "no trusted proxies ignores the header",
+			ctx:          incoming("198.51.100.4:5000", "x-forwarded-for", "6.6.6.6"),
+			hops:         0,
+			wantIP:       "198.51.100.4",
+			wantIsClient: true,
+		},
+		{
+			name:         "missing header behind a proxy is the proxy's address",
+			ctx:          incoming("10.0.0.2:443"),
+			hops:         1,
+			wantIP:       "10.0.0.2",
+			wantIsClient: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, isClient := clientIP(tt.ctx, tt.hops)
+			if ip != tt.wantIP || isClient != tt.wantIsClient {
+				t.Errorf("clientIP = %q, %v; want %q, %v", ip, isClient, tt.wantIP, tt.wantIsClient)
+			}
+		})
+	}
+}
+
+func TestRateLimiterBuckets(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantKeys []string
+	}{
+		{
+			name: "user behind a proxy without the header is limited by user alone",
+			ctx:  incoming("10.0.0.2:443", "x-user-id", "u1"),
+			wantKeys: []string{
+				"ratelimit:" + testMethod + ":user:u1",
+			},
+		},
+		{
+			name: "anonymous caller without the header falls back to the peer",
+			ctx:  incoming("10.0.0.2:443"),
+			wantKeys: []string{
+				"ratelimit:" + testMethod + ":ip:10.0.0.2",
+			},
+		},
+		{
+			name: "user and client IP are taken together",
+			ctx:  incoming("10.0.0.2:443", "x-user-id", "u1", "x-forwarded-for", "6.6.6.6, 203.0.113.7"),
+			wantKeys: []string{
+				"ratelimit:" + testMethod + ":user:u1",
+				"ratelimit:" + testMethod + ":ip:203.0.113.7",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := &memoryBuckets{tokens: map[string]int{}}
+			limiter := NewRateLimiter(buckets, RateLimit{Rate: 1, Burst: 1}, nil, nil, 1, testLogger())
+			_, err := limiter.Unary()(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod}, func(context.Context, any) (any, error) {
+				return "ok", nil
+			})
+			if err != nil {
+				t.Fatalf("Unary: %v", err)
+			}
+			if len(buckets.calls) != 1 || !slices.Equal(buckets.calls[0], tt.wantKeys) {
+				t.Errorf("took tokens from %v, want one call for %v", buckets.calls, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestRateLimiterRejectedRequestKeepsUserToken(t *testing.T) {
+	userKey := "ratelimit:" + testMethod + ":user:u1"
+	ipKey := "ratelimit:" + testMethod + ":ip:203.0.113.7"
+	buckets := &memoryBuckets{tokens: map[string]int{userKey: 1, ipKey: 0}}
+	limiter := NewRateLimiter(buckets, RateLimit{Rate: 1, Burst: 1}, nil, nil, 1, testLogger())
+
+	ctx := incoming("10.0.0.2:443", "x-user-id", "u1", "x-forwarded-for", "203.0.113.7")
+	_, err := limiter.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod}, func(context.Context, any) (any, error) {
+		t.Fatal("handler ran for a rate limited request")
+		return nil, nil
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("err = %v, want ResourceExhausted", err)
+	}
+	if buckets.tokens[userKey] != 1 {
+		t.Errorf("user bucket has %d tokens, want the one it had before the IP bucket refused", buckets.tokens[userKey])
+	}
+}
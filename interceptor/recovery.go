@@ -0,0 +1,32 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"xcode/customerrors"
+	zap_betterstack "xcode/logger"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Recovery turns a panicking handler into an Internal error instead of crashing the process
+func Recovery(logger *zap_betterstack.BetterStackLogStreamer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log(zapcore.ErrorLevel, TraceIDFromContext(ctx), "Recovered from panic in RPC handler", map[string]any{
+					"method":    info.FullMethod,
+					"panic":     fmt.Sprint(r),
+					"stack":     string(debug.Stack()),
+					"errorType": "PANIC",
+				}, "GRPC", nil)
+				err = customerrors.Status(codes.Internal, "INTERNAL_ERROR", "internal server error", nil)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
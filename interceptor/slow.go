@@ -0,0 +1,36 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	zap_betterstack "xcode/logger"
+	"xcode/metrics"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// slowRPCsTotal counts handlers slower than the threshold, by full method name
+var slowRPCsTotal = metrics.NewCounterVec("grpc_slow_requests_total", "method")
+
+// SlowRPC logs and counts every RPC whose handler runs longer than threshold. The log shares the RPC's trace ID,
+// so the slow MongoDB commands behind it can be found next to it.
+func SlowRPC(logger *zap_betterstack.BetterStackLogStreamer, threshold time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		if duration := time.Since(start); duration >= threshold {
+			slowRPCsTotal.Add(info.FullMethod, 1)
+			logger.Log(zapcore.WarnLevel, TraceIDFromContext(ctx), "Slow RPC", map[string]any{
+				"method":      info.FullMethod,
+				"code":        status.Code(err).String(),
+				"durationMs":  duration.Milliseconds(),
+				"thresholdMs": threshold.Milliseconds(),
+			}, "GRPC", nil)
+		}
+		return resp, err
+	}
+}
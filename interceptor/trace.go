@@ -0,0 +1,92 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	zap_betterstack "xcode/logger"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TraceIDHeader carries the trace ID in incoming metadata and is echoed back in the response header
+const TraceIDHeader = "x-trace-id"
+
+// TraceparentHeader is the W3C trace context header, used for the trace ID when x-trace-id is absent
+const TraceparentHeader = "traceparent"
+
+// traceIDField is the proto field most request messages use for the trace ID
+const traceIDField protoreflect.Name = "traceID"
+
+// TraceIDFromContext returns the RPC's trace ID, or "" outside an RPC
+func TraceIDFromContext(ctx context.Context) string {
+	return zap_betterstack.TraceIDFromContext(ctx)
+}
+
+// ContextWithTraceID attaches a trace ID for code that runs outside the interceptor chain, such as background jobs
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return zap_betterstack.ContextWithTraceID(ctx, traceID)
+}
+
+// TraceIDFromTraceparent returns the trace ID of a W3C traceparent header (version-traceid-parentid-flags), or ""
+// when the header is malformed or carries the all-zero trace ID
+func TraceIDFromTraceparent(traceparent string) string {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ""
+	}
+	traceID, err := trace.TraceIDFromHex(parts[1])
+	if err != nil {
+		return ""
+	}
+	return traceID.String()
+}
+
+// TraceID picks the trace ID from the x-trace-id metadata set by the API gateway, else from its W3C traceparent,
+// else from the request's traceID field, else from the OpenTelemetry span otelgrpc started for the RPC, and only
+// generates one when there is no valid span. It is stored in the context, copied into an empty traceID field so handlers reading req.TraceID see the same value,
+// and returned to the caller as a response header.
+func TraceID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		traceID := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(TraceIDHeader); len(values) > 0 {
+				traceID = values[0]
+			}
+			if values := md.Get(TraceparentHeader); traceID == "" && len(values) > 0 {
+				traceID = TraceIDFromTraceparent(values[0])
+			}
+		}
+
+		var field protoreflect.FieldDescriptor
+		var msg protoreflect.Message
+		if m, ok := req.(proto.Message); ok {
+			msg = m.ProtoReflect()
+			if fd := msg.Descriptor().Fields().ByName(traceIDField); fd != nil && fd.Kind() == protoreflect.StringKind {
+				field = fd
+			}
+		}
+		if traceID == "" && field != nil {
+			traceID = msg.Get(field).String()
+		}
+		if traceID == "" {
+			if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+				traceID = spanContext.TraceID().String()
+			}
+		}
+		if traceID == "" {
+			traceID = uuid.New().String()
+		}
+		if field != nil && msg.Get(field).String() == "" {
+			msg.Set(field, protoreflect.ValueOfString(traceID))
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(TraceIDHeader, traceID))
+		return handler(ContextWithTraceID(ctx, traceID), req)
+	}
+}
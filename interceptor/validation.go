@@ -0,0 +1,20 @@
+package interceptor
+
+import (
+	"context"
+
+	"xcode/validation"
+
+	"google.golang.org/grpc"
+)
+
+// Validation rejects requests that break the rules in package validation with InvalidArgument, before the handler
+// runs. The error lists every violating field in its BadRequest details.
+func Validation() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := validation.Request(info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,105 @@
+package zap_betterstack
+
+import (
+	"sync"
+	"time"
+
+	"xcode/metrics"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sampledOutTotal counts entries dropped by sampling, by level
+var sampledOutTotal = metrics.NewCounterVec("log_entries_sampled_out_total", "level")
+
+// SamplingConfig caps how often the same info message is logged: within every Tick the first First entries with a
+// given message are kept, then only every Thereafter-th. Warnings and errors are never sampled. First 0 disables
+// sampling.
+type SamplingConfig struct {
+	First      int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// Options tunes what the streamer ships and how
+type Options struct {
+	// Level is the minimum level logged; share it with the zap logger (see NewLogger) so one change applies to both.
+	// The zero value logs from info up.
+	Level    zap.AtomicLevel
+	Sampling SamplingConfig
+	Shipping ShippingConfig
+}
+
+// NewLogger builds the zap logger the streamer also writes to, with its level tied to level
+func NewLogger(environment string, level zap.AtomicLevel) (*zap.Logger, error) {
+	config := zap.NewProductionConfig()
+	if environment == "development" {
+		config = zap.NewDevelopmentConfig()
+	}
+	config.Level = level
+	return config.Build()
+}
+
+// SetLevel changes the minimum level at runtime and returns the previous one
+func (s *BetterStackLogStreamer) SetLevel(level zapcore.Level) zapcore.Level {
+	previous := s.level.Level()
+	s.level.SetLevel(level)
+	return previous
+}
+
+// Level returns the current minimum level
+func (s *BetterStackLogStreamer) Level() zapcore.Level {
+	return s.level.Level()
+}
+
+// sampler counts entries per level and message within the current tick
+type sampler struct {
+	config SamplingConfig
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+}
+
+type sampleKey struct {
+	level   zapcore.Level
+	message string
+}
+
+func newSampler(config SamplingConfig) *sampler {
+	if config.First <= 0 {
+		return nil
+	}
+	if config.Thereafter <= 0 {
+		config.Thereafter = 1
+	}
+	if config.Tick <= 0 {
+		config.Tick = time.Second
+	}
+	return &sampler{config: config, counts: make(map[sampleKey]int)}
+}
+
+// allow reports whether an entry is kept; a nil sampler keeps everything
+func (s *sampler) allow(level zapcore.Level, message string) bool {
+	if s == nil || level >= zapcore.WarnLevel {
+		return true
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.config.Tick {
+		s.windowStart = now
+		clear(s.counts)
+	}
+	key := sampleKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+	s.mu.Unlock()
+
+	if n <= s.config.First || (n-s.config.First)%s.config.Thereafter == 0 {
+		return true
+	}
+	sampledOutTotal.Add(level.String(), 1)
+	return false
+}
@@ -0,0 +1,185 @@
+package zap_betterstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"xcode/metrics"
+
+	"go.uber.org/zap"
+)
+
+// shippedEntriesTotal counts entries by outcome: shipped, or dropped because the queue was full or every send failed
+var shippedEntriesTotal = metrics.NewCounterVec("betterstack_log_entries_total", "outcome")
+
+// ShippingConfig controls how entries are batched and sent to Better Stack. Log only enqueues, so a slow or
+// unavailable Better Stack never holds up the caller; entries that do not fit the queue are dropped and counted.
+type ShippingConfig struct {
+	QueueSize     int           // entries waiting to be shipped
+	BatchSize     int           // entries per request at most
+	FlushInterval time.Duration // a partial batch is sent after this long
+	MaxRetries    int           // further attempts after a failed send, on network errors, 429 and 5xx
+	RetryBackoff  time.Duration // wait before the first retry, doubled afterwards
+}
+
+func (c ShippingConfig) withDefaults() ShippingConfig {
+	if c.QueueSize <= 0 {
+		c.QueueSize = 10000
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = 500 * time.Millisecond
+	}
+	return c
+}
+
+// shipper owns the queue and the goroutine that drains it
+type shipper struct {
+	config      ShippingConfig
+	client      *http.Client
+	uploadURL   string
+	sourceToken atomic.Pointer[string] // replaced when the token is rotated
+	logger      *zap.Logger
+
+	queue     chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newShipper(config ShippingConfig, uploadURL, sourceToken string, logger *zap.Logger) *shipper {
+	config = config.withDefaults()
+	s := &shipper{
+		config:    config,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		uploadURL: uploadURL,
+		logger:    logger,
+		queue:     make(chan []byte, config.QueueSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	s.sourceToken.Store(&sourceToken)
+	go s.run()
+	return s
+}
+
+// enqueue never blocks: when the queue is full the entry is dropped
+func (s *shipper) enqueue(entry []byte) {
+	select {
+	case s.queue <- entry:
+	default:
+		shippedEntriesTotal.Add("dropped_queue_full", 1)
+	}
+}
+
+func (s *shipper) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]json.RawMessage, 0, s.config.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			s.send(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) >= s.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.stop:
+			// ship whatever was logged before Close
+			for {
+				select {
+				case entry := <-s.queue:
+					batch = append(batch, entry)
+					if len(batch) >= s.config.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send posts batch as one JSON array, retrying transient failures with exponential backoff
+func (s *shipper) send(batch []json.RawMessage) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		s.logger.Error("Failed to marshal log batch", zap.Error(err))
+		shippedEntriesTotal.Add("dropped_send_failed", int64(len(batch)))
+		return
+	}
+
+	backoff := s.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := s.post(body)
+		if err == nil {
+			shippedEntriesTotal.Add("shipped", int64(len(batch)))
+			return
+		}
+		if !retryable || attempt >= s.config.MaxRetries {
+			s.logger.Error("Failed to send logs to Better Stack", zap.Int("entries", len(batch)), zap.Int("attempts", attempt+1), zap.Error(err))
+			shippedEntriesTotal.Add("dropped_send_failed", int64(len(batch)))
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post reports whether a failed request is worth retrying
+func (s *shipper) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.uploadURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+*s.sourceToken.Load())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("unexpected response from Better Stack: %s", resp.Status)
+}
+
+// close stops taking batches and ships what is queued, until ctx is done
+func (s *shipper) close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,10 +1,9 @@
 package zap_betterstack
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"io"
-	"net/http"
 	"os"
 	"sync"
 	"time"
@@ -32,18 +31,25 @@ type BetterStackLogStreamer struct {
 	environment string
 	uploadURL   string
 	logger      *zap.Logger
-	client      *http.Client
+	shipper     *shipper
 	fileWriter  io.Writer
 	fileMu      sync.Mutex
+	level       zap.AtomicLevel
+	sampler     *sampler
 }
 
 // NewBetterStackLogStreamer creates a new BetterStackLogStreamer instance
-func NewBetterStackLogStreamer(sourceToken, environment, uploadURL string, logger *zap.Logger) *BetterStackLogStreamer {
+func NewBetterStackLogStreamer(sourceToken, environment, uploadURL string, logger *zap.Logger, opts Options) *BetterStackLogStreamer {
+	if opts.Level == (zap.AtomicLevel{}) {
+		opts.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	}
 	streamer := &BetterStackLogStreamer{
 		sourceToken: sourceToken,
 		environment: environment,
 		uploadURL:   uploadURL,
 		logger:      logger,
+		level:       opts.Level,
+		sampler:     newSampler(opts.Sampling),
 	}
 
 	if environment == "development" {
@@ -57,19 +63,55 @@ func NewBetterStackLogStreamer(sourceToken, environment, uploadURL string, logge
 		}
 	}
 
-	if environment == "production" {
-		streamer.client = &http.Client{Timeout: 10 * time.Second}
+	if environment != "development" {
+		streamer.shipper = newShipper(opts.Shipping, uploadURL, sourceToken, logger)
 	}
 
 	return streamer
 }
 
+// SetSourceToken switches the token batches are shipped with, e.g. after it was rotated in the secret store
+func (s *BetterStackLogStreamer) SetSourceToken(token string) {
+	if s.shipper != nil {
+		s.shipper.sourceToken.Store(&token)
+	}
+}
+
+// Close ships the entries still queued for Better Stack, giving up when ctx is done
+func (s *BetterStackLogStreamer) Close(ctx context.Context) error {
+	if s.shipper == nil {
+		return nil
+	}
+	return s.shipper.close(ctx)
+}
+
+type traceIDKey struct{}
+
+// ContextWithTraceID attaches the trace ID that LogContext logs under
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID attached to ctx, or "" when there is none
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// LogContext is Log under the trace ID carried by ctx, for layers that are handed a context but no trace ID
+func (s *BetterStackLogStreamer) LogContext(ctx context.Context, level zapcore.Level, message string, attributes map[string]any, layer string, err error) {
+	s.Log(level, TraceIDFromContext(ctx), message, attributes, layer, err)
+}
+
 // Log streams a service-level log to a file (development) or Better Stack (production)
 func (s *BetterStackLogStreamer) Log(level zapcore.Level, traceID string, message string, attributes map[string]any, layer string, err error) {
 
 	if traceID == "" {
 		return
 	}
+	if !s.level.Enabled(level) || !s.sampler.allow(level, message) {
+		return
+	}
 
 	// Map zap level to Better Stack level string
 	var levelStr string
@@ -119,27 +161,8 @@ func (s *BetterStackLogStreamer) Log(level zapcore.Level, traceID string, messag
 			s.logger.Error("Failed to write log to file", zap.Error(writeErr))
 		}
 	} else {
-		// Send to Better Stack in production
-		req, err := http.NewRequest("POST", s.uploadURL, bytes.NewReader(body))
-		if err != nil {
-			s.logger.Error("Failed to create HTTP request", zap.Error(err))
-			return
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+s.sourceToken)
-
-		// Send log asynchronously
-		go func() {
-			resp, err := s.client.Do(req)
-			if err != nil {
-				s.logger.Error("Failed to send log to Better Stack", zap.Error(err))
-				return
-			}
-			defer resp.Body.Close()
-			if resp.StatusCode != http.StatusAccepted {
-				s.logger.Error("Unexpected response from Better Stack", zap.String("status", resp.Status))
-			}
-		}()
+		// Ship to Better Stack in production, in batches on the shipper's goroutine
+		s.shipper.enqueue(body)
 	}
 
 	// Also log to Zap for console visibility
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DefaultBuckets suit request latencies in seconds, from 5ms to 10s
+var DefaultBuckets = prometheus.DefBuckets
+
+// HistogramVec counts observations into buckets, one series per combination of label values. Its _count series
+// doubles as a throughput counter. Histograms are only exported on /metrics.
+type HistogramVec struct {
+	h *prometheus.HistogramVec
+}
+
+// NewHistogramVec registers a histogram with the given upper bucket bounds, which must be sorted ascending
+func NewHistogramVec(name string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{h: promauto.NewHistogramVec(prometheus.HistogramOpts{Name: name, Buckets: buckets}, labels)}
+}
+
+// Observe records value, e.g. a duration in seconds, under the given label values, one per label
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.h.WithLabelValues(labelValues...).Observe(value)
+}
+
+// ObserveDuration records the seconds elapsed since start; defer it at the top of the measured function
+func (h *HistogramVec) ObserveDuration(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Counts returns the number of observations per series since the process started, keyed by the series' label
+// values joined with ","; series never observed are absent
+func (h *HistogramVec) Counts() map[string]uint64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		h.h.Collect(ch)
+		close(ch)
+	}()
+
+	counts := make(map[string]uint64)
+	for m := range ch {
+		var d dto.Metric
+		if err := m.Write(&d); err != nil {
+			continue
+		}
+		values := make([]string, len(d.GetLabel()))
+		for i, label := range d.GetLabel() {
+			values[i] = label.GetValue()
+		}
+		counts[strings.Join(values, ",")] = d.GetHistogram().GetSampleCount()
+	}
+	return counts
+}
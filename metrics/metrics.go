@@ -0,0 +1,121 @@
+// Package metrics exposes process counters over HTTP, for Prometheus at /metrics and in expvar's JSON format at
+// /debug/vars, and dependency health at /healthz. Histograms and everything registered with the default Prometheus
+// registerer, such as the gRPC server metrics, are only on /metrics.
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// CounterVec is a set of counters sharing a name, one per value of its label
+type CounterVec struct {
+	m    *expvar.Map
+	prom *prometheus.CounterVec
+}
+
+// NewCounterVec registers a counter set whose series are told apart by label; names must be unique across the process
+func NewCounterVec(name, label string) *CounterVec {
+	return &CounterVec{
+		m:    expvar.NewMap(name),
+		prom: promauto.NewCounterVec(prometheus.CounterOpts{Name: name}, []string{label}),
+	}
+}
+
+func (c *CounterVec) Add(label string, delta int64) {
+	c.m.Add(label, delta)
+	c.prom.WithLabelValues(label).Add(float64(delta))
+}
+
+// Sum returns the total across every label value since the process started
+func (c *CounterVec) Sum() int64 {
+	var sum int64
+	c.m.Do(func(kv expvar.KeyValue) {
+		if v, ok := kv.Value.(*expvar.Int); ok {
+			sum += v.Value()
+		}
+	})
+	return sum
+}
+
+// Gauge is a single value that can go up and down, such as a queue depth
+type Gauge struct {
+	v    *expvar.Int
+	prom prometheus.Gauge
+}
+
+func NewGauge(name string) *Gauge {
+	return &Gauge{v: expvar.NewInt(name), prom: promauto.NewGauge(prometheus.GaugeOpts{Name: name})}
+}
+
+func (g *Gauge) Add(delta int64) {
+	g.v.Add(delta)
+	g.prom.Add(float64(delta))
+}
+
+// Counter is a single monotonically increasing value
+type Counter struct {
+	v    *expvar.Int
+	prom prometheus.Counter
+}
+
+func NewCounter(name string) *Counter {
+	return &Counter{v: expvar.NewInt(name), prom: promauto.NewCounter(prometheus.CounterOpts{Name: name})}
+}
+
+func (c *Counter) Add(delta int64) {
+	c.v.Add(delta)
+	c.prom.Add(float64(delta))
+}
+
+var (
+	healthMu     sync.RWMutex
+	healthChecks = make(map[string]func() error)
+)
+
+// RegisterHealthCheck adds a dependency to /healthz; check returns nil while the dependency is usable
+func RegisterHealthCheck(name string, check func() error) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	healthChecks[name] = check
+}
+
+// healthHandler answers 200 when every check passes and 503 otherwise, listing each check's result
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	healthMu.RLock()
+	defer healthMu.RUnlock()
+
+	status := http.StatusOK
+	results := make(map[string]string, len(healthChecks))
+	for name, check := range healthChecks {
+		if err := check(); err != nil {
+			results[name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(results)
+}
+
+// Serve exposes the registered metrics on addr; it blocks, so run it in its own goroutine
+func Serve(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/healthz", healthHandler)
+	log.Printf("Metrics server running on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("Metrics server stopped: %v", err)
+	}
+}
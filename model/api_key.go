@@ -0,0 +1,75 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scopes an API key can be given. APIKeyScopeAdmin allows everything.
+const (
+	APIKeyScopeReadProblems = "problems:read"
+	APIKeyScopeSubmit       = "submit"
+	APIKeyScopeAdmin        = "admin"
+)
+
+// AdminServiceName names the admin API in full method names; an API key needs APIKeyScopeAdmin for all of its methods
+const AdminServiceName = "xcode.admin.AdminService"
+
+// APIKeyScopes lists the scopes CreateAPIKey accepts
+var APIKeyScopes = []string{APIKeyScopeReadProblems, APIKeyScopeSubmit, APIKeyScopeAdmin}
+
+// APIKey lets an internal tool, a bot or another service call the API. Only the SHA-256 of the secret is stored;
+// Prefix is its first characters, kept to tell keys apart in listings.
+type APIKey struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name       string             `json:"name" bson:"name"`
+	Prefix     string             `json:"prefix" bson:"prefix"`
+	Hash       string             `json:"-" bson:"hash"`
+	Scopes     []string           `json:"scopes" bson:"scopes"`
+	CreatedBy  string             `json:"createdBy" bson:"createdBy"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	ExpiresAt  *time.Time         `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	RevokedAt  *time.Time         `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+	LastUsedAt *time.Time         `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+}
+
+// Active reports whether the key is neither revoked nor expired at now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope reports whether the key was given scope, or the admin scope
+func (k APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, APIKeyScopeAdmin)
+}
+
+// CreateAPIKeyRequest issues a key with Scopes that expires after ExpiresInDays, or never when it is 0
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+// CreateAPIKeyResponse holds the secret, which is shown this once and cannot be recovered
+type CreateAPIKeyResponse struct {
+	Key    APIKey `json:"key"`
+	Secret string `json:"secret"`
+}
+
+type ListAPIKeysRequest struct {
+	IncludeRevoked bool `json:"includeRevoked"`
+}
+
+type ListAPIKeysResponse struct {
+	Keys []APIKey `json:"keys"`
+}
+
+type RevokeAPIKeyRequest struct {
+	KeyID string `json:"keyId"`
+}
+
+type RevokeAPIKeyResponse struct {
+	Key APIKey `json:"key"`
+}
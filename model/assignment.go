@@ -0,0 +1,143 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What happens to work submitted after an assignment's DueAt
+const (
+	AssignmentLateReject  = "REJECT"  // it does not count
+	AssignmentLatePenalty = "PENALTY" // it counts for LatePenaltyPercent less
+	AssignmentLateAccept  = "ACCEPT"  // it counts in full
+)
+
+// AssignmentLatePolicies lists the policies CreateAssignment accepts
+var AssignmentLatePolicies = []string{AssignmentLateReject, AssignmentLatePenalty, AssignmentLateAccept}
+
+// Assignment is a problem set a teacher gives the students on its Roster. Like a contest it has a fixed problem set,
+// but rather than a timed round it is open from OpensAt and due at DueAt; LateUntil, when set, is when late work
+// stops being taken.
+type Assignment struct {
+	ID                 primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title              string             `json:"title" bson:"title"`
+	Description        string             `json:"description,omitempty" bson:"description,omitempty"`
+	ProblemIDs         []string           `json:"problemIds" bson:"problemIds"`
+	Roster             []string           `json:"roster" bson:"roster"` // user IDs of the students who may join
+	OpensAt            time.Time          `json:"opensAt" bson:"opensAt"`
+	DueAt              time.Time          `json:"dueAt" bson:"dueAt"`
+	LatePolicy         string             `json:"latePolicy" bson:"latePolicy"`
+	LatePenaltyPercent int                `json:"latePenaltyPercent,omitempty" bson:"latePenaltyPercent,omitempty"`
+	LateUntil          *time.Time         `json:"lateUntil,omitempty" bson:"lateUntil,omitempty"`
+	CreatedBy          string             `json:"createdBy" bson:"createdBy"` // the teacher
+	CreatedAt          time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// ClosesAt is when submissions stop counting: DueAt when late work is rejected, else LateUntil if set. ok is false
+// when late work is taken for good.
+func (a Assignment) ClosesAt() (closesAt time.Time, ok bool) {
+	if a.LatePolicy == AssignmentLateReject {
+		return a.DueAt, true
+	}
+	if a.LateUntil != nil {
+		return *a.LateUntil, true
+	}
+	return time.Time{}, false
+}
+
+// AssignmentEnrollment records that a student on the roster joined the assignment
+type AssignmentEnrollment struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	AssignmentID string             `json:"assignmentId" bson:"assignmentId"`
+	UserID       string             `json:"userId" bson:"userId"`
+	JoinedAt     time.Time          `json:"joinedAt" bson:"joinedAt"`
+}
+
+// AssignmentProgress is how far one student on the roster got. Score is the percentage of the problems' credit
+// earned, late solves counting as the late policy says.
+type AssignmentProgress struct {
+	UserID           string                    `json:"userId"`
+	Joined           bool                      `json:"joined"`
+	Solved           int                       `json:"solved"`
+	SolvedLate       int                       `json:"solvedLate"`
+	Score            float64                   `json:"score"`
+	Problems         []AssignmentProblemResult `json:"problems"`
+	LastSubmissionAt *time.Time                `json:"lastSubmissionAt,omitempty"`
+}
+
+// AssignmentProblemResult is how a student did on one problem. Attempts counts rejected submissions before the
+// accepted one, or all of them when the problem was not solved.
+type AssignmentProblemResult struct {
+	ProblemID string     `json:"problemId"`
+	Solved    bool       `json:"solved"`
+	Late      bool       `json:"late,omitempty"`
+	Attempts  int        `json:"attempts"`
+	SolvedAt  *time.Time `json:"solvedAt,omitempty"`
+	Credit    float64    `json:"credit"` // 1 for a solve on time, less or none for a late one
+}
+
+type CreateAssignmentRequest struct {
+	Assignment Assignment `json:"assignment"`
+	TeacherID  string     `json:"teacherId"`
+}
+
+type CreateAssignmentResponse struct {
+	Assignment Assignment `json:"assignment"`
+}
+
+// UpdateAssignmentRosterRequest adds and removes students; only the assignment's teacher may change it
+type UpdateAssignmentRosterRequest struct {
+	AssignmentID string   `json:"assignmentId"`
+	TeacherID    string   `json:"teacherId"`
+	Add          []string `json:"add"`
+	Remove       []string `json:"remove"`
+}
+
+type UpdateAssignmentRosterResponse struct {
+	Assignment Assignment `json:"assignment"`
+}
+
+// GetAssignmentRequest returns an assignment to its teacher and the students on its roster
+type GetAssignmentRequest struct {
+	AssignmentID string `json:"assignmentId"`
+	UserID       string `json:"userId"`
+}
+
+type GetAssignmentResponse struct {
+	Assignment Assignment `json:"assignment"`
+}
+
+type JoinAssignmentRequest struct {
+	AssignmentID string `json:"assignmentId"`
+	UserID       string `json:"userId"`
+}
+
+// JoinAssignmentResponse has Created false when the student had already joined
+type JoinAssignmentResponse struct {
+	Enrollment AssignmentEnrollment `json:"enrollment"`
+	Created    bool                 `json:"created"`
+}
+
+// GetAssignmentProblemsRequest lists the problems to the teacher and, once the assignment opens, to the students
+// who joined it
+type GetAssignmentProblemsRequest struct {
+	AssignmentID string `json:"assignmentId"`
+	UserID       string `json:"userId"`
+}
+
+type GetAssignmentProblemsResponse struct {
+	Problems []ContestProblem `json:"problems"`
+}
+
+// GetAssignmentProgressRequest reports every student's progress to the assignment's teacher
+type GetAssignmentProgressRequest struct {
+	AssignmentID string `json:"assignmentId"`
+	TeacherID    string `json:"teacherId"`
+}
+
+type GetAssignmentProgressResponse struct {
+	Progress []AssignmentProgress `json:"progress"`
+	// Final is true once no more submissions can count
+	Final bool `json:"final"`
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AuditActionCreateProblem          = "CREATE_PROBLEM"
+	AuditActionDeleteProblem          = "DELETE_PROBLEM"
+	AuditActionAddTestCases           = "ADD_TEST_CASES"
+	AuditActionChangeUserEntity       = "CHANGE_USER_ENTITY"
+	AuditActionInvalidateSubmission   = "INVALIDATE_SUBMISSION"
+	AuditActionSetLogLevel            = "SET_LOG_LEVEL"
+	AuditActionSetSignature           = "SET_FUNCTION_SIGNATURE"
+	AuditActionCreateContest          = "CREATE_CONTEST"
+	AuditActionFinalizeContest        = "FINALIZE_CONTEST"
+	AuditActionUpdateProblemReport    = "UPDATE_PROBLEM_REPORT"
+	AuditActionUpsertTranslation      = "UPSERT_TRANSLATION"
+	AuditActionSetProblemTier         = "SET_PROBLEM_TIER"
+	AuditActionCreateAPIKey           = "CREATE_API_KEY"
+	AuditActionRevokeAPIKey           = "REVOKE_API_KEY"
+	AuditActionAddMaintainer          = "ADD_PROBLEM_MAINTAINER"
+	AuditActionRegisterWebhook        = "REGISTER_WEBHOOK"
+	AuditActionDeleteWebhook          = "DELETE_WEBHOOK"
+	AuditActionCreateAssignment       = "CREATE_ASSIGNMENT"
+	AuditActionUpdateAssignmentRoster = "UPDATE_ASSIGNMENT_ROSTER"
+	AuditActionRejudgeProblem         = "REJUDGE_PROBLEM"
+	AuditActionCreateOrganization     = "CREATE_ORGANIZATION"
+	AuditActionRemoveOrgMember        = "REMOVE_ORGANIZATION_MEMBER"
+	AuditActionSetExecutionProfile    = "SET_EXECUTION_PROFILE"
+	AuditActionDeleteExecProfile      = "DELETE_EXECUTION_PROFILE"
+)
+
+const (
+	AuditTargetProblem    = "PROBLEM"
+	AuditTargetUser       = "USER"
+	AuditTargetSubmission = "SUBMISSION"
+	AuditTargetService    = "SERVICE"
+	AuditTargetContest    = "CONTEST"
+	AuditTargetReport     = "PROBLEM_REPORT"
+	AuditTargetAPIKey     = "API_KEY"
+	AuditTargetWebhook    = "WEBHOOK"
+	AuditTargetAssignment = "ASSIGNMENT"
+	AuditTargetOrg        = "ORGANIZATION"
+	AuditTargetProfile    = "EXECUTION_PROFILE"
+)
+
+// AuditEntry records one admin mutation. Entries are only ever inserted, never updated or deleted.
+// Before and After summarize the target around the mutation; either is empty when there is nothing to show,
+// e.g. Before of a created problem.
+type AuditEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action     string             `bson:"action" json:"action"`
+	Actor      string             `bson:"actor" json:"actor"` // "unknown" when the gateway did not identify the caller
+	TargetType string             `bson:"targetType" json:"targetType"`
+	TargetID   string             `bson:"targetId" json:"targetId"`
+	Before     map[string]any     `bson:"before,omitempty" json:"before,omitempty"`
+	After      map[string]any     `bson:"after,omitempty" json:"after,omitempty"`
+	TraceID    string             `bson:"traceId" json:"traceId"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// AuditLogFilter narrows an audit log query; zero fields match everything
+type AuditLogFilter struct {
+	Actor    string
+	Action   string
+	TargetID string
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+}
+
+type QueryAuditLogRequest struct {
+	Actor    string    `json:"actor" bson:"actor"`
+	Action   string    `json:"action" bson:"action"`
+	TargetID string    `json:"targetId" bson:"targetId"`
+	From     time.Time `json:"from" bson:"from"`
+	To       time.Time `json:"to" bson:"to"`
+	Page     int64     `json:"page" bson:"page"`
+	PageSize int64     `json:"pageSize" bson:"pageSize"`
+}
+
+type QueryAuditLogResponse struct {
+	Entries []AuditEntry `json:"entries" bson:"entries"`
+	Total   int64        `json:"total" bson:"total"`
+}
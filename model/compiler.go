@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Versions of the execution engine contract on problems.execute.request. v1 sends code and language and gets a
+// loose output string back; v2 adds execution limits and reports failures as structured errors.
+const (
+	CompilerContractV1 = 1
+	CompilerContractV2 = 2
+
+	LatestCompilerContract = CompilerContractV2
+)
+
+// Structured error codes of a v2 CompilerResponse
+const (
+	CompilerErrorCompilation         = "COMPILATION_ERROR"
+	CompilerErrorRuntime             = "RUNTIME_ERROR"
+	CompilerErrorTimeLimit           = "TIME_LIMIT_EXCEEDED"
+	CompilerErrorMemoryLimit         = "MEMORY_LIMIT_EXCEEDED"
+	CompilerErrorInternal            = "INTERNAL_ERROR"
+	CompilerErrorUnsupportedLanguage = "UNSUPPORTED_LANGUAGE"
+)
+
+// ErrInvalidCompilerResponse is returned for replies that do not follow the contract version they claim
+var ErrInvalidCompilerResponse = errors.New("invalid compiler response")
+
+// CompilerRequest is sent to the execution engine. Version is omitted for v1 so engines that predate it keep working.
+type CompilerRequest struct {
+	Version  int              `json:"version,omitempty"`
+	Code     string           `json:"code"`
+	Language string           `json:"language"`
+	Limits   *ExecutionLimits `json:"limits,omitempty"`  // v2
+	Runtime  *RuntimeOptions  `json:"runtime,omitempty"` // v2, only when the language has an ExecutionProfile
+}
+
+// RuntimeOptions tell the engine which runtime version and compiler flags to run a language with
+type RuntimeOptions struct {
+	Version       string   `json:"version,omitempty"`
+	CompilerFlags []string `json:"compilerFlags,omitempty"`
+}
+
+// ExecutionLimits bound a single run in the execution engine
+type ExecutionLimits struct {
+	TimeLimitMs   int64 `json:"timeLimitMs"`
+	MemoryLimitMb int64 `json:"memoryLimitMb"`
+}
+
+// CompilerResponse is the engine's reply. v1 engines send no version, only output and execution_time.
+type CompilerResponse struct {
+	Version       int            `json:"version,omitempty"`
+	Output        string         `json:"output"`
+	ExecutionTime string         `json:"execution_time"`  // a duration string, e.g. "1.230549718s"
+	Error         *CompilerError `json:"error,omitempty"` // v2
+}
+
+// CompilerError is a structured failure reported by a v2 engine
+type CompilerError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ContractVersion is the version the reply follows, treating a missing version as v1
+func (r *CompilerResponse) ContractVersion() int {
+	if r.Version == 0 {
+		return CompilerContractV1
+	}
+	return r.Version
+}
+
+// Validate checks the reply against the contract version it claims
+func (r *CompilerResponse) Validate() error {
+	switch r.ContractVersion() {
+	case CompilerContractV1:
+		if r.Output == "" {
+			return fmt.Errorf("%w: v1 response has no output", ErrInvalidCompilerResponse)
+		}
+	case CompilerContractV2:
+		if r.Error != nil && r.Error.Code == "" {
+			return fmt.Errorf("%w: v2 error has no code", ErrInvalidCompilerResponse)
+		}
+		if r.Error == nil && r.Output == "" {
+			return fmt.Errorf("%w: v2 response has neither output nor error", ErrInvalidCompilerResponse)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidCompilerResponse, r.Version)
+	}
+	return nil
+}
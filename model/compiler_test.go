@@ -0,0 +1,148 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// golden reads a file of testdata/compiler, the contract as the execution engine sees it
+func golden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "compiler", name))
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	return data
+}
+
+func TestCompilerRequestGolden(t *testing.T) {
+	tests := []struct {
+		golden  string
+		request CompilerRequest
+	}{
+		{
+			golden:  "request_v1.json",
+			request: CompilerRequest{Code: "print(sum(map(int, input().split())))", Language: "python"},
+		},
+		{
+			golden: "request_v2.json",
+			request: CompilerRequest{
+				Version:  CompilerContractV2,
+				Code:     "int main() { return 0; }",
+				Language: "cpp",
+				Limits:   &ExecutionLimits{TimeLimitMs: 2000, MemoryLimitMb: 256},
+				Runtime:  &RuntimeOptions{Version: "17", CompilerFlags: []string{"-O2", "-std=c++17"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got, err := json.Marshal(tt.request)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var want bytes.Buffer
+			if err := json.Compact(&want, golden(t, tt.golden)); err != nil {
+				t.Fatalf("golden file is not JSON: %v", err)
+			}
+			if !bytes.Equal(got, want.Bytes()) {
+				t.Errorf("request =\n%s\nwant\n%s", got, want.Bytes())
+			}
+		})
+	}
+}
+
+func TestCompilerResponseGolden(t *testing.T) {
+	tests := []struct {
+		golden        string
+		want          CompilerResponse
+		wantVersion   int
+		wantParseErr  bool
+		wantValidates bool
+	}{
+		{
+			golden:        "response_v1.json",
+			want:          CompilerResponse{Output: `{"totalTestCases":2,"passedTestCases":2,"failedTestCases":0}`, ExecutionTime: "1.230549718s"},
+			wantVersion:   CompilerContractV1,
+			wantValidates: true,
+		},
+		{
+			golden:        "response_v1_compile_error.json",
+			want:          CompilerResponse{Output: "# command-line-arguments\n./main.go:4:2: syntax error: unexpected }", ExecutionTime: "0.412s"},
+			wantVersion:   CompilerContractV1,
+			wantValidates: true,
+		},
+		{
+			golden:      "response_v1_empty.json",
+			want:        CompilerResponse{ExecutionTime: "0s"},
+			wantVersion: CompilerContractV1,
+		},
+		{
+			golden:        "response_v2_success.json",
+			want:          CompilerResponse{Version: 2, Output: `{"totalTestCases":2,"passedTestCases":1,"failedTestCases":1}`, ExecutionTime: "0.9s"},
+			wantVersion:   CompilerContractV2,
+			wantValidates: true,
+		},
+		{
+			golden: "response_v2_runtime_error.json",
+			want: CompilerResponse{Version: 2, ExecutionTime: "0.05s", Error: &CompilerError{
+				Code:    CompilerErrorRuntime,
+				Message: "panic: runtime error: index out of range [3] with length 3",
+			}},
+			wantVersion:   CompilerContractV2,
+			wantValidates: true,
+		},
+		{
+			golden:        "response_v2_engine_error.json",
+			want:          CompilerResponse{Version: 2, ExecutionTime: "0s", Error: &CompilerError{Code: CompilerErrorInternal, Message: "sandbox failed to start"}},
+			wantVersion:   CompilerContractV2,
+			wantValidates: true,
+		},
+		{
+			golden:      "response_v2_error_without_code.json",
+			want:        CompilerResponse{Version: 2, ExecutionTime: "0s", Error: &CompilerError{Message: "something went wrong"}},
+			wantVersion: CompilerContractV2,
+		},
+		{
+			// a newer engine's reply is refused rather than read as the nearest known version
+			golden:      "response_v3.json",
+			want:        CompilerResponse{Version: 3, Output: `{"totalTestCases":2,"passedTestCases":2,"failedTestCases":0}`, ExecutionTime: "1s"},
+			wantVersion: 3,
+		},
+		{golden: "response_malformed.json", wantParseErr: true},
+		{golden: "response_version_not_a_number.json", wantParseErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var got CompilerResponse
+			err := json.Unmarshal(golden(t, tt.golden), &got)
+			if tt.wantParseErr {
+				if err == nil {
+					t.Fatalf("Unmarshal = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("response = %+v, want %+v", got, tt.want)
+			}
+			if v := got.ContractVersion(); v != tt.wantVersion {
+				t.Errorf("ContractVersion = %d, want %d", v, tt.wantVersion)
+			}
+			err = got.Validate()
+			if tt.wantValidates && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.wantValidates && !errors.Is(err, ErrInvalidCompilerResponse) {
+				t.Errorf("Validate = %v, want ErrInvalidCompilerResponse", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Kinds of first success discrepancy found by the consistency check
+const (
+	// FirstSuccessMissing is a submission marked as a first success that has no first success entry for its user and
+	// problem, left behind when PushSubmissionData failed between its writes. Repaired by the check.
+	FirstSuccessMissing = "MISSING"
+	// FirstSuccessOrphaned is a first success entry whose submission is gone or no longer a success
+	FirstSuccessOrphaned = "ORPHANED"
+	// FirstSuccessDuplicate is a first success entry beyond the earliest for the same user and problem
+	FirstSuccessDuplicate = "DUPLICATE"
+	// FirstSuccessScoreMismatch is a first success entry whose score differs from its submission's
+	FirstSuccessScoreMismatch = "SCORE_MISMATCH"
+)
+
+// FirstSuccessDiscrepancy is one disagreement between the submissions and first success collections
+type FirstSuccessDiscrepancy struct {
+	Kind            string    `json:"kind" bson:"kind"`
+	UserID          string    `json:"userId" bson:"userId"`
+	ProblemID       string    `json:"problemId" bson:"problemId"`
+	SubmissionID    string    `json:"submissionId" bson:"submissionId"`
+	Score           int       `json:"score" bson:"score"` // of the first success entry, 0 when missing
+	SubmissionScore int       `json:"submissionScore" bson:"submissionScore"`
+	SubmittedAt     time.Time `json:"submittedAt" bson:"submittedAt"`
+	Repaired        bool      `json:"repaired" bson:"repaired"`
+}
+
+type CheckFirstSuccessConsistencyRequest struct {
+	Since  time.Time `json:"since"`  // only check submissions from then on, zero checks all of them
+	DryRun bool      `json:"dryRun"` // only report, repair nothing
+}
+
+type CheckFirstSuccessConsistencyResponse struct {
+	Discrepancies []FirstSuccessDiscrepancy `json:"discrepancies"`
+	Repaired      int                       `json:"repaired"`
+	Resynced      int                       `json:"resynced"` // users whose leaderboard scores were refreshed
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Contest statuses, derived from the clock and FinalizedAt by Contest.Status
+const (
+	ContestStatusScheduled = "SCHEDULED" // before StartsAt, registration may be open
+	ContestStatusRunning   = "RUNNING"   // between StartsAt and EndsAt
+	ContestStatusEnded     = "ENDED"     // past EndsAt, standings not final yet
+	ContestStatusFinalized = "FINALIZED" // official standings stored and ratings applied
+)
+
+const (
+	// DefaultContestDivision is the only division of contests that define none
+	DefaultContestDivision = "open"
+	// DefaultContestRating is the rating of users who have not taken part in a rated contest yet
+	DefaultContestRating = 1500
+	// ContestWrongAttemptPenalty is added to the penalty of a solved problem for every rejected attempt before it
+	ContestWrongAttemptPenalty = 20 * time.Minute
+)
+
+// Contest is a scheduled, official round with a fixed problem set that is only revealed at StartsAt. Unlike
+// challenge rooms it has a registration window, divisions by rating, official standings and, when Rated, changes
+// the ratings of those who took part.
+type Contest struct {
+	ID                   primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title                string             `json:"title" bson:"title"`
+	Description          string             `json:"description,omitempty" bson:"description,omitempty"`
+	Divisions            []ContestDivision  `json:"divisions" bson:"divisions"`
+	RegistrationOpensAt  time.Time          `json:"registrationOpensAt" bson:"registrationOpensAt"`
+	RegistrationClosesAt time.Time          `json:"registrationClosesAt" bson:"registrationClosesAt"`
+	StartsAt             time.Time          `json:"startsAt" bson:"startsAt"`
+	EndsAt               time.Time          `json:"endsAt" bson:"endsAt"`
+	// ProblemIDs are in contest order (A, B, C, ...); handlers leave them out before StartsAt
+	ProblemIDs  []string   `json:"problemIds,omitempty" bson:"problemIds"`
+	Rated       bool       `json:"rated" bson:"rated"`
+	CreatedBy   string     `json:"createdBy" bson:"createdBy"`
+	CreatedAt   time.Time  `json:"createdAt" bson:"createdAt"`
+	FinalizedAt *time.Time `json:"finalizedAt,omitempty" bson:"finalizedAt"`
+}
+
+// Status reports where the contest stands at now
+func (c Contest) Status(now time.Time) string {
+	switch {
+	case c.FinalizedAt != nil:
+		return ContestStatusFinalized
+	case now.Before(c.StartsAt):
+		return ContestStatusScheduled
+	case now.Before(c.EndsAt):
+		return ContestStatusRunning
+	}
+	return ContestStatusEnded
+}
+
+// ContestDivision takes the users whose rating is at least MinRating and below MaxRating; MaxRating 0 has no upper
+// bound
+type ContestDivision struct {
+	Name      string `json:"name" bson:"name"`
+	MinRating int    `json:"minRating" bson:"minRating"`
+	MaxRating int    `json:"maxRating,omitempty" bson:"maxRating,omitempty"`
+}
+
+// ContestRegistration places a user in a division by the rating they had when registering
+type ContestRegistration struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ContestID    string             `json:"contestId" bson:"contestId"`
+	UserID       string             `json:"userId" bson:"userId"`
+	Division     string             `json:"division" bson:"division"`
+	Rating       int                `json:"rating" bson:"rating"`
+	RegisteredAt time.Time          `json:"registeredAt" bson:"registeredAt"`
+}
+
+// ContestStanding is one participant's row, ranked within their division by problems solved and then penalty
+// minutes. Registered users without a submission are not participants.
+type ContestStanding struct {
+	ContestID    string                 `json:"contestId" bson:"contestId"`
+	Division     string                 `json:"division" bson:"division"`
+	UserID       string                 `json:"userId" bson:"userId"`
+	Rank         int                    `json:"rank" bson:"rank"`
+	Solved       int                    `json:"solved" bson:"solved"`
+	Penalty      int                    `json:"penalty" bson:"penalty"` // minutes
+	Problems     []ContestProblemResult `json:"problems" bson:"problems"`
+	RatingBefore int                    `json:"ratingBefore,omitempty" bson:"ratingBefore,omitempty"`
+	RatingAfter  int                    `json:"ratingAfter,omitempty" bson:"ratingAfter,omitempty"`
+}
+
+// ContestProblemResult is how a participant did on one problem. Attempts counts rejected submissions before the
+// accepted one, or all of them when the problem was not solved.
+type ContestProblemResult struct {
+	ProblemID     string `json:"problemId" bson:"problemId"`
+	Solved        bool   `json:"solved" bson:"solved"`
+	Attempts      int    `json:"attempts" bson:"attempts"`
+	SolvedMinutes int    `json:"solvedMinutes,omitempty" bson:"solvedMinutes,omitempty"` // since StartsAt
+}
+
+// UserRating is a user's contest rating, changed by every rated contest they take part in
+type UserRating struct {
+	UserID    string    `json:"userId" bson:"_id"`
+	Rating    int       `json:"rating" bson:"rating"`
+	Contests  int       `json:"contests" bson:"contests"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// ContestProblem is a contest problem as shown to participants
+type ContestProblem struct {
+	Index      string `json:"index"` // A, B, C, ...
+	ProblemID  string `json:"problemId"`
+	Title      string `json:"title"`
+	Difficulty string `json:"difficulty"`
+}
+
+type CreateContestRequest struct {
+	Contest Contest `json:"contest"`
+	AdminID string  `json:"adminId"`
+}
+
+type CreateContestResponse struct {
+	Contest Contest `json:"contest"`
+}
+
+type GetContestRequest struct {
+	ContestID string `json:"contestId"`
+}
+
+type GetContestResponse struct {
+	Contest Contest `json:"contest"`
+	Status  string  `json:"status"`
+}
+
+type RegisterForContestRequest struct {
+	ContestID string `json:"contestId"`
+	UserID    string `json:"userId"`
+}
+
+// RegisterForContestResponse has Created false when the user was already registered
+type RegisterForContestResponse struct {
+	Registration ContestRegistration `json:"registration"`
+	Created      bool                `json:"created"`
+}
+
+// GetContestProblemsRequest needs the UserID of a registered user while the contest runs; once it ended the
+// problems are public
+type GetContestProblemsRequest struct {
+	ContestID string `json:"contestId"`
+	UserID    string `json:"userId"`
+}
+
+type GetContestProblemsResponse struct {
+	Problems []ContestProblem `json:"problems"`
+}
+
+type GetContestStandingsRequest struct {
+	ContestID string `json:"contestId"`
+	Division  string `json:"division"` // empty for the first division
+	Page      int64  `json:"page"`
+	Limit     int64  `json:"limit"`
+}
+
+// GetContestStandingsResponse has Final false while the standings are computed live and may still change
+type GetContestStandingsResponse struct {
+	Standings []ContestStanding `json:"standings"`
+	Total     int64             `json:"total"`
+	Final     bool              `json:"final"`
+}
+
+type FinalizeContestRequest struct {
+	ContestID string `json:"contestId"`
+	AdminID   string `json:"adminId"`
+}
+
+type FinalizeContestResponse struct {
+	Contest      Contest `json:"contest"`
+	Participants int     `json:"participants"`
+}
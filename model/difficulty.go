@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// EmpiricalDifficulty is how hard a problem turned out to be, computed from its submissions by the difficulty
+// recalibration job. Difficulty is estimated from AcceptanceRate and only trusted once Attempters is large enough;
+// Mislabeled is set when a trusted estimate disagrees with the authored difficulty.
+type EmpiricalDifficulty struct {
+	Difficulty         string    `json:"difficulty" bson:"difficulty"`
+	AcceptanceRate     float64   `json:"acceptanceRate" bson:"acceptanceRate"` // accepted submissions over all submissions
+	MedianAttempts     float64   `json:"medianAttempts" bson:"medianAttempts"` // submissions up to a solver's first accept
+	MedianTimeToAccept float64   `json:"medianTimeToAcceptSeconds" bson:"medianTimeToAcceptSeconds"`
+	Attempters         int       `json:"attempters" bson:"attempters"`
+	Solvers            int       `json:"solvers" bson:"solvers"`
+	Trusted            bool      `json:"trusted" bson:"trusted"`
+	Mislabeled         bool      `json:"mislabeled" bson:"mislabeled"`
+	ComputedAt         time.Time `json:"computedAt" bson:"computedAt"`
+}
+
+// ProblemSolveStats are the raw submission statistics of one problem the empirical difficulty is computed from.
+// AttemptsToAccept and SecondsToAccept have one entry per solver.
+type ProblemSolveStats struct {
+	ProblemID        string    `bson:"_id"`
+	Submissions      int       `bson:"submissions"`
+	Accepted         int       `bson:"accepted"`
+	Attempters       int       `bson:"attempters"`
+	Solvers          int       `bson:"solvers"`
+	AttemptsToAccept []int     `bson:"attemptsToAccept"`
+	SecondsToAccept  []float64 `bson:"secondsToAccept"`
+}
+
+// ProblemDifficulty puts a problem's authored difficulty next to its empirical one, nil until the job computed it
+type ProblemDifficulty struct {
+	ProblemID  string               `json:"problemId"`
+	Title      string               `json:"title"`
+	Difficulty string               `json:"difficulty"`
+	Empirical  *EmpiricalDifficulty `json:"empirical,omitempty"`
+}
+
+type GetProblemDifficultyRequest struct {
+	ProblemID string `json:"problemId"`
+}
+
+type GetProblemDifficultyResponse struct {
+	Problem ProblemDifficulty `json:"problem"`
+}
+
+type ListMislabeledProblemsRequest struct {
+	AdminID string `json:"adminId"`
+}
+
+type ListMislabeledProblemsResponse struct {
+	Problems []ProblemDifficulty `json:"problems"`
+}
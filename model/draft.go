@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// CodeDraft is the in-progress editor content of a user for one problem and language
+type CodeDraft struct {
+	ID        string    `bson:"_id" json:"-"`
+	UserID    string    `bson:"userId" json:"userId"`
+	ProblemID string    `bson:"problemId" json:"problemId"`
+	Language  string    `bson:"language" json:"language"`
+	Code      string    `bson:"code" json:"code"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+func CodeDraftID(userID, problemID, language string) string {
+	return userID + ":" + problemID + ":" + language
+}
+
+type SaveCodeDraftRequest struct {
+	UserID    string `json:"userId" bson:"userId"`
+	ProblemID string `json:"problemId" bson:"problemId"`
+	Language  string `json:"language" bson:"language"`
+	Code      string `json:"code" bson:"code"`
+}
+
+type SaveCodeDraftResponse struct {
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+type GetCodeDraftRequest struct {
+	UserID    string `json:"userId" bson:"userId"`
+	ProblemID string `json:"problemId" bson:"problemId"`
+	Language  string `json:"language" bson:"language"`
+}
+
+type GetCodeDraftResponse struct {
+	Draft *CodeDraft `json:"draft,omitempty" bson:"draft,omitempty"` // nil when the user has no draft
+}
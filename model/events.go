@@ -0,0 +1,125 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventEnvelopeVersion is bumped whenever the shape of an event's Data changes incompatibly
+const EventEnvelopeVersion = 1
+
+// EventEnvelope wraps every domain event published by this service, so consumers can route on Type and
+// reject versions they do not understand before decoding Data
+type EventEnvelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	Source     string          `json:"source"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	TraceID    string          `json:"traceId,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+const (
+	ProblemEventCreated   = "problems.events.created"
+	ProblemEventUpdated   = "problems.events.updated"
+	ProblemEventValidated = "problems.events.validated"
+	ProblemEventDeleted   = "problems.events.deleted"
+)
+
+// ProblemEvent is the Data of a problem lifecycle event; fields that did not change on an update are left empty
+type ProblemEvent struct {
+	ProblemID  string   `json:"problemId"`
+	Title      string   `json:"title,omitempty"`
+	Difficulty string   `json:"difficulty,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Visible    *bool    `json:"visible,omitempty"`
+	Validated  *bool    `json:"validated,omitempty"`
+}
+
+// UserCountryChangedSubject is published by the user service whenever a user's country changes
+const UserCountryChangedSubject = "user.country.changed"
+
+// UserCountryChangedEvent is the payload of user.country.changed
+type UserCountryChangedEvent struct {
+	EventID         string    `json:"eventId"`
+	UserID          string    `json:"userId"`
+	Country         string    `json:"country"`
+	PreviousCountry string    `json:"previousCountry,omitempty"`
+	ChangedAt       time.Time `json:"changedAt"`
+}
+
+// ConfigChangedSubject is published by a replica after it applied a configuration reload
+const ConfigChangedSubject = "problems.config.changed"
+
+// ConfigChangedEvent is the payload of problems.config.changed. Changed names the groups of settings that took new
+// values: logLevel, cacheTTL, rateLimit, cron or features.
+type ConfigChangedEvent struct {
+	EventID   string    `json:"eventId"`
+	Replica   string    `json:"replica"`
+	Changed   []string  `json:"changed"`
+	Trigger   string    `json:"trigger"` // what caused the reload, e.g. SIGHUP or the .env file changing
+	ChangedAt time.Time `json:"changedAt"`
+	TraceID   string    `json:"traceId,omitempty"`
+}
+
+// DeadLetterSubject receives every event this service gave up on, with the original payload and the last error
+const DeadLetterSubject = "problems.deadletter"
+
+// DeadLetterSubjectExecutionResult is the subject recorded for execution engine replies that could not be parsed
+const DeadLetterSubjectExecutionResult = "execution.result"
+
+// DeadLetter is an event that failed parsing or kept failing in its handler. It is published to DeadLetterSubject and
+// kept in MongoDB so it can be inspected and replayed once the cause is fixed.
+type DeadLetter struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Subject string             `bson:"subject" json:"subject"`
+	// Data is the original payload as received; it is kept as a string because malformed payloads are not valid JSON
+	Data     string    `bson:"data" json:"data"`
+	Error    string    `bson:"error" json:"error"`
+	Attempts int       `bson:"attempts" json:"attempts"`
+	FailedAt time.Time `bson:"failedAt" json:"failedAt"`
+	// Replayable is false for payloads that were replies rather than events, such as execution results
+	Replayable     bool       `bson:"replayable" json:"replayable"`
+	ReplayCount    int        `bson:"replayCount" json:"replayCount"`
+	LastReplayedAt *time.Time `bson:"lastReplayedAt,omitempty" json:"lastReplayedAt,omitempty"`
+}
+
+type ListDeadLettersRequest struct {
+	Subject  string `json:"subject" bson:"subject"` // optional, exact subject to filter on
+	Page     int64  `json:"page" bson:"page"`
+	PageSize int64  `json:"pageSize" bson:"pageSize"`
+}
+
+type ListDeadLettersResponse struct {
+	DeadLetters []DeadLetter `json:"deadLetters" bson:"deadLetters"`
+	Total       int64        `json:"total" bson:"total"`
+}
+
+type ReplayDeadLetterRequest struct {
+	ID      string `json:"id" bson:"id"`
+	AdminID string `json:"adminId" bson:"adminId"`
+}
+
+type ReplayDeadLetterResponse struct {
+	ID          string `json:"id" bson:"id"`
+	Subject     string `json:"subject" bson:"subject"`
+	ReplayCount int    `json:"replayCount" bson:"replayCount"`
+}
+
+// OutboxEvent is an event written in the same transaction as the change it announces and published afterwards by
+// the outbox relay, so an event is never lost to a broker outage between the write and the publish
+type OutboxEvent struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Subject string             `bson:"subject" json:"subject"`
+	Data    []byte             `bson:"data" json:"data"`
+	// MsgID is the JetStream deduplication ID; events without one go out on core NATS
+	MsgID     string     `bson:"msgId,omitempty" json:"msgId,omitempty"`
+	TraceID   string     `bson:"traceId,omitempty" json:"traceId,omitempty"`
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
+	SentAt    *time.Time `bson:"sentAt" json:"sentAt,omitempty"` // nil until published
+	Attempts  int        `bson:"attempts" json:"attempts"`
+	LastError string     `bson:"lastError,omitempty" json:"lastError,omitempty"`
+}
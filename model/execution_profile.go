@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Bounds of an ExecutionProfile
+const (
+	MaxProfileCompilerFlags = 16
+	MaxProfileTimeLimitMs   = 60_000
+	MaxProfileMemoryLimitMb = 4096
+)
+
+var (
+	// profileRuntimeVersion and profileCompilerFlag only allow what the engine can pass on without a shell
+	profileRuntimeVersion = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]{0,31}$`)
+	profileCompilerFlag   = regexp.MustCompile(`^-[A-Za-z0-9_=+.,:/-]{1,63}$`)
+)
+
+// ExecutionProfile is how the execution engine runs one language: which runtime version, with which compiler flags
+// and, unless zero, with which limits instead of the configured defaults. Profiles are sent along with every run from
+// compiler contract v2 on, so upgrading a runtime or changing flags needs no engine redeploy.
+type ExecutionProfile struct {
+	Language       string    `json:"language" bson:"_id"`
+	RuntimeVersion string    `json:"runtimeVersion,omitempty" bson:"runtimeVersion,omitempty"` // e.g. "3.12", empty for the engine's default
+	CompilerFlags  []string  `json:"compilerFlags,omitempty" bson:"compilerFlags,omitempty"`   // e.g. ["-O2", "-std=c++17"]
+	TimeLimitMs    int64     `json:"timeLimitMs,omitempty" bson:"timeLimitMs,omitempty"`
+	MemoryLimitMb  int64     `json:"memoryLimitMb,omitempty" bson:"memoryLimitMb,omitempty"`
+	UpdatedBy      string    `json:"updatedBy" bson:"updatedBy"`
+	UpdatedAt      time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate checks the runtime version, flags and limits; the language is checked by the caller
+func (p ExecutionProfile) Validate() error {
+	if p.RuntimeVersion != "" && !profileRuntimeVersion.MatchString(p.RuntimeVersion) {
+		return fmt.Errorf("runtime version %q is not a version", p.RuntimeVersion)
+	}
+	if len(p.CompilerFlags) > MaxProfileCompilerFlags {
+		return fmt.Errorf("a profile has at most %d compiler flags", MaxProfileCompilerFlags)
+	}
+	for _, flag := range p.CompilerFlags {
+		if !profileCompilerFlag.MatchString(flag) {
+			return fmt.Errorf("compiler flag %q is not allowed", flag)
+		}
+	}
+	if p.TimeLimitMs < 0 || p.TimeLimitMs > MaxProfileTimeLimitMs {
+		return fmt.Errorf("time limit must be 0 to %d ms", MaxProfileTimeLimitMs)
+	}
+	if p.MemoryLimitMb < 0 || p.MemoryLimitMb > MaxProfileMemoryLimitMb {
+		return fmt.Errorf("memory limit must be 0 to %d MB", MaxProfileMemoryLimitMb)
+	}
+	return nil
+}
+
+type SetExecutionProfileRequest struct {
+	AdminID string           `json:"adminId"`
+	Profile ExecutionProfile `json:"profile"`
+}
+
+type SetExecutionProfileResponse struct {
+	Profile ExecutionProfile `json:"profile"`
+}
+
+type ListExecutionProfilesRequest struct{}
+
+type ListExecutionProfilesResponse struct {
+	Profiles []ExecutionProfile `json:"profiles"`
+}
+
+// DeleteExecutionProfileRequest makes the engine run the language with its own defaults again
+type DeleteExecutionProfileRequest struct {
+	AdminID  string `json:"adminId"`
+	Language string `json:"language"`
+}
+
+type DeleteExecutionProfileResponse struct {
+	Language string `json:"language"`
+	Deleted  bool   `json:"deleted"` // false when the language had no profile
+}
@@ -0,0 +1,9 @@
+package model
+
+// GetFeatureFlagsRequest asks which features are switched on, so clients can hide what the service does not offer
+type GetFeatureFlagsRequest struct{}
+
+type GetFeatureFlagsResponse struct {
+	Environment string          `json:"environment" bson:"environment"`
+	Flags       map[string]bool `json:"flags" bson:"flags"` // by flag name, e.g. "asyncSubmissions"
+}
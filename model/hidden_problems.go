@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// HiddenProblems is the set of problems a user asked not to be shown again, one document per user
+type HiddenProblems struct {
+	UserID     string    `json:"userId" bson:"_id"`
+	ProblemIDs []string  `json:"problemIds" bson:"problemIds"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// HideProblemForUserRequest leaves ProblemID out of UserID's problem lists and random picks until it is unhidden
+type HideProblemForUserRequest struct {
+	UserID    string `json:"userId"`
+	ProblemID string `json:"problemId"`
+}
+
+type HideProblemForUserResponse struct {
+	ProblemID string `json:"problemId"`
+	Hidden    bool   `json:"hidden"` // false when it already was
+}
+
+type UnhideProblemRequest struct {
+	UserID    string `json:"userId"`
+	ProblemID string `json:"problemId"`
+}
+
+type UnhideProblemResponse struct {
+	ProblemID string `json:"problemId"`
+	Unhidden  bool   `json:"unhidden"` // false when it was not hidden
+}
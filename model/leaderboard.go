@@ -0,0 +1,200 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetLeaderboardPageRequest struct {
+	Page     int64   `json:"page" bson:"page"`
+	PageSize int64   `json:"pageSize" bson:"pageSize"`
+	Entity   *string `json:"entity,omitempty" bson:"entity,omitempty"`
+}
+
+type GetLeaderboardPageResponse struct {
+	Users      []RankedUserScore `json:"users" bson:"users"`
+	TotalCount int64             `json:"totalCount" bson:"totalCount"`
+	Page       int64             `json:"page" bson:"page"`
+	PageSize   int64             `json:"pageSize" bson:"pageSize"`
+}
+
+// RankedUserScore is a leaderboard row with its 1-based position on the board
+type RankedUserScore struct {
+	UserID string  `json:"userId" bson:"_id"`
+	Score  float64 `json:"score" bson:"totalScore"`
+	Entity string  `json:"entity" bson:"primaryCountry"`
+	Rank   int64   `json:"rank" bson:"rank"`
+}
+
+// InactiveUserScore is a user's leaderboard total with when they last solved a problem for the first time
+type InactiveUserScore struct {
+	RankedUserScore `bson:",inline"`
+	LastActiveAt    time.Time `json:"lastActiveAt" bson:"lastActiveAt"`
+}
+
+const (
+	LeaderboardPeriodAllTime = "all"
+	LeaderboardPeriodWeekly  = "weekly"
+	LeaderboardPeriodMonthly = "monthly"
+)
+
+type GetTopKGlobalForPeriodRequest struct {
+	K      int32  `json:"k" bson:"k"`
+	Period string `json:"period" bson:"period"` // all, weekly or monthly
+}
+
+// LeaderboardSeasonSnapshot is the final standing of a weekly or monthly season, kept after the board resets
+type LeaderboardSeasonSnapshot struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Period      string             `bson:"period" json:"period"`
+	SeasonStart time.Time          `bson:"seasonStart" json:"seasonStart"`
+	SeasonEnd   time.Time          `bson:"seasonEnd" json:"seasonEnd"`
+	Users       []RankedUserScore  `bson:"users" json:"users"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+const LeaderboardScopeGlobal = "GLOBAL"
+
+// LeaderboardDailySnapshot is the top of the global board (Scope GLOBAL) or of one entity on a given UTC day
+type LeaderboardDailySnapshot struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Date      time.Time          `bson:"date" json:"date"`
+	Scope     string             `bson:"scope" json:"scope"`
+	Users     []RankedUserScore  `bson:"users" json:"users"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+type GetLeaderboardHistoryRequest struct {
+	Scope  string `json:"scope" bson:"scope"`   // GLOBAL (default) or an entity code
+	UserID string `json:"userId" bson:"userId"` // optional, fills UserHistory
+	Days   int32  `json:"days" bson:"days"`     // how far back to look, defaults to 30
+	TopN   int32  `json:"topN" bson:"topN"`     // users kept per snapshot, defaults to 10
+}
+
+type GetLeaderboardHistoryResponse struct {
+	Snapshots   []LeaderboardDailySnapshot `json:"snapshots" bson:"snapshots"`
+	UserHistory []UserRankPoint            `json:"userHistory,omitempty" bson:"userHistory,omitempty"`
+}
+
+// UserRankPoint is a user's position on one daily snapshot; Rank is 0 when the user was outside the snapshot
+type UserRankPoint struct {
+	Date  string  `json:"date" bson:"date"`
+	Rank  int64   `json:"rank" bson:"rank"`
+	Score float64 `json:"score" bson:"score"`
+}
+
+// UserProfileSummary is the display information resolved from the user service for leaderboard rows
+type UserProfileSummary struct {
+	UserName   string `json:"userName"`
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName"`
+	AvatarData string `json:"avatarData"`
+}
+
+type EnrichedUserScore struct {
+	UserID  string             `json:"userId"`
+	Score   float64            `json:"score"`
+	Entity  string             `json:"entity"`
+	Profile UserProfileSummary `json:"profile"`
+}
+
+type GetTopKWithProfilesResponse struct {
+	Users []EnrichedUserScore `json:"users"`
+}
+
+type GetLeaderboardDataWithProfilesResponse struct {
+	UserID     string              `json:"userId"`
+	Score      float64             `json:"score"`
+	Entity     string              `json:"entity"`
+	GlobalRank int32               `json:"globalRank"`
+	EntityRank int32               `json:"entityRank"`
+	Profile    UserProfileSummary  `json:"profile"`
+	TopKGlobal []EnrichedUserScore `json:"topKGlobal"`
+	TopKEntity []EnrichedUserScore `json:"topKEntity"`
+}
+
+type AdminResyncLeaderboardRequest struct{}
+
+type AdminResyncLeaderboardResponse struct {
+	Success  bool   `json:"success" bson:"success"`
+	Message  string `json:"message" bson:"message"`
+	Duration string `json:"duration" bson:"duration"`
+}
+
+type RecalculateScoresRequest struct {
+	DryRun bool `json:"dryRun" bson:"dryRun"` // only count the documents whose score would change
+}
+
+type RecalculateScoresResponse struct {
+	Scanned  int64 `json:"scanned" bson:"scanned"`
+	Updated  int64 `json:"updated" bson:"updated"`
+	Resynced bool  `json:"resynced" bson:"resynced"`
+}
+
+type NormalizeCountriesRequest struct {
+	DryRun bool `json:"dryRun" bson:"dryRun"` // only report what would change
+}
+
+// NormalizeCountriesResponse maps each stored country that changed to its ISO 3166-1 code, or to "" when it names
+// no country; Documents counts the submissions and first successes that carried them
+type NormalizeCountriesResponse struct {
+	Changes   map[string]string `json:"changes" bson:"changes"`
+	Documents int64             `json:"documents" bson:"documents"`
+	Resynced  bool              `json:"resynced" bson:"resynced"`
+}
+
+type GetEntityStatsRequest struct {
+	Entity *string `json:"entity,omitempty" bson:"entity,omitempty"` // optional, all entities when empty
+}
+
+type GetEntityStatsResponse struct {
+	Entities         []EntityStats `json:"entities" bson:"entities"`
+	MoversWindowDays int32         `json:"moversWindowDays" bson:"moversWindowDays"`
+}
+
+// EntityStats summarises one entity (country); users count towards the entity of their first solve, like on the leaderboard
+type EntityStats struct {
+	Entity       string        `json:"entity" bson:"_id"`
+	Participants int64         `json:"participants" bson:"participants"`
+	TotalSolves  int64         `json:"totalSolves" bson:"totalSolves"`
+	TotalScore   float64       `json:"totalScore" bson:"totalScore"`
+	AverageScore float64       `json:"averageScore" bson:"averageScore"`
+	TopMovers    []EntityMover `json:"topMovers" bson:"topMovers"`
+}
+
+// EntityMover is a user ranked by score gained inside the movers window
+type EntityMover struct {
+	UserID      string  `json:"userId" bson:"_id"`
+	ScoreGained float64 `json:"scoreGained" bson:"recentScore"`
+}
+
+const (
+	RankEventEnteredThreshold = "ENTERED"
+	RankEventLeftThreshold    = "LEFT"
+)
+
+// RankChangeEvent is published on NATS when a user crosses a rank threshold on the global or an entity board.
+// Ranks are 1-based; 0 means the user was not ranked.
+type RankChangeEvent struct {
+	UserID       string    `json:"userId"`
+	Scope        string    `json:"scope"` // GLOBAL or the entity code
+	Event        string    `json:"event"` // ENTERED or LEFT
+	Threshold    int64     `json:"threshold"`
+	PreviousRank int64     `json:"previousRank"`
+	NewRank      int64     `json:"newRank"`
+	OccurredAt   time.Time `json:"occurredAt"`
+}
+
+// LeaderboardUpdate is an outbox record of a first success whose score still has to reach the Redis board. It is
+// written in the same transaction as the first success and keyed by the submission ID, so it is applied at least
+// once and applying it again changes nothing.
+type LeaderboardUpdate struct {
+	SubmissionID string     `bson:"_id" json:"submissionId"`
+	UserID       string     `bson:"userId" json:"userId"`
+	Score        int        `bson:"score" json:"score"`
+	CreatedAt    time.Time  `bson:"createdAt" json:"createdAt"`
+	AppliedAt    *time.Time `bson:"appliedAt" json:"appliedAt,omitempty"` // nil while pending
+	Attempts     int        `bson:"attempts" json:"attempts"`
+	LastError    string     `bson:"lastError,omitempty" json:"lastError,omitempty"`
+}
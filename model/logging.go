@@ -0,0 +1,11 @@
+package model
+
+// SetLogLevelRequest changes the service's minimum log level; an empty Level only reports the current one
+type SetLogLevelRequest struct {
+	Level string `json:"level" bson:"level"` // debug, info, warn or error
+}
+
+type SetLogLevelResponse struct {
+	Level    string `json:"level" bson:"level"`
+	Previous string `json:"previous" bson:"previous"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+)
+
+// AddProblemMaintainerRequest lets UserID change the problem too. The caller, taken from x-user-id, must already
+// maintain it or be an admin.
+type AddProblemMaintainerRequest struct {
+	ProblemID string `json:"problemId"`
+	UserID    string `json:"userId"`
+}
+
+type AddProblemMaintainerResponse struct {
+	ProblemID   string   `json:"problemId"`
+	Maintainers []string `json:"maintainers"`
+	Added       bool     `json:"added"` // false when UserID already maintained it
+}
+
+// ListProblemsByAuthorRequest pages through the problems AuthorID created or maintains, newest first; an empty
+// AuthorID lists the caller's own
+type ListProblemsByAuthorRequest struct {
+	AuthorID string `json:"authorId"`
+	Page     int32  `json:"page"`
+	PageSize int32  `json:"pageSize"`
+}
+
+type ListProblemsByAuthorResponse struct {
+	AuthorID string                   `json:"authorId"`
+	Problems *pb.ListProblemsResponse `json:"problems"`
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -35,6 +36,27 @@ type Problem struct {
 	Validated          bool                `bson:"validated"`
 	ValidatedAt        *time.Time          `bson:"validated_at,omitempty"`
 	Visible            bool                `bson:"visible"`
+	// ValidationHashes holds, per language, the hash of the test cases and validation code that last passed, so
+	// validation can skip languages whose content did not change
+	ValidationHashes map[string]string `bson:"validation_hashes,omitempty"`
+	// Signature is the function the problem asks for, nil until an admin sets it
+	Signature *FunctionSignature `bson:"signature,omitempty"`
+	// Translations are localized variants of the statement by lowercase locale, e.g. "es" or "pt-br"; Title and
+	// Description are in DefaultProblemLocale
+	Translations map[string]ProblemTranslation `bson:"translations,omitempty"`
+	// Tier is ProblemTierPremium for problems only entitled users can open; empty means ProblemTierFree
+	Tier string `bson:"tier,omitempty"`
+	// EmpiricalDifficulty is recomputed from submissions by the difficulty recalibration job, nil until it first runs
+	EmpiricalDifficulty *EmpiricalDifficulty `bson:"empirical_difficulty,omitempty"`
+	// CreatedBy is the user who created the problem; Maintainers, which include them, may change it. Problems
+	// created before authors were recorded have neither and can only be changed by admins.
+	CreatedBy   string   `bson:"created_by,omitempty"`
+	Maintainers []string `bson:"maintainers,omitempty"`
+}
+
+// IsMaintainer reports whether userID created or maintains the problem
+func (p Problem) IsMaintainer(userID string) bool {
+	return userID != "" && (p.CreatedBy == userID || slices.Contains(p.Maintainers, userID))
 }
 
 type ProblemDone struct {
@@ -66,6 +88,7 @@ type Submission struct {
 	ExecutionTime float64            `bson:"executionTime,omitempty" json:"executionTime,omitempty"`
 	Difficulty    string             `bson:"difficulty" json:"difficulty"`
 	IsFirst       bool               `bson:"isFirst" json:"isFirst"`
+	Invalidated   bool               `bson:"invalidated,omitempty" json:"invalidated,omitempty"`
 }
 
 type UserScore struct {
@@ -196,7 +219,106 @@ type ProblemsDoneStatistics struct {
 }
 
 type GetUserRankResponse struct {
-	Username    string `json:"username" bson:"username"`
-	CountryRank int64  `json:"countryRank" bson:"countryRank"`
-	GlobalRank  int64  `json:"globalRank" bson:"globalRank"`
+	Username         string  `json:"username" bson:"username"`
+	CountryRank      int64   `json:"countryRank" bson:"countryRank"`
+	GlobalRank       int64   `json:"globalRank" bson:"globalRank"`
+	TotalRankedUsers int64   `json:"totalRankedUsers" bson:"totalRankedUsers"`
+	Percentile       float64 `json:"percentile" bson:"percentile"` // share of ranked users below this user, 0-100
+	Score            float64 `json:"score" bson:"score"`
+	Entity           string  `json:"entity" bson:"entity"`
+}
+
+// CollectionIndexes names the indexes ensured on one collection
+type CollectionIndexes struct {
+	Database   string   `json:"database" bson:"database"`
+	Collection string   `json:"collection" bson:"collection"`
+	Indexes    []string `json:"indexes" bson:"indexes"`
+}
+
+type EnsureIndexesRequest struct{}
+
+type EnsureIndexesResponse struct {
+	Collections []CollectionIndexes `json:"collections" bson:"collections"`
+}
+
+type ValidateSingleLanguageRequest struct {
+	ProblemID string `json:"problemId" bson:"problemId"`
+	Language  string `json:"language" bson:"language"`
+}
+
+type ValidateSingleLanguageResponse struct {
+	ProblemID string                   `json:"problemId" bson:"problemId"`
+	Result    LanguageValidationResult `json:"result" bson:"result"`
+}
+
+type ValidateProblemRequest struct {
+	ProblemID string `json:"problemId" bson:"problemId"`
+}
+
+// ValidateProblemResponse reports every supported language, not only the first that failed
+type ValidateProblemResponse struct {
+	ProblemID string                     `json:"problemId" bson:"problemId"`
+	Validated bool                       `json:"validated" bson:"validated"`
+	Message   string                     `json:"message" bson:"message"`
+	ErrorType string                     `json:"errorType,omitempty" bson:"errorType,omitempty"`
+	Languages []LanguageValidationResult `json:"languages" bson:"languages"`
+}
+
+// LanguageValidationResult is the outcome of running one language's validation code against the problem's test cases
+type LanguageValidationResult struct {
+	Language string `json:"language" bson:"language"`
+	Passed   bool   `json:"passed" bson:"passed"`
+	// Skipped is set when the language passed before with the same test cases and code and was not run again
+	Skipped         bool            `json:"skipped" bson:"skipped"`
+	ErrorType       string          `json:"errorType,omitempty" bson:"errorType,omitempty"`
+	Message         string          `json:"message,omitempty" bson:"message,omitempty"`
+	TotalTestCases  int             `json:"totalTestCases" bson:"totalTestCases"`
+	PassedTestCases int             `json:"passedTestCases" bson:"passedTestCases"`
+	FailedTestCase  *FailedTestCase `json:"failedTestCase,omitempty" bson:"failedTestCase,omitempty"`
+	DurationMs      int64           `json:"durationMs" bson:"durationMs"` // time the run took, queueing included
+}
+
+// Validation job statuses
+const (
+	ValidationJobQueued    = "queued"
+	ValidationJobRunning   = "running"
+	ValidationJobSucceeded = "succeeded"
+	ValidationJobFailed    = "failed"
+)
+
+// ValidationJob is a full validation running in the background. Languages holds the results so far and Pending the
+// languages still running, so a poller can report progress before the job finishes.
+type ValidationJob struct {
+	ID        primitive.ObjectID         `json:"id" bson:"_id,omitempty"`
+	ProblemID string                     `json:"problemId" bson:"problemId"`
+	Status    string                     `json:"status" bson:"status"`
+	Validated bool                       `json:"validated" bson:"validated"`
+	Message   string                     `json:"message,omitempty" bson:"message,omitempty"`
+	ErrorType string                     `json:"errorType,omitempty" bson:"errorType,omitempty"`
+	Pending   []string                   `json:"pending" bson:"pending"`
+	Languages []LanguageValidationResult `json:"languages" bson:"languages"`
+	TraceID   string                     `json:"traceID,omitempty" bson:"traceId,omitempty"`
+	CreatedAt time.Time                  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time                  `json:"updatedAt" bson:"updatedAt"`
+	// FinishedAt is nil while the job is queued or running
+	FinishedAt *time.Time `json:"finishedAt,omitempty" bson:"finishedAt"`
+}
+
+type ValidateProblemAsyncRequest struct {
+	ProblemID string `json:"problemId" bson:"problemId"`
+}
+
+// ValidateProblemAsyncResponse returns the job to poll; Created is false when a validation of the problem was
+// already queued or running and that job is returned instead
+type ValidateProblemAsyncResponse struct {
+	Job     ValidationJob `json:"job" bson:"job"`
+	Created bool          `json:"created" bson:"created"`
+}
+
+type GetValidationJobRequest struct {
+	JobID string `json:"jobId" bson:"jobId"`
+}
+
+type GetValidationJobResponse struct {
+	Job ValidationJob `json:"job" bson:"job"`
 }
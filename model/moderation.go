@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ModerationActionInvalidateSubmission = "INVALIDATE_SUBMISSION"
+	ModerationActionBanFromLeaderboard   = "BAN_FROM_LEADERBOARD"
+)
+
+// ModerationAudit records every admin moderation action for later review
+type ModerationAudit struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action       string             `bson:"action" json:"action"`
+	AdminID      string             `bson:"adminId" json:"adminId"`
+	TargetUserID string             `bson:"targetUserId" json:"targetUserId"`
+	SubmissionID string             `bson:"submissionId,omitempty" json:"submissionId,omitempty"`
+	Reason       string             `bson:"reason" json:"reason"`
+	ScoreRevoked int                `bson:"scoreRevoked" json:"scoreRevoked"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// LeaderboardBan keeps a user off every leaderboard; their submissions are kept
+type LeaderboardBan struct {
+	UserID    string    `bson:"_id" json:"userId"`
+	AdminID   string    `bson:"adminId" json:"adminId"`
+	Reason    string    `bson:"reason" json:"reason"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+type InvalidateSubmissionRequest struct {
+	SubmissionID string `json:"submissionId" bson:"submissionId"`
+	Reason       string `json:"reason" bson:"reason"`
+}
+
+type InvalidateSubmissionResponse struct {
+	SubmissionID string `json:"submissionId" bson:"submissionId"`
+	UserID       string `json:"userId" bson:"userId"`
+	ScoreRevoked int    `json:"scoreRevoked" bson:"scoreRevoked"`
+}
+
+type BanUserFromLeaderboardRequest struct {
+	UserID string `json:"userId" bson:"userId"`
+	Reason string `json:"reason" bson:"reason"`
+}
+
+type BanUserFromLeaderboardResponse struct {
+	UserID string `json:"userId" bson:"userId"`
+	Banned bool   `json:"banned" bson:"banned"`
+}
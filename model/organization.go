@@ -0,0 +1,99 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of organization
+const (
+	OrganizationSchool  = "SCHOOL"
+	OrganizationCompany = "COMPANY"
+	OrganizationOther   = "OTHER"
+)
+
+// OrganizationKinds lists the kinds CreateOrganization accepts
+var OrganizationKinds = []string{OrganizationSchool, OrganizationCompany, OrganizationOther}
+
+// Organization is a school, company or other group users rank within, next to their country. Users join with the
+// JoinCode, which is only shown to admins.
+type Organization struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Slug      string             `json:"slug" bson:"slug"`
+	Name      string             `json:"name" bson:"name"`
+	Kind      string             `json:"kind" bson:"kind"`
+	JoinCode  string             `json:"joinCode,omitempty" bson:"joinCode"`
+	CreatedBy string             `json:"createdBy" bson:"createdBy"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// OrganizationMember records the one organization a user belongs to
+type OrganizationMember struct {
+	UserID         string    `json:"userId" bson:"_id"`
+	OrganizationID string    `json:"organizationId" bson:"organizationId"`
+	JoinedAt       time.Time `json:"joinedAt" bson:"joinedAt"`
+}
+
+type CreateOrganizationRequest struct {
+	AdminID string `json:"adminId"`
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+}
+
+type CreateOrganizationResponse struct {
+	Organization Organization `json:"organization"`
+}
+
+type GetOrganizationRequest struct {
+	OrganizationID string `json:"organizationId"`
+}
+
+type GetOrganizationResponse struct {
+	Organization Organization `json:"organization"`
+	MemberCount  int64        `json:"memberCount"`
+}
+
+type ListOrganizationsRequest struct {
+	Kind     string `json:"kind"` // optional
+	Page     int64  `json:"page"`
+	PageSize int64  `json:"pageSize"`
+}
+
+type ListOrganizationsResponse struct {
+	Organizations []Organization `json:"organizations"`
+	Total         int64          `json:"total"`
+}
+
+// JoinOrganizationRequest moves UserID into the organization, out of the one they were in
+type JoinOrganizationRequest struct {
+	OrganizationID string `json:"organizationId"`
+	UserID         string `json:"userId"`
+	JoinCode       string `json:"joinCode"`
+}
+
+type JoinOrganizationResponse struct {
+	Member                 OrganizationMember `json:"member"`
+	PreviousOrganizationID string             `json:"previousOrganizationId,omitempty"`
+}
+
+// LeaveOrganizationRequest takes UserID out of their organization. An admin may remove any user by setting AdminID.
+type LeaveOrganizationRequest struct {
+	UserID  string `json:"userId"`
+	AdminID string `json:"adminId,omitempty"`
+}
+
+type LeaveOrganizationResponse struct {
+	OrganizationID string `json:"organizationId,omitempty"` // empty when the user was in none
+}
+
+type GetTopKOrganizationRequest struct {
+	OrganizationID string `json:"organizationId"`
+	K              int32  `json:"k"`
+}
+
+type GetTopKOrganizationResponse struct {
+	OrganizationID string            `json:"organizationId"`
+	Users          []RankedUserScore `json:"users"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// Access tiers of a problem
+const (
+	ProblemTierFree    = "free"
+	ProblemTierPremium = "premium"
+)
+
+// ProblemTiers lists the tiers SetProblemTier accepts
+var ProblemTiers = []string{ProblemTierFree, ProblemTierPremium}
+
+// SetProblemTierRequest makes a problem free or premium
+type SetProblemTierRequest struct {
+	ProblemID string `json:"problemId"`
+	Tier      string `json:"tier"`
+	AdminID   string `json:"adminId"`
+}
+
+type SetProblemTierResponse struct {
+	ProblemID string `json:"problemId"`
+	Tier      string `json:"tier"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+)
+
+// GetProblemsByIDsRequest asks for the metadata of up to a page of problems by ID
+type GetProblemsByIDsRequest struct {
+	ProblemIDs []string `json:"problemIds"`
+}
+
+// ProblemLookup is the result for one requested ID. Found is false, and Problem nil, when the ID is malformed or
+// names a problem that is deleted, hidden or never existed.
+type ProblemLookup struct {
+	ProblemID string                  `json:"problemId"`
+	Found     bool                    `json:"found"`
+	Problem   *pb.ProblemMetadataLite `json:"problem,omitempty"`
+}
+
+// GetProblemsByIDsResponse has one lookup per distinct requested ID, in the order they were requested
+type GetProblemsByIDsResponse struct {
+	Problems []ProblemLookup `json:"problems"`
+}
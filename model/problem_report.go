@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What a problem report is about
+const (
+	ProblemReportWrongTestCase    = "WRONG_TEST_CASE"
+	ProblemReportUnclearStatement = "UNCLEAR_STATEMENT"
+	ProblemReportBrokenTemplate   = "BROKEN_TEMPLATE"
+	ProblemReportOther            = "OTHER"
+)
+
+// ProblemReportCategories lists the categories ReportProblemIssue accepts
+var ProblemReportCategories = []string{ProblemReportWrongTestCase, ProblemReportUnclearStatement, ProblemReportBrokenTemplate, ProblemReportOther}
+
+// Statuses of a problem report; a report only moves forward, OPEN to TRIAGED to FIXED, and may skip TRIAGED
+const (
+	ProblemReportOpen    = "OPEN"
+	ProblemReportTriaged = "TRIAGED"
+	ProblemReportFixed   = "FIXED"
+)
+
+// ProblemReport is a user's report of something wrong with a problem. A problem's revision is its updated_at:
+// ProblemRevision is the revision the user saw, FixedRevision the one an admin marked as fixing it.
+type ProblemReport struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProblemID       string             `json:"problemId" bson:"problemId"`
+	UserID          string             `json:"userId" bson:"userId"`
+	Category        string             `json:"category" bson:"category"`
+	Language        string             `json:"language,omitempty" bson:"language,omitempty"` // of a broken template
+	Description     string             `json:"description" bson:"description"`
+	Status          string             `json:"status" bson:"status"`
+	ProblemRevision time.Time          `json:"problemRevision" bson:"problemRevision"`
+	FixedRevision   *time.Time         `json:"fixedRevision,omitempty" bson:"fixedRevision,omitempty"`
+	Note            string             `json:"note,omitempty" bson:"note,omitempty"` // the admin's, shown to the reporter
+	HandledBy       string             `json:"handledBy,omitempty" bson:"handledBy,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// ProblemReportFilter narrows ListProblemReports; zero fields match everything
+type ProblemReportFilter struct {
+	ProblemID string
+	Status    string
+	Category  string
+}
+
+type ReportProblemIssueRequest struct {
+	ProblemID   string `json:"problemId"`
+	UserID      string `json:"userId"`
+	Category    string `json:"category"`
+	Language    string `json:"language"`
+	Description string `json:"description"`
+}
+
+type ReportProblemIssueResponse struct {
+	Report ProblemReport `json:"report"`
+}
+
+type ListProblemReportsRequest struct {
+	AdminID   string `json:"adminId"`
+	ProblemID string `json:"problemId"`
+	Status    string `json:"status"`
+	Category  string `json:"category"`
+	Page      int64  `json:"page"`
+	PageSize  int64  `json:"pageSize"`
+}
+
+type ListProblemReportsResponse struct {
+	Reports []ProblemReport `json:"reports"`
+	Total   int64           `json:"total"`
+}
+
+// UpdateProblemReportStatusRequest moves a report to Status. Marking it FIXED links it to the problem's current
+// revision, which must be newer than the one reported.
+type UpdateProblemReportStatusRequest struct {
+	ReportID string `json:"reportId"`
+	AdminID  string `json:"adminId"`
+	Status   string `json:"status"`
+	Note     string `json:"note"`
+}
+
+type UpdateProblemReportStatusResponse struct {
+	Report ProblemReport `json:"report"`
+}
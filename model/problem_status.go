@@ -0,0 +1,40 @@
+package model
+
+import (
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+)
+
+// A user's progress on a problem
+const (
+	ProblemStatusSolved    = "SOLVED"    // at least one accepted submission that was not invalidated
+	ProblemStatusAttempted = "ATTEMPTED" // submissions, none of them accepted
+	ProblemStatusUntouched = "UNTOUCHED"
+)
+
+// ListProblemsWithStatusRequest is ListProblems plus the user whose progress to annotate the page with; an empty
+// UserID falls back to the caller's x-user-id
+type ListProblemsWithStatusRequest struct {
+	Request *pb.ListProblemsRequest `json:"request"`
+	UserID  string                  `json:"userId"`
+}
+
+// ListProblemsWithStatusResponse has the status of every problem on the page by problem ID. Statuses is empty when
+// no user is known or their progress could not be loaded.
+type ListProblemsWithStatusResponse struct {
+	Problems *pb.ListProblemsResponse `json:"problems"`
+	Statuses map[string]string        `json:"statuses,omitempty"`
+}
+
+// GetProblemMetadataListWithStatusRequest is GetProblemMetadataList plus the user whose progress to annotate the page
+// with; an empty UserID falls back to the caller's x-user-id
+type GetProblemMetadataListWithStatusRequest struct {
+	Request *pb.GetProblemMetadataListRequest `json:"request"`
+	UserID  string                            `json:"userId"`
+}
+
+// GetProblemMetadataListWithStatusResponse has the status of every problem on the page by problem ID, see
+// ListProblemsWithStatusResponse
+type GetProblemMetadataListWithStatusResponse struct {
+	Problems *pb.GetProblemMetadataListResponse `json:"problems"`
+	Statuses map[string]string                  `json:"statuses,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+)
+
+// RandomProblemFilter narrows GetRandomProblem; zero fields match everything
+type RandomProblemFilter struct {
+	Difficulty      string
+	Tags            []string // the problem has all of them
+	ExcludeSolvedBy string   // a user whose solved problems are skipped
+	ExcludeIDs      []string // problems skipped regardless, such as the ones the user hid
+	ExcludePremium  bool
+}
+
+// GetRandomProblemRequest picks a problem at random among the visible ones matching Difficulty and Tags, skipping
+// those ExcludeSolvedForUser already solved
+type GetRandomProblemRequest struct {
+	Difficulty           string   `json:"difficulty"`
+	Tags                 []string `json:"tags"`
+	ExcludeSolvedForUser string   `json:"excludeSolvedForUser"`
+}
+
+type GetRandomProblemResponse struct {
+	Problem *pb.ProblemMetadataLite `json:"problem"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rejudge job statuses
+const (
+	RejudgeJobQueued    = "queued"
+	RejudgeJobRunning   = "running"
+	RejudgeJobSucceeded = "succeeded"
+	RejudgeJobFailed    = "failed"
+)
+
+// RejudgeJob runs a problem's stored submissions against its current test cases in the background. Processed counts
+// up to Total as it goes, so a poller can report progress before the job finishes.
+type RejudgeJob struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProblemID string             `json:"problemId" bson:"problemId"`
+	AdminID   string             `json:"adminId" bson:"adminId"`
+	Since     *time.Time         `json:"since,omitempty" bson:"since,omitempty"` // nil rejudges every submission
+	Status    string             `json:"status" bson:"status"`
+	Total     int64              `json:"total" bson:"total"`
+	Processed int64              `json:"processed" bson:"processed"`
+	Changed   int64              `json:"changed" bson:"changed"` // submissions whose verdict flipped
+	// Errored counts submissions that could not be rejudged, e.g. their language was removed; they keep their verdict
+	Errored int64 `json:"errored" bson:"errored"`
+	// UsersRescored counts users whose first success on the problem, and so their score, moved
+	UsersRescored int64      `json:"usersRescored" bson:"usersRescored"`
+	Message       string     `json:"message,omitempty" bson:"message,omitempty"`
+	TraceID       string     `json:"traceID,omitempty" bson:"traceId,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt" bson:"updatedAt"`
+	FinishedAt    *time.Time `json:"finishedAt,omitempty" bson:"finishedAt"` // nil while the job is queued or running
+}
+
+// RejudgeProblemRequest rejudges the problem's accepted and failed submissions, only those submitted at or after
+// Since when it is set. Invalidated submissions are left alone.
+type RejudgeProblemRequest struct {
+	ProblemID string     `json:"problemId"`
+	Since     *time.Time `json:"since,omitempty"`
+}
+
+// RejudgeProblemResponse returns the job to poll; Created is false when a rejudge of the problem was already queued
+// or running and that job is returned instead
+type RejudgeProblemResponse struct {
+	Job     RejudgeJob `json:"job"`
+	Created bool       `json:"created"`
+}
+
+type GetRejudgeJobRequest struct {
+	JobID string `json:"jobId"`
+}
+
+type GetRejudgeJobResponse struct {
+	Job RejudgeJob `json:"job"`
+}
@@ -0,0 +1,32 @@
+package model
+
+// EngineRuntime is one language as the execution engine reports it on problems.runtimes.request
+type EngineRuntime struct {
+	Language string   `json:"language"`
+	Versions []string `json:"versions"`
+	Default  string   `json:"default"`
+}
+
+// EngineRuntimesResponse is the engine's reply on problems.runtimes.request
+type EngineRuntimesResponse struct {
+	Runtimes []EngineRuntime `json:"runtimes"`
+}
+
+// LanguageRuntime is a language problems can be written in, with the runtime versions the engine offers for it.
+// CurrentVersion is the one runs use: the language's ExecutionProfile version, else the engine's default.
+type LanguageRuntime struct {
+	Language       string   `json:"language"`
+	Aliases        []string `json:"aliases"` // other spellings accepted for Language
+	Versions       []string `json:"versions"`
+	DefaultVersion string   `json:"defaultVersion,omitempty"`
+	CurrentVersion string   `json:"currentVersion,omitempty"`
+}
+
+type GetSupportedRuntimesRequest struct{}
+
+// GetSupportedRuntimesResponse lists every language; FromEngine is false when the engine could not be asked, in which
+// case no versions are known
+type GetSupportedRuntimesResponse struct {
+	Runtimes   []LanguageRuntime `json:"runtimes"`
+	FromEngine bool              `json:"fromEngine"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+type GetServiceStatsRequest struct {
+	AdminID string `json:"adminId" bson:"adminId"`
+	Days    int    `json:"days" bson:"days"` // days of submissions to count, today included
+}
+
+// ServiceStats is the admin dashboard overview. The MongoDB totals cover the whole service; the cache and engine
+// rates are those of the replica that answered, since it started.
+type ServiceStats struct {
+	Problems          ProblemCounts `json:"problems" bson:"problems"`
+	SubmissionsPerDay []DailyCount  `json:"submissionsPerDay" bson:"submissionsPerDay"`
+	ActiveChallenges  int64         `json:"activeChallenges" bson:"activeChallenges"` // challenges with submissions in the window
+	LeaderboardSize   int64         `json:"leaderboardSize" bson:"leaderboardSize"`   // users with at least one first success
+	Cache             RateStats     `json:"cache" bson:"cache"`
+	Engine            RateStats     `json:"engine" bson:"engine"`
+	GeneratedAt       time.Time     `json:"generatedAt" bson:"generatedAt"`
+}
+
+// ProblemCounts counts problems that are not deleted
+type ProblemCounts struct {
+	Total        int64            `json:"total" bson:"total"`
+	ByDifficulty map[string]int64 `json:"byDifficulty" bson:"byDifficulty"`
+	Validated    int64            `json:"validated" bson:"validated"`
+	Unvalidated  int64            `json:"unvalidated" bson:"unvalidated"`
+}
+
+// DailyCount is a count for one UTC day, keyed YYYY-MM-DD
+type DailyCount struct {
+	Date  string `json:"date" bson:"date"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// RateStats is the share of Total that matched, e.g. cache hits among lookups or errors among engine requests
+type RateStats struct {
+	Total int64   `json:"total" bson:"total"`
+	Rate  float64 `json:"rate" bson:"rate"`
+}
+
+type GetServiceStatsResponse struct {
+	Stats ServiceStats `json:"stats" bson:"stats"`
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SignatureTypes are the language-neutral types a FunctionSignature may use. Any of them can be made an array by
+// appending [], e.g. int[][]; void is only allowed as a return type.
+var SignatureTypes = []string{"int", "long", "double", "bool", "char", "string", "ListNode", "TreeNode"}
+
+// FunctionSignature describes the function a problem asks for, so editors can show typed parameters and starter
+// code can be generated per language without parsing the validation templates
+type FunctionSignature struct {
+	Name       string          `bson:"name" json:"name"`
+	Params     []FunctionParam `bson:"params" json:"params"`
+	ReturnType string          `bson:"returnType" json:"returnType"`
+}
+
+type FunctionParam struct {
+	Name string `bson:"name" json:"name"`
+	Type string `bson:"type" json:"type"`
+}
+
+var signatureIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate checks that the names are identifiers, parameter names are unique and every type is one of
+// SignatureTypes
+func (f FunctionSignature) Validate() error {
+	if !signatureIdentifier.MatchString(f.Name) {
+		return fmt.Errorf("function name %q is not an identifier", f.Name)
+	}
+	seen := make(map[string]bool, len(f.Params))
+	for i, param := range f.Params {
+		if !signatureIdentifier.MatchString(param.Name) {
+			return fmt.Errorf("parameter %d name %q is not an identifier", i+1, param.Name)
+		}
+		if seen[param.Name] {
+			return fmt.Errorf("parameter %q is declared twice", param.Name)
+		}
+		seen[param.Name] = true
+		if !isSignatureType(param.Type) {
+			return fmt.Errorf("parameter %q has unknown type %q", param.Name, param.Type)
+		}
+	}
+	if f.ReturnType != "void" && !isSignatureType(f.ReturnType) {
+		return fmt.Errorf("unknown return type %q", f.ReturnType)
+	}
+	return nil
+}
+
+func isSignatureType(t string) bool {
+	for strings.HasSuffix(t, "[]") {
+		t = strings.TrimSuffix(t, "[]")
+	}
+	for _, known := range SignatureTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFunctionSignatureRequest sets the signature of a problem; a nil Signature removes it
+type SetFunctionSignatureRequest struct {
+	ProblemID string             `json:"problemId" bson:"problemId"`
+	Signature *FunctionSignature `json:"signature,omitempty" bson:"signature,omitempty"`
+	AdminID   string             `json:"adminId" bson:"adminId"`
+}
+
+type SetFunctionSignatureResponse struct {
+	Signature *FunctionSignature `json:"signature,omitempty" bson:"signature,omitempty"`
+}
+
+type GetFunctionSignatureRequest struct {
+	ProblemID string `json:"problemId" bson:"problemId"`
+}
+
+// GetFunctionSignatureResponse has a nil Signature when the problem has none yet
+type GetFunctionSignatureResponse struct {
+	Signature *FunctionSignature `json:"signature,omitempty" bson:"signature,omitempty"`
+}
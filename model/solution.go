@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Orders of ListSolutions
+const (
+	SolutionSortVotes  = "votes"  // most voted first, newest first among equal votes
+	SolutionSortRecent = "recent" // newest first
+)
+
+// Solution is a write-up a user posted in a problem's gallery. Its code and language are copied from the accepted
+// submission it was posted from, so every solution in the gallery is one that passed.
+type Solution struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProblemID    string             `json:"problemId" bson:"problemId"`
+	UserID       string             `json:"userId" bson:"userId"`
+	SubmissionID string             `json:"submissionId" bson:"submissionId"`
+	Title        string             `json:"title" bson:"title"`
+	Body         string             `json:"body" bson:"body"` // markdown
+	Language     string             `json:"language" bson:"language"`
+	Code         string             `json:"code" bson:"code"`
+	Votes        int                `json:"votes" bson:"votes"` // upvotes minus downvotes
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// SolutionVote is one user's vote on a solution, Value 1 or -1
+type SolutionVote struct {
+	ID         string    `json:"id" bson:"_id"` // SolutionVoteID
+	SolutionID string    `json:"solutionId" bson:"solutionId"`
+	UserID     string    `json:"userId" bson:"userId"`
+	Value      int       `json:"value" bson:"value"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// SolutionVoteID derives the ID of a user's vote so a user has at most one vote per solution
+func SolutionVoteID(solutionID, userID string) string {
+	return solutionID + ":" + userID
+}
+
+// PostSolutionRequest posts a write-up of the user's accepted submission SubmissionID
+type PostSolutionRequest struct {
+	SubmissionID string `json:"submissionId"`
+	UserID       string `json:"userId"`
+	Title        string `json:"title"`
+	Body         string `json:"body"`
+}
+
+type PostSolutionResponse struct {
+	Solution Solution `json:"solution"`
+}
+
+type ListSolutionsRequest struct {
+	ProblemID string `json:"problemId"`
+	Sort      string `json:"sort"` // SolutionSortVotes (default) or SolutionSortRecent
+	Page      int64  `json:"page"`
+	Limit     int64  `json:"limit"`
+}
+
+type ListSolutionsResponse struct {
+	Solutions []Solution `json:"solutions"`
+	Total     int64      `json:"total"`
+}
+
+// VoteSolutionRequest sets the user's vote: Value 1 upvotes, -1 downvotes and 0 withdraws the vote
+type VoteSolutionRequest struct {
+	SolutionID string `json:"solutionId"`
+	UserID     string `json:"userId"`
+	Value      int    `json:"value"`
+}
+
+type VoteSolutionResponse struct {
+	SolutionID string `json:"solutionId"`
+	Votes      int    `json:"votes"`
+	Value      int    `json:"value"`
+}
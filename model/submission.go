@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+)
+
+// FirstSolver is the user who solved a problem first, globally (empty ChallengeID) or inside a challenge.
+// ID is derived from problem and challenge so concurrent upserts cannot record two first solvers.
+type FirstSolver struct {
+	ID           string    `bson:"_id" json:"-"`
+	ProblemID    string    `bson:"problemId" json:"problemId"`
+	ChallengeID  string    `bson:"challengeId,omitempty" json:"challengeId,omitempty"`
+	UserID       string    `bson:"userId" json:"userId"`
+	SubmissionID string    `bson:"submissionId" json:"submissionId"`
+	Language     string    `bson:"language" json:"language"`
+	SolvedAt     time.Time `bson:"solvedAt" json:"solvedAt"`
+}
+
+func FirstSolverID(problemID, challengeID string) string {
+	if challengeID == "" {
+		return problemID
+	}
+	return problemID + ":" + challengeID
+}
+
+type GetFirstSolversRequest struct {
+	ProblemIDs  []string `json:"problemIds" bson:"problemIds"`
+	ChallengeID *string  `json:"challengeId,omitempty" bson:"challengeId,omitempty"`
+}
+
+type GetFirstSolversResponse struct {
+	FirstSolvers []FirstSolver `json:"firstSolvers" bson:"firstSolvers"`
+}
+
+type ListSubmissionsRequest struct {
+	ProblemID *string    `json:"problemId,omitempty" bson:"problemId,omitempty"`
+	UserID    string     `json:"userId" bson:"userId"`
+	Status    string     `json:"status,omitempty" bson:"status,omitempty"`     // SUCCESS or FAILED
+	Language  string     `json:"language,omitempty" bson:"language,omitempty"` // normalised, e.g. "py" matches python
+	From      *time.Time `json:"from,omitempty" bson:"from,omitempty"`         // inclusive
+	To        *time.Time `json:"to,omitempty" bson:"to,omitempty"`             // exclusive
+	IsFirst   *bool      `json:"isFirst,omitempty" bson:"isFirst,omitempty"`
+	Limit     int64      `json:"limit" bson:"limit"`
+	Cursor    string     `json:"cursor,omitempty" bson:"cursor,omitempty"` // NextCursor of the previous page, empty for the first page
+}
+
+type ListSubmissionsResponse struct {
+	Submissions []*pb.Submission `json:"submissions" bson:"submissions"`
+	NextCursor  string           `json:"nextCursor,omitempty" bson:"nextCursor,omitempty"`
+}
+
+// SubmissionFilter selects submissions for listing; empty fields don't filter
+type SubmissionFilter struct {
+	ProblemID string
+	UserID    string
+	Status    string
+	Language  string
+	From      *time.Time
+	To        *time.Time
+	IsFirst   *bool
+}
+
+type GetBestSubmissionsRequest struct {
+	UserID     string `json:"userId" bson:"userId"`
+	ByLanguage bool   `json:"byLanguage" bson:"byLanguage"` // one entry per problem and language instead of per problem
+}
+
+type GetBestSubmissionsResponse struct {
+	Submissions []*pb.Submission `json:"submissions" bson:"submissions"`
+}
+
+// SharedSubmission maps a public share token to an accepted submission until ExpiresAt
+type SharedSubmission struct {
+	Token        string    `bson:"_id" json:"token"`
+	SubmissionID string    `bson:"submissionId" json:"submissionId"`
+	UserID       string    `bson:"userId" json:"userId"`
+	CreatedAt    time.Time `bson:"createdAt" json:"createdAt"`
+	ExpiresAt    time.Time `bson:"expiresAt" json:"expiresAt"`
+}
+
+type ShareSubmissionRequest struct {
+	SubmissionID   string `json:"submissionId" bson:"submissionId"`
+	UserID         string `json:"userId" bson:"userId"`                 // must own the submission
+	ExpiresInHours int32  `json:"expiresInHours" bson:"expiresInHours"` // defaults to 7 days
+}
+
+type ShareSubmissionResponse struct {
+	Token     string    `json:"token" bson:"token"`
+	ExpiresAt time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+type GetSharedSubmissionRequest struct {
+	Token string `json:"token" bson:"token"`
+}
+
+type GetSharedSubmissionResponse struct {
+	Submission *pb.Submission          `json:"submission" bson:"submission"` // Id is cleared, the token is the only handle
+	Problem    *pb.ProblemMetadataLite `json:"problem" bson:"problem"`
+	ExpiresAt  time.Time               `json:"expiresAt" bson:"expiresAt"`
+}
+
+const (
+	SubmissionEventCreated  = "submission.created"
+	SubmissionEventAccepted = "submission.accepted"
+)
+
+// SubmissionEvent is published to JetStream once a submission is stored; accepted events are only sent for SUCCESS
+type SubmissionEvent struct {
+	SubmissionID  string    `json:"submissionId"`
+	UserID        string    `json:"userId"`
+	ProblemID     string    `json:"problemId"`
+	Country       string    `json:"country"`
+	Language      string    `json:"language"`
+	Status        string    `json:"status"`
+	Score         int       `json:"score"`
+	IsFirst       bool      `json:"isFirst"`
+	ExecutionTime float64   `json:"executionTime"`
+	SubmittedAt   time.Time `json:"submittedAt"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultProblemLocale is the language a problem's own title and description are written in
+const DefaultProblemLocale = "en"
+
+// ProblemTranslation is a problem statement in another language. Examples is markdown that follows the
+// description, for explanations of the examples that would otherwise stay in DefaultProblemLocale.
+type ProblemTranslation struct {
+	Title       string    `bson:"title" json:"title"`
+	Description string    `bson:"description" json:"description"`
+	Examples    string    `bson:"examples,omitempty" json:"examples,omitempty"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lowercases a BCP 47 style locale such as pt-BR and checks its shape; underscores are accepted
+// for hyphens
+func NormalizeLocale(locale string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if !localePattern.MatchString(normalized) {
+		return "", fmt.Errorf("locale %q is not a language tag such as es or pt-BR", locale)
+	}
+	return normalized, nil
+}
+
+type UpsertProblemTranslationRequest struct {
+	ProblemID   string `json:"problemId"`
+	Locale      string `json:"locale"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Examples    string `json:"examples"`
+	AdminID     string `json:"adminId"`
+}
+
+// UpsertProblemTranslationResponse has Created false when an existing translation was replaced
+type UpsertProblemTranslationResponse struct {
+	ProblemID   string             `json:"problemId"`
+	Locale      string             `json:"locale"`
+	Translation ProblemTranslation `json:"translation"`
+	Created     bool               `json:"created"`
+}
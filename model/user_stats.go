@@ -0,0 +1,37 @@
+package model
+
+type GetUserLanguageStatsRequest struct {
+	UserID string `json:"userId" bson:"userId"`
+}
+
+type GetUserLanguageStatsResponse struct {
+	Languages []LanguageStats `json:"languages" bson:"languages"`
+}
+
+// LanguageStats summarises a user's submissions in one language; AcceptanceRate is accepted/submissions in percent
+type LanguageStats struct {
+	Language       string  `json:"language" bson:"_id"`
+	Submissions    int64   `json:"submissions" bson:"submissions"`
+	Accepted       int64   `json:"accepted" bson:"accepted"`
+	ProblemsSolved int64   `json:"problemsSolved" bson:"problemsSolved"`
+	AcceptanceRate float64 `json:"acceptanceRate" bson:"acceptanceRate"`
+}
+
+type GetYearlyActivityHeatmapRequest struct {
+	UserID string `json:"userId" bson:"userId"`
+	Year   int32  `json:"year" bson:"year"` // 0 for the trailing 365 days ending today
+}
+
+type GetYearlyActivityHeatmapResponse struct {
+	Data    []ActivityDay   `json:"data" bson:"data"`
+	Summary ActivitySummary `json:"summary" bson:"summary"`
+}
+
+// ActivitySummary aggregates a heatmap range; streaks count consecutive active UTC days
+type ActivitySummary struct {
+	TotalSubmissions int         `json:"totalSubmissions" bson:"totalSubmissions"`
+	TotalActiveDays  int         `json:"totalActiveDays" bson:"totalActiveDays"`
+	BusiestDay       ActivityDay `json:"busiestDay" bson:"busiestDay"`
+	CurrentStreak    int         `json:"currentStreak" bson:"currentStreak"` // ends today or yesterday, else 0
+	LongestStreak    int         `json:"longestStreak" bson:"longestStreak"`
+}
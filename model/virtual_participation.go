@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VirtualParticipation replays a contest that ended for one user: they get the contest's duration from StartedAt,
+// and their submissions in that window are ranked against the original participants as they stood at the same
+// point of the contest. It never changes the contest's official standings or anyone's rating.
+type VirtualParticipation struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ContestID string             `json:"contestId" bson:"contestId"`
+	UserID    string             `json:"userId" bson:"userId"`
+	Division  string             `json:"division" bson:"division"` // whose historical standings they are ranked in
+	StartedAt time.Time          `json:"startedAt" bson:"startedAt"`
+	EndsAt    time.Time          `json:"endsAt" bson:"endsAt"`
+}
+
+type StartVirtualParticipationRequest struct {
+	ContestID string `json:"contestId"`
+	UserID    string `json:"userId"`
+}
+
+// StartVirtualParticipationResponse has Created false when the user had already started one, which is returned
+// unchanged
+type StartVirtualParticipationResponse struct {
+	Participation VirtualParticipation `json:"participation"`
+	Created       bool                 `json:"created"`
+}
+
+type GetVirtualStandingsRequest struct {
+	ContestID string `json:"contestId"`
+	UserID    string `json:"userId"`
+	Page      int64  `json:"page"`
+	Limit     int64  `json:"limit"`
+}
+
+// GetVirtualStandingsResponse is the private scoreboard of a virtual participant: the historical standings of their
+// division ElapsedMinutes into the contest with their own row, Participant, ranked in
+type GetVirtualStandingsResponse struct {
+	Participation  VirtualParticipation `json:"participation"`
+	ElapsedMinutes int                  `json:"elapsedMinutes"`
+	Finished       bool                 `json:"finished"`
+	Participant    ContestStanding      `json:"participant"`
+	Standings      []ContestStanding    `json:"standings"`
+	Total          int64                `json:"total"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Events a webhook can subscribe to. The payload is an EventEnvelope whose Type is the event and whose Data is a
+// SubmissionEvent, a ChallengeEndedEvent or a ProblemEvent.
+const (
+	WebhookEventSubmissionAccepted = "submission.accepted"
+	WebhookEventChallengeEnded     = "challenge.ended"
+	WebhookEventProblemPublished   = "problem.published"
+)
+
+// WebhookEventTypes lists the events RegisterWebhook accepts
+var WebhookEventTypes = []string{WebhookEventSubmissionAccepted, WebhookEventChallengeEnded, WebhookEventProblemPublished}
+
+// Statuses of a webhook delivery; PENDING ones are retried until they are DELIVERED or run out of attempts
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliveryDelivered = "DELIVERED"
+	WebhookDeliveryFailed    = "FAILED"
+)
+
+// Webhook is an endpoint outside the service, such as a Discord or Slack bot, that is sent EventTypes. Secret signs
+// every payload; unlike API key secrets it is stored as is, since signing needs it.
+type Webhook struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	URL         string             `json:"url" bson:"url"`
+	Secret      string             `json:"-" bson:"secret"`
+	EventTypes  []string           `json:"eventTypes" bson:"eventTypes"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	CreatedBy   string             `json:"createdBy" bson:"createdBy"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	DeletedAt   *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+}
+
+// WebhookDelivery is one event sent to one webhook, and the log of how sending it went
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	WebhookID      string             `json:"webhookId" bson:"webhookId"`
+	EventID        string             `json:"eventId" bson:"eventId"` // the envelope's ID, shared by every webhook sent the event
+	EventType      string             `json:"eventType" bson:"eventType"`
+	Payload        string             `json:"payload" bson:"payload"`
+	Status         string             `json:"status" bson:"status"`
+	Attempts       int                `json:"attempts" bson:"attempts"`
+	NextAttemptAt  time.Time          `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	LastStatusCode int                `json:"lastStatusCode,omitempty" bson:"lastStatusCode,omitempty"`
+	LastError      string             `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	DeliveredAt    *time.Time         `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
+
+// ChallengeEndedEvent is the Data of challenge.ended, sent once a contest is finalized and its standings are final
+type ChallengeEndedEvent struct {
+	ChallengeID  string    `json:"challengeId"`
+	Title        string    `json:"title"`
+	EndsAt       time.Time `json:"endsAt"`
+	Rated        bool      `json:"rated"`
+	Participants int       `json:"participants"`
+}
+
+// RegisterWebhookRequest subscribes URL to EventTypes. The secret to check signatures with is returned once.
+type RegisterWebhookRequest struct {
+	AdminID     string   `json:"adminId"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"eventTypes"`
+	Description string   `json:"description"`
+}
+
+type RegisterWebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+	Secret  string  `json:"secret"`
+}
+
+type ListWebhooksRequest struct {
+	AdminID string `json:"adminId"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// DeleteWebhookRequest stops sending events to a webhook; deliveries still pending are dropped
+type DeleteWebhookRequest struct {
+	AdminID   string `json:"adminId"`
+	WebhookID string `json:"webhookId"`
+}
+
+type DeleteWebhookResponse struct {
+	WebhookID string `json:"webhookId"`
+}
+
+// ListWebhookDeliveriesRequest pages through a webhook's deliveries newest first, optionally of one Status
+type ListWebhookDeliveriesRequest struct {
+	AdminID   string `json:"adminId"`
+	WebhookID string `json:"webhookId"`
+	Status    string `json:"status"`
+	Page      int64  `json:"page"`
+	PageSize  int64  `json:"pageSize"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int64             `json:"total"`
+}
@@ -3,26 +3,93 @@ package mongoconn
 import (
 	"context"
 	"fmt"
-	"log"
+	"strconv"
+	"time"
+	zap_betterstack "xcode/logger"
+	"xcode/tracing"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
-func ConnectDB() *mongo.Client {
-	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+// Options configures the MongoDB client; zero values keep the driver's defaults
+type Options struct {
+	URI string
 
-	client, err := mongo.Connect(context.TODO(), clientOptions)
+	MaxPoolSize            uint64
+	MinPoolSize            uint64        // connections kept open while idle
+	ConnectTimeout         time.Duration // per connection attempt
+	ServerSelectionTimeout time.Duration // how long an operation waits for a usable server
+	SocketTimeout          time.Duration // per read or write on a connection
+
+	// WriteConcern is "majority" or the number of nodes that must acknowledge a write
+	WriteConcern string
+	// ReadConcern is the read concern level, e.g. "local" or "majority"
+	ReadConcern string
+
+	// SlowQueryThreshold is the duration above which a command is logged and counted as slow
+	SlowQueryThreshold time.Duration
+	Logger             *zap_betterstack.BetterStackLogStreamer
+}
+
+// ConnectDB connects and pings the primary, so a returned client is known to be usable. On error nothing is left
+// open and the caller may try again.
+func ConnectDB(ctx context.Context, opts Options) (*mongo.Client, error) {
+	clientOptions, err := clientOptions(opts)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	// Ping the database
-	err = client.Ping(context.TODO(), nil)
+	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatal("Could not connect to MongoDB:", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
+	return client, nil
+}
+
+func clientOptions(opts Options) (*options.ClientOptions, error) {
+	monitor := combineMonitors(tracing.MongoMonitor(), SlowQueryMonitor(opts.SlowQueryThreshold, opts.Logger))
+	clientOptions := options.Client().ApplyURI(opts.URI).SetMonitor(monitor)
 
-	fmt.Println("Connected to MongoDB!")
-	return client
+	if opts.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(opts.MaxPoolSize)
+	}
+	if opts.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(opts.MinPoolSize)
+	}
+	if opts.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(opts.ConnectTimeout)
+	}
+	if opts.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(opts.ServerSelectionTimeout)
+	}
+	if opts.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(opts.SocketTimeout)
+	}
+
+	switch opts.WriteConcern {
+	case "":
+	case "majority":
+		clientOptions.SetWriteConcern(writeconcern.Majority())
+	default:
+		nodes, err := strconv.Atoi(opts.WriteConcern)
+		if err != nil || nodes < 0 {
+			return nil, fmt.Errorf("invalid write concern %q, want \"majority\" or a number of nodes", opts.WriteConcern)
+		}
+		clientOptions.SetWriteConcern(&writeconcern.WriteConcern{W: nodes})
+	}
+	if opts.ReadConcern != "" {
+		clientOptions.SetReadConcern(&readconcern.ReadConcern{Level: opts.ReadConcern})
+	}
+
+	if err := clientOptions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid MongoDB options: %w", err)
+	}
+	return clientOptions, nil
 }
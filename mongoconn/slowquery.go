@@ -0,0 +1,202 @@
+package mongoconn
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	zap_betterstack "xcode/logger"
+	"xcode/metrics"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/event"
+	"go.uber.org/zap/zapcore"
+)
+
+// slowCommandsTotal counts commands slower than the threshold, labelled collection.command
+var slowCommandsTotal = metrics.NewCounterVec("mongo_slow_commands_total", "command")
+
+// queryFields is where each command keeps the filter that decides which index is used
+var queryFields = map[string]string{
+	"find":          "filter",
+	"count":         "query",
+	"distinct":      "query",
+	"findAndModify": "query",
+}
+
+// SlowQueryMonitor returns a command monitor that logs and counts every command running longer than threshold,
+// with its collection, its filter reduced to field names and operators (values are replaced by "?", they may hold
+// user data) and, for aggregations, the names of the pipeline stages.
+func SlowQueryMonitor(threshold time.Duration, logger *zap_betterstack.BetterStackLogStreamer) *event.CommandMonitor {
+	var started sync.Map // request ID -> bson.Raw command; the driver hands monitors their own copy
+
+	finish := func(ctx context.Context, requestID int64, commandName string, duration time.Duration, failure string) {
+		value, ok := started.LoadAndDelete(requestID)
+		if !ok || duration < threshold {
+			return
+		}
+		command := value.(bson.Raw)
+
+		collection, _ := command.Lookup(commandName).StringValueOK()
+		attributes := map[string]any{
+			"command":     commandName,
+			"collection":  collection,
+			"durationMs":  duration.Milliseconds(),
+			"thresholdMs": threshold.Milliseconds(),
+		}
+		if filter := commandFilter(command, commandName); filter != "" {
+			attributes["filter"] = filter
+		}
+		if commandName == "aggregate" {
+			attributes["pipeline"] = pipelineStages(command)
+		}
+		if failure != "" {
+			attributes["failure"] = failure
+		}
+
+		slowCommandsTotal.Add(collection+"."+commandName, 1)
+		traceID := zap_betterstack.TraceIDFromContext(ctx)
+		if traceID == "" {
+			traceID = uuid.New().String()
+		}
+		logger.Log(zapcore.WarnLevel, traceID, "Slow MongoDB command", attributes, "DATABASE", nil)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			started.Store(evt.RequestID, evt.Command)
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			finish(ctx, evt.RequestID, evt.CommandName, evt.Duration, "")
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			finish(ctx, evt.RequestID, evt.CommandName, evt.Duration, evt.Failure)
+		},
+	}
+}
+
+// commandFilter returns the sanitized filter of a command: the first $match of an aggregation, the first
+// statement's filter of a bulk update or delete, "" for commands without one
+func commandFilter(command bson.Raw, commandName string) string {
+	var filter bson.RawValue
+	switch commandName {
+	case "aggregate":
+		stages, ok := command.Lookup("pipeline").ArrayOK()
+		if !ok {
+			return ""
+		}
+		values, _ := stages.Values()
+		for _, stage := range values {
+			if doc, ok := stage.DocumentOK(); ok {
+				if match, err := doc.LookupErr("$match"); err == nil {
+					filter = match
+					break
+				}
+			}
+		}
+	case "update", "delete":
+		statements, ok := command.Lookup(commandName + "s").ArrayOK()
+		if !ok {
+			return ""
+		}
+		first, err := statements.IndexErr(0)
+		if err != nil {
+			return ""
+		}
+		if doc, ok := first.Value().DocumentOK(); ok {
+			filter = doc.Lookup("q")
+		}
+	default:
+		field, ok := queryFields[commandName]
+		if !ok {
+			return ""
+		}
+		filter = command.Lookup(field)
+	}
+	if filter.Type != bsontype.EmbeddedDocument {
+		return ""
+	}
+	var b strings.Builder
+	writeSanitized(&b, filter)
+	return b.String()
+}
+
+// pipelineStages lists the stage names of an aggregation, e.g. "$match > $group > $sort"
+func pipelineStages(command bson.Raw) string {
+	stages, ok := command.Lookup("pipeline").ArrayOK()
+	if !ok {
+		return ""
+	}
+	values, _ := stages.Values()
+	names := make([]string, 0, len(values))
+	for _, stage := range values {
+		doc, ok := stage.DocumentOK()
+		if !ok {
+			continue
+		}
+		if elements, err := doc.Elements(); err == nil && len(elements) > 0 {
+			names = append(names, elements[0].Key())
+		}
+	}
+	return strings.Join(names, " > ")
+}
+
+// writeSanitized writes value keeping document keys, so the shape of a filter stays readable, and replacing
+// everything else by "?"
+func writeSanitized(b *strings.Builder, value bson.RawValue) {
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		elements, _ := value.Document().Elements()
+		b.WriteString("{")
+		for i, element := range elements {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(element.Key())
+			b.WriteString(": ")
+			writeSanitized(b, element.Value())
+		}
+		b.WriteString("}")
+	case bsontype.Array:
+		// $and/$or hold documents worth showing, $in lists only values
+		values, _ := value.Array().Values()
+		if len(values) > 0 && values[0].Type == bsontype.EmbeddedDocument {
+			b.WriteString("[")
+			for i, v := range values {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				writeSanitized(b, v)
+			}
+			b.WriteString("]")
+			return
+		}
+		b.WriteString("[?]")
+	default:
+		b.WriteString("?")
+	}
+}
+
+// combineMonitors fans every event out to each monitor, the driver accepts only one
+func combineMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				m.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				m.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				m.Failed(ctx, evt)
+			}
+		},
+	}
+}
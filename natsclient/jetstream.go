@@ -0,0 +1,133 @@
+package natsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// EventPublisher publishes domain events with at-least-once delivery.
+// msgID lets the broker drop duplicates when a publish is retried.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, subject string, data []byte, msgID string) error
+}
+
+// EventHandler processes one event; returning an error asks the broker to redeliver it later
+type EventHandler func(ctx context.Context, data []byte) error
+
+// ErrPoisonEvent marks handler errors that no redelivery can fix, such as a payload that does not parse.
+// Such events are dead-lettered straight away instead of being retried.
+var ErrPoisonEvent = errors.New("poison event")
+
+// DeadLetterFunc receives an event the consumer gave up on, after which the event is terminated on the stream
+type DeadLetterFunc func(subject string, data []byte, cause error, attempts int)
+
+const (
+	eventAckWait    = 30 * time.Second
+	eventMaxDeliver = 5
+)
+
+// eventRedeliveryBackoff spaces out redeliveries of a failed event; its length must not exceed eventMaxDeliver
+var eventRedeliveryBackoff = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+
+// JetStream wraps a JetStream context on the client's connection
+type JetStream struct {
+	js jetstream.JetStream
+}
+
+var _ EventPublisher = (*JetStream)(nil)
+
+func NewJetStream(n *NatsClient) (*JetStream, error) {
+	js, err := jetstream.New(n.Conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+	return &JetStream{js: js}, nil
+}
+
+// EnsureStream creates the stream or updates its subjects and retention; duplicates are detected within dedupWindow
+func (j *JetStream) EnsureStream(ctx context.Context, name string, subjects []string, maxAge, dedupWindow time.Duration) error {
+	_, err := j.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:       name,
+		Subjects:   subjects,
+		Storage:    jetstream.FileStorage,
+		MaxAge:     maxAge,
+		Duplicates: dedupWindow,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure stream %s: %w", name, err)
+	}
+	return nil
+}
+
+// PublishEvent waits for the stream to acknowledge the event, so a nil error means it is stored
+func (j *JetStream) PublishEvent(ctx context.Context, subject string, data []byte, msgID string) error {
+	if _, err := j.js.Publish(ctx, subject, data, jetstream.WithMsgID(msgID)); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", subject, err)
+	}
+	return nil
+}
+
+// ConsumeDurable delivers events matching filterSubject to handler through a durable consumer, so events published
+// while the service was down are picked up on restart. Events are acked after handler succeeds and redelivered with
+// backoff when it fails. Poison events and events that failed eventMaxDeliver times go to deadLetter instead of
+// being dropped silently. Call Stop on the returned context to stop consuming.
+func (j *JetStream) ConsumeDurable(ctx context.Context, stream, durable, filterSubject string, handler EventHandler, deadLetter DeadLetterFunc) (jetstream.ConsumeContext, error) {
+	consumer, err := j.js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: filterSubject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       eventAckWait,
+		MaxDeliver:    eventMaxDeliver,
+		BackOff:       eventRedeliveryBackoff,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer %s on %s: %w", durable, stream, err)
+	}
+
+	return consumer.Consume(func(msg jetstream.Msg) {
+		handlerCtx, cancel := context.WithTimeout(context.Background(), eventAckWait)
+		defer cancel()
+
+		if err := handler(handlerCtx, msg.Data()); err != nil {
+			attempts := deliveryCount(msg)
+			if errors.Is(err, ErrPoisonEvent) || attempts >= eventMaxDeliver {
+				log.Printf("event %s failed in consumer %s after %d attempts, dead-lettering: %v", msg.Subject(), durable, attempts, err)
+				deadLetter(msg.Subject(), msg.Data(), err, attempts)
+				if termErr := msg.Term(); termErr != nil {
+					log.Printf("failed to terminate event %s in consumer %s: %v", msg.Subject(), durable, termErr)
+				}
+				return
+			}
+			log.Printf("event %s failed in consumer %s, will be redelivered: %v", msg.Subject(), durable, err)
+			if nakErr := msg.NakWithDelay(redeliveryDelay(msg)); nakErr != nil {
+				log.Printf("failed to nak event %s in consumer %s: %v", msg.Subject(), durable, nakErr)
+			}
+			return
+		}
+		if err := msg.Ack(); err != nil {
+			log.Printf("failed to ack event %s in consumer %s: %v", msg.Subject(), durable, err)
+		}
+	})
+}
+
+// deliveryCount is how often the event has been delivered, including this delivery
+func deliveryCount(msg jetstream.Msg) int {
+	if meta, err := msg.Metadata(); err == nil && meta.NumDelivered > 0 {
+		return int(meta.NumDelivered)
+	}
+	return 1
+}
+
+// redeliveryDelay picks the backoff step for a failed event from how often it has been delivered already
+func redeliveryDelay(msg jetstream.Msg) time.Duration {
+	step := deliveryCount(msg) - 1
+	if step >= len(eventRedeliveryBackoff) {
+		step = len(eventRedeliveryBackoff) - 1
+	}
+	return eventRedeliveryBackoff[step]
+}
@@ -1,31 +1,146 @@
 package natsclient
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"math/rand"
 	"time"
 
+	"xcode/metrics"
+	"xcode/tracing"
+
 	"github.com/nats-io/nats.go"
+	"go.uber.org/zap/zapcore"
+
+	zap_betterstack "xcode/logger"
 )
 
 type NatsClient struct {
 	Conn *nats.Conn
 }
 
-func NewNatsClient(natsURL string) (*NatsClient, error) {
-	nc, err := nats.Connect(natsURL)
+// Options controls how the client rides out broker restarts
+type Options struct {
+	MaxReconnects    int           // reconnect attempts before giving up, 0 retries forever
+	ReconnectWait    time.Duration // delay before the first reconnect attempt, doubled on every further attempt
+	MaxReconnectWait time.Duration // upper bound for the reconnect delay
+	ConnectTimeout   time.Duration
+}
+
+// connectionEvents counts disconnects, reconnects, closes and async errors
+var connectionEvents = metrics.NewCounterVec("nats_connection_events_total", "event")
+
+func NewNatsClient(natsURL string, opts Options, logger *zap_betterstack.BetterStackLogStreamer) (*NatsClient, error) {
+	maxReconnects := opts.MaxReconnects
+	if maxReconnects == 0 {
+		maxReconnects = -1
+	}
+
+	nc, err := nats.Connect(natsURL,
+		nats.Timeout(opts.ConnectTimeout),
+		nats.MaxReconnects(maxReconnects),
+		nats.CustomReconnectDelay(func(attempts int) time.Duration {
+			return reconnectDelay(attempts, opts.ReconnectWait, opts.MaxReconnectWait)
+		}),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			connectionEvents.Add("disconnected", 1)
+			logger.Log(zapcore.WarnLevel, "", "Disconnected from NATS", map[string]any{
+				"method": "NewNatsClient",
+				"url":    natsURL,
+			}, "NATS", err)
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			connectionEvents.Add("reconnected", 1)
+			logger.Log(zapcore.InfoLevel, "", "Reconnected to NATS", map[string]any{
+				"method":     "NewNatsClient",
+				"url":        conn.ConnectedUrl(),
+				"reconnects": conn.Stats().Reconnects,
+			}, "NATS", nil)
+		}),
+		nats.ClosedHandler(func(conn *nats.Conn) {
+			connectionEvents.Add("closed", 1)
+			logger.Log(zapcore.ErrorLevel, "", "NATS connection closed", map[string]any{
+				"method": "NewNatsClient",
+				"url":    natsURL,
+			}, "NATS", conn.LastError())
+		}),
+		nats.ErrorHandler(func(conn *nats.Conn, sub *nats.Subscription, err error) {
+			connectionEvents.Add("error", 1)
+			subject := ""
+			if sub != nil {
+				subject = sub.Subject
+			}
+			logger.Log(zapcore.ErrorLevel, "", "NATS async error", map[string]any{
+				"method":  "NewNatsClient",
+				"subject": subject,
+			}, "NATS", err)
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
-	log.Printf("connected to nats ,%s ",natsURL)
+	log.Printf("connected to nats ,%s ", natsURL)
 	return &NatsClient{Conn: nc}, nil
 }
 
+// reconnectDelay doubles the wait on every attempt up to max and adds up to 20% jitter,
+// so replicas that lost the broker together do not reconnect in lockstep
+func reconnectDelay(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// Healthy reports an error while the connection is not usable, e.g. during a reconnect
+func (n *NatsClient) Healthy() error {
+	if n.Conn == nil || !n.Conn.IsConnected() {
+		status := nats.DISCONNECTED
+		if n.Conn != nil {
+			status = n.Conn.Status()
+		}
+		return fmt.Errorf("nats connection is %s", status)
+	}
+	return nil
+}
+
 func (n *NatsClient) Close() {
 	if n.Conn != nil {
 		n.Conn.Close()
 	}
 }
 
+// Drain stops the subscriptions after their pending messages are handled, flushes outstanding publishes and closes
+// the connection. It gives up and closes the connection when ctx is done first.
+func (n *NatsClient) Drain(ctx context.Context) error {
+	if n.Conn == nil || n.Conn.IsClosed() {
+		return nil
+	}
+	if err := n.Conn.Drain(); err != nil {
+		n.Conn.Close()
+		return fmt.Errorf("failed to drain nats connection: %w", err)
+	}
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for !n.Conn.IsClosed() {
+		select {
+		case <-ctx.Done():
+			n.Conn.Close()
+			return fmt.Errorf("nats drain did not finish: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 func (n *NatsClient) Publish(subject string, data []byte) error {
 	return n.Conn.Publish(subject, data)
 }
@@ -37,3 +152,18 @@ func (n *NatsClient) Request(subject string, data []byte, timeout time.Duration)
 func (n *NatsClient) Subscribe(subject string, handler func(*nats.Msg)) (*nats.Subscription, error) {
 	return n.Conn.Subscribe(subject, handler)
 }
+
+// QueueSubscribe delivers each message to only one member of queue, so every instance of the service can
+// subscribe without the message being handled once per instance
+func (n *NatsClient) QueueSubscribe(subject, queue string, handler func(*nats.Msg)) (*nats.Subscription, error) {
+	return n.Conn.QueueSubscribe(subject, queue, handler)
+}
+
+// RequestWithContext is Request bounded by ctx instead of a fixed timeout. The trace context of ctx travels in the
+// message headers.
+func (n *NatsClient) RequestWithContext(ctx context.Context, subject string, data []byte) (*nats.Msg, error) {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	tracing.InjectNATS(ctx, msg)
+	return n.Conn.RequestMsgWithContext(ctx, msg)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateAPIKey stores a new key and returns it with its ID
+func (r *Repository) CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	key.ID = primitive.NewObjectID()
+	if _, err := r.apiKeysCollection.InsertOne(ctx, key); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, customerrors.Conflict("an API key with the same secret exists")
+		}
+		return nil, fmt.Errorf("failed to create API key: %w", dbError(err))
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByHash returns the key whose secret hashes to hash, revoked and expired ones included, or a
+// customerrors.NotFound error
+func (r *Repository) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var key model.APIKey
+	if err := r.apiKeysCollection.FindOne(ctx, bson.M{"hash": hash}).Decode(&key); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("API key")
+		}
+		return nil, fmt.Errorf("failed to fetch API key: %w", dbError(err))
+	}
+	return &key, nil
+}
+
+// ListAPIKeys returns the keys newest first, without the revoked ones unless includeRevoked is set
+func (r *Repository) ListAPIKeys(ctx context.Context, includeRevoked bool) ([]model.APIKey, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{"revokedAt": nil}
+	if includeRevoked {
+		filter = bson.M{}
+	}
+	cursor, err := r.apiKeysCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch API keys: %w", dbError(err))
+	}
+	keys := []model.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", dbError(err))
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey marks a key revoked and returns it. Revoking a revoked key is a customerrors.Conflict.
+func (r *Repository) RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (*model.APIKey, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(keyID)
+	if err != nil {
+		return nil, customerrors.Validation("invalid API key id %q", keyID)
+	}
+
+	var key model.APIKey
+	err = r.apiKeysCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "revokedAt": nil},
+		bson.M{"$set": bson.M{"revokedAt": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		count, countErr := r.apiKeysCollection.CountDocuments(ctx, bson.M{"_id": id})
+		if countErr != nil {
+			return nil, fmt.Errorf("failed to fetch API key %s: %w", keyID, dbError(countErr))
+		}
+		if count > 0 {
+			return nil, customerrors.Conflict("API key %s is already revoked", keyID)
+		}
+		return nil, customerrors.NotFound("API key %s", keyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke API key %s: %w", keyID, dbError(err))
+	}
+	return &key, nil
+}
+
+// TouchAPIKey records that a key was used at
+func (r *Repository) TouchAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if _, err := r.apiKeysCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"lastUsedAt": at}}); err != nil {
+		return fmt.Errorf("failed to record use of API key: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func assignmentObjectID(assignmentID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(assignmentID)
+	if err != nil {
+		return primitive.NilObjectID, customerrors.Validation("invalid assignment id %q", assignmentID)
+	}
+	return id, nil
+}
+
+// CreateAssignment stores a new assignment and returns it with its ID
+func (r *Repository) CreateAssignment(ctx context.Context, assignment model.Assignment) (*model.Assignment, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	assignment.ID = primitive.NewObjectID()
+	if _, err := r.assignmentsCollection.InsertOne(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to create assignment: %w", dbError(err))
+	}
+	return &assignment, nil
+}
+
+// GetAssignment returns a customerrors.NotFound error when the ID is unknown
+func (r *Repository) GetAssignment(ctx context.Context, assignmentID string) (*model.Assignment, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := assignmentObjectID(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignment model.Assignment
+	if err := r.assignmentsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&assignment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("assignment %s", assignmentID)
+		}
+		return nil, fmt.Errorf("failed to fetch assignment %s: %w", assignmentID, dbError(err))
+	}
+	return &assignment, nil
+}
+
+// SetAssignmentRoster replaces the roster of an assignment and returns the assignment
+func (r *Repository) SetAssignmentRoster(ctx context.Context, assignmentID string, roster []string) (*model.Assignment, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := assignmentObjectID(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignment model.Assignment
+	err = r.assignmentsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"roster": roster}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&assignment)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.NotFound("assignment %s", assignmentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update roster of assignment %s: %w", assignmentID, dbError(err))
+	}
+	return &assignment, nil
+}
+
+// JoinAssignment stores enrollment unless the student already joined, in which case the existing enrollment is
+// returned with created false
+func (r *Repository) JoinAssignment(ctx context.Context, enrollment model.AssignmentEnrollment) (*model.AssignmentEnrollment, bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	enrollment.ID = primitive.NewObjectID()
+
+	var stored model.AssignmentEnrollment
+	err := r.assignmentEnrollmentsCollection.FindOneAndUpdate(ctx,
+		bson.M{"assignmentId": enrollment.AssignmentID, "userId": enrollment.UserID},
+		bson.M{"$setOnInsert": enrollment},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&stored)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to enroll user %s in assignment %s: %w", enrollment.UserID, enrollment.AssignmentID, dbError(err))
+	}
+	return &stored, stored.ID == enrollment.ID, nil
+}
+
+// GetAssignmentEnrollment returns a customerrors.NotFound error when the student did not join
+func (r *Repository) GetAssignmentEnrollment(ctx context.Context, assignmentID, userID string) (*model.AssignmentEnrollment, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var enrollment model.AssignmentEnrollment
+	err := r.assignmentEnrollmentsCollection.FindOne(ctx, bson.M{"assignmentId": assignmentID, "userId": userID}).Decode(&enrollment)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.NotFound("enrollment of user %s in assignment %s", userID, assignmentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignment enrollment: %w", dbError(err))
+	}
+	return &enrollment, nil
+}
+
+// GetAssignmentEnrollments returns every enrollment of an assignment
+func (r *Repository) GetAssignmentEnrollments(ctx context.Context, assignmentID string) ([]model.AssignmentEnrollment, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.assignmentEnrollmentsCollection.Find(ctx, bson.M{"assignmentId": assignmentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch enrollments of assignment %s: %w", assignmentID, dbError(err))
+	}
+	enrollments := []model.AssignmentEnrollment{}
+	if err := cursor.All(ctx, &enrollments); err != nil {
+		return nil, fmt.Errorf("failed to decode enrollments of assignment %s: %w", assignmentID, dbError(err))
+	}
+	return enrollments, nil
+}
+
+// GetAssignmentSubmissions returns the valid submissions of userIDs to problemIDs made from from on, and before to
+// unless to is zero, oldest first
+func (r *Repository) GetAssignmentSubmissions(ctx context.Context, userIDs, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	submittedAt := bson.M{"$gte": from}
+	if !to.IsZero() {
+		submittedAt["$lt"] = to
+	}
+	filter := bson.M{
+		"userId":      bson.M{"$in": userIDs},
+		"problemId":   bson.M{"$in": problemIDs},
+		"submittedAt": submittedAt,
+		"invalidated": bson.M{"$ne": true},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"userCode": 0, "output": 0})
+	cursor, err := r.submissionsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignment submissions: %w", dbError(err))
+	}
+	submissions := []model.Submission{}
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode assignment submissions: %w", dbError(err))
+	}
+	return submissions, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveAuditEntry appends entry to the audit log. The repository has no way to change or remove entries.
+func (r *Repository) SaveAuditEntry(ctx context.Context, entry model.AuditEntry) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if _, err := r.auditLogCollection.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+// QueryAuditLog returns the entries matching f newest first, plus the total count
+func (r *Repository) QueryAuditLog(ctx context.Context, f model.AuditLogFilter, skip, limit int64) ([]model.AuditEntry, int64, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	filter := bson.M{}
+	if f.Actor != "" {
+		filter["actor"] = f.Actor
+	}
+	if f.Action != "" {
+		filter["action"] = f.Action
+	}
+	if f.TargetID != "" {
+		filter["targetId"] = f.TargetID
+	}
+	createdAt := bson.M{}
+	if !f.From.IsZero() {
+		createdAt["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		createdAt["$lt"] = f.To
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	total, err := r.auditLogCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.auditLogCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []model.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FindMissingFirstSuccessesMongo returns the successful submissions submitted since since that are marked as a first
+// success but have no first success entry for their user and problem. The user code is left out.
+func (r *Repository) FindMissingFirstSuccessesMongo(ctx context.Context, since time.Time) ([]model.Submission, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	match := bson.M{"isFirst": true, "status": "SUCCESS"}
+	if !since.IsZero() {
+		match["submittedAt"] = bson.M{"$gte": since}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": r.submissionFirstSuccessCollection.Name(),
+			"let":  bson.M{"userId": "$userId", "problemId": "$problemId"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$userId", "$$userId"}},
+					bson.M{"$eq": bson.A{"$problemId", "$$problemId"}},
+				}}}},
+				bson.M{"$limit": 1},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "firstSuccess",
+		}}},
+		{{Key: "$match", Value: bson.M{"firstSuccess": bson.M{"$size": 0}}}},
+		{{Key: "$project", Value: bson.M{"userCode": 0, "output": 0, "firstSuccess": 0}}},
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+	}
+
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find missing first successes: %w", dbError(err))
+	}
+	var submissions []model.Submission
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode missing first successes: %w", dbError(err))
+	}
+	return submissions, nil
+}
+
+// FindFirstSuccessDiscrepanciesMongo compares the first success entries submitted since since against their
+// submissions and returns the orphaned, duplicate and mis-scored ones
+func (r *Repository) FindFirstSuccessDiscrepanciesMongo(ctx context.Context, since time.Time) ([]model.FirstSuccessDiscrepancy, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	match := bson.M{}
+	if !since.IsZero() {
+		match["submittedAt"] = bson.M{"$gte": since}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": r.submissionsCollection.Name(),
+			"let":  bson.M{"submissionId": bson.M{"$convert": bson.M{"input": "$submissionId", "to": "objectId", "onError": nil, "onNull": nil}}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$submissionId"}}}},
+				bson.M{"$project": bson.M{"status": 1, "score": 1}},
+			},
+			"as": "submission",
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"userId": "$userId", "problemId": "$problemId"},
+			"entries": bson.M{"$push": bson.M{
+				"submissionId": "$submissionId",
+				"score":        "$score",
+				"submittedAt":  "$submittedAt",
+				"submission":   bson.M{"$arrayElemAt": bson.A{"$submission", 0}},
+			}},
+		}}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check first successes: %w", dbError(err))
+	}
+	var groups []struct {
+		ID struct {
+			UserID    string `bson:"userId"`
+			ProblemID string `bson:"problemId"`
+		} `bson:"_id"`
+		Entries []struct {
+			SubmissionID string    `bson:"submissionId"`
+			Score        int       `bson:"score"`
+			SubmittedAt  time.Time `bson:"submittedAt"`
+			Submission   *struct {
+				ID     primitive.ObjectID `bson:"_id"`
+				Status string             `bson:"status"`
+				Score  int                `bson:"score"`
+			} `bson:"submission"`
+		} `bson:"entries"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode first success checks: %w", dbError(err))
+	}
+
+	var discrepancies []model.FirstSuccessDiscrepancy
+	for _, group := range groups {
+		for i, entry := range group.Entries {
+			discrepancy := model.FirstSuccessDiscrepancy{
+				UserID:       group.ID.UserID,
+				ProblemID:    group.ID.ProblemID,
+				SubmissionID: entry.SubmissionID,
+				Score:        entry.Score,
+				SubmittedAt:  entry.SubmittedAt,
+			}
+			if entry.Submission != nil {
+				discrepancy.SubmissionScore = entry.Submission.Score
+			}
+			switch {
+			case i > 0:
+				discrepancy.Kind = model.FirstSuccessDuplicate
+			case entry.Submission == nil || entry.Submission.Status != "SUCCESS":
+				discrepancy.Kind = model.FirstSuccessOrphaned
+			case entry.Submission.Score != entry.Score:
+				discrepancy.Kind = model.FirstSuccessScoreMismatch
+			default:
+				continue
+			}
+			discrepancies = append(discrepancies, discrepancy)
+		}
+	}
+	return discrepancies, nil
+}
+
+// RepairFirstSuccess stores the missing first success entry of a submission marked as one, scoring it from the
+// score table when the submission lost its score as well, and returns the score. It returns 0 when the user and
+// problem got a first success entry in the meantime.
+func (r *Repository) RepairFirstSuccess(ctx context.Context, submission model.Submission) (int, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	score := submission.Score
+	if score == 0 {
+		score = r.scores.Score(submission.Difficulty)
+	}
+
+	repaired := false
+	err := r.WithTransaction(ctx, func(ctx context.Context) error {
+		repaired = false
+		existing, err := r.submissionFirstSuccessCollection.CountDocuments(ctx, bson.M{
+			"userId":    submission.UserID,
+			"problemId": submission.ProblemID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to count first successes: %w", err)
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		_, err = r.submissionFirstSuccessCollection.InsertOne(ctx, model.ProblemDone{
+			ID:           primitive.NewObjectID(),
+			SubmissionID: submission.ID.Hex(),
+			ProblemID:    submission.ProblemID,
+			UserID:       submission.UserID,
+			Title:        submission.Title,
+			Language:     submission.Language,
+			Difficulty:   submission.Difficulty,
+			SubmittedAt:  submission.SubmittedAt,
+			Country:      submission.Country,
+			Score:        score,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert into submissionsfirstsuccess: %w", err)
+		}
+		if score != submission.Score {
+			_, err = r.submissionsCollection.UpdateOne(ctx, bson.M{"_id": submission.ID}, bson.M{"$set": bson.M{"score": score}})
+			if err != nil {
+				return fmt.Errorf("failed to update submission score: %w", err)
+			}
+		}
+		repaired = true
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to repair first success of submission %s: %w", submission.ID.Hex(), dbError(err))
+	}
+	if !repaired {
+		return 0, nil
+	}
+	return score, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	zap_betterstack "xcode/logger"
+
+	redisboard "github.com/lijuuu/RedisBoard"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newTestRepository returns a repository over a fresh single-node MongoDB replica set, so transactions behave as in
+// production, and a fresh Redis for its board, with the indexes in place. It skips when Docker is unavailable.
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("starts MongoDB and Redis containers")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	mongoContainer, err := testcontainers.Run(ctx, "mongo:7",
+		testcontainers.WithCmd("--replSet", "rs0", "--bind_ip_all"),
+		testcontainers.WithExposedPorts("27017/tcp"),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("27017/tcp")),
+	)
+	testcontainers.CleanupContainer(t, mongoContainer)
+	if err != nil {
+		t.Fatalf("start mongo: %v", err)
+	}
+	code, _, err := mongoContainer.Exec(ctx, []string{"mongosh", "--quiet", "--eval",
+		"rs.initiate(); while (!db.hello().isWritablePrimary) { sleep(100) }"})
+	if err != nil || code != 0 {
+		t.Fatalf("initiate replica set: exit %d, %v", code, err)
+	}
+	mongoEndpoint, err := mongoContainer.PortEndpoint(ctx, "27017/tcp", "mongodb")
+	if err != nil {
+		t.Fatalf("mongo endpoint: %v", err)
+	}
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoEndpoint+"/?directConnection=true"))
+	if err != nil {
+		t.Fatalf("connect mongo: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	redisContainer, err := testcontainers.Run(ctx, "redis:7",
+		testcontainers.WithExposedPorts("6379/tcp"),
+		testcontainers.WithWaitStrategy(wait.ForLog("Ready to accept connections")),
+	)
+	testcontainers.CleanupContainer(t, redisContainer)
+	if err != nil {
+		t.Fatalf("start redis: %v", err)
+	}
+	redisAddr, err := redisContainer.PortEndpoint(ctx, "6379/tcp", "")
+	if err != nil {
+		t.Fatalf("redis endpoint: %v", err)
+	}
+	lb, err := redisboard.New(redisboard.Config{Namespace: "test", FloatScores: true, RedisAddr: redisAddr})
+	if err != nil {
+		t.Fatalf("connect redis: %v", err)
+	}
+	t.Cleanup(func() { lb.Close() })
+
+	logger := zap_betterstack.NewBetterStackLogStreamer("", "test", "", zap.NewNop(), zap_betterstack.Options{
+		Level: zap.NewAtomicLevelAt(zapcore.FatalLevel),
+	})
+	repo := NewRepository(client, lb, Options{
+		Timeouts: Timeouts{Read: 10 * time.Second, Write: 10 * time.Second, Aggregate: 10 * time.Second},
+	}, logger)
+	if _, err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+	return repo
+}
+
+// countDocuments fails the test when the count cannot be taken
+func countDocuments(t *testing.T, collection *mongo.Collection, filter any) int64 {
+	t.Helper()
+	count, err := collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("count %s: %v", collection.Name(), err)
+	}
+	return count
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrContestAlreadyFinalized is returned by FinalizeContest when another run finalized the contest first
+var ErrContestAlreadyFinalized = errors.New("contest already finalized")
+
+func contestObjectID(contestID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(contestID)
+	if err != nil {
+		return primitive.NilObjectID, customerrors.Validation("invalid contest id %q", contestID)
+	}
+	return id, nil
+}
+
+// CreateContest stores a new contest and returns it with its ID
+func (r *Repository) CreateContest(ctx context.Context, contest model.Contest) (*model.Contest, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	contest.ID = primitive.NewObjectID()
+	contest.FinalizedAt = nil
+	if _, err := r.contestsCollection.InsertOne(ctx, contest); err != nil {
+		return nil, fmt.Errorf("failed to create contest: %w", dbError(err))
+	}
+	return &contest, nil
+}
+
+// GetContest returns a customerrors.NotFound error when the ID is unknown
+func (r *Repository) GetContest(ctx context.Context, contestID string) (*model.Contest, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := contestObjectID(contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var contest model.Contest
+	if err := r.contestsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&contest); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("contest %s", contestID)
+		}
+		return nil, fmt.Errorf("failed to fetch contest %s: %w", contestID, dbError(err))
+	}
+	return &contest, nil
+}
+
+// GetContestsToFinalize returns the contests that ended before endedBefore and are not finalized yet
+func (r *Repository) GetContestsToFinalize(ctx context.Context, endedBefore time.Time) ([]model.Contest, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.contestsCollection.Find(ctx,
+		bson.M{"finalizedAt": nil, "endsAt": bson.M{"$lt": endedBefore}},
+		options.Find().SetSort(bson.M{"endsAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contests to finalize: %w", dbError(err))
+	}
+	contests := []model.Contest{}
+	if err := cursor.All(ctx, &contests); err != nil {
+		return nil, fmt.Errorf("failed to decode contests to finalize: %w", dbError(err))
+	}
+	return contests, nil
+}
+
+// RegisterForContest stores registration unless the user is already registered, in which case the existing
+// registration is returned with created false
+func (r *Repository) RegisterForContest(ctx context.Context, registration model.ContestRegistration) (*model.ContestRegistration, bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	registration.ID = primitive.NewObjectID()
+
+	var stored model.ContestRegistration
+	err := r.contestRegistrationsCollection.FindOneAndUpdate(ctx,
+		bson.M{"contestId": registration.ContestID, "userId": registration.UserID},
+		bson.M{"$setOnInsert": registration},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&stored)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to register user %s for contest %s: %w", registration.UserID, registration.ContestID, dbError(err))
+	}
+	return &stored, stored.ID == registration.ID, nil
+}
+
+// GetContestRegistration returns a customerrors.NotFound error when the user is not registered
+func (r *Repository) GetContestRegistration(ctx context.Context, contestID, userID string) (*model.ContestRegistration, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var registration model.ContestRegistration
+	err := r.contestRegistrationsCollection.FindOne(ctx, bson.M{"contestId": contestID, "userId": userID}).Decode(&registration)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.NotFound("registration of user %s for contest %s", userID, contestID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contest registration: %w", dbError(err))
+	}
+	return &registration, nil
+}
+
+// GetContestRegistrations returns every registration of a contest
+func (r *Repository) GetContestRegistrations(ctx context.Context, contestID string) ([]model.ContestRegistration, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	cursor, err := r.contestRegistrationsCollection.Find(ctx, bson.M{"contestId": contestID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registrations of contest %s: %w", contestID, dbError(err))
+	}
+	registrations := []model.ContestRegistration{}
+	if err := cursor.All(ctx, &registrations); err != nil {
+		return nil, fmt.Errorf("failed to decode registrations of contest %s: %w", contestID, dbError(err))
+	}
+	return registrations, nil
+}
+
+// GetContestSubmissions returns the valid submissions to problemIDs made in [from, to), oldest first
+func (r *Repository) GetContestSubmissions(ctx context.Context, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	filter := bson.M{
+		"problemId":   bson.M{"$in": problemIDs},
+		"submittedAt": bson.M{"$gte": from, "$lt": to},
+		"invalidated": bson.M{"$ne": true},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"userCode": 0, "output": 0})
+	cursor, err := r.submissionsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contest submissions: %w", dbError(err))
+	}
+	submissions := []model.Submission{}
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode contest submissions: %w", dbError(err))
+	}
+	return submissions, nil
+}
+
+// GetUserRatings returns the ratings of those of userIDs who have one
+func (r *Repository) GetUserRatings(ctx context.Context, userIDs []string) (map[string]model.UserRating, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.userRatingsCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user ratings: %w", dbError(err))
+	}
+	var ratings []model.UserRating
+	if err := cursor.All(ctx, &ratings); err != nil {
+		return nil, fmt.Errorf("failed to decode user ratings: %w", dbError(err))
+	}
+	byUser := make(map[string]model.UserRating, len(ratings))
+	for _, rating := range ratings {
+		byUser[rating.UserID] = rating
+	}
+	return byUser, nil
+}
+
+// FinalizeContest marks the contest finalized, stores its official standings and, for every standing with a
+// RatingAfter, sets the user's rating, all in one transaction. It returns ErrContestAlreadyFinalized when the
+// contest was finalized before, leaving everything as it was.
+func (r *Repository) FinalizeContest(ctx context.Context, contestID string, standings []model.ContestStanding, rated bool) (*model.Contest, error) {
+	id, err := contestObjectID(contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var finalized model.Contest
+	err = r.WithTransaction(ctx, func(ctx context.Context) error {
+		ctx, cancel := r.writeContext(ctx)
+		defer cancel()
+
+		now := time.Now()
+		err := r.contestsCollection.FindOneAndUpdate(ctx,
+			bson.M{"_id": id, "finalizedAt": nil},
+			bson.M{"$set": bson.M{"finalizedAt": now}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&finalized)
+		if err == mongo.ErrNoDocuments {
+			return ErrContestAlreadyFinalized
+		}
+		if err != nil {
+			return fmt.Errorf("failed to finalize contest %s: %w", contestID, dbError(err))
+		}
+
+		if len(standings) == 0 {
+			return nil
+		}
+		documents := make([]any, len(standings))
+		for i, standing := range standings {
+			documents[i] = standing
+		}
+		if _, err := r.contestStandingsCollection.InsertMany(ctx, documents); err != nil {
+			return fmt.Errorf("failed to store standings of contest %s: %w", contestID, dbError(err))
+		}
+
+		if !rated {
+			return nil
+		}
+		writes := make([]mongo.WriteModel, 0, len(standings))
+		for _, standing := range standings {
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": standing.UserID}).
+				SetUpdate(bson.M{"$set": bson.M{"rating": standing.RatingAfter, "updatedAt": now}, "$inc": bson.M{"contests": 1}}).
+				SetUpsert(true))
+		}
+		if _, err := r.userRatingsCollection.BulkWrite(ctx, writes); err != nil {
+			return fmt.Errorf("failed to apply ratings of contest %s: %w", contestID, dbError(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &finalized, nil
+}
+
+// GetContestStandings returns a page of the official standings of one division, best first, and the division's
+// number of participants
+func (r *Repository) GetContestStandings(ctx context.Context, contestID, division string, skip, limit int64) ([]model.ContestStanding, int64, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{"contestId": contestID, "division": division}
+	total, err := r.contestStandingsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count standings of contest %s: %w", contestID, dbError(err))
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "userId", Value: 1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.contestStandingsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch standings of contest %s: %w", contestID, dbError(err))
+	}
+	standings := []model.ContestStanding{}
+	if err := cursor.All(ctx, &standings); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode standings of contest %s: %w", contestID, dbError(err))
+	}
+	return standings, total, nil
+}
+
+// StartVirtualParticipation stores participation unless the user already has one for the contest, in which case the
+// existing one is returned with created false
+func (r *Repository) StartVirtualParticipation(ctx context.Context, participation model.VirtualParticipation) (*model.VirtualParticipation, bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	participation.ID = primitive.NewObjectID()
+
+	var stored model.VirtualParticipation
+	err := r.virtualParticipationsCollection.FindOneAndUpdate(ctx,
+		bson.M{"contestId": participation.ContestID, "userId": participation.UserID},
+		bson.M{"$setOnInsert": participation},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&stored)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to start virtual participation of user %s in contest %s: %w", participation.UserID, participation.ContestID, dbError(err))
+	}
+	return &stored, stored.ID == participation.ID, nil
+}
+
+// GetVirtualParticipation returns a customerrors.NotFound error when the user has not started one
+func (r *Repository) GetVirtualParticipation(ctx context.Context, contestID, userID string) (*model.VirtualParticipation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var participation model.VirtualParticipation
+	err := r.virtualParticipationsCollection.FindOne(ctx, bson.M{"contestId": contestID, "userId": userID}).Decode(&participation)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.NotFound("virtual participation of user %s in contest %s", userID, contestID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch virtual participation: %w", dbError(err))
+	}
+	return &participation, nil
+}
+
+// GetUserContestSubmissions returns the valid submissions of one user to problemIDs made in [from, to), oldest first
+func (r *Repository) GetUserContestSubmissions(ctx context.Context, userID string, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{
+		"userId":      userID,
+		"problemId":   bson.M{"$in": problemIDs},
+		"submittedAt": bson.M{"$gte": from, "$lt": to},
+		"invalidated": bson.M{"$ne": true},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"userCode": 0, "output": 0})
+	cursor, err := r.submissionsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch submissions of user %s: %w", userID, dbError(err))
+	}
+	submissions := []model.Submission{}
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode submissions of user %s: %w", userID, dbError(err))
+	}
+	return submissions, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *Repository) SaveDeadLetter(ctx context.Context, letter model.DeadLetter) (primitive.ObjectID, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	result, err := r.deadLettersCollection.InsertOne(ctx, letter)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	id, _ := result.InsertedID.(primitive.ObjectID)
+	return id, nil
+}
+
+// ListDeadLetters returns dead letters newest first, optionally only those of one subject, plus the total count
+func (r *Repository) ListDeadLetters(ctx context.Context, subject string, skip, limit int64) ([]model.DeadLetter, int64, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	filter := bson.M{}
+	if subject != "" {
+		filter["subject"] = subject
+	}
+	total, err := r.deadLettersCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.M{"failedAt": -1}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.deadLettersCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch dead letters: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	letters := []model.DeadLetter{}
+	if err := cursor.All(ctx, &letters); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode dead letters: %w", err)
+	}
+	return letters, total, nil
+}
+
+// GetDeadLetter returns mongo.ErrNoDocuments (wrapped) when the ID is unknown
+func (r *Repository) GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dead letter id %s: %w", id, err)
+	}
+	var letter model.DeadLetter
+	if err := r.deadLettersCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&letter); err != nil {
+		return nil, fmt.Errorf("failed to fetch dead letter %s: %w", id, err)
+	}
+	return &letter, nil
+}
+
+// MarkDeadLetterReplayed bumps the replay count; the letter is kept so a replay that fails again can be compared
+func (r *Repository) MarkDeadLetterReplayed(ctx context.Context, id primitive.ObjectID, at time.Time) (int, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	var letter model.DeadLetter
+	err := r.deadLettersCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"replayCount": 1}, "$set": bson.M{"lastReplayedAt": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&letter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark dead letter %s replayed: %w", id.Hex(), err)
+	}
+	return letter.ReplayCount, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetProblemSolveStats aggregates the submissions of every problem into model.ProblemSolveStats. Invalidated
+// submissions are left out. A solver's attempts and time to accept count from their first submission to their first
+// accepted one.
+func (r *Repository) GetProblemSolveStats(ctx context.Context) ([]model.ProblemSolveStats, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	accepted := bson.M{"$eq": bson.A{"$status", "SUCCESS"}}
+	solved := bson.M{"$ne": bson.A{"$acceptedAt", nil}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"invalidated": bson.M{"$ne": true}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"problemId": "$problemId", "userId": "$userId"},
+			"submissions": bson.M{"$sum": 1},
+			"accepted":    bson.M{"$sum": bson.M{"$cond": bson.A{accepted, 1, 0}}},
+			"firstAt":     bson.M{"$first": "$submittedAt"},
+			"acceptedAt":  bson.M{"$min": bson.M{"$cond": bson.A{accepted, "$submittedAt", nil}}},
+			"statuses":    bson.M{"$push": "$status"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$_id.problemId",
+			"submissions": bson.M{"$sum": "$submissions"},
+			"accepted":    bson.M{"$sum": "$accepted"},
+			"attempters":  bson.M{"$sum": 1},
+			"solvers":     bson.M{"$sum": bson.M{"$cond": bson.A{solved, 1, 0}}},
+			"attemptsToAccept": bson.M{"$push": bson.M{"$cond": bson.A{solved,
+				bson.M{"$add": bson.A{bson.M{"$indexOfArray": bson.A{"$statuses", "SUCCESS"}}, 1}},
+				"$$REMOVE"}}},
+			"secondsToAccept": bson.M{"$push": bson.M{"$cond": bson.A{solved,
+				bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{"$acceptedAt", "$firstAt"}}, 1000}},
+				"$$REMOVE"}}},
+		}}},
+	}
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate solve statistics: %w", dbError(err))
+	}
+	var stats []model.ProblemSolveStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode solve statistics: %w", dbError(err))
+	}
+	return stats, nil
+}
+
+// GetProblemDifficulties returns the authored and empirical difficulty of every problem that is not deleted
+func (r *Repository) GetProblemDifficulties(ctx context.Context) ([]model.ProblemDifficulty, error) {
+	return r.findProblemDifficulties(ctx, bson.M{"deleted_at": nil})
+}
+
+// GetMislabeledProblems returns the problems whose trusted empirical difficulty disagrees with the authored one
+func (r *Repository) GetMislabeledProblems(ctx context.Context) ([]model.ProblemDifficulty, error) {
+	return r.findProblemDifficulties(ctx, bson.M{"deleted_at": nil, "empirical_difficulty.mislabeled": true})
+}
+
+// GetProblemDifficulty returns a customerrors.NotFound error when the problem is unknown or deleted
+func (r *Repository) GetProblemDifficulty(ctx context.Context, problemID string) (*model.ProblemDifficulty, error) {
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return nil, err
+	}
+	difficulties, err := r.findProblemDifficulties(ctx, bson.M{"_id": id, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	if len(difficulties) == 0 {
+		return nil, customerrors.NotFound("problem %s", problemID)
+	}
+	return &difficulties[0], nil
+}
+
+func (r *Repository) findProblemDifficulties(ctx context.Context, filter bson.M) ([]model.ProblemDifficulty, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.problemsCollection.Find(ctx, filter, options.Find().
+		SetProjection(bson.M{"title": 1, "difficulty": 1, "empirical_difficulty": 1}).
+		SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch problem difficulties: %w", dbError(err))
+	}
+	var problems []model.Problem
+	if err := cursor.All(ctx, &problems); err != nil {
+		return nil, fmt.Errorf("failed to decode problem difficulties: %w", dbError(err))
+	}
+	difficulties := make([]model.ProblemDifficulty, len(problems))
+	for i, problem := range problems {
+		difficulties[i] = model.ProblemDifficulty{
+			ProblemID:  problem.ID.Hex(),
+			Title:      problem.Title,
+			Difficulty: problem.Difficulty,
+			Empirical:  problem.EmpiricalDifficulty,
+		}
+	}
+	return difficulties, nil
+}
+
+// SetEmpiricalDifficulties stores the empirical difficulty of each problem by ID. It is derived data, so the
+// problems' updated_at is left alone.
+func (r *Repository) SetEmpiricalDifficulties(ctx context.Context, difficulties map[string]model.EmpiricalDifficulty) error {
+	if len(difficulties) == 0 {
+		return nil
+	}
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	writes := make([]mongo.WriteModel, 0, len(difficulties))
+	for problemID, difficulty := range difficulties {
+		id, err := problemObjectID(problemID)
+		if err != nil {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"empirical_difficulty": difficulty}}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	if _, err := r.problemsCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to store empirical difficulties: %w", dbError(err))
+	}
+	return nil
+}
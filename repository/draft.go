@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveCodeDrafts upserts the drafts, skipping any that are older than what is already stored
+func (r *Repository) SaveCodeDrafts(ctx context.Context, drafts []model.CodeDraft) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if len(drafts) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(drafts))
+	for _, draft := range drafts {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": draft.ID, "updatedAt": bson.M{"$lt": draft.UpdatedAt}}).
+			SetReplacement(draft).
+			SetUpsert(true))
+	}
+	_, err := r.codeDraftsCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	// a newer stored draft makes the filter miss and the upsert collide on _id, which is the outcome we want
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to save code drafts: %w", err)
+	}
+	return nil
+}
+
+// GetCodeDraft returns mongo.ErrNoDocuments when there is no stored draft
+func (r *Repository) GetCodeDraft(ctx context.Context, id string) (*model.CodeDraft, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var draft model.CodeDraft
+	if err := r.codeDraftsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&draft); err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
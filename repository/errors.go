@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"fmt"
+
+	"xcode/customerrors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrProblemNotFound is returned by GetProblem for a problem that does not exist or was soft deleted. It wraps
+// customerrors.ErrNotFound, so handlers answer codes.NotFound without checking for it.
+var ErrProblemNotFound = fmt.Errorf("%w: problem", customerrors.ErrNotFound)
+
+// problemNotFound reports that the problem with problemID does not exist or was deleted
+func problemNotFound(problemID string) error {
+	return fmt.Errorf("%w %s", ErrProblemNotFound, problemID)
+}
+
+// problemObjectID parses a problem ID, reporting a malformed one as a validation error rather than a driver error
+func problemObjectID(problemID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(problemID)
+	if err != nil {
+		return primitive.NilObjectID, customerrors.Validation("invalid problem id %q", problemID)
+	}
+	return id, nil
+}
+
+// dbError classifies a MongoDB failure: duplicate keys are conflicts, timeouts and network errors mean the database
+// is unavailable, anything else stays an internal error
+func dbError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case mongo.IsDuplicateKeyError(err):
+		return customerrors.Conflict("a record with the same key already exists")
+	case mongo.IsTimeout(err), mongo.IsNetworkError(err):
+		return customerrors.Downstream("mongodb", err)
+	}
+	return err
+}
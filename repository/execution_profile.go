@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetExecutionProfile stores the profile of its language, replacing the previous one, which is returned (nil when the
+// language had none)
+func (r *Repository) SetExecutionProfile(ctx context.Context, profile model.ExecutionProfile) (*model.ExecutionProfile, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	var previous model.ExecutionProfile
+	err := r.executionProfilesCollection.FindOneAndReplace(ctx,
+		bson.M{"_id": profile.Language},
+		profile,
+		options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set execution profile of %s: %w", profile.Language, dbError(err))
+	}
+	return &previous, nil
+}
+
+// GetExecutionProfiles returns the profile of every language that has one, by language
+func (r *Repository) GetExecutionProfiles(ctx context.Context) ([]model.ExecutionProfile, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.executionProfilesCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch execution profiles: %w", dbError(err))
+	}
+	profiles := []model.ExecutionProfile{}
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode execution profiles: %w", dbError(err))
+	}
+	return profiles, nil
+}
+
+// DeleteExecutionProfile removes the profile of a language and returns it, nil when there was none
+func (r *Repository) DeleteExecutionProfile(ctx context.Context, language string) (*model.ExecutionProfile, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	var deleted model.ExecutionProfile
+	err := r.executionProfilesCollection.FindOneAndDelete(ctx, bson.M{"_id": language}).Decode(&deleted)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete execution profile of %s: %w", language, dbError(err))
+	}
+	return &deleted, nil
+}
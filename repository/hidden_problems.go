@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetHiddenProblemIDs returns the problems userID hid, none when they never hid one
+func (r *Repository) GetHiddenProblemIDs(ctx context.Context, userID string) ([]string, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var hidden model.HiddenProblems
+	err := r.hiddenProblemsCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&hidden)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hidden problems of %s: %w", userID, dbError(err))
+	}
+	return hidden.ProblemIDs, nil
+}
+
+// HideProblem adds problemID to userID's hidden problems and reports whether it was not there yet
+func (r *Repository) HideProblem(ctx context.Context, userID, problemID string) (bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.hiddenProblemsCollection.UpdateOne(ctx,
+		bson.M{"_id": userID, "problemIds": bson.M{"$ne": problemID}},
+		bson.M{
+			"$push": bson.M{"problemIds": problemID},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+		options.Update().SetUpsert(true),
+	)
+	// an already hidden problem makes the filter miss and the upsert collide on _id
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to hide problem %s for %s: %w", problemID, userID, dbError(err))
+	}
+	return true, nil
+}
+
+// UnhideProblem removes problemID from userID's hidden problems and reports whether it was there
+func (r *Repository) UnhideProblem(ctx context.Context, userID, problemID string) (bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	result, err := r.hiddenProblemsCollection.UpdateOne(ctx,
+		bson.M{"_id": userID, "problemIds": problemID},
+		bson.M{
+			"$pull": bson.M{"problemIds": problemID},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to unhide problem %s for %s: %w", problemID, userID, dbError(err))
+	}
+	return result.ModifiedCount > 0, nil
+}
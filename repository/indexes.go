@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// collectionIndexes are the indexes one collection needs
+type collectionIndexes struct {
+	collection *mongo.Collection
+	models     []mongo.IndexModel
+}
+
+// indexDefinitions lists an index for every filter and sort the repository runs. deleted_at comes first on problem
+// indexes because every problem read excludes soft deleted problems; it matches both null and missing.
+func (r *Repository) indexDefinitions() []collectionIndexes {
+	return []collectionIndexes{
+		{r.problemsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "title", Value: 1}, {Key: "deleted_at", Value: 1}}}, // duplicate title checks
+			{Keys: bson.D{{Key: "slug", Value: 1}, {Key: "deleted_at", Value: 1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}, {Key: "difficulty", Value: 1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}, {Key: "tags", Value: 1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}, {Key: "visible", Value: 1}, {Key: "difficulty", Value: 1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}, {Key: "tier", Value: 1}}}, // premium problem IDs
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}, {Key: "empirical_difficulty.mislabeled", Value: 1}}},
+			{Keys: bson.D{{Key: "maintainers", Value: 1}, {Key: "deleted_at", Value: 1}, {Key: "created_at", Value: -1}}},
+		}},
+		// every listing is scoped by user or problem and sorted by submittedAt, so those prefixes keep filtered
+		// pages off a collection scan
+		{r.submissionsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "submittedAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "problemId", Value: 1}, {Key: "submittedAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "status", Value: 1}, {Key: "language", Value: 1}, {Key: "submittedAt", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "problemId", Value: 1}, {Key: "status", Value: 1}}}, // first success checks
+			{Keys: bson.D{{Key: "submittedAt", Value: 1}}},
+		}},
+		// a user solves a problem first only once, however many of their submissions race to be it
+		{r.submissionFirstSuccessCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "problemId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "country", Value: 1}, {Key: "userId", Value: 1}}}, // entity leaderboards
+			{Keys: bson.D{{Key: "problemId", Value: 1}}},
+			{Keys: bson.D{{Key: "submissionId", Value: 1}}},
+			{Keys: bson.D{{Key: "submittedAt", Value: 1}}}, // seasonal leaderboards
+		}},
+		// lets MongoDB purge expired share links
+		{r.sharedSubmissionsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		}},
+		// pending updates have no appliedAt and are kept; applied ones expire
+		{r.leaderboardUpdatesCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "appliedAt", Value: 1}, {Key: "createdAt", Value: 1}}},
+			{Keys: bson.D{{Key: "appliedAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(appliedLeaderboardUpdateTTL.Seconds()))},
+		}},
+		// the relay reads pending events oldest first; sent ones expire
+		{r.outboxCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "sentAt", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "sentAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(sentOutboxEventTTL.Seconds()))},
+		}},
+		// a problem's active job is looked up before queueing another; finished ones expire
+		{r.validationJobsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "problemId", Value: 1}, {Key: "status", Value: 1}, {Key: "updatedAt", Value: 1}}},
+			{Keys: bson.D{{Key: "finishedAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(finishedValidationJobTTL.Seconds()))},
+		}},
+		// like validation jobs, one active rejudge per problem; finished ones expire
+		{r.rejudgeJobsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "problemId", Value: 1}, {Key: "status", Value: 1}, {Key: "updatedAt", Value: 1}}},
+			{Keys: bson.D{{Key: "finishedAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(finishedRejudgeJobTTL.Seconds()))},
+		}},
+		// the finalization job looks for ended contests that are not finalized
+		{r.contestsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "finalizedAt", Value: 1}, {Key: "endsAt", Value: 1}}},
+		}},
+		// one registration per user and contest, which also makes registering idempotent
+		{r.contestRegistrationsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "contestId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		}},
+		{r.contestStandingsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "contestId", Value: 1}, {Key: "division", Value: 1}, {Key: "rank", Value: 1}, {Key: "userId", Value: 1}}},
+		}},
+		// one virtual participation per user and contest
+		{r.virtualParticipationsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "contestId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		}},
+		// organizations are looked up by slug, which must be unique; members are listed per organization
+		{r.organizationsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "name", Value: 1}}},
+		}},
+		{r.organizationMembersCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "organizationId", Value: 1}}},
+		}},
+		// one enrollment per student and assignment
+		{r.assignmentEnrollmentsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "assignmentId", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		}},
+		// the gallery's two orders; a submission is posted at most once
+		{r.solutionsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "problemId", Value: 1}, {Key: "votes", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "problemId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "submissionId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		}},
+		// admins page through reports by status, optionally of one problem
+		{r.problemReportsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "problemId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+		}},
+		{r.apiKeysCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "revokedAt", Value: 1}, {Key: "createdAt", Value: -1}}},
+		}},
+		{r.webhooksCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "eventTypes", Value: 1}, {Key: "deletedAt", Value: 1}}},
+		}},
+		// the dispatcher reads due pending deliveries; the log of a webhook is read newest first and expires
+		{r.webhookDeliveriesCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(webhookDeliveryTTL.Seconds()))},
+		}},
+		{r.leaderboardSeasonsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "period", Value: 1}, {Key: "seasonStart", Value: 1}}},
+		}},
+		{r.leaderboardSnapshotsCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "scope", Value: 1}, {Key: "date", Value: 1}}},
+		}},
+		{r.deadLettersCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "failedAt", Value: -1}}},
+			{Keys: bson.D{{Key: "subject", Value: 1}, {Key: "failedAt", Value: -1}}},
+		}},
+		{r.auditLogCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "targetId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		}},
+	}
+}
+
+// EnsureIndexes creates every index in indexDefinitions. It is idempotent, MongoDB skips indexes that already exist,
+// so it runs on every startup. A failing collection does not stop the others; the failures are joined.
+func (r *Repository) EnsureIndexes(ctx context.Context) ([]model.CollectionIndexes, error) {
+	var (
+		ensured []model.CollectionIndexes
+		errs    []error
+	)
+	for _, def := range r.indexDefinitions() {
+		if err := dropIndexesMadeUnique(ctx, def); err != nil {
+			errs = append(errs, fmt.Errorf("failed to replace indexes made unique on %s: %w", def.collection.Name(), err))
+			continue
+		}
+		names, err := def.collection.Indexes().CreateMany(ctx, def.models)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create indexes on %s: %w", def.collection.Name(), err))
+			continue
+		}
+		ensured = append(ensured, model.CollectionIndexes{
+			Database:   def.collection.Database().Name(),
+			Collection: def.collection.Name(),
+			Indexes:    names,
+		})
+	}
+	return ensured, errors.Join(errs...)
+}
+
+// dropIndexesMadeUnique drops the indexes def now wants unique that exist without the constraint; MongoDB refuses
+// to create an index under the name of one with other options. Creating the unique index fails while duplicates
+// remain, which the first success consistency check reports.
+func dropIndexesMadeUnique(ctx context.Context, def collectionIndexes) error {
+	unique := map[string]bool{}
+	for _, index := range def.models {
+		if index.Options != nil && index.Options.Unique != nil && *index.Options.Unique {
+			unique[indexName(index)] = true
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	cursor, err := def.collection.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	var existing []struct {
+		Name   string `bson:"name"`
+		Unique bool   `bson:"unique"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return err
+	}
+	for _, index := range existing {
+		if unique[index.Name] && !index.Unique {
+			if _, err := def.collection.Indexes().DropOne(ctx, index.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// indexName is the name MongoDB gives an index without an explicit one, e.g. userId_1_problemId_1
+func indexName(index mongo.IndexModel) string {
+	if index.Options != nil && index.Options.Name != nil {
+		return *index.Options.Name
+	}
+	keys, _ := index.Keys.(bson.D)
+	parts := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
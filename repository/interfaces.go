@@ -0,0 +1,271 @@
+package repository
+
+//go:generate mockgen -source=interfaces.go -destination=mocks/repository.go -package=mocks
+
+import (
+	"context"
+	"time"
+
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	redisboard "github.com/lijuuu/RedisBoard"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProblemRepository is everything the service layer reads and writes in MongoDB. The service depends on it rather
+// than on Repository, so tests can hand it the generated mocks.MockProblemRepository. The sub-interfaces let code
+// that only needs one area ask for just that.
+type ProblemRepository interface {
+	ProblemStore
+	SubmissionStore
+	LeaderboardStore
+	AdminStore
+	OutboxStore
+	ValidationJobStore
+	RejudgeStore
+	ContestStore
+	SolutionStore
+	ProblemReportStore
+	APIKeyStore
+	WebhookStore
+	AssignmentStore
+	OrganizationStore
+	ConsistencyStore
+	ExecutionProfileStore
+}
+
+// ProblemStore holds problems, their test cases and language supports
+type ProblemStore interface {
+	CreateProblem(ctx context.Context, req *pb.CreateProblemRequest) (*pb.CreateProblemResponse, error)
+	UpdateProblem(ctx context.Context, req *pb.UpdateProblemRequest) (*pb.UpdateProblemResponse, error)
+	DeleteProblem(ctx context.Context, req *pb.DeleteProblemRequest) (*pb.DeleteProblemResponse, error)
+	GetProblem(ctx context.Context, req *pb.GetProblemRequest) (*model.Problem, error)
+	ListProblems(ctx context.Context, req *pb.ListProblemsRequest, excludeIDs []string) (*pb.ListProblemsResponse, error)
+	GetProblemByIDSlug(ctx context.Context, req *pb.GetProblemByIdSlugRequest) (*pb.GetProblemByIdSlugResponse, error)
+	GetProblemByIDList(ctx context.Context, req *pb.GetProblemMetadataListRequest) (*pb.GetProblemMetadataListResponse, error)
+	GetBulkProblemMetadata(ctx context.Context, req *pb.GetBulkProblemMetadataRequest) (*pb.GetBulkProblemMetadataResponse, error)
+	GetProblemsByIDs(ctx context.Context, problemIDs []string) ([]model.Problem, error)
+	GetRandomProblem(ctx context.Context, f model.RandomProblemFilter) (*model.Problem, error)
+	GetProblemDifficulty(ctx context.Context, problemID string) (*model.ProblemDifficulty, error)
+	GetProblemDifficulties(ctx context.Context) ([]model.ProblemDifficulty, error)
+	GetMislabeledProblems(ctx context.Context) ([]model.ProblemDifficulty, error)
+	SetEmpiricalDifficulties(ctx context.Context, difficulties map[string]model.EmpiricalDifficulty) error
+
+	AddTestCases(ctx context.Context, req *pb.AddTestCasesRequest) (*pb.AddTestCasesResponse, error)
+	DeleteTestCase(ctx context.Context, req *pb.DeleteTestCaseRequest) (*pb.DeleteTestCaseResponse, error)
+
+	AddLanguageSupport(ctx context.Context, req *pb.AddLanguageSupportRequest) (*pb.AddLanguageSupportResponse, error)
+	UpdateLanguageSupport(ctx context.Context, req *pb.UpdateLanguageSupportRequest) (*pb.UpdateLanguageSupportResponse, error)
+	RemoveLanguageSupport(ctx context.Context, req *pb.RemoveLanguageSupportRequest) (*pb.RemoveLanguageSupportResponse, error)
+	GetLanguageSupports(ctx context.Context, req *pb.GetLanguageSupportsRequest) (*pb.GetLanguageSupportsResponse, error)
+
+	BasicValidationByProblemID(ctx context.Context, req *pb.FullValidationByProblemIDRequest) (*pb.FullValidationByProblemIDResponse, model.Problem, error)
+	ToggleProblemValidaition(ctx context.Context, problemID string, status bool) bool
+	SetValidationHash(ctx context.Context, problemID, language, hash string) error
+	SetFunctionSignature(ctx context.Context, problemID string, signature *model.FunctionSignature) error
+	UpsertProblemTranslation(ctx context.Context, problemID, locale string, translation model.ProblemTranslation) (bool, error)
+	GetProblemTranslations(ctx context.Context, problemID string) (map[string]model.ProblemTranslation, error)
+	SetProblemAuthor(ctx context.Context, problemID, userID string) error
+	AddProblemMaintainer(ctx context.Context, problemID, userID string) ([]string, bool, error)
+	ListProblemsByMaintainer(ctx context.Context, userID string, page, pageSize int32) (*pb.ListProblemsResponse, error)
+	SetProblemTier(ctx context.Context, problemID, tier string) (string, error)
+	GetPremiumProblemIDs(ctx context.Context) ([]string, error)
+	GetHiddenProblemIDs(ctx context.Context, userID string) ([]string, error)
+	HideProblem(ctx context.Context, userID, problemID string) (bool, error)
+	UnhideProblem(ctx context.Context, userID, problemID string) (bool, error)
+}
+
+// SubmissionStore holds submissions, first successes and everything derived from a user's submission history
+type SubmissionStore interface {
+	PushSubmissionData(ctx context.Context, submission *model.Submission, status string, events func(submission model.Submission) ([]model.OutboxEvent, error)) error
+	GetSubmissionByID(ctx context.Context, submissionID string) (*model.Submission, error)
+	GetSubmissionsByOptionalProblemID(ctx context.Context, req *pb.GetSubmissionsRequest) (*pb.GetSubmissionsResponse, error)
+	ListSubmissionsCursor(ctx context.Context, f model.SubmissionFilter, cursorToken string, limit int64) ([]*pb.Submission, string, error)
+	GetBestSubmissions(ctx context.Context, userID string, byLanguage bool) ([]*pb.Submission, error)
+	GetFirstSolvers(ctx context.Context, problemIDs []string, challengeID string) ([]model.FirstSolver, error)
+	GetProblemStatuses(ctx context.Context, userID string, problemIDs []string) (map[string]string, error)
+	GetProblemSolveStats(ctx context.Context) ([]model.ProblemSolveStats, error)
+
+	SaveSharedSubmission(ctx context.Context, shared model.SharedSubmission) error
+	GetSharedSubmission(ctx context.Context, token string) (*model.SharedSubmission, error)
+	SaveCodeDrafts(ctx context.Context, drafts []model.CodeDraft) error
+	GetCodeDraft(ctx context.Context, id string) (*model.CodeDraft, error)
+
+	ProblemsDoneStatistics(ctx context.Context, userID string) (model.ProblemsDoneStatistics, error)
+	GetMonthlyContributionHistory(ctx context.Context, userID string, month, year int) (model.MonthlyActivityHeatmapProps, error)
+	GetUserLanguageStats(ctx context.Context, userID string) ([]model.LanguageStats, error)
+	GetDailySubmissionCounts(ctx context.Context, userID string, from, to time.Time) (map[string]int, error)
+}
+
+// LeaderboardStore computes standings from first successes and keeps the Redis boards and their snapshots in sync
+type LeaderboardStore interface {
+	SyncLeaderboardToRedis(ctx context.Context) error
+	SyncPeriodLeaderboardToRedis(ctx context.Context, lb *redisboard.Leaderboard, since time.Time) error
+	GetUsersChangedSinceMongo(ctx context.Context, since time.Time) ([]string, time.Time, error)
+	GetUserTotalsMongo(ctx context.Context, userIDs []string, since time.Time) ([]model.RankedUserScore, error)
+	GetInactiveUserTotalsMongo(ctx context.Context, inactiveSince, since time.Time) ([]model.InactiveUserScore, error)
+	RecalculateFirstSuccessScores(ctx context.Context, dryRun bool) (scanned int64, updated int64, err error)
+	UpdateUserEntityMongo(ctx context.Context, userID, entity string) (int64, error)
+	NormalizeEntitiesMongo(ctx context.Context, normalize func(country string) string, dryRun bool) (map[string]string, int64, error)
+	ApplyPendingLeaderboardUpdates(ctx context.Context, limit int64) (int, error)
+
+	GetLeaderboardPageMongo(ctx context.Context, entity string, skip, limit int64) ([]model.RankedUserScore, int64, error)
+	GetLeaderboardDataMongo(ctx context.Context, userID string) (*model.UserScore, error)
+	GetTopKGlobalMongo(ctx context.Context, k int) ([]model.UserScore, error)
+	GetTopKEntityMongo(ctx context.Context, entity string, k int) ([]model.UserScore, error)
+	GetUserRankMongo(ctx context.Context, userID string) (globalRank, entityRank int, err error)
+	GetUserStandingMongo(ctx context.Context, userID string) (standing model.RankedUserScore, entityRank int64, total int64, err error)
+	GetStandingsBetweenMongo(ctx context.Context, from, to time.Time, limit int) ([]model.RankedUserScore, error)
+	GetEntityStandingsMongo(ctx context.Context, perEntity int) (map[string][]model.RankedUserScore, error)
+	GetEntityStatsMongo(ctx context.Context, moversSince time.Time, moversPerEntity int) ([]model.EntityStats, error)
+
+	SaveLeaderboardSeasonSnapshot(ctx context.Context, snapshot model.LeaderboardSeasonSnapshot) error
+	SaveLeaderboardDailySnapshots(ctx context.Context, snapshots []model.LeaderboardDailySnapshot) error
+	GetLeaderboardDailySnapshots(ctx context.Context, scope string, from time.Time) ([]model.LeaderboardDailySnapshot, error)
+}
+
+// AdminStore backs moderation and operations: bans, invalidations, dead letters, the audit log, indexes, retention
+// and the dashboard totals
+type AdminStore interface {
+	BanUserFromLeaderboard(ctx context.Context, ban model.LeaderboardBan) error
+	IsLeaderboardBanned(ctx context.Context, userID string) (bool, error)
+	InvalidateSubmission(ctx context.Context, submissionID string) (*model.Submission, int, error)
+	SaveModerationAudit(ctx context.Context, audit model.ModerationAudit) error
+
+	SaveDeadLetter(ctx context.Context, letter model.DeadLetter) (primitive.ObjectID, error)
+	ListDeadLetters(ctx context.Context, subject string, skip, limit int64) ([]model.DeadLetter, int64, error)
+	GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error)
+	MarkDeadLetterReplayed(ctx context.Context, id primitive.ObjectID, at time.Time) (int, error)
+
+	SaveAuditEntry(ctx context.Context, entry model.AuditEntry) error
+	QueryAuditLog(ctx context.Context, f model.AuditLogFilter, skip, limit int64) ([]model.AuditEntry, int64, error)
+
+	EnsureIndexes(ctx context.Context) ([]model.CollectionIndexes, error)
+	PurgeDeletedProblems(ctx context.Context, deletedBefore time.Time, archive bool, limit int64) (int64, error)
+	GetServiceStatsMongo(ctx context.Context, since time.Time) (*model.ServiceStats, error)
+}
+
+// OutboxStore holds events waiting to be published. WithTransaction lets a change and the events announcing it be
+// written together.
+type OutboxStore interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	SaveOutboxEvents(ctx context.Context, events ...model.OutboxEvent) error
+	GetPendingOutboxEvents(ctx context.Context, limit int64) ([]model.OutboxEvent, error)
+	MarkOutboxEventSent(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	RecordOutboxEventFailure(ctx context.Context, id primitive.ObjectID, cause error) error
+}
+
+// ValidationJobStore tracks full validations running in the background and their progress per language
+type ValidationJobStore interface {
+	CreateValidationJob(ctx context.Context, job model.ValidationJob, staleBefore time.Time) (*model.ValidationJob, bool, error)
+	GetValidationJob(ctx context.Context, jobID string) (*model.ValidationJob, error)
+	StartValidationJob(ctx context.Context, id primitive.ObjectID, languages []string) error
+	RecordValidationJobResult(ctx context.Context, id primitive.ObjectID, result model.LanguageValidationResult) error
+	FinishValidationJob(ctx context.Context, id primitive.ObjectID, report model.ValidateProblemResponse) error
+}
+
+// RejudgeStore tracks rejudges running in the background and moves verdicts and first successes they change
+type RejudgeStore interface {
+	CreateRejudgeJob(ctx context.Context, job model.RejudgeJob, staleBefore time.Time) (*model.RejudgeJob, bool, error)
+	GetRejudgeJob(ctx context.Context, jobID string) (*model.RejudgeJob, error)
+	StartRejudgeJob(ctx context.Context, job model.RejudgeJob) (int64, error)
+	RecordRejudgeProgress(ctx context.Context, id primitive.ObjectID, processed, changed, errored int64) error
+	FinishRejudgeJob(ctx context.Context, id primitive.ObjectID, status, message string, usersRescored int64) error
+	GetRejudgeSubmissions(ctx context.Context, problemID string, since *time.Time, after primitive.ObjectID, limit int64) ([]model.Submission, error)
+	SetSubmissionStatus(ctx context.Context, id primitive.ObjectID, status string) error
+	ReconcileFirstSuccess(ctx context.Context, userID, problemID string) (bool, error)
+}
+
+// ContestStore holds scheduled contests, their registrations and official standings, and the users' contest ratings
+type ContestStore interface {
+	CreateContest(ctx context.Context, contest model.Contest) (*model.Contest, error)
+	GetContest(ctx context.Context, contestID string) (*model.Contest, error)
+	GetContestsToFinalize(ctx context.Context, endedBefore time.Time) ([]model.Contest, error)
+	RegisterForContest(ctx context.Context, registration model.ContestRegistration) (*model.ContestRegistration, bool, error)
+	GetContestRegistration(ctx context.Context, contestID, userID string) (*model.ContestRegistration, error)
+	GetContestRegistrations(ctx context.Context, contestID string) ([]model.ContestRegistration, error)
+	GetContestSubmissions(ctx context.Context, problemIDs []string, from, to time.Time) ([]model.Submission, error)
+	GetUserRatings(ctx context.Context, userIDs []string) (map[string]model.UserRating, error)
+	FinalizeContest(ctx context.Context, contestID string, standings []model.ContestStanding, rated bool) (*model.Contest, error)
+	GetContestStandings(ctx context.Context, contestID, division string, skip, limit int64) ([]model.ContestStanding, int64, error)
+	StartVirtualParticipation(ctx context.Context, participation model.VirtualParticipation) (*model.VirtualParticipation, bool, error)
+	GetVirtualParticipation(ctx context.Context, contestID, userID string) (*model.VirtualParticipation, error)
+	GetUserContestSubmissions(ctx context.Context, userID string, problemIDs []string, from, to time.Time) ([]model.Submission, error)
+}
+
+// SolutionStore holds the solutions users post in problem galleries and the votes on them
+type SolutionStore interface {
+	CreateSolution(ctx context.Context, solution model.Solution) (*model.Solution, error)
+	GetSolution(ctx context.Context, solutionID string) (*model.Solution, error)
+	ListSolutions(ctx context.Context, problemID, sort string, skip, limit int64) ([]model.Solution, int64, error)
+	VoteSolution(ctx context.Context, solutionID, userID string, value int) (*model.Solution, error)
+}
+
+// ProblemReportStore holds the issues users report with problems
+type ProblemReportStore interface {
+	CreateProblemReport(ctx context.Context, report model.ProblemReport) (*model.ProblemReport, error)
+	GetProblemReport(ctx context.Context, reportID string) (*model.ProblemReport, error)
+	ListProblemReports(ctx context.Context, f model.ProblemReportFilter, skip, limit int64) ([]model.ProblemReport, int64, error)
+	UpdateProblemReportStatus(ctx context.Context, reportID, fromStatus, status, note, handledBy string, fixedRevision *time.Time) (*model.ProblemReport, error)
+}
+
+// APIKeyStore holds the API keys tools and services authenticate with
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context, includeRevoked bool) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (*model.APIKey, error)
+	TouchAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+// WebhookStore holds webhooks and the log of what was delivered to them
+type WebhookStore interface {
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]model.Webhook, error)
+	GetWebhooksForEvent(ctx context.Context, eventType string) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID string, at time.Time) (*model.Webhook, error)
+	SaveWebhookDeliveries(ctx context.Context, deliveries ...model.WebhookDelivery) error
+	GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) ([]model.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, webhookID, status string, skip, limit int64) ([]model.WebhookDelivery, int64, error)
+}
+
+// AssignmentStore holds classroom assignments and the students who joined them
+type AssignmentStore interface {
+	CreateAssignment(ctx context.Context, assignment model.Assignment) (*model.Assignment, error)
+	GetAssignment(ctx context.Context, assignmentID string) (*model.Assignment, error)
+	SetAssignmentRoster(ctx context.Context, assignmentID string, roster []string) (*model.Assignment, error)
+	JoinAssignment(ctx context.Context, enrollment model.AssignmentEnrollment) (*model.AssignmentEnrollment, bool, error)
+	GetAssignmentEnrollment(ctx context.Context, assignmentID, userID string) (*model.AssignmentEnrollment, error)
+	GetAssignmentEnrollments(ctx context.Context, assignmentID string) ([]model.AssignmentEnrollment, error)
+	GetAssignmentSubmissions(ctx context.Context, userIDs, problemIDs []string, from, to time.Time) ([]model.Submission, error)
+}
+
+// OrganizationStore holds organizations and which one each user belongs to
+type OrganizationStore interface {
+	CreateOrganization(ctx context.Context, organization model.Organization) (*model.Organization, error)
+	GetOrganization(ctx context.Context, organizationID string) (*model.Organization, error)
+	ListOrganizations(ctx context.Context, kind string, skip, limit int64) ([]model.Organization, int64, error)
+	SetUserOrganization(ctx context.Context, member model.OrganizationMember) (string, error)
+	RemoveUserOrganization(ctx context.Context, userID string) (string, error)
+	GetOrganizationMemberships(ctx context.Context, userIDs []string) (map[string]string, error)
+	GetOrganizationMemberIDs(ctx context.Context, organizationID string) ([]string, error)
+	CountOrganizationMembers(ctx context.Context, organizationID string) (int64, error)
+}
+
+// ConsistencyStore cross-checks the submissions against the first successes the leaderboards are built from
+type ConsistencyStore interface {
+	FindMissingFirstSuccessesMongo(ctx context.Context, since time.Time) ([]model.Submission, error)
+	FindFirstSuccessDiscrepanciesMongo(ctx context.Context, since time.Time) ([]model.FirstSuccessDiscrepancy, error)
+	RepairFirstSuccess(ctx context.Context, submission model.Submission) (int, error)
+}
+
+// ExecutionProfileStore holds the runtime version, compiler flags and limits the engine runs each language with
+type ExecutionProfileStore interface {
+	SetExecutionProfile(ctx context.Context, profile model.ExecutionProfile) (*model.ExecutionProfile, error)
+	GetExecutionProfiles(ctx context.Context) ([]model.ExecutionProfile, error)
+	DeleteExecutionProfile(ctx context.Context, language string) (*model.ExecutionProfile, error)
+}
+
+var _ ProblemRepository = (*Repository)(nil)
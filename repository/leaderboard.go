@@ -0,0 +1,570 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetLeaderboardPageMongo returns one page of the global (or entity) leaderboard along with the total number of ranked users
+func (r *Repository) GetLeaderboardPageMongo(ctx context.Context, entity string, skip, limit int64) ([]model.RankedUserScore, int64, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	pipeline := mongo.Pipeline{}
+	if entity != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"country": entity}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+		}}},
+		// _id breaks ties so pages never overlap or skip users with equal scores
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "totalScore", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"users": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+			},
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+		}}},
+	)
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to aggregate leaderboard page: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		Users []model.RankedUserScore `bson:"users"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode leaderboard page: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if len(result.Total) > 0 {
+		total = result.Total[0].Count
+	}
+	for i := range result.Users {
+		result.Users[i].Rank = skip + int64(i) + 1
+	}
+	return result.Users, total, nil
+}
+
+// GetStandingsBetweenMongo returns the top users by score earned from first successes submitted in [from, to)
+func (r *Repository) GetStandingsBetweenMongo(ctx context.Context, from, to time.Time, limit int) ([]model.RankedUserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"submittedAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "totalScore", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate standings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []model.RankedUserScore
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode standings: %w", err)
+	}
+	for i := range users {
+		users[i].Rank = int64(i) + 1
+	}
+	return users, nil
+}
+
+// SaveLeaderboardSeasonSnapshot stores the final standings of a season; a season is only stored once
+func (r *Repository) SaveLeaderboardSeasonSnapshot(ctx context.Context, snapshot model.LeaderboardSeasonSnapshot) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	filter := bson.M{"period": snapshot.Period, "seasonStart": snapshot.SeasonStart}
+	update := bson.M{"$setOnInsert": snapshot}
+	if _, err := r.leaderboardSeasonsCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save %s season snapshot: %w", snapshot.Period, err)
+	}
+	return nil
+}
+
+// GetEntityStandingsMongo returns the top perEntity users of every entity in one aggregation
+func (r *Repository) GetEntityStandingsMongo(ctx context.Context, perEntity int) (map[string][]model.RankedUserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "totalScore", Value: -1}, {Key: "_id", Value: 1}}}},
+		// $push keeps the sorted order, so each entity's array is already ranked
+		{{Key: "$group", Value: bson.M{
+			"_id": "$primaryCountry",
+			"users": bson.M{"$push": bson.M{
+				"_id":            "$_id",
+				"totalScore":     "$totalScore",
+				"primaryCountry": "$primaryCountry",
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{"users": bson.M{"$slice": bson.A{"$users", perEntity}}}}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate entity standings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	standings := make(map[string][]model.RankedUserScore)
+	for cursor.Next(ctx) {
+		var result struct {
+			Entity string                  `bson:"_id"`
+			Users  []model.RankedUserScore `bson:"users"`
+		}
+		if err := cursor.Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode entity standings: %w", err)
+		}
+		if result.Entity == "" {
+			continue
+		}
+		for i := range result.Users {
+			result.Users[i].Rank = int64(i) + 1
+		}
+		standings[result.Entity] = result.Users
+	}
+	return standings, cursor.Err()
+}
+
+// SaveLeaderboardDailySnapshots upserts the snapshots keyed by (date, scope) so a rerun on the same day overwrites
+func (r *Repository) SaveLeaderboardDailySnapshots(ctx context.Context, snapshots []model.LeaderboardDailySnapshot) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if len(snapshots) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"date": snapshot.Date, "scope": snapshot.Scope}).
+			SetUpdate(bson.M{"$set": bson.M{"users": snapshot.Users, "createdAt": snapshot.CreatedAt}}).
+			SetUpsert(true))
+	}
+	if _, err := r.leaderboardSnapshotsCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to save leaderboard snapshots: %w", err)
+	}
+	return nil
+}
+
+// GetLeaderboardDailySnapshots returns the snapshots of a scope taken since from, oldest first
+func (r *Repository) GetLeaderboardDailySnapshots(ctx context.Context, scope string, from time.Time) ([]model.LeaderboardDailySnapshot, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{"scope": scope, "date": bson.M{"$gte": from}}
+	cursor, err := r.leaderboardSnapshotsCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"date": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find leaderboard snapshots: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []model.LeaderboardDailySnapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode leaderboard snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// GetUsersChangedSinceMongo returns the users with first successes submitted at or after since, plus the latest submittedAt seen
+func (r *Repository) GetUsersChangedSinceMongo(ctx context.Context, since time.Time) ([]string, time.Time, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"submittedAt": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    nil,
+			"users":  bson.M{"$addToSet": "$userId"},
+			"latest": bson.M{"$max": "$submittedAt"},
+		}}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to aggregate changed users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result struct {
+		Users  []string  `bson:"users"`
+		Latest time.Time `bson:"latest"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to decode changed users: %w", err)
+		}
+	}
+	return result.Users, result.Latest, cursor.Err()
+}
+
+// GetUserTotalsMongo returns the total score and primary entity of each given user, counting only first successes since since
+func (r *Repository) GetUserTotalsMongo(ctx context.Context, userIDs []string, since time.Time) ([]model.RankedUserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	banned, err := r.GetLeaderboardBannedUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	match := bson.M{"userId": bson.M{"$in": userIDs, "$nin": banned}}
+	if !since.IsZero() {
+		match["submittedAt"] = bson.M{"$gte": since}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		// same ordering as the full sync so the primary entity matches
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+		}}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate user totals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []model.RankedUserScore
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode user totals: %w", err)
+	}
+	return users, nil
+}
+
+// GetInactiveUserTotalsMongo returns the total score, primary entity and last first success of every user whose
+// last first success is before inactiveSince, counting only first successes since since
+func (r *Repository) GetInactiveUserTotalsMongo(ctx context.Context, inactiveSince, since time.Time) ([]model.InactiveUserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	banned, err := r.GetLeaderboardBannedUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	match := bson.M{"userId": bson.M{"$nin": banned}}
+	if !since.IsZero() {
+		match["submittedAt"] = bson.M{"$gte": since}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		// same ordering as the full sync so the primary entity matches
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+			"lastActiveAt":   bson.M{"$max": "$submittedAt"},
+		}}},
+		{{Key: "$match", Value: bson.M{"lastActiveAt": bson.M{"$lt": inactiveSince}}}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate inactive users: %w", dbError(err))
+	}
+	defer cursor.Close(ctx)
+
+	var users []model.InactiveUserScore
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode inactive users: %w", err)
+	}
+	return users, nil
+}
+
+// scoreRecalculationBatchSize bounds the number of updates sent per BulkWrite
+const scoreRecalculationBatchSize = 500
+
+// RecalculateFirstSuccessScores replays every first success through the score table and rewrites the stored score,
+// on both the first success and its originating submission, wherever it differs. With dryRun nothing is written.
+func (r *Repository) RecalculateFirstSuccessScores(ctx context.Context, dryRun bool) (scanned int64, updated int64, err error) {
+	cursor, err := r.submissionFirstSuccessCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find first successes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var firstSuccessModels, submissionModels []mongo.WriteModel
+	flush := func() error {
+		if len(firstSuccessModels) > 0 {
+			if _, err := r.submissionFirstSuccessCollection.BulkWrite(ctx, firstSuccessModels, options.BulkWrite().SetOrdered(false)); err != nil {
+				return fmt.Errorf("failed to update first success scores: %w", err)
+			}
+		}
+		if len(submissionModels) > 0 {
+			if _, err := r.submissionsCollection.BulkWrite(ctx, submissionModels, options.BulkWrite().SetOrdered(false)); err != nil {
+				return fmt.Errorf("failed to update submission scores: %w", err)
+			}
+		}
+		firstSuccessModels, submissionModels = firstSuccessModels[:0], submissionModels[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var done model.ProblemDone
+		if err := cursor.Decode(&done); err != nil {
+			return scanned, updated, fmt.Errorf("failed to decode first success: %w", err)
+		}
+		scanned++
+
+		score := r.scores.Score(done.Difficulty)
+		if score == done.Score {
+			continue
+		}
+		updated++
+		if dryRun {
+			continue
+		}
+
+		firstSuccessModels = append(firstSuccessModels, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": done.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"score": score}}))
+		if submissionID, err := primitive.ObjectIDFromHex(done.SubmissionID); err == nil {
+			submissionModels = append(submissionModels, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": submissionID}).
+				SetUpdate(bson.M{"$set": bson.M{"score": score}}))
+		}
+		if len(firstSuccessModels) >= scoreRecalculationBatchSize {
+			if err := flush(); err != nil {
+				return scanned, updated, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return scanned, updated, err
+	}
+	return scanned, updated, flush()
+}
+
+// GetEntityStatsMongo aggregates participation per entity, with the moversPerEntity users who gained the most score since moversSince
+func (r *Repository) GetEntityStatsMongo(ctx context.Context, moversSince time.Time, moversPerEntity int) ([]model.EntityStats, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+			"solves":         bson.M{"$sum": 1},
+			"recentScore": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$gte": bson.A{"$submittedAt", moversSince}}, "$score", 0,
+			}}},
+		}}},
+		// sorted before the $push so every entity's movers come out ranked
+		{{Key: "$sort", Value: bson.D{{Key: "recentScore", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$primaryCountry",
+			"participants": bson.M{"$sum": 1},
+			"totalSolves":  bson.M{"$sum": "$solves"},
+			"totalScore":   bson.M{"$sum": "$totalScore"},
+			"averageScore": bson.M{"$avg": "$totalScore"},
+			"movers":       bson.M{"$push": bson.M{"_id": "$_id", "recentScore": "$recentScore"}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"participants": 1,
+			"totalSolves":  1,
+			"totalScore":   1,
+			"averageScore": 1,
+			"topMovers": bson.M{"$slice": bson.A{
+				bson.M{"$filter": bson.M{"input": "$movers", "as": "m", "cond": bson.M{"$gt": bson.A{"$$m.recentScore", 0}}}},
+				moversPerEntity,
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "participants", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate entity stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []model.EntityStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode entity stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetUserStandingMongo returns a user's total score, entity, 1-based global and entity ranks and the number of ranked users.
+// Ranks are 0 when the user has no first successes.
+func (r *Repository) GetUserStandingMongo(ctx context.Context, userID string) (standing model.RankedUserScore, entityRank int64, total int64, err error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	userTotals := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$userId",
+			"totalScore":     bson.M{"$sum": "$score"},
+			"primaryCountry": bson.M{"$first": "$country"},
+		}}},
+	}
+
+	pipeline := append(mongo.Pipeline{}, userTotals...)
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"user":  bson.A{bson.M{"$match": bson.M{"_id": userID}}},
+		"total": bson.A{bson.M{"$count": "count"}},
+	}}})
+	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return standing, 0, 0, fmt.Errorf("failed to aggregate user standing: %w", err)
+	}
+	var result struct {
+		User  []model.RankedUserScore `bson:"user"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			cursor.Close(ctx)
+			return standing, 0, 0, fmt.Errorf("failed to decode user standing: %w", err)
+		}
+	}
+	cursor.Close(ctx)
+	if len(result.Total) > 0 {
+		total = result.Total[0].Count
+	}
+	if len(result.User) == 0 {
+		return model.RankedUserScore{UserID: userID}, 0, total, nil
+	}
+	standing = result.User[0]
+
+	// rank is one more than the number of users with a strictly higher score
+	pipeline = append(mongo.Pipeline{}, userTotals...)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$match", Value: bson.M{"totalScore": bson.M{"$gt": standing.Score}}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"global": bson.A{bson.M{"$count": "count"}},
+			"entity": bson.A{bson.M{"$match": bson.M{"primaryCountry": standing.Entity}}, bson.M{"$count": "count"}},
+		}}},
+	)
+	cursor, err = r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return standing, 0, total, fmt.Errorf("failed to aggregate user ranks: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var ahead struct {
+		Global []struct {
+			Count int64 `bson:"count"`
+		} `bson:"global"`
+		Entity []struct {
+			Count int64 `bson:"count"`
+		} `bson:"entity"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&ahead); err != nil {
+			return standing, 0, total, fmt.Errorf("failed to decode user ranks: %w", err)
+		}
+	}
+	standing.Rank = 1
+	if len(ahead.Global) > 0 {
+		standing.Rank += ahead.Global[0].Count
+	}
+	entityRank = 1
+	if len(ahead.Entity) > 0 {
+		entityRank += ahead.Entity[0].Count
+	}
+	return standing, entityRank, total, cursor.Err()
+}
+
+// NormalizeEntitiesMongo rewrites every stored country of submissions and first successes to normalize(country),
+// and returns the distinct countries that changed with how many documents carried them. A dry run only counts.
+func (r *Repository) NormalizeEntitiesMongo(ctx context.Context, normalize func(country string) string, dryRun bool) (map[string]string, int64, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+
+	collections := []*mongo.Collection{r.submissionsCollection, r.submissionFirstSuccessCollection}
+	changes := map[string]string{}
+	for _, collection := range collections {
+		countries, err := collection.Distinct(ctx, "country", bson.M{})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list countries of %s: %w", collection.Name(), dbError(err))
+		}
+		for _, value := range countries {
+			if country, ok := value.(string); ok {
+				if normalized := normalize(country); normalized != country {
+					changes[country] = normalized
+				}
+			}
+		}
+	}
+
+	var documents int64
+	for country, normalized := range changes {
+		filter := bson.M{"country": country}
+		for _, collection := range collections {
+			if dryRun {
+				count, err := collection.CountDocuments(ctx, filter)
+				if err != nil {
+					return changes, documents, fmt.Errorf("failed to count country %q in %s: %w", country, collection.Name(), dbError(err))
+				}
+				documents += count
+				continue
+			}
+			result, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"country": normalized}})
+			if err != nil {
+				return changes, documents, fmt.Errorf("failed to normalize country %q in %s: %w", country, collection.Name(), dbError(err))
+			}
+			documents += result.ModifiedCount
+		}
+	}
+	return changes, documents, nil
+}
+
+// UpdateUserEntityMongo moves all of a user's submissions and first successes to entity and returns how many first successes changed
+func (r *Repository) UpdateUserEntityMongo(ctx context.Context, userID, entity string) (int64, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	filter := bson.M{"userId": userID, "country": bson.M{"$ne": entity}}
+	update := bson.M{"$set": bson.M{"country": entity}}
+
+	if _, err := r.submissionsCollection.UpdateMany(ctx, filter, update); err != nil {
+		return 0, fmt.Errorf("failed to update submission entity for user %s: %w", userID, err)
+	}
+	result, err := r.submissionFirstSuccessCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update first success entity for user %s: %w", userID, err)
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xcode/model"
+
+	redisboard "github.com/lijuuu/RedisBoard"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap/zapcore"
+)
+
+// appliedLeaderboardUpdateTTL is how long applied outbox records are kept for inspection before MongoDB drops them
+const appliedLeaderboardUpdateTTL = 7 * 24 * time.Hour
+
+// applyLeaderboardUpdate sets the user's all-time score on the Redis board to their total in MongoDB, which already
+// includes update, and marks update applied. Setting the total instead of incrementing keeps it idempotent: a
+// crash between the Redis write and the mark only means the same total is written again.
+func (r *Repository) applyLeaderboardUpdate(ctx context.Context, update model.LeaderboardUpdate) error {
+	// banned users are left out of the totals and so stay off the board
+	totals, err := r.GetUserTotalsMongo(ctx, []string{update.UserID}, time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, total := range totals {
+		user := redisboard.User{ID: total.UserID, Entity: total.Entity, Score: total.Score}
+		if err := r.lb.AddUser(user); err != nil {
+			return fmt.Errorf("failed to set score of user %s on RedisBoard: %w", update.UserID, err)
+		}
+	}
+
+	_, err = r.leaderboardUpdatesCollection.UpdateOne(ctx,
+		bson.M{"_id": update.SubmissionID},
+		bson.M{"$set": bson.M{"appliedAt": time.Now()}, "$inc": bson.M{"attempts": 1}, "$unset": bson.M{"lastError": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to mark leaderboard update %s applied: %w", update.SubmissionID, err)
+	}
+	return nil
+}
+
+// ApplyPendingLeaderboardUpdates retries up to limit outbox records that were not applied right after their
+// submission, oldest first, and returns how many were applied. A failing record is left pending for the next run.
+func (r *Repository) ApplyPendingLeaderboardUpdates(ctx context.Context, limit int64) (int, error) {
+	opts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(limit)
+	cursor, err := r.leaderboardUpdatesCollection.Find(ctx, bson.M{"appliedAt": nil}, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch pending leaderboard updates: %w", err)
+	}
+	var pending []model.LeaderboardUpdate
+	if err := cursor.All(ctx, &pending); err != nil {
+		return 0, fmt.Errorf("failed to decode pending leaderboard updates: %w", err)
+	}
+
+	applied := 0
+	for _, update := range pending {
+		if err := r.applyLeaderboardUpdate(ctx, update); err != nil {
+			r.recordLeaderboardUpdateFailure(ctx, update, err)
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func (r *Repository) recordLeaderboardUpdateFailure(ctx context.Context, update model.LeaderboardUpdate, cause error) {
+	r.logger.LogContext(ctx, zapcore.WarnLevel, "Failed to apply leaderboard update", map[string]any{
+		"method":       "applyLeaderboardUpdate",
+		"submissionId": update.SubmissionID,
+		"userId":       update.UserID,
+		"attempts":     update.Attempts + 1,
+	}, "REPOSITORY", cause)
+
+	_, err := r.leaderboardUpdatesCollection.UpdateOne(ctx,
+		bson.M{"_id": update.SubmissionID},
+		bson.M{"$inc": bson.M{"attempts": 1}, "$set": bson.M{"lastError": cause.Error()}})
+	if err != nil {
+		r.logger.LogContext(ctx, zapcore.ErrorLevel, "Failed to record leaderboard update failure", map[string]any{
+			"method":       "recordLeaderboardUpdateFailure",
+			"submissionId": update.SubmissionID,
+		}, "REPOSITORY", err)
+	}
+}
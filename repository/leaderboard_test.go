@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// seedFirstSuccesses stores the given first successes of userID on distinct problems, submitted a minute apart in order
+func seedFirstSuccesses(t *testing.T, repo *Repository, userID string, successes ...model.ProblemDone) {
+	t.Helper()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, success := range successes {
+		success.ID = primitive.NewObjectID()
+		success.UserID = userID
+		success.ProblemID = fmt.Sprintf("problem-%d", i)
+		success.SubmittedAt = base.Add(time.Duration(i) * time.Minute)
+		if _, err := repo.submissionFirstSuccessCollection.InsertOne(context.Background(), success); err != nil {
+			t.Fatalf("seed %s: %v", userID, err)
+		}
+	}
+}
+
+func TestGetUserRankMongoTiesAndPageBoundaries(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	// with pages of two, bob and carol tie across the first boundary and dave and erin across the second
+	seedFirstSuccesses(t, repo, "alice", model.ProblemDone{Score: 200, Country: "IN"}, model.ProblemDone{Score: 100, Country: "IN"})
+	seedFirstSuccesses(t, repo, "bob", model.ProblemDone{Score: 200, Country: "US"})
+	seedFirstSuccesses(t, repo, "carol", model.ProblemDone{Score: 100, Country: "IN"}, model.ProblemDone{Score: 100, Country: "IN"})
+	seedFirstSuccesses(t, repo, "dave", model.ProblemDone{Score: 100, Country: "US"})
+	// erin's entity is the country of her earliest first success
+	seedFirstSuccesses(t, repo, "erin", model.ProblemDone{Score: 50, Country: "US"}, model.ProblemDone{Score: 50, Country: "IN"})
+	seedFirstSuccesses(t, repo, "frank", model.ProblemDone{Score: 50, Country: "IN"})
+
+	tests := []struct {
+		user       string
+		global     int
+		entity     int
+		pageOffset int64 // of the user in the global listing
+	}{
+		{user: "alice", global: 1, entity: 1, pageOffset: 0},
+		{user: "bob", global: 2, entity: 1, pageOffset: 1},
+		{user: "carol", global: 2, entity: 2, pageOffset: 2},
+		{user: "dave", global: 4, entity: 2, pageOffset: 3},
+		{user: "erin", global: 4, entity: 2, pageOffset: 4},
+		{user: "frank", global: 6, entity: 3, pageOffset: 5},
+	}
+	const pageSize = 2
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			global, entity, err := repo.GetUserRankMongo(ctx, tt.user)
+			if err != nil {
+				t.Fatalf("GetUserRankMongo: %v", err)
+			}
+			if global != tt.global || entity != tt.entity {
+				t.Errorf("ranks = %d global, %d entity; want %d, %d", global, entity, tt.global, tt.entity)
+			}
+
+			// the page holding the user lists them, and never above the rank their score earns them
+			skip := tt.pageOffset / pageSize * pageSize
+			page, total, err := repo.GetLeaderboardPageMongo(ctx, "", skip, pageSize)
+			if err != nil {
+				t.Fatalf("GetLeaderboardPageMongo: %v", err)
+			}
+			if total != int64(len(tests)) {
+				t.Errorf("total = %d, want %d", total, len(tests))
+			}
+			if int64(len(page)) <= tt.pageOffset-skip {
+				t.Fatalf("page at skip %d has %d users, want the user at offset %d", skip, len(page), tt.pageOffset)
+			}
+			listed := page[tt.pageOffset-skip]
+			if listed.UserID != tt.user {
+				t.Fatalf("page at skip %d lists %s at the user's position, want %s", skip, listed.UserID, tt.user)
+			}
+			if listed.Rank != tt.pageOffset+1 || int64(global) > listed.Rank {
+				t.Errorf("listed at %d with rank %d, want position %d and a rank no lower", listed.Rank, global, tt.pageOffset+1)
+			}
+		})
+	}
+}
+
+func TestGetUserRankMongoUnranked(t *testing.T) {
+	repo := newTestRepository(t)
+	seedFirstSuccesses(t, repo, "alice", model.ProblemDone{Score: 100, Country: "IN"})
+
+	_, _, err := repo.GetUserRankMongo(context.Background(), "nobody")
+	if !errors.Is(err, customerrors.ErrNotFound) {
+		t.Errorf("err = %v, want customerrors.ErrNotFound", err)
+	}
+}
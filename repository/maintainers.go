@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetProblemAuthor records userID as the creator and first maintainer of a problem
+func (r *Repository) SetProblemAuthor(ctx context.Context, problemID, userID string) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.problemsCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{"created_by": userID}, "$addToSet": bson.M{"maintainers": userID}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set author of problem %s: %w", problemID, dbError(err))
+	}
+	if res.MatchedCount == 0 {
+		return customerrors.NotFound("problem %s", problemID)
+	}
+	return nil
+}
+
+// AddProblemMaintainer adds userID to the maintainers of a problem and returns them; added is false when userID
+// already was one
+func (r *Repository) AddProblemMaintainer(ctx context.Context, problemID, userID string) (maintainers []string, added bool, err error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var before model.Problem
+	err = r.problemsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$addToSet": bson.M{"maintainers": userID}, "$set": bson.M{"updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before).SetProjection(bson.M{"maintainers": 1}),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, customerrors.NotFound("problem %s", problemID)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to add maintainer to problem %s: %w", problemID, dbError(err))
+	}
+	if slices.Contains(before.Maintainers, userID) {
+		return before.Maintainers, false, nil
+	}
+	return append(before.Maintainers, userID), true, nil
+}
+
+// ListProblemsByMaintainer returns a page of the problems userID maintains, newest first
+func (r *Repository) ListProblemsByMaintainer(ctx context.Context, userID string, page, pageSize int32) (*pb.ListProblemsResponse, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	filter := bson.M{"deleted_at": nil, "maintainers": userID}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64(page-1) * int64(pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := r.problemsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problems of %s: %w", userID, dbError(err))
+	}
+	defer cursor.Close(ctx)
+	var problems []model.Problem
+	if err := cursor.All(ctx, &problems); err != nil {
+		return nil, fmt.Errorf("failed to decode problems of %s: %w", userID, dbError(err))
+	}
+	total, err := r.problemsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count problems of %s: %w", userID, dbError(err))
+	}
+
+	resp := &pb.ListProblemsResponse{
+		Problems:   make([]*pb.Problem, len(problems)),
+		TotalCount: int32(total),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	for i, p := range problems {
+		resp.Problems[i] = ToProblem(p)
+	}
+	return resp, nil
+}
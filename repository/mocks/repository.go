@@ -0,0 +1,5150 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interfaces.go -destination=mocks/repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "xcode/model"
+
+	proto "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	redisboard "github.com/lijuuu/RedisBoard"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProblemRepository is a mock of ProblemRepository interface.
+type MockProblemRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProblemRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProblemRepositoryMockRecorder is the mock recorder for MockProblemRepository.
+type MockProblemRepositoryMockRecorder struct {
+	mock *MockProblemRepository
+}
+
+// NewMockProblemRepository creates a new mock instance.
+func NewMockProblemRepository(ctrl *gomock.Controller) *MockProblemRepository {
+	mock := &MockProblemRepository{ctrl: ctrl}
+	mock.recorder = &MockProblemRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProblemRepository) EXPECT() *MockProblemRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddLanguageSupport mocks base method.
+func (m *MockProblemRepository) AddLanguageSupport(ctx context.Context, req *proto.AddLanguageSupportRequest) (*proto.AddLanguageSupportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLanguageSupport", ctx, req)
+	ret0, _ := ret[0].(*proto.AddLanguageSupportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLanguageSupport indicates an expected call of AddLanguageSupport.
+func (mr *MockProblemRepositoryMockRecorder) AddLanguageSupport(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLanguageSupport", reflect.TypeOf((*MockProblemRepository)(nil).AddLanguageSupport), ctx, req)
+}
+
+// AddProblemMaintainer mocks base method.
+func (m *MockProblemRepository) AddProblemMaintainer(ctx context.Context, problemID, userID string) ([]string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProblemMaintainer", ctx, problemID, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddProblemMaintainer indicates an expected call of AddProblemMaintainer.
+func (mr *MockProblemRepositoryMockRecorder) AddProblemMaintainer(ctx, problemID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProblemMaintainer", reflect.TypeOf((*MockProblemRepository)(nil).AddProblemMaintainer), ctx, problemID, userID)
+}
+
+// AddTestCases mocks base method.
+func (m *MockProblemRepository) AddTestCases(ctx context.Context, req *proto.AddTestCasesRequest) (*proto.AddTestCasesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTestCases", ctx, req)
+	ret0, _ := ret[0].(*proto.AddTestCasesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTestCases indicates an expected call of AddTestCases.
+func (mr *MockProblemRepositoryMockRecorder) AddTestCases(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTestCases", reflect.TypeOf((*MockProblemRepository)(nil).AddTestCases), ctx, req)
+}
+
+// ApplyPendingLeaderboardUpdates mocks base method.
+func (m *MockProblemRepository) ApplyPendingLeaderboardUpdates(ctx context.Context, limit int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPendingLeaderboardUpdates", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyPendingLeaderboardUpdates indicates an expected call of ApplyPendingLeaderboardUpdates.
+func (mr *MockProblemRepositoryMockRecorder) ApplyPendingLeaderboardUpdates(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPendingLeaderboardUpdates", reflect.TypeOf((*MockProblemRepository)(nil).ApplyPendingLeaderboardUpdates), ctx, limit)
+}
+
+// BanUserFromLeaderboard mocks base method.
+func (m *MockProblemRepository) BanUserFromLeaderboard(ctx context.Context, ban model.LeaderboardBan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUserFromLeaderboard", ctx, ban)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BanUserFromLeaderboard indicates an expected call of BanUserFromLeaderboard.
+func (mr *MockProblemRepositoryMockRecorder) BanUserFromLeaderboard(ctx, ban any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUserFromLeaderboard", reflect.TypeOf((*MockProblemRepository)(nil).BanUserFromLeaderboard), ctx, ban)
+}
+
+// BasicValidationByProblemID mocks base method.
+func (m *MockProblemRepository) BasicValidationByProblemID(ctx context.Context, req *proto.FullValidationByProblemIDRequest) (*proto.FullValidationByProblemIDResponse, model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BasicValidationByProblemID", ctx, req)
+	ret0, _ := ret[0].(*proto.FullValidationByProblemIDResponse)
+	ret1, _ := ret[1].(model.Problem)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BasicValidationByProblemID indicates an expected call of BasicValidationByProblemID.
+func (mr *MockProblemRepositoryMockRecorder) BasicValidationByProblemID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BasicValidationByProblemID", reflect.TypeOf((*MockProblemRepository)(nil).BasicValidationByProblemID), ctx, req)
+}
+
+// CountOrganizationMembers mocks base method.
+func (m *MockProblemRepository) CountOrganizationMembers(ctx context.Context, organizationID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOrganizationMembers", ctx, organizationID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOrganizationMembers indicates an expected call of CountOrganizationMembers.
+func (mr *MockProblemRepositoryMockRecorder) CountOrganizationMembers(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrganizationMembers", reflect.TypeOf((*MockProblemRepository)(nil).CountOrganizationMembers), ctx, organizationID)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockProblemRepository) CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockProblemRepositoryMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockProblemRepository)(nil).CreateAPIKey), ctx, key)
+}
+
+// CreateAssignment mocks base method.
+func (m *MockProblemRepository) CreateAssignment(ctx context.Context, assignment model.Assignment) (*model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAssignment", ctx, assignment)
+	ret0, _ := ret[0].(*model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAssignment indicates an expected call of CreateAssignment.
+func (mr *MockProblemRepositoryMockRecorder) CreateAssignment(ctx, assignment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAssignment", reflect.TypeOf((*MockProblemRepository)(nil).CreateAssignment), ctx, assignment)
+}
+
+// CreateContest mocks base method.
+func (m *MockProblemRepository) CreateContest(ctx context.Context, contest model.Contest) (*model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContest", ctx, contest)
+	ret0, _ := ret[0].(*model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContest indicates an expected call of CreateContest.
+func (mr *MockProblemRepositoryMockRecorder) CreateContest(ctx, contest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContest", reflect.TypeOf((*MockProblemRepository)(nil).CreateContest), ctx, contest)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockProblemRepository) CreateOrganization(ctx context.Context, organization model.Organization) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, organization)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockProblemRepositoryMockRecorder) CreateOrganization(ctx, organization any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockProblemRepository)(nil).CreateOrganization), ctx, organization)
+}
+
+// CreateProblem mocks base method.
+func (m *MockProblemRepository) CreateProblem(ctx context.Context, req *proto.CreateProblemRequest) (*proto.CreateProblemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProblem", ctx, req)
+	ret0, _ := ret[0].(*proto.CreateProblemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProblem indicates an expected call of CreateProblem.
+func (mr *MockProblemRepositoryMockRecorder) CreateProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProblem", reflect.TypeOf((*MockProblemRepository)(nil).CreateProblem), ctx, req)
+}
+
+// CreateProblemReport mocks base method.
+func (m *MockProblemRepository) CreateProblemReport(ctx context.Context, report model.ProblemReport) (*model.ProblemReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProblemReport", ctx, report)
+	ret0, _ := ret[0].(*model.ProblemReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProblemReport indicates an expected call of CreateProblemReport.
+func (mr *MockProblemRepositoryMockRecorder) CreateProblemReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProblemReport", reflect.TypeOf((*MockProblemRepository)(nil).CreateProblemReport), ctx, report)
+}
+
+// CreateRejudgeJob mocks base method.
+func (m *MockProblemRepository) CreateRejudgeJob(ctx context.Context, job model.RejudgeJob, staleBefore time.Time) (*model.RejudgeJob, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRejudgeJob", ctx, job, staleBefore)
+	ret0, _ := ret[0].(*model.RejudgeJob)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRejudgeJob indicates an expected call of CreateRejudgeJob.
+func (mr *MockProblemRepositoryMockRecorder) CreateRejudgeJob(ctx, job, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRejudgeJob", reflect.TypeOf((*MockProblemRepository)(nil).CreateRejudgeJob), ctx, job, staleBefore)
+}
+
+// CreateSolution mocks base method.
+func (m *MockProblemRepository) CreateSolution(ctx context.Context, solution model.Solution) (*model.Solution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSolution", ctx, solution)
+	ret0, _ := ret[0].(*model.Solution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSolution indicates an expected call of CreateSolution.
+func (mr *MockProblemRepositoryMockRecorder) CreateSolution(ctx, solution any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSolution", reflect.TypeOf((*MockProblemRepository)(nil).CreateSolution), ctx, solution)
+}
+
+// CreateValidationJob mocks base method.
+func (m *MockProblemRepository) CreateValidationJob(ctx context.Context, job model.ValidationJob, staleBefore time.Time) (*model.ValidationJob, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateValidationJob", ctx, job, staleBefore)
+	ret0, _ := ret[0].(*model.ValidationJob)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateValidationJob indicates an expected call of CreateValidationJob.
+func (mr *MockProblemRepositoryMockRecorder) CreateValidationJob(ctx, job, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateValidationJob", reflect.TypeOf((*MockProblemRepository)(nil).CreateValidationJob), ctx, job, staleBefore)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockProblemRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockProblemRepositoryMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockProblemRepository)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteExecutionProfile mocks base method.
+func (m *MockProblemRepository) DeleteExecutionProfile(ctx context.Context, language string) (*model.ExecutionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExecutionProfile", ctx, language)
+	ret0, _ := ret[0].(*model.ExecutionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExecutionProfile indicates an expected call of DeleteExecutionProfile.
+func (mr *MockProblemRepositoryMockRecorder) DeleteExecutionProfile(ctx, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExecutionProfile", reflect.TypeOf((*MockProblemRepository)(nil).DeleteExecutionProfile), ctx, language)
+}
+
+// DeleteProblem mocks base method.
+func (m *MockProblemRepository) DeleteProblem(ctx context.Context, req *proto.DeleteProblemRequest) (*proto.DeleteProblemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProblem", ctx, req)
+	ret0, _ := ret[0].(*proto.DeleteProblemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProblem indicates an expected call of DeleteProblem.
+func (mr *MockProblemRepositoryMockRecorder) DeleteProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProblem", reflect.TypeOf((*MockProblemRepository)(nil).DeleteProblem), ctx, req)
+}
+
+// DeleteTestCase mocks base method.
+func (m *MockProblemRepository) DeleteTestCase(ctx context.Context, req *proto.DeleteTestCaseRequest) (*proto.DeleteTestCaseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTestCase", ctx, req)
+	ret0, _ := ret[0].(*proto.DeleteTestCaseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTestCase indicates an expected call of DeleteTestCase.
+func (mr *MockProblemRepositoryMockRecorder) DeleteTestCase(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTestCase", reflect.TypeOf((*MockProblemRepository)(nil).DeleteTestCase), ctx, req)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockProblemRepository) DeleteWebhook(ctx context.Context, webhookID string, at time.Time) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookID, at)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockProblemRepositoryMockRecorder) DeleteWebhook(ctx, webhookID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockProblemRepository)(nil).DeleteWebhook), ctx, webhookID, at)
+}
+
+// EnsureIndexes mocks base method.
+func (m *MockProblemRepository) EnsureIndexes(ctx context.Context) ([]model.CollectionIndexes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].([]model.CollectionIndexes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes.
+func (mr *MockProblemRepositoryMockRecorder) EnsureIndexes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockProblemRepository)(nil).EnsureIndexes), ctx)
+}
+
+// FinalizeContest mocks base method.
+func (m *MockProblemRepository) FinalizeContest(ctx context.Context, contestID string, standings []model.ContestStanding, rated bool) (*model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizeContest", ctx, contestID, standings, rated)
+	ret0, _ := ret[0].(*model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalizeContest indicates an expected call of FinalizeContest.
+func (mr *MockProblemRepositoryMockRecorder) FinalizeContest(ctx, contestID, standings, rated any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeContest", reflect.TypeOf((*MockProblemRepository)(nil).FinalizeContest), ctx, contestID, standings, rated)
+}
+
+// FindFirstSuccessDiscrepanciesMongo mocks base method.
+func (m *MockProblemRepository) FindFirstSuccessDiscrepanciesMongo(ctx context.Context, since time.Time) ([]model.FirstSuccessDiscrepancy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFirstSuccessDiscrepanciesMongo", ctx, since)
+	ret0, _ := ret[0].([]model.FirstSuccessDiscrepancy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFirstSuccessDiscrepanciesMongo indicates an expected call of FindFirstSuccessDiscrepanciesMongo.
+func (mr *MockProblemRepositoryMockRecorder) FindFirstSuccessDiscrepanciesMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFirstSuccessDiscrepanciesMongo", reflect.TypeOf((*MockProblemRepository)(nil).FindFirstSuccessDiscrepanciesMongo), ctx, since)
+}
+
+// FindMissingFirstSuccessesMongo mocks base method.
+func (m *MockProblemRepository) FindMissingFirstSuccessesMongo(ctx context.Context, since time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMissingFirstSuccessesMongo", ctx, since)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMissingFirstSuccessesMongo indicates an expected call of FindMissingFirstSuccessesMongo.
+func (mr *MockProblemRepositoryMockRecorder) FindMissingFirstSuccessesMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMissingFirstSuccessesMongo", reflect.TypeOf((*MockProblemRepository)(nil).FindMissingFirstSuccessesMongo), ctx, since)
+}
+
+// FinishRejudgeJob mocks base method.
+func (m *MockProblemRepository) FinishRejudgeJob(ctx context.Context, id primitive.ObjectID, status, message string, usersRescored int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRejudgeJob", ctx, id, status, message, usersRescored)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRejudgeJob indicates an expected call of FinishRejudgeJob.
+func (mr *MockProblemRepositoryMockRecorder) FinishRejudgeJob(ctx, id, status, message, usersRescored any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRejudgeJob", reflect.TypeOf((*MockProblemRepository)(nil).FinishRejudgeJob), ctx, id, status, message, usersRescored)
+}
+
+// FinishValidationJob mocks base method.
+func (m *MockProblemRepository) FinishValidationJob(ctx context.Context, id primitive.ObjectID, report model.ValidateProblemResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishValidationJob", ctx, id, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishValidationJob indicates an expected call of FinishValidationJob.
+func (mr *MockProblemRepositoryMockRecorder) FinishValidationJob(ctx, id, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishValidationJob", reflect.TypeOf((*MockProblemRepository)(nil).FinishValidationJob), ctx, id, report)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockProblemRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockProblemRepositoryMockRecorder) GetAPIKeyByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockProblemRepository)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetAssignment mocks base method.
+func (m *MockProblemRepository) GetAssignment(ctx context.Context, assignmentID string) (*model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignment", ctx, assignmentID)
+	ret0, _ := ret[0].(*model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignment indicates an expected call of GetAssignment.
+func (mr *MockProblemRepositoryMockRecorder) GetAssignment(ctx, assignmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignment", reflect.TypeOf((*MockProblemRepository)(nil).GetAssignment), ctx, assignmentID)
+}
+
+// GetAssignmentEnrollment mocks base method.
+func (m *MockProblemRepository) GetAssignmentEnrollment(ctx context.Context, assignmentID, userID string) (*model.AssignmentEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentEnrollment", ctx, assignmentID, userID)
+	ret0, _ := ret[0].(*model.AssignmentEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentEnrollment indicates an expected call of GetAssignmentEnrollment.
+func (mr *MockProblemRepositoryMockRecorder) GetAssignmentEnrollment(ctx, assignmentID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentEnrollment", reflect.TypeOf((*MockProblemRepository)(nil).GetAssignmentEnrollment), ctx, assignmentID, userID)
+}
+
+// GetAssignmentEnrollments mocks base method.
+func (m *MockProblemRepository) GetAssignmentEnrollments(ctx context.Context, assignmentID string) ([]model.AssignmentEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentEnrollments", ctx, assignmentID)
+	ret0, _ := ret[0].([]model.AssignmentEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentEnrollments indicates an expected call of GetAssignmentEnrollments.
+func (mr *MockProblemRepositoryMockRecorder) GetAssignmentEnrollments(ctx, assignmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentEnrollments", reflect.TypeOf((*MockProblemRepository)(nil).GetAssignmentEnrollments), ctx, assignmentID)
+}
+
+// GetAssignmentSubmissions mocks base method.
+func (m *MockProblemRepository) GetAssignmentSubmissions(ctx context.Context, userIDs, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentSubmissions", ctx, userIDs, problemIDs, from, to)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentSubmissions indicates an expected call of GetAssignmentSubmissions.
+func (mr *MockProblemRepositoryMockRecorder) GetAssignmentSubmissions(ctx, userIDs, problemIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentSubmissions", reflect.TypeOf((*MockProblemRepository)(nil).GetAssignmentSubmissions), ctx, userIDs, problemIDs, from, to)
+}
+
+// GetBestSubmissions mocks base method.
+func (m *MockProblemRepository) GetBestSubmissions(ctx context.Context, userID string, byLanguage bool) ([]*proto.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBestSubmissions", ctx, userID, byLanguage)
+	ret0, _ := ret[0].([]*proto.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBestSubmissions indicates an expected call of GetBestSubmissions.
+func (mr *MockProblemRepositoryMockRecorder) GetBestSubmissions(ctx, userID, byLanguage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestSubmissions", reflect.TypeOf((*MockProblemRepository)(nil).GetBestSubmissions), ctx, userID, byLanguage)
+}
+
+// GetBulkProblemMetadata mocks base method.
+func (m *MockProblemRepository) GetBulkProblemMetadata(ctx context.Context, req *proto.GetBulkProblemMetadataRequest) (*proto.GetBulkProblemMetadataResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkProblemMetadata", ctx, req)
+	ret0, _ := ret[0].(*proto.GetBulkProblemMetadataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkProblemMetadata indicates an expected call of GetBulkProblemMetadata.
+func (mr *MockProblemRepositoryMockRecorder) GetBulkProblemMetadata(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkProblemMetadata", reflect.TypeOf((*MockProblemRepository)(nil).GetBulkProblemMetadata), ctx, req)
+}
+
+// GetCodeDraft mocks base method.
+func (m *MockProblemRepository) GetCodeDraft(ctx context.Context, id string) (*model.CodeDraft, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeDraft", ctx, id)
+	ret0, _ := ret[0].(*model.CodeDraft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeDraft indicates an expected call of GetCodeDraft.
+func (mr *MockProblemRepositoryMockRecorder) GetCodeDraft(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeDraft", reflect.TypeOf((*MockProblemRepository)(nil).GetCodeDraft), ctx, id)
+}
+
+// GetContest mocks base method.
+func (m *MockProblemRepository) GetContest(ctx context.Context, contestID string) (*model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContest", ctx, contestID)
+	ret0, _ := ret[0].(*model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContest indicates an expected call of GetContest.
+func (mr *MockProblemRepositoryMockRecorder) GetContest(ctx, contestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContest", reflect.TypeOf((*MockProblemRepository)(nil).GetContest), ctx, contestID)
+}
+
+// GetContestRegistration mocks base method.
+func (m *MockProblemRepository) GetContestRegistration(ctx context.Context, contestID, userID string) (*model.ContestRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestRegistration", ctx, contestID, userID)
+	ret0, _ := ret[0].(*model.ContestRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestRegistration indicates an expected call of GetContestRegistration.
+func (mr *MockProblemRepositoryMockRecorder) GetContestRegistration(ctx, contestID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestRegistration", reflect.TypeOf((*MockProblemRepository)(nil).GetContestRegistration), ctx, contestID, userID)
+}
+
+// GetContestRegistrations mocks base method.
+func (m *MockProblemRepository) GetContestRegistrations(ctx context.Context, contestID string) ([]model.ContestRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestRegistrations", ctx, contestID)
+	ret0, _ := ret[0].([]model.ContestRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestRegistrations indicates an expected call of GetContestRegistrations.
+func (mr *MockProblemRepositoryMockRecorder) GetContestRegistrations(ctx, contestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestRegistrations", reflect.TypeOf((*MockProblemRepository)(nil).GetContestRegistrations), ctx, contestID)
+}
+
+// GetContestStandings mocks base method.
+func (m *MockProblemRepository) GetContestStandings(ctx context.Context, contestID, division string, skip, limit int64) ([]model.ContestStanding, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestStandings", ctx, contestID, division, skip, limit)
+	ret0, _ := ret[0].([]model.ContestStanding)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetContestStandings indicates an expected call of GetContestStandings.
+func (mr *MockProblemRepositoryMockRecorder) GetContestStandings(ctx, contestID, division, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestStandings", reflect.TypeOf((*MockProblemRepository)(nil).GetContestStandings), ctx, contestID, division, skip, limit)
+}
+
+// GetContestSubmissions mocks base method.
+func (m *MockProblemRepository) GetContestSubmissions(ctx context.Context, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestSubmissions", ctx, problemIDs, from, to)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestSubmissions indicates an expected call of GetContestSubmissions.
+func (mr *MockProblemRepositoryMockRecorder) GetContestSubmissions(ctx, problemIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestSubmissions", reflect.TypeOf((*MockProblemRepository)(nil).GetContestSubmissions), ctx, problemIDs, from, to)
+}
+
+// GetContestsToFinalize mocks base method.
+func (m *MockProblemRepository) GetContestsToFinalize(ctx context.Context, endedBefore time.Time) ([]model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestsToFinalize", ctx, endedBefore)
+	ret0, _ := ret[0].([]model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestsToFinalize indicates an expected call of GetContestsToFinalize.
+func (mr *MockProblemRepositoryMockRecorder) GetContestsToFinalize(ctx, endedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestsToFinalize", reflect.TypeOf((*MockProblemRepository)(nil).GetContestsToFinalize), ctx, endedBefore)
+}
+
+// GetDailySubmissionCounts mocks base method.
+func (m *MockProblemRepository) GetDailySubmissionCounts(ctx context.Context, userID string, from, to time.Time) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailySubmissionCounts", ctx, userID, from, to)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailySubmissionCounts indicates an expected call of GetDailySubmissionCounts.
+func (mr *MockProblemRepositoryMockRecorder) GetDailySubmissionCounts(ctx, userID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailySubmissionCounts", reflect.TypeOf((*MockProblemRepository)(nil).GetDailySubmissionCounts), ctx, userID, from, to)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockProblemRepository) GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*model.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockProblemRepositoryMockRecorder) GetDeadLetter(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockProblemRepository)(nil).GetDeadLetter), ctx, id)
+}
+
+// GetDueWebhookDeliveries mocks base method.
+func (m *MockProblemRepository) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueWebhookDeliveries", ctx, now, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueWebhookDeliveries indicates an expected call of GetDueWebhookDeliveries.
+func (mr *MockProblemRepositoryMockRecorder) GetDueWebhookDeliveries(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueWebhookDeliveries", reflect.TypeOf((*MockProblemRepository)(nil).GetDueWebhookDeliveries), ctx, now, limit)
+}
+
+// GetEntityStandingsMongo mocks base method.
+func (m *MockProblemRepository) GetEntityStandingsMongo(ctx context.Context, perEntity int) (map[string][]model.RankedUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityStandingsMongo", ctx, perEntity)
+	ret0, _ := ret[0].(map[string][]model.RankedUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntityStandingsMongo indicates an expected call of GetEntityStandingsMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetEntityStandingsMongo(ctx, perEntity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityStandingsMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetEntityStandingsMongo), ctx, perEntity)
+}
+
+// GetEntityStatsMongo mocks base method.
+func (m *MockProblemRepository) GetEntityStatsMongo(ctx context.Context, moversSince time.Time, moversPerEntity int) ([]model.EntityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityStatsMongo", ctx, moversSince, moversPerEntity)
+	ret0, _ := ret[0].([]model.EntityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntityStatsMongo indicates an expected call of GetEntityStatsMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetEntityStatsMongo(ctx, moversSince, moversPerEntity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityStatsMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetEntityStatsMongo), ctx, moversSince, moversPerEntity)
+}
+
+// GetExecutionProfiles mocks base method.
+func (m *MockProblemRepository) GetExecutionProfiles(ctx context.Context) ([]model.ExecutionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExecutionProfiles", ctx)
+	ret0, _ := ret[0].([]model.ExecutionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExecutionProfiles indicates an expected call of GetExecutionProfiles.
+func (mr *MockProblemRepositoryMockRecorder) GetExecutionProfiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionProfiles", reflect.TypeOf((*MockProblemRepository)(nil).GetExecutionProfiles), ctx)
+}
+
+// GetFirstSolvers mocks base method.
+func (m *MockProblemRepository) GetFirstSolvers(ctx context.Context, problemIDs []string, challengeID string) ([]model.FirstSolver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstSolvers", ctx, problemIDs, challengeID)
+	ret0, _ := ret[0].([]model.FirstSolver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstSolvers indicates an expected call of GetFirstSolvers.
+func (mr *MockProblemRepositoryMockRecorder) GetFirstSolvers(ctx, problemIDs, challengeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstSolvers", reflect.TypeOf((*MockProblemRepository)(nil).GetFirstSolvers), ctx, problemIDs, challengeID)
+}
+
+// GetHiddenProblemIDs mocks base method.
+func (m *MockProblemRepository) GetHiddenProblemIDs(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHiddenProblemIDs", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHiddenProblemIDs indicates an expected call of GetHiddenProblemIDs.
+func (mr *MockProblemRepositoryMockRecorder) GetHiddenProblemIDs(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHiddenProblemIDs", reflect.TypeOf((*MockProblemRepository)(nil).GetHiddenProblemIDs), ctx, userID)
+}
+
+// GetInactiveUserTotalsMongo mocks base method.
+func (m *MockProblemRepository) GetInactiveUserTotalsMongo(ctx context.Context, inactiveSince, since time.Time) ([]model.InactiveUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInactiveUserTotalsMongo", ctx, inactiveSince, since)
+	ret0, _ := ret[0].([]model.InactiveUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInactiveUserTotalsMongo indicates an expected call of GetInactiveUserTotalsMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetInactiveUserTotalsMongo(ctx, inactiveSince, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInactiveUserTotalsMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetInactiveUserTotalsMongo), ctx, inactiveSince, since)
+}
+
+// GetLanguageSupports mocks base method.
+func (m *MockProblemRepository) GetLanguageSupports(ctx context.Context, req *proto.GetLanguageSupportsRequest) (*proto.GetLanguageSupportsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLanguageSupports", ctx, req)
+	ret0, _ := ret[0].(*proto.GetLanguageSupportsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLanguageSupports indicates an expected call of GetLanguageSupports.
+func (mr *MockProblemRepositoryMockRecorder) GetLanguageSupports(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLanguageSupports", reflect.TypeOf((*MockProblemRepository)(nil).GetLanguageSupports), ctx, req)
+}
+
+// GetLeaderboardDailySnapshots mocks base method.
+func (m *MockProblemRepository) GetLeaderboardDailySnapshots(ctx context.Context, scope string, from time.Time) ([]model.LeaderboardDailySnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardDailySnapshots", ctx, scope, from)
+	ret0, _ := ret[0].([]model.LeaderboardDailySnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboardDailySnapshots indicates an expected call of GetLeaderboardDailySnapshots.
+func (mr *MockProblemRepositoryMockRecorder) GetLeaderboardDailySnapshots(ctx, scope, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardDailySnapshots", reflect.TypeOf((*MockProblemRepository)(nil).GetLeaderboardDailySnapshots), ctx, scope, from)
+}
+
+// GetLeaderboardDataMongo mocks base method.
+func (m *MockProblemRepository) GetLeaderboardDataMongo(ctx context.Context, userID string) (*model.UserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardDataMongo", ctx, userID)
+	ret0, _ := ret[0].(*model.UserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboardDataMongo indicates an expected call of GetLeaderboardDataMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetLeaderboardDataMongo(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardDataMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetLeaderboardDataMongo), ctx, userID)
+}
+
+// GetLeaderboardPageMongo mocks base method.
+func (m *MockProblemRepository) GetLeaderboardPageMongo(ctx context.Context, entity string, skip, limit int64) ([]model.RankedUserScore, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardPageMongo", ctx, entity, skip, limit)
+	ret0, _ := ret[0].([]model.RankedUserScore)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLeaderboardPageMongo indicates an expected call of GetLeaderboardPageMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetLeaderboardPageMongo(ctx, entity, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardPageMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetLeaderboardPageMongo), ctx, entity, skip, limit)
+}
+
+// GetMislabeledProblems mocks base method.
+func (m *MockProblemRepository) GetMislabeledProblems(ctx context.Context) ([]model.ProblemDifficulty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMislabeledProblems", ctx)
+	ret0, _ := ret[0].([]model.ProblemDifficulty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMislabeledProblems indicates an expected call of GetMislabeledProblems.
+func (mr *MockProblemRepositoryMockRecorder) GetMislabeledProblems(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMislabeledProblems", reflect.TypeOf((*MockProblemRepository)(nil).GetMislabeledProblems), ctx)
+}
+
+// GetMonthlyContributionHistory mocks base method.
+func (m *MockProblemRepository) GetMonthlyContributionHistory(ctx context.Context, userID string, month, year int) (model.MonthlyActivityHeatmapProps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyContributionHistory", ctx, userID, month, year)
+	ret0, _ := ret[0].(model.MonthlyActivityHeatmapProps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyContributionHistory indicates an expected call of GetMonthlyContributionHistory.
+func (mr *MockProblemRepositoryMockRecorder) GetMonthlyContributionHistory(ctx, userID, month, year any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyContributionHistory", reflect.TypeOf((*MockProblemRepository)(nil).GetMonthlyContributionHistory), ctx, userID, month, year)
+}
+
+// GetOrganization mocks base method.
+func (m *MockProblemRepository) GetOrganization(ctx context.Context, organizationID string) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganization", ctx, organizationID)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganization indicates an expected call of GetOrganization.
+func (mr *MockProblemRepositoryMockRecorder) GetOrganization(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganization", reflect.TypeOf((*MockProblemRepository)(nil).GetOrganization), ctx, organizationID)
+}
+
+// GetOrganizationMemberIDs mocks base method.
+func (m *MockProblemRepository) GetOrganizationMemberIDs(ctx context.Context, organizationID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberIDs", ctx, organizationID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberIDs indicates an expected call of GetOrganizationMemberIDs.
+func (mr *MockProblemRepositoryMockRecorder) GetOrganizationMemberIDs(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberIDs", reflect.TypeOf((*MockProblemRepository)(nil).GetOrganizationMemberIDs), ctx, organizationID)
+}
+
+// GetOrganizationMemberships mocks base method.
+func (m *MockProblemRepository) GetOrganizationMemberships(ctx context.Context, userIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberships", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberships indicates an expected call of GetOrganizationMemberships.
+func (mr *MockProblemRepositoryMockRecorder) GetOrganizationMemberships(ctx, userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberships", reflect.TypeOf((*MockProblemRepository)(nil).GetOrganizationMemberships), ctx, userIDs)
+}
+
+// GetPendingOutboxEvents mocks base method.
+func (m *MockProblemRepository) GetPendingOutboxEvents(ctx context.Context, limit int64) ([]model.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingOutboxEvents", ctx, limit)
+	ret0, _ := ret[0].([]model.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingOutboxEvents indicates an expected call of GetPendingOutboxEvents.
+func (mr *MockProblemRepositoryMockRecorder) GetPendingOutboxEvents(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOutboxEvents", reflect.TypeOf((*MockProblemRepository)(nil).GetPendingOutboxEvents), ctx, limit)
+}
+
+// GetPremiumProblemIDs mocks base method.
+func (m *MockProblemRepository) GetPremiumProblemIDs(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPremiumProblemIDs", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPremiumProblemIDs indicates an expected call of GetPremiumProblemIDs.
+func (mr *MockProblemRepositoryMockRecorder) GetPremiumProblemIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPremiumProblemIDs", reflect.TypeOf((*MockProblemRepository)(nil).GetPremiumProblemIDs), ctx)
+}
+
+// GetProblem mocks base method.
+func (m *MockProblemRepository) GetProblem(ctx context.Context, req *proto.GetProblemRequest) (*model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblem", ctx, req)
+	ret0, _ := ret[0].(*model.Problem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblem indicates an expected call of GetProblem.
+func (mr *MockProblemRepositoryMockRecorder) GetProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblem", reflect.TypeOf((*MockProblemRepository)(nil).GetProblem), ctx, req)
+}
+
+// GetProblemByIDList mocks base method.
+func (m *MockProblemRepository) GetProblemByIDList(ctx context.Context, req *proto.GetProblemMetadataListRequest) (*proto.GetProblemMetadataListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemByIDList", ctx, req)
+	ret0, _ := ret[0].(*proto.GetProblemMetadataListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemByIDList indicates an expected call of GetProblemByIDList.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemByIDList(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemByIDList", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemByIDList), ctx, req)
+}
+
+// GetProblemByIDSlug mocks base method.
+func (m *MockProblemRepository) GetProblemByIDSlug(ctx context.Context, req *proto.GetProblemByIdSlugRequest) (*proto.GetProblemByIdSlugResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemByIDSlug", ctx, req)
+	ret0, _ := ret[0].(*proto.GetProblemByIdSlugResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemByIDSlug indicates an expected call of GetProblemByIDSlug.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemByIDSlug(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemByIDSlug", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemByIDSlug), ctx, req)
+}
+
+// GetProblemDifficulties mocks base method.
+func (m *MockProblemRepository) GetProblemDifficulties(ctx context.Context) ([]model.ProblemDifficulty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemDifficulties", ctx)
+	ret0, _ := ret[0].([]model.ProblemDifficulty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemDifficulties indicates an expected call of GetProblemDifficulties.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemDifficulties(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemDifficulties", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemDifficulties), ctx)
+}
+
+// GetProblemDifficulty mocks base method.
+func (m *MockProblemRepository) GetProblemDifficulty(ctx context.Context, problemID string) (*model.ProblemDifficulty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemDifficulty", ctx, problemID)
+	ret0, _ := ret[0].(*model.ProblemDifficulty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemDifficulty indicates an expected call of GetProblemDifficulty.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemDifficulty(ctx, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemDifficulty", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemDifficulty), ctx, problemID)
+}
+
+// GetProblemReport mocks base method.
+func (m *MockProblemRepository) GetProblemReport(ctx context.Context, reportID string) (*model.ProblemReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemReport", ctx, reportID)
+	ret0, _ := ret[0].(*model.ProblemReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemReport indicates an expected call of GetProblemReport.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemReport(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemReport", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemReport), ctx, reportID)
+}
+
+// GetProblemSolveStats mocks base method.
+func (m *MockProblemRepository) GetProblemSolveStats(ctx context.Context) ([]model.ProblemSolveStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemSolveStats", ctx)
+	ret0, _ := ret[0].([]model.ProblemSolveStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemSolveStats indicates an expected call of GetProblemSolveStats.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemSolveStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemSolveStats", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemSolveStats), ctx)
+}
+
+// GetProblemStatuses mocks base method.
+func (m *MockProblemRepository) GetProblemStatuses(ctx context.Context, userID string, problemIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemStatuses", ctx, userID, problemIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemStatuses indicates an expected call of GetProblemStatuses.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemStatuses(ctx, userID, problemIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemStatuses", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemStatuses), ctx, userID, problemIDs)
+}
+
+// GetProblemTranslations mocks base method.
+func (m *MockProblemRepository) GetProblemTranslations(ctx context.Context, problemID string) (map[string]model.ProblemTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemTranslations", ctx, problemID)
+	ret0, _ := ret[0].(map[string]model.ProblemTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemTranslations indicates an expected call of GetProblemTranslations.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemTranslations(ctx, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemTranslations", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemTranslations), ctx, problemID)
+}
+
+// GetProblemsByIDs mocks base method.
+func (m *MockProblemRepository) GetProblemsByIDs(ctx context.Context, problemIDs []string) ([]model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemsByIDs", ctx, problemIDs)
+	ret0, _ := ret[0].([]model.Problem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemsByIDs indicates an expected call of GetProblemsByIDs.
+func (mr *MockProblemRepositoryMockRecorder) GetProblemsByIDs(ctx, problemIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemsByIDs", reflect.TypeOf((*MockProblemRepository)(nil).GetProblemsByIDs), ctx, problemIDs)
+}
+
+// GetRandomProblem mocks base method.
+func (m *MockProblemRepository) GetRandomProblem(ctx context.Context, f model.RandomProblemFilter) (*model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRandomProblem", ctx, f)
+	ret0, _ := ret[0].(*model.Problem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRandomProblem indicates an expected call of GetRandomProblem.
+func (mr *MockProblemRepositoryMockRecorder) GetRandomProblem(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandomProblem", reflect.TypeOf((*MockProblemRepository)(nil).GetRandomProblem), ctx, f)
+}
+
+// GetRejudgeJob mocks base method.
+func (m *MockProblemRepository) GetRejudgeJob(ctx context.Context, jobID string) (*model.RejudgeJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejudgeJob", ctx, jobID)
+	ret0, _ := ret[0].(*model.RejudgeJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRejudgeJob indicates an expected call of GetRejudgeJob.
+func (mr *MockProblemRepositoryMockRecorder) GetRejudgeJob(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejudgeJob", reflect.TypeOf((*MockProblemRepository)(nil).GetRejudgeJob), ctx, jobID)
+}
+
+// GetRejudgeSubmissions mocks base method.
+func (m *MockProblemRepository) GetRejudgeSubmissions(ctx context.Context, problemID string, since *time.Time, after primitive.ObjectID, limit int64) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejudgeSubmissions", ctx, problemID, since, after, limit)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRejudgeSubmissions indicates an expected call of GetRejudgeSubmissions.
+func (mr *MockProblemRepositoryMockRecorder) GetRejudgeSubmissions(ctx, problemID, since, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejudgeSubmissions", reflect.TypeOf((*MockProblemRepository)(nil).GetRejudgeSubmissions), ctx, problemID, since, after, limit)
+}
+
+// GetServiceStatsMongo mocks base method.
+func (m *MockProblemRepository) GetServiceStatsMongo(ctx context.Context, since time.Time) (*model.ServiceStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceStatsMongo", ctx, since)
+	ret0, _ := ret[0].(*model.ServiceStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceStatsMongo indicates an expected call of GetServiceStatsMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetServiceStatsMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceStatsMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetServiceStatsMongo), ctx, since)
+}
+
+// GetSharedSubmission mocks base method.
+func (m *MockProblemRepository) GetSharedSubmission(ctx context.Context, token string) (*model.SharedSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedSubmission", ctx, token)
+	ret0, _ := ret[0].(*model.SharedSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedSubmission indicates an expected call of GetSharedSubmission.
+func (mr *MockProblemRepositoryMockRecorder) GetSharedSubmission(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedSubmission", reflect.TypeOf((*MockProblemRepository)(nil).GetSharedSubmission), ctx, token)
+}
+
+// GetSolution mocks base method.
+func (m *MockProblemRepository) GetSolution(ctx context.Context, solutionID string) (*model.Solution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSolution", ctx, solutionID)
+	ret0, _ := ret[0].(*model.Solution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSolution indicates an expected call of GetSolution.
+func (mr *MockProblemRepositoryMockRecorder) GetSolution(ctx, solutionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSolution", reflect.TypeOf((*MockProblemRepository)(nil).GetSolution), ctx, solutionID)
+}
+
+// GetStandingsBetweenMongo mocks base method.
+func (m *MockProblemRepository) GetStandingsBetweenMongo(ctx context.Context, from, to time.Time, limit int) ([]model.RankedUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStandingsBetweenMongo", ctx, from, to, limit)
+	ret0, _ := ret[0].([]model.RankedUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStandingsBetweenMongo indicates an expected call of GetStandingsBetweenMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetStandingsBetweenMongo(ctx, from, to, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStandingsBetweenMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetStandingsBetweenMongo), ctx, from, to, limit)
+}
+
+// GetSubmissionByID mocks base method.
+func (m *MockProblemRepository) GetSubmissionByID(ctx context.Context, submissionID string) (*model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubmissionByID", ctx, submissionID)
+	ret0, _ := ret[0].(*model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubmissionByID indicates an expected call of GetSubmissionByID.
+func (mr *MockProblemRepositoryMockRecorder) GetSubmissionByID(ctx, submissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubmissionByID", reflect.TypeOf((*MockProblemRepository)(nil).GetSubmissionByID), ctx, submissionID)
+}
+
+// GetSubmissionsByOptionalProblemID mocks base method.
+func (m *MockProblemRepository) GetSubmissionsByOptionalProblemID(ctx context.Context, req *proto.GetSubmissionsRequest) (*proto.GetSubmissionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubmissionsByOptionalProblemID", ctx, req)
+	ret0, _ := ret[0].(*proto.GetSubmissionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubmissionsByOptionalProblemID indicates an expected call of GetSubmissionsByOptionalProblemID.
+func (mr *MockProblemRepositoryMockRecorder) GetSubmissionsByOptionalProblemID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubmissionsByOptionalProblemID", reflect.TypeOf((*MockProblemRepository)(nil).GetSubmissionsByOptionalProblemID), ctx, req)
+}
+
+// GetTopKEntityMongo mocks base method.
+func (m *MockProblemRepository) GetTopKEntityMongo(ctx context.Context, entity string, k int) ([]model.UserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopKEntityMongo", ctx, entity, k)
+	ret0, _ := ret[0].([]model.UserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopKEntityMongo indicates an expected call of GetTopKEntityMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetTopKEntityMongo(ctx, entity, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopKEntityMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetTopKEntityMongo), ctx, entity, k)
+}
+
+// GetTopKGlobalMongo mocks base method.
+func (m *MockProblemRepository) GetTopKGlobalMongo(ctx context.Context, k int) ([]model.UserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopKGlobalMongo", ctx, k)
+	ret0, _ := ret[0].([]model.UserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopKGlobalMongo indicates an expected call of GetTopKGlobalMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetTopKGlobalMongo(ctx, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopKGlobalMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetTopKGlobalMongo), ctx, k)
+}
+
+// GetUserContestSubmissions mocks base method.
+func (m *MockProblemRepository) GetUserContestSubmissions(ctx context.Context, userID string, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserContestSubmissions", ctx, userID, problemIDs, from, to)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserContestSubmissions indicates an expected call of GetUserContestSubmissions.
+func (mr *MockProblemRepositoryMockRecorder) GetUserContestSubmissions(ctx, userID, problemIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserContestSubmissions", reflect.TypeOf((*MockProblemRepository)(nil).GetUserContestSubmissions), ctx, userID, problemIDs, from, to)
+}
+
+// GetUserLanguageStats mocks base method.
+func (m *MockProblemRepository) GetUserLanguageStats(ctx context.Context, userID string) ([]model.LanguageStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLanguageStats", ctx, userID)
+	ret0, _ := ret[0].([]model.LanguageStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLanguageStats indicates an expected call of GetUserLanguageStats.
+func (mr *MockProblemRepositoryMockRecorder) GetUserLanguageStats(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLanguageStats", reflect.TypeOf((*MockProblemRepository)(nil).GetUserLanguageStats), ctx, userID)
+}
+
+// GetUserRankMongo mocks base method.
+func (m *MockProblemRepository) GetUserRankMongo(ctx context.Context, userID string) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRankMongo", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserRankMongo indicates an expected call of GetUserRankMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetUserRankMongo(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRankMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetUserRankMongo), ctx, userID)
+}
+
+// GetUserRatings mocks base method.
+func (m *MockProblemRepository) GetUserRatings(ctx context.Context, userIDs []string) (map[string]model.UserRating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRatings", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]model.UserRating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRatings indicates an expected call of GetUserRatings.
+func (mr *MockProblemRepositoryMockRecorder) GetUserRatings(ctx, userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRatings", reflect.TypeOf((*MockProblemRepository)(nil).GetUserRatings), ctx, userIDs)
+}
+
+// GetUserStandingMongo mocks base method.
+func (m *MockProblemRepository) GetUserStandingMongo(ctx context.Context, userID string) (model.RankedUserScore, int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStandingMongo", ctx, userID)
+	ret0, _ := ret[0].(model.RankedUserScore)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(int64)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetUserStandingMongo indicates an expected call of GetUserStandingMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetUserStandingMongo(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStandingMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetUserStandingMongo), ctx, userID)
+}
+
+// GetUserTotalsMongo mocks base method.
+func (m *MockProblemRepository) GetUserTotalsMongo(ctx context.Context, userIDs []string, since time.Time) ([]model.RankedUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTotalsMongo", ctx, userIDs, since)
+	ret0, _ := ret[0].([]model.RankedUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTotalsMongo indicates an expected call of GetUserTotalsMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetUserTotalsMongo(ctx, userIDs, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTotalsMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetUserTotalsMongo), ctx, userIDs, since)
+}
+
+// GetUsersChangedSinceMongo mocks base method.
+func (m *MockProblemRepository) GetUsersChangedSinceMongo(ctx context.Context, since time.Time) ([]string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersChangedSinceMongo", ctx, since)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsersChangedSinceMongo indicates an expected call of GetUsersChangedSinceMongo.
+func (mr *MockProblemRepositoryMockRecorder) GetUsersChangedSinceMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersChangedSinceMongo", reflect.TypeOf((*MockProblemRepository)(nil).GetUsersChangedSinceMongo), ctx, since)
+}
+
+// GetValidationJob mocks base method.
+func (m *MockProblemRepository) GetValidationJob(ctx context.Context, jobID string) (*model.ValidationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidationJob", ctx, jobID)
+	ret0, _ := ret[0].(*model.ValidationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidationJob indicates an expected call of GetValidationJob.
+func (mr *MockProblemRepositoryMockRecorder) GetValidationJob(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidationJob", reflect.TypeOf((*MockProblemRepository)(nil).GetValidationJob), ctx, jobID)
+}
+
+// GetVirtualParticipation mocks base method.
+func (m *MockProblemRepository) GetVirtualParticipation(ctx context.Context, contestID, userID string) (*model.VirtualParticipation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVirtualParticipation", ctx, contestID, userID)
+	ret0, _ := ret[0].(*model.VirtualParticipation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVirtualParticipation indicates an expected call of GetVirtualParticipation.
+func (mr *MockProblemRepositoryMockRecorder) GetVirtualParticipation(ctx, contestID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVirtualParticipation", reflect.TypeOf((*MockProblemRepository)(nil).GetVirtualParticipation), ctx, contestID, userID)
+}
+
+// GetWebhooksForEvent mocks base method.
+func (m *MockProblemRepository) GetWebhooksForEvent(ctx context.Context, eventType string) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksForEvent", ctx, eventType)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksForEvent indicates an expected call of GetWebhooksForEvent.
+func (mr *MockProblemRepositoryMockRecorder) GetWebhooksForEvent(ctx, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksForEvent", reflect.TypeOf((*MockProblemRepository)(nil).GetWebhooksForEvent), ctx, eventType)
+}
+
+// HideProblem mocks base method.
+func (m *MockProblemRepository) HideProblem(ctx context.Context, userID, problemID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideProblem", ctx, userID, problemID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HideProblem indicates an expected call of HideProblem.
+func (mr *MockProblemRepositoryMockRecorder) HideProblem(ctx, userID, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideProblem", reflect.TypeOf((*MockProblemRepository)(nil).HideProblem), ctx, userID, problemID)
+}
+
+// InvalidateSubmission mocks base method.
+func (m *MockProblemRepository) InvalidateSubmission(ctx context.Context, submissionID string) (*model.Submission, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateSubmission", ctx, submissionID)
+	ret0, _ := ret[0].(*model.Submission)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// InvalidateSubmission indicates an expected call of InvalidateSubmission.
+func (mr *MockProblemRepositoryMockRecorder) InvalidateSubmission(ctx, submissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateSubmission", reflect.TypeOf((*MockProblemRepository)(nil).InvalidateSubmission), ctx, submissionID)
+}
+
+// IsLeaderboardBanned mocks base method.
+func (m *MockProblemRepository) IsLeaderboardBanned(ctx context.Context, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeaderboardBanned", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLeaderboardBanned indicates an expected call of IsLeaderboardBanned.
+func (mr *MockProblemRepositoryMockRecorder) IsLeaderboardBanned(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeaderboardBanned", reflect.TypeOf((*MockProblemRepository)(nil).IsLeaderboardBanned), ctx, userID)
+}
+
+// JoinAssignment mocks base method.
+func (m *MockProblemRepository) JoinAssignment(ctx context.Context, enrollment model.AssignmentEnrollment) (*model.AssignmentEnrollment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinAssignment", ctx, enrollment)
+	ret0, _ := ret[0].(*model.AssignmentEnrollment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// JoinAssignment indicates an expected call of JoinAssignment.
+func (mr *MockProblemRepositoryMockRecorder) JoinAssignment(ctx, enrollment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinAssignment", reflect.TypeOf((*MockProblemRepository)(nil).JoinAssignment), ctx, enrollment)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockProblemRepository) ListAPIKeys(ctx context.Context, includeRevoked bool) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, includeRevoked)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockProblemRepositoryMockRecorder) ListAPIKeys(ctx, includeRevoked any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockProblemRepository)(nil).ListAPIKeys), ctx, includeRevoked)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockProblemRepository) ListDeadLetters(ctx context.Context, subject string, skip, limit int64) ([]model.DeadLetter, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, subject, skip, limit)
+	ret0, _ := ret[0].([]model.DeadLetter)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockProblemRepositoryMockRecorder) ListDeadLetters(ctx, subject, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockProblemRepository)(nil).ListDeadLetters), ctx, subject, skip, limit)
+}
+
+// ListOrganizations mocks base method.
+func (m *MockProblemRepository) ListOrganizations(ctx context.Context, kind string, skip, limit int64) ([]model.Organization, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizations", ctx, kind, skip, limit)
+	ret0, _ := ret[0].([]model.Organization)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockProblemRepositoryMockRecorder) ListOrganizations(ctx, kind, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockProblemRepository)(nil).ListOrganizations), ctx, kind, skip, limit)
+}
+
+// ListProblemReports mocks base method.
+func (m *MockProblemRepository) ListProblemReports(ctx context.Context, f model.ProblemReportFilter, skip, limit int64) ([]model.ProblemReport, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProblemReports", ctx, f, skip, limit)
+	ret0, _ := ret[0].([]model.ProblemReport)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListProblemReports indicates an expected call of ListProblemReports.
+func (mr *MockProblemRepositoryMockRecorder) ListProblemReports(ctx, f, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProblemReports", reflect.TypeOf((*MockProblemRepository)(nil).ListProblemReports), ctx, f, skip, limit)
+}
+
+// ListProblems mocks base method.
+func (m *MockProblemRepository) ListProblems(ctx context.Context, req *proto.ListProblemsRequest, excludeIDs []string) (*proto.ListProblemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProblems", ctx, req, excludeIDs)
+	ret0, _ := ret[0].(*proto.ListProblemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProblems indicates an expected call of ListProblems.
+func (mr *MockProblemRepositoryMockRecorder) ListProblems(ctx, req, excludeIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProblems", reflect.TypeOf((*MockProblemRepository)(nil).ListProblems), ctx, req, excludeIDs)
+}
+
+// ListProblemsByMaintainer mocks base method.
+func (m *MockProblemRepository) ListProblemsByMaintainer(ctx context.Context, userID string, page, pageSize int32) (*proto.ListProblemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProblemsByMaintainer", ctx, userID, page, pageSize)
+	ret0, _ := ret[0].(*proto.ListProblemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProblemsByMaintainer indicates an expected call of ListProblemsByMaintainer.
+func (mr *MockProblemRepositoryMockRecorder) ListProblemsByMaintainer(ctx, userID, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProblemsByMaintainer", reflect.TypeOf((*MockProblemRepository)(nil).ListProblemsByMaintainer), ctx, userID, page, pageSize)
+}
+
+// ListSolutions mocks base method.
+func (m *MockProblemRepository) ListSolutions(ctx context.Context, problemID, sort string, skip, limit int64) ([]model.Solution, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSolutions", ctx, problemID, sort, skip, limit)
+	ret0, _ := ret[0].([]model.Solution)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSolutions indicates an expected call of ListSolutions.
+func (mr *MockProblemRepositoryMockRecorder) ListSolutions(ctx, problemID, sort, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSolutions", reflect.TypeOf((*MockProblemRepository)(nil).ListSolutions), ctx, problemID, sort, skip, limit)
+}
+
+// ListSubmissionsCursor mocks base method.
+func (m *MockProblemRepository) ListSubmissionsCursor(ctx context.Context, f model.SubmissionFilter, cursorToken string, limit int64) ([]*proto.Submission, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubmissionsCursor", ctx, f, cursorToken, limit)
+	ret0, _ := ret[0].([]*proto.Submission)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSubmissionsCursor indicates an expected call of ListSubmissionsCursor.
+func (mr *MockProblemRepositoryMockRecorder) ListSubmissionsCursor(ctx, f, cursorToken, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubmissionsCursor", reflect.TypeOf((*MockProblemRepository)(nil).ListSubmissionsCursor), ctx, f, cursorToken, limit)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockProblemRepository) ListWebhookDeliveries(ctx context.Context, webhookID, status string, skip, limit int64) ([]model.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, webhookID, status, skip, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockProblemRepositoryMockRecorder) ListWebhookDeliveries(ctx, webhookID, status, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockProblemRepository)(nil).ListWebhookDeliveries), ctx, webhookID, status, skip, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockProblemRepository) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockProblemRepositoryMockRecorder) ListWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockProblemRepository)(nil).ListWebhooks), ctx)
+}
+
+// MarkDeadLetterReplayed mocks base method.
+func (m *MockProblemRepository) MarkDeadLetterReplayed(ctx context.Context, id primitive.ObjectID, at time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDeadLetterReplayed", ctx, id, at)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkDeadLetterReplayed indicates an expected call of MarkDeadLetterReplayed.
+func (mr *MockProblemRepositoryMockRecorder) MarkDeadLetterReplayed(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeadLetterReplayed", reflect.TypeOf((*MockProblemRepository)(nil).MarkDeadLetterReplayed), ctx, id, at)
+}
+
+// MarkOutboxEventSent mocks base method.
+func (m *MockProblemRepository) MarkOutboxEventSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventSent", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventSent indicates an expected call of MarkOutboxEventSent.
+func (mr *MockProblemRepositoryMockRecorder) MarkOutboxEventSent(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventSent", reflect.TypeOf((*MockProblemRepository)(nil).MarkOutboxEventSent), ctx, id, at)
+}
+
+// NormalizeEntitiesMongo mocks base method.
+func (m *MockProblemRepository) NormalizeEntitiesMongo(ctx context.Context, normalize func(string) string, dryRun bool) (map[string]string, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizeEntitiesMongo", ctx, normalize, dryRun)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// NormalizeEntitiesMongo indicates an expected call of NormalizeEntitiesMongo.
+func (mr *MockProblemRepositoryMockRecorder) NormalizeEntitiesMongo(ctx, normalize, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizeEntitiesMongo", reflect.TypeOf((*MockProblemRepository)(nil).NormalizeEntitiesMongo), ctx, normalize, dryRun)
+}
+
+// ProblemsDoneStatistics mocks base method.
+func (m *MockProblemRepository) ProblemsDoneStatistics(ctx context.Context, userID string) (model.ProblemsDoneStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProblemsDoneStatistics", ctx, userID)
+	ret0, _ := ret[0].(model.ProblemsDoneStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProblemsDoneStatistics indicates an expected call of ProblemsDoneStatistics.
+func (mr *MockProblemRepositoryMockRecorder) ProblemsDoneStatistics(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProblemsDoneStatistics", reflect.TypeOf((*MockProblemRepository)(nil).ProblemsDoneStatistics), ctx, userID)
+}
+
+// PurgeDeletedProblems mocks base method.
+func (m *MockProblemRepository) PurgeDeletedProblems(ctx context.Context, deletedBefore time.Time, archive bool, limit int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedProblems", ctx, deletedBefore, archive, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedProblems indicates an expected call of PurgeDeletedProblems.
+func (mr *MockProblemRepositoryMockRecorder) PurgeDeletedProblems(ctx, deletedBefore, archive, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedProblems", reflect.TypeOf((*MockProblemRepository)(nil).PurgeDeletedProblems), ctx, deletedBefore, archive, limit)
+}
+
+// PushSubmissionData mocks base method.
+func (m *MockProblemRepository) PushSubmissionData(ctx context.Context, submission *model.Submission, status string, events func(model.Submission) ([]model.OutboxEvent, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushSubmissionData", ctx, submission, status, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushSubmissionData indicates an expected call of PushSubmissionData.
+func (mr *MockProblemRepositoryMockRecorder) PushSubmissionData(ctx, submission, status, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushSubmissionData", reflect.TypeOf((*MockProblemRepository)(nil).PushSubmissionData), ctx, submission, status, events)
+}
+
+// QueryAuditLog mocks base method.
+func (m *MockProblemRepository) QueryAuditLog(ctx context.Context, f model.AuditLogFilter, skip, limit int64) ([]model.AuditEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAuditLog", ctx, f, skip, limit)
+	ret0, _ := ret[0].([]model.AuditEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryAuditLog indicates an expected call of QueryAuditLog.
+func (mr *MockProblemRepositoryMockRecorder) QueryAuditLog(ctx, f, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAuditLog", reflect.TypeOf((*MockProblemRepository)(nil).QueryAuditLog), ctx, f, skip, limit)
+}
+
+// RecalculateFirstSuccessScores mocks base method.
+func (m *MockProblemRepository) RecalculateFirstSuccessScores(ctx context.Context, dryRun bool) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateFirstSuccessScores", ctx, dryRun)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RecalculateFirstSuccessScores indicates an expected call of RecalculateFirstSuccessScores.
+func (mr *MockProblemRepositoryMockRecorder) RecalculateFirstSuccessScores(ctx, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateFirstSuccessScores", reflect.TypeOf((*MockProblemRepository)(nil).RecalculateFirstSuccessScores), ctx, dryRun)
+}
+
+// ReconcileFirstSuccess mocks base method.
+func (m *MockProblemRepository) ReconcileFirstSuccess(ctx context.Context, userID, problemID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileFirstSuccess", ctx, userID, problemID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileFirstSuccess indicates an expected call of ReconcileFirstSuccess.
+func (mr *MockProblemRepositoryMockRecorder) ReconcileFirstSuccess(ctx, userID, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileFirstSuccess", reflect.TypeOf((*MockProblemRepository)(nil).ReconcileFirstSuccess), ctx, userID, problemID)
+}
+
+// RecordOutboxEventFailure mocks base method.
+func (m *MockProblemRepository) RecordOutboxEventFailure(ctx context.Context, id primitive.ObjectID, cause error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOutboxEventFailure", ctx, id, cause)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOutboxEventFailure indicates an expected call of RecordOutboxEventFailure.
+func (mr *MockProblemRepositoryMockRecorder) RecordOutboxEventFailure(ctx, id, cause any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxEventFailure", reflect.TypeOf((*MockProblemRepository)(nil).RecordOutboxEventFailure), ctx, id, cause)
+}
+
+// RecordRejudgeProgress mocks base method.
+func (m *MockProblemRepository) RecordRejudgeProgress(ctx context.Context, id primitive.ObjectID, processed, changed, errored int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRejudgeProgress", ctx, id, processed, changed, errored)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordRejudgeProgress indicates an expected call of RecordRejudgeProgress.
+func (mr *MockProblemRepositoryMockRecorder) RecordRejudgeProgress(ctx, id, processed, changed, errored any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRejudgeProgress", reflect.TypeOf((*MockProblemRepository)(nil).RecordRejudgeProgress), ctx, id, processed, changed, errored)
+}
+
+// RecordValidationJobResult mocks base method.
+func (m *MockProblemRepository) RecordValidationJobResult(ctx context.Context, id primitive.ObjectID, result model.LanguageValidationResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordValidationJobResult", ctx, id, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordValidationJobResult indicates an expected call of RecordValidationJobResult.
+func (mr *MockProblemRepositoryMockRecorder) RecordValidationJobResult(ctx, id, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordValidationJobResult", reflect.TypeOf((*MockProblemRepository)(nil).RecordValidationJobResult), ctx, id, result)
+}
+
+// RegisterForContest mocks base method.
+func (m *MockProblemRepository) RegisterForContest(ctx context.Context, registration model.ContestRegistration) (*model.ContestRegistration, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterForContest", ctx, registration)
+	ret0, _ := ret[0].(*model.ContestRegistration)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterForContest indicates an expected call of RegisterForContest.
+func (mr *MockProblemRepositoryMockRecorder) RegisterForContest(ctx, registration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterForContest", reflect.TypeOf((*MockProblemRepository)(nil).RegisterForContest), ctx, registration)
+}
+
+// RemoveLanguageSupport mocks base method.
+func (m *MockProblemRepository) RemoveLanguageSupport(ctx context.Context, req *proto.RemoveLanguageSupportRequest) (*proto.RemoveLanguageSupportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLanguageSupport", ctx, req)
+	ret0, _ := ret[0].(*proto.RemoveLanguageSupportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLanguageSupport indicates an expected call of RemoveLanguageSupport.
+func (mr *MockProblemRepositoryMockRecorder) RemoveLanguageSupport(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLanguageSupport", reflect.TypeOf((*MockProblemRepository)(nil).RemoveLanguageSupport), ctx, req)
+}
+
+// RemoveUserOrganization mocks base method.
+func (m *MockProblemRepository) RemoveUserOrganization(ctx context.Context, userID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserOrganization", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUserOrganization indicates an expected call of RemoveUserOrganization.
+func (mr *MockProblemRepositoryMockRecorder) RemoveUserOrganization(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserOrganization", reflect.TypeOf((*MockProblemRepository)(nil).RemoveUserOrganization), ctx, userID)
+}
+
+// RepairFirstSuccess mocks base method.
+func (m *MockProblemRepository) RepairFirstSuccess(ctx context.Context, submission model.Submission) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairFirstSuccess", ctx, submission)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairFirstSuccess indicates an expected call of RepairFirstSuccess.
+func (mr *MockProblemRepositoryMockRecorder) RepairFirstSuccess(ctx, submission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairFirstSuccess", reflect.TypeOf((*MockProblemRepository)(nil).RepairFirstSuccess), ctx, submission)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockProblemRepository) RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, keyID, at)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockProblemRepositoryMockRecorder) RevokeAPIKey(ctx, keyID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockProblemRepository)(nil).RevokeAPIKey), ctx, keyID, at)
+}
+
+// SaveAuditEntry mocks base method.
+func (m *MockProblemRepository) SaveAuditEntry(ctx context.Context, entry model.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuditEntry indicates an expected call of SaveAuditEntry.
+func (mr *MockProblemRepositoryMockRecorder) SaveAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuditEntry", reflect.TypeOf((*MockProblemRepository)(nil).SaveAuditEntry), ctx, entry)
+}
+
+// SaveCodeDrafts mocks base method.
+func (m *MockProblemRepository) SaveCodeDrafts(ctx context.Context, drafts []model.CodeDraft) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCodeDrafts", ctx, drafts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCodeDrafts indicates an expected call of SaveCodeDrafts.
+func (mr *MockProblemRepositoryMockRecorder) SaveCodeDrafts(ctx, drafts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCodeDrafts", reflect.TypeOf((*MockProblemRepository)(nil).SaveCodeDrafts), ctx, drafts)
+}
+
+// SaveDeadLetter mocks base method.
+func (m *MockProblemRepository) SaveDeadLetter(ctx context.Context, letter model.DeadLetter) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeadLetter", ctx, letter)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDeadLetter indicates an expected call of SaveDeadLetter.
+func (mr *MockProblemRepositoryMockRecorder) SaveDeadLetter(ctx, letter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeadLetter", reflect.TypeOf((*MockProblemRepository)(nil).SaveDeadLetter), ctx, letter)
+}
+
+// SaveLeaderboardDailySnapshots mocks base method.
+func (m *MockProblemRepository) SaveLeaderboardDailySnapshots(ctx context.Context, snapshots []model.LeaderboardDailySnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLeaderboardDailySnapshots", ctx, snapshots)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLeaderboardDailySnapshots indicates an expected call of SaveLeaderboardDailySnapshots.
+func (mr *MockProblemRepositoryMockRecorder) SaveLeaderboardDailySnapshots(ctx, snapshots any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLeaderboardDailySnapshots", reflect.TypeOf((*MockProblemRepository)(nil).SaveLeaderboardDailySnapshots), ctx, snapshots)
+}
+
+// SaveLeaderboardSeasonSnapshot mocks base method.
+func (m *MockProblemRepository) SaveLeaderboardSeasonSnapshot(ctx context.Context, snapshot model.LeaderboardSeasonSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLeaderboardSeasonSnapshot", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLeaderboardSeasonSnapshot indicates an expected call of SaveLeaderboardSeasonSnapshot.
+func (mr *MockProblemRepositoryMockRecorder) SaveLeaderboardSeasonSnapshot(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLeaderboardSeasonSnapshot", reflect.TypeOf((*MockProblemRepository)(nil).SaveLeaderboardSeasonSnapshot), ctx, snapshot)
+}
+
+// SaveModerationAudit mocks base method.
+func (m *MockProblemRepository) SaveModerationAudit(ctx context.Context, audit model.ModerationAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveModerationAudit", ctx, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveModerationAudit indicates an expected call of SaveModerationAudit.
+func (mr *MockProblemRepositoryMockRecorder) SaveModerationAudit(ctx, audit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveModerationAudit", reflect.TypeOf((*MockProblemRepository)(nil).SaveModerationAudit), ctx, audit)
+}
+
+// SaveOutboxEvents mocks base method.
+func (m *MockProblemRepository) SaveOutboxEvents(ctx context.Context, events ...model.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveOutboxEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOutboxEvents indicates an expected call of SaveOutboxEvents.
+func (mr *MockProblemRepositoryMockRecorder) SaveOutboxEvents(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOutboxEvents", reflect.TypeOf((*MockProblemRepository)(nil).SaveOutboxEvents), varargs...)
+}
+
+// SaveSharedSubmission mocks base method.
+func (m *MockProblemRepository) SaveSharedSubmission(ctx context.Context, shared model.SharedSubmission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSharedSubmission", ctx, shared)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSharedSubmission indicates an expected call of SaveSharedSubmission.
+func (mr *MockProblemRepositoryMockRecorder) SaveSharedSubmission(ctx, shared any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSharedSubmission", reflect.TypeOf((*MockProblemRepository)(nil).SaveSharedSubmission), ctx, shared)
+}
+
+// SaveWebhookDeliveries mocks base method.
+func (m *MockProblemRepository) SaveWebhookDeliveries(ctx context.Context, deliveries ...model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range deliveries {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveWebhookDeliveries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhookDeliveries indicates an expected call of SaveWebhookDeliveries.
+func (mr *MockProblemRepositoryMockRecorder) SaveWebhookDeliveries(ctx any, deliveries ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, deliveries...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhookDeliveries", reflect.TypeOf((*MockProblemRepository)(nil).SaveWebhookDeliveries), varargs...)
+}
+
+// SetAssignmentRoster mocks base method.
+func (m *MockProblemRepository) SetAssignmentRoster(ctx context.Context, assignmentID string, roster []string) (*model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAssignmentRoster", ctx, assignmentID, roster)
+	ret0, _ := ret[0].(*model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAssignmentRoster indicates an expected call of SetAssignmentRoster.
+func (mr *MockProblemRepositoryMockRecorder) SetAssignmentRoster(ctx, assignmentID, roster any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAssignmentRoster", reflect.TypeOf((*MockProblemRepository)(nil).SetAssignmentRoster), ctx, assignmentID, roster)
+}
+
+// SetEmpiricalDifficulties mocks base method.
+func (m *MockProblemRepository) SetEmpiricalDifficulties(ctx context.Context, difficulties map[string]model.EmpiricalDifficulty) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmpiricalDifficulties", ctx, difficulties)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEmpiricalDifficulties indicates an expected call of SetEmpiricalDifficulties.
+func (mr *MockProblemRepositoryMockRecorder) SetEmpiricalDifficulties(ctx, difficulties any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmpiricalDifficulties", reflect.TypeOf((*MockProblemRepository)(nil).SetEmpiricalDifficulties), ctx, difficulties)
+}
+
+// SetExecutionProfile mocks base method.
+func (m *MockProblemRepository) SetExecutionProfile(ctx context.Context, profile model.ExecutionProfile) (*model.ExecutionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExecutionProfile", ctx, profile)
+	ret0, _ := ret[0].(*model.ExecutionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetExecutionProfile indicates an expected call of SetExecutionProfile.
+func (mr *MockProblemRepositoryMockRecorder) SetExecutionProfile(ctx, profile any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExecutionProfile", reflect.TypeOf((*MockProblemRepository)(nil).SetExecutionProfile), ctx, profile)
+}
+
+// SetFunctionSignature mocks base method.
+func (m *MockProblemRepository) SetFunctionSignature(ctx context.Context, problemID string, signature *model.FunctionSignature) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFunctionSignature", ctx, problemID, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFunctionSignature indicates an expected call of SetFunctionSignature.
+func (mr *MockProblemRepositoryMockRecorder) SetFunctionSignature(ctx, problemID, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFunctionSignature", reflect.TypeOf((*MockProblemRepository)(nil).SetFunctionSignature), ctx, problemID, signature)
+}
+
+// SetProblemAuthor mocks base method.
+func (m *MockProblemRepository) SetProblemAuthor(ctx context.Context, problemID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProblemAuthor", ctx, problemID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProblemAuthor indicates an expected call of SetProblemAuthor.
+func (mr *MockProblemRepositoryMockRecorder) SetProblemAuthor(ctx, problemID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProblemAuthor", reflect.TypeOf((*MockProblemRepository)(nil).SetProblemAuthor), ctx, problemID, userID)
+}
+
+// SetProblemTier mocks base method.
+func (m *MockProblemRepository) SetProblemTier(ctx context.Context, problemID, tier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProblemTier", ctx, problemID, tier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProblemTier indicates an expected call of SetProblemTier.
+func (mr *MockProblemRepositoryMockRecorder) SetProblemTier(ctx, problemID, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProblemTier", reflect.TypeOf((*MockProblemRepository)(nil).SetProblemTier), ctx, problemID, tier)
+}
+
+// SetSubmissionStatus mocks base method.
+func (m *MockProblemRepository) SetSubmissionStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSubmissionStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSubmissionStatus indicates an expected call of SetSubmissionStatus.
+func (mr *MockProblemRepositoryMockRecorder) SetSubmissionStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubmissionStatus", reflect.TypeOf((*MockProblemRepository)(nil).SetSubmissionStatus), ctx, id, status)
+}
+
+// SetUserOrganization mocks base method.
+func (m *MockProblemRepository) SetUserOrganization(ctx context.Context, member model.OrganizationMember) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserOrganization", ctx, member)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserOrganization indicates an expected call of SetUserOrganization.
+func (mr *MockProblemRepositoryMockRecorder) SetUserOrganization(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserOrganization", reflect.TypeOf((*MockProblemRepository)(nil).SetUserOrganization), ctx, member)
+}
+
+// SetValidationHash mocks base method.
+func (m *MockProblemRepository) SetValidationHash(ctx context.Context, problemID, language, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetValidationHash", ctx, problemID, language, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetValidationHash indicates an expected call of SetValidationHash.
+func (mr *MockProblemRepositoryMockRecorder) SetValidationHash(ctx, problemID, language, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidationHash", reflect.TypeOf((*MockProblemRepository)(nil).SetValidationHash), ctx, problemID, language, hash)
+}
+
+// StartRejudgeJob mocks base method.
+func (m *MockProblemRepository) StartRejudgeJob(ctx context.Context, job model.RejudgeJob) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRejudgeJob", ctx, job)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartRejudgeJob indicates an expected call of StartRejudgeJob.
+func (mr *MockProblemRepositoryMockRecorder) StartRejudgeJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRejudgeJob", reflect.TypeOf((*MockProblemRepository)(nil).StartRejudgeJob), ctx, job)
+}
+
+// StartValidationJob mocks base method.
+func (m *MockProblemRepository) StartValidationJob(ctx context.Context, id primitive.ObjectID, languages []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartValidationJob", ctx, id, languages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartValidationJob indicates an expected call of StartValidationJob.
+func (mr *MockProblemRepositoryMockRecorder) StartValidationJob(ctx, id, languages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartValidationJob", reflect.TypeOf((*MockProblemRepository)(nil).StartValidationJob), ctx, id, languages)
+}
+
+// StartVirtualParticipation mocks base method.
+func (m *MockProblemRepository) StartVirtualParticipation(ctx context.Context, participation model.VirtualParticipation) (*model.VirtualParticipation, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVirtualParticipation", ctx, participation)
+	ret0, _ := ret[0].(*model.VirtualParticipation)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartVirtualParticipation indicates an expected call of StartVirtualParticipation.
+func (mr *MockProblemRepositoryMockRecorder) StartVirtualParticipation(ctx, participation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVirtualParticipation", reflect.TypeOf((*MockProblemRepository)(nil).StartVirtualParticipation), ctx, participation)
+}
+
+// SyncLeaderboardToRedis mocks base method.
+func (m *MockProblemRepository) SyncLeaderboardToRedis(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncLeaderboardToRedis", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncLeaderboardToRedis indicates an expected call of SyncLeaderboardToRedis.
+func (mr *MockProblemRepositoryMockRecorder) SyncLeaderboardToRedis(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncLeaderboardToRedis", reflect.TypeOf((*MockProblemRepository)(nil).SyncLeaderboardToRedis), ctx)
+}
+
+// SyncPeriodLeaderboardToRedis mocks base method.
+func (m *MockProblemRepository) SyncPeriodLeaderboardToRedis(ctx context.Context, lb *redisboard.Leaderboard, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPeriodLeaderboardToRedis", ctx, lb, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncPeriodLeaderboardToRedis indicates an expected call of SyncPeriodLeaderboardToRedis.
+func (mr *MockProblemRepositoryMockRecorder) SyncPeriodLeaderboardToRedis(ctx, lb, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPeriodLeaderboardToRedis", reflect.TypeOf((*MockProblemRepository)(nil).SyncPeriodLeaderboardToRedis), ctx, lb, since)
+}
+
+// ToggleProblemValidaition mocks base method.
+func (m *MockProblemRepository) ToggleProblemValidaition(ctx context.Context, problemID string, status bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleProblemValidaition", ctx, problemID, status)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ToggleProblemValidaition indicates an expected call of ToggleProblemValidaition.
+func (mr *MockProblemRepositoryMockRecorder) ToggleProblemValidaition(ctx, problemID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleProblemValidaition", reflect.TypeOf((*MockProblemRepository)(nil).ToggleProblemValidaition), ctx, problemID, status)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockProblemRepository) TouchAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockProblemRepositoryMockRecorder) TouchAPIKey(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockProblemRepository)(nil).TouchAPIKey), ctx, id, at)
+}
+
+// UnhideProblem mocks base method.
+func (m *MockProblemRepository) UnhideProblem(ctx context.Context, userID, problemID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnhideProblem", ctx, userID, problemID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnhideProblem indicates an expected call of UnhideProblem.
+func (mr *MockProblemRepositoryMockRecorder) UnhideProblem(ctx, userID, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnhideProblem", reflect.TypeOf((*MockProblemRepository)(nil).UnhideProblem), ctx, userID, problemID)
+}
+
+// UpdateLanguageSupport mocks base method.
+func (m *MockProblemRepository) UpdateLanguageSupport(ctx context.Context, req *proto.UpdateLanguageSupportRequest) (*proto.UpdateLanguageSupportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLanguageSupport", ctx, req)
+	ret0, _ := ret[0].(*proto.UpdateLanguageSupportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLanguageSupport indicates an expected call of UpdateLanguageSupport.
+func (mr *MockProblemRepositoryMockRecorder) UpdateLanguageSupport(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLanguageSupport", reflect.TypeOf((*MockProblemRepository)(nil).UpdateLanguageSupport), ctx, req)
+}
+
+// UpdateProblem mocks base method.
+func (m *MockProblemRepository) UpdateProblem(ctx context.Context, req *proto.UpdateProblemRequest) (*proto.UpdateProblemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProblem", ctx, req)
+	ret0, _ := ret[0].(*proto.UpdateProblemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProblem indicates an expected call of UpdateProblem.
+func (mr *MockProblemRepositoryMockRecorder) UpdateProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProblem", reflect.TypeOf((*MockProblemRepository)(nil).UpdateProblem), ctx, req)
+}
+
+// UpdateProblemReportStatus mocks base method.
+func (m *MockProblemRepository) UpdateProblemReportStatus(ctx context.Context, reportID, fromStatus, status, note, handledBy string, fixedRevision *time.Time) (*model.ProblemReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProblemReportStatus", ctx, reportID, fromStatus, status, note, handledBy, fixedRevision)
+	ret0, _ := ret[0].(*model.ProblemReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProblemReportStatus indicates an expected call of UpdateProblemReportStatus.
+func (mr *MockProblemRepositoryMockRecorder) UpdateProblemReportStatus(ctx, reportID, fromStatus, status, note, handledBy, fixedRevision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProblemReportStatus", reflect.TypeOf((*MockProblemRepository)(nil).UpdateProblemReportStatus), ctx, reportID, fromStatus, status, note, handledBy, fixedRevision)
+}
+
+// UpdateUserEntityMongo mocks base method.
+func (m *MockProblemRepository) UpdateUserEntityMongo(ctx context.Context, userID, entity string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserEntityMongo", ctx, userID, entity)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserEntityMongo indicates an expected call of UpdateUserEntityMongo.
+func (mr *MockProblemRepositoryMockRecorder) UpdateUserEntityMongo(ctx, userID, entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserEntityMongo", reflect.TypeOf((*MockProblemRepository)(nil).UpdateUserEntityMongo), ctx, userID, entity)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockProblemRepository) UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockProblemRepositoryMockRecorder) UpdateWebhookDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockProblemRepository)(nil).UpdateWebhookDelivery), ctx, delivery)
+}
+
+// UpsertProblemTranslation mocks base method.
+func (m *MockProblemRepository) UpsertProblemTranslation(ctx context.Context, problemID, locale string, translation model.ProblemTranslation) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertProblemTranslation", ctx, problemID, locale, translation)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertProblemTranslation indicates an expected call of UpsertProblemTranslation.
+func (mr *MockProblemRepositoryMockRecorder) UpsertProblemTranslation(ctx, problemID, locale, translation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertProblemTranslation", reflect.TypeOf((*MockProblemRepository)(nil).UpsertProblemTranslation), ctx, problemID, locale, translation)
+}
+
+// VoteSolution mocks base method.
+func (m *MockProblemRepository) VoteSolution(ctx context.Context, solutionID, userID string, value int) (*model.Solution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteSolution", ctx, solutionID, userID, value)
+	ret0, _ := ret[0].(*model.Solution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoteSolution indicates an expected call of VoteSolution.
+func (mr *MockProblemRepositoryMockRecorder) VoteSolution(ctx, solutionID, userID, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteSolution", reflect.TypeOf((*MockProblemRepository)(nil).VoteSolution), ctx, solutionID, userID, value)
+}
+
+// WithTransaction mocks base method.
+func (m *MockProblemRepository) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockProblemRepositoryMockRecorder) WithTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockProblemRepository)(nil).WithTransaction), ctx, fn)
+}
+
+// MockProblemStore is a mock of ProblemStore interface.
+type MockProblemStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockProblemStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockProblemStoreMockRecorder is the mock recorder for MockProblemStore.
+type MockProblemStoreMockRecorder struct {
+	mock *MockProblemStore
+}
+
+// NewMockProblemStore creates a new mock instance.
+func NewMockProblemStore(ctrl *gomock.Controller) *MockProblemStore {
+	mock := &MockProblemStore{ctrl: ctrl}
+	mock.recorder = &MockProblemStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProblemStore) EXPECT() *MockProblemStoreMockRecorder {
+	return m.recorder
+}
+
+// AddLanguageSupport mocks base method.
+func (m *MockProblemStore) AddLanguageSupport(ctx context.Context, req *proto.AddLanguageSupportRequest) (*proto.AddLanguageSupportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLanguageSupport", ctx, req)
+	ret0, _ := ret[0].(*proto.AddLanguageSupportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLanguageSupport indicates an expected call of AddLanguageSupport.
+func (mr *MockProblemStoreMockRecorder) AddLanguageSupport(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLanguageSupport", reflect.TypeOf((*MockProblemStore)(nil).AddLanguageSupport), ctx, req)
+}
+
+// AddProblemMaintainer mocks base method.
+func (m *MockProblemStore) AddProblemMaintainer(ctx context.Context, problemID, userID string) ([]string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProblemMaintainer", ctx, problemID, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AddProblemMaintainer indicates an expected call of AddProblemMaintainer.
+func (mr *MockProblemStoreMockRecorder) AddProblemMaintainer(ctx, problemID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProblemMaintainer", reflect.TypeOf((*MockProblemStore)(nil).AddProblemMaintainer), ctx, problemID, userID)
+}
+
+// AddTestCases mocks base method.
+func (m *MockProblemStore) AddTestCases(ctx context.Context, req *proto.AddTestCasesRequest) (*proto.AddTestCasesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTestCases", ctx, req)
+	ret0, _ := ret[0].(*proto.AddTestCasesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTestCases indicates an expected call of AddTestCases.
+func (mr *MockProblemStoreMockRecorder) AddTestCases(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTestCases", reflect.TypeOf((*MockProblemStore)(nil).AddTestCases), ctx, req)
+}
+
+// BasicValidationByProblemID mocks base method.
+func (m *MockProblemStore) BasicValidationByProblemID(ctx context.Context, req *proto.FullValidationByProblemIDRequest) (*proto.FullValidationByProblemIDResponse, model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BasicValidationByProblemID", ctx, req)
+	ret0, _ := ret[0].(*proto.FullValidationByProblemIDResponse)
+	ret1, _ := ret[1].(model.Problem)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BasicValidationByProblemID indicates an expected call of BasicValidationByProblemID.
+func (mr *MockProblemStoreMockRecorder) BasicValidationByProblemID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BasicValidationByProblemID", reflect.TypeOf((*MockProblemStore)(nil).BasicValidationByProblemID), ctx, req)
+}
+
+// CreateProblem mocks base method.
+func (m *MockProblemStore) CreateProblem(ctx context.Context, req *proto.CreateProblemRequest) (*proto.CreateProblemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProblem", ctx, req)
+	ret0, _ := ret[0].(*proto.CreateProblemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProblem indicates an expected call of CreateProblem.
+func (mr *MockProblemStoreMockRecorder) CreateProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProblem", reflect.TypeOf((*MockProblemStore)(nil).CreateProblem), ctx, req)
+}
+
+// DeleteProblem mocks base method.
+func (m *MockProblemStore) DeleteProblem(ctx context.Context, req *proto.DeleteProblemRequest) (*proto.DeleteProblemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProblem", ctx, req)
+	ret0, _ := ret[0].(*proto.DeleteProblemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProblem indicates an expected call of DeleteProblem.
+func (mr *MockProblemStoreMockRecorder) DeleteProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProblem", reflect.TypeOf((*MockProblemStore)(nil).DeleteProblem), ctx, req)
+}
+
+// DeleteTestCase mocks base method.
+func (m *MockProblemStore) DeleteTestCase(ctx context.Context, req *proto.DeleteTestCaseRequest) (*proto.DeleteTestCaseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTestCase", ctx, req)
+	ret0, _ := ret[0].(*proto.DeleteTestCaseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTestCase indicates an expected call of DeleteTestCase.
+func (mr *MockProblemStoreMockRecorder) DeleteTestCase(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTestCase", reflect.TypeOf((*MockProblemStore)(nil).DeleteTestCase), ctx, req)
+}
+
+// GetBulkProblemMetadata mocks base method.
+func (m *MockProblemStore) GetBulkProblemMetadata(ctx context.Context, req *proto.GetBulkProblemMetadataRequest) (*proto.GetBulkProblemMetadataResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkProblemMetadata", ctx, req)
+	ret0, _ := ret[0].(*proto.GetBulkProblemMetadataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkProblemMetadata indicates an expected call of GetBulkProblemMetadata.
+func (mr *MockProblemStoreMockRecorder) GetBulkProblemMetadata(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkProblemMetadata", reflect.TypeOf((*MockProblemStore)(nil).GetBulkProblemMetadata), ctx, req)
+}
+
+// GetHiddenProblemIDs mocks base method.
+func (m *MockProblemStore) GetHiddenProblemIDs(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHiddenProblemIDs", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHiddenProblemIDs indicates an expected call of GetHiddenProblemIDs.
+func (mr *MockProblemStoreMockRecorder) GetHiddenProblemIDs(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHiddenProblemIDs", reflect.TypeOf((*MockProblemStore)(nil).GetHiddenProblemIDs), ctx, userID)
+}
+
+// GetLanguageSupports mocks base method.
+func (m *MockProblemStore) GetLanguageSupports(ctx context.Context, req *proto.GetLanguageSupportsRequest) (*proto.GetLanguageSupportsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLanguageSupports", ctx, req)
+	ret0, _ := ret[0].(*proto.GetLanguageSupportsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLanguageSupports indicates an expected call of GetLanguageSupports.
+func (mr *MockProblemStoreMockRecorder) GetLanguageSupports(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLanguageSupports", reflect.TypeOf((*MockProblemStore)(nil).GetLanguageSupports), ctx, req)
+}
+
+// GetMislabeledProblems mocks base method.
+func (m *MockProblemStore) GetMislabeledProblems(ctx context.Context) ([]model.ProblemDifficulty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMislabeledProblems", ctx)
+	ret0, _ := ret[0].([]model.ProblemDifficulty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMislabeledProblems indicates an expected call of GetMislabeledProblems.
+func (mr *MockProblemStoreMockRecorder) GetMislabeledProblems(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMislabeledProblems", reflect.TypeOf((*MockProblemStore)(nil).GetMislabeledProblems), ctx)
+}
+
+// GetPremiumProblemIDs mocks base method.
+func (m *MockProblemStore) GetPremiumProblemIDs(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPremiumProblemIDs", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPremiumProblemIDs indicates an expected call of GetPremiumProblemIDs.
+func (mr *MockProblemStoreMockRecorder) GetPremiumProblemIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPremiumProblemIDs", reflect.TypeOf((*MockProblemStore)(nil).GetPremiumProblemIDs), ctx)
+}
+
+// GetProblem mocks base method.
+func (m *MockProblemStore) GetProblem(ctx context.Context, req *proto.GetProblemRequest) (*model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblem", ctx, req)
+	ret0, _ := ret[0].(*model.Problem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblem indicates an expected call of GetProblem.
+func (mr *MockProblemStoreMockRecorder) GetProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblem", reflect.TypeOf((*MockProblemStore)(nil).GetProblem), ctx, req)
+}
+
+// GetProblemByIDList mocks base method.
+func (m *MockProblemStore) GetProblemByIDList(ctx context.Context, req *proto.GetProblemMetadataListRequest) (*proto.GetProblemMetadataListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemByIDList", ctx, req)
+	ret0, _ := ret[0].(*proto.GetProblemMetadataListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemByIDList indicates an expected call of GetProblemByIDList.
+func (mr *MockProblemStoreMockRecorder) GetProblemByIDList(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemByIDList", reflect.TypeOf((*MockProblemStore)(nil).GetProblemByIDList), ctx, req)
+}
+
+// GetProblemByIDSlug mocks base method.
+func (m *MockProblemStore) GetProblemByIDSlug(ctx context.Context, req *proto.GetProblemByIdSlugRequest) (*proto.GetProblemByIdSlugResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemByIDSlug", ctx, req)
+	ret0, _ := ret[0].(*proto.GetProblemByIdSlugResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemByIDSlug indicates an expected call of GetProblemByIDSlug.
+func (mr *MockProblemStoreMockRecorder) GetProblemByIDSlug(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemByIDSlug", reflect.TypeOf((*MockProblemStore)(nil).GetProblemByIDSlug), ctx, req)
+}
+
+// GetProblemDifficulties mocks base method.
+func (m *MockProblemStore) GetProblemDifficulties(ctx context.Context) ([]model.ProblemDifficulty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemDifficulties", ctx)
+	ret0, _ := ret[0].([]model.ProblemDifficulty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemDifficulties indicates an expected call of GetProblemDifficulties.
+func (mr *MockProblemStoreMockRecorder) GetProblemDifficulties(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemDifficulties", reflect.TypeOf((*MockProblemStore)(nil).GetProblemDifficulties), ctx)
+}
+
+// GetProblemDifficulty mocks base method.
+func (m *MockProblemStore) GetProblemDifficulty(ctx context.Context, problemID string) (*model.ProblemDifficulty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemDifficulty", ctx, problemID)
+	ret0, _ := ret[0].(*model.ProblemDifficulty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemDifficulty indicates an expected call of GetProblemDifficulty.
+func (mr *MockProblemStoreMockRecorder) GetProblemDifficulty(ctx, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemDifficulty", reflect.TypeOf((*MockProblemStore)(nil).GetProblemDifficulty), ctx, problemID)
+}
+
+// GetProblemTranslations mocks base method.
+func (m *MockProblemStore) GetProblemTranslations(ctx context.Context, problemID string) (map[string]model.ProblemTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemTranslations", ctx, problemID)
+	ret0, _ := ret[0].(map[string]model.ProblemTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemTranslations indicates an expected call of GetProblemTranslations.
+func (mr *MockProblemStoreMockRecorder) GetProblemTranslations(ctx, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemTranslations", reflect.TypeOf((*MockProblemStore)(nil).GetProblemTranslations), ctx, problemID)
+}
+
+// GetProblemsByIDs mocks base method.
+func (m *MockProblemStore) GetProblemsByIDs(ctx context.Context, problemIDs []string) ([]model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemsByIDs", ctx, problemIDs)
+	ret0, _ := ret[0].([]model.Problem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemsByIDs indicates an expected call of GetProblemsByIDs.
+func (mr *MockProblemStoreMockRecorder) GetProblemsByIDs(ctx, problemIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemsByIDs", reflect.TypeOf((*MockProblemStore)(nil).GetProblemsByIDs), ctx, problemIDs)
+}
+
+// GetRandomProblem mocks base method.
+func (m *MockProblemStore) GetRandomProblem(ctx context.Context, f model.RandomProblemFilter) (*model.Problem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRandomProblem", ctx, f)
+	ret0, _ := ret[0].(*model.Problem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRandomProblem indicates an expected call of GetRandomProblem.
+func (mr *MockProblemStoreMockRecorder) GetRandomProblem(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandomProblem", reflect.TypeOf((*MockProblemStore)(nil).GetRandomProblem), ctx, f)
+}
+
+// HideProblem mocks base method.
+func (m *MockProblemStore) HideProblem(ctx context.Context, userID, problemID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideProblem", ctx, userID, problemID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HideProblem indicates an expected call of HideProblem.
+func (mr *MockProblemStoreMockRecorder) HideProblem(ctx, userID, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideProblem", reflect.TypeOf((*MockProblemStore)(nil).HideProblem), ctx, userID, problemID)
+}
+
+// ListProblems mocks base method.
+func (m *MockProblemStore) ListProblems(ctx context.Context, req *proto.ListProblemsRequest, excludeIDs []string) (*proto.ListProblemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProblems", ctx, req, excludeIDs)
+	ret0, _ := ret[0].(*proto.ListProblemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProblems indicates an expected call of ListProblems.
+func (mr *MockProblemStoreMockRecorder) ListProblems(ctx, req, excludeIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProblems", reflect.TypeOf((*MockProblemStore)(nil).ListProblems), ctx, req, excludeIDs)
+}
+
+// ListProblemsByMaintainer mocks base method.
+func (m *MockProblemStore) ListProblemsByMaintainer(ctx context.Context, userID string, page, pageSize int32) (*proto.ListProblemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProblemsByMaintainer", ctx, userID, page, pageSize)
+	ret0, _ := ret[0].(*proto.ListProblemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProblemsByMaintainer indicates an expected call of ListProblemsByMaintainer.
+func (mr *MockProblemStoreMockRecorder) ListProblemsByMaintainer(ctx, userID, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProblemsByMaintainer", reflect.TypeOf((*MockProblemStore)(nil).ListProblemsByMaintainer), ctx, userID, page, pageSize)
+}
+
+// RemoveLanguageSupport mocks base method.
+func (m *MockProblemStore) RemoveLanguageSupport(ctx context.Context, req *proto.RemoveLanguageSupportRequest) (*proto.RemoveLanguageSupportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLanguageSupport", ctx, req)
+	ret0, _ := ret[0].(*proto.RemoveLanguageSupportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveLanguageSupport indicates an expected call of RemoveLanguageSupport.
+func (mr *MockProblemStoreMockRecorder) RemoveLanguageSupport(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLanguageSupport", reflect.TypeOf((*MockProblemStore)(nil).RemoveLanguageSupport), ctx, req)
+}
+
+// SetEmpiricalDifficulties mocks base method.
+func (m *MockProblemStore) SetEmpiricalDifficulties(ctx context.Context, difficulties map[string]model.EmpiricalDifficulty) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmpiricalDifficulties", ctx, difficulties)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEmpiricalDifficulties indicates an expected call of SetEmpiricalDifficulties.
+func (mr *MockProblemStoreMockRecorder) SetEmpiricalDifficulties(ctx, difficulties any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmpiricalDifficulties", reflect.TypeOf((*MockProblemStore)(nil).SetEmpiricalDifficulties), ctx, difficulties)
+}
+
+// SetFunctionSignature mocks base method.
+func (m *MockProblemStore) SetFunctionSignature(ctx context.Context, problemID string, signature *model.FunctionSignature) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFunctionSignature", ctx, problemID, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFunctionSignature indicates an expected call of SetFunctionSignature.
+func (mr *MockProblemStoreMockRecorder) SetFunctionSignature(ctx, problemID, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFunctionSignature", reflect.TypeOf((*MockProblemStore)(nil).SetFunctionSignature), ctx, problemID, signature)
+}
+
+// SetProblemAuthor mocks base method.
+func (m *MockProblemStore) SetProblemAuthor(ctx context.Context, problemID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProblemAuthor", ctx, problemID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProblemAuthor indicates an expected call of SetProblemAuthor.
+func (mr *MockProblemStoreMockRecorder) SetProblemAuthor(ctx, problemID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProblemAuthor", reflect.TypeOf((*MockProblemStore)(nil).SetProblemAuthor), ctx, problemID, userID)
+}
+
+// SetProblemTier mocks base method.
+func (m *MockProblemStore) SetProblemTier(ctx context.Context, problemID, tier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProblemTier", ctx, problemID, tier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProblemTier indicates an expected call of SetProblemTier.
+func (mr *MockProblemStoreMockRecorder) SetProblemTier(ctx, problemID, tier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProblemTier", reflect.TypeOf((*MockProblemStore)(nil).SetProblemTier), ctx, problemID, tier)
+}
+
+// SetValidationHash mocks base method.
+func (m *MockProblemStore) SetValidationHash(ctx context.Context, problemID, language, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetValidationHash", ctx, problemID, language, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetValidationHash indicates an expected call of SetValidationHash.
+func (mr *MockProblemStoreMockRecorder) SetValidationHash(ctx, problemID, language, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidationHash", reflect.TypeOf((*MockProblemStore)(nil).SetValidationHash), ctx, problemID, language, hash)
+}
+
+// ToggleProblemValidaition mocks base method.
+func (m *MockProblemStore) ToggleProblemValidaition(ctx context.Context, problemID string, status bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleProblemValidaition", ctx, problemID, status)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ToggleProblemValidaition indicates an expected call of ToggleProblemValidaition.
+func (mr *MockProblemStoreMockRecorder) ToggleProblemValidaition(ctx, problemID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleProblemValidaition", reflect.TypeOf((*MockProblemStore)(nil).ToggleProblemValidaition), ctx, problemID, status)
+}
+
+// UnhideProblem mocks base method.
+func (m *MockProblemStore) UnhideProblem(ctx context.Context, userID, problemID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnhideProblem", ctx, userID, problemID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnhideProblem indicates an expected call of UnhideProblem.
+func (mr *MockProblemStoreMockRecorder) UnhideProblem(ctx, userID, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnhideProblem", reflect.TypeOf((*MockProblemStore)(nil).UnhideProblem), ctx, userID, problemID)
+}
+
+// UpdateLanguageSupport mocks base method.
+func (m *MockProblemStore) UpdateLanguageSupport(ctx context.Context, req *proto.UpdateLanguageSupportRequest) (*proto.UpdateLanguageSupportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLanguageSupport", ctx, req)
+	ret0, _ := ret[0].(*proto.UpdateLanguageSupportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLanguageSupport indicates an expected call of UpdateLanguageSupport.
+func (mr *MockProblemStoreMockRecorder) UpdateLanguageSupport(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLanguageSupport", reflect.TypeOf((*MockProblemStore)(nil).UpdateLanguageSupport), ctx, req)
+}
+
+// UpdateProblem mocks base method.
+func (m *MockProblemStore) UpdateProblem(ctx context.Context, req *proto.UpdateProblemRequest) (*proto.UpdateProblemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProblem", ctx, req)
+	ret0, _ := ret[0].(*proto.UpdateProblemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProblem indicates an expected call of UpdateProblem.
+func (mr *MockProblemStoreMockRecorder) UpdateProblem(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProblem", reflect.TypeOf((*MockProblemStore)(nil).UpdateProblem), ctx, req)
+}
+
+// UpsertProblemTranslation mocks base method.
+func (m *MockProblemStore) UpsertProblemTranslation(ctx context.Context, problemID, locale string, translation model.ProblemTranslation) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertProblemTranslation", ctx, problemID, locale, translation)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertProblemTranslation indicates an expected call of UpsertProblemTranslation.
+func (mr *MockProblemStoreMockRecorder) UpsertProblemTranslation(ctx, problemID, locale, translation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertProblemTranslation", reflect.TypeOf((*MockProblemStore)(nil).UpsertProblemTranslation), ctx, problemID, locale, translation)
+}
+
+// MockSubmissionStore is a mock of SubmissionStore interface.
+type MockSubmissionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubmissionStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockSubmissionStoreMockRecorder is the mock recorder for MockSubmissionStore.
+type MockSubmissionStoreMockRecorder struct {
+	mock *MockSubmissionStore
+}
+
+// NewMockSubmissionStore creates a new mock instance.
+func NewMockSubmissionStore(ctrl *gomock.Controller) *MockSubmissionStore {
+	mock := &MockSubmissionStore{ctrl: ctrl}
+	mock.recorder = &MockSubmissionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubmissionStore) EXPECT() *MockSubmissionStoreMockRecorder {
+	return m.recorder
+}
+
+// GetBestSubmissions mocks base method.
+func (m *MockSubmissionStore) GetBestSubmissions(ctx context.Context, userID string, byLanguage bool) ([]*proto.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBestSubmissions", ctx, userID, byLanguage)
+	ret0, _ := ret[0].([]*proto.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBestSubmissions indicates an expected call of GetBestSubmissions.
+func (mr *MockSubmissionStoreMockRecorder) GetBestSubmissions(ctx, userID, byLanguage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestSubmissions", reflect.TypeOf((*MockSubmissionStore)(nil).GetBestSubmissions), ctx, userID, byLanguage)
+}
+
+// GetCodeDraft mocks base method.
+func (m *MockSubmissionStore) GetCodeDraft(ctx context.Context, id string) (*model.CodeDraft, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCodeDraft", ctx, id)
+	ret0, _ := ret[0].(*model.CodeDraft)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCodeDraft indicates an expected call of GetCodeDraft.
+func (mr *MockSubmissionStoreMockRecorder) GetCodeDraft(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCodeDraft", reflect.TypeOf((*MockSubmissionStore)(nil).GetCodeDraft), ctx, id)
+}
+
+// GetDailySubmissionCounts mocks base method.
+func (m *MockSubmissionStore) GetDailySubmissionCounts(ctx context.Context, userID string, from, to time.Time) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailySubmissionCounts", ctx, userID, from, to)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailySubmissionCounts indicates an expected call of GetDailySubmissionCounts.
+func (mr *MockSubmissionStoreMockRecorder) GetDailySubmissionCounts(ctx, userID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailySubmissionCounts", reflect.TypeOf((*MockSubmissionStore)(nil).GetDailySubmissionCounts), ctx, userID, from, to)
+}
+
+// GetFirstSolvers mocks base method.
+func (m *MockSubmissionStore) GetFirstSolvers(ctx context.Context, problemIDs []string, challengeID string) ([]model.FirstSolver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstSolvers", ctx, problemIDs, challengeID)
+	ret0, _ := ret[0].([]model.FirstSolver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstSolvers indicates an expected call of GetFirstSolvers.
+func (mr *MockSubmissionStoreMockRecorder) GetFirstSolvers(ctx, problemIDs, challengeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstSolvers", reflect.TypeOf((*MockSubmissionStore)(nil).GetFirstSolvers), ctx, problemIDs, challengeID)
+}
+
+// GetMonthlyContributionHistory mocks base method.
+func (m *MockSubmissionStore) GetMonthlyContributionHistory(ctx context.Context, userID string, month, year int) (model.MonthlyActivityHeatmapProps, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyContributionHistory", ctx, userID, month, year)
+	ret0, _ := ret[0].(model.MonthlyActivityHeatmapProps)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyContributionHistory indicates an expected call of GetMonthlyContributionHistory.
+func (mr *MockSubmissionStoreMockRecorder) GetMonthlyContributionHistory(ctx, userID, month, year any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyContributionHistory", reflect.TypeOf((*MockSubmissionStore)(nil).GetMonthlyContributionHistory), ctx, userID, month, year)
+}
+
+// GetProblemSolveStats mocks base method.
+func (m *MockSubmissionStore) GetProblemSolveStats(ctx context.Context) ([]model.ProblemSolveStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemSolveStats", ctx)
+	ret0, _ := ret[0].([]model.ProblemSolveStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemSolveStats indicates an expected call of GetProblemSolveStats.
+func (mr *MockSubmissionStoreMockRecorder) GetProblemSolveStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemSolveStats", reflect.TypeOf((*MockSubmissionStore)(nil).GetProblemSolveStats), ctx)
+}
+
+// GetProblemStatuses mocks base method.
+func (m *MockSubmissionStore) GetProblemStatuses(ctx context.Context, userID string, problemIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemStatuses", ctx, userID, problemIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemStatuses indicates an expected call of GetProblemStatuses.
+func (mr *MockSubmissionStoreMockRecorder) GetProblemStatuses(ctx, userID, problemIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemStatuses", reflect.TypeOf((*MockSubmissionStore)(nil).GetProblemStatuses), ctx, userID, problemIDs)
+}
+
+// GetSharedSubmission mocks base method.
+func (m *MockSubmissionStore) GetSharedSubmission(ctx context.Context, token string) (*model.SharedSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedSubmission", ctx, token)
+	ret0, _ := ret[0].(*model.SharedSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedSubmission indicates an expected call of GetSharedSubmission.
+func (mr *MockSubmissionStoreMockRecorder) GetSharedSubmission(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedSubmission", reflect.TypeOf((*MockSubmissionStore)(nil).GetSharedSubmission), ctx, token)
+}
+
+// GetSubmissionByID mocks base method.
+func (m *MockSubmissionStore) GetSubmissionByID(ctx context.Context, submissionID string) (*model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubmissionByID", ctx, submissionID)
+	ret0, _ := ret[0].(*model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubmissionByID indicates an expected call of GetSubmissionByID.
+func (mr *MockSubmissionStoreMockRecorder) GetSubmissionByID(ctx, submissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubmissionByID", reflect.TypeOf((*MockSubmissionStore)(nil).GetSubmissionByID), ctx, submissionID)
+}
+
+// GetSubmissionsByOptionalProblemID mocks base method.
+func (m *MockSubmissionStore) GetSubmissionsByOptionalProblemID(ctx context.Context, req *proto.GetSubmissionsRequest) (*proto.GetSubmissionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubmissionsByOptionalProblemID", ctx, req)
+	ret0, _ := ret[0].(*proto.GetSubmissionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubmissionsByOptionalProblemID indicates an expected call of GetSubmissionsByOptionalProblemID.
+func (mr *MockSubmissionStoreMockRecorder) GetSubmissionsByOptionalProblemID(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubmissionsByOptionalProblemID", reflect.TypeOf((*MockSubmissionStore)(nil).GetSubmissionsByOptionalProblemID), ctx, req)
+}
+
+// GetUserLanguageStats mocks base method.
+func (m *MockSubmissionStore) GetUserLanguageStats(ctx context.Context, userID string) ([]model.LanguageStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLanguageStats", ctx, userID)
+	ret0, _ := ret[0].([]model.LanguageStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLanguageStats indicates an expected call of GetUserLanguageStats.
+func (mr *MockSubmissionStoreMockRecorder) GetUserLanguageStats(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLanguageStats", reflect.TypeOf((*MockSubmissionStore)(nil).GetUserLanguageStats), ctx, userID)
+}
+
+// ListSubmissionsCursor mocks base method.
+func (m *MockSubmissionStore) ListSubmissionsCursor(ctx context.Context, f model.SubmissionFilter, cursorToken string, limit int64) ([]*proto.Submission, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubmissionsCursor", ctx, f, cursorToken, limit)
+	ret0, _ := ret[0].([]*proto.Submission)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSubmissionsCursor indicates an expected call of ListSubmissionsCursor.
+func (mr *MockSubmissionStoreMockRecorder) ListSubmissionsCursor(ctx, f, cursorToken, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubmissionsCursor", reflect.TypeOf((*MockSubmissionStore)(nil).ListSubmissionsCursor), ctx, f, cursorToken, limit)
+}
+
+// ProblemsDoneStatistics mocks base method.
+func (m *MockSubmissionStore) ProblemsDoneStatistics(ctx context.Context, userID string) (model.ProblemsDoneStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProblemsDoneStatistics", ctx, userID)
+	ret0, _ := ret[0].(model.ProblemsDoneStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProblemsDoneStatistics indicates an expected call of ProblemsDoneStatistics.
+func (mr *MockSubmissionStoreMockRecorder) ProblemsDoneStatistics(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProblemsDoneStatistics", reflect.TypeOf((*MockSubmissionStore)(nil).ProblemsDoneStatistics), ctx, userID)
+}
+
+// PushSubmissionData mocks base method.
+func (m *MockSubmissionStore) PushSubmissionData(ctx context.Context, submission *model.Submission, status string, events func(model.Submission) ([]model.OutboxEvent, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushSubmissionData", ctx, submission, status, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushSubmissionData indicates an expected call of PushSubmissionData.
+func (mr *MockSubmissionStoreMockRecorder) PushSubmissionData(ctx, submission, status, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushSubmissionData", reflect.TypeOf((*MockSubmissionStore)(nil).PushSubmissionData), ctx, submission, status, events)
+}
+
+// SaveCodeDrafts mocks base method.
+func (m *MockSubmissionStore) SaveCodeDrafts(ctx context.Context, drafts []model.CodeDraft) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCodeDrafts", ctx, drafts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCodeDrafts indicates an expected call of SaveCodeDrafts.
+func (mr *MockSubmissionStoreMockRecorder) SaveCodeDrafts(ctx, drafts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCodeDrafts", reflect.TypeOf((*MockSubmissionStore)(nil).SaveCodeDrafts), ctx, drafts)
+}
+
+// SaveSharedSubmission mocks base method.
+func (m *MockSubmissionStore) SaveSharedSubmission(ctx context.Context, shared model.SharedSubmission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSharedSubmission", ctx, shared)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSharedSubmission indicates an expected call of SaveSharedSubmission.
+func (mr *MockSubmissionStoreMockRecorder) SaveSharedSubmission(ctx, shared any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSharedSubmission", reflect.TypeOf((*MockSubmissionStore)(nil).SaveSharedSubmission), ctx, shared)
+}
+
+// MockLeaderboardStore is a mock of LeaderboardStore interface.
+type MockLeaderboardStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderboardStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockLeaderboardStoreMockRecorder is the mock recorder for MockLeaderboardStore.
+type MockLeaderboardStoreMockRecorder struct {
+	mock *MockLeaderboardStore
+}
+
+// NewMockLeaderboardStore creates a new mock instance.
+func NewMockLeaderboardStore(ctrl *gomock.Controller) *MockLeaderboardStore {
+	mock := &MockLeaderboardStore{ctrl: ctrl}
+	mock.recorder = &MockLeaderboardStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderboardStore) EXPECT() *MockLeaderboardStoreMockRecorder {
+	return m.recorder
+}
+
+// ApplyPendingLeaderboardUpdates mocks base method.
+func (m *MockLeaderboardStore) ApplyPendingLeaderboardUpdates(ctx context.Context, limit int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPendingLeaderboardUpdates", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyPendingLeaderboardUpdates indicates an expected call of ApplyPendingLeaderboardUpdates.
+func (mr *MockLeaderboardStoreMockRecorder) ApplyPendingLeaderboardUpdates(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPendingLeaderboardUpdates", reflect.TypeOf((*MockLeaderboardStore)(nil).ApplyPendingLeaderboardUpdates), ctx, limit)
+}
+
+// GetEntityStandingsMongo mocks base method.
+func (m *MockLeaderboardStore) GetEntityStandingsMongo(ctx context.Context, perEntity int) (map[string][]model.RankedUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityStandingsMongo", ctx, perEntity)
+	ret0, _ := ret[0].(map[string][]model.RankedUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntityStandingsMongo indicates an expected call of GetEntityStandingsMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetEntityStandingsMongo(ctx, perEntity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityStandingsMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetEntityStandingsMongo), ctx, perEntity)
+}
+
+// GetEntityStatsMongo mocks base method.
+func (m *MockLeaderboardStore) GetEntityStatsMongo(ctx context.Context, moversSince time.Time, moversPerEntity int) ([]model.EntityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityStatsMongo", ctx, moversSince, moversPerEntity)
+	ret0, _ := ret[0].([]model.EntityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntityStatsMongo indicates an expected call of GetEntityStatsMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetEntityStatsMongo(ctx, moversSince, moversPerEntity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityStatsMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetEntityStatsMongo), ctx, moversSince, moversPerEntity)
+}
+
+// GetInactiveUserTotalsMongo mocks base method.
+func (m *MockLeaderboardStore) GetInactiveUserTotalsMongo(ctx context.Context, inactiveSince, since time.Time) ([]model.InactiveUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInactiveUserTotalsMongo", ctx, inactiveSince, since)
+	ret0, _ := ret[0].([]model.InactiveUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInactiveUserTotalsMongo indicates an expected call of GetInactiveUserTotalsMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetInactiveUserTotalsMongo(ctx, inactiveSince, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInactiveUserTotalsMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetInactiveUserTotalsMongo), ctx, inactiveSince, since)
+}
+
+// GetLeaderboardDailySnapshots mocks base method.
+func (m *MockLeaderboardStore) GetLeaderboardDailySnapshots(ctx context.Context, scope string, from time.Time) ([]model.LeaderboardDailySnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardDailySnapshots", ctx, scope, from)
+	ret0, _ := ret[0].([]model.LeaderboardDailySnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboardDailySnapshots indicates an expected call of GetLeaderboardDailySnapshots.
+func (mr *MockLeaderboardStoreMockRecorder) GetLeaderboardDailySnapshots(ctx, scope, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardDailySnapshots", reflect.TypeOf((*MockLeaderboardStore)(nil).GetLeaderboardDailySnapshots), ctx, scope, from)
+}
+
+// GetLeaderboardDataMongo mocks base method.
+func (m *MockLeaderboardStore) GetLeaderboardDataMongo(ctx context.Context, userID string) (*model.UserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardDataMongo", ctx, userID)
+	ret0, _ := ret[0].(*model.UserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboardDataMongo indicates an expected call of GetLeaderboardDataMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetLeaderboardDataMongo(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardDataMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetLeaderboardDataMongo), ctx, userID)
+}
+
+// GetLeaderboardPageMongo mocks base method.
+func (m *MockLeaderboardStore) GetLeaderboardPageMongo(ctx context.Context, entity string, skip, limit int64) ([]model.RankedUserScore, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardPageMongo", ctx, entity, skip, limit)
+	ret0, _ := ret[0].([]model.RankedUserScore)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLeaderboardPageMongo indicates an expected call of GetLeaderboardPageMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetLeaderboardPageMongo(ctx, entity, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardPageMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetLeaderboardPageMongo), ctx, entity, skip, limit)
+}
+
+// GetStandingsBetweenMongo mocks base method.
+func (m *MockLeaderboardStore) GetStandingsBetweenMongo(ctx context.Context, from, to time.Time, limit int) ([]model.RankedUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStandingsBetweenMongo", ctx, from, to, limit)
+	ret0, _ := ret[0].([]model.RankedUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStandingsBetweenMongo indicates an expected call of GetStandingsBetweenMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetStandingsBetweenMongo(ctx, from, to, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStandingsBetweenMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetStandingsBetweenMongo), ctx, from, to, limit)
+}
+
+// GetTopKEntityMongo mocks base method.
+func (m *MockLeaderboardStore) GetTopKEntityMongo(ctx context.Context, entity string, k int) ([]model.UserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopKEntityMongo", ctx, entity, k)
+	ret0, _ := ret[0].([]model.UserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopKEntityMongo indicates an expected call of GetTopKEntityMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetTopKEntityMongo(ctx, entity, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopKEntityMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetTopKEntityMongo), ctx, entity, k)
+}
+
+// GetTopKGlobalMongo mocks base method.
+func (m *MockLeaderboardStore) GetTopKGlobalMongo(ctx context.Context, k int) ([]model.UserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopKGlobalMongo", ctx, k)
+	ret0, _ := ret[0].([]model.UserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopKGlobalMongo indicates an expected call of GetTopKGlobalMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetTopKGlobalMongo(ctx, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopKGlobalMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetTopKGlobalMongo), ctx, k)
+}
+
+// GetUserRankMongo mocks base method.
+func (m *MockLeaderboardStore) GetUserRankMongo(ctx context.Context, userID string) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRankMongo", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserRankMongo indicates an expected call of GetUserRankMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetUserRankMongo(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRankMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetUserRankMongo), ctx, userID)
+}
+
+// GetUserStandingMongo mocks base method.
+func (m *MockLeaderboardStore) GetUserStandingMongo(ctx context.Context, userID string) (model.RankedUserScore, int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStandingMongo", ctx, userID)
+	ret0, _ := ret[0].(model.RankedUserScore)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(int64)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetUserStandingMongo indicates an expected call of GetUserStandingMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetUserStandingMongo(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStandingMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetUserStandingMongo), ctx, userID)
+}
+
+// GetUserTotalsMongo mocks base method.
+func (m *MockLeaderboardStore) GetUserTotalsMongo(ctx context.Context, userIDs []string, since time.Time) ([]model.RankedUserScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTotalsMongo", ctx, userIDs, since)
+	ret0, _ := ret[0].([]model.RankedUserScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTotalsMongo indicates an expected call of GetUserTotalsMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetUserTotalsMongo(ctx, userIDs, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTotalsMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetUserTotalsMongo), ctx, userIDs, since)
+}
+
+// GetUsersChangedSinceMongo mocks base method.
+func (m *MockLeaderboardStore) GetUsersChangedSinceMongo(ctx context.Context, since time.Time) ([]string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersChangedSinceMongo", ctx, since)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsersChangedSinceMongo indicates an expected call of GetUsersChangedSinceMongo.
+func (mr *MockLeaderboardStoreMockRecorder) GetUsersChangedSinceMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersChangedSinceMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).GetUsersChangedSinceMongo), ctx, since)
+}
+
+// NormalizeEntitiesMongo mocks base method.
+func (m *MockLeaderboardStore) NormalizeEntitiesMongo(ctx context.Context, normalize func(string) string, dryRun bool) (map[string]string, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizeEntitiesMongo", ctx, normalize, dryRun)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// NormalizeEntitiesMongo indicates an expected call of NormalizeEntitiesMongo.
+func (mr *MockLeaderboardStoreMockRecorder) NormalizeEntitiesMongo(ctx, normalize, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizeEntitiesMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).NormalizeEntitiesMongo), ctx, normalize, dryRun)
+}
+
+// RecalculateFirstSuccessScores mocks base method.
+func (m *MockLeaderboardStore) RecalculateFirstSuccessScores(ctx context.Context, dryRun bool) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateFirstSuccessScores", ctx, dryRun)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RecalculateFirstSuccessScores indicates an expected call of RecalculateFirstSuccessScores.
+func (mr *MockLeaderboardStoreMockRecorder) RecalculateFirstSuccessScores(ctx, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateFirstSuccessScores", reflect.TypeOf((*MockLeaderboardStore)(nil).RecalculateFirstSuccessScores), ctx, dryRun)
+}
+
+// SaveLeaderboardDailySnapshots mocks base method.
+func (m *MockLeaderboardStore) SaveLeaderboardDailySnapshots(ctx context.Context, snapshots []model.LeaderboardDailySnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLeaderboardDailySnapshots", ctx, snapshots)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLeaderboardDailySnapshots indicates an expected call of SaveLeaderboardDailySnapshots.
+func (mr *MockLeaderboardStoreMockRecorder) SaveLeaderboardDailySnapshots(ctx, snapshots any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLeaderboardDailySnapshots", reflect.TypeOf((*MockLeaderboardStore)(nil).SaveLeaderboardDailySnapshots), ctx, snapshots)
+}
+
+// SaveLeaderboardSeasonSnapshot mocks base method.
+func (m *MockLeaderboardStore) SaveLeaderboardSeasonSnapshot(ctx context.Context, snapshot model.LeaderboardSeasonSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLeaderboardSeasonSnapshot", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLeaderboardSeasonSnapshot indicates an expected call of SaveLeaderboardSeasonSnapshot.
+func (mr *MockLeaderboardStoreMockRecorder) SaveLeaderboardSeasonSnapshot(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLeaderboardSeasonSnapshot", reflect.TypeOf((*MockLeaderboardStore)(nil).SaveLeaderboardSeasonSnapshot), ctx, snapshot)
+}
+
+// SyncLeaderboardToRedis mocks base method.
+func (m *MockLeaderboardStore) SyncLeaderboardToRedis(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncLeaderboardToRedis", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncLeaderboardToRedis indicates an expected call of SyncLeaderboardToRedis.
+func (mr *MockLeaderboardStoreMockRecorder) SyncLeaderboardToRedis(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncLeaderboardToRedis", reflect.TypeOf((*MockLeaderboardStore)(nil).SyncLeaderboardToRedis), ctx)
+}
+
+// SyncPeriodLeaderboardToRedis mocks base method.
+func (m *MockLeaderboardStore) SyncPeriodLeaderboardToRedis(ctx context.Context, lb *redisboard.Leaderboard, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncPeriodLeaderboardToRedis", ctx, lb, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncPeriodLeaderboardToRedis indicates an expected call of SyncPeriodLeaderboardToRedis.
+func (mr *MockLeaderboardStoreMockRecorder) SyncPeriodLeaderboardToRedis(ctx, lb, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncPeriodLeaderboardToRedis", reflect.TypeOf((*MockLeaderboardStore)(nil).SyncPeriodLeaderboardToRedis), ctx, lb, since)
+}
+
+// UpdateUserEntityMongo mocks base method.
+func (m *MockLeaderboardStore) UpdateUserEntityMongo(ctx context.Context, userID, entity string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserEntityMongo", ctx, userID, entity)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserEntityMongo indicates an expected call of UpdateUserEntityMongo.
+func (mr *MockLeaderboardStoreMockRecorder) UpdateUserEntityMongo(ctx, userID, entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserEntityMongo", reflect.TypeOf((*MockLeaderboardStore)(nil).UpdateUserEntityMongo), ctx, userID, entity)
+}
+
+// MockAdminStore is a mock of AdminStore interface.
+type MockAdminStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAdminStoreMockRecorder is the mock recorder for MockAdminStore.
+type MockAdminStoreMockRecorder struct {
+	mock *MockAdminStore
+}
+
+// NewMockAdminStore creates a new mock instance.
+func NewMockAdminStore(ctrl *gomock.Controller) *MockAdminStore {
+	mock := &MockAdminStore{ctrl: ctrl}
+	mock.recorder = &MockAdminStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminStore) EXPECT() *MockAdminStoreMockRecorder {
+	return m.recorder
+}
+
+// BanUserFromLeaderboard mocks base method.
+func (m *MockAdminStore) BanUserFromLeaderboard(ctx context.Context, ban model.LeaderboardBan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUserFromLeaderboard", ctx, ban)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BanUserFromLeaderboard indicates an expected call of BanUserFromLeaderboard.
+func (mr *MockAdminStoreMockRecorder) BanUserFromLeaderboard(ctx, ban any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUserFromLeaderboard", reflect.TypeOf((*MockAdminStore)(nil).BanUserFromLeaderboard), ctx, ban)
+}
+
+// EnsureIndexes mocks base method.
+func (m *MockAdminStore) EnsureIndexes(ctx context.Context) ([]model.CollectionIndexes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].([]model.CollectionIndexes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes.
+func (mr *MockAdminStoreMockRecorder) EnsureIndexes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockAdminStore)(nil).EnsureIndexes), ctx)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockAdminStore) GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(*model.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockAdminStoreMockRecorder) GetDeadLetter(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockAdminStore)(nil).GetDeadLetter), ctx, id)
+}
+
+// GetServiceStatsMongo mocks base method.
+func (m *MockAdminStore) GetServiceStatsMongo(ctx context.Context, since time.Time) (*model.ServiceStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceStatsMongo", ctx, since)
+	ret0, _ := ret[0].(*model.ServiceStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceStatsMongo indicates an expected call of GetServiceStatsMongo.
+func (mr *MockAdminStoreMockRecorder) GetServiceStatsMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceStatsMongo", reflect.TypeOf((*MockAdminStore)(nil).GetServiceStatsMongo), ctx, since)
+}
+
+// InvalidateSubmission mocks base method.
+func (m *MockAdminStore) InvalidateSubmission(ctx context.Context, submissionID string) (*model.Submission, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateSubmission", ctx, submissionID)
+	ret0, _ := ret[0].(*model.Submission)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// InvalidateSubmission indicates an expected call of InvalidateSubmission.
+func (mr *MockAdminStoreMockRecorder) InvalidateSubmission(ctx, submissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateSubmission", reflect.TypeOf((*MockAdminStore)(nil).InvalidateSubmission), ctx, submissionID)
+}
+
+// IsLeaderboardBanned mocks base method.
+func (m *MockAdminStore) IsLeaderboardBanned(ctx context.Context, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeaderboardBanned", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLeaderboardBanned indicates an expected call of IsLeaderboardBanned.
+func (mr *MockAdminStoreMockRecorder) IsLeaderboardBanned(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeaderboardBanned", reflect.TypeOf((*MockAdminStore)(nil).IsLeaderboardBanned), ctx, userID)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockAdminStore) ListDeadLetters(ctx context.Context, subject string, skip, limit int64) ([]model.DeadLetter, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, subject, skip, limit)
+	ret0, _ := ret[0].([]model.DeadLetter)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockAdminStoreMockRecorder) ListDeadLetters(ctx, subject, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockAdminStore)(nil).ListDeadLetters), ctx, subject, skip, limit)
+}
+
+// MarkDeadLetterReplayed mocks base method.
+func (m *MockAdminStore) MarkDeadLetterReplayed(ctx context.Context, id primitive.ObjectID, at time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDeadLetterReplayed", ctx, id, at)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkDeadLetterReplayed indicates an expected call of MarkDeadLetterReplayed.
+func (mr *MockAdminStoreMockRecorder) MarkDeadLetterReplayed(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeadLetterReplayed", reflect.TypeOf((*MockAdminStore)(nil).MarkDeadLetterReplayed), ctx, id, at)
+}
+
+// PurgeDeletedProblems mocks base method.
+func (m *MockAdminStore) PurgeDeletedProblems(ctx context.Context, deletedBefore time.Time, archive bool, limit int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedProblems", ctx, deletedBefore, archive, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedProblems indicates an expected call of PurgeDeletedProblems.
+func (mr *MockAdminStoreMockRecorder) PurgeDeletedProblems(ctx, deletedBefore, archive, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedProblems", reflect.TypeOf((*MockAdminStore)(nil).PurgeDeletedProblems), ctx, deletedBefore, archive, limit)
+}
+
+// QueryAuditLog mocks base method.
+func (m *MockAdminStore) QueryAuditLog(ctx context.Context, f model.AuditLogFilter, skip, limit int64) ([]model.AuditEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAuditLog", ctx, f, skip, limit)
+	ret0, _ := ret[0].([]model.AuditEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryAuditLog indicates an expected call of QueryAuditLog.
+func (mr *MockAdminStoreMockRecorder) QueryAuditLog(ctx, f, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAuditLog", reflect.TypeOf((*MockAdminStore)(nil).QueryAuditLog), ctx, f, skip, limit)
+}
+
+// SaveAuditEntry mocks base method.
+func (m *MockAdminStore) SaveAuditEntry(ctx context.Context, entry model.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuditEntry indicates an expected call of SaveAuditEntry.
+func (mr *MockAdminStoreMockRecorder) SaveAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuditEntry", reflect.TypeOf((*MockAdminStore)(nil).SaveAuditEntry), ctx, entry)
+}
+
+// SaveDeadLetter mocks base method.
+func (m *MockAdminStore) SaveDeadLetter(ctx context.Context, letter model.DeadLetter) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeadLetter", ctx, letter)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDeadLetter indicates an expected call of SaveDeadLetter.
+func (mr *MockAdminStoreMockRecorder) SaveDeadLetter(ctx, letter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeadLetter", reflect.TypeOf((*MockAdminStore)(nil).SaveDeadLetter), ctx, letter)
+}
+
+// SaveModerationAudit mocks base method.
+func (m *MockAdminStore) SaveModerationAudit(ctx context.Context, audit model.ModerationAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveModerationAudit", ctx, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveModerationAudit indicates an expected call of SaveModerationAudit.
+func (mr *MockAdminStoreMockRecorder) SaveModerationAudit(ctx, audit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveModerationAudit", reflect.TypeOf((*MockAdminStore)(nil).SaveModerationAudit), ctx, audit)
+}
+
+// MockOutboxStore is a mock of OutboxStore interface.
+type MockOutboxStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxStoreMockRecorder is the mock recorder for MockOutboxStore.
+type MockOutboxStoreMockRecorder struct {
+	mock *MockOutboxStore
+}
+
+// NewMockOutboxStore creates a new mock instance.
+func NewMockOutboxStore(ctrl *gomock.Controller) *MockOutboxStore {
+	mock := &MockOutboxStore{ctrl: ctrl}
+	mock.recorder = &MockOutboxStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxStore) EXPECT() *MockOutboxStoreMockRecorder {
+	return m.recorder
+}
+
+// GetPendingOutboxEvents mocks base method.
+func (m *MockOutboxStore) GetPendingOutboxEvents(ctx context.Context, limit int64) ([]model.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingOutboxEvents", ctx, limit)
+	ret0, _ := ret[0].([]model.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingOutboxEvents indicates an expected call of GetPendingOutboxEvents.
+func (mr *MockOutboxStoreMockRecorder) GetPendingOutboxEvents(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOutboxEvents", reflect.TypeOf((*MockOutboxStore)(nil).GetPendingOutboxEvents), ctx, limit)
+}
+
+// MarkOutboxEventSent mocks base method.
+func (m *MockOutboxStore) MarkOutboxEventSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventSent", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventSent indicates an expected call of MarkOutboxEventSent.
+func (mr *MockOutboxStoreMockRecorder) MarkOutboxEventSent(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventSent", reflect.TypeOf((*MockOutboxStore)(nil).MarkOutboxEventSent), ctx, id, at)
+}
+
+// RecordOutboxEventFailure mocks base method.
+func (m *MockOutboxStore) RecordOutboxEventFailure(ctx context.Context, id primitive.ObjectID, cause error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOutboxEventFailure", ctx, id, cause)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOutboxEventFailure indicates an expected call of RecordOutboxEventFailure.
+func (mr *MockOutboxStoreMockRecorder) RecordOutboxEventFailure(ctx, id, cause any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxEventFailure", reflect.TypeOf((*MockOutboxStore)(nil).RecordOutboxEventFailure), ctx, id, cause)
+}
+
+// SaveOutboxEvents mocks base method.
+func (m *MockOutboxStore) SaveOutboxEvents(ctx context.Context, events ...model.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveOutboxEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOutboxEvents indicates an expected call of SaveOutboxEvents.
+func (mr *MockOutboxStoreMockRecorder) SaveOutboxEvents(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOutboxEvents", reflect.TypeOf((*MockOutboxStore)(nil).SaveOutboxEvents), varargs...)
+}
+
+// WithTransaction mocks base method.
+func (m *MockOutboxStore) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockOutboxStoreMockRecorder) WithTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockOutboxStore)(nil).WithTransaction), ctx, fn)
+}
+
+// MockValidationJobStore is a mock of ValidationJobStore interface.
+type MockValidationJobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockValidationJobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockValidationJobStoreMockRecorder is the mock recorder for MockValidationJobStore.
+type MockValidationJobStoreMockRecorder struct {
+	mock *MockValidationJobStore
+}
+
+// NewMockValidationJobStore creates a new mock instance.
+func NewMockValidationJobStore(ctrl *gomock.Controller) *MockValidationJobStore {
+	mock := &MockValidationJobStore{ctrl: ctrl}
+	mock.recorder = &MockValidationJobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValidationJobStore) EXPECT() *MockValidationJobStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateValidationJob mocks base method.
+func (m *MockValidationJobStore) CreateValidationJob(ctx context.Context, job model.ValidationJob, staleBefore time.Time) (*model.ValidationJob, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateValidationJob", ctx, job, staleBefore)
+	ret0, _ := ret[0].(*model.ValidationJob)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateValidationJob indicates an expected call of CreateValidationJob.
+func (mr *MockValidationJobStoreMockRecorder) CreateValidationJob(ctx, job, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateValidationJob", reflect.TypeOf((*MockValidationJobStore)(nil).CreateValidationJob), ctx, job, staleBefore)
+}
+
+// FinishValidationJob mocks base method.
+func (m *MockValidationJobStore) FinishValidationJob(ctx context.Context, id primitive.ObjectID, report model.ValidateProblemResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishValidationJob", ctx, id, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishValidationJob indicates an expected call of FinishValidationJob.
+func (mr *MockValidationJobStoreMockRecorder) FinishValidationJob(ctx, id, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishValidationJob", reflect.TypeOf((*MockValidationJobStore)(nil).FinishValidationJob), ctx, id, report)
+}
+
+// GetValidationJob mocks base method.
+func (m *MockValidationJobStore) GetValidationJob(ctx context.Context, jobID string) (*model.ValidationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidationJob", ctx, jobID)
+	ret0, _ := ret[0].(*model.ValidationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidationJob indicates an expected call of GetValidationJob.
+func (mr *MockValidationJobStoreMockRecorder) GetValidationJob(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidationJob", reflect.TypeOf((*MockValidationJobStore)(nil).GetValidationJob), ctx, jobID)
+}
+
+// RecordValidationJobResult mocks base method.
+func (m *MockValidationJobStore) RecordValidationJobResult(ctx context.Context, id primitive.ObjectID, result model.LanguageValidationResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordValidationJobResult", ctx, id, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordValidationJobResult indicates an expected call of RecordValidationJobResult.
+func (mr *MockValidationJobStoreMockRecorder) RecordValidationJobResult(ctx, id, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordValidationJobResult", reflect.TypeOf((*MockValidationJobStore)(nil).RecordValidationJobResult), ctx, id, result)
+}
+
+// StartValidationJob mocks base method.
+func (m *MockValidationJobStore) StartValidationJob(ctx context.Context, id primitive.ObjectID, languages []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartValidationJob", ctx, id, languages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartValidationJob indicates an expected call of StartValidationJob.
+func (mr *MockValidationJobStoreMockRecorder) StartValidationJob(ctx, id, languages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartValidationJob", reflect.TypeOf((*MockValidationJobStore)(nil).StartValidationJob), ctx, id, languages)
+}
+
+// MockRejudgeStore is a mock of RejudgeStore interface.
+type MockRejudgeStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRejudgeStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRejudgeStoreMockRecorder is the mock recorder for MockRejudgeStore.
+type MockRejudgeStoreMockRecorder struct {
+	mock *MockRejudgeStore
+}
+
+// NewMockRejudgeStore creates a new mock instance.
+func NewMockRejudgeStore(ctrl *gomock.Controller) *MockRejudgeStore {
+	mock := &MockRejudgeStore{ctrl: ctrl}
+	mock.recorder = &MockRejudgeStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRejudgeStore) EXPECT() *MockRejudgeStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateRejudgeJob mocks base method.
+func (m *MockRejudgeStore) CreateRejudgeJob(ctx context.Context, job model.RejudgeJob, staleBefore time.Time) (*model.RejudgeJob, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRejudgeJob", ctx, job, staleBefore)
+	ret0, _ := ret[0].(*model.RejudgeJob)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRejudgeJob indicates an expected call of CreateRejudgeJob.
+func (mr *MockRejudgeStoreMockRecorder) CreateRejudgeJob(ctx, job, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRejudgeJob", reflect.TypeOf((*MockRejudgeStore)(nil).CreateRejudgeJob), ctx, job, staleBefore)
+}
+
+// FinishRejudgeJob mocks base method.
+func (m *MockRejudgeStore) FinishRejudgeJob(ctx context.Context, id primitive.ObjectID, status, message string, usersRescored int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRejudgeJob", ctx, id, status, message, usersRescored)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRejudgeJob indicates an expected call of FinishRejudgeJob.
+func (mr *MockRejudgeStoreMockRecorder) FinishRejudgeJob(ctx, id, status, message, usersRescored any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRejudgeJob", reflect.TypeOf((*MockRejudgeStore)(nil).FinishRejudgeJob), ctx, id, status, message, usersRescored)
+}
+
+// GetRejudgeJob mocks base method.
+func (m *MockRejudgeStore) GetRejudgeJob(ctx context.Context, jobID string) (*model.RejudgeJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejudgeJob", ctx, jobID)
+	ret0, _ := ret[0].(*model.RejudgeJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRejudgeJob indicates an expected call of GetRejudgeJob.
+func (mr *MockRejudgeStoreMockRecorder) GetRejudgeJob(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejudgeJob", reflect.TypeOf((*MockRejudgeStore)(nil).GetRejudgeJob), ctx, jobID)
+}
+
+// GetRejudgeSubmissions mocks base method.
+func (m *MockRejudgeStore) GetRejudgeSubmissions(ctx context.Context, problemID string, since *time.Time, after primitive.ObjectID, limit int64) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejudgeSubmissions", ctx, problemID, since, after, limit)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRejudgeSubmissions indicates an expected call of GetRejudgeSubmissions.
+func (mr *MockRejudgeStoreMockRecorder) GetRejudgeSubmissions(ctx, problemID, since, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejudgeSubmissions", reflect.TypeOf((*MockRejudgeStore)(nil).GetRejudgeSubmissions), ctx, problemID, since, after, limit)
+}
+
+// ReconcileFirstSuccess mocks base method.
+func (m *MockRejudgeStore) ReconcileFirstSuccess(ctx context.Context, userID, problemID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileFirstSuccess", ctx, userID, problemID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileFirstSuccess indicates an expected call of ReconcileFirstSuccess.
+func (mr *MockRejudgeStoreMockRecorder) ReconcileFirstSuccess(ctx, userID, problemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileFirstSuccess", reflect.TypeOf((*MockRejudgeStore)(nil).ReconcileFirstSuccess), ctx, userID, problemID)
+}
+
+// RecordRejudgeProgress mocks base method.
+func (m *MockRejudgeStore) RecordRejudgeProgress(ctx context.Context, id primitive.ObjectID, processed, changed, errored int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRejudgeProgress", ctx, id, processed, changed, errored)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordRejudgeProgress indicates an expected call of RecordRejudgeProgress.
+func (mr *MockRejudgeStoreMockRecorder) RecordRejudgeProgress(ctx, id, processed, changed, errored any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRejudgeProgress", reflect.TypeOf((*MockRejudgeStore)(nil).RecordRejudgeProgress), ctx, id, processed, changed, errored)
+}
+
+// SetSubmissionStatus mocks base method.
+func (m *MockRejudgeStore) SetSubmissionStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSubmissionStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSubmissionStatus indicates an expected call of SetSubmissionStatus.
+func (mr *MockRejudgeStoreMockRecorder) SetSubmissionStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubmissionStatus", reflect.TypeOf((*MockRejudgeStore)(nil).SetSubmissionStatus), ctx, id, status)
+}
+
+// StartRejudgeJob mocks base method.
+func (m *MockRejudgeStore) StartRejudgeJob(ctx context.Context, job model.RejudgeJob) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRejudgeJob", ctx, job)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartRejudgeJob indicates an expected call of StartRejudgeJob.
+func (mr *MockRejudgeStoreMockRecorder) StartRejudgeJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRejudgeJob", reflect.TypeOf((*MockRejudgeStore)(nil).StartRejudgeJob), ctx, job)
+}
+
+// MockContestStore is a mock of ContestStore interface.
+type MockContestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockContestStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockContestStoreMockRecorder is the mock recorder for MockContestStore.
+type MockContestStoreMockRecorder struct {
+	mock *MockContestStore
+}
+
+// NewMockContestStore creates a new mock instance.
+func NewMockContestStore(ctrl *gomock.Controller) *MockContestStore {
+	mock := &MockContestStore{ctrl: ctrl}
+	mock.recorder = &MockContestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContestStore) EXPECT() *MockContestStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateContest mocks base method.
+func (m *MockContestStore) CreateContest(ctx context.Context, contest model.Contest) (*model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContest", ctx, contest)
+	ret0, _ := ret[0].(*model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContest indicates an expected call of CreateContest.
+func (mr *MockContestStoreMockRecorder) CreateContest(ctx, contest any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContest", reflect.TypeOf((*MockContestStore)(nil).CreateContest), ctx, contest)
+}
+
+// FinalizeContest mocks base method.
+func (m *MockContestStore) FinalizeContest(ctx context.Context, contestID string, standings []model.ContestStanding, rated bool) (*model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinalizeContest", ctx, contestID, standings, rated)
+	ret0, _ := ret[0].(*model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinalizeContest indicates an expected call of FinalizeContest.
+func (mr *MockContestStoreMockRecorder) FinalizeContest(ctx, contestID, standings, rated any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeContest", reflect.TypeOf((*MockContestStore)(nil).FinalizeContest), ctx, contestID, standings, rated)
+}
+
+// GetContest mocks base method.
+func (m *MockContestStore) GetContest(ctx context.Context, contestID string) (*model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContest", ctx, contestID)
+	ret0, _ := ret[0].(*model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContest indicates an expected call of GetContest.
+func (mr *MockContestStoreMockRecorder) GetContest(ctx, contestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContest", reflect.TypeOf((*MockContestStore)(nil).GetContest), ctx, contestID)
+}
+
+// GetContestRegistration mocks base method.
+func (m *MockContestStore) GetContestRegistration(ctx context.Context, contestID, userID string) (*model.ContestRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestRegistration", ctx, contestID, userID)
+	ret0, _ := ret[0].(*model.ContestRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestRegistration indicates an expected call of GetContestRegistration.
+func (mr *MockContestStoreMockRecorder) GetContestRegistration(ctx, contestID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestRegistration", reflect.TypeOf((*MockContestStore)(nil).GetContestRegistration), ctx, contestID, userID)
+}
+
+// GetContestRegistrations mocks base method.
+func (m *MockContestStore) GetContestRegistrations(ctx context.Context, contestID string) ([]model.ContestRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestRegistrations", ctx, contestID)
+	ret0, _ := ret[0].([]model.ContestRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestRegistrations indicates an expected call of GetContestRegistrations.
+func (mr *MockContestStoreMockRecorder) GetContestRegistrations(ctx, contestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestRegistrations", reflect.TypeOf((*MockContestStore)(nil).GetContestRegistrations), ctx, contestID)
+}
+
+// GetContestStandings mocks base method.
+func (m *MockContestStore) GetContestStandings(ctx context.Context, contestID, division string, skip, limit int64) ([]model.ContestStanding, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestStandings", ctx, contestID, division, skip, limit)
+	ret0, _ := ret[0].([]model.ContestStanding)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetContestStandings indicates an expected call of GetContestStandings.
+func (mr *MockContestStoreMockRecorder) GetContestStandings(ctx, contestID, division, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestStandings", reflect.TypeOf((*MockContestStore)(nil).GetContestStandings), ctx, contestID, division, skip, limit)
+}
+
+// GetContestSubmissions mocks base method.
+func (m *MockContestStore) GetContestSubmissions(ctx context.Context, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestSubmissions", ctx, problemIDs, from, to)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestSubmissions indicates an expected call of GetContestSubmissions.
+func (mr *MockContestStoreMockRecorder) GetContestSubmissions(ctx, problemIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestSubmissions", reflect.TypeOf((*MockContestStore)(nil).GetContestSubmissions), ctx, problemIDs, from, to)
+}
+
+// GetContestsToFinalize mocks base method.
+func (m *MockContestStore) GetContestsToFinalize(ctx context.Context, endedBefore time.Time) ([]model.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContestsToFinalize", ctx, endedBefore)
+	ret0, _ := ret[0].([]model.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContestsToFinalize indicates an expected call of GetContestsToFinalize.
+func (mr *MockContestStoreMockRecorder) GetContestsToFinalize(ctx, endedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContestsToFinalize", reflect.TypeOf((*MockContestStore)(nil).GetContestsToFinalize), ctx, endedBefore)
+}
+
+// GetUserContestSubmissions mocks base method.
+func (m *MockContestStore) GetUserContestSubmissions(ctx context.Context, userID string, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserContestSubmissions", ctx, userID, problemIDs, from, to)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserContestSubmissions indicates an expected call of GetUserContestSubmissions.
+func (mr *MockContestStoreMockRecorder) GetUserContestSubmissions(ctx, userID, problemIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserContestSubmissions", reflect.TypeOf((*MockContestStore)(nil).GetUserContestSubmissions), ctx, userID, problemIDs, from, to)
+}
+
+// GetUserRatings mocks base method.
+func (m *MockContestStore) GetUserRatings(ctx context.Context, userIDs []string) (map[string]model.UserRating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRatings", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]model.UserRating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRatings indicates an expected call of GetUserRatings.
+func (mr *MockContestStoreMockRecorder) GetUserRatings(ctx, userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRatings", reflect.TypeOf((*MockContestStore)(nil).GetUserRatings), ctx, userIDs)
+}
+
+// GetVirtualParticipation mocks base method.
+func (m *MockContestStore) GetVirtualParticipation(ctx context.Context, contestID, userID string) (*model.VirtualParticipation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVirtualParticipation", ctx, contestID, userID)
+	ret0, _ := ret[0].(*model.VirtualParticipation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVirtualParticipation indicates an expected call of GetVirtualParticipation.
+func (mr *MockContestStoreMockRecorder) GetVirtualParticipation(ctx, contestID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVirtualParticipation", reflect.TypeOf((*MockContestStore)(nil).GetVirtualParticipation), ctx, contestID, userID)
+}
+
+// RegisterForContest mocks base method.
+func (m *MockContestStore) RegisterForContest(ctx context.Context, registration model.ContestRegistration) (*model.ContestRegistration, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterForContest", ctx, registration)
+	ret0, _ := ret[0].(*model.ContestRegistration)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterForContest indicates an expected call of RegisterForContest.
+func (mr *MockContestStoreMockRecorder) RegisterForContest(ctx, registration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterForContest", reflect.TypeOf((*MockContestStore)(nil).RegisterForContest), ctx, registration)
+}
+
+// StartVirtualParticipation mocks base method.
+func (m *MockContestStore) StartVirtualParticipation(ctx context.Context, participation model.VirtualParticipation) (*model.VirtualParticipation, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVirtualParticipation", ctx, participation)
+	ret0, _ := ret[0].(*model.VirtualParticipation)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartVirtualParticipation indicates an expected call of StartVirtualParticipation.
+func (mr *MockContestStoreMockRecorder) StartVirtualParticipation(ctx, participation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVirtualParticipation", reflect.TypeOf((*MockContestStore)(nil).StartVirtualParticipation), ctx, participation)
+}
+
+// MockSolutionStore is a mock of SolutionStore interface.
+type MockSolutionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSolutionStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockSolutionStoreMockRecorder is the mock recorder for MockSolutionStore.
+type MockSolutionStoreMockRecorder struct {
+	mock *MockSolutionStore
+}
+
+// NewMockSolutionStore creates a new mock instance.
+func NewMockSolutionStore(ctrl *gomock.Controller) *MockSolutionStore {
+	mock := &MockSolutionStore{ctrl: ctrl}
+	mock.recorder = &MockSolutionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSolutionStore) EXPECT() *MockSolutionStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateSolution mocks base method.
+func (m *MockSolutionStore) CreateSolution(ctx context.Context, solution model.Solution) (*model.Solution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSolution", ctx, solution)
+	ret0, _ := ret[0].(*model.Solution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSolution indicates an expected call of CreateSolution.
+func (mr *MockSolutionStoreMockRecorder) CreateSolution(ctx, solution any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSolution", reflect.TypeOf((*MockSolutionStore)(nil).CreateSolution), ctx, solution)
+}
+
+// GetSolution mocks base method.
+func (m *MockSolutionStore) GetSolution(ctx context.Context, solutionID string) (*model.Solution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSolution", ctx, solutionID)
+	ret0, _ := ret[0].(*model.Solution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSolution indicates an expected call of GetSolution.
+func (mr *MockSolutionStoreMockRecorder) GetSolution(ctx, solutionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSolution", reflect.TypeOf((*MockSolutionStore)(nil).GetSolution), ctx, solutionID)
+}
+
+// ListSolutions mocks base method.
+func (m *MockSolutionStore) ListSolutions(ctx context.Context, problemID, sort string, skip, limit int64) ([]model.Solution, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSolutions", ctx, problemID, sort, skip, limit)
+	ret0, _ := ret[0].([]model.Solution)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSolutions indicates an expected call of ListSolutions.
+func (mr *MockSolutionStoreMockRecorder) ListSolutions(ctx, problemID, sort, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSolutions", reflect.TypeOf((*MockSolutionStore)(nil).ListSolutions), ctx, problemID, sort, skip, limit)
+}
+
+// VoteSolution mocks base method.
+func (m *MockSolutionStore) VoteSolution(ctx context.Context, solutionID, userID string, value int) (*model.Solution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteSolution", ctx, solutionID, userID, value)
+	ret0, _ := ret[0].(*model.Solution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoteSolution indicates an expected call of VoteSolution.
+func (mr *MockSolutionStoreMockRecorder) VoteSolution(ctx, solutionID, userID, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteSolution", reflect.TypeOf((*MockSolutionStore)(nil).VoteSolution), ctx, solutionID, userID, value)
+}
+
+// MockProblemReportStore is a mock of ProblemReportStore interface.
+type MockProblemReportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockProblemReportStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockProblemReportStoreMockRecorder is the mock recorder for MockProblemReportStore.
+type MockProblemReportStoreMockRecorder struct {
+	mock *MockProblemReportStore
+}
+
+// NewMockProblemReportStore creates a new mock instance.
+func NewMockProblemReportStore(ctrl *gomock.Controller) *MockProblemReportStore {
+	mock := &MockProblemReportStore{ctrl: ctrl}
+	mock.recorder = &MockProblemReportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProblemReportStore) EXPECT() *MockProblemReportStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateProblemReport mocks base method.
+func (m *MockProblemReportStore) CreateProblemReport(ctx context.Context, report model.ProblemReport) (*model.ProblemReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProblemReport", ctx, report)
+	ret0, _ := ret[0].(*model.ProblemReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProblemReport indicates an expected call of CreateProblemReport.
+func (mr *MockProblemReportStoreMockRecorder) CreateProblemReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProblemReport", reflect.TypeOf((*MockProblemReportStore)(nil).CreateProblemReport), ctx, report)
+}
+
+// GetProblemReport mocks base method.
+func (m *MockProblemReportStore) GetProblemReport(ctx context.Context, reportID string) (*model.ProblemReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProblemReport", ctx, reportID)
+	ret0, _ := ret[0].(*model.ProblemReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProblemReport indicates an expected call of GetProblemReport.
+func (mr *MockProblemReportStoreMockRecorder) GetProblemReport(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProblemReport", reflect.TypeOf((*MockProblemReportStore)(nil).GetProblemReport), ctx, reportID)
+}
+
+// ListProblemReports mocks base method.
+func (m *MockProblemReportStore) ListProblemReports(ctx context.Context, f model.ProblemReportFilter, skip, limit int64) ([]model.ProblemReport, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProblemReports", ctx, f, skip, limit)
+	ret0, _ := ret[0].([]model.ProblemReport)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListProblemReports indicates an expected call of ListProblemReports.
+func (mr *MockProblemReportStoreMockRecorder) ListProblemReports(ctx, f, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProblemReports", reflect.TypeOf((*MockProblemReportStore)(nil).ListProblemReports), ctx, f, skip, limit)
+}
+
+// UpdateProblemReportStatus mocks base method.
+func (m *MockProblemReportStore) UpdateProblemReportStatus(ctx context.Context, reportID, fromStatus, status, note, handledBy string, fixedRevision *time.Time) (*model.ProblemReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProblemReportStatus", ctx, reportID, fromStatus, status, note, handledBy, fixedRevision)
+	ret0, _ := ret[0].(*model.ProblemReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProblemReportStatus indicates an expected call of UpdateProblemReportStatus.
+func (mr *MockProblemReportStoreMockRecorder) UpdateProblemReportStatus(ctx, reportID, fromStatus, status, note, handledBy, fixedRevision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProblemReportStatus", reflect.TypeOf((*MockProblemReportStore)(nil).UpdateProblemReportStatus), ctx, reportID, fromStatus, status, note, handledBy, fixedRevision)
+}
+
+// MockAPIKeyStore is a mock of APIKeyStore interface.
+type MockAPIKeyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyStoreMockRecorder is the mock recorder for MockAPIKeyStore.
+type MockAPIKeyStoreMockRecorder struct {
+	mock *MockAPIKeyStore
+}
+
+// NewMockAPIKeyStore creates a new mock instance.
+func NewMockAPIKeyStore(ctrl *gomock.Controller) *MockAPIKeyStore {
+	mock := &MockAPIKeyStore{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyStore) EXPECT() *MockAPIKeyStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyStore) CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyStoreMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyStore)(nil).CreateAPIKey), ctx, key)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockAPIKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockAPIKeyStoreMockRecorder) GetAPIKeyByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockAPIKeyStore)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPIKeyStore) ListAPIKeys(ctx context.Context, includeRevoked bool) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, includeRevoked)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIKeyStoreMockRecorder) ListAPIKeys(ctx, includeRevoked any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKeyStore)(nil).ListAPIKeys), ctx, includeRevoked)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyStore) RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, keyID, at)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyStoreMockRecorder) RevokeAPIKey(ctx, keyID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyStore)(nil).RevokeAPIKey), ctx, keyID, at)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockAPIKeyStore) TouchAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockAPIKeyStoreMockRecorder) TouchAPIKey(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockAPIKeyStore)(nil).TouchAPIKey), ctx, id, at)
+}
+
+// MockWebhookStore is a mock of WebhookStore interface.
+type MockWebhookStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookStoreMockRecorder is the mock recorder for MockWebhookStore.
+type MockWebhookStoreMockRecorder struct {
+	mock *MockWebhookStore
+}
+
+// NewMockWebhookStore creates a new mock instance.
+func NewMockWebhookStore(ctrl *gomock.Controller) *MockWebhookStore {
+	mock := &MockWebhookStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookStore) EXPECT() *MockWebhookStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookStore) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookStoreMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookStore)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookStore) DeleteWebhook(ctx context.Context, webhookID string, at time.Time) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookID, at)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookStoreMockRecorder) DeleteWebhook(ctx, webhookID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookStore)(nil).DeleteWebhook), ctx, webhookID, at)
+}
+
+// GetDueWebhookDeliveries mocks base method.
+func (m *MockWebhookStore) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueWebhookDeliveries", ctx, now, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueWebhookDeliveries indicates an expected call of GetDueWebhookDeliveries.
+func (mr *MockWebhookStoreMockRecorder) GetDueWebhookDeliveries(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueWebhookDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).GetDueWebhookDeliveries), ctx, now, limit)
+}
+
+// GetWebhooksForEvent mocks base method.
+func (m *MockWebhookStore) GetWebhooksForEvent(ctx context.Context, eventType string) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksForEvent", ctx, eventType)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksForEvent indicates an expected call of GetWebhooksForEvent.
+func (mr *MockWebhookStoreMockRecorder) GetWebhooksForEvent(ctx, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksForEvent", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhooksForEvent), ctx, eventType)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookStore) ListWebhookDeliveries(ctx context.Context, webhookID, status string, skip, limit int64) ([]model.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, webhookID, status, skip, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookStoreMockRecorder) ListWebhookDeliveries(ctx, webhookID, status, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).ListWebhookDeliveries), ctx, webhookID, status, skip, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookStore) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookStoreMockRecorder) ListWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookStore)(nil).ListWebhooks), ctx)
+}
+
+// SaveWebhookDeliveries mocks base method.
+func (m *MockWebhookStore) SaveWebhookDeliveries(ctx context.Context, deliveries ...model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range deliveries {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveWebhookDeliveries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhookDeliveries indicates an expected call of SaveWebhookDeliveries.
+func (mr *MockWebhookStoreMockRecorder) SaveWebhookDeliveries(ctx any, deliveries ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, deliveries...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhookDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).SaveWebhookDeliveries), varargs...)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockWebhookStore) UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockWebhookStoreMockRecorder) UpdateWebhookDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockWebhookStore)(nil).UpdateWebhookDelivery), ctx, delivery)
+}
+
+// MockAssignmentStore is a mock of AssignmentStore interface.
+type MockAssignmentStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssignmentStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAssignmentStoreMockRecorder is the mock recorder for MockAssignmentStore.
+type MockAssignmentStoreMockRecorder struct {
+	mock *MockAssignmentStore
+}
+
+// NewMockAssignmentStore creates a new mock instance.
+func NewMockAssignmentStore(ctrl *gomock.Controller) *MockAssignmentStore {
+	mock := &MockAssignmentStore{ctrl: ctrl}
+	mock.recorder = &MockAssignmentStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssignmentStore) EXPECT() *MockAssignmentStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateAssignment mocks base method.
+func (m *MockAssignmentStore) CreateAssignment(ctx context.Context, assignment model.Assignment) (*model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAssignment", ctx, assignment)
+	ret0, _ := ret[0].(*model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAssignment indicates an expected call of CreateAssignment.
+func (mr *MockAssignmentStoreMockRecorder) CreateAssignment(ctx, assignment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAssignment", reflect.TypeOf((*MockAssignmentStore)(nil).CreateAssignment), ctx, assignment)
+}
+
+// GetAssignment mocks base method.
+func (m *MockAssignmentStore) GetAssignment(ctx context.Context, assignmentID string) (*model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignment", ctx, assignmentID)
+	ret0, _ := ret[0].(*model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignment indicates an expected call of GetAssignment.
+func (mr *MockAssignmentStoreMockRecorder) GetAssignment(ctx, assignmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignment", reflect.TypeOf((*MockAssignmentStore)(nil).GetAssignment), ctx, assignmentID)
+}
+
+// GetAssignmentEnrollment mocks base method.
+func (m *MockAssignmentStore) GetAssignmentEnrollment(ctx context.Context, assignmentID, userID string) (*model.AssignmentEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentEnrollment", ctx, assignmentID, userID)
+	ret0, _ := ret[0].(*model.AssignmentEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentEnrollment indicates an expected call of GetAssignmentEnrollment.
+func (mr *MockAssignmentStoreMockRecorder) GetAssignmentEnrollment(ctx, assignmentID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentEnrollment", reflect.TypeOf((*MockAssignmentStore)(nil).GetAssignmentEnrollment), ctx, assignmentID, userID)
+}
+
+// GetAssignmentEnrollments mocks base method.
+func (m *MockAssignmentStore) GetAssignmentEnrollments(ctx context.Context, assignmentID string) ([]model.AssignmentEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentEnrollments", ctx, assignmentID)
+	ret0, _ := ret[0].([]model.AssignmentEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentEnrollments indicates an expected call of GetAssignmentEnrollments.
+func (mr *MockAssignmentStoreMockRecorder) GetAssignmentEnrollments(ctx, assignmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentEnrollments", reflect.TypeOf((*MockAssignmentStore)(nil).GetAssignmentEnrollments), ctx, assignmentID)
+}
+
+// GetAssignmentSubmissions mocks base method.
+func (m *MockAssignmentStore) GetAssignmentSubmissions(ctx context.Context, userIDs, problemIDs []string, from, to time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentSubmissions", ctx, userIDs, problemIDs, from, to)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentSubmissions indicates an expected call of GetAssignmentSubmissions.
+func (mr *MockAssignmentStoreMockRecorder) GetAssignmentSubmissions(ctx, userIDs, problemIDs, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentSubmissions", reflect.TypeOf((*MockAssignmentStore)(nil).GetAssignmentSubmissions), ctx, userIDs, problemIDs, from, to)
+}
+
+// JoinAssignment mocks base method.
+func (m *MockAssignmentStore) JoinAssignment(ctx context.Context, enrollment model.AssignmentEnrollment) (*model.AssignmentEnrollment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinAssignment", ctx, enrollment)
+	ret0, _ := ret[0].(*model.AssignmentEnrollment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// JoinAssignment indicates an expected call of JoinAssignment.
+func (mr *MockAssignmentStoreMockRecorder) JoinAssignment(ctx, enrollment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinAssignment", reflect.TypeOf((*MockAssignmentStore)(nil).JoinAssignment), ctx, enrollment)
+}
+
+// SetAssignmentRoster mocks base method.
+func (m *MockAssignmentStore) SetAssignmentRoster(ctx context.Context, assignmentID string, roster []string) (*model.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAssignmentRoster", ctx, assignmentID, roster)
+	ret0, _ := ret[0].(*model.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAssignmentRoster indicates an expected call of SetAssignmentRoster.
+func (mr *MockAssignmentStoreMockRecorder) SetAssignmentRoster(ctx, assignmentID, roster any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAssignmentRoster", reflect.TypeOf((*MockAssignmentStore)(nil).SetAssignmentRoster), ctx, assignmentID, roster)
+}
+
+// MockOrganizationStore is a mock of OrganizationStore interface.
+type MockOrganizationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizationStoreMockRecorder is the mock recorder for MockOrganizationStore.
+type MockOrganizationStoreMockRecorder struct {
+	mock *MockOrganizationStore
+}
+
+// NewMockOrganizationStore creates a new mock instance.
+func NewMockOrganizationStore(ctrl *gomock.Controller) *MockOrganizationStore {
+	mock := &MockOrganizationStore{ctrl: ctrl}
+	mock.recorder = &MockOrganizationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationStore) EXPECT() *MockOrganizationStoreMockRecorder {
+	return m.recorder
+}
+
+// CountOrganizationMembers mocks base method.
+func (m *MockOrganizationStore) CountOrganizationMembers(ctx context.Context, organizationID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOrganizationMembers", ctx, organizationID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOrganizationMembers indicates an expected call of CountOrganizationMembers.
+func (mr *MockOrganizationStoreMockRecorder) CountOrganizationMembers(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrganizationMembers", reflect.TypeOf((*MockOrganizationStore)(nil).CountOrganizationMembers), ctx, organizationID)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationStore) CreateOrganization(ctx context.Context, organization model.Organization) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, organization)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationStoreMockRecorder) CreateOrganization(ctx, organization any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationStore)(nil).CreateOrganization), ctx, organization)
+}
+
+// GetOrganization mocks base method.
+func (m *MockOrganizationStore) GetOrganization(ctx context.Context, organizationID string) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganization", ctx, organizationID)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganization indicates an expected call of GetOrganization.
+func (mr *MockOrganizationStoreMockRecorder) GetOrganization(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganization", reflect.TypeOf((*MockOrganizationStore)(nil).GetOrganization), ctx, organizationID)
+}
+
+// GetOrganizationMemberIDs mocks base method.
+func (m *MockOrganizationStore) GetOrganizationMemberIDs(ctx context.Context, organizationID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberIDs", ctx, organizationID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberIDs indicates an expected call of GetOrganizationMemberIDs.
+func (mr *MockOrganizationStoreMockRecorder) GetOrganizationMemberIDs(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberIDs", reflect.TypeOf((*MockOrganizationStore)(nil).GetOrganizationMemberIDs), ctx, organizationID)
+}
+
+// GetOrganizationMemberships mocks base method.
+func (m *MockOrganizationStore) GetOrganizationMemberships(ctx context.Context, userIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberships", ctx, userIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberships indicates an expected call of GetOrganizationMemberships.
+func (mr *MockOrganizationStoreMockRecorder) GetOrganizationMemberships(ctx, userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberships", reflect.TypeOf((*MockOrganizationStore)(nil).GetOrganizationMemberships), ctx, userIDs)
+}
+
+// ListOrganizations mocks base method.
+func (m *MockOrganizationStore) ListOrganizations(ctx context.Context, kind string, skip, limit int64) ([]model.Organization, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizations", ctx, kind, skip, limit)
+	ret0, _ := ret[0].([]model.Organization)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockOrganizationStoreMockRecorder) ListOrganizations(ctx, kind, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockOrganizationStore)(nil).ListOrganizations), ctx, kind, skip, limit)
+}
+
+// RemoveUserOrganization mocks base method.
+func (m *MockOrganizationStore) RemoveUserOrganization(ctx context.Context, userID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserOrganization", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUserOrganization indicates an expected call of RemoveUserOrganization.
+func (mr *MockOrganizationStoreMockRecorder) RemoveUserOrganization(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserOrganization", reflect.TypeOf((*MockOrganizationStore)(nil).RemoveUserOrganization), ctx, userID)
+}
+
+// SetUserOrganization mocks base method.
+func (m *MockOrganizationStore) SetUserOrganization(ctx context.Context, member model.OrganizationMember) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserOrganization", ctx, member)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserOrganization indicates an expected call of SetUserOrganization.
+func (mr *MockOrganizationStoreMockRecorder) SetUserOrganization(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserOrganization", reflect.TypeOf((*MockOrganizationStore)(nil).SetUserOrganization), ctx, member)
+}
+
+// MockConsistencyStore is a mock of ConsistencyStore interface.
+type MockConsistencyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsistencyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockConsistencyStoreMockRecorder is the mock recorder for MockConsistencyStore.
+type MockConsistencyStoreMockRecorder struct {
+	mock *MockConsistencyStore
+}
+
+// NewMockConsistencyStore creates a new mock instance.
+func NewMockConsistencyStore(ctrl *gomock.Controller) *MockConsistencyStore {
+	mock := &MockConsistencyStore{ctrl: ctrl}
+	mock.recorder = &MockConsistencyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsistencyStore) EXPECT() *MockConsistencyStoreMockRecorder {
+	return m.recorder
+}
+
+// FindFirstSuccessDiscrepanciesMongo mocks base method.
+func (m *MockConsistencyStore) FindFirstSuccessDiscrepanciesMongo(ctx context.Context, since time.Time) ([]model.FirstSuccessDiscrepancy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFirstSuccessDiscrepanciesMongo", ctx, since)
+	ret0, _ := ret[0].([]model.FirstSuccessDiscrepancy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFirstSuccessDiscrepanciesMongo indicates an expected call of FindFirstSuccessDiscrepanciesMongo.
+func (mr *MockConsistencyStoreMockRecorder) FindFirstSuccessDiscrepanciesMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFirstSuccessDiscrepanciesMongo", reflect.TypeOf((*MockConsistencyStore)(nil).FindFirstSuccessDiscrepanciesMongo), ctx, since)
+}
+
+// FindMissingFirstSuccessesMongo mocks base method.
+func (m *MockConsistencyStore) FindMissingFirstSuccessesMongo(ctx context.Context, since time.Time) ([]model.Submission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMissingFirstSuccessesMongo", ctx, since)
+	ret0, _ := ret[0].([]model.Submission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMissingFirstSuccessesMongo indicates an expected call of FindMissingFirstSuccessesMongo.
+func (mr *MockConsistencyStoreMockRecorder) FindMissingFirstSuccessesMongo(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMissingFirstSuccessesMongo", reflect.TypeOf((*MockConsistencyStore)(nil).FindMissingFirstSuccessesMongo), ctx, since)
+}
+
+// RepairFirstSuccess mocks base method.
+func (m *MockConsistencyStore) RepairFirstSuccess(ctx context.Context, submission model.Submission) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairFirstSuccess", ctx, submission)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairFirstSuccess indicates an expected call of RepairFirstSuccess.
+func (mr *MockConsistencyStoreMockRecorder) RepairFirstSuccess(ctx, submission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairFirstSuccess", reflect.TypeOf((*MockConsistencyStore)(nil).RepairFirstSuccess), ctx, submission)
+}
+
+// MockExecutionProfileStore is a mock of ExecutionProfileStore interface.
+type MockExecutionProfileStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExecutionProfileStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockExecutionProfileStoreMockRecorder is the mock recorder for MockExecutionProfileStore.
+type MockExecutionProfileStoreMockRecorder struct {
+	mock *MockExecutionProfileStore
+}
+
+// NewMockExecutionProfileStore creates a new mock instance.
+func NewMockExecutionProfileStore(ctrl *gomock.Controller) *MockExecutionProfileStore {
+	mock := &MockExecutionProfileStore{ctrl: ctrl}
+	mock.recorder = &MockExecutionProfileStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExecutionProfileStore) EXPECT() *MockExecutionProfileStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteExecutionProfile mocks base method.
+func (m *MockExecutionProfileStore) DeleteExecutionProfile(ctx context.Context, language string) (*model.ExecutionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExecutionProfile", ctx, language)
+	ret0, _ := ret[0].(*model.ExecutionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExecutionProfile indicates an expected call of DeleteExecutionProfile.
+func (mr *MockExecutionProfileStoreMockRecorder) DeleteExecutionProfile(ctx, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExecutionProfile", reflect.TypeOf((*MockExecutionProfileStore)(nil).DeleteExecutionProfile), ctx, language)
+}
+
+// GetExecutionProfiles mocks base method.
+func (m *MockExecutionProfileStore) GetExecutionProfiles(ctx context.Context) ([]model.ExecutionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExecutionProfiles", ctx)
+	ret0, _ := ret[0].([]model.ExecutionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExecutionProfiles indicates an expected call of GetExecutionProfiles.
+func (mr *MockExecutionProfileStoreMockRecorder) GetExecutionProfiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionProfiles", reflect.TypeOf((*MockExecutionProfileStore)(nil).GetExecutionProfiles), ctx)
+}
+
+// SetExecutionProfile mocks base method.
+func (m *MockExecutionProfileStore) SetExecutionProfile(ctx context.Context, profile model.ExecutionProfile) (*model.ExecutionProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExecutionProfile", ctx, profile)
+	ret0, _ := ret[0].(*model.ExecutionProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetExecutionProfile indicates an expected call of SetExecutionProfile.
+func (mr *MockExecutionProfileStoreMockRecorder) SetExecutionProfile(ctx, profile any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExecutionProfile", reflect.TypeOf((*MockExecutionProfileStore)(nil).SetExecutionProfile), ctx, profile)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrSubmissionAlreadyInvalidated is returned when a submission was invalidated before
+var ErrSubmissionAlreadyInvalidated = errors.New("submission already invalidated")
+
+// GetLeaderboardBannedUserIDs never returns a nil slice so the result is safe inside $nin
+func (r *Repository) GetLeaderboardBannedUserIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	ids, err := r.leaderboardBansCollection.Distinct(ctx, "_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leaderboard bans: %w", err)
+	}
+	banned := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if userID, ok := id.(string); ok {
+			banned = append(banned, userID)
+		}
+	}
+	return banned, nil
+}
+
+func (r *Repository) IsLeaderboardBanned(ctx context.Context, userID string) (bool, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	count, err := r.leaderboardBansCollection.CountDocuments(ctx, bson.M{"_id": userID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check leaderboard ban: %w", err)
+	}
+	return count > 0, nil
+}
+
+// BanUserFromLeaderboard records the ban; banning twice keeps the original ban
+func (r *Repository) BanUserFromLeaderboard(ctx context.Context, ban model.LeaderboardBan) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.leaderboardBansCollection.UpdateOne(ctx,
+		bson.M{"_id": ban.UserID},
+		bson.M{"$setOnInsert": ban},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save leaderboard ban: %w", err)
+	}
+	return nil
+}
+
+// InvalidateSubmission marks the submission INVALIDATED with no score and drops its first success, returning the
+// submission and the score that was revoked (0 when it was not a first success)
+func (r *Repository) InvalidateSubmission(ctx context.Context, submissionID string) (*model.Submission, int, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	submission, err := r.GetSubmissionByID(ctx, submissionID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if submission.Invalidated {
+		return submission, 0, ErrSubmissionAlreadyInvalidated
+	}
+
+	// INVALIDATED no longer counts as a success, so a later accepted submission can become the first success again
+	_, err = r.submissionsCollection.UpdateOne(ctx,
+		bson.M{"_id": submission.ID},
+		bson.M{"$set": bson.M{"invalidated": true, "status": "INVALIDATED", "score": 0, "isFirst": false}})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to invalidate submission: %w", err)
+	}
+
+	var firstSuccess model.ProblemDone
+	err = r.submissionFirstSuccessCollection.FindOneAndDelete(ctx, bson.M{"submissionId": submissionID}).Decode(&firstSuccess)
+	if err == mongo.ErrNoDocuments {
+		return submission, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to remove first success: %w", err)
+	}
+	return submission, firstSuccess.Score, nil
+}
+
+func (r *Repository) SaveModerationAudit(ctx context.Context, audit model.ModerationAudit) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if _, err := r.moderationAuditCollection.InsertOne(ctx, audit); err != nil {
+		return fmt.Errorf("failed to save moderation audit: %w", err)
+	}
+	return nil
+}
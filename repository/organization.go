@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func organizationObjectID(organizationID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		return primitive.NilObjectID, customerrors.Validation("invalid organization id %q", organizationID)
+	}
+	return id, nil
+}
+
+// CreateOrganization stores a new organization and returns it with its ID; a taken slug is a customerrors.Conflict
+func (r *Repository) CreateOrganization(ctx context.Context, organization model.Organization) (*model.Organization, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	organization.ID = primitive.NewObjectID()
+	if _, err := r.organizationsCollection.InsertOne(ctx, organization); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, customerrors.Conflict("organization %q already exists", organization.Slug)
+		}
+		return nil, fmt.Errorf("failed to create organization: %w", dbError(err))
+	}
+	return &organization, nil
+}
+
+// GetOrganization returns a customerrors.NotFound error when the ID is unknown
+func (r *Repository) GetOrganization(ctx context.Context, organizationID string) (*model.Organization, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := organizationObjectID(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var organization model.Organization
+	if err := r.organizationsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&organization); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("organization %s", organizationID)
+		}
+		return nil, fmt.Errorf("failed to fetch organization %s: %w", organizationID, dbError(err))
+	}
+	return &organization, nil
+}
+
+// ListOrganizations pages through organizations by name, of one kind unless kind is empty
+func (r *Repository) ListOrganizations(ctx context.Context, kind string, skip, limit int64) ([]model.Organization, int64, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+
+	total, err := r.organizationsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count organizations: %w", dbError(err))
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.organizationsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list organizations: %w", dbError(err))
+	}
+	organizations := []model.Organization{}
+	if err := cursor.All(ctx, &organizations); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode organizations: %w", dbError(err))
+	}
+	return organizations, total, nil
+}
+
+// SetUserOrganization makes member's user a member of member's organization only, and returns the organization they
+// were in before, "" when none
+func (r *Repository) SetUserOrganization(ctx context.Context, member model.OrganizationMember) (string, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	var previous model.OrganizationMember
+	err := r.organizationMembersCollection.FindOneAndReplace(ctx,
+		bson.M{"_id": member.UserID},
+		member,
+		options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&previous)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return "", fmt.Errorf("failed to set organization of user %s: %w", member.UserID, dbError(err))
+	}
+	return previous.OrganizationID, nil
+}
+
+// RemoveUserOrganization takes a user out of their organization and returns it, "" when they were in none
+func (r *Repository) RemoveUserOrganization(ctx context.Context, userID string) (string, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	var removed model.OrganizationMember
+	err := r.organizationMembersCollection.FindOneAndDelete(ctx, bson.M{"_id": userID}).Decode(&removed)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to remove organization of user %s: %w", userID, dbError(err))
+	}
+	return removed.OrganizationID, nil
+}
+
+// GetOrganizationMemberships returns the organization of each given user that is in one, or of every member when
+// userIDs is nil
+func (r *Repository) GetOrganizationMemberships(ctx context.Context, userIDs []string) (map[string]string, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	filter := bson.M{}
+	if userIDs != nil {
+		filter["_id"] = bson.M{"$in": userIDs}
+	}
+	cursor, err := r.organizationMembersCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organization memberships: %w", dbError(err))
+	}
+	var members []model.OrganizationMember
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, fmt.Errorf("failed to decode organization memberships: %w", dbError(err))
+	}
+	memberships := make(map[string]string, len(members))
+	for _, member := range members {
+		memberships[member.UserID] = member.OrganizationID
+	}
+	return memberships, nil
+}
+
+// GetOrganizationMemberIDs returns the user IDs of an organization's members
+func (r *Repository) GetOrganizationMemberIDs(ctx context.Context, organizationID string) ([]string, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	cursor, err := r.organizationMembersCollection.Find(ctx, bson.M{"organizationId": organizationID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch members of organization %s: %w", organizationID, dbError(err))
+	}
+	var members []model.OrganizationMember
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, fmt.Errorf("failed to decode members of organization %s: %w", organizationID, dbError(err))
+	}
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	return userIDs, nil
+}
+
+// CountOrganizationMembers returns how many users belong to an organization
+func (r *Repository) CountOrganizationMembers(ctx context.Context, organizationID string) (int64, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	count, err := r.organizationMembersCollection.CountDocuments(ctx, bson.M{"organizationId": organizationID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count members of organization %s: %w", organizationID, dbError(err))
+	}
+	return count, nil
+}
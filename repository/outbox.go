@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sentOutboxEventTTL is how long published outbox events are kept for inspection before MongoDB drops them
+const sentOutboxEventTTL = 7 * 24 * time.Hour
+
+// SaveOutboxEvents adds events to the outbox. Called with a ctx from WithTransaction they are only stored when the
+// change they announce is committed.
+func (r *Repository) SaveOutboxEvents(ctx context.Context, events ...model.OutboxEvent) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]any, len(events))
+	for i, event := range events {
+		if event.ID.IsZero() {
+			event.ID = primitive.NewObjectID()
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+		event.SentAt = nil
+		docs[i] = event
+	}
+	if _, err := r.outboxCollection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to save outbox events: %w", err)
+	}
+	return nil
+}
+
+// GetPendingOutboxEvents returns up to limit events that were not published yet, oldest first
+func (r *Repository) GetPendingOutboxEvents(ctx context.Context, limit int64) ([]model.OutboxEvent, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.outboxCollection.Find(ctx, bson.M{"sentAt": nil}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pending outbox events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := []model.OutboxEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode pending outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// MarkOutboxEventSent records that the event was published, so the relay does not publish it again
+func (r *Repository) MarkOutboxEventSent(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.outboxCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"sentAt": at}, "$inc": bson.M{"attempts": 1}, "$unset": bson.M{"lastError": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event %s sent: %w", id.Hex(), err)
+	}
+	return nil
+}
+
+// RecordOutboxEventFailure counts a failed publish of the event, which stays pending
+func (r *Repository) RecordOutboxEventFailure(ctx context.Context, id primitive.ObjectID, cause error) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.outboxCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"attempts": 1}, "$set": bson.M{"lastError": cause.Error()}})
+	if err != nil {
+		return fmt.Errorf("failed to record failure of outbox event %s: %w", id.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SetProblemTier moves a problem to tier and returns the tier it was in before. Free problems carry no tier field,
+// so problems created before tiers existed are free.
+func (r *Repository) SetProblemTier(ctx context.Context, problemID, tier string) (string, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return "", err
+	}
+
+	update := bson.M{"$set": bson.M{"tier": tier, "updated_at": time.Now()}}
+	if tier == model.ProblemTierFree {
+		update = bson.M{"$unset": bson.M{"tier": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	var before model.Problem
+	err = r.problemsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.Before).SetProjection(bson.M{"tier": 1}),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return "", customerrors.NotFound("problem %s", problemID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to set tier of problem %s: %w", problemID, dbError(err))
+	}
+	if before.Tier == "" {
+		return model.ProblemTierFree, nil
+	}
+	return before.Tier, nil
+}
+
+// GetPremiumProblemIDs returns the IDs of every premium problem that is not deleted
+func (r *Repository) GetPremiumProblemIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.problemsCollection.Find(ctx,
+		bson.M{"deleted_at": nil, "tier": model.ProblemTierPremium},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch premium problems: %w", dbError(err))
+	}
+	var problems []model.Problem
+	if err := cursor.All(ctx, &problems); err != nil {
+		return nil, fmt.Errorf("failed to decode premium problems: %w", dbError(err))
+	}
+	ids := make([]string, len(problems))
+	for i, problem := range problems {
+		ids[i] = problem.ID.Hex()
+	}
+	return ids, nil
+}
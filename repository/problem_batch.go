@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GetProblemsByIDs returns the visible, not deleted problems among problemIDs in no particular order. Malformed IDs
+// are skipped like unknown ones.
+func (r *Repository) GetProblemsByIDs(ctx context.Context, problemIDs []string) ([]model.Problem, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	ids := convertHexToObjectIDs(problemIDs)
+	if len(ids) == 0 {
+		return []model.Problem{}, nil
+	}
+	cursor, err := r.problemsCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil, "visible": true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch problems: %w", dbError(err))
+	}
+	problems := []model.Problem{}
+	if err := cursor.All(ctx, &problems); err != nil {
+		return nil, fmt.Errorf("failed to decode problems: %w", dbError(err))
+	}
+	return problems, nil
+}
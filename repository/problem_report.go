@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func problemReportObjectID(reportID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return primitive.NilObjectID, customerrors.Validation("invalid report id %q", reportID)
+	}
+	return id, nil
+}
+
+// CreateProblemReport stores a new report and returns it with its ID
+func (r *Repository) CreateProblemReport(ctx context.Context, report model.ProblemReport) (*model.ProblemReport, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	report.ID = primitive.NewObjectID()
+	if _, err := r.problemReportsCollection.InsertOne(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to create problem report: %w", dbError(err))
+	}
+	return &report, nil
+}
+
+// GetProblemReport returns a customerrors.NotFound error when the ID is unknown
+func (r *Repository) GetProblemReport(ctx context.Context, reportID string) (*model.ProblemReport, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := problemReportObjectID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	var report model.ProblemReport
+	if err := r.problemReportsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&report); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("problem report %s", reportID)
+		}
+		return nil, fmt.Errorf("failed to fetch problem report %s: %w", reportID, dbError(err))
+	}
+	return &report, nil
+}
+
+// ListProblemReports returns the reports matching f oldest first, so the longest waiting are handled first, plus
+// the total count
+func (r *Repository) ListProblemReports(ctx context.Context, f model.ProblemReportFilter, skip, limit int64) ([]model.ProblemReport, int64, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{}
+	if f.ProblemID != "" {
+		filter["problemId"] = f.ProblemID
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.Category != "" {
+		filter["category"] = f.Category
+	}
+
+	total, err := r.problemReportsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count problem reports: %w", dbError(err))
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.problemReportsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch problem reports: %w", dbError(err))
+	}
+	reports := []model.ProblemReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode problem reports: %w", dbError(err))
+	}
+	return reports, total, nil
+}
+
+// UpdateProblemReportStatus moves a report from fromStatus to status. It returns a customerrors.Conflict error when
+// the report is no longer in fromStatus, e.g. because another admin handled it meanwhile.
+func (r *Repository) UpdateProblemReportStatus(ctx context.Context, reportID, fromStatus, status, note, handledBy string, fixedRevision *time.Time) (*model.ProblemReport, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemReportObjectID(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"status": status, "handledBy": handledBy, "updatedAt": time.Now()}
+	if note != "" {
+		set["note"] = note
+	}
+	if fixedRevision != nil {
+		set["fixedRevision"] = *fixedRevision
+	}
+	var report model.ProblemReport
+	err = r.problemReportsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": fromStatus},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&report)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.Conflict("problem report %s is no longer %s", reportID, fromStatus)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update problem report %s: %w", reportID, dbError(err))
+	}
+	return &report, nil
+}
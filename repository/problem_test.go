@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"xcode/customerrors"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetProblemNotFound(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	created, err := repo.CreateProblem(ctx, &pb.CreateProblemRequest{Title: "Two Sum", Description: "Add two numbers.", Difficulty: "E"})
+	if err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+	deleted, err := repo.CreateProblem(ctx, &pb.CreateProblemRequest{Title: "Three Sum", Description: "Add three numbers.", Difficulty: "M"})
+	if err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+	if _, err := repo.DeleteProblem(ctx, &pb.DeleteProblemRequest{ProblemId: deleted.ProblemId}); err != nil {
+		t.Fatalf("DeleteProblem: %v", err)
+	}
+
+	if _, err := repo.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: created.ProblemId}); err != nil {
+		t.Fatalf("GetProblem of a live problem: %v", err)
+	}
+	tests := []struct {
+		name      string
+		problemID string
+	}{
+		{name: "soft deleted", problemID: deleted.ProblemId},
+		{name: "missing", problemID: primitive.NewObjectID().Hex()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem, err := repo.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: tt.problemID})
+			if problem != nil {
+				t.Errorf("problem = %+v, want none", problem)
+			}
+			if !errors.Is(err, ErrProblemNotFound) || !errors.Is(err, customerrors.ErrNotFound) {
+				t.Errorf("err = %v, want ErrProblemNotFound", err)
+			}
+		})
+	}
+}
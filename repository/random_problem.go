@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetRandomProblem samples one visible problem matching f, or returns a customerrors.NotFound error when none does
+func (r *Repository) GetRandomProblem(ctx context.Context, f model.RandomProblemFilter) (*model.Problem, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	match := bson.M{"deleted_at": nil, "visible": true}
+	if f.Difficulty != "" {
+		match["difficulty"] = f.Difficulty
+	}
+	if len(f.Tags) > 0 {
+		match["tags"] = bson.M{"$all": f.Tags}
+	}
+	if f.ExcludePremium {
+		match["tier"] = bson.M{"$ne": model.ProblemTierPremium}
+	}
+	excludeIDs := slices.Clone(f.ExcludeIDs)
+	if f.ExcludeSolvedBy != "" {
+		solved, err := r.submissionFirstSuccessCollection.Distinct(ctx, "problemId", bson.M{"userId": f.ExcludeSolvedBy})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch solved problems: %w", dbError(err))
+		}
+		for _, id := range solved {
+			if id, ok := id.(string); ok {
+				excludeIDs = append(excludeIDs, id)
+			}
+		}
+	}
+	if len(excludeIDs) > 0 {
+		match["_id"] = bson.M{"$nin": convertHexToObjectIDs(excludeIDs)}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sample", Value: bson.M{"size": 1}}},
+	}
+	cursor, err := r.problemsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample a problem: %w", dbError(err))
+	}
+	var problems []model.Problem
+	if err := cursor.All(ctx, &problems); err != nil {
+		return nil, fmt.Errorf("failed to decode sampled problem: %w", dbError(err))
+	}
+	if len(problems) == 0 {
+		return nil, customerrors.NotFound("no problem matches the filter")
+	}
+	return &problems[0], nil
+}
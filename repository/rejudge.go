@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// finishedRejudgeJobTTL is how long finished rejudge jobs can be polled before MongoDB drops them
+const finishedRejudgeJobTTL = 7 * 24 * time.Hour
+
+// activeRejudgeJobStatuses are the statuses of a job that has not finished
+var activeRejudgeJobStatuses = bson.M{"$in": bson.A{model.RejudgeJobQueued, model.RejudgeJobRunning}}
+
+// rejudgeSubmissionsFilter matches the submissions of a problem a rejudge reruns. INVALIDATED ones were taken away
+// by an admin and stay that way.
+func rejudgeSubmissionsFilter(problemID string, since *time.Time) bson.M {
+	filter := bson.M{"problemId": problemID, "status": bson.M{"$in": bson.A{"SUCCESS", "FAILED"}}, "invalidated": bson.M{"$ne": true}}
+	if since != nil {
+		filter["submittedAt"] = bson.M{"$gte": *since}
+	}
+	return filter
+}
+
+// CreateRejudgeJob queues job unless the problem already has an active one, which is returned instead with created
+// false. Active jobs not updated since staleBefore were abandoned by a replica that stopped; they are marked failed
+// and no longer count.
+func (r *Repository) CreateRejudgeJob(ctx context.Context, job model.RejudgeJob, staleBefore time.Time) (*model.RejudgeJob, bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	now := time.Now()
+	_, err := r.rejudgeJobsCollection.UpdateMany(ctx,
+		bson.M{"problemId": job.ProblemID, "status": activeRejudgeJobStatuses, "updatedAt": bson.M{"$lt": staleBefore}},
+		bson.M{"$set": bson.M{
+			"status":     model.RejudgeJobFailed,
+			"message":    "Rejudge was abandoned before it finished",
+			"updatedAt":  now,
+			"finishedAt": now,
+		}})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fail abandoned rejudge jobs of problem %s: %w", job.ProblemID, dbError(err))
+	}
+
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	job.Status = model.RejudgeJobQueued
+	job.CreatedAt, job.UpdatedAt, job.FinishedAt = now, now, nil
+
+	var active model.RejudgeJob
+	err = r.rejudgeJobsCollection.FindOneAndUpdate(ctx,
+		bson.M{"problemId": job.ProblemID, "status": activeRejudgeJobStatuses, "updatedAt": bson.M{"$gte": staleBefore}},
+		bson.M{"$setOnInsert": job},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&active)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create rejudge job for problem %s: %w", job.ProblemID, dbError(err))
+	}
+	return &active, active.ID == job.ID, nil
+}
+
+// GetRejudgeJob returns a customerrors.NotFound error when the ID is unknown or the job has expired
+func (r *Repository) GetRejudgeJob(ctx context.Context, jobID string) (*model.RejudgeJob, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, customerrors.Validation("invalid rejudge job id %q", jobID)
+	}
+
+	var job model.RejudgeJob
+	if err := r.rejudgeJobsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, customerrors.NotFound("rejudge job %s", jobID)
+		}
+		return nil, fmt.Errorf("failed to fetch rejudge job %s: %w", jobID, dbError(err))
+	}
+	return &job, nil
+}
+
+// StartRejudgeJob counts the submissions the job will rerun and marks it running
+func (r *Repository) StartRejudgeJob(ctx context.Context, job model.RejudgeJob) (int64, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	total, err := r.submissionsCollection.CountDocuments(ctx, rejudgeSubmissionsFilter(job.ProblemID, job.Since))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count submissions of problem %s: %w", job.ProblemID, dbError(err))
+	}
+	_, err = r.rejudgeJobsCollection.UpdateOne(ctx,
+		bson.M{"_id": job.ID},
+		bson.M{"$set": bson.M{"status": model.RejudgeJobRunning, "total": total, "updatedAt": time.Now()}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to start rejudge job %s: %w", job.ID.Hex(), dbError(err))
+	}
+	return total, nil
+}
+
+// RecordRejudgeProgress adds a finished batch to the job's counts
+func (r *Repository) RecordRejudgeProgress(ctx context.Context, id primitive.ObjectID, processed, changed, errored int64) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.rejudgeJobsCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"processed": processed, "changed": changed, "errored": errored},
+			"$set": bson.M{"updatedAt": time.Now()},
+		})
+	if err != nil {
+		return fmt.Errorf("failed to record progress of rejudge job %s: %w", id.Hex(), dbError(err))
+	}
+	return nil
+}
+
+// FinishRejudgeJob stores the job's final status
+func (r *Repository) FinishRejudgeJob(ctx context.Context, id primitive.ObjectID, status, message string, usersRescored int64) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	now := time.Now()
+	_, err := r.rejudgeJobsCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"status":        status,
+			"message":       message,
+			"usersRescored": usersRescored,
+			"updatedAt":     now,
+			"finishedAt":    now,
+		}})
+	if err != nil {
+		return fmt.Errorf("failed to finish rejudge job %s: %w", id.Hex(), dbError(err))
+	}
+	return nil
+}
+
+// GetRejudgeSubmissions returns the next limit submissions a rejudge of problemID reruns after the one with ID
+// after, in ID order; a zero after starts from the beginning
+func (r *Repository) GetRejudgeSubmissions(ctx context.Context, problemID string, since *time.Time, after primitive.ObjectID, limit int64) ([]model.Submission, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := rejudgeSubmissionsFilter(problemID, since)
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+	cursor, err := r.submissionsCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch submissions of problem %s: %w", problemID, dbError(err))
+	}
+	var submissions []model.Submission
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode submissions of problem %s: %w", problemID, dbError(err))
+	}
+	return submissions, nil
+}
+
+// SetSubmissionStatus changes a submission's verdict. Its score and first success are left to
+// ReconcileFirstSuccess.
+func (r *Repository) SetSubmissionStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.submissionsCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return fmt.Errorf("failed to set status of submission %s: %w", id.Hex(), dbError(err))
+	}
+	return nil
+}
+
+// ReconcileFirstSuccess makes userID's earliest accepted submission of problemID their first success, moving the
+// score onto it, or drops the first success when nothing is accepted any more. It reports whether anything moved;
+// the leaderboards are not touched.
+func (r *Repository) ReconcileFirstSuccess(ctx context.Context, userID, problemID string) (bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	var changed bool
+	err := r.WithTransaction(ctx, func(ctx context.Context) error {
+		changed = false
+		var earliest *model.Submission
+		var submission model.Submission
+		err := r.submissionsCollection.FindOne(ctx,
+			bson.M{"userId": userID, "problemId": problemID, "status": "SUCCESS", "invalidated": bson.M{"$ne": true}},
+			options.FindOne().SetSort(bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}),
+		).Decode(&submission)
+		switch {
+		case err == nil:
+			earliest = &submission
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			return fmt.Errorf("failed to find earliest accepted submission: %w", dbError(err))
+		}
+
+		var current *model.ProblemDone
+		var done model.ProblemDone
+		err = r.submissionFirstSuccessCollection.FindOne(ctx, bson.M{"userId": userID, "problemId": problemID}).Decode(&done)
+		switch {
+		case err == nil:
+			current = &done
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			return fmt.Errorf("failed to fetch first success: %w", dbError(err))
+		}
+
+		if (current == nil && earliest == nil) || (current != nil && earliest != nil && current.SubmissionID == earliest.ID.Hex()) {
+			return nil
+		}
+		changed = true
+
+		if current != nil {
+			if _, err := r.submissionFirstSuccessCollection.DeleteOne(ctx, bson.M{"_id": current.ID}); err != nil {
+				return fmt.Errorf("failed to remove first success: %w", dbError(err))
+			}
+			if previous, err := primitive.ObjectIDFromHex(current.SubmissionID); err == nil {
+				_, err := r.submissionsCollection.UpdateOne(ctx, bson.M{"_id": previous}, bson.M{"$set": bson.M{"isFirst": false, "score": 0}})
+				if err != nil {
+					return fmt.Errorf("failed to clear previous first success: %w", dbError(err))
+				}
+			}
+		}
+		if earliest == nil {
+			return nil
+		}
+
+		score := r.scores.Score(earliest.Difficulty)
+		_, err = r.submissionsCollection.UpdateOne(ctx, bson.M{"_id": earliest.ID}, bson.M{"$set": bson.M{"isFirst": true, "score": score}})
+		if err != nil {
+			return fmt.Errorf("failed to mark first success: %w", dbError(err))
+		}
+		_, err = r.submissionFirstSuccessCollection.InsertOne(ctx, model.ProblemDone{
+			ID:           primitive.NewObjectID(),
+			SubmissionID: earliest.ID.Hex(),
+			ProblemID:    earliest.ProblemID,
+			UserID:       earliest.UserID,
+			Title:        earliest.Title,
+			Language:     earliest.Language,
+			Difficulty:   earliest.Difficulty,
+			SubmittedAt:  earliest.SubmittedAt,
+			Country:      earliest.Country,
+			Score:        score,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert first success: %w", dbError(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reconcile first success of %s on problem %s: %w", userID, problemID, err)
+	}
+	return changed, nil
+}
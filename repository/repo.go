@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"xcode/customerrors"
 	"xcode/model"
 
 	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
@@ -24,19 +26,93 @@ type Repository struct {
 	challengeCollection              *mongo.Collection
 	submissionsCollection            *mongo.Collection
 	submissionFirstSuccessCollection *mongo.Collection
+	firstSolversCollection           *mongo.Collection
+	sharedSubmissionsCollection      *mongo.Collection
+	moderationAuditCollection        *mongo.Collection
+	leaderboardBansCollection        *mongo.Collection
+	codeDraftsCollection             *mongo.Collection
+	leaderboardSeasonsCollection     *mongo.Collection
+	leaderboardSnapshotsCollection   *mongo.Collection
+	deadLettersCollection            *mongo.Collection
+	leaderboardUpdatesCollection     *mongo.Collection
+	auditLogCollection               *mongo.Collection
+	outboxCollection                 *mongo.Collection
+	problemsArchiveCollection        *mongo.Collection
+	validationJobsCollection         *mongo.Collection
+	contestsCollection               *mongo.Collection
+	contestRegistrationsCollection   *mongo.Collection
+	contestStandingsCollection       *mongo.Collection
+	userRatingsCollection            *mongo.Collection
+	virtualParticipationsCollection  *mongo.Collection
+	solutionsCollection              *mongo.Collection
+	solutionVotesCollection          *mongo.Collection
+	problemReportsCollection         *mongo.Collection
+	apiKeysCollection                *mongo.Collection
+	webhooksCollection               *mongo.Collection
+	webhookDeliveriesCollection      *mongo.Collection
+	assignmentsCollection            *mongo.Collection
+	assignmentEnrollmentsCollection  *mongo.Collection
+	hiddenProblemsCollection         *mongo.Collection
+	rejudgeJobsCollection            *mongo.Collection
+	organizationsCollection          *mongo.Collection
+	organizationMembersCollection    *mongo.Collection
+	executionProfilesCollection      *mongo.Collection
 	lb                               *redisboard.Leaderboard
+	timeouts                         Timeouts
+	scores                           ScoreTable
+	testCaseLimits                   TestCaseLimits
 
 	logger *zap_betterstack.BetterStackLogStreamer
 }
 
-func NewRepository(client *mongo.Client, lb *redisboard.Leaderboard, logger *zap_betterstack.BetterStackLogStreamer) *Repository {
+func NewRepository(client *mongo.Client, lb *redisboard.Leaderboard, opts Options, logger *zap_betterstack.BetterStackLogStreamer) *Repository {
+	if opts.Scores == (ScoreTable{}) {
+		opts.Scores = DefaultScoreTable
+	}
+	if opts.TestCaseLimits == (TestCaseLimits{}) {
+		opts.TestCaseLimits = DefaultTestCaseLimits
+	}
 	return &Repository{
 		mongoclientInstance:              client,
 		problemsCollection:               client.Database("problems_db").Collection("problems"),
 		submissionsCollection:            client.Database("submissions_db").Collection("submissions"),
 		challengeCollection:              client.Database("challenges_db").Collection("challenges"),
 		submissionFirstSuccessCollection: client.Database("submissions_db").Collection("submissionsfirstsuccess"),
+		firstSolversCollection:           client.Database("submissions_db").Collection("firstsolvers"),
+		sharedSubmissionsCollection:      client.Database("submissions_db").Collection("sharedsubmissions"),
+		moderationAuditCollection:        client.Database("submissions_db").Collection("moderationaudit"),
+		leaderboardBansCollection:        client.Database("leaderboards_db").Collection("bans"),
+		codeDraftsCollection:             client.Database("submissions_db").Collection("codedrafts"),
+		leaderboardSeasonsCollection:     client.Database("leaderboards_db").Collection("seasons"),
+		leaderboardSnapshotsCollection:   client.Database("leaderboards_db").Collection("dailysnapshots"),
+		deadLettersCollection:            client.Database("problems_db").Collection("deadletters"),
+		leaderboardUpdatesCollection:     client.Database("submissions_db").Collection("leaderboardupdates"),
+		auditLogCollection:               client.Database("problems_db").Collection("auditlog"),
+		outboxCollection:                 client.Database("problems_db").Collection("outbox"),
+		problemsArchiveCollection:        client.Database("problems_db").Collection("problems_archive"),
+		validationJobsCollection:         client.Database("problems_db").Collection("validation_jobs"),
+		contestsCollection:               client.Database("contests_db").Collection("contests"),
+		contestRegistrationsCollection:   client.Database("contests_db").Collection("registrations"),
+		contestStandingsCollection:       client.Database("contests_db").Collection("standings"),
+		userRatingsCollection:            client.Database("contests_db").Collection("ratings"),
+		virtualParticipationsCollection:  client.Database("contests_db").Collection("virtual_participations"),
+		solutionsCollection:              client.Database("problems_db").Collection("solutions"),
+		solutionVotesCollection:          client.Database("problems_db").Collection("solution_votes"),
+		problemReportsCollection:         client.Database("problems_db").Collection("problem_reports"),
+		apiKeysCollection:                client.Database("problems_db").Collection("api_keys"),
+		webhooksCollection:               client.Database("problems_db").Collection("webhooks"),
+		webhookDeliveriesCollection:      client.Database("problems_db").Collection("webhook_deliveries"),
+		assignmentsCollection:            client.Database("contests_db").Collection("assignments"),
+		assignmentEnrollmentsCollection:  client.Database("contests_db").Collection("assignment_enrollments"),
+		hiddenProblemsCollection:         client.Database("problems_db").Collection("hidden_problems"),
+		rejudgeJobsCollection:            client.Database("problems_db").Collection("rejudge_jobs"),
+		organizationsCollection:          client.Database("problems_db").Collection("organizations"),
+		organizationMembersCollection:    client.Database("problems_db").Collection("organization_members"),
+		executionProfilesCollection:      client.Database("problems_db").Collection("execution_profiles"),
 		lb:                               lb,
+		timeouts:                         opts.Timeouts,
+		scores:                           opts.Scores,
+		testCaseLimits:                   opts.TestCaseLimits,
 		logger:                           logger,
 	}
 }
@@ -45,9 +121,38 @@ func NewRepository(client *mongo.Client, lb *redisboard.Leaderboard, logger *zap
 func (r *Repository) SyncLeaderboardToRedis(ctx context.Context) error {
 
 	syncStartTime := time.Now()
-	r.logger.Log(zapcore.InfoLevel, "REDIBOARDSYNC", "Syncing Leaderboard to Redis started", nil, "REPOSITORY", nil)
+	r.logger.LogContext(ctx, zapcore.InfoLevel, "Syncing Leaderboard to Redis started", map[string]any{
+		"method": "SyncLeaderboardToRedis",
+	}, "REPOSITORY", nil)
+
+	if err := r.syncLeaderboardToRedis(ctx, r.lb, bson.M{}); err != nil {
+		return err
+	}
+
+	r.logger.LogContext(ctx, zapcore.InfoLevel, "Syncing Leaderboard to Redis Finished", map[string]any{
+		"method":   "SyncLeaderboardToRedis",
+		"duration": time.Since(syncStartTime).Seconds(),
+	}, "REPOSITORY", nil)
+
+	return nil
+}
+
+// SyncPeriodLeaderboardToRedis rebuilds a seasonal RedisBoard from first successes submitted since the season start
+func (r *Repository) SyncPeriodLeaderboardToRedis(ctx context.Context, lb *redisboard.Leaderboard, since time.Time) error {
+	return r.syncLeaderboardToRedis(ctx, lb, bson.M{"submittedAt": bson.M{"$gte": since}})
+}
+
+func (r *Repository) syncLeaderboardToRedis(ctx context.Context, lb *redisboard.Leaderboard, match bson.M) error {
+	banned, err := r.GetLeaderboardBannedUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(banned) > 0 {
+		match["userId"] = bson.M{"$nin": banned}
+	}
 
 	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
 		// Sort by SubmittedAt to ensure consistent country selection
 		{{Key: "$sort", Value: bson.M{"submittedAt": 1}}},
 		// Group by UserID, sum Score, and take first Country
@@ -81,100 +186,127 @@ func (r *Repository) SyncLeaderboardToRedis(ctx context.Context) error {
 		}
 
 		// fmt.Println("adding ",user)
-		if err := r.lb.AddUser(user); err != nil {
+		if err := lb.AddUser(user); err != nil {
 			return fmt.Errorf("failed to add user %s to RedisBoard: %w", result.ID, err)
 		}
 	}
 
-	r.logger.Log(zapcore.InfoLevel, "REDIBOARDSYNC", "Syncing Leaderboard to Redis Finished", map[string]any{
-		"duration": time.Since(syncStartTime).Seconds(),
-	}, "REPOSITORY", nil)
-
 	return cursor.Err()
 }
 
-// PushSubmissionData handles submission insertion and RedisBoard updates
-func (r *Repository) PushSubmissionData(ctx context.Context, submission *model.Submission, status string) error {
+// errFirstSuccessTaken aborts a submission write that lost the race to be the user's first success on the problem
+var errFirstSuccessTaken = errors.New("first success already recorded")
+
+// PushSubmissionData handles submission insertion and RedisBoard updates. events, when not nil, is called with the
+// stored submission and the events it returns are written to the outbox in the same transaction.
+func (r *Repository) PushSubmissionData(ctx context.Context, submission *model.Submission, status string, events func(submission model.Submission) ([]model.OutboxEvent, error)) error {
 	if r == nil || submission == nil {
 		return fmt.Errorf("repository or submission is nil")
 	}
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
 
 	submission.Country = strings.ToUpper(submission.Country)
 
-	// Count successful submissions for the problem
-	SuccessCount, err := r.submissionsCollection.CountDocuments(ctx, bson.M{
-		"userId":    submission.UserID,
-		"problemId": submission.ProblemID,
-		"status":    "SUCCESS",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to count successful submissions: %w", err)
-	}
-
-	// Insert into submissions collection (all history)
-	if SuccessCount == 0 && status == "SUCCESS" {
-		submission.Score = CalculateScore(submission.Difficulty)
-		submission.IsFirst = true
-	}
-	submissionObject, err := r.submissionsCollection.InsertOne(ctx, submission)
-	if err != nil {
-		return fmt.Errorf("failed to insert into submissions: %w", err)
-	}
-
-	// Extract submission ID
-	submissionID, ok := submissionObject.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return fmt.Errorf("failed to assert submission ID to ObjectID")
-	}
-	submissionIDHex := submissionID.Hex()
-	fmt.Println("submission added:", submissionIDHex)
-
-	// Handle first successful submission
-	if status == "SUCCESS" && submission.IsFirst {
-		leaderboardEntry := model.ProblemDone{
-			ID:           primitive.NewObjectID(),
-			SubmissionID: submissionIDHex,
-			ProblemID:    submission.ProblemID,
-			UserID:       submission.UserID,
-			Title:        submission.Title,
-			Language:     submission.Language,
-			Difficulty:   submission.Difficulty,
-			SubmittedAt:  submission.SubmittedAt,
-			Country:      submission.Country,
-			Score:        submission.Score,
+	// the submission, its first success and the pending leaderboard update are written together, so a crash cannot
+	// leave a first success whose score never reaches the board
+	var update *model.LeaderboardUpdate
+	firstTaken := false
+	write := func(ctx context.Context) error {
+		submission.ID = primitive.NewObjectID()
+		submission.Score, submission.IsFirst = 0, false
+		update = nil
+
+		// Count successful submissions for the problem
+		SuccessCount, err := r.submissionsCollection.CountDocuments(ctx, bson.M{
+			"userId":    submission.UserID,
+			"problemId": submission.ProblemID,
+			"status":    "SUCCESS",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to count successful submissions: %w", err)
 		}
 
-		_, err = r.submissionFirstSuccessCollection.InsertOne(ctx, leaderboardEntry)
-		if err != nil {
-			return fmt.Errorf("failed to insert into submissionsfirstsuccess: %w", err)
+		// Handle first successful submission. Concurrent submissions can all count none before any commits; the
+		// unique first success index lets exactly one of them in, the rest are written again as repeats.
+		if SuccessCount == 0 && status == "SUCCESS" && !firstTaken {
+			submission.Score = r.scores.Score(submission.Difficulty)
+			submission.IsFirst = true
+
+			leaderboardEntry := model.ProblemDone{
+				ID:           primitive.NewObjectID(),
+				SubmissionID: submission.ID.Hex(),
+				ProblemID:    submission.ProblemID,
+				UserID:       submission.UserID,
+				Title:        submission.Title,
+				Language:     submission.Language,
+				Difficulty:   submission.Difficulty,
+				SubmittedAt:  submission.SubmittedAt,
+				Country:      submission.Country,
+				Score:        submission.Score,
+			}
+			if _, err := r.submissionFirstSuccessCollection.InsertOne(ctx, leaderboardEntry); err != nil {
+				if mongo.IsDuplicateKeyError(err) {
+					return errFirstSuccessTaken
+				}
+				return fmt.Errorf("failed to insert into submissionsfirstsuccess: %w", err)
+			}
 		}
-		fmt.Println("first successful submission added")
 
-		// Update RedisBoard
-		user := redisboard.User{
-			ID:     submission.UserID,
-			Entity: submission.Country,
-			Score:  float64(submission.Score),
+		// Insert into submissions collection (all history)
+		if _, err := r.submissionsCollection.InsertOne(ctx, submission); err != nil {
+			return fmt.Errorf("failed to insert into submissions: %w", err)
 		}
-		// Check if user exists in RedisBoard
-		existingEntity, err := r.lb.GetUserEntity(submission.UserID)
-		if err != nil || existingEntity == "" {
-			// Add new user
-			if err := r.lb.AddUser(user); err != nil {
-				return fmt.Errorf("failed to add user %s to RedisBoard: %w", submission.UserID, err)
+		if events != nil {
+			outbox, err := events(*submission)
+			if err != nil {
+				return err
 			}
-		} else {
-			// Increment score
-			if err := r.lb.IncrementScore(submission.UserID, existingEntity, float64(submission.Score)); err != nil {
-				return fmt.Errorf("failed to increment score for user %s: %w", submission.UserID, err)
+			if err := r.SaveOutboxEvents(ctx, outbox...); err != nil {
+				return err
 			}
 		}
+		if !submission.IsFirst {
+			return nil
+		}
+
+		update = &model.LeaderboardUpdate{
+			SubmissionID: submission.ID.Hex(),
+			UserID:       submission.UserID,
+			Score:        submission.Score,
+			CreatedAt:    time.Now(),
+		}
+		if _, err := r.leaderboardUpdatesCollection.InsertOne(ctx, update); err != nil {
+			return fmt.Errorf("failed to insert leaderboard update: %w", err)
+		}
+		return nil
+	}
+	err := r.WithTransaction(ctx, write)
+	if errors.Is(err, errFirstSuccessTaken) {
+		// nothing of the attempt was written, the first success belongs to a submission that committed meanwhile
+		firstTaken = true
+		err = r.WithTransaction(ctx, write)
+	}
+	if err != nil {
+		return err
+	}
+	if update == nil {
+		return nil
+	}
+
+	r.recordFirstSolver(ctx, submission, submission.ID.Hex())
+
+	// the submission is stored; a board that could not be updated now is caught up by ApplyPendingLeaderboardUpdates
+	if err := r.applyLeaderboardUpdate(ctx, *update); err != nil {
+		r.recordLeaderboardUpdateFailure(ctx, *update, err)
 	}
 	return nil
 }
 
 // GetTopKGlobalMongo returns the top K users globally
 func (r *Repository) GetTopKGlobalMongo(ctx context.Context, k int) ([]model.UserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	pipeline := mongo.Pipeline{
 		{{Key: "$group", Value: bson.M{
 			"_id":            "$userId",
@@ -204,6 +336,8 @@ func (r *Repository) GetTopKGlobalMongo(ctx context.Context, k int) ([]model.Use
 
 // GetTopKEntityMongo returns the top K users for a specific entity
 func (r *Repository) GetTopKEntityMongo(ctx context.Context, entity string, k int) ([]model.UserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: bson.M{"country": entity}}},
 		{{Key: "$group", Value: bson.M{
@@ -232,90 +366,24 @@ func (r *Repository) GetTopKEntityMongo(ctx context.Context, entity string, k in
 	return results, cursor.Err()
 }
 
-// GetUserRankMongo returns the global and entity ranks for a user
+// GetUserRankMongo returns the global and entity ranks for a user; the entity is the country of the user's first
+// successes. MongoDB counts the users with a higher total instead of the standings being walked here, see
+// GetUserStandingMongo. A user without first successes has no rank and gets a customerrors.ErrNotFound.
 func (r *Repository) GetUserRankMongo(ctx context.Context, userID string) (globalRank, entityRank int, err error) {
-	// Global rank
-	globalPipeline := mongo.Pipeline{
-		{{Key: "$group", Value: bson.M{
-			"_id":        "$userId",
-			"totalScore": bson.M{"$sum": "$score"},
-		}}},
-		{{Key: "$sort", Value: bson.M{"totalScore": -1}}},
-	}
-	cursor, err := r.submissionFirstSuccessCollection.Aggregate(ctx, globalPipeline)
+	standing, entity, _, err := r.GetUserStandingMongo(ctx, userID)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to aggregate global rank: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	globalRank = 0
-	foundGlobal := false
-	for cursor.Next(ctx) {
-		globalRank++
-		var result struct {
-			ID string `bson:"_id"`
-		}
-		if err := cursor.Decode(&result); err != nil {
-			return 0, 0, fmt.Errorf("failed to decode global rank result: %w", err)
-		}
-		if result.ID == userID {
-			foundGlobal = true
-			break
-		}
-	}
-	if !foundGlobal {
-		globalRank = 0
+		return 0, 0, err
 	}
-
-	// Entity rank
-	var userEntity string
-	if err := r.submissionFirstSuccessCollection.FindOne(ctx, bson.M{"userId": userID}).Decode(&struct {
-		Country string `bson:"country"`
-	}{Country: userEntity}); err != nil {
-		if err == mongo.ErrNoDocuments {
-			return globalRank, 0, nil
-		}
-		return 0, 0, fmt.Errorf("failed to find user entity: %w", err)
+	if standing.Rank == 0 {
+		return 0, 0, customerrors.NotFound("ranked user %s", userID)
 	}
-
-	entityPipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.M{"country": userEntity}}},
-		{{Key: "$group", Value: bson.M{
-			"_id":        "$userId",
-			"totalScore": bson.M{"$sum": "$score"},
-		}}},
-		{{Key: "$sort", Value: bson.M{"totalScore": -1}}},
-	}
-	cursor, err = r.submissionFirstSuccessCollection.Aggregate(ctx, entityPipeline)
-	if err != nil {
-		return globalRank, 0, fmt.Errorf("failed to aggregate entity rank: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	entityRank = 0
-	foundEntity := false
-	for cursor.Next(ctx) {
-		entityRank++
-		var result struct {
-			ID string `bson:"_id"`
-		}
-		if err := cursor.Decode(&result); err != nil {
-			return globalRank, 0, fmt.Errorf("failed to decode entity rank result: %w", err)
-		}
-		if result.ID == userID {
-			foundEntity = true
-			break
-		}
-	}
-	if !foundEntity {
-		entityRank = 0
-	}
-
-	return globalRank, entityRank, cursor.Err()
+	return int(standing.Rank), int(entity), nil
 }
 
 // GetLeaderboardDataMongo returns leaderboard data for a specific user
 func (r *Repository) GetLeaderboardDataMongo(ctx context.Context, userID string) (*model.UserScore, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	pipeline := mongo.Pipeline{
 		{{Key: "$match", Value: bson.M{"userId": userID}}},
 		{{Key: "$group", Value: bson.M{
@@ -343,12 +411,14 @@ func (r *Repository) GetLeaderboardDataMongo(ctx context.Context, userID string)
 }
 
 func (r *Repository) CreateProblem(ctx context.Context, req *pb.CreateProblemRequest) (*pb.CreateProblemResponse, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
 	count, err := r.problemsCollection.CountDocuments(ctx, bson.M{"title": req.Title, "deleted_at": nil})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if count > 0 {
-		return &pb.CreateProblemResponse{Success: false, Message: "Problem with this title already exists"}, nil
+		return nil, customerrors.Conflict("problem with title %q already exists", req.Title)
 	}
 	now := time.Now()
 	problem := model.Problem{
@@ -366,43 +436,45 @@ func (r *Repository) CreateProblem(ctx context.Context, req *pb.CreateProblemReq
 	}
 	res, err := r.problemsCollection.InsertOne(ctx, problem)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	return &pb.CreateProblemResponse{ProblemId: res.InsertedID.(primitive.ObjectID).Hex(), Success: true, Message: "Problem created successfully"}, nil
 }
 
 func (r *Repository) UpdateProblem(ctx context.Context, req *pb.UpdateProblemRequest) (*pb.UpdateProblemResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.UpdateProblemResponse{Success: false, Message: "Problem not found or deleted"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
 	// resetValidation := false
 	if req.Title != nil {
 		if *req.Title == "" {
-			return &pb.UpdateProblemResponse{Success: false, Message: "Title cannot be empty"}, nil
+			return nil, customerrors.Validation("title cannot be empty")
 		}
 		count, err := r.problemsCollection.CountDocuments(ctx, bson.M{"title": *req.Title, "_id": bson.M{"$ne": id}, "deleted_at": nil})
 		if err != nil {
-			return nil, err
+			return nil, dbError(err)
 		}
 		if count > 0 {
-			return &pb.UpdateProblemResponse{Success: false, Message: "Another problem with this title already exists"}, nil
+			return nil, customerrors.Conflict("another problem with title %q already exists", *req.Title)
 		}
 		update["$set"].(bson.M)["title"] = *req.Title
 		// resetValidation = true
 	}
 	if req.Description != nil {
 		if *req.Description == "" {
-			return &pb.UpdateProblemResponse{Success: false, Message: "Description cannot be empty"}, nil
+			return nil, customerrors.Validation("description cannot be empty")
 		}
 		update["$set"].(bson.M)["description"] = *req.Description
 		// resetValidation = true
@@ -413,7 +485,7 @@ func (r *Repository) UpdateProblem(ctx context.Context, req *pb.UpdateProblemReq
 	}
 	if req.Difficulty != nil {
 		if *req.Difficulty == "" {
-			return &pb.UpdateProblemResponse{Success: false, Message: "Difficulty cannot be empty"}, nil
+			return nil, customerrors.Validation("difficulty cannot be empty")
 		}
 		update["$set"].(bson.M)["difficulty"] = *req.Difficulty
 		// resetValidation = true
@@ -423,16 +495,18 @@ func (r *Repository) UpdateProblem(ctx context.Context, req *pb.UpdateProblemReq
 
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.UpdateProblemResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.UpdateProblemResponse{Success: true, Message: "Problem updated successfully"}, nil
 }
 
 func (r *Repository) DeleteProblem(ctx context.Context, req *pb.DeleteProblemRequest) (*pb.DeleteProblemResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
@@ -440,31 +514,36 @@ func (r *Repository) DeleteProblem(ctx context.Context, req *pb.DeleteProblemReq
 	update := bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}}
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.DeleteProblemResponse{Success: false, Message: "Problem not found or already deleted"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.DeleteProblemResponse{Success: true, Message: "Problem marked as deleted"}, nil
 }
 
 func (r *Repository) GetProblem(ctx context.Context, req *pb.GetProblemRequest) (*model.Problem, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
-		return &model.Problem{}, err
+		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &model.Problem{}, nil
+		return nil, problemNotFound(req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	return &problem, nil
 }
 
-func (r *Repository) ListProblems(ctx context.Context, req *pb.ListProblemsRequest) (*pb.ListProblemsResponse, error) {
+// ListProblems returns a page of problems matching req, leaving out excludeIDs
+func (r *Repository) ListProblems(ctx context.Context, req *pb.ListProblemsRequest, excludeIDs []string) (*pb.ListProblemsResponse, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	fmt.Println("list problems", req)
 
 	filter := bson.M{"deleted_at": nil}
@@ -480,6 +559,9 @@ func (r *Repository) ListProblems(ctx context.Context, req *pb.ListProblemsReque
 			{"description": bson.M{"$regex": req.SearchQuery, "$options": "i"}},
 		}
 	}
+	if len(excludeIDs) > 0 {
+		filter["_id"] = bson.M{"$nin": convertHexToObjectIDs(excludeIDs)}
+	}
 
 	opts := options.Find().SetSkip(int64(req.Page-1) * int64(req.PageSize)).SetLimit(int64(req.PageSize))
 	cursor, err := r.problemsCollection.Find(ctx, filter, opts)
@@ -508,23 +590,25 @@ func (r *Repository) ListProblems(ctx context.Context, req *pb.ListProblemsReque
 }
 
 func (r *Repository) AddTestCases(ctx context.Context, req *pb.AddTestCasesRequest) (*pb.AddTestCasesResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.AddTestCasesResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-	if len(problem.TestCases.Run)+len(req.Testcases.Run) > 3 {
-		return &pb.AddTestCasesResponse{Success: false, Message: "Run test case limit (3) exceeded"}, nil
+	if len(problem.TestCases.Run)+len(req.Testcases.Run) > r.testCaseLimits.Run {
+		return nil, customerrors.Validation("run test case limit (%d) exceeded", r.testCaseLimits.Run)
 	}
-	if len(problem.TestCases.Submit)+len(req.Testcases.Submit) > 100 {
-		return &pb.AddTestCasesResponse{Success: false, Message: "Submit test case limit (100) exceeded"}, nil
+	if len(problem.TestCases.Submit)+len(req.Testcases.Submit) > r.testCaseLimits.Submit {
+		return nil, customerrors.Validation("submit test case limit (%d) exceeded", r.testCaseLimits.Submit)
 	}
 	existingRunIDs := make(map[string]bool)
 	existingSubmitIDs := make(map[string]bool)
@@ -548,10 +632,10 @@ func (r *Repository) AddTestCases(ctx context.Context, req *pb.AddTestCasesReque
 	}
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.AddTestCasesResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.AddTestCasesResponse{
 		Success:    true,
@@ -561,17 +645,19 @@ func (r *Repository) AddTestCases(ctx context.Context, req *pb.AddTestCasesReque
 }
 
 func (r *Repository) DeleteTestCase(ctx context.Context, req *pb.DeleteTestCaseRequest) (*pb.DeleteTestCaseResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.DeleteTestCaseResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	field := "testcases.submit"
 	testcases := problem.TestCases.Submit
@@ -587,7 +673,7 @@ func (r *Repository) DeleteTestCase(ctx context.Context, req *pb.DeleteTestCaseR
 		}
 	}
 	if !found {
-		return &pb.DeleteTestCaseResponse{Success: false, Message: "Testcase not found"}, nil
+		return nil, customerrors.NotFound("testcase %s in problem %s", req.TestcaseId, req.ProblemId)
 	}
 	update := bson.M{
 		"$pull": bson.M{field: bson.M{"id": req.TestcaseId}},
@@ -595,30 +681,32 @@ func (r *Repository) DeleteTestCase(ctx context.Context, req *pb.DeleteTestCaseR
 	}
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.DeleteTestCaseResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.DeleteTestCaseResponse{Success: true, Message: "Testcase deleted successfully"}, nil
 }
 
 func (r *Repository) AddLanguageSupport(ctx context.Context, req *pb.AddLanguageSupportRequest) (*pb.AddLanguageSupportResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.AddLanguageSupportResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	for _, lang := range problem.SupportedLanguages {
 		if lang == req.Language {
-			return &pb.AddLanguageSupportResponse{Success: false, Message: "Language already supported"}, nil
+			return nil, customerrors.Conflict("language %s is already supported", req.Language)
 		}
 	}
 	update := bson.M{
@@ -635,26 +723,28 @@ func (r *Repository) AddLanguageSupport(ctx context.Context, req *pb.AddLanguage
 	}
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.AddLanguageSupportResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.AddLanguageSupportResponse{Success: true, Message: "Language support added successfully"}, nil
 }
 
 func (r *Repository) UpdateLanguageSupport(ctx context.Context, req *pb.UpdateLanguageSupportRequest) (*pb.UpdateLanguageSupportResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.UpdateLanguageSupportResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	langExists := false
 	for _, lang := range problem.SupportedLanguages {
@@ -664,7 +754,7 @@ func (r *Repository) UpdateLanguageSupport(ctx context.Context, req *pb.UpdateLa
 		}
 	}
 	if !langExists {
-		return &pb.UpdateLanguageSupportResponse{Success: false, Message: "Language not supported"}, nil
+		return nil, customerrors.NotFound("language %s is not supported by problem %s", req.Language, req.ProblemId)
 	}
 	update := bson.M{
 		"$set": bson.M{
@@ -679,26 +769,28 @@ func (r *Repository) UpdateLanguageSupport(ctx context.Context, req *pb.UpdateLa
 	}
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.UpdateLanguageSupportResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.UpdateLanguageSupportResponse{Success: true, Message: "Language support updated successfully"}, nil
 }
 
 func (r *Repository) RemoveLanguageSupport(ctx context.Context, req *pb.RemoveLanguageSupportRequest) (*pb.RemoveLanguageSupportResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.RemoveLanguageSupportResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	langExists := false
 	for _, lang := range problem.SupportedLanguages {
@@ -708,7 +800,7 @@ func (r *Repository) RemoveLanguageSupport(ctx context.Context, req *pb.RemoveLa
 		}
 	}
 	if !langExists {
-		return &pb.RemoveLanguageSupportResponse{Success: false, Message: "Language not supported"}, nil
+		return nil, customerrors.NotFound("language %s is not supported by problem %s", req.Language, req.ProblemId)
 	}
 	update := bson.M{
 		"$pull":  bson.M{"supported_languages": req.Language},
@@ -717,26 +809,28 @@ func (r *Repository) RemoveLanguageSupport(ctx context.Context, req *pb.RemoveLa
 	}
 	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	if result.MatchedCount == 0 {
-		return &pb.RemoveLanguageSupportResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	return &pb.RemoveLanguageSupportResponse{Success: true, Message: "Language support removed successfully"}, nil
 }
 
 func (r *Repository) GetLanguageSupports(ctx context.Context, req *pb.GetLanguageSupportsRequest) (*pb.GetLanguageSupportsResponse, error) {
-	id, err := primitive.ObjectIDFromHex(req.ProblemId)
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(req.ProblemId)
 	if err != nil {
 		return nil, err
 	}
 	var problem model.Problem
 	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.GetLanguageSupportsResponse{Success: false, Message: "Problem not found"}, nil
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	validateCode := make(map[string]*pb.ValidationCode)
 	for lang, vc := range problem.ValidateCode {
@@ -755,6 +849,8 @@ func (r *Repository) GetLanguageSupports(ctx context.Context, req *pb.GetLanguag
 }
 
 func (r *Repository) BasicValidationByProblemID(ctx context.Context, req *pb.FullValidationByProblemIDRequest) (*pb.FullValidationByProblemIDResponse, model.Problem, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
 	id, err := primitive.ObjectIDFromHex(req.ProblemId)
 	if err != nil {
 		return &pb.FullValidationByProblemIDResponse{Success: false, Message: "Invalid problem ID", ErrorType: "INVALID_ID"}, model.Problem{}, nil
@@ -790,6 +886,8 @@ func (r *Repository) BasicValidationByProblemID(ctx context.Context, req *pb.Ful
 }
 
 func (r *Repository) ToggleProblemValidaition(ctx context.Context, problemID string, status bool) bool {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
 	now := time.Now()
 	problemUUID, _ := primitive.ObjectIDFromHex(problemID)
 	update := bson.M{"$set": bson.M{"validated": status, "validated_at": now, "updated_at": now}}
@@ -799,7 +897,27 @@ func (r *Repository) ToggleProblemValidaition(ctx context.Context, problemID str
 	return problem.Validated
 }
 
+// SetValidationHash records the content hash a language of the problem passed validation with
+func (r *Repository) SetValidationHash(ctx context.Context, problemID, language, hash string) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return err
+	}
+	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, bson.M{"$set": bson.M{"validation_hashes." + language: hash}})
+	if err != nil {
+		return dbError(err)
+	}
+	if result.MatchedCount == 0 {
+		return customerrors.NotFound("problem %s", problemID)
+	}
+	return nil
+}
+
 func (r *Repository) GetSubmissionsByOptionalProblemID(ctx context.Context, req *pb.GetSubmissionsRequest) (*pb.GetSubmissionsResponse, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	var filter bson.M
 	if req.ProblemId != nil && *req.ProblemId != "" {
 		fmt.Println(req)
@@ -834,6 +952,7 @@ func (r *Repository) GetSubmissionsByOptionalProblemID(ctx context.Context, req
 	cursor, err := r.submissionsCollection.Find(ctx, filter, &options.FindOptions{
 		Skip:  func(i int32) *int64 { v := int64(i); return &v }(skip),
 		Limit: func(i int32) *int64 { v := int64(i); return &v }(limit),
+		Sort:  submissionsListSort,
 	})
 	if err != nil {
 		fmt.Println("error finding submissions:", err)
@@ -853,29 +972,7 @@ func (r *Repository) GetSubmissionsByOptionalProblemID(ctx context.Context, req
 
 	pbSubmissions := make([]*pb.Submission, len(submissions))
 	for i, sub := range submissions {
-		var challengeID string
-		if sub.ChallengeID != nil {
-			challengeID = *sub.ChallengeID
-		}
-		pbSubmissions[i] = &pb.Submission{
-			Id:          sub.ID.Hex(),
-			ProblemId:   sub.ProblemID,
-			Title:       sub.Title,
-			UserId:      sub.UserID,
-			ChallengeId: challengeID,
-			SubmittedAt: &pb.Timestamp{
-				Seconds: sub.SubmittedAt.Unix(),
-				Nanos:   int32(sub.SubmittedAt.Nanosecond()),
-			},
-			UserCode:      sub.UserCode,
-			Score:         int32(sub.Score),
-			Status:        sub.Status,
-			Output:        sub.Output,
-			Language:      sub.Language,
-			ExecutionTime: float32(sub.ExecutionTime),
-			Difficulty:    sub.Difficulty,
-			IsFirst:       sub.IsFirst,
-		}
+		pbSubmissions[i] = ToPbSubmission(sub)
 	}
 
 	return &pb.GetSubmissionsResponse{
@@ -886,12 +983,14 @@ func (r *Repository) GetSubmissionsByOptionalProblemID(ctx context.Context, req
 }
 
 func (r *Repository) GetProblemByIDSlug(ctx context.Context, req *pb.GetProblemByIdSlugRequest) (*pb.GetProblemByIdSlugResponse, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
 	var problem model.Problem
 	filter := bson.M{"deleted_at": nil}
 	if req.ProblemId != "" {
 		id, err := primitive.ObjectIDFromHex(req.ProblemId)
 		if err != nil {
-			return &pb.GetProblemByIdSlugResponse{Message: "Invalid problem ID"}, nil
+			return nil, customerrors.Validation("invalid problem id %q", req.ProblemId)
 		}
 		filter["_id"] = id
 	} else if req.Slug != nil {
@@ -899,10 +998,13 @@ func (r *Repository) GetProblemByIDSlug(ctx context.Context, req *pb.GetProblemB
 	}
 	err := r.problemsCollection.FindOne(ctx, filter).Decode(&problem)
 	if err == mongo.ErrNoDocuments {
-		return &pb.GetProblemByIdSlugResponse{Message: "Problem not found"}, nil
+		if req.ProblemId == "" && req.Slug != nil {
+			return nil, customerrors.NotFound("problem with slug %q", *req.Slug)
+		}
+		return nil, customerrors.NotFound("problem %s", req.ProblemId)
 	}
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	return &pb.GetProblemByIdSlugResponse{
 		Problemmetdata: ToProblemMetadataLite(problem),
@@ -911,6 +1013,8 @@ func (r *Repository) GetProblemByIDSlug(ctx context.Context, req *pb.GetProblemB
 }
 
 func (r *Repository) GetProblemByIDList(ctx context.Context, req *pb.GetProblemMetadataListRequest) (*pb.GetProblemMetadataListResponse, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	filter := bson.M{
 		"deleted_at": nil,
 		"visible":    true,
@@ -1062,22 +1166,10 @@ func ToPBTestCases(tcs []model.TestCase) []*pb.TestCase {
 //		}
 //		return time.Unix(pbTimestamp.Seconds, int64(pbTimestamp.Nanos))
 //	}
-func CalculateScore(difficulty string) int {
-	score := 2
-
-	switch difficulty {
-	// case "EASY":
-	// 	score = 2
-	case "MEDIUM":
-		score = 4
-	case "HARD":
-		score = 6
-	}
 
-	return score
-}
-
-func (r *Repository) ProblemsDoneStatistics(userID string) (model.ProblemsDoneStatistics, error) {
+func (r *Repository) ProblemsDoneStatistics(ctx context.Context, userID string) (model.ProblemsDoneStatistics, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	if userID == "" {
 		return model.ProblemsDoneStatistics{}, fmt.Errorf("userID cannot be empty")
 	}
@@ -1093,14 +1185,14 @@ func (r *Repository) ProblemsDoneStatistics(userID string) (model.ProblemsDoneSt
 	}
 
 	// get the total count of problems based on difficulty
-	problemsCursor, err := r.problemsCollection.Find(context.TODO(), bson.M{"deleted_at": nil})
+	problemsCursor, err := r.problemsCollection.Find(ctx, bson.M{"deleted_at": nil})
 	if err != nil {
 		fmt.Println("failed to fetch problems:", err)
 		return stats, err
 	}
-	defer problemsCursor.Close(context.TODO())
+	defer problemsCursor.Close(ctx)
 
-	for problemsCursor.Next(context.TODO()) {
+	for problemsCursor.Next(ctx) {
 		var problem model.Problem
 		if err := problemsCursor.Decode(&problem); err != nil {
 			fmt.Println("failed to decode problem:", err)
@@ -1118,14 +1210,14 @@ func (r *Repository) ProblemsDoneStatistics(userID string) (model.ProblemsDoneSt
 	}
 
 	// get the count of problems done by the user
-	doneCursor, err := r.submissionFirstSuccessCollection.Find(context.TODO(), bson.M{"userId": userID})
+	doneCursor, err := r.submissionFirstSuccessCollection.Find(ctx, bson.M{"userId": userID})
 	if err != nil {
 		fmt.Println("failed to fetch submissions:", err)
 		return stats, err
 	}
-	defer doneCursor.Close(context.TODO())
+	defer doneCursor.Close(ctx)
 
-	for doneCursor.Next(context.TODO()) {
+	for doneCursor.Next(ctx) {
 		var submission model.ProblemDone
 		if err := doneCursor.Decode(&submission); err != nil {
 			fmt.Println("failed to decode submission:", err)
@@ -1146,7 +1238,9 @@ func (r *Repository) ProblemsDoneStatistics(userID string) (model.ProblemsDoneSt
 	return stats, nil
 }
 
-func (r *Repository) GetMonthlyContributionHistory(userID string, month, year int) (model.MonthlyActivityHeatmapProps, error) {
+func (r *Repository) GetMonthlyContributionHistory(ctx context.Context, userID string, month, year int) (model.MonthlyActivityHeatmapProps, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	// validate user id
 	if userID == "" {
 		return model.MonthlyActivityHeatmapProps{}, fmt.Errorf("userID cannot be empty")
@@ -1208,15 +1302,15 @@ func (r *Repository) GetMonthlyContributionHistory(userID string, month, year in
 		},
 	}
 
-	cursor, err := r.submissionsCollection.Aggregate(context.TODO(), pipeline)
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline)
 	if err != nil {
 		fmt.Println("failed to aggregate submissions:", err)
 		return model.MonthlyActivityHeatmapProps{}, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var activityDays []model.ActivityDay
-	if err = cursor.All(context.TODO(), &activityDays); err != nil {
+	if err = cursor.All(ctx, &activityDays); err != nil {
 		fmt.Println("failed to decode activity days:", err)
 		return model.MonthlyActivityHeatmapProps{}, err
 	}
@@ -1269,27 +1363,9 @@ func ToPBSubmission(s model.Submission) *pb.Submission {
 	}
 }
 
-func (r *Repository) ForceChangeUserCountryInSubmission(ctx context.Context, req *pb.ForceChangeUserEntityInSubmissionRequest) {
-	newEntity := strings.ToUpper(req.Entity)
-
-	filter := bson.M{"userId": req.UserId}
-	update := bson.M{
-		"$set": bson.M{
-			"country": newEntity,
-		},
-	}
-
-	const maxRetries = 3
-	for i := 0; i < maxRetries; i++ {
-		_, err := r.submissionFirstSuccessCollection.UpdateMany(ctx, filter, update)
-		if err == nil {
-			return
-		}
-		time.Sleep(time.Millisecond * 50) // simple backoff
-	}
-}
-
 func (r *Repository) GetBulkProblemMetadata(ctx context.Context, req *pb.GetBulkProblemMetadataRequest) (*pb.GetBulkProblemMetadataResponse, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
 	if len(req.ProblemIds) == 0 {
 		return &pb.GetBulkProblemMetadataResponse{}, nil
 	}
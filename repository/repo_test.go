@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPushSubmissionDataConcurrentFirstSuccess(t *testing.T) {
+	repo := newTestRepository(t)
+	const submissions = 8
+
+	var wg sync.WaitGroup
+	errs := make([]error, submissions)
+	pushed := make([]*model.Submission, submissions)
+	for i := range submissions {
+		pushed[i] = &model.Submission{
+			UserID:      "user-1",
+			ProblemID:   "problem-1",
+			Title:       "Two Sum",
+			Status:      "SUCCESS",
+			Language:    "go",
+			Difficulty:  "E",
+			SubmittedAt: time.Now(),
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.PushSubmissionData(context.Background(), pushed[i], "SUCCESS", nil)
+		}()
+	}
+	wg.Wait()
+
+	firsts := 0
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("PushSubmissionData %d: %v", i, err)
+		}
+		if pushed[i].IsFirst {
+			firsts++
+		} else if pushed[i].Score != 0 {
+			t.Errorf("repeat submission %d scored %d, want 0", i, pushed[i].Score)
+		}
+	}
+	if firsts != 1 {
+		t.Errorf("%d submissions marked first, want exactly 1", firsts)
+	}
+
+	filter := bson.M{"userId": "user-1", "problemId": "problem-1"}
+	if n := countDocuments(t, repo.submissionFirstSuccessCollection, filter); n != 1 {
+		t.Errorf("%d first success entries, want 1", n)
+	}
+	if n := countDocuments(t, repo.submissionsCollection, filter); n != submissions {
+		t.Errorf("%d submissions stored, want all %d", n, submissions)
+	}
+	if n := countDocuments(t, repo.leaderboardUpdatesCollection, bson.M{"userId": "user-1"}); n != 1 {
+		t.Errorf("%d leaderboard updates, want 1", n)
+	}
+}
+
+func TestEnsureIndexesMakesFirstSuccessUnique(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	// a database indexed before the constraint has the same index without it
+	if _, err := repo.submissionFirstSuccessCollection.Indexes().DropOne(ctx, "userId_1_problemId_1"); err != nil {
+		t.Fatalf("drop index: %v", err)
+	}
+	if _, err := repo.submissionFirstSuccessCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "problemId", Value: 1}},
+	}); err != nil {
+		t.Fatalf("create non-unique index: %v", err)
+	}
+	if _, err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+
+	if _, err := repo.submissionFirstSuccessCollection.InsertOne(ctx, bson.M{"userId": "user-1", "problemId": "problem-1"}); err != nil {
+		t.Fatalf("insert first entry: %v", err)
+	}
+	_, err := repo.submissionFirstSuccessCollection.InsertOne(ctx, bson.M{"userId": "user-1", "problemId": "problem-1"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("second first success entry for the same user and problem: err = %v, want a duplicate key error", err)
+	}
+}
+
+func TestPushSubmissionDataNil(t *testing.T) {
+	var repo *Repository
+	if err := repo.PushSubmissionData(context.Background(), &model.Submission{}, "SUCCESS", nil); err == nil {
+		t.Error("nil repository accepted a submission")
+	}
+	repo = &Repository{}
+	if err := repo.PushSubmissionData(context.Background(), nil, "SUCCESS", nil); err == nil {
+		t.Error("nil submission accepted")
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PurgeDeletedProblems removes up to limit problems soft deleted before deletedBefore and returns how many were
+// removed. With archive set each problem is first copied to the archive collection, in the same transaction, so a
+// purge never loses a problem that was not archived. Submissions of purged problems are kept.
+func (r *Repository) PurgeDeletedProblems(ctx context.Context, deletedBefore time.Time, archive bool, limit int64) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lt": deletedBefore}}
+	cursor, err := r.problemsCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"deleted_at": 1}).SetLimit(limit))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch deleted problems: %w", err)
+	}
+	// raw documents, so the archive keeps fields the model does not know about
+	var problems []bson.M
+	if err := cursor.All(ctx, &problems); err != nil {
+		return 0, fmt.Errorf("failed to decode deleted problems: %w", err)
+	}
+	if len(problems) == 0 {
+		return 0, nil
+	}
+
+	ids := make(bson.A, len(problems))
+	for i, problem := range problems {
+		ids[i] = problem["_id"]
+	}
+
+	var purged int64
+	err = r.WithTransaction(ctx, func(ctx context.Context) error {
+		if archive {
+			// replacing by ID keeps a rerun after a failed purge on a standalone server from tripping over copies
+			// that were archived but not deleted
+			writes := make([]mongo.WriteModel, len(problems))
+			for i, problem := range problems {
+				writes[i] = mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": problem["_id"]}).SetReplacement(problem).SetUpsert(true)
+			}
+			if _, err := r.problemsArchiveCollection.BulkWrite(ctx, writes); err != nil {
+				return fmt.Errorf("failed to archive deleted problems: %w", err)
+			}
+		}
+
+		// the deleted_at condition skips a problem restored since it was read
+		result, err := r.problemsCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$lt": deletedBefore}})
+		if err != nil {
+			return fmt.Errorf("failed to purge deleted problems: %w", err)
+		}
+		purged = result.DeletedCount
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
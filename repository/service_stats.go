@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetServiceStatsMongo fills the MongoDB totals of the admin overview: problems by difficulty and validation state,
+// submissions per UTC day since since (days without submissions are left out), challenges with submissions since
+// since, and the number of users on the leaderboard
+func (r *Repository) GetServiceStatsMongo(ctx context.Context, since time.Time) (*model.ServiceStats, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	stats := &model.ServiceStats{Problems: model.ProblemCounts{ByDifficulty: map[string]int64{}}}
+
+	cursor, err := r.problemsCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": nil}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"difficulty": "$difficulty", "validated": "$validated"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate problem counts: %w", dbError(err))
+	}
+	var problemGroups []struct {
+		ID struct {
+			Difficulty string `bson:"difficulty"`
+			Validated  bool   `bson:"validated"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &problemGroups); err != nil {
+		return nil, fmt.Errorf("failed to decode problem counts: %w", err)
+	}
+	for _, group := range problemGroups {
+		stats.Problems.Total += group.Count
+		stats.Problems.ByDifficulty[group.ID.Difficulty] += group.Count
+		if group.ID.Validated {
+			stats.Problems.Validated += group.Count
+		} else {
+			stats.Problems.Unvalidated += group.Count
+		}
+	}
+
+	cursor, err = r.submissionsCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"submittedAt": bson.M{"$gte": since}}}},
+		{{Key: "$facet", Value: bson.M{
+			"days": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$submittedAt"}},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"challenges": bson.A{
+				bson.M{"$match": bson.M{"challengeId": bson.M{"$nin": bson.A{nil, ""}}}},
+				bson.M{"$group": bson.M{"_id": "$challengeId"}},
+				bson.M{"$count": "count"},
+			},
+		}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate submission counts: %w", dbError(err))
+	}
+	var submissions []struct {
+		Days []struct {
+			Date  string `bson:"_id"`
+			Count int64  `bson:"count"`
+		} `bson:"days"`
+		Challenges []struct {
+			Count int64 `bson:"count"`
+		} `bson:"challenges"`
+	}
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode submission counts: %w", err)
+	}
+	stats.SubmissionsPerDay = []model.DailyCount{}
+	if len(submissions) > 0 {
+		for _, day := range submissions[0].Days {
+			stats.SubmissionsPerDay = append(stats.SubmissionsPerDay, model.DailyCount{Date: day.Date, Count: day.Count})
+		}
+		if len(submissions[0].Challenges) > 0 {
+			stats.ActiveChallenges = submissions[0].Challenges[0].Count
+		}
+	}
+
+	cursor, err = r.submissionFirstSuccessCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$userId"}}},
+		{{Key: "$count", Value: "count"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count leaderboard users: %w", dbError(err))
+	}
+	var users []struct {
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode leaderboard user count: %w", err)
+	}
+	if len(users) > 0 {
+		stats.LeaderboardSize = users[0].Count
+	}
+	return stats, nil
+}
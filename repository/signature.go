@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SetFunctionSignature stores the function signature of a problem, or removes it when signature is nil
+func (r *Repository) SetFunctionSignature(ctx context.Context, problemID string, signature *model.FunctionSignature) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"signature": signature, "updated_at": time.Now()}}
+	if signature == nil {
+		update = bson.M{"$unset": bson.M{"signature": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	}
+	result, err := r.problemsCollection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set function signature: %w", dbError(err))
+	}
+	if result.MatchedCount == 0 {
+		return customerrors.NotFound("problem %s", problemID)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateSolution stores a new solution and returns it with its ID. A submission can be posted once; posting it again
+// is a customerrors.Conflict.
+func (r *Repository) CreateSolution(ctx context.Context, solution model.Solution) (*model.Solution, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	solution.ID = primitive.NewObjectID()
+	solution.Votes = 0
+	if _, err := r.solutionsCollection.InsertOne(ctx, solution); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, customerrors.Conflict("submission %s is already posted as a solution", solution.SubmissionID)
+		}
+		return nil, fmt.Errorf("failed to create solution: %w", dbError(err))
+	}
+	return &solution, nil
+}
+
+// ListSolutions returns a page of a problem's solutions, most voted or newest first, and their total
+func (r *Repository) ListSolutions(ctx context.Context, problemID, sort string, skip, limit int64) ([]model.Solution, int64, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{"problemId": problemID}
+	total, err := r.solutionsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count solutions: %w", dbError(err))
+	}
+
+	order := bson.D{{Key: "votes", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	if sort == model.SolutionSortRecent {
+		order = bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	}
+	cursor, err := r.solutionsCollection.Find(ctx, filter, options.Find().SetSort(order).SetSkip(skip).SetLimit(limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch solutions: %w", dbError(err))
+	}
+	solutions := []model.Solution{}
+	if err := cursor.All(ctx, &solutions); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode solutions: %w", dbError(err))
+	}
+	return solutions, total, nil
+}
+
+// VoteSolution sets userID's vote on a solution to value (1, -1, or 0 to withdraw it) and adjusts the solution's
+// votes by the difference, in one transaction. It returns the updated solution, or a customerrors.NotFound error.
+func (r *Repository) VoteSolution(ctx context.Context, solutionID, userID string, value int) (*model.Solution, error) {
+	id, err := primitive.ObjectIDFromHex(solutionID)
+	if err != nil {
+		return nil, customerrors.Validation("invalid solution id %q", solutionID)
+	}
+	voteID := model.SolutionVoteID(solutionID, userID)
+
+	var updated model.Solution
+	err = r.WithTransaction(ctx, func(ctx context.Context) error {
+		ctx, cancel := r.writeContext(ctx)
+		defer cancel()
+
+		var previous model.SolutionVote
+		err := r.solutionVotesCollection.FindOne(ctx, bson.M{"_id": voteID}).Decode(&previous)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return fmt.Errorf("failed to fetch vote: %w", dbError(err))
+		}
+
+		// the solution is updated first so a vote on a missing solution is never stored
+		err = r.solutionsCollection.FindOneAndUpdate(ctx,
+			bson.M{"_id": id},
+			bson.M{"$inc": bson.M{"votes": value - previous.Value}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err == mongo.ErrNoDocuments {
+			return customerrors.NotFound("solution %s", solutionID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update votes of solution %s: %w", solutionID, dbError(err))
+		}
+		if value == 0 {
+			_, err = r.solutionVotesCollection.DeleteOne(ctx, bson.M{"_id": voteID})
+		} else {
+			_, err = r.solutionVotesCollection.UpdateOne(ctx,
+				bson.M{"_id": voteID},
+				bson.M{
+					"$set":         bson.M{"value": value},
+					"$setOnInsert": bson.M{"solutionId": solutionID, "userId": userID, "createdAt": time.Now()},
+				},
+				options.Update().SetUpsert(true))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store vote: %w", dbError(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// GetSolution returns a customerrors.NotFound error when the ID is unknown
+func (r *Repository) GetSolution(ctx context.Context, solutionID string) (*model.Solution, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(solutionID)
+	if err != nil {
+		return nil, customerrors.Validation("invalid solution id %q", solutionID)
+	}
+	var solution model.Solution
+	if err := r.solutionsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&solution); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("solution %s", solutionID)
+		}
+		return nil, fmt.Errorf("failed to fetch solution %s: %w", solutionID, dbError(err))
+	}
+	return &solution, nil
+}
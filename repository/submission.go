@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordFirstSolver claims the first-solver slot of the problem (and of the challenge, if any) for this submission.
+// Slots already taken are left untouched; failures are logged since they must not fail the submission.
+func (r *Repository) recordFirstSolver(ctx context.Context, submission *model.Submission, submissionID string) {
+	challengeIDs := []string{""}
+	if submission.ChallengeID != nil && *submission.ChallengeID != "" {
+		challengeIDs = append(challengeIDs, *submission.ChallengeID)
+	}
+
+	for _, challengeID := range challengeIDs {
+		solver := model.FirstSolver{
+			ID:           model.FirstSolverID(submission.ProblemID, challengeID),
+			ProblemID:    submission.ProblemID,
+			ChallengeID:  challengeID,
+			UserID:       submission.UserID,
+			SubmissionID: submissionID,
+			Language:     submission.Language,
+			SolvedAt:     submission.SubmittedAt,
+		}
+		_, err := r.firstSolversCollection.UpdateOne(ctx,
+			bson.M{"_id": solver.ID},
+			bson.M{"$setOnInsert": solver},
+			options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			r.logger.LogContext(ctx, zapcore.ErrorLevel, "Failed to record first solver", map[string]any{
+				"method":      "recordFirstSolver",
+				"problemId":   submission.ProblemID,
+				"challengeId": challengeID,
+				"userId":      submission.UserID,
+			}, "REPOSITORY", err)
+		}
+	}
+}
+
+// GetFirstSolvers returns the recorded first solvers of the given problems, globally or for one challenge.
+// Problems solved before tracking started are resolved from the earliest first success and recorded on the way.
+func (r *Repository) GetFirstSolvers(ctx context.Context, problemIDs []string, challengeID string) ([]model.FirstSolver, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	ids := make([]string, len(problemIDs))
+	for i, problemID := range problemIDs {
+		ids[i] = model.FirstSolverID(problemID, challengeID)
+	}
+
+	cursor, err := r.firstSolversCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find first solvers: %w", err)
+	}
+	var solvers []model.FirstSolver
+	if err := cursor.All(ctx, &solvers); err != nil {
+		return nil, fmt.Errorf("failed to decode first solvers: %w", err)
+	}
+	if challengeID != "" {
+		return solvers, nil
+	}
+
+	found := make(map[string]bool, len(solvers))
+	for _, solver := range solvers {
+		found[solver.ProblemID] = true
+	}
+	var missing []string
+	for _, problemID := range problemIDs {
+		if !found[problemID] {
+			missing = append(missing, problemID)
+		}
+	}
+	if len(missing) == 0 {
+		return solvers, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"problemId": bson.M{"$in": missing}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "submittedAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$problemId",
+			"userId":       bson.M{"$first": "$userId"},
+			"submissionId": bson.M{"$first": "$submissionId"},
+			"language":     bson.M{"$first": "$language"},
+			"solvedAt":     bson.M{"$first": "$submittedAt"},
+		}}},
+	}
+	backfill, err := r.submissionFirstSuccessCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate first solvers: %w", err)
+	}
+	defer backfill.Close(ctx)
+	for backfill.Next(ctx) {
+		var solver model.FirstSolver
+		if err := backfill.Decode(&solver); err != nil {
+			return nil, fmt.Errorf("failed to decode first solver: %w", err)
+		}
+		solver.ProblemID = solver.ID
+		_, err := r.firstSolversCollection.UpdateOne(ctx,
+			bson.M{"_id": solver.ID},
+			bson.M{"$setOnInsert": solver},
+			options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("failed to record first solver: %w", err)
+		}
+		solvers = append(solvers, solver)
+	}
+	return solvers, backfill.Err()
+}
+
+// submissionsListSort orders submissions newest first, with _id breaking ties so pages are stable
+var submissionsListSort = bson.D{{Key: "submittedAt", Value: -1}, {Key: "_id", Value: -1}}
+
+func ToPbSubmission(sub model.Submission) *pb.Submission {
+	var challengeID string
+	if sub.ChallengeID != nil {
+		challengeID = *sub.ChallengeID
+	}
+	return &pb.Submission{
+		Id:          sub.ID.Hex(),
+		ProblemId:   sub.ProblemID,
+		Title:       sub.Title,
+		UserId:      sub.UserID,
+		ChallengeId: challengeID,
+		SubmittedAt: &pb.Timestamp{
+			Seconds: sub.SubmittedAt.Unix(),
+			Nanos:   int32(sub.SubmittedAt.Nanosecond()),
+		},
+		UserCode:      sub.UserCode,
+		Score:         int32(sub.Score),
+		Status:        sub.Status,
+		Output:        sub.Output,
+		Language:      sub.Language,
+		ExecutionTime: float32(sub.ExecutionTime),
+		Difficulty:    sub.Difficulty,
+		IsFirst:       sub.IsFirst,
+	}
+}
+
+// ErrInvalidCursor is returned when a pagination cursor was not produced by ListSubmissionsCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// encodeSubmissionCursor packs the sort position of a submission into an opaque token
+func encodeSubmissionCursor(sub model.Submission) string {
+	raw := strconv.FormatInt(sub.SubmittedAt.UnixNano(), 10) + ":" + sub.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSubmissionCursor(token string) (time.Time, primitive.ObjectID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, ErrInvalidCursor
+	}
+	nanos, hexID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, primitive.NilObjectID, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, ErrInvalidCursor
+	}
+	return time.Unix(0, unixNano), id, nil
+}
+
+func submissionFilterQuery(f model.SubmissionFilter) bson.M {
+	filter := bson.M{}
+	if f.ProblemID != "" {
+		filter["problemId"] = f.ProblemID
+	}
+	if f.UserID != "" {
+		filter["userId"] = f.UserID
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.Language != "" {
+		filter["language"] = f.Language
+	}
+	if f.From != nil || f.To != nil {
+		submittedAt := bson.M{}
+		if f.From != nil {
+			submittedAt["$gte"] = *f.From
+		}
+		if f.To != nil {
+			submittedAt["$lt"] = *f.To
+		}
+		filter["submittedAt"] = submittedAt
+	}
+	if f.IsFirst != nil {
+		filter["isFirst"] = *f.IsFirst
+	}
+	return filter
+}
+
+// ListSubmissionsCursor returns up to limit submissions matching f that sort after the cursor position,
+// plus the cursor of the next page (empty on the last page)
+func (r *Repository) ListSubmissionsCursor(ctx context.Context, f model.SubmissionFilter, cursorToken string, limit int64) ([]*pb.Submission, string, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	filter := submissionFilterQuery(f)
+	if cursorToken != "" {
+		submittedAt, id, err := decodeSubmissionCursor(cursorToken)
+		if err != nil {
+			return nil, "", err
+		}
+		filter = bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{"submittedAt": bson.M{"$lt": submittedAt}},
+			bson.M{"submittedAt": submittedAt, "_id": bson.M{"$lt": id}},
+		}}}}
+	}
+
+	// one extra document tells whether another page exists
+	cursor, err := r.submissionsCollection.Find(ctx, filter, options.Find().SetSort(submissionsListSort).SetLimit(limit+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find submissions: %w", err)
+	}
+	var submissions []model.Submission
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, "", fmt.Errorf("failed to decode submissions: %w", err)
+	}
+
+	nextCursor := ""
+	if int64(len(submissions)) > limit {
+		submissions = submissions[:limit]
+		nextCursor = encodeSubmissionCursor(submissions[len(submissions)-1])
+	}
+	pbSubmissions := make([]*pb.Submission, len(submissions))
+	for i, sub := range submissions {
+		pbSubmissions[i] = ToPbSubmission(sub)
+	}
+	return pbSubmissions, nextCursor, nil
+}
+
+// GetBestSubmissions returns the user's fastest accepted submission for every solved problem, or for every
+// problem and language pair when byLanguage is set. Submissions without a recorded execution time rank last.
+func (r *Repository) GetBestSubmissions(ctx context.Context, userID string, byLanguage bool) ([]*pb.Submission, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	groupID := bson.M{"problemId": "$problemId"}
+	if byLanguage {
+		groupID["language"] = "$language"
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID, "status": "SUCCESS"}}},
+		{{Key: "$addFields", Value: bson.M{"hasExecutionTime": bson.M{"$gt": bson.A{"$executionTime", 0}}}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "hasExecutionTime", Value: -1},
+			{Key: "executionTime", Value: 1},
+			{Key: "submittedAt", Value: 1},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": groupID, "best": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$best"}}},
+		{{Key: "$sort", Value: submissionsListSort}},
+	}
+
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate best submissions: %w", err)
+	}
+	var submissions []model.Submission
+	if err := cursor.All(ctx, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode best submissions: %w", err)
+	}
+
+	pbSubmissions := make([]*pb.Submission, len(submissions))
+	for i, sub := range submissions {
+		pbSubmissions[i] = ToPbSubmission(sub)
+	}
+	return pbSubmissions, nil
+}
+
+// GetSubmissionByID returns mongo.ErrNoDocuments when the ID is malformed or unknown
+func (r *Repository) GetSubmissionByID(ctx context.Context, submissionID string) (*model.Submission, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(submissionID)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	var submission model.Submission
+	if err := r.submissionsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&submission); err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (r *Repository) SaveSharedSubmission(ctx context.Context, shared model.SharedSubmission) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if _, err := r.sharedSubmissionsCollection.InsertOne(ctx, shared); err != nil {
+		return fmt.Errorf("failed to save shared submission: %w", err)
+	}
+	return nil
+}
+
+// GetSharedSubmission resolves an unexpired share token; the TTL index purges lazily, so expiry is checked here too
+func (r *Repository) GetSharedSubmission(ctx context.Context, token string) (*model.SharedSubmission, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	var shared model.SharedSubmission
+	err := r.sharedSubmissionsCollection.FindOne(ctx, bson.M{"_id": token, "expiresAt": bson.M{"$gt": time.Now()}}).Decode(&shared)
+	if err != nil {
+		return nil, err
+	}
+	return &shared, nil
+}
+
+// GetProblemStatuses returns userID's status on each of problemIDs, model.ProblemStatusUntouched for the problems
+// they never submitted to, from one aggregation over their submissions
+func (r *Repository) GetProblemStatuses(ctx context.Context, userID string, problemIDs []string) (map[string]string, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	statuses := make(map[string]string, len(problemIDs))
+	for _, problemID := range problemIDs {
+		statuses[problemID] = model.ProblemStatusUntouched
+	}
+	if len(problemIDs) == 0 {
+		return statuses, nil
+	}
+
+	accepted := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$status", "SUCCESS"}},
+		bson.M{"$ne": bson.A{"$invalidated", true}},
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID, "problemId": bson.M{"$in": problemIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$problemId",
+			"solved": bson.M{"$max": bson.M{"$cond": bson.A{accepted, 1, 0}}},
+		}}},
+	}
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate problem statuses: %w", dbError(err))
+	}
+	var results []struct {
+		ProblemID string `bson:"_id"`
+		Solved    int    `bson:"solved"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode problem statuses: %w", dbError(err))
+	}
+	for _, result := range results {
+		if result.Solved == 1 {
+			statuses[result.ProblemID] = model.ProblemStatusSolved
+		} else {
+			statuses[result.ProblemID] = model.ProblemStatusAttempted
+		}
+	}
+	return statuses, nil
+}
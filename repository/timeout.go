@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Timeouts bound repository operations whose caller did not set an earlier deadline, so a slow MongoDB node cannot
+// hold an RPC forever. A zero timeout leaves that kind of operation unbounded. Scheduled jobs that scan whole
+// collections, such as leaderboard syncs, are only bound by the ctx they are given.
+type Timeouts struct {
+	Read      time.Duration // lookups of single documents
+	Write     time.Duration // inserts, updates and deletes, including their transactions
+	Aggregate time.Duration // listings, aggregations and statistics
+}
+
+func (r *Repository) readContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withDefaultTimeout(ctx, r.timeouts.Read)
+}
+
+func (r *Repository) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withDefaultTimeout(ctx, r.timeouts.Write)
+}
+
+func (r *Repository) aggregateContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withDefaultTimeout(ctx, r.timeouts.Aggregate)
+}
+
+// withDefaultTimeout shortens ctx to timeout unless its own deadline is already closer. The values of ctx, including
+// a transaction's session, are kept.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"xcode/metrics"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap/zapcore"
+)
+
+// illegalOperationCode is what a standalone server answers to a transaction, it only supports them on replica sets
+const illegalOperationCode = 20
+
+// transactionFallbacks counts writes that ran without the transaction they asked for; it should stay at 0 outside
+// of development
+var transactionFallbacks = metrics.NewCounter("mongo_transaction_fallbacks_total")
+
+// WithTransaction runs fn in a MongoDB transaction, committing when it returns nil. Repository calls made with the
+// ctx handed to fn join the transaction. fn may run more than once when the transaction hits a transient error, so
+// it must not keep state between runs. When ctx is already in a transaction fn simply joins it, and on a
+// standalone server, which has no transactions, fn runs without one: its writes are not atomic, so each time this
+// happens a warning is logged and mongo_transaction_fallbacks_total goes up. Run MongoDB as a replica set, even a
+// single node one as in docker-compose.yml, to get transactions.
+func (r *Repository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := r.mongoclientInstance.StartSession()
+	if err != nil {
+		return dbError(err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	if transactionsUnsupported(err) {
+		transactionFallbacks.Add(1)
+		r.logger.LogContext(ctx, zapcore.WarnLevel, "MongoDB has no transactions, writing without one", map[string]any{
+			"method":    "WithTransaction",
+			"errorType": "TRANSACTIONS_UNSUPPORTED",
+		}, "REPOSITORY", err)
+		return fn(ctx)
+	}
+	return err
+}
+
+func transactionsUnsupported(err error) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && commandErr.Code == illegalOperationCode
+}
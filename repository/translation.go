@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UpsertProblemTranslation stores the translation of a problem into locale, replacing any earlier one, and bumps the
+// problem's updated_at like any other change to its statement. created reports whether the locale is new.
+func (r *Repository) UpsertProblemTranslation(ctx context.Context, problemID, locale string, translation model.ProblemTranslation) (bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return false, err
+	}
+
+	field := "translations." + locale
+	var before model.Problem
+	err = r.problemsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{field: translation, "updated_at": translation.UpdatedAt}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before).SetProjection(bson.M{field: 1}),
+	).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return false, customerrors.NotFound("problem %s", problemID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to store translation of problem %s: %w", problemID, dbError(err))
+	}
+	_, existed := before.Translations[locale]
+	return !existed, nil
+}
+
+// GetProblemTranslations returns every translation of a problem by locale, empty when it has none
+func (r *Repository) GetProblemTranslations(ctx context.Context, problemID string) (map[string]model.ProblemTranslation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := problemObjectID(problemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var problem model.Problem
+	err = r.problemsCollection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}, options.FindOne().SetProjection(bson.M{"translations": 1})).Decode(&problem)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.NotFound("problem %s", problemID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch translations of problem %s: %w", problemID, dbError(err))
+	}
+	if problem.Translations == nil {
+		return map[string]model.ProblemTranslation{}, nil
+	}
+	return problem.Translations, nil
+}
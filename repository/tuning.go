@@ -0,0 +1,39 @@
+package repository
+
+// ScoreTable is the leaderboard score of a first success by problem difficulty; unknown difficulties score as Easy.
+// A change applies to new first successes; RecalculateFirstSuccessScores brings the stored ones in line.
+type ScoreTable struct {
+	Easy   int
+	Medium int
+	Hard   int
+}
+
+// DefaultScoreTable is the score table used when Options leaves it zero
+var DefaultScoreTable = ScoreTable{Easy: 2, Medium: 4, Hard: 6}
+
+// Score returns the score of a first success on a problem of the given difficulty
+func (t ScoreTable) Score(difficulty string) int {
+	switch difficulty {
+	case "MEDIUM":
+		return t.Medium
+	case "HARD":
+		return t.Hard
+	}
+	return t.Easy
+}
+
+// TestCaseLimits caps how many run and submit test cases a problem can have
+type TestCaseLimits struct {
+	Run    int
+	Submit int
+}
+
+// DefaultTestCaseLimits are the limits used when Options leaves them zero
+var DefaultTestCaseLimits = TestCaseLimits{Run: 3, Submit: 100}
+
+// Options tunes a Repository; zero Scores and TestCaseLimits fall back to their defaults
+type Options struct {
+	Timeouts       Timeouts
+	Scores         ScoreTable
+	TestCaseLimits TestCaseLimits
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetUserLanguageStats counts a user's submissions, accepted submissions and distinct solved problems per language
+func (r *Repository) GetUserLanguageStats(ctx context.Context, userID string) ([]model.LanguageStats, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	accepted := bson.M{"$eq": bson.A{"$status", "SUCCESS"}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID, "invalidated": bson.M{"$ne": true}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$language",
+			"submissions": bson.M{"$sum": 1},
+			"accepted":    bson.M{"$sum": bson.M{"$cond": bson.A{accepted, 1, 0}}},
+			"solved":      bson.M{"$addToSet": bson.M{"$cond": bson.A{accepted, "$problemId", "$$REMOVE"}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"submissions":    1,
+			"accepted":       1,
+			"problemsSolved": bson.M{"$size": "$solved"},
+			"acceptanceRate": bson.M{"$round": bson.A{
+				bson.M{"$multiply": bson.A{bson.M{"$divide": bson.A{"$accepted", "$submissions"}}, 100}}, 2,
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "problemsSolved", Value: -1}, {Key: "submissions", Value: -1}}}},
+	}
+
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate language stats: %w", err)
+	}
+	stats := []model.LanguageStats{}
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode language stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetDailySubmissionCounts returns the number of submissions per UTC day (keyed YYYY-MM-DD) in [from, to)
+func (r *Repository) GetDailySubmissionCounts(ctx context.Context, userID string, from, to time.Time) (map[string]int, error) {
+	ctx, cancel := r.aggregateContext(ctx)
+	defer cancel()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID, "submittedAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$submittedAt"}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.submissionsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate daily submissions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[string]int)
+	for cursor.Next(ctx) {
+		var day struct {
+			Date  string `bson:"_id"`
+			Count int    `bson:"count"`
+		}
+		if err := cursor.Decode(&day); err != nil {
+			return nil, fmt.Errorf("failed to decode daily submissions: %w", err)
+		}
+		counts[day.Date] = day.Count
+	}
+	return counts, cursor.Err()
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// finishedValidationJobTTL is how long finished validation jobs can be polled before MongoDB drops them
+const finishedValidationJobTTL = 7 * 24 * time.Hour
+
+// activeValidationJobStatuses are the statuses of a job that has not finished
+var activeValidationJobStatuses = bson.M{"$in": bson.A{model.ValidationJobQueued, model.ValidationJobRunning}}
+
+// CreateValidationJob queues job unless the problem already has an active one, which is returned instead with
+// created false. Active jobs not updated since staleBefore were abandoned by a replica that stopped; they are marked
+// failed and no longer count.
+func (r *Repository) CreateValidationJob(ctx context.Context, job model.ValidationJob, staleBefore time.Time) (*model.ValidationJob, bool, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	now := time.Now()
+	_, err := r.validationJobsCollection.UpdateMany(ctx,
+		bson.M{"problemId": job.ProblemID, "status": activeValidationJobStatuses, "updatedAt": bson.M{"$lt": staleBefore}},
+		bson.M{"$set": bson.M{
+			"status":     model.ValidationJobFailed,
+			"message":    "Validation was abandoned before it finished",
+			"errorType":  "ABANDONED",
+			"updatedAt":  now,
+			"finishedAt": now,
+		}})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fail abandoned validation jobs of problem %s: %w", job.ProblemID, dbError(err))
+	}
+
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+	job.Status = model.ValidationJobQueued
+	job.CreatedAt, job.UpdatedAt, job.FinishedAt = now, now, nil
+	if job.Pending == nil {
+		job.Pending = []string{}
+	}
+	if job.Languages == nil {
+		job.Languages = []model.LanguageValidationResult{}
+	}
+
+	var active model.ValidationJob
+	err = r.validationJobsCollection.FindOneAndUpdate(ctx,
+		bson.M{"problemId": job.ProblemID, "status": activeValidationJobStatuses, "updatedAt": bson.M{"$gte": staleBefore}},
+		bson.M{"$setOnInsert": job},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&active)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create validation job for problem %s: %w", job.ProblemID, dbError(err))
+	}
+	return &active, active.ID == job.ID, nil
+}
+
+// GetValidationJob returns a customerrors.NotFound error when the ID is unknown or the job has expired
+func (r *Repository) GetValidationJob(ctx context.Context, jobID string) (*model.ValidationJob, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, customerrors.Validation("invalid validation job id %q", jobID)
+	}
+
+	var job model.ValidationJob
+	if err := r.validationJobsCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, customerrors.NotFound("validation job %s", jobID)
+		}
+		return nil, fmt.Errorf("failed to fetch validation job %s: %w", jobID, dbError(err))
+	}
+	return &job, nil
+}
+
+// StartValidationJob marks the job running with every language pending
+func (r *Repository) StartValidationJob(ctx context.Context, id primitive.ObjectID, languages []string) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.validationJobsCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": model.ValidationJobRunning, "pending": languages, "updatedAt": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("failed to start validation job %s: %w", id.Hex(), dbError(err))
+	}
+	return nil
+}
+
+// RecordValidationJobResult moves a finished language from the job's pending list to its results
+func (r *Repository) RecordValidationJobResult(ctx context.Context, id primitive.ObjectID, result model.LanguageValidationResult) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.validationJobsCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$pull": bson.M{"pending": result.Language},
+			"$push": bson.M{"languages": result},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		})
+	if err != nil {
+		return fmt.Errorf("failed to record %s result of validation job %s: %w", result.Language, id.Hex(), dbError(err))
+	}
+	return nil
+}
+
+// FinishValidationJob stores the job's final report, replacing the results recorded so far with the report's
+// ordered ones
+func (r *Repository) FinishValidationJob(ctx context.Context, id primitive.ObjectID, report model.ValidateProblemResponse) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	status := model.ValidationJobFailed
+	if report.Validated {
+		status = model.ValidationJobSucceeded
+	}
+	languages := report.Languages
+	if languages == nil {
+		languages = []model.LanguageValidationResult{}
+	}
+
+	now := time.Now()
+	_, err := r.validationJobsCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"status":     status,
+			"validated":  report.Validated,
+			"message":    report.Message,
+			"errorType":  report.ErrorType,
+			"pending":    []string{},
+			"languages":  languages,
+			"updatedAt":  now,
+			"finishedAt": now,
+		}})
+	if err != nil {
+		return fmt.Errorf("failed to finish validation job %s: %w", id.Hex(), dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookDeliveryTTL is how long the delivery log is kept before MongoDB drops it
+const webhookDeliveryTTL = 30 * 24 * time.Hour
+
+// CreateWebhook stores a new webhook and returns it with its ID
+func (r *Repository) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	webhook.ID = primitive.NewObjectID()
+	if _, err := r.webhooksCollection.InsertOne(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", dbError(err))
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks returns the webhooks that were not deleted, newest first
+func (r *Repository) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	return r.findWebhooks(ctx, bson.M{"deletedAt": nil})
+}
+
+// GetWebhooksForEvent returns the webhooks subscribed to eventType
+func (r *Repository) GetWebhooksForEvent(ctx context.Context, eventType string) ([]model.Webhook, error) {
+	return r.findWebhooks(ctx, bson.M{"eventTypes": eventType, "deletedAt": nil})
+}
+
+func (r *Repository) findWebhooks(ctx context.Context, filter bson.M) ([]model.Webhook, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	cursor, err := r.webhooksCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhooks: %w", dbError(err))
+	}
+	webhooks := []model.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", dbError(err))
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook marks a webhook deleted and fails its pending deliveries. Deleting it twice is a
+// customerrors.NotFound, as deleted webhooks are not listed.
+func (r *Repository) DeleteWebhook(ctx context.Context, webhookID string, at time.Time) (*model.Webhook, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	id, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, customerrors.Validation("invalid webhook id %q", webhookID)
+	}
+
+	var webhook model.Webhook
+	err = r.webhooksCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deletedAt": nil},
+		bson.M{"$set": bson.M{"deletedAt": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return nil, customerrors.NotFound("webhook %s", webhookID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete webhook %s: %w", webhookID, dbError(err))
+	}
+
+	_, err = r.webhookDeliveriesCollection.UpdateMany(ctx,
+		bson.M{"webhookId": webhookID, "status": model.WebhookDeliveryPending},
+		bson.M{"$set": bson.M{"status": model.WebhookDeliveryFailed, "lastError": "webhook deleted"}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to drop pending deliveries of webhook %s: %w", webhookID, dbError(err))
+	}
+	return &webhook, nil
+}
+
+// SaveWebhookDeliveries queues deliveries for the dispatcher
+func (r *Repository) SaveWebhookDeliveries(ctx context.Context, deliveries ...model.WebhookDelivery) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	if len(deliveries) == 0 {
+		return nil
+	}
+	docs := make([]any, len(deliveries))
+	for i, delivery := range deliveries {
+		if delivery.ID.IsZero() {
+			delivery.ID = primitive.NewObjectID()
+		}
+		docs[i] = delivery
+	}
+	if _, err := r.webhookDeliveriesCollection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to save webhook deliveries: %w", dbError(err))
+	}
+	return nil
+}
+
+// GetDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due at now, most overdue first
+func (r *Repository) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) ([]model.WebhookDelivery, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := r.webhookDeliveriesCollection.Find(ctx, bson.M{
+		"status":        model.WebhookDeliveryPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due webhook deliveries: %w", dbError(err))
+	}
+	deliveries := []model.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode due webhook deliveries: %w", dbError(err))
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery records the outcome of an attempt: the status, attempts, next attempt, last response and
+// delivery time of delivery are stored as they are
+func (r *Repository) UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+	_, err := r.webhookDeliveriesCollection.UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{"$set": bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"nextAttemptAt":  delivery.NextAttemptAt,
+		"lastStatusCode": delivery.LastStatusCode,
+		"lastError":      delivery.LastError,
+		"deliveredAt":    delivery.DeliveredAt,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery %s: %w", delivery.ID.Hex(), dbError(err))
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns a page of a webhook's deliveries newest first, of one status unless status is empty,
+// and how many there are in all
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, webhookID, status string, skip, limit int64) ([]model.WebhookDelivery, int64, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+	filter := bson.M{"webhookId": webhookID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.webhookDeliveriesCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch webhook deliveries: %w", dbError(err))
+	}
+	deliveries := []model.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode webhook deliveries: %w", dbError(err))
+	}
+	total, err := r.webhookDeliveriesCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", dbError(err))
+	}
+	return deliveries, total, nil
+}
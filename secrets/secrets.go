@@ -0,0 +1,86 @@
+// Package secrets reads credentials from a secret store, HashiCorp Vault or AWS Secrets Manager, so they do not have
+// to live in the environment, and refreshes them while the service runs.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// FetchTimeout bounds one request to the secret store
+const FetchTimeout = 10 * time.Second
+
+// Provider fetches the key/value pairs of one secret, e.g. {"MONGODBURL": "...", "REDISPASSWORD": "..."}. Keys are
+// named like the environment variables they replace.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// Options selects and configures a provider
+type Options struct {
+	Provider string // "vault", "aws", or empty for none
+
+	VaultAddr  string // e.g. https://vault.internal:8200
+	VaultToken string
+	VaultPath  string // path after /v1/, e.g. secret/data/problems-service for a KV v2 mount
+
+	AWSRegion   string
+	AWSSecretID string // name or ARN of a secret whose value is a JSON object
+}
+
+// NewProvider returns the configured provider, nil when none is configured
+func NewProvider(opts Options) (Provider, error) {
+	switch opts.Provider {
+	case "":
+		return nil, nil
+	case "vault":
+		if opts.VaultAddr == "" || opts.VaultToken == "" || opts.VaultPath == "" {
+			return nil, fmt.Errorf("vault secrets need an address, a token and a path")
+		}
+		return NewVault(opts.VaultAddr, opts.VaultToken, opts.VaultPath), nil
+	case "aws":
+		if opts.AWSRegion == "" || opts.AWSSecretID == "" {
+			return nil, fmt.Errorf("AWS Secrets Manager secrets need a region and a secret ID")
+		}
+		return NewAWSSecretsManager(opts.AWSRegion, opts.AWSSecretID), nil
+	}
+	return nil, fmt.Errorf("unknown secrets provider %q, want vault or aws", opts.Provider)
+}
+
+// Watch fetches the secret again every interval until ctx is done and calls onChange with the keys whose values
+// differ from the last fetch, starting from current. A failed fetch is passed to onError and the previous values are
+// kept. Watch blocks, so run it in its own goroutine.
+func Watch(ctx context.Context, provider Provider, interval time.Duration, current map[string]string, onChange func(changed map[string]string), onError func(err error)) {
+	current = maps.Clone(current)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fetchCtx, cancel := context.WithTimeout(ctx, FetchTimeout)
+		values, err := provider.Fetch(fetchCtx)
+		cancel()
+		if err != nil {
+			onError(err)
+			continue
+		}
+
+		changed := make(map[string]string)
+		for key, value := range values {
+			if current[key] != value {
+				changed[key] = value
+			}
+		}
+		current = values
+		if len(changed) > 0 {
+			onChange(changed)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+
+	"xcode/interceptor"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// apiKeyActorPrefix marks audit actors that are API keys rather than users
+const apiKeyActorPrefix = "api_key:"
+
+// AdminServer is the admin API. It has no proto definition, so its requests and responses are the JSON types of
+// package model.
+type AdminServer interface {
+	CreateAPIKey(context.Context, *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error)
+	ListAPIKeys(context.Context, *model.ListAPIKeysRequest) (*model.ListAPIKeysResponse, error)
+	RevokeAPIKey(context.Context, *model.RevokeAPIKeyRequest) (*model.RevokeAPIKeyResponse, error)
+	AdminResyncLeaderboard(context.Context, *model.AdminResyncLeaderboardRequest) (*model.AdminResyncLeaderboardResponse, error)
+	RecalculateScores(context.Context, *model.RecalculateScoresRequest) (*model.RecalculateScoresResponse, error)
+	BanUserFromLeaderboard(context.Context, *model.BanUserFromLeaderboardRequest) (*model.BanUserFromLeaderboardResponse, error)
+	InvalidateSubmission(context.Context, *model.InvalidateSubmissionRequest) (*model.InvalidateSubmissionResponse, error)
+	RejudgeProblem(context.Context, *model.RejudgeProblemRequest) (*model.RejudgeProblemResponse, error)
+	EnsureIndexes(context.Context, *model.EnsureIndexesRequest) (*model.EnsureIndexesResponse, error)
+	QueryAuditLog(context.Context, *model.QueryAuditLogRequest) (*model.QueryAuditLogResponse, error)
+	SetLogLevel(context.Context, *model.SetLogLevelRequest) (*model.SetLogLevelResponse, error)
+	AddProblemMaintainer(context.Context, *model.AddProblemMaintainerRequest) (*model.AddProblemMaintainerResponse, error)
+}
+
+var _ AdminServer = (*ProblemService)(nil)
+
+// AdminServiceDesc describes AdminServer the way protoc-gen-go-grpc describes a service, so the REST gateway serves
+// it through the server's interceptors like any RPC
+var AdminServiceDesc = grpc.ServiceDesc{
+	ServiceName: model.AdminServiceName,
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		adminMethod("CreateAPIKey", AdminServer.CreateAPIKey),
+		adminMethod("ListAPIKeys", AdminServer.ListAPIKeys),
+		adminMethod("RevokeAPIKey", AdminServer.RevokeAPIKey),
+		adminMethod("AdminResyncLeaderboard", AdminServer.AdminResyncLeaderboard),
+		adminMethod("RecalculateScores", AdminServer.RecalculateScores),
+		adminMethod("BanUserFromLeaderboard", AdminServer.BanUserFromLeaderboard),
+		adminMethod("InvalidateSubmission", AdminServer.InvalidateSubmission),
+		adminMethod("RejudgeProblem", AdminServer.RejudgeProblem),
+		adminMethod("EnsureIndexes", AdminServer.EnsureIndexes),
+		adminMethod("QueryAuditLog", AdminServer.QueryAuditLog),
+		adminMethod("SetLogLevel", AdminServer.SetLogLevel),
+		adminMethod("AddProblemMaintainer", AdminServer.AddProblemMaintainer),
+	},
+}
+
+// adminMethod is the generated handler of one AdminServer method: decode the request, then call through the interceptor
+func adminMethod[Req, Resp any](name string, call func(AdminServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	fullMethod := "/" + model.AdminServiceName + "/" + name
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, unary grpc.UnaryServerInterceptor) (any, error) {
+			in := new(Req)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if unary == nil {
+				return call(srv.(AdminServer), ctx, in)
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return call(srv.(AdminServer), ctx, req.(*Req))
+			}
+			return unary(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, handler)
+		},
+	}
+}
+
+// requireAdmin returns who is making an admin call: an API key with the admin scope, or the x-user-id caller when
+// the user service gives them the admin role. The request body cannot name the admin. Callers without an identity
+// get Unauthenticated, everyone else PermissionDenied.
+func (s *ProblemService) requireAdmin(ctx context.Context, traceID, method string) (string, error) {
+	if key := interceptor.APIKeyFromContext(ctx); key != nil {
+		if key.HasScope(model.APIKeyScopeAdmin) {
+			return apiKeyActorPrefix + key.ID.Hex(), nil
+		}
+		s.logger.Log(zapcore.WarnLevel, traceID, "Admin call with a non-admin API key", map[string]any{
+			"method":    method,
+			"keyId":     key.ID.Hex(),
+			"errorType": "PERMISSION_DENIED",
+		}, "SERVICE", nil)
+		return "", s.createGrpcError(ctx, codes.PermissionDenied, "API key lacks the admin scope", "PERMISSION_DENIED", nil)
+	}
+
+	userID := interceptor.UserIDFromMetadata(ctx)
+	if userID == "" {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Admin call without a caller", map[string]any{
+			"method":    method,
+			"errorType": "UNAUTHENTICATED",
+		}, "SERVICE", nil)
+		return "", s.createGrpcError(ctx, codes.Unauthenticated, "Sign in as an admin or use an admin API key", "UNAUTHENTICATED", nil)
+	}
+	role, err := s.userRole(ctx, userID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to look up role in user service", map[string]any{
+			"method":    method,
+			"userId":    userID,
+			"errorType": "USER_SERVICE_ERROR",
+		}, "SERVICE", err)
+		return "", s.createGrpcError(ctx, codes.Unavailable, "Failed to check the caller's role", "USER_SERVICE_ERROR", err)
+	}
+	if role != adminRole {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Admin call by a non-admin", map[string]any{
+			"method":    method,
+			"userId":    userID,
+			"errorType": "PERMISSION_DENIED",
+		}, "SERVICE", nil)
+		return "", s.createGrpcError(ctx, codes.PermissionDenied, "Only admins may do this", "PERMISSION_DENIED", nil)
+	}
+	return userID, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/gateway"
+	"xcode/interceptor"
+	"xcode/model"
+	"xcode/repository/mocks"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+// apiKeyStore backs the API key methods of a mock repository with a map, so keys live through a whole test
+type apiKeyStore struct {
+	mu   sync.Mutex
+	keys []*model.APIKey
+}
+
+func (k *apiKeyStore) expect(repo *mocks.MockProblemRepository) {
+	repo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key model.APIKey) (*model.APIKey, error) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		key.ID = primitive.NewObjectID()
+		k.keys = append(k.keys, &key)
+		created := key
+		return &created, nil
+	}).AnyTimes()
+	repo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, hash string) (*model.APIKey, error) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		for _, key := range k.keys {
+			if key.Hash == hash {
+				found := *key
+				return &found, nil
+			}
+		}
+		return nil, customerrors.NotFound("API key")
+	}).AnyTimes()
+	repo.EXPECT().TouchAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repo.EXPECT().ListAPIKeys(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, includeRevoked bool) ([]model.APIKey, error) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		var keys []model.APIKey
+		for _, key := range k.keys {
+			if includeRevoked || key.RevokedAt == nil {
+				keys = append(keys, *key)
+			}
+		}
+		return keys, nil
+	}).AnyTimes()
+	repo.EXPECT().RevokeAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, keyID string, at time.Time) (*model.APIKey, error) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		for _, key := range k.keys {
+			if key.ID.Hex() == keyID {
+				key.RevokedAt = &at
+				revoked := *key
+				return &revoked, nil
+			}
+		}
+		return nil, customerrors.NotFound("API key")
+	}).AnyTimes()
+	repo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func TestAPIKeyLifecycleThroughGateway(t *testing.T) {
+	repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+	var store apiKeyStore
+	store.expect(repo)
+	s := newTestService(t, repo)
+	// roles the user service would return, as userRole caches them
+	s.RedisCacheClient.Set(context.Background(), userRoleCachePrefix+"admin-user", `"admin"`, time.Minute)
+	s.RedisCacheClient.Set(context.Background(), userRoleCachePrefix+"member", `"user"`, time.Minute)
+
+	g, err := gateway.New(s, interceptor.Chain(interceptor.Unary(s.logger, nil, s, 0)...),
+		gateway.AnyOf(gateway.SharedSecret("gateway-secret"), gateway.APIKeys(s)))
+	if err != nil {
+		t.Fatalf("gateway.New: %v", err)
+	}
+	g.Register("admin", &AdminServiceDesc, s)
+
+	call := func(method string, headers map[string]string, body string, wantStatus int, out any) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "/v1/admin/"+method, strings.NewReader(body))
+		for header, value := range headers {
+			r.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		if w.Code != wantStatus {
+			t.Fatalf("%s %v: status %d, want %d: %s", method, headers, w.Code, wantStatus, w.Body)
+		}
+		if out != nil {
+			if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+				t.Fatalf("%s: decode response: %v", method, err)
+			}
+		}
+	}
+	asUser := func(userID string) map[string]string {
+		return map[string]string{gateway.SecretHeader: "gateway-secret", "x-user-id": userID}
+	}
+	withKey := func(secret string) map[string]string {
+		return map[string]string{interceptor.APIKeyHeader: secret}
+	}
+
+	// nobody can name themselves admin in the body
+	call("CreateAPIKey", nil, `{"name":"ops","scopes":["admin"],"adminId":"admin-user"}`, http.StatusUnauthorized, nil)
+	call("CreateAPIKey", asUser("member"), `{"name":"ops","scopes":["admin"],"adminId":"admin-user"}`, http.StatusForbidden, nil)
+
+	var ops model.CreateAPIKeyResponse
+	call("CreateAPIKey", asUser("admin-user"), `{"name":"ops","scopes":["admin"]}`, http.StatusOK, &ops)
+	if ops.Secret == "" || ops.Key.CreatedBy != "admin-user" {
+		t.Fatalf("ops key = %+v, want a secret created by admin-user", ops)
+	}
+
+	// an admin key manages keys on its own, and is recorded as their creator
+	var reader model.CreateAPIKeyResponse
+	call("CreateAPIKey", withKey(ops.Secret), `{"name":"reader","scopes":["problems:read"]}`, http.StatusOK, &reader)
+	if want := apiKeyActorPrefix + ops.Key.ID.Hex(); reader.Key.CreatedBy != want {
+		t.Errorf("reader key created by %q, want %q", reader.Key.CreatedBy, want)
+	}
+	call("ListAPIKeys", withKey(reader.Secret), `{}`, http.StatusForbidden, nil)
+
+	var listed model.ListAPIKeysResponse
+	call("ListAPIKeys", withKey(ops.Secret), `{}`, http.StatusOK, &listed)
+	if len(listed.Keys) != 2 {
+		t.Fatalf("listed %d keys, want 2", len(listed.Keys))
+	}
+
+	call("RevokeAPIKey", withKey(ops.Secret), `{"keyId":"`+reader.Key.ID.Hex()+`"}`, http.StatusOK, nil)
+	call("ListAPIKeys", withKey(reader.Secret), `{}`, http.StatusUnauthorized, nil)
+	call("ListAPIKeys", withKey(ops.Secret), `{}`, http.StatusOK, &listed)
+	if len(listed.Keys) != 1 || listed.Keys[0].ID != ops.Key.ID {
+		t.Errorf("listed %+v after revoking, want only the ops key", listed.Keys)
+	}
+}
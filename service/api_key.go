@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	apiKeySecretPrefix = "xck_"
+	apiKeyDisplayChars = 12 // of the secret kept as APIKey.Prefix
+	apiKeyCachePrefix  = "api_key:"
+	// a revoked key is dropped from the cache at once, so this mostly bounds how often last use is recorded
+	apiKeyCacheTTL = time.Minute
+
+	maxAPIKeyNameLength = 100
+)
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey issues a scoped API key. The secret is returned once; only its hash is stored.
+func (s *ProblemService) CreateAPIKey(ctx context.Context, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting CreateAPIKey", map[string]any{
+		"method": "CreateAPIKey",
+		"name":   req.Name,
+		"scopes": req.Scopes,
+	}, "SERVICE", nil)
+
+	adminID, err := s.requireAdmin(ctx, traceID, "CreateAPIKey")
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Name must be 1 to 100 characters", "VALIDATION_ERROR", nil)
+	}
+	if len(req.Scopes) == 0 {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "At least one scope is required", "VALIDATION_ERROR", nil)
+	}
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !slices.Contains(model.APIKeyScopes, scope) {
+			return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Scopes must be among "+strings.Join(model.APIKeyScopes, ", "), "VALIDATION_ERROR", nil)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresInDays < 0 {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Expiry cannot be negative", "VALIDATION_ERROR", nil)
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to generate API key", "INTERNAL_ERROR", err)
+	}
+	secret := apiKeySecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	now := time.Now()
+	key := model.APIKey{
+		Name:      name,
+		Prefix:    secret[:apiKeyDisplayChars],
+		Hash:      hashAPIKey(secret),
+		Scopes:    scopes,
+		CreatedBy: adminID,
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	created, err := s.RepoConnInstance.CreateAPIKey(ctx, key)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to create API key", map[string]any{
+			"method":    "CreateAPIKey",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to create API key")
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionCreateAPIKey,
+		Actor:      adminID,
+		TargetType: model.AuditTargetAPIKey,
+		TargetID:   created.ID.Hex(),
+		After:      map[string]any{"name": created.Name, "prefix": created.Prefix, "scopes": created.Scopes, "expiresAt": created.ExpiresAt},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "API key created", map[string]any{
+		"method": "CreateAPIKey",
+		"keyId":  created.ID.Hex(),
+		"prefix": created.Prefix,
+	}, "SERVICE", nil)
+	return &model.CreateAPIKeyResponse{Key: *created, Secret: secret}, nil
+}
+
+// ListAPIKeys lists the API keys newest first; secrets cannot be listed
+func (s *ProblemService) ListAPIKeys(ctx context.Context, req *model.ListAPIKeysRequest) (*model.ListAPIKeysResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if _, err := s.requireAdmin(ctx, traceID, "ListAPIKeys"); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.RepoConnInstance.ListAPIKeys(ctx, req.IncludeRevoked)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list API keys", map[string]any{
+			"method":    "ListAPIKeys",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list API keys")
+	}
+	return &model.ListAPIKeysResponse{Keys: keys}, nil
+}
+
+// RevokeAPIKey revokes a key; requests carrying it are rejected from then on
+func (s *ProblemService) RevokeAPIKey(ctx context.Context, req *model.RevokeAPIKeyRequest) (*model.RevokeAPIKeyResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RevokeAPIKey", map[string]any{
+		"method": "RevokeAPIKey",
+		"keyId":  req.KeyID,
+	}, "SERVICE", nil)
+
+	adminID, err := s.requireAdmin(ctx, traceID, "RevokeAPIKey")
+	if err != nil {
+		return nil, err
+	}
+	if req.KeyID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Key ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	key, err := s.RepoConnInstance.RevokeAPIKey(ctx, req.KeyID, time.Now())
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to revoke API key", map[string]any{
+			"method":    "RevokeAPIKey",
+			"keyId":     req.KeyID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to revoke API key")
+	}
+	if err := s.RedisCacheClient.Delete(ctx, apiKeyCachePrefix+key.Hash); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    "RevokeAPIKey",
+			"keyId":     req.KeyID,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionRevokeAPIKey,
+		Actor:      adminID,
+		TargetType: model.AuditTargetAPIKey,
+		TargetID:   req.KeyID,
+		Before:     map[string]any{"name": key.Name, "prefix": key.Prefix},
+		After:      map[string]any{"revokedAt": key.RevokedAt},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "API key revoked", map[string]any{
+		"method": "RevokeAPIKey",
+		"keyId":  req.KeyID,
+	}, "SERVICE", nil)
+	return &model.RevokeAPIKeyResponse{Key: *key}, nil
+}
+
+// ValidateAPIKey returns the key secret belongs to, revoked and expired keys included, for the API key interceptor
+// to check. Keys are cached by hash for a minute and their last use is recorded when they are loaded.
+func (s *ProblemService) ValidateAPIKey(ctx context.Context, secret string) (*model.APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeySecretPrefix) {
+		return nil, customerrors.NotFound("API key")
+	}
+	hash := hashAPIKey(secret)
+	key, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, apiKeyCachePrefix+hash, apiKeyCacheTTL, func(ctx context.Context) (*model.APIKey, error) {
+		key, err := s.RepoConnInstance.GetAPIKeyByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.RepoConnInstance.TouchAPIKey(ctx, key.ID, time.Now()); err != nil {
+			s.logger.Log(zapcore.WarnLevel, traceIDFromContext(ctx), "Failed to record API key use", map[string]any{
+				"method":    "ValidateAPIKey",
+				"keyId":     key.ID.Hex(),
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
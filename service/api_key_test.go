@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/repository/mocks"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidateAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("secret without the prefix is not looked up", func(t *testing.T) {
+		repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+		s := newTestService(t, repo)
+
+		_, err := s.ValidateAPIKey(ctx, "not-a-key")
+		if !errors.Is(err, customerrors.ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("known key is loaded once, touched and then served from the cache", func(t *testing.T) {
+		repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+		s := newTestService(t, repo)
+		secret := apiKeySecretPrefix + "known"
+		stored := &model.APIKey{ID: primitive.NewObjectID(), Name: "ci", Scopes: []string{model.APIKeyScopeSubmit}}
+
+		repo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey(secret)).Return(stored, nil).Times(1)
+		repo.EXPECT().TouchAPIKey(gomock.Any(), stored.ID, gomock.Any()).Return(nil).Times(1)
+
+		for range 2 {
+			key, err := s.ValidateAPIKey(ctx, secret)
+			if err != nil {
+				t.Fatalf("ValidateAPIKey: %v", err)
+			}
+			if key.ID != stored.ID || !key.HasScope(model.APIKeyScopeSubmit) {
+				t.Fatalf("key = %+v, want %+v", key, stored)
+			}
+		}
+	})
+
+	t.Run("failing to record the use still validates the key", func(t *testing.T) {
+		repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+		s := newTestService(t, repo)
+		secret := apiKeySecretPrefix + "untouched"
+		stored := &model.APIKey{ID: primitive.NewObjectID()}
+
+		repo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey(secret)).Return(stored, nil)
+		repo.EXPECT().TouchAPIKey(gomock.Any(), stored.ID, gomock.Any()).Return(errors.New("write timeout"))
+
+		if _, err := s.ValidateAPIKey(ctx, secret); err != nil {
+			t.Fatalf("ValidateAPIKey: %v", err)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+		s := newTestService(t, repo)
+		secret := apiKeySecretPrefix + "unknown"
+
+		repo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey(secret)).Return(nil, customerrors.NotFound("API key"))
+
+		if _, err := s.ValidateAPIKey(ctx, secret); !errors.Is(err, customerrors.ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/interceptor"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+	unknownAuditActor    = "unknown"
+	systemAuditActor     = "system" // jobs the service runs on its own
+)
+
+// recordAudit appends an entry to the audit log. It is best effort: the mutation already happened, so a failed write
+// is logged instead of failing the RPC. The actor is taken from the gateway's x-user-id metadata when the entry
+// does not name one.
+func (s *ProblemService) recordAudit(ctx context.Context, traceID string, entry model.AuditEntry) {
+	if entry.Actor == "" {
+		entry.Actor = interceptor.UserIDFromMetadata(ctx)
+	}
+	if entry.Actor == "" {
+		entry.Actor = unknownAuditActor
+	}
+	entry.TraceID = traceID
+	entry.CreatedAt = time.Now()
+
+	if err := s.RepoConnInstance.SaveAuditEntry(ctx, entry); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save audit entry", map[string]any{
+			"method":    "recordAudit",
+			"action":    entry.Action,
+			"actor":     entry.Actor,
+			"targetId":  entry.TargetID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+	}
+}
+
+// problemForAudit loads a problem for the Before summary of an audit entry; the mutation does not depend on it,
+// so a failed load only leaves the summary empty
+func (s *ProblemService) problemForAudit(ctx context.Context, traceID, method, problemID string) *model.Problem {
+	problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: problemID})
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to load problem for audit", map[string]any{
+			"method":    method,
+			"problemId": problemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil
+	}
+	return problem
+}
+
+// problemAuditSummary is what the audit log keeps of a problem: enough to recognize it, not its full content
+func problemAuditSummary(problem *model.Problem) map[string]any {
+	if problem == nil {
+		return nil
+	}
+	return map[string]any{
+		"title":           problem.Title,
+		"difficulty":      problem.Difficulty,
+		"tags":            problem.Tags,
+		"visible":         problem.Visible,
+		"runTestCases":    len(problem.TestCases.Run),
+		"submitTestCases": len(problem.TestCases.Submit),
+	}
+}
+
+// QueryAuditLog pages through the audit log newest first, optionally filtered by actor, action, target and time range
+func (s *ProblemService) QueryAuditLog(ctx context.Context, req *model.QueryAuditLogRequest) (*model.QueryAuditLogResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting QueryAuditLog", map[string]any{
+		"method":   "QueryAuditLog",
+		"actor":    req.Actor,
+		"action":   req.Action,
+		"targetId": req.TargetID,
+		"page":     req.Page,
+	}, "SERVICE", nil)
+
+	if _, err := s.requireAdmin(ctx, traceID, "QueryAuditLog"); err != nil {
+		return nil, err
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid time range", map[string]any{
+			"method":    "QueryAuditLog",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "From must be before to", "VALIDATION_ERROR", nil)
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultAuditPageSize
+	}
+	if pageSize > maxAuditPageSize {
+		pageSize = maxAuditPageSize
+	}
+
+	filter := model.AuditLogFilter{
+		Actor:    req.Actor,
+		Action:   req.Action,
+		TargetID: req.TargetID,
+		From:     req.From,
+		To:       req.To,
+	}
+	entries, total, err := s.RepoConnInstance.QueryAuditLog(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to query audit log", map[string]any{
+			"method":    "QueryAuditLog",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to query audit log", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Audit log retrieved successfully", map[string]any{
+		"method": "QueryAuditLog",
+		"count":  len(entries),
+		"total":  total,
+	}, "SERVICE", nil)
+	return &model.QueryAuditLogResponse{Entries: entries, Total: total}, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+)
+
+const cacheWarmLockTTL = time.Minute
+
+// EnableCacheWarming makes the service precompute the first pages of the problem lists at startup and after
+// every bulk list invalidation. Warming is off until this is called with pages > 0.
+func (s *ProblemService) EnableCacheWarming(pages, pageSize int) {
+	s.warmPages = pages
+	s.warmPageSize = pageSize
+}
+
+// WarmProblemListCaches loads the first pages of ListProblems and GetProblemMetadataList through the regular
+// handlers so they land in the cache exactly as a user request would store them
+func (s *ProblemService) WarmProblemListCaches(ctx context.Context) {
+	if s.warmPages <= 0 {
+		return
+	}
+	s.runSingleton(ctx, "cache_warm", cacheWarmLockTTL, false, func(ctx context.Context) {
+		traceID := traceIDFromContext(ctx)
+		start := time.Now()
+		s.logger.Log(zapcore.InfoLevel, traceID, "Starting WarmProblemListCaches", map[string]any{
+			"method":   "WarmProblemListCaches",
+			"pages":    s.warmPages,
+			"pageSize": s.warmPageSize,
+		}, "SERVICE", nil)
+
+		failed := 0
+		for page := 1; page <= s.warmPages; page++ {
+			if _, err := s.ListProblems(ctx, &pb.ListProblemsRequest{Page: int32(page), PageSize: int32(s.warmPageSize)}); err != nil {
+				failed++
+			}
+			if _, err := s.GetProblemMetadataList(ctx, &pb.GetProblemMetadataListRequest{Page: int32(page), PageSize: int32(s.warmPageSize)}); err != nil {
+				failed++
+			}
+		}
+
+		s.logger.Log(zapcore.InfoLevel, traceID, "Problem list caches warmed", map[string]any{
+			"method":   "WarmProblemListCaches",
+			"failed":   failed,
+			"duration": time.Since(start).String(),
+		}, "SERVICE", nil)
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	configs "xcode/config"
+	"xcode/model"
+
+	"github.com/google/uuid"
+	cron "github.com/robfig/cron/v3"
+	"go.uber.org/zap/zapcore"
+)
+
+// cronJob is a periodic job whose schedule comes from configs.CronConfig and can be changed while it is scheduled
+type cronJob struct {
+	schedule func(configs.CronConfig) string
+	run      func()
+	entry    cron.EntryID
+}
+
+// scheduleCronJob adds run to c on the schedule picked from schedules and remembers it for RescheduleCronJobs
+func (s *ProblemService) scheduleCronJob(c *cron.Cron, schedule func(configs.CronConfig) string, schedules configs.CronConfig, run func()) {
+	job := &cronJob{schedule: schedule, run: run}
+	job.entry, _ = c.AddFunc(schedule(schedules), run)
+
+	s.cronMu.Lock()
+	s.cronJobs = append(s.cronJobs, job)
+	s.cronMu.Unlock()
+}
+
+// RescheduleCronJobs moves the jobs started by StartCronJob to the given schedules. Runs in progress finish
+// undisturbed. Every schedule is parsed before anything moves, so an invalid one changes nothing.
+func (s *ProblemService) RescheduleCronJobs(schedules configs.CronConfig) error {
+	s.cronMu.Lock()
+	defer s.cronMu.Unlock()
+	if s.cron == nil {
+		return nil
+	}
+
+	parsed := make([]cron.Schedule, len(s.cronJobs))
+	for i, job := range s.cronJobs {
+		spec := job.schedule(schedules)
+		if spec == job.schedule(s.cronSchedules) {
+			continue
+		}
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			return fmt.Errorf("invalid cron schedule %q: %w", spec, err)
+		}
+		parsed[i] = schedule
+	}
+
+	for i, job := range s.cronJobs {
+		if parsed[i] == nil {
+			continue
+		}
+		s.cron.Remove(job.entry)
+		job.entry = s.cron.Schedule(parsed[i], cron.FuncJob(job.run))
+	}
+	s.cronSchedules = schedules
+	return nil
+}
+
+// cacheTTLs returns the cache lifetimes currently in effect
+func (s *ProblemService) cacheTTLs() configs.CacheTTLConfig {
+	return *s.cacheTTL.Load()
+}
+
+// ApplyReloadedConfig switches the log level, cache lifetimes, cron schedules and feature flags from previous to
+// current and announces the change on model.ConfigChangedSubject. Rate limits live in the gRPC interceptors, which
+// the caller updates itself. Cached entries keep the lifetime they were written with.
+func (s *ProblemService) ApplyReloadedConfig(ctx context.Context, trigger string, previous, current configs.Reloadable) error {
+	traceID := traceIDFromContext(ctx)
+	changed := current.Changed(previous)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if slices.Contains(changed, "cron") {
+		if err := s.RescheduleCronJobs(current.Cron); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to reschedule cron jobs", map[string]any{
+				"method":    "ApplyReloadedConfig",
+				"trigger":   trigger,
+				"errorType": "CONFIG_ERROR",
+			}, "SERVICE", err)
+			return err
+		}
+	}
+	if slices.Contains(changed, "logLevel") {
+		level, err := zapcore.ParseLevel(current.LogLevel)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q: %w", current.LogLevel, err)
+		}
+		s.logger.SetLevel(level)
+	}
+	if slices.Contains(changed, "cacheTTL") {
+		cacheTTL := current.CacheTTL
+		s.cacheTTL.Store(&cacheTTL)
+	}
+	if slices.Contains(changed, "features") {
+		features := current.Features
+		s.features.Store(&features)
+	}
+
+	// logged at warn so the change itself shows up whatever the new level is
+	s.logger.Log(zapcore.WarnLevel, traceID, "Configuration reloaded", map[string]any{
+		"method":  "ApplyReloadedConfig",
+		"trigger": trigger,
+		"changed": changed,
+	}, "SERVICE", nil)
+	s.publishConfigChanged(traceID, trigger, changed)
+	return nil
+}
+
+// publishConfigChanged tells other services and replicas that this replica now runs with changed settings
+func (s *ProblemService) publishConfigChanged(traceID, trigger string, changed []string) {
+	replica, _ := os.Hostname()
+	eventBytes, err := json.Marshal(model.ConfigChangedEvent{
+		EventID:   uuid.New().String(),
+		Replica:   replica,
+		Changed:   changed,
+		Trigger:   trigger,
+		ChangedAt: time.Now(),
+		TraceID:   traceID,
+	})
+	if err == nil {
+		err = s.NatsClient.Publish(model.ConfigChangedSubject, eventBytes)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to publish config changed event", map[string]any{
+			"method":    "publishConfigChanged",
+			"changed":   changed,
+			"errorType": "NATS_ERROR",
+		}, "SERVICE", err)
+	}
+}
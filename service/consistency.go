@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// firstSuccessCheckWindow is how far back the scheduled consistency check looks; it runs daily, so a window of two
+// days also covers a skipped run
+const firstSuccessCheckWindow = 48 * time.Hour
+
+// CheckFirstSuccessConsistency cross-checks the successful submissions against the first successes the leaderboards
+// are built from. Missing first successes are stored again, with their scores, and their users refreshed on the
+// boards; the other discrepancies are only reported. Every discrepancy is counted in first_success_discrepancies_total.
+func (s *ProblemService) CheckFirstSuccessConsistency(ctx context.Context, req *model.CheckFirstSuccessConsistencyRequest) (*model.CheckFirstSuccessConsistencyResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	started := time.Now()
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting CheckFirstSuccessConsistency", map[string]any{
+		"method": "CheckFirstSuccessConsistency",
+		"since":  req.Since,
+		"dryRun": req.DryRun,
+	}, "SERVICE", nil)
+
+	missing, err := s.RepoConnInstance.FindMissingFirstSuccessesMongo(ctx, req.Since)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to find missing first successes", map[string]any{
+			"method":    "CheckFirstSuccessConsistency",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to check first successes")
+	}
+	discrepancies, err := s.RepoConnInstance.FindFirstSuccessDiscrepanciesMongo(ctx, req.Since)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to check first successes", map[string]any{
+			"method":    "CheckFirstSuccessConsistency",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to check first successes")
+	}
+
+	resp := &model.CheckFirstSuccessConsistencyResponse{}
+	repairedUsers := make(map[string]bool)
+	for _, submission := range missing {
+		discrepancy := model.FirstSuccessDiscrepancy{
+			Kind:            model.FirstSuccessMissing,
+			UserID:          submission.UserID,
+			ProblemID:       submission.ProblemID,
+			SubmissionID:    submission.ID.Hex(),
+			SubmissionScore: submission.Score,
+			SubmittedAt:     submission.SubmittedAt,
+		}
+		if !req.DryRun {
+			score, err := s.RepoConnInstance.RepairFirstSuccess(ctx, submission)
+			if err != nil {
+				s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to repair first success", map[string]any{
+					"method":       "CheckFirstSuccessConsistency",
+					"submissionId": discrepancy.SubmissionID,
+					"errorType":    customerrors.Type(err),
+				}, "SERVICE", err)
+			} else if score > 0 {
+				discrepancy.Score, discrepancy.Repaired = score, true
+				resp.Repaired++
+				repairedUsers[submission.UserID] = true
+				s.invalidateUserSubmissionCaches(ctx, traceID, submission.UserID, submission.ProblemID)
+			}
+		}
+		resp.Discrepancies = append(resp.Discrepancies, discrepancy)
+	}
+	resp.Discrepancies = append(resp.Discrepancies, discrepancies...)
+
+	for userID := range repairedUsers {
+		if err := s.refreshUserOnLeaderboards(ctx, userID); err != nil {
+			// the next full sync picks the repaired first successes up
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update leaderboards after repair", map[string]any{
+				"method":    "CheckFirstSuccessConsistency",
+				"userId":    userID,
+				"errorType": "LEADERBOARD_ERROR",
+			}, "SERVICE", err)
+			continue
+		}
+		resp.Resynced++
+	}
+
+	for _, discrepancy := range resp.Discrepancies {
+		firstSuccessDiscrepancies.Add(discrepancy.Kind, 1)
+	}
+	firstSuccessRepairs.Add(int64(resp.Repaired))
+
+	level := zapcore.InfoLevel
+	if len(resp.Discrepancies) > 0 {
+		level = zapcore.WarnLevel
+	}
+	s.logger.Log(level, traceID, "First success consistency checked", map[string]any{
+		"method":        "CheckFirstSuccessConsistency",
+		"discrepancies": len(resp.Discrepancies),
+		"repaired":      resp.Repaired,
+		"resynced":      resp.Resynced,
+		"duration":      time.Since(started).Seconds(),
+	}, "SERVICE", nil)
+	return resp, nil
+}
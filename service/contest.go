@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/repository"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// contestFinalizeGrace leaves late judging a few minutes to land before a contest's standings become official
+	contestFinalizeGrace = 5 * time.Minute
+
+	defaultContestStandingsPageSize = 50
+	maxContestStandingsPageSize     = 200
+)
+
+// CreateContest schedules a contest. The problems must exist; they stay hidden from everyone but admins until the
+// contest starts.
+func (s *ProblemService) CreateContest(ctx context.Context, req *model.CreateContestRequest) (*model.CreateContestResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting CreateContest", map[string]any{
+		"method":   "CreateContest",
+		"title":    req.Contest.Title,
+		"adminId":  req.AdminID,
+		"problems": len(req.Contest.ProblemIDs),
+	}, "SERVICE", nil)
+
+	if err := s.checkRequest(traceID, "CreateContest", req); err != nil {
+		return nil, err
+	}
+	contest := req.Contest
+	contest.Title = strings.TrimSpace(contest.Title)
+	if len(contest.Divisions) == 0 {
+		contest.Divisions = []model.ContestDivision{{Name: model.DefaultContestDivision}}
+	}
+	for _, problemID := range contest.ProblemIDs {
+		if _, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: problemID}); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch contest problem", map[string]any{
+				"method":    "CreateContest",
+				"problemId": problemID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			return nil, s.repoError(ctx, err, "Failed to fetch problem")
+		}
+	}
+
+	contest.CreatedBy = req.AdminID
+	contest.CreatedAt = time.Now()
+	created, err := s.RepoConnInstance.CreateContest(ctx, contest)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to create contest", map[string]any{
+			"method":    "CreateContest",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to create contest")
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionCreateContest,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetContest,
+		TargetID:   created.ID.Hex(),
+		After: map[string]any{
+			"title":      created.Title,
+			"startsAt":   created.StartsAt,
+			"endsAt":     created.EndsAt,
+			"problemIds": created.ProblemIDs,
+			"rated":      created.Rated,
+		},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Contest created", map[string]any{
+		"method":    "CreateContest",
+		"contestId": created.ID.Hex(),
+	}, "SERVICE", nil)
+	return &model.CreateContestResponse{Contest: *created}, nil
+}
+
+// validateProblemSet checks that a contest or an assignment (what) has 1 to max problems, each given once
+func validateProblemSet(problemIDs []string, max int, what string) error {
+	if len(problemIDs) == 0 {
+		return customerrors.Validation("at least one problem is required")
+	}
+	if len(problemIDs) > max {
+		return customerrors.Validation("a %s has at most %d problems", what, max)
+	}
+	problems := make(map[string]bool, len(problemIDs))
+	for _, problemID := range problemIDs {
+		if problemID == "" || problems[problemID] {
+			return customerrors.Validation("problem IDs must be non-empty and unique")
+		}
+		problems[problemID] = true
+	}
+	return nil
+}
+
+// GetContest returns a contest and its status; its problems are left out until it starts
+func (s *ProblemService) GetContest(ctx context.Context, req *model.GetContestRequest) (*model.GetContestResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	contest, err := s.fetchContest(ctx, traceID, "GetContest", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	status := contest.Status(time.Now())
+	if status == model.ContestStatusScheduled {
+		contest.ProblemIDs = nil
+	}
+	return &model.GetContestResponse{Contest: *contest, Status: status}, nil
+}
+
+// RegisterForContest registers a user while registration is open, placing them in the division their current rating
+// falls in. Registering again returns the existing registration.
+func (s *ProblemService) RegisterForContest(ctx context.Context, req *model.RegisterForContestRequest) (*model.RegisterForContestResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RegisterForContest", map[string]any{
+		"method":    "RegisterForContest",
+		"contestId": req.ContestID,
+		"userId":    req.UserID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+	contest, err := s.fetchContest(ctx, traceID, "RegisterForContest", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(contest.RegistrationOpensAt) || !now.Before(contest.RegistrationClosesAt) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Registration is closed", map[string]any{
+			"method":    "RegisterForContest",
+			"contestId": req.ContestID,
+			"errorType": "REGISTRATION_CLOSED",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Registration for this contest is not open", "REGISTRATION_CLOSED", nil)
+	}
+
+	ratings, err := s.RepoConnInstance.GetUserRatings(ctx, []string{req.UserID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch user rating", map[string]any{
+			"method":    "RegisterForContest",
+			"userId":    req.UserID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch user rating")
+	}
+	rating := model.DefaultContestRating
+	if userRating, ok := ratings[req.UserID]; ok {
+		rating = userRating.Rating
+	}
+	division, ok := contestDivisionFor(contest.Divisions, rating)
+	if !ok {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "No division for rating", map[string]any{
+			"method":    "RegisterForContest",
+			"contestId": req.ContestID,
+			"rating":    rating,
+			"errorType": "NOT_ELIGIBLE",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "No division of this contest accepts your rating", "NOT_ELIGIBLE", nil)
+	}
+
+	registration, created, err := s.RepoConnInstance.RegisterForContest(ctx, model.ContestRegistration{
+		ContestID:    req.ContestID,
+		UserID:       req.UserID,
+		Division:     division,
+		Rating:       rating,
+		RegisteredAt: now,
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to register for contest", map[string]any{
+			"method":    "RegisterForContest",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to register for contest")
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Registered for contest", map[string]any{
+		"method":    "RegisterForContest",
+		"contestId": req.ContestID,
+		"division":  registration.Division,
+		"created":   created,
+	}, "SERVICE", nil)
+	return &model.RegisterForContestResponse{Registration: *registration, Created: created}, nil
+}
+
+// contestDivisionFor returns the first division whose rating range holds rating
+func contestDivisionFor(divisions []model.ContestDivision, rating int) (string, bool) {
+	for _, division := range divisions {
+		if rating >= division.MinRating && (division.MaxRating == 0 || rating < division.MaxRating) {
+			return division.Name, true
+		}
+	}
+	return "", false
+}
+
+// GetContestProblems lists the problems of a contest that started. While it runs only registered users see them.
+func (s *ProblemService) GetContestProblems(ctx context.Context, req *model.GetContestProblemsRequest) (*model.GetContestProblemsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	contest, err := s.fetchContest(ctx, traceID, "GetContestProblems", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch contest.Status(time.Now()) {
+	case model.ContestStatusScheduled:
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Contest has not started", "CONTEST_NOT_STARTED", nil)
+	case model.ContestStatusRunning:
+		if req.UserID == "" {
+			return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+		}
+		if _, err := s.RepoConnInstance.GetContestRegistration(ctx, req.ContestID, req.UserID); err != nil {
+			if errors.Is(err, customerrors.ErrNotFound) {
+				return nil, s.createGrpcError(ctx, codes.PermissionDenied, "Only registered users can see the problems of a running contest", "NOT_REGISTERED", err)
+			}
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch contest registration", map[string]any{
+				"method":    "GetContestProblems",
+				"contestId": req.ContestID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			return nil, s.repoError(ctx, err, "Failed to fetch contest registration")
+		}
+	}
+
+	problems, err := s.problemSet(ctx, traceID, "GetContestProblems", contest.ProblemIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &model.GetContestProblemsResponse{Problems: problems}, nil
+}
+
+// problemSet lists the problems of a contest or an assignment in order, indexed A, B, C, ...
+func (s *ProblemService) problemSet(ctx context.Context, traceID, method string, problemIDs []string) ([]model.ContestProblem, error) {
+	problems := make([]model.ContestProblem, 0, len(problemIDs))
+	for i, problemID := range problemIDs {
+		problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: problemID})
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem of problem set", map[string]any{
+				"method":    method,
+				"problemId": problemID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			return nil, s.repoError(ctx, err, "Failed to fetch problem")
+		}
+		problems = append(problems, model.ContestProblem{
+			Index:      problemSetIndex(i),
+			ProblemID:  problemID,
+			Title:      problem.Title,
+			Difficulty: problem.Difficulty,
+		})
+	}
+	return problems, nil
+}
+
+// problemSetIndex names the i-th problem of a set: A to Z, then AA, AB, ...
+func problemSetIndex(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return problemSetIndex(i/26-1) + string(rune('A'+i%26))
+}
+
+// GetContestStandings returns a page of one division's standings: the official ones once the contest is finalized,
+// live ones computed from submissions before that
+func (s *ProblemService) GetContestStandings(ctx context.Context, req *model.GetContestStandingsRequest) (*model.GetContestStandingsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	contest, err := s.fetchContest(ctx, traceID, "GetContestStandings", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+
+	division := req.Division
+	if division == "" && len(contest.Divisions) > 0 {
+		division = contest.Divisions[0].Name
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultContestStandingsPageSize
+	}
+	if limit > maxContestStandingsPageSize {
+		limit = maxContestStandingsPageSize
+	}
+	skip := (page - 1) * limit
+
+	switch contest.Status(time.Now()) {
+	case model.ContestStatusScheduled:
+		return &model.GetContestStandingsResponse{Standings: []model.ContestStanding{}}, nil
+	case model.ContestStatusFinalized:
+		standings, total, err := s.RepoConnInstance.GetContestStandings(ctx, req.ContestID, division, skip, limit)
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch contest standings", map[string]any{
+				"method":    "GetContestStandings",
+				"contestId": req.ContestID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			return nil, s.repoError(ctx, err, "Failed to fetch contest standings")
+		}
+		return &model.GetContestStandingsResponse{Standings: standings, Total: total, Final: true}, nil
+	}
+
+	live, err := s.liveContestStandings(ctx, *contest)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to compute contest standings", map[string]any{
+			"method":    "GetContestStandings",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to compute contest standings")
+	}
+	standings := live[division]
+	total := int64(len(standings))
+	if skip >= total {
+		return &model.GetContestStandingsResponse{Standings: []model.ContestStanding{}, Total: total}, nil
+	}
+	return &model.GetContestStandingsResponse{Standings: standings[skip:min(skip+limit, total)], Total: total}, nil
+}
+
+// FinalizeContest publishes the official standings of a contest that ended and, if it is rated, applies the rating
+// changes. The cron job does the same for every contest once contestFinalizeGrace has passed.
+func (s *ProblemService) FinalizeContest(ctx context.Context, req *model.FinalizeContestRequest) (*model.FinalizeContestResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting FinalizeContest", map[string]any{
+		"method":    "FinalizeContest",
+		"contestId": req.ContestID,
+		"adminId":   req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "FinalizeContest",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	contest, err := s.fetchContest(ctx, traceID, "FinalizeContest", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	switch contest.Status(time.Now()) {
+	case model.ContestStatusFinalized:
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Contest is already finalized", "CONTEST_FINALIZED", nil)
+	case model.ContestStatusScheduled, model.ContestStatusRunning:
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Contest has not ended", "CONTEST_NOT_ENDED", nil)
+	}
+
+	finalized, participants, err := s.finalizeContest(ctx, *contest)
+	if errors.Is(err, repository.ErrContestAlreadyFinalized) {
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Contest is already finalized", "CONTEST_FINALIZED", err)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to finalize contest", map[string]any{
+			"method":    "FinalizeContest",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to finalize contest")
+	}
+
+	s.enqueueWebhookEvent(ctx, traceID, model.WebhookEventChallengeEnded, challengeEndedEvent(*finalized, participants))
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionFinalizeContest,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetContest,
+		TargetID:   req.ContestID,
+		After:      map[string]any{"participants": participants, "rated": finalized.Rated},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Contest finalized", map[string]any{
+		"method":       "FinalizeContest",
+		"contestId":    req.ContestID,
+		"participants": participants,
+	}, "SERVICE", nil)
+	return &model.FinalizeContestResponse{Contest: *finalized, Participants: participants}, nil
+}
+
+// FinalizeEndedContests finalizes every contest that ended more than contestFinalizeGrace ago. A contest that
+// fails is logged and retried on the next run.
+func (s *ProblemService) FinalizeEndedContests(ctx context.Context) error {
+	traceID := traceIDFromContext(ctx)
+	contests, err := s.RepoConnInstance.GetContestsToFinalize(ctx, time.Now().Add(-contestFinalizeGrace))
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch contests to finalize", map[string]any{
+			"method":    "FinalizeEndedContests",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return err
+	}
+
+	var failed error
+	for _, contest := range contests {
+		_, participants, err := s.finalizeContest(ctx, contest)
+		if errors.Is(err, repository.ErrContestAlreadyFinalized) {
+			continue
+		}
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to finalize contest", map[string]any{
+				"method":    "FinalizeEndedContests",
+				"contestId": contest.ID.Hex(),
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			failed = err
+			continue
+		}
+		s.enqueueWebhookEvent(ctx, traceID, model.WebhookEventChallengeEnded, challengeEndedEvent(contest, participants))
+		s.recordAudit(ctx, traceID, model.AuditEntry{
+			Action:     model.AuditActionFinalizeContest,
+			Actor:      systemAuditActor,
+			TargetType: model.AuditTargetContest,
+			TargetID:   contest.ID.Hex(),
+			After:      map[string]any{"participants": participants, "rated": contest.Rated},
+		})
+		s.logger.Log(zapcore.InfoLevel, traceID, "Contest finalized", map[string]any{
+			"method":       "FinalizeEndedContests",
+			"contestId":    contest.ID.Hex(),
+			"participants": participants,
+		}, "SERVICE", nil)
+	}
+	return failed
+}
+
+// finalizeContest computes the final standings of every division, rates them when the contest is rated and stores
+// the result. It returns the finalized contest and the number of participants.
+func (s *ProblemService) finalizeContest(ctx context.Context, contest model.Contest) (*model.Contest, int, error) {
+	byDivision, err := s.liveContestStandings(ctx, contest)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var standings []model.ContestStanding
+	if contest.Rated {
+		var userIDs []string
+		for _, division := range byDivision {
+			for _, standing := range division {
+				userIDs = append(userIDs, standing.UserID)
+			}
+		}
+		ratings := map[string]model.UserRating{}
+		if len(userIDs) > 0 {
+			if ratings, err = s.RepoConnInstance.GetUserRatings(ctx, userIDs); err != nil {
+				return nil, 0, err
+			}
+		}
+		for _, division := range byDivision {
+			rateContestDivision(division, ratings)
+		}
+	}
+	// contest order of divisions keeps the stored standings in a stable order
+	for _, division := range contest.Divisions {
+		standings = append(standings, byDivision[division.Name]...)
+	}
+
+	finalized, err := s.RepoConnInstance.FinalizeContest(ctx, contest.ID.Hex(), standings, contest.Rated)
+	if err != nil {
+		return nil, 0, err
+	}
+	return finalized, len(standings), nil
+}
+
+func challengeEndedEvent(contest model.Contest, participants int) model.ChallengeEndedEvent {
+	return model.ChallengeEndedEvent{
+		ChallengeID:  contest.ID.Hex(),
+		Title:        contest.Title,
+		EndsAt:       contest.EndsAt,
+		Rated:        contest.Rated,
+		Participants: participants,
+	}
+}
+
+// liveContestStandings computes the standings of every division from the submissions made during the contest
+func (s *ProblemService) liveContestStandings(ctx context.Context, contest model.Contest) (map[string][]model.ContestStanding, error) {
+	registrations, err := s.RepoConnInstance.GetContestRegistrations(ctx, contest.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if len(registrations) == 0 {
+		return map[string][]model.ContestStanding{}, nil
+	}
+	submissions, err := s.RepoConnInstance.GetContestSubmissions(ctx, contest.ProblemIDs, contest.StartsAt, contest.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	return computeContestStandings(contest, registrations, submissions), nil
+}
+
+// fetchContest loads a contest for a handler, turning failures into gRPC errors
+func (s *ProblemService) fetchContest(ctx context.Context, traceID, method, contestID string) (*model.Contest, error) {
+	if contestID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Contest ID is required", "VALIDATION_ERROR", nil)
+	}
+	contest, err := s.RepoConnInstance.GetContest(ctx, contestID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch contest", map[string]any{
+			"method":    method,
+			"contestId": contestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch contest")
+	}
+	return contest, nil
+}
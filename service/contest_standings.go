@@ -0,0 +1,171 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"xcode/model"
+)
+
+// contestRatingK scales rating changes: a participant who beats everyone they were expected to lose to gains at
+// most this much
+const contestRatingK = 32
+
+// computeContestStandings ranks, per division, the registered users who submitted during the contest. Submissions
+// must be oldest first; those by unregistered users and to other problems are ignored, and nothing after a
+// problem's first accepted submission counts. Equal solved counts and penalties share a rank.
+func computeContestStandings(contest model.Contest, registrations []model.ContestRegistration, submissions []model.Submission) map[string][]model.ContestStanding {
+	divisionOf := make(map[string]string, len(registrations))
+	for _, registration := range registrations {
+		divisionOf[registration.UserID] = registration.Division
+	}
+	problemIndex := make(map[string]int, len(contest.ProblemIDs))
+	for i, problemID := range contest.ProblemIDs {
+		problemIndex[problemID] = i
+	}
+
+	rows := map[string]*model.ContestStanding{}
+	for _, submission := range submissions {
+		division, registered := divisionOf[submission.UserID]
+		index, inContest := problemIndex[submission.ProblemID]
+		if !registered || !inContest || submission.SubmittedAt.Before(contest.StartsAt) || !submission.SubmittedAt.Before(contest.EndsAt) {
+			continue
+		}
+		row, ok := rows[submission.UserID]
+		if !ok {
+			row = &model.ContestStanding{
+				ContestID: contest.ID.Hex(),
+				Division:  division,
+				UserID:    submission.UserID,
+				Problems:  make([]model.ContestProblemResult, len(contest.ProblemIDs)),
+			}
+			for i, problemID := range contest.ProblemIDs {
+				row.Problems[i].ProblemID = problemID
+			}
+			rows[submission.UserID] = row
+		}
+
+		result := &row.Problems[index]
+		if result.Solved {
+			continue
+		}
+		if submission.Status != "SUCCESS" {
+			result.Attempts++
+			continue
+		}
+		result.Solved = true
+		result.SolvedMinutes = int(submission.SubmittedAt.Sub(contest.StartsAt) / time.Minute)
+		row.Solved++
+		row.Penalty += result.SolvedMinutes + result.Attempts*int(model.ContestWrongAttemptPenalty/time.Minute)
+	}
+
+	standings := map[string][]model.ContestStanding{}
+	for _, row := range rows {
+		standings[row.Division] = append(standings[row.Division], *row)
+	}
+	for _, division := range standings {
+		rankContestStandings(division)
+	}
+	return standings
+}
+
+// rankContestStandings sorts one division's standings best first and sets their ranks
+func rankContestStandings(division []model.ContestStanding) {
+	sort.Slice(division, func(i, j int) bool {
+		a, b := division[i], division[j]
+		if a.Solved != b.Solved {
+			return a.Solved > b.Solved
+		}
+		if a.Penalty != b.Penalty {
+			return a.Penalty < b.Penalty
+		}
+		return a.UserID < b.UserID
+	})
+	for i := range division {
+		division[i].Rank = i + 1
+		if i > 0 && division[i].Solved == division[i-1].Solved && division[i].Penalty == division[i-1].Penalty {
+			division[i].Rank = division[i-1].Rank
+		}
+	}
+}
+
+// virtualContestStandings ranks a virtual participant among the original participants of their division, elapsed
+// into the contest. history are the original submissions and own the participant's submissions since StartedAt, both
+// oldest first. The participant is always in the result, even before their first submission.
+func virtualContestStandings(contest model.Contest, participation model.VirtualParticipation, registrations []model.ContestRegistration, history, own []model.Submission, elapsed time.Duration) []model.ContestStanding {
+	contest.EndsAt = contest.StartsAt.Add(elapsed)
+
+	// anything the participant submitted during the original contest without registering is not theirs to count
+	submissions := make([]model.Submission, 0, len(history)+len(own))
+	for _, submission := range history {
+		if submission.UserID != participation.UserID {
+			submissions = append(submissions, submission)
+		}
+	}
+	offset := contest.StartsAt.Sub(participation.StartedAt)
+	for _, submission := range own {
+		submission.SubmittedAt = submission.SubmittedAt.Add(offset)
+		submissions = append(submissions, submission)
+	}
+	registrations = append(registrations[:len(registrations):len(registrations)], model.ContestRegistration{
+		ContestID: participation.ContestID,
+		UserID:    participation.UserID,
+		Division:  participation.Division,
+	})
+
+	division := computeContestStandings(contest, registrations, submissions)[participation.Division]
+	for _, standing := range division {
+		if standing.UserID == participation.UserID {
+			return division
+		}
+	}
+	participant := model.ContestStanding{
+		ContestID: participation.ContestID,
+		Division:  participation.Division,
+		UserID:    participation.UserID,
+		Problems:  make([]model.ContestProblemResult, len(contest.ProblemIDs)),
+	}
+	for i, problemID := range contest.ProblemIDs {
+		participant.Problems[i].ProblemID = problemID
+	}
+	division = append(division, participant)
+	rankContestStandings(division)
+	return division
+}
+
+// rateContestDivision sets RatingBefore and RatingAfter of one division's standings from ratings, with
+// model.DefaultContestRating for users without one. Every pair of participants counts as a game decided by rank,
+// a shared rank being a draw, and each participant moves by contestRatingK times the mean of their results minus
+// what Elo expected of them.
+func rateContestDivision(standings []model.ContestStanding, ratings map[string]model.UserRating) {
+	for i := range standings {
+		standings[i].RatingBefore = model.DefaultContestRating
+		if rating, ok := ratings[standings[i].UserID]; ok {
+			standings[i].RatingBefore = rating.Rating
+		}
+		standings[i].RatingAfter = standings[i].RatingBefore
+	}
+	if len(standings) < 2 {
+		return
+	}
+
+	for i := range standings {
+		var difference float64
+		for j := range standings {
+			if i == j {
+				continue
+			}
+			expected := 1 / (1 + math.Pow(10, float64(standings[j].RatingBefore-standings[i].RatingBefore)/400))
+			actual := 0.5
+			if standings[i].Rank < standings[j].Rank {
+				actual = 1
+			} else if standings[i].Rank > standings[j].Rank {
+				actual = 0
+			}
+			difference += actual - expected
+		}
+		change := contestRatingK * difference / float64(len(standings)-1)
+		standings[i].RatingAfter = standings[i].RatingBefore + int(math.Round(change))
+	}
+}
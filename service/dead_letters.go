@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"xcode/model"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	defaultDeadLetterPageSize = 20
+	maxDeadLetterPageSize     = 100
+)
+
+// deadLetter keeps a payload this service gave up on: it is stored in MongoDB for ListDeadLetters and ReplayDeadLetter
+// and announced on the dead-letter subject. Neither step failing stops the caller.
+func (s *ProblemService) deadLetter(ctx context.Context, traceID, subject string, data []byte, cause error, attempts int, replayable bool) {
+	letter := model.DeadLetter{
+		Subject:    subject,
+		Data:       string(data),
+		Error:      cause.Error(),
+		Attempts:   attempts,
+		FailedAt:   time.Now(),
+		Replayable: replayable,
+	}
+
+	// the caller's context may already be done, e.g. when the failure was a timeout
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	id, err := s.RepoConnInstance.SaveDeadLetter(saveCtx, letter)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to persist dead letter", map[string]any{
+			"method":    "deadLetter",
+			"subject":   subject,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+	}
+	letter.ID = id
+
+	letterBytes, err := json.Marshal(letter)
+	if err == nil {
+		err = s.NatsClient.Publish(model.DeadLetterSubject, letterBytes)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to publish dead letter", map[string]any{
+			"method":    "deadLetter",
+			"subject":   subject,
+			"errorType": "NATS_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.logger.Log(zapcore.WarnLevel, traceID, "Event dead-lettered", map[string]any{
+		"method":       "deadLetter",
+		"subject":      subject,
+		"deadLetterId": id.Hex(),
+		"attempts":     attempts,
+	}, "SERVICE", cause)
+}
+
+// deadLetterFromConsumer adapts deadLetter for the JetStream consumers
+func (s *ProblemService) deadLetterFromConsumer(subject string, data []byte, cause error, attempts int) {
+	s.deadLetter(context.Background(), uuid.New().String(), subject, data, cause, attempts, true)
+}
+
+// ListDeadLetters pages through stored dead letters, newest first
+func (s *ProblemService) ListDeadLetters(ctx context.Context, req *model.ListDeadLettersRequest) (*model.ListDeadLettersResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ListDeadLetters", map[string]any{
+		"method":  "ListDeadLetters",
+		"subject": req.Subject,
+		"page":    req.Page,
+	}, "SERVICE", nil)
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultDeadLetterPageSize
+	}
+	if pageSize > maxDeadLetterPageSize {
+		pageSize = maxDeadLetterPageSize
+	}
+
+	letters, total, err := s.RepoConnInstance.ListDeadLetters(ctx, req.Subject, (page-1)*pageSize, pageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list dead letters", map[string]any{
+			"method":    "ListDeadLetters",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to list dead letters", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Dead letters retrieved successfully", map[string]any{
+		"method": "ListDeadLetters",
+		"count":  len(letters),
+		"total":  total,
+	}, "SERVICE", nil)
+	return &model.ListDeadLettersResponse{DeadLetters: letters, Total: total}, nil
+}
+
+// ReplayDeadLetter publishes a stored payload again on its original subject, so the regular consumers retry it
+func (s *ProblemService) ReplayDeadLetter(ctx context.Context, req *model.ReplayDeadLetterRequest) (*model.ReplayDeadLetterResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ReplayDeadLetter", map[string]any{
+		"method":       "ReplayDeadLetter",
+		"deadLetterId": req.ID,
+		"adminId":      req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.ID == "" || req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing required fields", map[string]any{
+			"method":    "ReplayDeadLetter",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Dead letter ID and admin ID are required", "VALIDATION_ERROR", nil)
+	}
+
+	if !primitive.IsValidObjectID(req.ID) {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Invalid dead letter ID", "VALIDATION_ERROR", nil)
+	}
+
+	letter, err := s.RepoConnInstance.GetDeadLetter(ctx, req.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, s.createGrpcError(ctx, codes.NotFound, "Dead letter not found", "NOT_FOUND", nil)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch dead letter", map[string]any{
+			"method":       "ReplayDeadLetter",
+			"deadLetterId": req.ID,
+			"errorType":    "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch dead letter", "DB_ERROR", err)
+	}
+	if !letter.Replayable {
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Dead letter cannot be replayed", "NOT_REPLAYABLE", nil)
+	}
+
+	// submission events go back through the stream so the durable consumers pick them up; the ID keeps every
+	// replay distinct from the original for the duplicate window
+	if s.JetStream != nil && strings.HasPrefix(letter.Subject, "submission.") {
+		msgID := fmt.Sprintf("replay:%s:%d", req.ID, letter.ReplayCount+1)
+		err = s.JetStream.PublishEvent(ctx, letter.Subject, []byte(letter.Data), msgID)
+	} else {
+		err = s.NatsClient.Publish(letter.Subject, []byte(letter.Data))
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to replay dead letter", map[string]any{
+			"method":       "ReplayDeadLetter",
+			"deadLetterId": req.ID,
+			"subject":      letter.Subject,
+			"errorType":    "NATS_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Unavailable, "Failed to replay dead letter", "NATS_ERROR", err)
+	}
+
+	replayCount, err := s.RepoConnInstance.MarkDeadLetterReplayed(ctx, letter.ID, time.Now())
+	if err != nil {
+		// the event is already out again, only the bookkeeping is off
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to mark dead letter replayed", map[string]any{
+			"method":       "ReplayDeadLetter",
+			"deadLetterId": req.ID,
+			"errorType":    "DB_ERROR",
+		}, "SERVICE", err)
+		replayCount = letter.ReplayCount + 1
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Dead letter replayed successfully", map[string]any{
+		"method":       "ReplayDeadLetter",
+		"deadLetterId": req.ID,
+		"subject":      letter.Subject,
+		"adminId":      req.AdminID,
+	}, "SERVICE", nil)
+	return &model.ReplayDeadLetterResponse{ID: req.ID, Subject: letter.Subject, ReplayCount: replayCount}, nil
+}
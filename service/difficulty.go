@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// an empirical difficulty is trusted, and can flag a problem as mislabeled, once this many users attempted it
+	minAttemptersForCalibration = 20
+
+	// submission acceptance rates at or above these are estimated easy or medium, anything below is hard
+	easyAcceptanceRate   = 0.5
+	mediumAcceptanceRate = 0.3
+)
+
+// RecalibrateDifficulties recomputes the empirical difficulty of every problem from its submissions and flags the
+// problems whose authored difficulty disagrees with a trusted estimate. Problems nobody attempted yet get an
+// untrusted empty estimate.
+func (s *ProblemService) RecalibrateDifficulties(ctx context.Context) error {
+	traceID := traceIDFromContext(ctx)
+	started := time.Now()
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RecalibrateDifficulties", map[string]any{
+		"method": "RecalibrateDifficulties",
+	}, "SERVICE", nil)
+
+	problems, err := s.RepoConnInstance.GetProblemDifficulties(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem difficulties", map[string]any{
+			"method":    "RecalibrateDifficulties",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return err
+	}
+	stats, err := s.RepoConnInstance.GetProblemSolveStats(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to compute solve statistics", map[string]any{
+			"method":    "RecalibrateDifficulties",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return err
+	}
+	statsByProblem := make(map[string]model.ProblemSolveStats, len(stats))
+	for _, stat := range stats {
+		statsByProblem[stat.ProblemID] = stat
+	}
+
+	difficulties := make(map[string]model.EmpiricalDifficulty, len(problems))
+	mislabeled := 0
+	for _, problem := range problems {
+		empirical := empiricalDifficulty(problem.Difficulty, statsByProblem[problem.ProblemID], started)
+		if empirical.Mislabeled {
+			mislabeled++
+		}
+		difficulties[problem.ProblemID] = empirical
+	}
+	if err := s.RepoConnInstance.SetEmpiricalDifficulties(ctx, difficulties); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to store empirical difficulties", map[string]any{
+			"method":    "RecalibrateDifficulties",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return err
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Difficulties recalibrated", map[string]any{
+		"method":     "RecalibrateDifficulties",
+		"problems":   len(difficulties),
+		"mislabeled": mislabeled,
+		"duration":   time.Since(started).Seconds(),
+	}, "SERVICE", nil)
+	return nil
+}
+
+// empiricalDifficulty turns a problem's solve statistics into its empirical difficulty
+func empiricalDifficulty(authored string, stats model.ProblemSolveStats, now time.Time) model.EmpiricalDifficulty {
+	empirical := model.EmpiricalDifficulty{
+		Attempters: stats.Attempters,
+		Solvers:    stats.Solvers,
+		ComputedAt: now,
+	}
+	if stats.Submissions == 0 {
+		return empirical
+	}
+	empirical.AcceptanceRate = float64(stats.Accepted) / float64(stats.Submissions)
+	attempts := make([]float64, len(stats.AttemptsToAccept))
+	for i, n := range stats.AttemptsToAccept {
+		attempts[i] = float64(n)
+	}
+	empirical.MedianAttempts = median(attempts)
+	empirical.MedianTimeToAccept = median(stats.SecondsToAccept)
+
+	switch {
+	case empirical.AcceptanceRate >= easyAcceptanceRate:
+		empirical.Difficulty = "EASY"
+	case empirical.AcceptanceRate >= mediumAcceptanceRate:
+		empirical.Difficulty = "MEDIUM"
+	default:
+		empirical.Difficulty = "HARD"
+	}
+	empirical.Trusted = stats.Attempters >= minAttemptersForCalibration
+	empirical.Mislabeled = empirical.Trusted && !strings.EqualFold(authored, empirical.Difficulty)
+	return empirical
+}
+
+// median returns the median of values, 0 when there are none. values is sorted in place.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
+
+// GetProblemDifficulty returns a problem's authored difficulty next to its empirical one
+func (s *ProblemService) GetProblemDifficulty(ctx context.Context, req *model.GetProblemDifficultyRequest) (*model.GetProblemDifficultyResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	difficulty, err := s.RepoConnInstance.GetProblemDifficulty(ctx, req.ProblemID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem difficulty", map[string]any{
+			"method":    "GetProblemDifficulty",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem difficulty")
+	}
+	return &model.GetProblemDifficultyResponse{Problem: *difficulty}, nil
+}
+
+// ListMislabeledProblems lists the problems whose trusted empirical difficulty disagrees with the authored one, for
+// admins to relabel
+func (s *ProblemService) ListMislabeledProblems(ctx context.Context, req *model.ListMislabeledProblemsRequest) (*model.ListMislabeledProblemsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "ListMislabeledProblems",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	problems, err := s.RepoConnInstance.GetMislabeledProblems(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list mislabeled problems", map[string]any{
+			"method":    "ListMislabeledProblems",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list mislabeled problems")
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Mislabeled problems listed", map[string]any{
+		"method": "ListMislabeledProblems",
+		"count":  len(problems),
+	}, "SERVICE", nil)
+	return &model.ListMislabeledProblemsResponse{Problems: problems}, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	codeDraftCachePrefix = "code_draft:"
+	// codeDraftDirtySet holds the IDs of drafts saved to Redis but not yet flushed to MongoDB
+	codeDraftDirtySet  = "code_drafts:dirty"
+	codeDraftCacheTTL  = 24 * time.Hour
+	codeDraftFlushSize = 500
+)
+
+// SaveCodeDraft stores the user's editor content in Redis; FlushCodeDrafts persists it to MongoDB later
+func (s *ProblemService) SaveCodeDraft(ctx context.Context, req *model.SaveCodeDraftRequest) (*model.SaveCodeDraftResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting SaveCodeDraft", map[string]any{
+		"method":    "SaveCodeDraft",
+		"userId":    req.UserID,
+		"problemId": req.ProblemID,
+		"language":  req.Language,
+	}, "SERVICE", nil)
+
+	if err := s.checkRequest(traceID, "SaveCodeDraft", req); err != nil {
+		return nil, err
+	}
+
+	draft := model.CodeDraft{
+		ID:        model.CodeDraftID(req.UserID, req.ProblemID, req.Language),
+		UserID:    req.UserID,
+		ProblemID: req.ProblemID,
+		Language:  req.Language,
+		Code:      req.Code,
+		UpdatedAt: time.Now(),
+	}
+	draftBytes, err := json.Marshal(draft)
+	if err == nil {
+		err = s.RedisCacheClient.Set(ctx, codeDraftCachePrefix+draft.ID, draftBytes, codeDraftCacheTTL)
+	}
+	if err == nil {
+		err = s.RedisCacheClient.SAdd(ctx, codeDraftDirtySet, draft.ID)
+	}
+	if err != nil {
+		// Redis is the write path, fall back to MongoDB directly rather than losing the draft
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to cache draft, saving to MongoDB", map[string]any{
+			"method":    "SaveCodeDraft",
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+		if err := s.RepoConnInstance.SaveCodeDrafts(ctx, []model.CodeDraft{draft}); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save draft", map[string]any{
+				"method":    "SaveCodeDraft",
+				"errorType": "DB_ERROR",
+			}, "SERVICE", err)
+			return nil, s.createGrpcError(ctx, codes.Internal, "Failed to save draft", "DB_ERROR", err)
+		}
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Draft saved successfully", map[string]any{
+		"method":    "SaveCodeDraft",
+		"userId":    req.UserID,
+		"problemId": req.ProblemID,
+	}, "SERVICE", nil)
+	return &model.SaveCodeDraftResponse{UpdatedAt: draft.UpdatedAt}, nil
+}
+
+// GetCodeDraft returns the latest draft from Redis, falling back to the copy flushed to MongoDB
+func (s *ProblemService) GetCodeDraft(ctx context.Context, req *model.GetCodeDraftRequest) (*model.GetCodeDraftResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetCodeDraft", map[string]any{
+		"method":    "GetCodeDraft",
+		"userId":    req.UserID,
+		"problemId": req.ProblemID,
+		"language":  req.Language,
+	}, "SERVICE", nil)
+
+	if err := s.checkRequest(traceID, "GetCodeDraft", req); err != nil {
+		return nil, err
+	}
+
+	id := model.CodeDraftID(req.UserID, req.ProblemID, req.Language)
+	if draft, ok := s.getCachedCodeDraft(ctx, id); ok {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Draft retrieved from cache", map[string]any{
+			"method": "GetCodeDraft",
+			"userId": req.UserID,
+		}, "SERVICE", nil)
+		return &model.GetCodeDraftResponse{Draft: draft}, nil
+	}
+
+	draft, err := s.RepoConnInstance.GetCodeDraft(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &model.GetCodeDraftResponse{}, nil
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch draft", map[string]any{
+			"method":    "GetCodeDraft",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch draft", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Draft retrieved from MongoDB", map[string]any{
+		"method": "GetCodeDraft",
+		"userId": req.UserID,
+	}, "SERVICE", nil)
+	return &model.GetCodeDraftResponse{Draft: draft}, nil
+}
+
+func (s *ProblemService) getCachedCodeDraft(ctx context.Context, id string) (*model.CodeDraft, bool) {
+	cached, err := s.RedisCacheClient.Get(ctx, codeDraftCachePrefix+id)
+	if err != nil || cached == nil {
+		return nil, false
+	}
+	cachedStr, ok := cached.(string)
+	if !ok {
+		return nil, false
+	}
+	var draft model.CodeDraft
+	if err := json.Unmarshal([]byte(cachedStr), &draft); err != nil {
+		return nil, false
+	}
+	return &draft, true
+}
+
+// FlushCodeDrafts persists the drafts changed since the last flush to MongoDB
+func (s *ProblemService) FlushCodeDrafts(ctx context.Context) error {
+	traceID := traceIDFromContext(ctx)
+	flushed := 0
+	for {
+		ids, err := s.RedisCacheClient.SPopN(ctx, codeDraftDirtySet, codeDraftFlushSize)
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to read dirty drafts", map[string]any{
+				"method":    "FlushCodeDrafts",
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+			return err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		drafts := make([]model.CodeDraft, 0, len(ids))
+		for _, id := range ids {
+			if draft, ok := s.getCachedCodeDraft(ctx, id); ok {
+				drafts = append(drafts, *draft)
+			}
+		}
+		if err := s.RepoConnInstance.SaveCodeDrafts(ctx, drafts); err != nil {
+			// put them back so the next flush retries
+			members := make([]interface{}, len(ids))
+			for i, id := range ids {
+				members[i] = id
+			}
+			s.RedisCacheClient.SAdd(ctx, codeDraftDirtySet, members...)
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to flush drafts", map[string]any{
+				"method":    "FlushCodeDrafts",
+				"errorType": "DB_ERROR",
+			}, "SERVICE", err)
+			return err
+		}
+		flushed += len(drafts)
+	}
+
+	if flushed > 0 {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Drafts flushed to MongoDB", map[string]any{
+			"method": "FlushCodeDrafts",
+			"count":  flushed,
+		}, "SERVICE", nil)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"xcode/model"
+	"xcode/tracing"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const executeRequestSubject = "problems.execute.request"
+
+// errEngineUnavailable marks a request that never got an answer from the execution engine,
+// as opposed to code that ran and failed
+var errEngineUnavailable = errors.New("execution engine unavailable")
+
+// compilerContractVersion is the contract version requests are sent with: the configured one, or v1 when it is
+// unset or not one this service knows
+func (s *ProblemService) compilerContractVersion() int {
+	version := s.engine.ContractVersion
+	if version <= model.CompilerContractV1 || version > model.LatestCompilerContract {
+		return model.CompilerContractV1
+	}
+	return version
+}
+
+// newCompilerRequest builds the request for the configured contract version; limits and the language's profile, when
+// it has one, are only sent from v2 on. Limits set in the profile win over the configured ones.
+func (s *ProblemService) newCompilerRequest(code, language string, profile *model.ExecutionProfile) model.CompilerRequest {
+	request := model.CompilerRequest{Code: code, Language: language}
+	version := s.compilerContractVersion()
+	if version == model.CompilerContractV1 {
+		return request
+	}
+	request.Version = version
+	request.Limits = &model.ExecutionLimits{
+		TimeLimitMs:   s.engine.TimeLimit.Milliseconds(),
+		MemoryLimitMb: int64(s.engine.MemoryLimitMB),
+	}
+	if profile == nil {
+		return request
+	}
+	if profile.TimeLimitMs > 0 {
+		request.Limits.TimeLimitMs = profile.TimeLimitMs
+	}
+	if profile.MemoryLimitMb > 0 {
+		request.Limits.MemoryLimitMb = profile.MemoryLimitMb
+	}
+	if profile.RuntimeVersion != "" || len(profile.CompilerFlags) > 0 {
+		request.Runtime = &model.RuntimeOptions{Version: profile.RuntimeVersion, CompilerFlags: profile.CompilerFlags}
+	}
+	return request
+}
+
+// userCodeFailure reports whether the engine ran the user's code and it failed to compile, crashed or hit a limit,
+// which is stored as a failed submission. v1 engines only report compile errors, inside the output text.
+func userCodeFailure(result *model.CompilerResponse) (errorType, message string, ok bool) {
+	if result.ContractVersion() == model.CompilerContractV1 {
+		if strings.Contains(result.Output, "syntax error") || strings.Contains(result.Output, "# command-line-arguments") {
+			return model.CompilerErrorCompilation, result.Output, true
+		}
+		return "", "", false
+	}
+	if result.Error == nil {
+		return "", "", false
+	}
+	switch result.Error.Code {
+	case model.CompilerErrorCompilation, model.CompilerErrorRuntime, model.CompilerErrorTimeLimit, model.CompilerErrorMemoryLimit:
+		return result.Error.Code, result.Error.Message, true
+	}
+	return "", "", false
+}
+
+// requestExecution sends code to the execution engine, retrying transport failures (no responders, timeouts,
+// a reconnecting connection) with jittered exponential backoff. Each attempt gets the configured timeout or
+// what is left of the caller's deadline split across the remaining attempts, whichever is shorter.
+func (s *ProblemService) requestExecution(ctx context.Context, traceID string, payload []byte) (msg *nats.Msg, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "engine.execute", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("messaging.destination.name", executeRequestSubject)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	attempts := s.engine.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := s.engine.Backoff
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		// the slot is held per attempt, so requests waiting out a backoff do not block others
+		if err := s.engineDispatcher.acquire(ctx); err != nil {
+			return nil, fmt.Errorf("%w: %v", errEngineUnavailable, err)
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, s.engineAttemptTimeout(ctx, attempts-attempt+1))
+		start := time.Now()
+		msg, err := s.NatsClient.RequestWithContext(attemptCtx, executeRequestSubject, payload)
+		engineRequestDuration.ObserveDuration(start, engineOutcome(err))
+		cancel()
+		s.engineDispatcher.release()
+		if err == nil {
+			return msg, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil || !isRetryableEngineError(err) {
+			break
+		}
+		s.logger.Log(zapcore.WarnLevel, traceID, "Execution request failed, retrying", map[string]any{
+			"method":    "requestExecution",
+			"attempt":   attempt,
+			"attempts":  attempts,
+			"errorType": "ENGINE_UNAVAILABLE",
+		}, "SERVICE", err)
+		if attempt == attempts {
+			break
+		}
+
+		wait := backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", errEngineUnavailable, ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("%w: %v", errEngineUnavailable, lastErr)
+}
+
+func (s *ProblemService) engineAttemptTimeout(ctx context.Context, remainingAttempts int) time.Duration {
+	timeout := s.engine.AttemptTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if share := time.Until(deadline) / time.Duration(remainingAttempts); share < timeout {
+			timeout = share
+		}
+	}
+	return timeout
+}
+
+func isRetryableEngineError(err error) bool {
+	return errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, nats.ErrTimeout) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, nats.ErrConnectionReconnecting) ||
+		errors.Is(err, nats.ErrConnectionClosed)
+}
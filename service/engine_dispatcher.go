@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/metrics"
+)
+
+const defaultEngineMaxConcurrency = 32
+
+var (
+	engineQueueDepth      = metrics.NewGauge("engine_queue_depth")
+	engineInFlight        = metrics.NewGauge("engine_requests_in_flight")
+	engineQueueWaitMicros = metrics.NewCounter("engine_queue_wait_microseconds_sum") // divide by engine_requests_total for the mean
+	engineRequestsTotal   = metrics.NewCounter("engine_requests_total")
+)
+
+// executionDispatcher caps how many execution requests are in flight, so a burst of submissions waits here
+// instead of opening hundreds of concurrent NATS requests against the engine
+type executionDispatcher struct {
+	slots chan struct{}
+}
+
+func newExecutionDispatcher(maxConcurrency int) *executionDispatcher {
+	if maxConcurrency < 1 {
+		maxConcurrency = defaultEngineMaxConcurrency
+	}
+	return &executionDispatcher{slots: make(chan struct{}, maxConcurrency)}
+}
+
+// acquire blocks until a slot is free or ctx ends; every successful acquire must be paired with release
+func (d *executionDispatcher) acquire(ctx context.Context) error {
+	start := time.Now()
+	engineQueueDepth.Add(1)
+	defer engineQueueDepth.Add(-1)
+
+	select {
+	case d.slots <- struct{}{}:
+		engineQueueWaitMicros.Add(time.Since(start).Microseconds())
+		engineRequestsTotal.Add(1)
+		engineInFlight.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *executionDispatcher) release() {
+	engineInFlight.Add(-1)
+	<-d.slots
+}
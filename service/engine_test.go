@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"xcode/model"
+)
+
+// golden reads a file of testdata/compiler, the contract as the execution engine sees it
+func golden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "compiler", name))
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	return data
+}
+
+func TestNewCompilerRequestGolden(t *testing.T) {
+	profile := &model.ExecutionProfile{Language: "python", RuntimeVersion: "3.12", CompilerFlags: []string{"-O"}, TimeLimitMs: 5000}
+	tests := []struct {
+		name    string
+		version int
+		profile *model.ExecutionProfile
+		golden  string
+	}{
+		{name: "v1 sends code and language only", version: model.CompilerContractV1, profile: profile, golden: "request_v1.json"},
+		{name: "unset version is v1", version: 0, golden: "request_v1.json"},
+		{name: "unknown version falls back to v1", version: model.LatestCompilerContract + 1, profile: profile, golden: "request_v1.json"},
+		{name: "v2 sends the configured limits", version: model.CompilerContractV2, golden: "request_v2.json"},
+		{name: "v2 profile overrides limits and sets the runtime", version: model.CompilerContractV2, profile: profile, golden: "request_v2_profile.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, nil)
+			s.engine.ContractVersion = tt.version
+			s.engine.TimeLimit = 3 * time.Second
+			s.engine.MemoryLimitMB = 128
+
+			got, err := json.Marshal(s.newCompilerRequest("print(1)", "python", tt.profile))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var want bytes.Buffer
+			if err := json.Compact(&want, golden(t, tt.golden)); err != nil {
+				t.Fatalf("golden file is not JSON: %v", err)
+			}
+			if !bytes.Equal(got, want.Bytes()) {
+				t.Errorf("request =\n%s\nwant\n%s", got, want.Bytes())
+			}
+		})
+	}
+}
+
+func TestUserCodeFailureGolden(t *testing.T) {
+	tests := []struct {
+		golden        string
+		wantErrorType string
+		wantMessage   string
+		wantFailure   bool
+	}{
+		{golden: "response_v1.json"},
+		{
+			golden:        "response_v1_compile_error.json",
+			wantErrorType: model.CompilerErrorCompilation,
+			wantMessage:   "# command-line-arguments\n./main.go:4:2: syntax error: unexpected }",
+			wantFailure:   true,
+		},
+		{golden: "response_v2_success.json"},
+		{
+			golden:        "response_v2_runtime_error.json",
+			wantErrorType: model.CompilerErrorRuntime,
+			wantMessage:   "panic: runtime error: index out of range [3] with length 3",
+			wantFailure:   true,
+		},
+		{
+			golden:        "response_v2_time_limit.json",
+			wantErrorType: model.CompilerErrorTimeLimit,
+			wantMessage:   "time limit of 3000ms exceeded",
+			wantFailure:   true,
+		},
+		// the engine failing is not the user's code failing, nothing is stored for it
+		{golden: "response_v2_engine_error.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var result model.CompilerResponse
+			if err := json.Unmarshal(golden(t, tt.golden), &result); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if err := result.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			errorType, message, ok := userCodeFailure(&result)
+			if ok != tt.wantFailure || errorType != tt.wantErrorType || message != tt.wantMessage {
+				t.Errorf("userCodeFailure = %q, %q, %v; want %q, %q, %v", errorType, message, ok, tt.wantErrorType, tt.wantMessage, tt.wantFailure)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	entityStatsCacheKey    = "entity_stats"
+	entityMoversWindowDays = 7
+	entityMoversPerEntity  = 5
+)
+
+// GetEntityStats returns participation and score statistics per entity (country), computed for all entities and cached together
+func (s *ProblemService) GetEntityStats(ctx context.Context, req *model.GetEntityStatsRequest) (*model.GetEntityStatsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	entity := ""
+	if req.Entity != nil {
+		entity = entityFilter(*req.Entity)
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetEntityStats", map[string]any{
+		"method": "GetEntityStats",
+		"entity": entity,
+	}, "SERVICE", nil)
+
+	var stats []model.EntityStats
+	cached, err := s.RedisCacheClient.Get(ctx, entityStatsCacheKey)
+	if err == nil && cached != nil {
+		if cachedStr, ok := cached.(string); ok && json.Unmarshal([]byte(cachedStr), &stats) == nil {
+			s.logger.Log(zapcore.InfoLevel, traceID, "Cache hit for entity stats", map[string]any{
+				"method": "GetEntityStats",
+			}, "SERVICE", nil)
+		} else {
+			stats = nil
+		}
+	}
+
+	if stats == nil {
+		moversSince := time.Now().UTC().AddDate(0, 0, -entityMoversWindowDays)
+		stats, err = s.RepoConnInstance.GetEntityStatsMongo(ctx, moversSince, entityMoversPerEntity)
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch entity stats from MongoDB", map[string]any{
+				"method":    "GetEntityStats",
+				"errorType": "DB_ERROR",
+			}, "SERVICE", err)
+			return nil, err
+		}
+
+		if statsBytes, err := json.Marshal(stats); err == nil {
+			if err := s.RedisCacheClient.Set(ctx, entityStatsCacheKey, statsBytes, s.cacheTTLs().Leaderboard); err != nil {
+				s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache entity stats", map[string]any{
+					"method":    "GetEntityStats",
+					"cacheKey":  entityStatsCacheKey,
+					"errorType": "CACHE_ERROR",
+				}, "SERVICE", err)
+			}
+		}
+	}
+
+	if entity != "" {
+		filtered := []model.EntityStats{}
+		for _, stat := range stats {
+			if stat.Entity == entity {
+				filtered = append(filtered, stat)
+				break
+			}
+		}
+		stats = filtered
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Entity stats retrieved successfully", map[string]any{
+		"method":      "GetEntityStats",
+		"entityCount": len(stats),
+	}, "SERVICE", nil)
+	return &model.GetEntityStatsResponse{
+		Entities:         stats,
+		MoversWindowDays: entityMoversWindowDays,
+	}, nil
+}
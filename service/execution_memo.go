@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// executionResultCachePrefix keys the engine's replies to earlier runs, see memoizeExecutionResult
+const executionResultCachePrefix = "execution_result:"
+
+// executionResultKey identifies a run by a hash of its compiler request, which holds the user's code, the template
+// filled with the test cases and the limits; changing any of them makes it a different run
+func executionResultKey(problemID, language string, compilerRequest []byte) string {
+	sum := sha256.Sum256(compilerRequest)
+	return executionResultCachePrefix + problemID + ":" + language + ":" + hex.EncodeToString(sum[:])
+}
+
+// cachedExecutionResult returns the engine's reply to an identical earlier run, or nil when there is none or
+// memoization is off (CacheTTL.ExecutionResult 0). A cache error only costs an engine round trip.
+func (s *ProblemService) cachedExecutionResult(ctx context.Context, traceID, key string) []byte {
+	if s.cacheTTLs().ExecutionResult <= 0 {
+		return nil
+	}
+	cached, err := s.RedisCacheClient.Get(ctx, key)
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to read memoized execution result", map[string]any{
+			"method":    "cachedExecutionResult",
+			"cacheKey":  key,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+		return nil
+	}
+	if data, ok := cached.(string); ok && data != "" {
+		return []byte(data)
+	}
+	return nil
+}
+
+// memoizeExecutionResult keeps the engine's reply so a byte-identical resubmission, e.g. a retry from the UI, skips
+// the engine. Only verdicts on the user's code are kept: engine failures and time limits may come out differently
+// when run again.
+func (s *ProblemService) memoizeExecutionResult(ctx context.Context, traceID, key string, data []byte, result *model.CompilerResponse) {
+	ttl := s.cacheTTLs().ExecutionResult
+	if ttl <= 0 {
+		return
+	}
+	if errorType, _, ok := userCodeFailure(result); ok {
+		if errorType == model.CompilerErrorTimeLimit {
+			return
+		}
+	} else if result.Error != nil {
+		return
+	}
+
+	if err := s.RedisCacheClient.Set(ctx, key, data, ttl); err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to memoize execution result", map[string]any{
+			"method":    "memoizeExecutionResult",
+			"cacheKey":  key,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+}
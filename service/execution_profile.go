@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/validation"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// executionProfilesCacheKey holds every language's profile, as read for each run
+const executionProfilesCacheKey = "execution_profiles"
+
+// executionProfiles returns the profile of every language that has one. Failing to load them is logged and treated
+// as no profiles, so runs use the engine's defaults rather than fail.
+func (s *ProblemService) executionProfiles(ctx context.Context, traceID string) []model.ExecutionProfile {
+	profiles, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, executionProfilesCacheKey, s.cacheTTLs().Problem, func(ctx context.Context) ([]model.ExecutionProfile, error) {
+		return s.RepoConnInstance.GetExecutionProfiles(ctx)
+	})
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to load execution profiles, using engine defaults", map[string]any{
+			"method":    "executionProfiles",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil
+	}
+	return profiles
+}
+
+// executionProfile returns the profile the engine runs language with, nil when it has none
+func (s *ProblemService) executionProfile(ctx context.Context, traceID, language string) *model.ExecutionProfile {
+	profiles := s.executionProfiles(ctx, traceID)
+	for i := range profiles {
+		if profiles[i].Language == language {
+			return &profiles[i]
+		}
+	}
+	return nil
+}
+
+func (s *ProblemService) deleteExecutionProfilesCache(ctx context.Context, traceID, method string) {
+	if err := s.RedisCacheClient.Delete(ctx, executionProfilesCacheKey); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    method,
+			"cacheKey":  executionProfilesCacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+}
+
+// SetExecutionProfile sets the runtime version, compiler flags and limits a language is run with. Runs already
+// memoized keep their verdict only until the profile change makes their compiler request differ. Profiles are only
+// sent from compiler contract v2 on, so under v1 nothing is stored and FailedPrecondition is returned.
+func (s *ProblemService) SetExecutionProfile(ctx context.Context, req *model.SetExecutionProfileRequest) (*model.SetExecutionProfileResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting SetExecutionProfile", map[string]any{
+		"method":   "SetExecutionProfile",
+		"language": req.Profile.Language,
+		"adminId":  req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "SetExecutionProfile",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	if version := s.compilerContractVersion(); version < model.CompilerContractV2 {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Execution profile set while the engine contract ignores profiles", map[string]any{
+			"method":          "SetExecutionProfile",
+			"contractVersion": version,
+			"errorType":       "CONTRACT_VERSION_UNSUPPORTED",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Execution profiles need compiler contract v2, set ENGINECONTRACTVERSION=2", "CONTRACT_VERSION_UNSUPPORTED", nil)
+	}
+	profile := req.Profile
+	profile.Language = strings.TrimSpace(profile.Language)
+	if !slices.Contains(validation.Languages, profile.Language) {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Language must be one of "+strings.Join(validation.Languages, ", "), "VALIDATION_ERROR", nil)
+	}
+	if err := profile.Validate(); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid execution profile", map[string]any{
+			"method":    "SetExecutionProfile",
+			"language":  profile.Language,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, err.Error(), "VALIDATION_ERROR", err)
+	}
+
+	profile.UpdatedBy = req.AdminID
+	profile.UpdatedAt = time.Now()
+	previous, err := s.RepoConnInstance.SetExecutionProfile(ctx, profile)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to set execution profile", map[string]any{
+			"method":    "SetExecutionProfile",
+			"language":  profile.Language,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to set execution profile")
+	}
+	s.deleteExecutionProfilesCache(ctx, traceID, "SetExecutionProfile")
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionSetExecutionProfile,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetProfile,
+		TargetID:   profile.Language,
+		Before:     executionProfileAuditSummary(previous),
+		After:      executionProfileAuditSummary(&profile),
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Execution profile set", map[string]any{
+		"method":         "SetExecutionProfile",
+		"language":       profile.Language,
+		"runtimeVersion": profile.RuntimeVersion,
+	}, "SERVICE", nil)
+	return &model.SetExecutionProfileResponse{Profile: profile}, nil
+}
+
+// ListExecutionProfiles returns the profile of every language that has one
+func (s *ProblemService) ListExecutionProfiles(ctx context.Context, req *model.ListExecutionProfilesRequest) (*model.ListExecutionProfilesResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	profiles, err := s.RepoConnInstance.GetExecutionProfiles(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch execution profiles", map[string]any{
+			"method":    "ListExecutionProfiles",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch execution profiles")
+	}
+	return &model.ListExecutionProfilesResponse{Profiles: profiles}, nil
+}
+
+// DeleteExecutionProfile makes the engine run a language with its own defaults again
+func (s *ProblemService) DeleteExecutionProfile(ctx context.Context, req *model.DeleteExecutionProfileRequest) (*model.DeleteExecutionProfileResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting DeleteExecutionProfile", map[string]any{
+		"method":   "DeleteExecutionProfile",
+		"language": req.Language,
+		"adminId":  req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "DeleteExecutionProfile",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.Language == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Language is required", "VALIDATION_ERROR", nil)
+	}
+
+	deleted, err := s.RepoConnInstance.DeleteExecutionProfile(ctx, req.Language)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete execution profile", map[string]any{
+			"method":    "DeleteExecutionProfile",
+			"language":  req.Language,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to delete execution profile")
+	}
+	if deleted == nil {
+		return &model.DeleteExecutionProfileResponse{Language: req.Language}, nil
+	}
+	s.deleteExecutionProfilesCache(ctx, traceID, "DeleteExecutionProfile")
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionDeleteExecProfile,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetProfile,
+		TargetID:   req.Language,
+		Before:     executionProfileAuditSummary(deleted),
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Execution profile deleted", map[string]any{
+		"method":   "DeleteExecutionProfile",
+		"language": req.Language,
+	}, "SERVICE", nil)
+	return &model.DeleteExecutionProfileResponse{Language: req.Language, Deleted: true}, nil
+}
+
+func executionProfileAuditSummary(profile *model.ExecutionProfile) map[string]any {
+	if profile == nil {
+		return nil
+	}
+	return map[string]any{
+		"runtimeVersion": profile.RuntimeVersion,
+		"compilerFlags":  profile.CompilerFlags,
+		"timeLimitMs":    profile.TimeLimitMs,
+		"memoryLimitMb":  profile.MemoryLimitMb,
+	}
+}
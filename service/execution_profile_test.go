@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetExecutionProfile(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		req      model.SetExecutionProfileRequest
+		version  int // compiler contract version, v2 when unset
+		expect   func(repo *mocks.MockProblemRepository)
+		wantCode codes.Code
+	}{
+		{
+			name:     "missing admin",
+			req:      model.SetExecutionProfileRequest{Profile: model.ExecutionProfile{Language: "go"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			// the v1 contract sends no profile, so storing one would silently change nothing
+			name:     "contract v1 ignores profiles",
+			req:      model.SetExecutionProfileRequest{AdminID: "admin", Profile: model.ExecutionProfile{Language: "go"}},
+			version:  model.CompilerContractV1,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "unsupported language",
+			req:      model.SetExecutionProfileRequest{AdminID: "admin", Profile: model.ExecutionProfile{Language: "cobol"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "disallowed compiler flag",
+			req: model.SetExecutionProfileRequest{AdminID: "admin", Profile: model.ExecutionProfile{
+				Language:      "go",
+				CompilerFlags: []string{"; rm -rf /"},
+			}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "repository failure is not leaked",
+			req:  model.SetExecutionProfileRequest{AdminID: "admin", Profile: model.ExecutionProfile{Language: "go"}},
+			expect: func(repo *mocks.MockProblemRepository) {
+				repo.EXPECT().SetExecutionProfile(gomock.Any(), gomock.Any()).Return(nil, customerrors.Downstream("mongodb", errors.New("server selection timeout")))
+			},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name: "stored, audited and the cached profiles dropped",
+			req:  model.SetExecutionProfileRequest{AdminID: "admin", Profile: model.ExecutionProfile{Language: " go ", TimeLimitMs: 2000}},
+			expect: func(repo *mocks.MockProblemRepository) {
+				repo.EXPECT().SetExecutionProfile(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, profile model.ExecutionProfile) (*model.ExecutionProfile, error) {
+						if profile.Language != "go" || profile.UpdatedBy != "admin" {
+							t.Errorf("stored profile = %+v, want go updated by admin", profile)
+						}
+						return nil, nil
+					})
+				repo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry model.AuditEntry) error {
+					if entry.Action != model.AuditActionSetExecutionProfile || entry.TargetID != "go" {
+						t.Errorf("audit entry = %+v", entry)
+					}
+					return nil
+				})
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+			if tt.expect != nil {
+				tt.expect(repo)
+			}
+			s := newTestService(t, repo)
+			s.engine.ContractVersion = model.CompilerContractV2
+			if tt.version != 0 {
+				s.engine.ContractVersion = tt.version
+			}
+			s.RedisCacheClient.Set(ctx, executionProfilesCacheKey, "[]", 0)
+
+			_, err := s.SetExecutionProfile(ctx, &tt.req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v (%v), want %v", got, err, tt.wantCode)
+			}
+			if err != nil {
+				if msg := status.Convert(err).Message(); tt.wantCode == codes.Unavailable && msg != "Failed to set execution profile" {
+					t.Errorf("message = %q, want the public fallback", msg)
+				}
+				return
+			}
+			if cached, _ := s.RedisCacheClient.Get(ctx, executionProfilesCacheKey); cached != nil {
+				t.Errorf("execution profiles still cached as %v", cached)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	configs "xcode/config"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SetFeatureFlags sets the environment and the flags GetFeatureFlags reports; until it is called every flag is off.
+// ApplyReloadedConfig replaces the flags when they change.
+func (s *ProblemService) SetFeatureFlags(environment string, flags configs.FeatureFlags) {
+	s.environment = environment
+	s.features.Store(&flags)
+}
+
+// featureFlags returns the flags currently in effect
+func (s *ProblemService) featureFlags() configs.FeatureFlags {
+	if flags := s.features.Load(); flags != nil {
+		return *flags
+	}
+	return configs.FeatureFlags{}
+}
+
+// GetFeatureFlags reports the feature flags of the running replica. It needs no admin ID: the frontend calls it to
+// decide which features to show.
+func (s *ProblemService) GetFeatureFlags(ctx context.Context, req *model.GetFeatureFlagsRequest) (*model.GetFeatureFlagsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	flags := s.featureFlags().Map()
+	s.logger.Log(zapcore.DebugLevel, traceID, "Reporting feature flags", map[string]any{
+		"method":      "GetFeatureFlags",
+		"environment": s.environment,
+		"flags":       flags,
+	}, "SERVICE", nil)
+	return &model.GetFeatureFlagsResponse{Environment: s.environment, Flags: flags}, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// maxHiddenProblems keeps a user's hidden set small enough to send along with every list query
+const maxHiddenProblems = 1000
+
+func hiddenProblemsCacheKey(userID string) string {
+	return "hidden_problems:" + userID
+}
+
+// HideProblemForUser stops showing a problem to a user in ListProblems and GetRandomProblem. The problem itself
+// stays reachable by ID or slug.
+func (s *ProblemService) HideProblemForUser(ctx context.Context, req *model.HideProblemForUserRequest) (*model.HideProblemForUserResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting HideProblemForUser", map[string]any{
+		"method":    "HideProblemForUser",
+		"userId":    req.UserID,
+		"problemId": req.ProblemID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" || req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID and problem ID are required", "VALIDATION_ERROR", nil)
+	}
+	if _, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID}); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "HideProblemForUser",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+
+	hidden, err := s.hiddenProblemIDs(ctx, req.UserID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to load hidden problems", map[string]any{
+			"method":    "HideProblemForUser",
+			"userId":    req.UserID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to load hidden problems")
+	}
+	if slices.Contains(hidden, req.ProblemID) {
+		return &model.HideProblemForUserResponse{ProblemID: req.ProblemID, Hidden: false}, nil
+	}
+	if len(hidden) >= maxHiddenProblems {
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, fmt.Sprintf("At most %d problems can be hidden, unhide some first", maxHiddenProblems), "HIDDEN_LIMIT_REACHED", nil)
+	}
+
+	added, err := s.RepoConnInstance.HideProblem(ctx, req.UserID, req.ProblemID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to hide problem", map[string]any{
+			"method":    "HideProblemForUser",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to hide problem")
+	}
+	s.invalidateHiddenProblems(ctx, traceID, "HideProblemForUser", req.UserID)
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem hidden", map[string]any{
+		"method":    "HideProblemForUser",
+		"problemId": req.ProblemID,
+		"hidden":    added,
+	}, "SERVICE", nil)
+	return &model.HideProblemForUserResponse{ProblemID: req.ProblemID, Hidden: added}, nil
+}
+
+// UnhideProblem shows a hidden problem to the user again
+func (s *ProblemService) UnhideProblem(ctx context.Context, req *model.UnhideProblemRequest) (*model.UnhideProblemResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting UnhideProblem", map[string]any{
+		"method":    "UnhideProblem",
+		"userId":    req.UserID,
+		"problemId": req.ProblemID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" || req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID and problem ID are required", "VALIDATION_ERROR", nil)
+	}
+	removed, err := s.RepoConnInstance.UnhideProblem(ctx, req.UserID, req.ProblemID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to unhide problem", map[string]any{
+			"method":    "UnhideProblem",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to unhide problem")
+	}
+	if removed {
+		s.invalidateHiddenProblems(ctx, traceID, "UnhideProblem", req.UserID)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem unhidden", map[string]any{
+		"method":    "UnhideProblem",
+		"problemId": req.ProblemID,
+		"unhidden":  removed,
+	}, "SERVICE", nil)
+	return &model.UnhideProblemResponse{ProblemID: req.ProblemID, Unhidden: removed}, nil
+}
+
+// hiddenProblemIDs returns the problems userID hid, cached for as long as their statistics are
+func (s *ProblemService) hiddenProblemIDs(ctx context.Context, userID string) ([]string, error) {
+	hidden, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, hiddenProblemsCacheKey(userID), s.cacheTTLs().Stats, func(ctx context.Context) ([]string, error) {
+		return s.RepoConnInstance.GetHiddenProblemIDs(ctx, userID)
+	})
+	return hidden, err
+}
+
+// hiddenProblemsFor is hiddenProblemIDs for the lists that leave hidden problems out. Hiding is only a preference,
+// so when the set cannot be loaded nothing is left out rather than failing the list.
+func (s *ProblemService) hiddenProblemsFor(ctx context.Context, traceID, method, userID string) []string {
+	if userID == "" {
+		return nil
+	}
+	hidden, err := s.hiddenProblemIDs(ctx, userID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to load hidden problems, showing them all", map[string]any{
+			"method":    method,
+			"userId":    userID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil
+	}
+	return hidden
+}
+
+func (s *ProblemService) invalidateHiddenProblems(ctx context.Context, traceID, method, userID string) {
+	cacheKey := hiddenProblemsCacheKey(userID)
+	if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    method,
+			"cacheKey":  cacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// idempotencyKeyHeader is read from gRPC metadata since the request messages have no field for it
+	idempotencyKeyHeader = "idempotency-key"
+	// idempotencyTTL is how long the response of a finished request is replayed
+	idempotencyTTL = 10 * time.Minute
+	// idempotencyPendingTTL is how long a claim outlives a process that died while running its request; the
+	// claim of a running request is renewed every idempotencyRefreshInterval
+	idempotencyPendingTTL = 30 * time.Second
+)
+
+var idempotencyRefreshInterval = idempotencyPendingTTL / 3 // a var so tests can shorten it
+
+// idempotencyRecord is stored under an idempotency key: the hash of the request that claimed it and, once that
+// request finished, its response
+type idempotencyRecord struct {
+	RequestHash string `json:"requestHash"`
+	// Response is the protojson of the *pb.RunProblemResponse, empty while the request is running. encoding/json
+	// would lose oneofs and well-known types.
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// runRequestHash identifies the content of a run request, so a key reused for a different request is told apart
+// from a retry
+func runRequestHash(req *pb.RunProblemRequest) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(idempotencyKeyHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// withRunIdempotency runs handler at most once per (user, idempotency key): the first call claims the key with SETNX,
+// storing the hash of its request, and replays of that request get the stored response. The claim expires after
+// idempotencyPendingTTL unless renewed, which it is while handler runs. A replay that arrives while
+// the first call is still running is rejected with Aborted, and a different request sent with a used key with
+// FailedPrecondition. Requests without a key, or anonymous ones, run as before.
+func (s *ProblemService) withRunIdempotency(ctx context.Context, req *pb.RunProblemRequest, handler func(context.Context, *pb.RunProblemRequest) (*pb.RunProblemResponse, error)) (*pb.RunProblemResponse, error) {
+	key := idempotencyKeyFromContext(ctx)
+	if key == "" || req.UserId == "" {
+		return handler(ctx, req)
+	}
+
+	traceID := traceIDFromContext(ctx)
+	cacheKey := fmt.Sprintf("idempotency:run:%s:%s", req.UserId, key)
+	requestHash, err := runRequestHash(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash request for idempotency: %w", err)
+	}
+	pending, err := json.Marshal(idempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize idempotency record: %w", err)
+	}
+	claimed, err := s.RedisCacheClient.SetNX(ctx, cacheKey, pending, idempotencyPendingTTL)
+	if err != nil {
+		// without Redis we can't dedupe; running the request beats failing it
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to claim idempotency key", map[string]any{
+			"method":    "withRunIdempotency",
+			"cacheKey":  cacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+		return handler(ctx, req)
+	}
+
+	if !claimed {
+		var record idempotencyRecord
+		cached, err := s.RedisCacheClient.Get(ctx, cacheKey)
+		cachedStr, _ := cached.(string)
+		if err == nil && cachedStr != "" && json.Unmarshal([]byte(cachedStr), &record) == nil {
+			if record.RequestHash != requestHash {
+				s.logger.Log(zapcore.WarnLevel, traceID, "Idempotency key reused for a different request", map[string]any{
+					"method":    "withRunIdempotency",
+					"problemId": req.ProblemId,
+					"userId":    req.UserId,
+					"errorType": "IDEMPOTENCY_KEY_REUSED",
+				}, "SERVICE", nil)
+				return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "This idempotency key was already used for a different request", "IDEMPOTENCY_KEY_REUSED", nil)
+			}
+			if len(record.Response) > 0 {
+				var resp pb.RunProblemResponse
+				if err := protojson.Unmarshal(record.Response, &resp); err != nil {
+					s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to decode idempotent response", map[string]any{
+						"method":    "withRunIdempotency",
+						"cacheKey":  cacheKey,
+						"errorType": "CACHE_ERROR",
+					}, "SERVICE", err)
+					return nil, s.createGrpcError(ctx, codes.Internal, "Failed to replay the response for this idempotency key", "CACHE_ERROR", err)
+				}
+				s.logger.Log(zapcore.InfoLevel, traceID, "Replaying response for idempotency key", map[string]any{
+					"method":    "withRunIdempotency",
+					"problemId": req.ProblemId,
+					"userId":    req.UserId,
+				}, "SERVICE", nil)
+				return &resp, nil
+			}
+		}
+		s.logger.Log(zapcore.WarnLevel, traceID, "Request with this idempotency key is still running", map[string]any{
+			"method":    "withRunIdempotency",
+			"problemId": req.ProblemId,
+			"userId":    req.UserId,
+			"errorType": "DUPLICATE_REQUEST",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.Aborted, "A request with this idempotency key is still in progress", "DUPLICATE_REQUEST", nil)
+	}
+
+	stopRefresh := s.keepIdempotencyClaim(ctx, traceID, cacheKey, pending)
+	resp, err := handler(ctx, req)
+	stopRefresh()
+	if err != nil {
+		// release the key so the client can retry a request that never produced a result
+		if delErr := s.RedisCacheClient.Delete(ctx, cacheKey); delErr != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to release idempotency key", map[string]any{
+				"method":    "withRunIdempotency",
+				"cacheKey":  cacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", delErr)
+		}
+		return nil, err
+	}
+
+	encoded, err := protojson.Marshal(resp)
+	var record []byte
+	if err == nil {
+		record, err = json.Marshal(idempotencyRecord{RequestHash: requestHash, Response: encoded})
+	}
+	if err == nil {
+		err = s.RedisCacheClient.Set(ctx, cacheKey, record, idempotencyTTL)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to store idempotent response", map[string]any{
+			"method":    "withRunIdempotency",
+			"cacheKey":  cacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+	return resp, nil
+}
+
+// keepIdempotencyClaim renews the pending record under cacheKey until the returned stop is called, so a request
+// running longer than idempotencyPendingTTL keeps its key while a crashed one frees it soon. stop returns once no
+// renewal can overwrite what the caller stores next.
+func (s *ProblemService) keepIdempotencyClaim(ctx context.Context, traceID, cacheKey string, pending []byte) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(idempotencyRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.RedisCacheClient.Set(ctx, cacheKey, pending, idempotencyPendingTTL); err != nil {
+					s.logger.Log(zapcore.WarnLevel, traceID, "Failed to renew idempotency key", map[string]any{
+						"method":    "keepIdempotencyClaim",
+						"cacheKey":  cacheKey,
+						"errorType": "CACHE_ERROR",
+					}, "SERVICE", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWithRunIdempotency(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "key-1"))
+	first := &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1", Language: "go", UserCode: "func f() {}"}
+	changed := &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1", Language: "go", UserCode: "func f() { panic(1) }"}
+
+	tests := []struct {
+		name      string
+		replay    *pb.RunProblemRequest
+		wantCode  codes.Code
+		wantRuns  int
+		wantReply bool
+	}{
+		{name: "same request replays the response", replay: first, wantCode: codes.OK, wantRuns: 1, wantReply: true},
+		{name: "different request is refused", replay: changed, wantCode: codes.FailedPrecondition, wantRuns: 1},
+		{name: "other problem is refused", replay: &pb.RunProblemRequest{UserId: "u1", ProblemId: "p2", Language: "go", UserCode: "func f() {}"}, wantCode: codes.FailedPrecondition, wantRuns: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, nil)
+			runs := 0
+			handler := func(_ context.Context, req *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+				runs++
+				return &pb.RunProblemResponse{Success: true, ProblemId: req.ProblemId, Message: "ran"}, nil
+			}
+
+			if _, err := s.withRunIdempotency(ctx, first, handler); err != nil {
+				t.Fatalf("first request: %v", err)
+			}
+			resp, err := s.withRunIdempotency(ctx, tt.replay, handler)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("replay err = %v, want %v", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.FailedPrecondition {
+				if reason := errorInfo(t, err).Reason; reason != "IDEMPOTENCY_KEY_REUSED" {
+					t.Errorf("reason = %q, want IDEMPOTENCY_KEY_REUSED", reason)
+				}
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("handler ran %d times, want %d", runs, tt.wantRuns)
+			}
+			if tt.wantReply && (resp == nil || resp.Message != "ran" || !resp.Success) {
+				t.Errorf("replayed response = %v, want the stored one", resp)
+			}
+		})
+	}
+}
+
+func TestWithRunIdempotencyInProgress(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "key-1"))
+	req := &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1", Language: "go", UserCode: "func f() {}"}
+	changed := &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1", Language: "python", UserCode: "def f(): pass"}
+	s := newTestService(t, nil)
+
+	var replay, reuse error
+	_, err := s.withRunIdempotency(ctx, req, func(ctx context.Context, _ *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+		_, replay = s.withRunIdempotency(ctx, req, nil)
+		_, reuse = s.withRunIdempotency(ctx, changed, nil)
+		return &pb.RunProblemResponse{Success: true}, nil
+	})
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if status.Code(replay) != codes.Aborted {
+		t.Errorf("replay while running: err = %v, want Aborted", replay)
+	}
+	if status.Code(reuse) != codes.FailedPrecondition {
+		t.Errorf("different request while running: err = %v, want FailedPrecondition", reuse)
+	}
+}
+
+func TestWithRunIdempotencyReleasesKeyOnError(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "key-1"))
+	s := newTestService(t, nil)
+	failing := func(context.Context, *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+		return nil, errors.New("engine unavailable")
+	}
+	if _, err := s.withRunIdempotency(ctx, &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1"}, failing); err == nil {
+		t.Fatal("first request succeeded, want the handler's error")
+	}
+
+	// the key is free again, also for a corrected request
+	ran := false
+	_, err := s.withRunIdempotency(ctx, &pb.RunProblemRequest{UserId: "u1", ProblemId: "p2"}, func(context.Context, *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+		ran = true
+		return &pb.RunProblemResponse{}, nil
+	})
+	if err != nil || !ran {
+		t.Errorf("retry: ran %v, err %v; want it to run", ran, err)
+	}
+}
+
+func TestWithRunIdempotencyStoresProtoJSON(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "key-1"))
+	req := &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1", Language: "go", UserCode: "func f() {}"}
+	want := &pb.RunProblemResponse{Success: true, ProblemId: "p1", Language: "go", IsRunTestcase: true, Message: "ran"}
+	s := newTestService(t, nil)
+
+	if _, err := s.withRunIdempotency(ctx, req, func(context.Context, *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+		return want, nil
+	}); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	cached, _ := s.RedisCacheClient.Get(ctx, "idempotency:run:u1:key-1")
+	var record idempotencyRecord
+	if err := json.Unmarshal([]byte(cached.(string)), &record); err != nil {
+		t.Fatalf("stored record is not JSON: %v", err)
+	}
+	var stored pb.RunProblemResponse
+	if err := protojson.Unmarshal(record.Response, &stored); err != nil {
+		t.Fatalf("stored response is not protojson: %v", err)
+	}
+	if !proto.Equal(&stored, want) {
+		t.Errorf("stored response = %v, want %v", &stored, want)
+	}
+
+	replayed, err := s.withRunIdempotency(ctx, req, nil)
+	if err != nil || !proto.Equal(replayed, want) {
+		t.Errorf("replay = %v, %v; want %v", replayed, err, want)
+	}
+}
+
+// ttlCache records the expiration of every Set of a memoryCache
+type ttlCache struct {
+	*memoryCache
+	ttlMu sync.Mutex
+	ttls  []time.Duration
+}
+
+func (c *ttlCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.ttlMu.Lock()
+	c.ttls = append(c.ttls, expiration)
+	c.ttlMu.Unlock()
+	return c.memoryCache.Set(ctx, key, value, expiration)
+}
+
+func TestWithRunIdempotencyRenewsClaim(t *testing.T) {
+	interval := idempotencyRefreshInterval
+	idempotencyRefreshInterval = time.Millisecond
+	t.Cleanup(func() { idempotencyRefreshInterval = interval })
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "key-1"))
+	s := newTestService(t, nil)
+	recorded := &ttlCache{memoryCache: newMemoryCache()}
+	s.RedisCacheClient = recorded
+
+	_, err := s.withRunIdempotency(ctx, &pb.RunProblemRequest{UserId: "u1", ProblemId: "p1"}, func(context.Context, *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+		time.Sleep(20 * time.Millisecond)
+		return &pb.RunProblemResponse{Success: true}, nil
+	})
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	// a few renewals of the short pending claim, then the response stored for the full TTL, never overwritten
+	ttls := recorded.ttls
+	if len(ttls) < 2 || ttls[0] != idempotencyPendingTTL || ttls[len(ttls)-1] != idempotencyTTL {
+		t.Fatalf("Set expirations = %v, want renewals of %v then %v", ttls, idempotencyPendingTTL, idempotencyTTL)
+	}
+}
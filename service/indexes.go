@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// EnsureIndexes creates any missing MongoDB indexes, the same routine that runs on startup, e.g. after a
+// collection was restored from a dump without its indexes
+func (s *ProblemService) EnsureIndexes(ctx context.Context, req *model.EnsureIndexesRequest) (*model.EnsureIndexesResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting EnsureIndexes", map[string]any{
+		"method": "EnsureIndexes",
+	}, "SERVICE", nil)
+
+	if _, err := s.requireAdmin(ctx, traceID, "EnsureIndexes"); err != nil {
+		return nil, err
+	}
+
+	collections, err := s.RepoConnInstance.EnsureIndexes(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to ensure indexes", map[string]any{
+			"method":      "EnsureIndexes",
+			"collections": len(collections),
+			"errorType":   "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to ensure indexes", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Indexes ensured successfully", map[string]any{
+		"method":      "EnsureIndexes",
+		"collections": len(collections),
+	}, "SERVICE", nil)
+	return &model.EnsureIndexesResponse{Collections: collections}, nil
+}
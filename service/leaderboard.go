@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"xcode/model"
+	"xcode/utils"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const maxLeaderboardPageSize = 100
+
+// entityFilter normalizes an entity a caller filters by. Values that name no country are upper-cased as before, so
+// they still match entities stored before NormalizeCountries cleaned them up.
+func entityFilter(entity string) string {
+	if country, ok := utils.NormalizeCountry(entity); ok {
+		return country
+	}
+	return strings.ToUpper(entity)
+}
+
+// GetLeaderboardPage retrieves one page of the global or entity leaderboard with the total participant count
+func (s *ProblemService) GetLeaderboardPage(ctx context.Context, req *model.GetLeaderboardPageRequest) (*model.GetLeaderboardPageResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	entity := ""
+	if req.Entity != nil {
+		entity = entityFilter(*req.Entity)
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetLeaderboardPage", map[string]any{
+		"method":   "GetLeaderboardPage",
+		"page":     req.Page,
+		"pageSize": req.PageSize,
+		"entity":   entity,
+	}, "SERVICE", nil)
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+	if req.PageSize > maxLeaderboardPageSize {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Page size too large", map[string]any{
+			"method":    "GetLeaderboardPage",
+			"pageSize":  req.PageSize,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("Page size cannot exceed %d", maxLeaderboardPageSize), "VALIDATION_ERROR", nil)
+	}
+	skip := (req.Page - 1) * req.PageSize
+
+	startRedis := time.Now()
+	users, total, err := s.getLeaderboardPageRedis(ctx, entity, skip, req.PageSize)
+	if err == nil && total > 0 {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved leaderboard page from Redis", map[string]any{
+			"method":   "GetLeaderboardPage",
+			"entity":   entity,
+			"duration": time.Since(startRedis).String(),
+		}, "SERVICE", nil)
+		return &model.GetLeaderboardPageResponse{
+			Users:      users,
+			TotalCount: total,
+			Page:       req.Page,
+			PageSize:   req.PageSize,
+		}, nil
+	}
+	s.logger.Log(zapcore.WarnLevel, traceID, "Redis miss for leaderboard page", map[string]any{
+		"method":   "GetLeaderboardPage",
+		"entity":   entity,
+		"duration": time.Since(startRedis).String(),
+	}, "SERVICE", err)
+
+	startMongo := time.Now()
+	users, total, err = s.RepoConnInstance.GetLeaderboardPageMongo(ctx, entity, skip, req.PageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch leaderboard page from MongoDB", map[string]any{
+			"method":    "GetLeaderboardPage",
+			"entity":    entity,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, fmt.Errorf("failed to fetch leaderboard page from MongoDB: %w", err)
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved leaderboard page from MongoDB", map[string]any{
+		"method":   "GetLeaderboardPage",
+		"entity":   entity,
+		"duration": time.Since(startMongo).String(),
+	}, "SERVICE", nil)
+
+	return &model.GetLeaderboardPageResponse{
+		Users:      users,
+		TotalCount: total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}, nil
+}
+
+// getLeaderboardPageRedis reads a page straight from the RedisBoard sorted sets, which are not limited to K
+func (s *ProblemService) getLeaderboardPageRedis(ctx context.Context, entity string, skip, limit int64) ([]model.RankedUserScore, int64, error) {
+	key := s.lbNamespace + ":global"
+	if entity != "" {
+		key = s.lbNamespace + ":entity:" + entity
+	}
+
+	total, err := s.RedisCacheClient.ZCard(ctx, key)
+	if err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	members, err := s.RedisCacheClient.ZRevRangeWithScores(ctx, key, skip, skip+limit-1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	userIDs := make([]string, len(members))
+	for i, m := range members {
+		userIDs[i], _ = m.Member.(string)
+	}
+
+	entities := make([]interface{}, len(userIDs))
+	if entity == "" && len(userIDs) > 0 {
+		entities, err = s.RedisCacheClient.HMGet(ctx, s.lbNamespace+":user:entities", userIDs...)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	users := make([]model.RankedUserScore, len(members))
+	for i, m := range members {
+		userEntity := entity
+		if userEntity == "" {
+			userEntity, _ = entities[i].(string)
+		}
+		users[i] = model.RankedUserScore{
+			UserID: userIDs[i],
+			Score:  m.Score,
+			Entity: userEntity,
+			Rank:   skip + int64(i) + 1,
+		}
+	}
+	return users, total, nil
+}
+
+const seasonSnapshotSize = 100
+
+// seasonStart returns the UTC start of the weekly (Monday) or monthly season containing t
+func seasonStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == model.LeaderboardPeriodWeekly {
+		return midnight.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// previousSeasonStart returns the start of the season before the one beginning at start
+func previousSeasonStart(period string, start time.Time) time.Time {
+	if period == model.LeaderboardPeriodWeekly {
+		return start.AddDate(0, 0, -7)
+	}
+	return start.AddDate(0, -1, 0)
+}
+
+// syncPeriodLeaderboardsFromMongo rebuilds every seasonal board from the current season's first successes
+func (s *ProblemService) syncPeriodLeaderboardsFromMongo(ctx context.Context, traceID string) {
+	for period, lb := range s.PeriodLBs {
+		lb.ForceClearLeaderBoardWithNamespacePrefix()
+		if err := s.RepoConnInstance.SyncPeriodLeaderboardToRedis(ctx, lb, seasonStart(period, time.Now())); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to sync seasonal leaderboard to Redis", map[string]any{
+				"method":    "syncPeriodLeaderboardsFromMongo",
+				"period":    period,
+				"errorType": "LEADERBOARD_SYNC_FAILED",
+			}, "SERVICE", err)
+		}
+	}
+}
+
+// RolloverLeaderboardSeason snapshots the season that just ended to Mongo and resets the seasonal board
+func (s *ProblemService) RolloverLeaderboardSeason(ctx context.Context, period string) error {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RolloverLeaderboardSeason", map[string]any{
+		"method": "RolloverLeaderboardSeason",
+		"period": period,
+	}, "SERVICE", nil)
+
+	lb, ok := s.PeriodLBs[period]
+	if !ok {
+		return fmt.Errorf("unknown leaderboard period %q", period)
+	}
+
+	currentStart := seasonStart(period, time.Now())
+	previousStart := previousSeasonStart(period, currentStart)
+
+	users, err := s.RepoConnInstance.GetStandingsBetweenMongo(ctx, previousStart, currentStart, seasonSnapshotSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to compute season standings", map[string]any{
+			"method":    "RolloverLeaderboardSeason",
+			"period":    period,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+
+	err = s.RepoConnInstance.SaveLeaderboardSeasonSnapshot(ctx, model.LeaderboardSeasonSnapshot{
+		Period:      period,
+		SeasonStart: previousStart,
+		SeasonEnd:   currentStart,
+		Users:       users,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save season snapshot", map[string]any{
+			"method":    "RolloverLeaderboardSeason",
+			"period":    period,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+
+	lb.ForceClearLeaderBoardWithNamespacePrefix()
+	if err := s.RepoConnInstance.SyncPeriodLeaderboardToRedis(ctx, lb, currentStart); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to resync seasonal leaderboard", map[string]any{
+			"method":    "RolloverLeaderboardSeason",
+			"period":    period,
+			"errorType": "LEADERBOARD_SYNC_FAILED",
+		}, "SERVICE", err)
+		return err
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard season rolled over", map[string]any{
+		"method":      "RolloverLeaderboardSeason",
+		"period":      period,
+		"seasonStart": previousStart,
+		"users":       len(users),
+	}, "SERVICE", nil)
+	return nil
+}
+
+// GetTopKGlobalForPeriod retrieves top K global users for the all-time, weekly or monthly board
+func (s *ProblemService) GetTopKGlobalForPeriod(ctx context.Context, req *model.GetTopKGlobalForPeriodRequest) (*pb.GetTopKGlobalResponse, error) {
+	if req.Period == "" || req.Period == model.LeaderboardPeriodAllTime {
+		return s.GetTopKGlobal(ctx, &pb.GetTopKGlobalRequest{K: req.K})
+	}
+
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetTopKGlobalForPeriod", map[string]any{
+		"method": "GetTopKGlobalForPeriod",
+		"k":      req.K,
+		"period": req.Period,
+	}, "SERVICE", nil)
+
+	lb, ok := s.PeriodLBs[req.Period]
+	if !ok {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Unknown leaderboard period", map[string]any{
+			"method":    "GetTopKGlobalForPeriod",
+			"period":    req.Period,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Period must be one of all, weekly or monthly", "VALIDATION_ERROR", nil)
+	}
+
+	startRedis := time.Now()
+	users, err := lb.GetTopKGlobal()
+	if err == nil && len(users) > 0 {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved top K period users from Redis", map[string]any{
+			"method":   "GetTopKGlobalForPeriod",
+			"period":   req.Period,
+			"duration": time.Since(startRedis).String(),
+		}, "SERVICE", nil)
+		resp := &pb.GetTopKGlobalResponse{
+			Users: make([]*pb.UserScore, len(users)),
+		}
+		for i, user := range users {
+			resp.Users[i] = &pb.UserScore{
+				UserId: user.ID,
+				Score:  user.Score,
+				Entity: user.Entity,
+			}
+		}
+		return resp, nil
+	}
+	s.logger.Log(zapcore.WarnLevel, traceID, "Redis miss for top K period users", map[string]any{
+		"method":   "GetTopKGlobalForPeriod",
+		"period":   req.Period,
+		"duration": time.Since(startRedis).String(),
+	}, "SERVICE", nil)
+
+	k := int(req.K)
+	if k == 0 {
+		k = 10
+	}
+	now := time.Now()
+	mongoUsers, err := s.RepoConnInstance.GetStandingsBetweenMongo(ctx, seasonStart(req.Period, now), now.Add(time.Minute), k)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch top K period users from MongoDB", map[string]any{
+			"method":    "GetTopKGlobalForPeriod",
+			"period":    req.Period,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, fmt.Errorf("failed to fetch top K %s users from MongoDB: %w", req.Period, err)
+	}
+
+	resp := &pb.GetTopKGlobalResponse{
+		Users: make([]*pb.UserScore, len(mongoUsers)),
+	}
+	for i, user := range mongoUsers {
+		resp.Users[i] = &pb.UserScore{
+			UserId: user.UserID,
+			Score:  user.Score,
+			Entity: user.Entity,
+		}
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Top K period users retrieved successfully", map[string]any{
+		"method": "GetTopKGlobalForPeriod",
+		"period": req.Period,
+	}, "SERVICE", nil)
+	return resp, nil
+}
+
+const dailySnapshotSize = 100
+
+// SnapshotLeaderboards stores today's top global and per-entity standings for history charts
+func (s *ProblemService) SnapshotLeaderboards(ctx context.Context) error {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting SnapshotLeaderboards", map[string]any{
+		"method": "SnapshotLeaderboards",
+	}, "SERVICE", nil)
+
+	now := time.Now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	globalUsers, _, err := s.RepoConnInstance.GetLeaderboardPageMongo(ctx, "", 0, dailySnapshotSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to compute global standings", map[string]any{
+			"method":    "SnapshotLeaderboards",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+	entityStandings, err := s.RepoConnInstance.GetEntityStandingsMongo(ctx, dailySnapshotSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to compute entity standings", map[string]any{
+			"method":    "SnapshotLeaderboards",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+
+	snapshots := []model.LeaderboardDailySnapshot{{
+		Date:      date,
+		Scope:     model.LeaderboardScopeGlobal,
+		Users:     globalUsers,
+		CreatedAt: now,
+	}}
+	for entity, users := range entityStandings {
+		snapshots = append(snapshots, model.LeaderboardDailySnapshot{
+			Date:      date,
+			Scope:     entity,
+			Users:     users,
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.RepoConnInstance.SaveLeaderboardDailySnapshots(ctx, snapshots); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save leaderboard snapshots", map[string]any{
+			"method":    "SnapshotLeaderboards",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard snapshots saved", map[string]any{
+		"method":    "SnapshotLeaderboards",
+		"snapshots": len(snapshots),
+	}, "SERVICE", nil)
+	return nil
+}
+
+// GetLeaderboardHistory retrieves daily snapshots of a board and optionally one user's rank over time
+func (s *ProblemService) GetLeaderboardHistory(ctx context.Context, req *model.GetLeaderboardHistoryRequest) (*model.GetLeaderboardHistoryResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	scope := strings.ToUpper(req.Scope)
+	if scope == "" {
+		scope = model.LeaderboardScopeGlobal
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetLeaderboardHistory", map[string]any{
+		"method": "GetLeaderboardHistory",
+		"scope":  scope,
+		"userId": req.UserID,
+		"days":   req.Days,
+	}, "SERVICE", nil)
+
+	if req.Days < 1 {
+		req.Days = 30
+	}
+	if req.Days > 366 {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "History range too large", map[string]any{
+			"method":    "GetLeaderboardHistory",
+			"days":      req.Days,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Days cannot exceed 366", "VALIDATION_ERROR", nil)
+	}
+	if req.TopN < 1 {
+		req.TopN = 10
+	}
+
+	from := time.Now().UTC().AddDate(0, 0, -int(req.Days))
+	snapshots, err := s.RepoConnInstance.GetLeaderboardDailySnapshots(ctx, scope, from)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch leaderboard snapshots", map[string]any{
+			"method":    "GetLeaderboardHistory",
+			"scope":     scope,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, fmt.Errorf("failed to fetch leaderboard snapshots: %w", err)
+	}
+
+	resp := &model.GetLeaderboardHistoryResponse{
+		Snapshots: make([]model.LeaderboardDailySnapshot, len(snapshots)),
+	}
+	for i, snapshot := range snapshots {
+		if req.UserID != "" {
+			point := model.UserRankPoint{Date: snapshot.Date.Format("2006-01-02")}
+			for _, user := range snapshot.Users {
+				if user.UserID == req.UserID {
+					point.Rank = user.Rank
+					point.Score = user.Score
+					break
+				}
+			}
+			resp.UserHistory = append(resp.UserHistory, point)
+		}
+		if len(snapshot.Users) > int(req.TopN) {
+			snapshot.Users = snapshot.Users[:req.TopN]
+		}
+		resp.Snapshots[i] = snapshot
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard history retrieved successfully", map[string]any{
+		"method":    "GetLeaderboardHistory",
+		"scope":     scope,
+		"snapshots": len(resp.Snapshots),
+	}, "SERVICE", nil)
+	return resp, nil
+}
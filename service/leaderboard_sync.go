@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/model"
+	"xcode/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// leaderboardSyncBatchSize bounds how many users are written per Redis pipeline
+const leaderboardSyncBatchSize = 500
+
+// leaderboardOutboxBatchSize bounds how many pending leaderboard updates one run applies
+const leaderboardOutboxBatchSize = 500
+
+// leaderboardSyncSafetyWindow is how far the incremental sync marker trails the newest first success it saw.
+// submittedAt is stamped before the write commits, so a slow write or a replica's clock can make a first success
+// appear after a newer one was already synced; rescanning the window picks it up. Rewriting users already synced is
+// harmless, their scores are absolute.
+const leaderboardSyncSafetyWindow = 5 * time.Minute
+
+// PeriodLeaderboardNamespace is the RedisBoard namespace of a seasonal board; the prefix keeps it out of ForceClear on the all-time namespace
+func PeriodLeaderboardNamespace(period, namespace string) string {
+	return "season_" + period + ":" + namespace
+}
+
+// leaderboardSyncMarkerKey lives under the board namespace so a ForceClear also drops it and forces the next sync to rebuild
+func (s *ProblemService) leaderboardSyncMarkerKey() string {
+	return s.lbNamespace + ":sync:lastSubmittedAt"
+}
+
+func (s *ProblemService) getLeaderboardSyncMarker(ctx context.Context) (time.Time, bool) {
+	cached, err := s.RedisCacheClient.Get(ctx, s.leaderboardSyncMarkerKey())
+	if err != nil || cached == nil {
+		return time.Time{}, false
+	}
+	cachedStr, ok := cached.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	marker, err := time.Parse(time.RFC3339Nano, cachedStr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return marker, true
+}
+
+func (s *ProblemService) setLeaderboardSyncMarker(ctx context.Context, traceID string, marker time.Time) {
+	if err := s.RedisCacheClient.Set(ctx, s.leaderboardSyncMarkerKey(), marker.UTC().Format(time.RFC3339Nano), 0); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to store leaderboard sync marker", map[string]any{
+			"method":    "setLeaderboardSyncMarker",
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+}
+
+// writeLeaderboardBatch sets absolute scores for users on a RedisBoard namespace, mirroring RedisBoard's AddUser key layout
+// but sending each batch in one pipeline. Absolute scores make the write safe to repeat for users already updated live.
+// A user whose entity changed is taken off the board of the entity recorded for them before.
+func (s *ProblemService) writeLeaderboardBatch(ctx context.Context, namespace string, users []model.RankedUserScore) error {
+	for start := 0; start < len(users); start += leaderboardSyncBatchSize {
+		end := start + leaderboardSyncBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+		batch := users[start:end]
+		userIDs := make([]string, len(batch))
+		for i, user := range batch {
+			userIDs[i] = user.UserID
+		}
+		previous, err := s.RedisCacheClient.HMGet(ctx, namespace+":user:entities", userIDs...)
+		if err != nil {
+			return err
+		}
+		err = s.RedisCacheClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, user := range batch {
+				if entity, _ := previous[i].(string); entity != "" && entity != user.Entity {
+					pipe.ZRem(ctx, namespace+":entity:"+entity, user.UserID)
+				}
+				pipe.ZAdd(ctx, namespace+":global", &redis.Z{Score: user.Score, Member: user.UserID})
+				pipe.HSet(ctx, namespace+":user:entities", user.UserID, user.Entity)
+				if user.Entity != "" {
+					pipe.ZAdd(ctx, namespace+":entity:"+user.Entity, &redis.Z{Score: user.Score, Member: user.UserID})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IncrementalSyncLeaderboard reconciles only the users with first successes since the last sync marker.
+// Without a marker (first start or after a clear) it falls back to a full rebuild.
+func (s *ProblemService) IncrementalSyncLeaderboard(ctx context.Context) error {
+	traceID := traceIDFromContext(ctx)
+
+	marker, ok := s.getLeaderboardSyncMarker(ctx)
+	if !ok {
+		s.logger.Log(zapcore.InfoLevel, traceID, "No leaderboard sync marker, running full rebuild", map[string]any{
+			"method": "IncrementalSyncLeaderboard",
+		}, "SERVICE", nil)
+		return s.SyncLeaderboardFromMongo(ctx)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting IncrementalSyncLeaderboard", map[string]any{
+		"method": "IncrementalSyncLeaderboard",
+		"since":  marker,
+	}, "SERVICE", nil)
+	start := time.Now()
+	defer leaderboardSyncDuration.ObserveDuration(start, "incremental")
+
+	userIDs, latest, err := s.RepoConnInstance.GetUsersChangedSinceMongo(ctx, marker)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch changed users", map[string]any{
+			"method":    "IncrementalSyncLeaderboard",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+	if len(userIDs) == 0 {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard already up to date", map[string]any{
+			"method": "IncrementalSyncLeaderboard",
+		}, "SERVICE", nil)
+		// inactivity grows without new solves, so decay is applied all the same
+		s.applyScoreDecay(ctx, traceID)
+		return nil
+	}
+
+	totals, err := s.RepoConnInstance.GetUserTotalsMongo(ctx, userIDs, time.Time{})
+	if err == nil {
+		err = s.writeLeaderboardBatch(ctx, s.lbNamespace, totals)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to sync changed users to Redis", map[string]any{
+			"method":    "IncrementalSyncLeaderboard",
+			"errorType": "LEADERBOARD_SYNC_FAILED",
+		}, "SERVICE", err)
+		return err
+	}
+
+	for period := range s.PeriodLBs {
+		periodTotals, err := s.RepoConnInstance.GetUserTotalsMongo(ctx, userIDs, seasonStart(period, time.Now()))
+		if err == nil {
+			err = s.writeLeaderboardBatch(ctx, PeriodLeaderboardNamespace(period, s.lbNamespace), periodTotals)
+		}
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to sync changed users to seasonal leaderboard", map[string]any{
+				"method":    "IncrementalSyncLeaderboard",
+				"period":    period,
+				"errorType": "LEADERBOARD_SYNC_FAILED",
+			}, "SERVICE", err)
+		}
+	}
+
+	if err := s.syncOrganizationLeaderboard(ctx, userIDs); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to sync changed users to organization leaderboard", map[string]any{
+			"method":    "IncrementalSyncLeaderboard",
+			"errorType": "LEADERBOARD_SYNC_FAILED",
+		}, "SERVICE", err)
+	}
+
+	s.applyScoreDecay(ctx, traceID)
+	// the marker only moves forward, a rescan of the window must not take it back past where it was
+	if next := latest.Add(-leaderboardSyncSafetyWindow); next.After(marker) {
+		s.setLeaderboardSyncMarker(ctx, traceID, next)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard synced incrementally", map[string]any{
+		"method":    "IncrementalSyncLeaderboard",
+		"userCount": len(userIDs),
+		"duration":  time.Since(start).Seconds(),
+	}, "SERVICE", nil)
+	return nil
+}
+
+// AdminResyncLeaderboard clears every leaderboard and rebuilds it from MongoDB
+func (s *ProblemService) AdminResyncLeaderboard(ctx context.Context, req *model.AdminResyncLeaderboardRequest) (*model.AdminResyncLeaderboardResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting AdminResyncLeaderboard", map[string]any{
+		"method": "AdminResyncLeaderboard",
+	}, "SERVICE", nil)
+
+	if _, err := s.requireAdmin(ctx, traceID, "AdminResyncLeaderboard"); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := s.SyncLeaderboardFromMongo(ctx); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to resync leaderboard", map[string]any{
+			"method":    "AdminResyncLeaderboard",
+			"errorType": "LEADERBOARD_SYNC_FAILED",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to resync leaderboard", "LEADERBOARD_SYNC_FAILED", err)
+	}
+
+	duration := time.Since(start)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard resynced successfully", map[string]any{
+		"method":   "AdminResyncLeaderboard",
+		"duration": duration.Seconds(),
+	}, "SERVICE", nil)
+	return &model.AdminResyncLeaderboardResponse{
+		Success:  true,
+		Message:  "Leaderboard resynced successfully",
+		Duration: duration.String(),
+	}, nil
+}
+
+// RecalculateScores rewrites stored first-success scores with the configured score table and rebuilds the leaderboards
+func (s *ProblemService) RecalculateScores(ctx context.Context, req *model.RecalculateScoresRequest) (*model.RecalculateScoresResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RecalculateScores", map[string]any{
+		"method": "RecalculateScores",
+		"dryRun": req.DryRun,
+	}, "SERVICE", nil)
+
+	if _, err := s.requireAdmin(ctx, traceID, "RecalculateScores"); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	scanned, updated, err := s.RepoConnInstance.RecalculateFirstSuccessScores(ctx, req.DryRun)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to recalculate scores", map[string]any{
+			"method":    "RecalculateScores",
+			"scanned":   scanned,
+			"updated":   updated,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to recalculate scores", "DB_ERROR", err)
+	}
+
+	resp := &model.RecalculateScoresResponse{Scanned: scanned, Updated: updated}
+	if !req.DryRun && updated > 0 {
+		if err := s.SyncLeaderboardFromMongo(ctx); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Scores recalculated but leaderboard resync failed", map[string]any{
+				"method":    "RecalculateScores",
+				"errorType": "LEADERBOARD_SYNC_FAILED",
+			}, "SERVICE", err)
+			return nil, s.createGrpcError(ctx, codes.Internal, "Scores recalculated but leaderboard resync failed", "LEADERBOARD_SYNC_FAILED", err)
+		}
+		resp.Resynced = true
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Scores recalculated successfully", map[string]any{
+		"method":   "RecalculateScores",
+		"scanned":  scanned,
+		"updated":  updated,
+		"resynced": resp.Resynced,
+		"duration": time.Since(start).Seconds(),
+	}, "SERVICE", nil)
+	return resp, nil
+}
+
+// NormalizeCountries rewrites the countries stored on submissions and first successes to ISO 3166-1 codes, clearing
+// the ones that name no country, and rebuilds the leaderboards so their entities follow
+func (s *ProblemService) NormalizeCountries(ctx context.Context, req *model.NormalizeCountriesRequest) (*model.NormalizeCountriesResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting NormalizeCountries", map[string]any{
+		"method": "NormalizeCountries",
+		"dryRun": req.DryRun,
+	}, "SERVICE", nil)
+
+	if _, err := s.requireAdmin(ctx, traceID, "RecalculateScores"); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	normalize := func(country string) string {
+		code, _ := utils.NormalizeCountry(country)
+		return code
+	}
+	changes, documents, err := s.RepoConnInstance.NormalizeEntitiesMongo(ctx, normalize, req.DryRun)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to normalize countries", map[string]any{
+			"method":    "NormalizeCountries",
+			"documents": documents,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to normalize countries", "DB_ERROR", err)
+	}
+
+	resp := &model.NormalizeCountriesResponse{Changes: changes, Documents: documents}
+	if !req.DryRun && documents > 0 {
+		if err := s.SyncLeaderboardFromMongo(ctx); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Countries normalized but leaderboard resync failed", map[string]any{
+				"method":    "NormalizeCountries",
+				"errorType": "LEADERBOARD_SYNC_FAILED",
+			}, "SERVICE", err)
+			return nil, s.createGrpcError(ctx, codes.Internal, "Countries normalized but leaderboard resync failed", "LEADERBOARD_SYNC_FAILED", err)
+		}
+		if err := s.RedisCacheClient.Delete(ctx, entityStatsCacheKey); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    "NormalizeCountries",
+				"cacheKey":  entityStatsCacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+		resp.Resynced = true
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Countries normalized successfully", map[string]any{
+		"method":    "NormalizeCountries",
+		"countries": len(changes),
+		"documents": documents,
+		"resynced":  resp.Resynced,
+		"duration":  time.Since(start).Seconds(),
+	}, "SERVICE", nil)
+	return resp, nil
+}
+
+// ApplyPendingLeaderboardUpdates catches the board up with first successes whose score could not be written to
+// Redis right after the submission, e.g. during a Redis outage
+func (s *ProblemService) ApplyPendingLeaderboardUpdates(ctx context.Context) error {
+	traceID := traceIDFromContext(ctx)
+	applied, err := s.RepoConnInstance.ApplyPendingLeaderboardUpdates(ctx, leaderboardOutboxBatchSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to apply pending leaderboard updates", map[string]any{
+			"method":    "ApplyPendingLeaderboardUpdates",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+	if applied > 0 {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Pending leaderboard updates applied", map[string]any{
+			"method":  "ApplyPendingLeaderboardUpdates",
+			"applied": applied,
+		}, "SERVICE", nil)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// SetLogLevel changes the minimum log level of the running process, e.g. to debug an incident without a restart.
+// The change is not persisted: a restart, or a configuration reload that changes LOGLEVEL, goes back to LOGLEVEL.
+func (s *ProblemService) SetLogLevel(ctx context.Context, req *model.SetLogLevelRequest) (*model.SetLogLevelResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	if req.Level == "" {
+		current := s.logger.Level().String()
+		return &model.SetLogLevelResponse{Level: current, Previous: current}, nil
+	}
+	adminID, err := s.requireAdmin(ctx, traceID, "SetLogLevel")
+	if err != nil {
+		return nil, err
+	}
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Level must be one of debug, info, warn or error", "VALIDATION_ERROR", err)
+	}
+
+	previous := s.logger.SetLevel(level)
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionSetLogLevel,
+		Actor:      adminID,
+		TargetType: model.AuditTargetService,
+		TargetID:   "log_level",
+		Before:     map[string]any{"level": previous.String()},
+		After:      map[string]any{"level": level.String()},
+	})
+
+	// logged at warn so the change itself shows up whatever the new level is
+	s.logger.Log(zapcore.WarnLevel, traceID, "Log level changed", map[string]any{
+		"method":   "SetLogLevel",
+		"adminId":  adminID,
+		"level":    level.String(),
+		"previous": previous.String(),
+	}, "SERVICE", nil)
+	return &model.SetLogLevelResponse{Level: level.String(), Previous: previous.String()}, nil
+}
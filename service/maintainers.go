@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+
+	"xcode/customerrors"
+	"xcode/interceptor"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// adminRole is the user service role that may change any problem
+	adminRole = "admin"
+
+	defaultAuthorProblemsPageSize = 20
+	maxAuthorProblemsPageSize     = 100
+)
+
+// AddProblemMaintainer lets another user change a problem. Only its maintainers and admins may add one.
+func (s *ProblemService) AddProblemMaintainer(ctx context.Context, req *model.AddProblemMaintainerRequest) (*model.AddProblemMaintainerResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting AddProblemMaintainer", map[string]any{
+		"method":    "AddProblemMaintainer",
+		"problemId": req.ProblemID,
+		"userId":    req.UserID,
+	}, "SERVICE", nil)
+
+	if req.ProblemID == "" || req.UserID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and user ID are required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "AddProblemMaintainer", req.ProblemID); err != nil {
+		return nil, err
+	}
+
+	maintainers, added, err := s.RepoConnInstance.AddProblemMaintainer(ctx, req.ProblemID, req.UserID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to add problem maintainer", map[string]any{
+			"method":    "AddProblemMaintainer",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to add problem maintainer")
+	}
+
+	if added {
+		cacheKey := problemMaintainersCacheKey(req.ProblemID)
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    "AddProblemMaintainer",
+				"cacheKey":  cacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+		s.recordAudit(ctx, traceID, model.AuditEntry{
+			Action:     model.AuditActionAddMaintainer,
+			TargetType: model.AuditTargetProblem,
+			TargetID:   req.ProblemID,
+			After:      map[string]any{"maintainer": req.UserID, "maintainers": maintainers},
+		})
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem maintainer added", map[string]any{
+		"method":    "AddProblemMaintainer",
+		"problemId": req.ProblemID,
+		"userId":    req.UserID,
+		"added":     added,
+	}, "SERVICE", nil)
+	return &model.AddProblemMaintainerResponse{ProblemID: req.ProblemID, Maintainers: maintainers, Added: added}, nil
+}
+
+// ListProblemsByAuthor pages through the problems a user created or maintains. Lists are per user and change
+// rarely once written, so they are not cached.
+func (s *ProblemService) ListProblemsByAuthor(ctx context.Context, req *model.ListProblemsByAuthorRequest) (*model.ListProblemsByAuthorResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	authorID := req.AuthorID
+	if authorID == "" {
+		authorID = interceptor.UserIDFromMetadata(ctx)
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ListProblemsByAuthor", map[string]any{
+		"method":   "ListProblemsByAuthor",
+		"authorId": authorID,
+		"page":     req.Page,
+	}, "SERVICE", nil)
+
+	if authorID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Author ID is required", "VALIDATION_ERROR", nil)
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultAuthorProblemsPageSize
+	}
+	if pageSize > maxAuthorProblemsPageSize {
+		pageSize = maxAuthorProblemsPageSize
+	}
+
+	problems, err := s.RepoConnInstance.ListProblemsByMaintainer(ctx, authorID, page, pageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list problems by author", map[string]any{
+			"method":    "ListProblemsByAuthor",
+			"authorId":  authorID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list problems")
+	}
+	if problems, err = s.problemsForView(ctx, traceID, "ListProblemsByAuthor", problems); err != nil {
+		return nil, err
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problems by author retrieved successfully", map[string]any{
+		"method":   "ListProblemsByAuthor",
+		"authorId": authorID,
+		"count":    len(problems.Problems),
+		"total":    problems.TotalCount,
+	}, "SERVICE", nil)
+	return &model.ListProblemsByAuthorResponse{AuthorID: authorID, Problems: problems}, nil
+}
+
+// EnforceProblemAuthorization sets whether authorizeProblemMutation rejects callers who may not change a problem.
+// Without it they are only logged, with "enforced": false, so clients can start sending x-user-id or an admin API
+// key before changes are refused.
+func (s *ProblemService) EnforceProblemAuthorization(enforce bool) {
+	s.enforceProblemAuthz = enforce
+}
+
+// authorizeProblemMutation returns nil when the caller may change problemID: a maintainer of it, a user with the
+// admin role or an API key with the admin scope. Callers are identified by the gateway's x-user-id, so requests
+// with neither it nor an API key are rejected. Problems from before authors were recorded have no maintainers and
+// are left to admins. Until EnforceProblemAuthorization is on, rejected callers are logged and let through.
+func (s *ProblemService) authorizeProblemMutation(ctx context.Context, traceID, method, problemID string) error {
+	if key := interceptor.APIKeyFromContext(ctx); key != nil && key.HasScope(model.APIKeyScopeAdmin) {
+		return nil
+	}
+	userID := interceptor.UserIDFromMetadata(ctx)
+	if userID == "" {
+		return s.denyProblemMutation(ctx, traceID, "Problem change without a caller", map[string]any{
+			"method":    method,
+			"problemId": problemID,
+		}, codes.Unauthenticated, "UNAUTHENTICATED", "Sign in to change problems")
+	}
+
+	problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: problemID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    method,
+			"problemId": problemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return s.repoError(ctx, err, "Failed to fetch problem")
+	}
+	if problem.IsMaintainer(userID) {
+		return nil
+	}
+
+	role, err := s.userRole(ctx, userID)
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to look up role in user service", map[string]any{
+			"method":    method,
+			"userId":    userID,
+			"errorType": "USER_SERVICE_ERROR",
+		}, "SERVICE", err)
+	}
+	if role == adminRole {
+		return nil
+	}
+
+	return s.denyProblemMutation(ctx, traceID, "Problem change by a non-maintainer", map[string]any{
+		"method":      method,
+		"problemId":   problemID,
+		"userId":      userID,
+		"maintainers": problem.Maintainers,
+	}, codes.PermissionDenied, "NOT_A_MAINTAINER", "Only the problem's maintainers can change it")
+}
+
+// denyProblemMutation logs a caller who may not change a problem and returns their error, or nil while
+// authorization is not enforced
+func (s *ProblemService) denyProblemMutation(ctx context.Context, traceID, msg string, fields map[string]any, code codes.Code, errorType, message string) error {
+	fields["errorType"] = errorType
+	fields["enforced"] = s.enforceProblemAuthz
+	s.logger.Log(zapcore.WarnLevel, traceID, msg, fields, "SERVICE", nil)
+	if !s.enforceProblemAuthz {
+		return nil
+	}
+	return s.createGrpcError(ctx, code, message, errorType, nil)
+}
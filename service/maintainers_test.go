@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"xcode/model"
+	"xcode/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeProblemMutation(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		enforce  bool
+		wantCode codes.Code
+	}{
+		{name: "maintainer", userID: "maintainer", enforce: true, wantCode: codes.OK},
+		{name: "author", userID: "author", enforce: true, wantCode: codes.OK},
+		{name: "admin", userID: "admin-user", enforce: true, wantCode: codes.OK},
+		{name: "anonymous", enforce: true, wantCode: codes.Unauthenticated},
+		{name: "non-maintainer", userID: "member", enforce: true, wantCode: codes.PermissionDenied},
+		// until enforcement is turned on, callers who would be refused are only logged
+		{name: "anonymous when not enforced", wantCode: codes.OK},
+		{name: "non-maintainer when not enforced", userID: "member", wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+			repo.EXPECT().GetProblem(gomock.Any(), gomock.Any()).
+				Return(&model.Problem{CreatedBy: "author", Maintainers: []string{"maintainer"}}, nil).AnyTimes()
+			s := newTestService(t, repo)
+			s.EnforceProblemAuthorization(tt.enforce)
+			// roles the user service would return, as userRole caches them
+			s.RedisCacheClient.Set(context.Background(), userRoleCachePrefix+"admin-user", `"admin"`, time.Minute)
+			s.RedisCacheClient.Set(context.Background(), userRoleCachePrefix+"member", `"user"`, time.Minute)
+
+			ctx := context.Background()
+			if tt.userID != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-user-id", tt.userID))
+			}
+			err := s.authorizeProblemMutation(ctx, "trace", "UpdateProblem", "problem-1")
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("authorizeProblemMutation = %v, want code %v", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.PermissionDenied {
+				if reason := errorInfo(t, err).Reason; reason != "NOT_A_MAINTAINER" {
+					t.Errorf("reason = %q, want NOT_A_MAINTAINER", reason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"xcode/metrics"
+
+	"github.com/nats-io/nats.go"
+)
+
+var (
+	// submissionVerdicts counts judged runs by verdict: SUCCESS, FAILED, or the engine's error code for code that
+	// did not compile, crashed or hit a limit
+	submissionVerdicts = metrics.NewCounterVec("submission_verdicts_total", "verdict")
+
+	// engineRequestDuration is the latency of each request to the execution engine, retries counted separately
+	engineRequestDuration = metrics.NewHistogramVec("engine_request_duration_seconds", metrics.DefaultBuckets, "outcome")
+
+	// leaderboardSyncDuration is how long rebuilding the boards from MongoDB takes, by full or incremental sync
+	leaderboardSyncDuration = metrics.NewHistogramVec("leaderboard_sync_duration_seconds",
+		[]float64{.1, .5, 1, 5, 10, 30, 60, 120, 300}, "mode")
+
+	// firstSuccessDiscrepancies counts what the first success consistency check found, by model.FirstSuccess* kind
+	firstSuccessDiscrepancies = metrics.NewCounterVec("first_success_discrepancies_total", "kind")
+
+	// firstSuccessRepairs counts the missing first successes the consistency check stored again
+	firstSuccessRepairs = metrics.NewCounter("first_success_repairs_total")
+)
+
+// engineOutcome labels an execution request by how it ended
+func engineOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, nats.ErrNoResponders):
+		return "no_responders"
+	case errors.Is(err, nats.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "error"
+}
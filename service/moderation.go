@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"xcode/model"
+	"xcode/repository"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// refreshUserOnLeaderboards recomputes a user's score on the all-time, seasonal and organization boards from MongoDB,
+// removing the user where nothing is left to count (including when the user is banned)
+func (s *ProblemService) refreshUserOnLeaderboards(ctx context.Context, userID string) error {
+	boards := map[string]time.Time{s.lbNamespace: {}}
+	for period := range s.PeriodLBs {
+		boards[PeriodLeaderboardNamespace(period, s.lbNamespace)] = seasonStart(period, time.Now())
+	}
+
+	for namespace, since := range boards {
+		totals, err := s.RepoConnInstance.GetUserTotalsMongo(ctx, []string{userID}, since)
+		if err != nil {
+			return err
+		}
+		if len(totals) > 0 {
+			if err := s.writeLeaderboardBatch(ctx, namespace, totals); err != nil {
+				return err
+			}
+			continue
+		}
+		lb := s.LB
+		for period, periodLB := range s.PeriodLBs {
+			if PeriodLeaderboardNamespace(period, s.lbNamespace) == namespace {
+				lb = periodLB
+			}
+		}
+		if err := lb.RemoveUser(userID); err != nil {
+			return fmt.Errorf("failed to remove user %s from leaderboard: %w", userID, err)
+		}
+	}
+	return s.syncOrganizationLeaderboard(ctx, []string{userID})
+}
+
+// InvalidateSubmission revokes a submission's score, drops its first success and updates the leaderboards
+func (s *ProblemService) InvalidateSubmission(ctx context.Context, req *model.InvalidateSubmissionRequest) (*model.InvalidateSubmissionResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting InvalidateSubmission", map[string]any{
+		"method":       "InvalidateSubmission",
+		"submissionId": req.SubmissionID,
+	}, "SERVICE", nil)
+
+	adminID, err := s.requireAdmin(ctx, traceID, "InvalidateSubmission")
+	if err != nil {
+		return nil, err
+	}
+	if req.SubmissionID == "" || req.Reason == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing required fields", map[string]any{
+			"method":    "InvalidateSubmission",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Submission ID and reason are required", "VALIDATION_ERROR", nil)
+	}
+
+	submission, scoreRevoked, err := s.RepoConnInstance.InvalidateSubmission(ctx, req.SubmissionID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Submission not found", map[string]any{
+			"method":       "InvalidateSubmission",
+			"submissionId": req.SubmissionID,
+			"errorType":    "NOT_FOUND",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.NotFound, "Submission not found", "NOT_FOUND", nil)
+	}
+	if errors.Is(err, repository.ErrSubmissionAlreadyInvalidated) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Submission already invalidated", map[string]any{
+			"method":       "InvalidateSubmission",
+			"submissionId": req.SubmissionID,
+			"errorType":    "ALREADY_EXISTS",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.AlreadyExists, "Submission already invalidated", "ALREADY_EXISTS", nil)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to invalidate submission", map[string]any{
+			"method":       "InvalidateSubmission",
+			"submissionId": req.SubmissionID,
+			"errorType":    "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to invalidate submission", "DB_ERROR", err)
+	}
+
+	if scoreRevoked > 0 {
+		if err := s.refreshUserOnLeaderboards(ctx, submission.UserID); err != nil {
+			// the next sync reconciles the boards, the invalidation itself already succeeded
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update leaderboards after invalidation", map[string]any{
+				"method":    "InvalidateSubmission",
+				"userId":    submission.UserID,
+				"errorType": "LEADERBOARD_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+
+	s.saveModerationAudit(ctx, traceID, model.ModerationAudit{
+		Action:       model.ModerationActionInvalidateSubmission,
+		AdminID:      adminID,
+		TargetUserID: submission.UserID,
+		SubmissionID: req.SubmissionID,
+		Reason:       req.Reason,
+		ScoreRevoked: scoreRevoked,
+		CreatedAt:    time.Now(),
+	})
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionInvalidateSubmission,
+		Actor:      adminID,
+		TargetType: model.AuditTargetSubmission,
+		TargetID:   req.SubmissionID,
+		Before:     map[string]any{"status": submission.Status, "score": submission.Score},
+		After:      map[string]any{"status": "INVALIDATED", "score": 0, "scoreRevoked": scoreRevoked, "reason": req.Reason},
+	})
+	s.invalidateUserSubmissionCaches(ctx, traceID, submission.UserID, submission.ProblemID)
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Submission invalidated successfully", map[string]any{
+		"method":       "InvalidateSubmission",
+		"submissionId": req.SubmissionID,
+		"userId":       submission.UserID,
+		"scoreRevoked": scoreRevoked,
+	}, "SERVICE", nil)
+	return &model.InvalidateSubmissionResponse{
+		SubmissionID: req.SubmissionID,
+		UserID:       submission.UserID,
+		ScoreRevoked: scoreRevoked,
+	}, nil
+}
+
+// BanUserFromLeaderboard removes a user from every leaderboard and keeps them off future syncs
+func (s *ProblemService) BanUserFromLeaderboard(ctx context.Context, req *model.BanUserFromLeaderboardRequest) (*model.BanUserFromLeaderboardResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting BanUserFromLeaderboard", map[string]any{
+		"method": "BanUserFromLeaderboard",
+		"userId": req.UserID,
+	}, "SERVICE", nil)
+
+	adminID, err := s.requireAdmin(ctx, traceID, "BanUserFromLeaderboard")
+	if err != nil {
+		return nil, err
+	}
+	if req.UserID == "" || req.Reason == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing required fields", map[string]any{
+			"method":    "BanUserFromLeaderboard",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID and reason are required", "VALIDATION_ERROR", nil)
+	}
+
+	err = s.RepoConnInstance.BanUserFromLeaderboard(ctx, model.LeaderboardBan{
+		UserID:    req.UserID,
+		AdminID:   adminID,
+		Reason:    req.Reason,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to ban user from leaderboard", map[string]any{
+			"method":    "BanUserFromLeaderboard",
+			"userId":    req.UserID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to ban user from leaderboard", "DB_ERROR", err)
+	}
+
+	// banned users have no totals left, so the refresh takes them off every board
+	if err := s.refreshUserOnLeaderboards(ctx, req.UserID); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to remove banned user from leaderboards", map[string]any{
+			"method":    "BanUserFromLeaderboard",
+			"userId":    req.UserID,
+			"errorType": "LEADERBOARD_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.saveModerationAudit(ctx, traceID, model.ModerationAudit{
+		Action:       model.ModerationActionBanFromLeaderboard,
+		AdminID:      adminID,
+		TargetUserID: req.UserID,
+		Reason:       req.Reason,
+		CreatedAt:    time.Now(),
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "User banned from leaderboard successfully", map[string]any{
+		"method": "BanUserFromLeaderboard",
+		"userId": req.UserID,
+	}, "SERVICE", nil)
+	return &model.BanUserFromLeaderboardResponse{UserID: req.UserID, Banned: true}, nil
+}
+
+func (s *ProblemService) saveModerationAudit(ctx context.Context, traceID string, audit model.ModerationAudit) {
+	if err := s.RepoConnInstance.SaveModerationAudit(ctx, audit); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save moderation audit", map[string]any{
+			"method":       "saveModerationAudit",
+			"action":       audit.Action,
+			"targetUserId": audit.TargetUserID,
+			"errorType":    "DB_ERROR",
+		}, "SERVICE", err)
+	}
+}
+
+func (s *ProblemService) invalidateUserSubmissionCaches(ctx context.Context, traceID, userID, problemID string) {
+	cacheKeys := []string{
+		fmt.Sprintf("submissions:%s:%s", problemID, userID),
+		fmt.Sprintf("stats:%s", userID),
+		fmt.Sprintf("language_stats:%s", userID),
+	}
+	for _, cacheKey := range cacheKeys {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    "invalidateUserSubmissionCaches",
+				"cacheKey":  cacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+}
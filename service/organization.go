@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	redisboard "github.com/lijuuu/RedisBoard"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	defaultOrganizationPageSize = 20
+	maxOrganizationPageSize     = 100
+	defaultOrganizationTopK     = 10
+	maxOrganizationTopK         = 100
+	maxOrganizationNameLength   = 100
+)
+
+// organizationSlugPattern keeps slugs usable in URLs
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// OrganizationLeaderboardNamespace is the RedisBoard namespace of the organization board, where each user's entity is
+// their organization ID; the prefix keeps it out of ForceClear on the all-time namespace
+func OrganizationLeaderboardNamespace(namespace string) string {
+	return "org:" + namespace
+}
+
+// EnableOrganizationLeaderboard ranks organization members on lb, which must use OrganizationLeaderboardNamespace.
+// Without it GetTopKOrganization always ranks from MongoDB.
+func (s *ProblemService) EnableOrganizationLeaderboard(lb *redisboard.Leaderboard) {
+	s.OrgLB = lb
+}
+
+// syncOrganizationLeaderboard writes the all-time totals of the given organization members to the organization
+// board, or of every member when userIDs is nil. Members without a total left (e.g. banned) are taken off it.
+func (s *ProblemService) syncOrganizationLeaderboard(ctx context.Context, userIDs []string) error {
+	if s.OrgLB == nil {
+		return nil
+	}
+	memberships, err := s.RepoConnInstance.GetOrganizationMemberships(ctx, userIDs)
+	if err != nil || len(memberships) == 0 {
+		return err
+	}
+	members := make([]string, 0, len(memberships))
+	for userID := range memberships {
+		members = append(members, userID)
+	}
+	totals, err := s.RepoConnInstance.GetUserTotalsMongo(ctx, members, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	ranked := make(map[string]bool, len(totals))
+	for i := range totals {
+		totals[i].Entity = memberships[totals[i].UserID]
+		ranked[totals[i].UserID] = true
+	}
+	if userIDs != nil {
+		for _, userID := range members {
+			if ranked[userID] {
+				continue
+			}
+			if err := s.OrgLB.RemoveUser(userID); err != nil {
+				return fmt.Errorf("failed to remove user %s from organization leaderboard: %w", userID, err)
+			}
+		}
+	}
+	return s.writeLeaderboardBatch(ctx, OrganizationLeaderboardNamespace(s.lbNamespace), totals)
+}
+
+// rebuildOrganizationLeaderboard clears the organization board and ranks every member again
+func (s *ProblemService) rebuildOrganizationLeaderboard(ctx context.Context, traceID string) {
+	if s.OrgLB == nil {
+		return
+	}
+	s.OrgLB.ForceClearLeaderBoardWithNamespacePrefix()
+	if err := s.syncOrganizationLeaderboard(ctx, nil); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to sync organization leaderboard to Redis", map[string]any{
+			"method":    "rebuildOrganizationLeaderboard",
+			"errorType": "LEADERBOARD_SYNC_FAILED",
+		}, "SERVICE", err)
+	}
+}
+
+// CreateOrganization registers a school, company or other group users can join with the returned join code
+func (s *ProblemService) CreateOrganization(ctx context.Context, req *model.CreateOrganizationRequest) (*model.CreateOrganizationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting CreateOrganization", map[string]any{
+		"method":  "CreateOrganization",
+		"slug":    req.Slug,
+		"kind":    req.Kind,
+		"adminId": req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "CreateOrganization",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	organization := model.Organization{
+		Slug: strings.ToLower(strings.TrimSpace(req.Slug)),
+		Name: strings.TrimSpace(req.Name),
+		Kind: strings.ToUpper(strings.TrimSpace(req.Kind)),
+	}
+	if err := validateOrganization(organization); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid organization", map[string]any{
+			"method":    "CreateOrganization",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, err.Error(), "VALIDATION_ERROR", err)
+	}
+
+	random := make([]byte, 9)
+	if _, err := rand.Read(random); err != nil {
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to generate join code", "INTERNAL_ERROR", err)
+	}
+	organization.JoinCode = base64.RawURLEncoding.EncodeToString(random)
+	organization.CreatedBy = req.AdminID
+	organization.CreatedAt = time.Now()
+
+	created, err := s.RepoConnInstance.CreateOrganization(ctx, organization)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to create organization", map[string]any{
+			"method":    "CreateOrganization",
+			"slug":      organization.Slug,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to create organization")
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionCreateOrganization,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetOrg,
+		TargetID:   created.ID.Hex(),
+		After:      map[string]any{"slug": created.Slug, "name": created.Name, "kind": created.Kind},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Organization created", map[string]any{
+		"method":         "CreateOrganization",
+		"organizationId": created.ID.Hex(),
+	}, "SERVICE", nil)
+	return &model.CreateOrganizationResponse{Organization: *created}, nil
+}
+
+// validateOrganization checks the slug, name and kind of a new organization
+func validateOrganization(organization model.Organization) error {
+	if !organizationSlugPattern.MatchString(organization.Slug) {
+		return customerrors.Validation("slug must be 1 to 64 lowercase letters, digits or inner hyphens")
+	}
+	if organization.Name == "" {
+		return customerrors.Validation("name is required")
+	}
+	if len(organization.Name) > maxOrganizationNameLength {
+		return customerrors.Validation("name must be at most %d characters", maxOrganizationNameLength)
+	}
+	if !slices.Contains(model.OrganizationKinds, organization.Kind) {
+		return customerrors.Validation("kind must be one of %s", strings.Join(model.OrganizationKinds, ", "))
+	}
+	return nil
+}
+
+// GetOrganization returns an organization and its member count; the join code is left out
+func (s *ProblemService) GetOrganization(ctx context.Context, req *model.GetOrganizationRequest) (*model.GetOrganizationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetOrganization", map[string]any{
+		"method":         "GetOrganization",
+		"organizationId": req.OrganizationID,
+	}, "SERVICE", nil)
+
+	if req.OrganizationID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Organization ID is required", "VALIDATION_ERROR", nil)
+	}
+	organization, err := s.RepoConnInstance.GetOrganization(ctx, req.OrganizationID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch organization", map[string]any{
+			"method":         "GetOrganization",
+			"organizationId": req.OrganizationID,
+			"errorType":      customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch organization")
+	}
+	members, err := s.RepoConnInstance.CountOrganizationMembers(ctx, req.OrganizationID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to count organization members", map[string]any{
+			"method":         "GetOrganization",
+			"organizationId": req.OrganizationID,
+			"errorType":      customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to count organization members")
+	}
+
+	organization.JoinCode = ""
+	return &model.GetOrganizationResponse{Organization: *organization, MemberCount: members}, nil
+}
+
+// ListOrganizations pages through organizations by name, optionally of one kind; join codes are left out
+func (s *ProblemService) ListOrganizations(ctx context.Context, req *model.ListOrganizationsRequest) (*model.ListOrganizationsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ListOrganizations", map[string]any{
+		"method":   "ListOrganizations",
+		"kind":     req.Kind,
+		"page":     req.Page,
+		"pageSize": req.PageSize,
+	}, "SERVICE", nil)
+
+	kind := strings.ToUpper(strings.TrimSpace(req.Kind))
+	if kind != "" && !slices.Contains(model.OrganizationKinds, kind) {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Kind must be one of "+strings.Join(model.OrganizationKinds, ", "), "VALIDATION_ERROR", nil)
+	}
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultOrganizationPageSize
+	}
+	if pageSize > maxOrganizationPageSize {
+		pageSize = maxOrganizationPageSize
+	}
+
+	organizations, total, err := s.RepoConnInstance.ListOrganizations(ctx, kind, (page-1)*pageSize, pageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list organizations", map[string]any{
+			"method":    "ListOrganizations",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list organizations")
+	}
+	for i := range organizations {
+		organizations[i].JoinCode = ""
+	}
+	return &model.ListOrganizationsResponse{Organizations: organizations, Total: total}, nil
+}
+
+// JoinOrganization moves a user into an organization with its join code, out of the one they were in before, and
+// ranks them on its board
+func (s *ProblemService) JoinOrganization(ctx context.Context, req *model.JoinOrganizationRequest) (*model.JoinOrganizationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting JoinOrganization", map[string]any{
+		"method":         "JoinOrganization",
+		"organizationId": req.OrganizationID,
+		"userId":         req.UserID,
+	}, "SERVICE", nil)
+
+	if req.OrganizationID == "" || req.UserID == "" || req.JoinCode == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Organization ID, user ID and join code are required", "VALIDATION_ERROR", nil)
+	}
+	organization, err := s.RepoConnInstance.GetOrganization(ctx, req.OrganizationID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch organization", map[string]any{
+			"method":         "JoinOrganization",
+			"organizationId": req.OrganizationID,
+			"errorType":      customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch organization")
+	}
+	if organization.JoinCode != req.JoinCode {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Wrong organization join code", map[string]any{
+			"method":         "JoinOrganization",
+			"organizationId": req.OrganizationID,
+			"userId":         req.UserID,
+			"errorType":      "PERMISSION_DENIED",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.PermissionDenied, "Wrong join code", "PERMISSION_DENIED", nil)
+	}
+
+	member := model.OrganizationMember{UserID: req.UserID, OrganizationID: req.OrganizationID, JoinedAt: time.Now()}
+	previous, err := s.RepoConnInstance.SetUserOrganization(ctx, member)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to join organization", map[string]any{
+			"method":         "JoinOrganization",
+			"organizationId": req.OrganizationID,
+			"userId":         req.UserID,
+			"errorType":      customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to join organization")
+	}
+
+	if err := s.moveUserOnOrganizationLeaderboard(ctx, req.UserID, previous != ""); err != nil {
+		// the next full sync reconciles the board, the membership itself is stored
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update organization leaderboard", map[string]any{
+			"method":    "JoinOrganization",
+			"userId":    req.UserID,
+			"errorType": "LEADERBOARD_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "User joined organization", map[string]any{
+		"method":                 "JoinOrganization",
+		"organizationId":         req.OrganizationID,
+		"previousOrganizationId": previous,
+		"userId":                 req.UserID,
+	}, "SERVICE", nil)
+	return &model.JoinOrganizationResponse{Member: member, PreviousOrganizationID: previous}, nil
+}
+
+// moveUserOnOrganizationLeaderboard ranks a user under their current organization, first taking them out of the
+// one they left since RedisBoard keeps an entity set per organization
+func (s *ProblemService) moveUserOnOrganizationLeaderboard(ctx context.Context, userID string, hadOrganization bool) error {
+	if s.OrgLB == nil {
+		return nil
+	}
+	if hadOrganization {
+		if err := s.OrgLB.RemoveUser(userID); err != nil {
+			return fmt.Errorf("failed to remove user %s from organization leaderboard: %w", userID, err)
+		}
+	}
+	return s.syncOrganizationLeaderboard(ctx, []string{userID})
+}
+
+// LeaveOrganization takes a user out of their organization and its board. Admins may remove any user.
+func (s *ProblemService) LeaveOrganization(ctx context.Context, req *model.LeaveOrganizationRequest) (*model.LeaveOrganizationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting LeaveOrganization", map[string]any{
+		"method":  "LeaveOrganization",
+		"userId":  req.UserID,
+		"adminId": req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+	organizationID, err := s.RepoConnInstance.RemoveUserOrganization(ctx, req.UserID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to leave organization", map[string]any{
+			"method":    "LeaveOrganization",
+			"userId":    req.UserID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to leave organization")
+	}
+	if organizationID == "" {
+		return &model.LeaveOrganizationResponse{}, nil
+	}
+
+	if s.OrgLB != nil {
+		if err := s.OrgLB.RemoveUser(req.UserID); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to remove user from organization leaderboard", map[string]any{
+				"method":    "LeaveOrganization",
+				"userId":    req.UserID,
+				"errorType": "LEADERBOARD_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+	if req.AdminID != "" && req.AdminID != req.UserID {
+		s.recordAudit(ctx, traceID, model.AuditEntry{
+			Action:     model.AuditActionRemoveOrgMember,
+			Actor:      req.AdminID,
+			TargetType: model.AuditTargetOrg,
+			TargetID:   organizationID,
+			Before:     map[string]any{"userId": req.UserID},
+		})
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "User left organization", map[string]any{
+		"method":         "LeaveOrganization",
+		"organizationId": organizationID,
+		"userId":         req.UserID,
+	}, "SERVICE", nil)
+	return &model.LeaveOrganizationResponse{OrganizationID: organizationID}, nil
+}
+
+// GetTopKOrganization ranks an organization's members by all-time score, from Redis and else from MongoDB
+func (s *ProblemService) GetTopKOrganization(ctx context.Context, req *model.GetTopKOrganizationRequest) (*model.GetTopKOrganizationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetTopKOrganization", map[string]any{
+		"method":         "GetTopKOrganization",
+		"organizationId": req.OrganizationID,
+		"k":              req.K,
+	}, "SERVICE", nil)
+
+	if req.OrganizationID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Organization ID is required", "VALIDATION_ERROR", nil)
+	}
+	k := int64(req.K)
+	if k <= 0 {
+		k = defaultOrganizationTopK
+	}
+	if k > maxOrganizationTopK {
+		k = maxOrganizationTopK
+	}
+	if _, err := s.RepoConnInstance.GetOrganization(ctx, req.OrganizationID); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch organization", map[string]any{
+			"method":         "GetTopKOrganization",
+			"organizationId": req.OrganizationID,
+			"errorType":      customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch organization")
+	}
+
+	if s.OrgLB != nil {
+		startRedis := time.Now()
+		key := OrganizationLeaderboardNamespace(s.lbNamespace) + ":entity:" + req.OrganizationID
+		members, err := s.RedisCacheClient.ZRevRangeWithScores(ctx, key, 0, k-1)
+		if err == nil && len(members) > 0 {
+			users := make([]model.RankedUserScore, len(members))
+			for i, m := range members {
+				userID, _ := m.Member.(string)
+				users[i] = model.RankedUserScore{UserID: userID, Score: m.Score, Entity: req.OrganizationID, Rank: int64(i) + 1}
+			}
+			s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved top K organization users from Redis", map[string]any{
+				"method":   "GetTopKOrganization",
+				"duration": time.Since(startRedis).String(),
+			}, "SERVICE", nil)
+			return &model.GetTopKOrganizationResponse{OrganizationID: req.OrganizationID, Users: users}, nil
+		}
+		s.logger.Log(zapcore.WarnLevel, traceID, "Redis miss for top K organization users", map[string]any{
+			"method":   "GetTopKOrganization",
+			"duration": time.Since(startRedis).String(),
+		}, "SERVICE", err)
+	}
+
+	memberIDs, err := s.RepoConnInstance.GetOrganizationMemberIDs(ctx, req.OrganizationID)
+	var users []model.RankedUserScore
+	if err == nil {
+		users, err = s.RepoConnInstance.GetUserTotalsMongo(ctx, memberIDs, time.Time{})
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch top K organization users from MongoDB", map[string]any{
+			"method":         "GetTopKOrganization",
+			"organizationId": req.OrganizationID,
+			"errorType":      customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch organization leaderboard")
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if users[i].Score != users[j].Score {
+			return users[i].Score > users[j].Score
+		}
+		return users[i].UserID < users[j].UserID
+	})
+	if int64(len(users)) > k {
+		users = users[:k]
+	}
+	for i := range users {
+		users[i].Entity = req.OrganizationID
+		users[i].Rank = int64(i) + 1
+	}
+	return &model.GetTopKOrganizationResponse{OrganizationID: req.OrganizationID, Users: users}, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/metrics"
+	"xcode/model"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap/zapcore"
+)
+
+// outboxRelayBatchSize bounds how many events are read from the outbox at a time
+const outboxRelayBatchSize = 100
+
+// outboxEventsRelayed counts outbox events by whether publishing them succeeded
+var outboxEventsRelayed = metrics.NewCounterVec("outbox_events_relayed_total", "outcome")
+
+// StartOutboxRelay publishes outbox events every interval, and right away when a request has just written some.
+// The returned stop ends the relay after the batch in progress; events still pending are left to the next start
+// or another replica.
+func (s *ProblemService) StartOutboxRelay(interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			// a batch is not cut off half way by stop, so a published event is also marked sent
+			s.runSingleton(context.WithoutCancel(ctx), "outbox_relay", outboxRelayLockTTL, false, s.relayOutbox)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.outboxWake:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// wakeOutboxRelay asks the relay to run now instead of at its next tick; it never blocks
+func (s *ProblemService) wakeOutboxRelay() {
+	select {
+	case s.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// relayOutbox publishes pending outbox events oldest first and marks them sent. It stops at the first event that
+// cannot be published, which keeps the order and leaves the rest pending until the broker is back. An event that
+// was published but could not be marked is published again, so consumers must tolerate duplicates; JetStream drops
+// them by MsgID within its duplicate window.
+func (s *ProblemService) relayOutbox(ctx context.Context) {
+	for {
+		events, err := s.RepoConnInstance.GetPendingOutboxEvents(ctx, outboxRelayBatchSize)
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, uuid.New().String(), "Failed to fetch pending outbox events", map[string]any{
+				"method":    "relayOutbox",
+				"errorType": "DB_ERROR",
+			}, "SERVICE", err)
+			return
+		}
+
+		for _, event := range events {
+			traceID := event.TraceID
+			if traceID == "" {
+				traceID = uuid.New().String()
+			}
+
+			if err := s.publishOutboxEvent(ctx, event); err != nil {
+				outboxEventsRelayed.Add("failed", 1)
+				s.logger.Log(zapcore.WarnLevel, traceID, "Failed to publish outbox event, will retry", map[string]any{
+					"method":    "relayOutbox",
+					"subject":   event.Subject,
+					"eventId":   event.ID.Hex(),
+					"attempts":  event.Attempts + 1,
+					"errorType": "NATS_ERROR",
+				}, "SERVICE", err)
+				if err := s.RepoConnInstance.RecordOutboxEventFailure(ctx, event.ID, err); err != nil {
+					s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to record outbox event failure", map[string]any{
+						"method":    "relayOutbox",
+						"eventId":   event.ID.Hex(),
+						"errorType": "DB_ERROR",
+					}, "SERVICE", err)
+				}
+				return
+			}
+			outboxEventsRelayed.Add("published", 1)
+
+			if err := s.RepoConnInstance.MarkOutboxEventSent(ctx, event.ID, time.Now()); err != nil {
+				s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to mark outbox event sent", map[string]any{
+					"method":    "relayOutbox",
+					"subject":   event.Subject,
+					"eventId":   event.ID.Hex(),
+					"errorType": "DB_ERROR",
+				}, "SERVICE", err)
+				return
+			}
+		}
+
+		if len(events) < outboxRelayBatchSize {
+			return
+		}
+	}
+}
+
+// publishOutboxEvent sends events with a MsgID through JetStream, which stores them before acknowledging, and the
+// rest on core NATS. Without JetStream every event goes out on core NATS.
+func (s *ProblemService) publishOutboxEvent(ctx context.Context, event model.OutboxEvent) error {
+	if s.JetStream != nil && event.MsgID != "" {
+		return s.JetStream.PublishEvent(ctx, event.Subject, event.Data, event.MsgID)
+	}
+	return s.NatsClient.Publish(event.Subject, event.Data)
+}
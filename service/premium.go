@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/interceptor"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// entitlementsHeader carries the caller's entitlements as a comma separated list when the gateway already knows
+	// them, e.g. "premium"; it saves asking the user service
+	entitlementsHeader = "x-user-entitlements"
+
+	premiumProblemIDsCacheKey = "premium_problem_ids"
+	userRoleCachePrefix       = "user_role:"
+
+	lockedDescription = "This is a premium problem. Upgrade to premium to read and solve it."
+)
+
+// defaultPremiumRoles are entitled to premium problems until SetPremiumRoles names others
+var defaultPremiumRoles = []string{model.ProblemTierPremium, adminRole}
+
+// SetPremiumRoles sets the user service roles that are entitled to premium problems; nil keeps the default
+func (s *ProblemService) SetPremiumRoles(roles []string) {
+	s.premiumRoles = roles
+}
+
+func (s *ProblemService) premiumRolesOrDefault() []string {
+	if len(s.premiumRoles) == 0 {
+		return defaultPremiumRoles
+	}
+	return s.premiumRoles
+}
+
+// SetProblemTier makes a problem free or premium
+func (s *ProblemService) SetProblemTier(ctx context.Context, req *model.SetProblemTierRequest) (*model.SetProblemTierResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting SetProblemTier", map[string]any{
+		"method":    "SetProblemTier",
+		"problemId": req.ProblemID,
+		"tier":      req.Tier,
+		"adminId":   req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "SetProblemTier",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if !slices.Contains(model.ProblemTiers, req.Tier) {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Tier must be one of "+strings.Join(model.ProblemTiers, ", "), "VALIDATION_ERROR", nil)
+	}
+
+	previous, err := s.RepoConnInstance.SetProblemTier(ctx, req.ProblemID, req.Tier)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to set problem tier", map[string]any{
+			"method":    "SetProblemTier",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to set problem tier")
+	}
+	if err := s.RedisCacheClient.Delete(ctx, premiumProblemIDsCacheKey); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    "SetProblemTier",
+			"cacheKey":  premiumProblemIDsCacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionSetProblemTier,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetProblem,
+		TargetID:   req.ProblemID,
+		Before:     map[string]any{"tier": previous},
+		After:      map[string]any{"tier": req.Tier},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem tier set", map[string]any{
+		"method":    "SetProblemTier",
+		"problemId": req.ProblemID,
+		"tier":      req.Tier,
+	}, "SERVICE", nil)
+	return &model.SetProblemTierResponse{ProblemID: req.ProblemID, Tier: req.Tier}, nil
+}
+
+// premiumProblemIDs returns the set of premium problem IDs, cached as briefly as single problems are
+func (s *ProblemService) premiumProblemIDs(ctx context.Context) (map[string]bool, error) {
+	ids, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, premiumProblemIDsCacheKey, s.cacheTTLs().Problem, func(ctx context.Context) ([]string, error) {
+		return s.RepoConnInstance.GetPremiumProblemIDs(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	premium := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		premium[id] = true
+	}
+	return premium, nil
+}
+
+// entitled reports whether the caller may open premium problems. Entitlements the gateway passes in metadata are
+// taken as they are; otherwise the role of the x-user-id user is looked up in the user service and cached.
+// Anonymous callers and failed lookups are not entitled, and a failed lookup is not cached.
+func (s *ProblemService) entitled(ctx context.Context, traceID string) bool {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(entitlementsHeader); len(values) > 0 {
+			for _, entitlement := range strings.Split(strings.Join(values, ","), ",") {
+				if strings.EqualFold(strings.TrimSpace(entitlement), model.ProblemTierPremium) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	userID := interceptor.UserIDFromMetadata(ctx)
+	if userID == "" {
+		return false
+	}
+	role, err := s.userRole(ctx, userID)
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to look up entitlements in user service", map[string]any{
+			"method":    "entitled",
+			"userId":    userID,
+			"errorType": "USER_SERVICE_ERROR",
+		}, "SERVICE", err)
+		return false
+	}
+	return slices.Contains(s.premiumRolesOrDefault(), role)
+}
+
+// userRole returns the user service role of userID, cached for as long as entitlements are
+func (s *ProblemService) userRole(ctx context.Context, userID string) (string, error) {
+	role, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, userRoleCachePrefix+userID, s.cacheTTLs().Entitlement, func(ctx context.Context) (string, error) {
+		if s.UserClient == nil {
+			return "", errors.New("user service client is not configured")
+		}
+		fetchCtx, cancel := context.WithTimeout(ctx, userProfileFetchTimeout)
+		defer cancel()
+		return s.UserClient.GetUserRole(fetchCtx, userID)
+	})
+	return role, err
+}
+
+// requirePremiumAccess returns a PermissionDenied error when problemID is premium and the caller is not entitled
+func (s *ProblemService) requirePremiumAccess(ctx context.Context, traceID, method, problemID string) error {
+	premium, err := s.premiumProblemIDs(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to load premium problems", map[string]any{
+			"method":    method,
+			"problemId": problemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return s.repoError(ctx, err, "Failed to check problem access")
+	}
+	if !premium[problemID] || s.entitled(ctx, traceID) {
+		return nil
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Premium problem requested without entitlement", map[string]any{
+		"method":    method,
+		"problemId": problemID,
+		"errorType": "PREMIUM_REQUIRED",
+	}, "SERVICE", nil)
+	return s.createGrpcError(ctx, codes.PermissionDenied, "This problem requires a premium subscription", "PREMIUM_REQUIRED", nil)
+}
+
+// premiumToLock returns which of problemIDs are premium when the caller is not entitled to them, and nil when
+// nothing has to be locked
+func (s *ProblemService) premiumToLock(ctx context.Context, traceID, method string, problemIDs []string) (map[string]bool, error) {
+	premium, err := s.premiumProblemIDs(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to load premium problems", map[string]any{
+			"method":    method,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to check problem access")
+	}
+	listsPremium := slices.ContainsFunc(problemIDs, func(id string) bool { return premium[id] })
+	if !listsPremium || s.entitled(ctx, traceID) {
+		return nil, nil
+	}
+	return premium, nil
+}
+
+// lockPremiumProblems returns resp with the statement, run test cases and templates of premium problems removed
+// when the caller is not entitled, so lists still show that they exist. resp may be shared through the cache, so a
+// locked copy is returned rather than changing it.
+func (s *ProblemService) lockPremiumProblems(ctx context.Context, traceID, method string, resp *pb.GetProblemMetadataListResponse) (*pb.GetProblemMetadataListResponse, error) {
+	ids := make([]string, 0, len(resp.GetProblemmetdata()))
+	for _, problem := range resp.GetProblemmetdata() {
+		ids = append(ids, problem.GetProblemId())
+	}
+	premium, err := s.premiumToLock(ctx, traceID, method, ids)
+	if err != nil || premium == nil {
+		return resp, err
+	}
+
+	locked := proto.Clone(resp).(*pb.GetProblemMetadataListResponse)
+	for _, problem := range locked.Problemmetdata {
+		if premium[problem.ProblemId] {
+			problem.Description = lockedDescription
+			problem.TestcaseRun = nil
+			problem.PlaceholderMaps = nil
+		}
+	}
+	return locked, nil
+}
+
+// lockPremiumProblemPage is lockPremiumProblems for a ListProblems page, which also carries test cases and the
+// code each language is validated with
+func (s *ProblemService) lockPremiumProblemPage(ctx context.Context, traceID, method string, resp *pb.ListProblemsResponse) (*pb.ListProblemsResponse, error) {
+	ids := make([]string, 0, len(resp.GetProblems()))
+	for _, problem := range resp.GetProblems() {
+		ids = append(ids, problem.GetProblemId())
+	}
+	premium, err := s.premiumToLock(ctx, traceID, method, ids)
+	if err != nil || premium == nil {
+		return resp, err
+	}
+
+	locked := proto.Clone(resp).(*pb.ListProblemsResponse)
+	for _, problem := range locked.Problems {
+		if premium[problem.ProblemId] {
+			problem.Description = lockedDescription
+			problem.Testcases = nil
+			problem.ValidateCode = nil
+		}
+	}
+	return locked, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"xcode/model"
+	"xcode/repository"
+	"xcode/repository/mocks"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	premiumProblemID = primitive.NewObjectID()
+	freeProblemID    = primitive.NewObjectID()
+)
+
+// premiumCallers are a free and a premium caller, told apart by the entitlements the gateway forwards
+var premiumCallers = []struct {
+	name     string
+	entitled bool
+	ctx      context.Context
+}{
+	{name: "free", entitled: false, ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "free-user", entitlementsHeader, "basic"))},
+	{name: "premium", entitled: true, ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "premium-user", entitlementsHeader, "premium"))},
+}
+
+func premiumTestProblem(id primitive.ObjectID) *model.Problem {
+	return &model.Problem{
+		ID:          id,
+		Title:       "Two Sum",
+		Description: "Find two numbers adding up to target.",
+		Difficulty:  "E",
+		TestCases: model.TestCaseCollection{
+			Run: []model.TestCase{{ID: "run-1", Input: "[1,2]", Expected: "3"}},
+		},
+		ValidateCode: map[string]model.CodeData{"go": {Placeholder: "func twoSum() {}", Code: "reference"}},
+	}
+}
+
+func newPremiumTestService(t *testing.T) (*ProblemService, *mocks.MockProblemRepository) {
+	t.Helper()
+	repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+	repo.EXPECT().GetPremiumProblemIDs(gomock.Any()).Return([]string{premiumProblemID.Hex()}, nil).AnyTimes()
+	return newTestService(t, repo), repo
+}
+
+func TestGetProblemPremiumGate(t *testing.T) {
+	for _, caller := range premiumCallers {
+		for _, id := range []primitive.ObjectID{premiumProblemID, freeProblemID} {
+			premium := id == premiumProblemID
+			name := caller.name + "/free problem"
+			if premium {
+				name = caller.name + "/premium problem"
+			}
+			t.Run(name, func(t *testing.T) {
+				s, repo := newPremiumTestService(t)
+				allowed := caller.entitled || !premium
+				if allowed {
+					repo.EXPECT().GetProblem(gomock.Any(), gomock.Any()).Return(premiumTestProblem(id), nil).MinTimes(1)
+				}
+
+				resp, err := s.GetProblem(caller.ctx, &pb.GetProblemRequest{ProblemId: id.Hex()})
+				if !allowed {
+					if status.Code(err) != codes.PermissionDenied {
+						t.Fatalf("err = %v, want PermissionDenied", err)
+					}
+					if reason := errorInfo(t, err).Reason; reason != "PREMIUM_REQUIRED" {
+						t.Errorf("reason = %q, want PREMIUM_REQUIRED", reason)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("GetProblem: %v", err)
+				}
+				if resp.GetProblem().GetDescription() != "Find two numbers adding up to target." {
+					t.Errorf("description = %q, want the statement", resp.GetProblem().GetDescription())
+				}
+			})
+		}
+	}
+}
+
+func TestListProblemsLocksPremiumProblems(t *testing.T) {
+	for _, caller := range premiumCallers {
+		t.Run(caller.name, func(t *testing.T) {
+			s, repo := newPremiumTestService(t)
+			repo.EXPECT().GetHiddenProblemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			repo.EXPECT().ListProblems(gomock.Any(), gomock.Any(), gomock.Any()).Return(&pb.ListProblemsResponse{
+				Problems: []*pb.Problem{
+					repository.ToProblem(*premiumTestProblem(premiumProblemID)),
+					repository.ToProblem(*premiumTestProblem(freeProblemID)),
+				},
+				TotalCount: 2,
+			}, nil)
+
+			resp, err := s.ListProblems(caller.ctx, &pb.ListProblemsRequest{Page: 1, PageSize: 10})
+			if err != nil {
+				t.Fatalf("ListProblems: %v", err)
+			}
+			if len(resp.Problems) != 2 {
+				t.Fatalf("got %d problems, want both listed", len(resp.Problems))
+			}
+			premium, free := resp.Problems[0], resp.Problems[1]
+			if free.Description == lockedDescription || free.Testcases == nil {
+				t.Errorf("free problem was locked: %v", free)
+			}
+			if caller.entitled {
+				if premium.Description == lockedDescription || premium.Testcases == nil || premium.ValidateCode == nil {
+					t.Errorf("premium problem locked for an entitled caller: %v", premium)
+				}
+				return
+			}
+			if premium.Description != lockedDescription || premium.Testcases != nil || premium.ValidateCode != nil {
+				t.Errorf("premium problem not locked for a free caller: %v", premium)
+			}
+			if premium.Title != "Two Sum" {
+				t.Errorf("title = %q, want the premium problem still listed by name", premium.Title)
+			}
+		})
+	}
+}
+
+func TestRunUserCodeProblemPremiumGate(t *testing.T) {
+	for _, caller := range premiumCallers {
+		t.Run(caller.name, func(t *testing.T) {
+			s, repo := newPremiumTestService(t)
+			if caller.entitled {
+				repo.EXPECT().GetProblem(gomock.Any(), gomock.Any()).Return(premiumTestProblem(premiumProblemID), nil)
+			}
+
+			// an unsupported language stops the run before the engine, past the gate
+			resp, err := s.RunUserCodeProblem(caller.ctx, &pb.RunProblemRequest{
+				ProblemId: premiumProblemID.Hex(),
+				UserCode:  "print(1)",
+				Language:  "cobol",
+			})
+			if !caller.entitled {
+				if status.Code(err) != codes.PermissionDenied {
+					t.Fatalf("err = %v, want PermissionDenied", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunUserCodeProblem: %v", err)
+			}
+			if resp.ErrorType != "INVALID_LANGUAGE" {
+				t.Errorf("errorType = %q, want the run to reach language checks", resp.ErrorType)
+			}
+		})
+	}
+}
+
+func TestRunUserCodeProblemFreeProblemNeedsNoEntitlement(t *testing.T) {
+	s, repo := newPremiumTestService(t)
+	repo.EXPECT().GetProblem(gomock.Any(), gomock.Any()).Return(premiumTestProblem(freeProblemID), nil)
+
+	resp, err := s.RunUserCodeProblem(premiumCallers[0].ctx, &pb.RunProblemRequest{
+		ProblemId: freeProblemID.Hex(),
+		Language:  "cobol",
+	})
+	if err != nil {
+		t.Fatalf("RunUserCodeProblem: %v", err)
+	}
+	if resp.ErrorType != "INVALID_LANGUAGE" {
+		t.Errorf("errorType = %q, want INVALID_LANGUAGE", resp.ErrorType)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/repository"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// maxProblemsByIDs bounds one GetProblemsByIDs call, enough for any challenge
+const maxProblemsByIDs = 100
+
+// GetProblemsByIDs returns the metadata of the requested problems in one round trip, marking the IDs that were not
+// found instead of failing. Repeated IDs are answered once, and premium problems are locked as in
+// GetProblemMetadataList.
+func (s *ProblemService) GetProblemsByIDs(ctx context.Context, req *model.GetProblemsByIDsRequest) (*model.GetProblemsByIDsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetProblemsByIDs", map[string]any{
+		"method": "GetProblemsByIDs",
+		"count":  len(req.ProblemIDs),
+	}, "SERVICE", nil)
+
+	var ids []string
+	seen := make(map[string]bool, len(req.ProblemIDs))
+	for _, id := range req.ProblemIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "At least one problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if len(ids) > maxProblemsByIDs {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("At most %d problem IDs can be requested at once", maxProblemsByIDs), "VALIDATION_ERROR", nil)
+	}
+
+	problems, err := s.RepoConnInstance.GetProblemsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problems", map[string]any{
+			"method":    "GetProblemsByIDs",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problems")
+	}
+	found := &pb.GetProblemMetadataListResponse{Problemmetdata: make([]*pb.ProblemMetadataLite, len(problems))}
+	for i, problem := range problems {
+		found.Problemmetdata[i] = repository.ToProblemMetadataLite(problem)
+	}
+	if found, err = s.lockPremiumProblems(ctx, traceID, "GetProblemsByIDs", found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*pb.ProblemMetadataLite, len(found.Problemmetdata))
+	for _, problem := range found.Problemmetdata {
+		byID[problem.ProblemId] = problem
+	}
+	lookups := make([]model.ProblemLookup, len(ids))
+	for i, id := range ids {
+		problem, ok := byID[id]
+		lookups[i] = model.ProblemLookup{ProblemID: id, Found: ok, Problem: problem}
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problems retrieved by ID", map[string]any{
+		"method":    "GetProblemsByIDs",
+		"requested": len(ids),
+		"found":     len(problems),
+	}, "SERVICE", nil)
+	return &model.GetProblemsByIDsResponse{Problems: lookups}, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"xcode/model"
+
+	"github.com/google/uuid"
+)
+
+const eventSource = "problem-service"
+
+// problemOutboxEvent wraps a problem change in an envelope for problems.events.*; the subject is the event type
+func problemOutboxEvent(traceID, eventType string, event model.ProblemEvent) (model.OutboxEvent, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+	envelope, err := json.Marshal(model.EventEnvelope{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Version:    model.EventEnvelopeVersion,
+		Source:     eventSource,
+		OccurredAt: time.Now(),
+		TraceID:    traceID,
+		Data:       data,
+	})
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+	return model.OutboxEvent{Subject: eventType, Data: envelope, TraceID: traceID}, nil
+}
+
+// enqueueProblemEvent writes a problem event to the outbox, from where the relay publishes it. Called with a ctx
+// from WithTransaction the event is only kept when the change it announces is committed.
+func (s *ProblemService) enqueueProblemEvent(ctx context.Context, traceID, eventType string, event model.ProblemEvent) error {
+	outboxEvent, err := problemOutboxEvent(traceID, eventType, event)
+	if err != nil {
+		return err
+	}
+	return s.RepoConnInstance.SaveOutboxEvents(ctx, outboxEvent)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/utils"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	minProblemReportLength = 10
+	maxProblemReportLength = 5000
+
+	defaultProblemReportPageSize = 20
+	maxProblemReportPageSize     = 100
+
+	// a user may file a few reports in a row, then one every five minutes
+	problemReportRate  = 1.0 / 300
+	problemReportBurst = 5
+)
+
+// problemReportTransitions lists the statuses a report may move to from each status
+var problemReportTransitions = map[string][]string{
+	model.ProblemReportOpen:    {model.ProblemReportTriaged, model.ProblemReportFixed},
+	model.ProblemReportTriaged: {model.ProblemReportFixed},
+}
+
+// ReportProblemIssue files a user's report of a wrong test case, an unclear statement, a broken template or
+// anything else wrong with a problem, against the problem's current revision
+func (s *ProblemService) ReportProblemIssue(ctx context.Context, req *model.ReportProblemIssueRequest) (*model.ReportProblemIssueResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ReportProblemIssue", map[string]any{
+		"method":    "ReportProblemIssue",
+		"problemId": req.ProblemID,
+		"userId":    req.UserID,
+		"category":  req.Category,
+	}, "SERVICE", nil)
+
+	description := strings.TrimSpace(req.Description)
+	language := utils.NormalizeLanguage(req.Language)
+	switch {
+	case req.ProblemID == "" || req.UserID == "":
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and user ID are required", "VALIDATION_ERROR", nil)
+	case !slices.Contains(model.ProblemReportCategories, req.Category):
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Category must be one of "+strings.Join(model.ProblemReportCategories, ", "), "VALIDATION_ERROR", nil)
+	case req.Category == model.ProblemReportBrokenTemplate && language == "":
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Language is required for a broken template", "VALIDATION_ERROR", nil)
+	case len(description) < minProblemReportLength || len(description) > maxProblemReportLength:
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("Description must be %d to %d characters", minProblemReportLength, maxProblemReportLength), "VALIDATION_ERROR", nil)
+	}
+
+	problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "ReportProblemIssue",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+
+	if err := s.throttle(ctx, traceID, "ReportProblemIssue", "problem_report:"+req.UserID, problemReportRate, problemReportBurst); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report, err := s.RepoConnInstance.CreateProblemReport(ctx, model.ProblemReport{
+		ProblemID:       req.ProblemID,
+		UserID:          req.UserID,
+		Category:        req.Category,
+		Language:        language,
+		Description:     description,
+		Status:          model.ProblemReportOpen,
+		ProblemRevision: problem.UpdatedAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to create problem report", map[string]any{
+			"method":    "ReportProblemIssue",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to create problem report")
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem report filed", map[string]any{
+		"method":    "ReportProblemIssue",
+		"reportId":  report.ID.Hex(),
+		"problemId": req.ProblemID,
+	}, "SERVICE", nil)
+	return &model.ReportProblemIssueResponse{Report: *report}, nil
+}
+
+// ListProblemReports pages through problem reports oldest first, optionally filtered by problem, status and category
+func (s *ProblemService) ListProblemReports(ctx context.Context, req *model.ListProblemReportsRequest) (*model.ListProblemReportsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ListProblemReports", map[string]any{
+		"method":    "ListProblemReports",
+		"adminId":   req.AdminID,
+		"problemId": req.ProblemID,
+		"status":    req.Status,
+		"page":      req.Page,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "ListProblemReports",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultProblemReportPageSize
+	}
+	if pageSize > maxProblemReportPageSize {
+		pageSize = maxProblemReportPageSize
+	}
+
+	filter := model.ProblemReportFilter{ProblemID: req.ProblemID, Status: req.Status, Category: req.Category}
+	reports, total, err := s.RepoConnInstance.ListProblemReports(ctx, filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list problem reports", map[string]any{
+			"method":    "ListProblemReports",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list problem reports")
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem reports retrieved successfully", map[string]any{
+		"method": "ListProblemReports",
+		"count":  len(reports),
+		"total":  total,
+	}, "SERVICE", nil)
+	return &model.ListProblemReportsResponse{Reports: reports, Total: total}, nil
+}
+
+// UpdateProblemReportStatus moves a report along OPEN, TRIAGED, FIXED. A report can only be marked fixed once the
+// problem changed after it was filed; the revision that fixed it is recorded on the report.
+func (s *ProblemService) UpdateProblemReportStatus(ctx context.Context, req *model.UpdateProblemReportStatusRequest) (*model.UpdateProblemReportStatusResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting UpdateProblemReportStatus", map[string]any{
+		"method":   "UpdateProblemReportStatus",
+		"reportId": req.ReportID,
+		"adminId":  req.AdminID,
+		"status":   req.Status,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "UpdateProblemReportStatus",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.ReportID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Report ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	report, err := s.RepoConnInstance.GetProblemReport(ctx, req.ReportID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem report", map[string]any{
+			"method":    "UpdateProblemReportStatus",
+			"reportId":  req.ReportID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem report")
+	}
+	if !slices.Contains(problemReportTransitions[report.Status], req.Status) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid report status transition", map[string]any{
+			"method":    "UpdateProblemReportStatus",
+			"reportId":  req.ReportID,
+			"from":      report.Status,
+			"to":        req.Status,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, fmt.Sprintf("A %s report cannot be moved to %q", report.Status, req.Status), "VALIDATION_ERROR", nil)
+	}
+
+	var fixedRevision *time.Time
+	if req.Status == model.ProblemReportFixed {
+		problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: report.ProblemID})
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+				"method":    "UpdateProblemReportStatus",
+				"problemId": report.ProblemID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			return nil, s.repoError(ctx, err, "Failed to fetch problem")
+		}
+		if !problem.UpdatedAt.After(report.ProblemRevision) {
+			return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "The problem has not changed since it was reported", "NOT_FIXED", nil)
+		}
+		fixedRevision = &problem.UpdatedAt
+	}
+
+	updated, err := s.RepoConnInstance.UpdateProblemReportStatus(ctx, req.ReportID, report.Status, req.Status, strings.TrimSpace(req.Note), req.AdminID, fixedRevision)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update problem report", map[string]any{
+			"method":    "UpdateProblemReportStatus",
+			"reportId":  req.ReportID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to update problem report")
+	}
+
+	after := map[string]any{"status": updated.Status}
+	if fixedRevision != nil {
+		after["fixedRevision"] = *fixedRevision
+	}
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionUpdateProblemReport,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetReport,
+		TargetID:   req.ReportID,
+		Before:     map[string]any{"status": report.Status},
+		After:      after,
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem report updated", map[string]any{
+		"method":   "UpdateProblemReportStatus",
+		"reportId": req.ReportID,
+		"status":   updated.Status,
+	}, "SERVICE", nil)
+	return &model.UpdateProblemReportStatusResponse{Report: *updated}, nil
+}
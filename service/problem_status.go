@@ -0,0 +1,78 @@
+package service
+
+import (
+	"cmp"
+	"context"
+
+	"xcode/customerrors"
+	"xcode/interceptor"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+)
+
+// ListProblemsWithStatus is ListProblems for UserID with their SOLVED, ATTEMPTED or UNTOUCHED status on every
+// problem of the page, so a problems table can show its checkmarks without a call per problem
+func (s *ProblemService) ListProblemsWithStatus(ctx context.Context, req *model.ListProblemsWithStatusRequest) (*model.ListProblemsWithStatusResponse, error) {
+	listReq := req.Request
+	if listReq == nil {
+		listReq = &pb.ListProblemsRequest{}
+	}
+	resp, err := s.listProblems(ctx, listReq, cmp.Or(req.UserID, interceptor.UserIDFromMetadata(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	problemIDs := make([]string, len(resp.Problems))
+	for i, problem := range resp.Problems {
+		problemIDs[i] = problem.ProblemId
+	}
+	return &model.ListProblemsWithStatusResponse{
+		Problems: resp,
+		Statuses: s.problemStatuses(ctx, "ListProblemsWithStatus", req.UserID, problemIDs),
+	}, nil
+}
+
+// GetProblemMetadataListWithStatus is GetProblemMetadataList with the user's status on every problem of the page,
+// see ListProblemsWithStatus
+func (s *ProblemService) GetProblemMetadataListWithStatus(ctx context.Context, req *model.GetProblemMetadataListWithStatusRequest) (*model.GetProblemMetadataListWithStatusResponse, error) {
+	listReq := req.Request
+	if listReq == nil {
+		listReq = &pb.GetProblemMetadataListRequest{}
+	}
+	resp, err := s.GetProblemMetadataList(ctx, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	problemIDs := make([]string, len(resp.Problemmetdata))
+	for i, problem := range resp.Problemmetdata {
+		problemIDs[i] = problem.ProblemId
+	}
+	return &model.GetProblemMetadataListWithStatusResponse{
+		Problems: resp,
+		Statuses: s.problemStatuses(ctx, "GetProblemMetadataListWithStatus", req.UserID, problemIDs),
+	}, nil
+}
+
+// problemStatuses returns the user's status on each problem, for userID or else the caller's x-user-id. Statuses
+// only decorate a list, so nil is returned rather than an error when there is no user or they cannot be loaded.
+func (s *ProblemService) problemStatuses(ctx context.Context, method, userID string, problemIDs []string) map[string]string {
+	if userID == "" {
+		userID = interceptor.UserIDFromMetadata(ctx)
+	}
+	if userID == "" || len(problemIDs) == 0 {
+		return nil
+	}
+	statuses, err := s.RepoConnInstance.GetProblemStatuses(ctx, userID, problemIDs)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceIDFromContext(ctx), "Failed to load problem statuses, listing without them", map[string]any{
+			"method":    method,
+			"userId":    userID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil
+	}
+	return statuses
+}
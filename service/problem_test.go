@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"xcode/repository"
+	"xcode/repository/mocks"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestProblemNotFound(t *testing.T) {
+	problemID := primitive.NewObjectID().Hex()
+	tests := []struct {
+		name string
+		call func(s *ProblemService, ctx context.Context) error
+	}{
+		{
+			name: "GetProblem",
+			call: func(s *ProblemService, ctx context.Context) error {
+				_, err := s.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: problemID})
+				return err
+			},
+		},
+		{
+			name: "RunUserCodeProblem",
+			call: func(s *ProblemService, ctx context.Context) error {
+				_, err := s.RunUserCodeProblem(ctx, &pb.RunProblemRequest{ProblemId: problemID, UserCode: "print(1)", Language: "python"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockProblemRepository(gomock.NewController(t))
+			repo.EXPECT().GetPremiumProblemIDs(gomock.Any()).Return(nil, nil).AnyTimes()
+			// what the repository returns for a missing or soft deleted problem
+			repo.EXPECT().GetProblem(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%w %s", repository.ErrProblemNotFound, problemID))
+			s := newTestService(t, repo)
+
+			err := tt.call(s, premiumCallers[1].ctx)
+			if status.Code(err) != codes.NotFound {
+				t.Fatalf("err = %v, want NotFound", err)
+			}
+			if reason := errorInfo(t, err).Reason; reason != "NOT_FOUND" {
+				t.Errorf("reason = %q, want NOT_FOUND", reason)
+			}
+		})
+	}
+}
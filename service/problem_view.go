@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/interceptor"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// problemViewHeader is read from gRPC metadata since the problem requests have no field for it. "full" asks for
+// the reference solutions and submit test cases, "lite" leaves them out; without it callers get the most they may see.
+const problemViewHeader = "x-problem-view"
+
+const (
+	problemViewFull = "full"
+	problemViewLite = "lite"
+)
+
+func problemMaintainersCacheKey(problemID string) string {
+	return "problem_maintainers:" + problemID
+}
+
+// problemView returns the view of problemID the caller gets. The full view is for admins, either by role or API
+// key scope, and for the problem's maintainers; lists pass no problemID and are full for admins alone. Asking for
+// the full view without being allowed it is a PermissionDenied error rather than a quiet downgrade.
+func (s *ProblemService) problemView(ctx context.Context, traceID, method, problemID string) (string, error) {
+	requested := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(problemViewHeader); len(values) > 0 {
+			requested = strings.ToLower(strings.TrimSpace(values[0]))
+		}
+	}
+	switch requested {
+	case problemViewLite:
+		return problemViewLite, nil
+	case "", problemViewFull:
+	default:
+		return "", s.createGrpcError(ctx, codes.InvalidArgument, "Problem view must be full or lite", "VALIDATION_ERROR", nil)
+	}
+
+	if s.mayViewFullProblem(ctx, traceID, method, problemID) {
+		return problemViewFull, nil
+	}
+	if requested == problemViewFull {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Full problem view requested without permission", map[string]any{
+			"method":    method,
+			"problemId": problemID,
+			"errorType": "PERMISSION_DENIED",
+		}, "SERVICE", nil)
+		return "", s.createGrpcError(ctx, codes.PermissionDenied, "Only admins and the problem's maintainers may see reference solutions", "PERMISSION_DENIED", nil)
+	}
+	return problemViewLite, nil
+}
+
+// mayViewFullProblem reports whether the caller may see reference solutions and submit test cases. Failed lookups
+// deny, so a user service outage hides reference code rather than exposing it.
+func (s *ProblemService) mayViewFullProblem(ctx context.Context, traceID, method, problemID string) bool {
+	if key := interceptor.APIKeyFromContext(ctx); key != nil && key.HasScope(model.APIKeyScopeAdmin) {
+		return true
+	}
+	userID := interceptor.UserIDFromMetadata(ctx)
+	if userID == "" {
+		return false
+	}
+
+	if problemID != "" {
+		maintainers, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, problemMaintainersCacheKey(problemID), s.cacheTTLs().Problem, func(ctx context.Context) ([]string, error) {
+			problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: problemID})
+			if err != nil {
+				return nil, err
+			}
+			return append([]string{problem.CreatedBy}, problem.Maintainers...), nil
+		})
+		if err != nil {
+			s.logger.Log(zapcore.WarnLevel, traceID, "Failed to load problem maintainers", map[string]any{
+				"method":    method,
+				"problemId": problemID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+		}
+		if slices.Contains(maintainers, userID) {
+			return true
+		}
+	}
+
+	role, err := s.userRole(ctx, userID)
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to look up role in user service", map[string]any{
+			"method":    method,
+			"userId":    userID,
+			"errorType": "USER_SERVICE_ERROR",
+		}, "SERVICE", err)
+		return false
+	}
+	return role == adminRole
+}
+
+// liteValidationCode leaves only the placeholder each language starts users with; the code is the reference
+// solution and the template holds the judge's harness
+func liteValidationCode(validateCode map[string]*pb.ValidationCode) {
+	for language, code := range validateCode {
+		validateCode[language] = &pb.ValidationCode{Placeholder: code.GetPlaceholder()}
+	}
+}
+
+// liteProblem strips a problem down to what a solver may see, see liteValidationCode; submit test cases are hidden
+// so they cannot be hardcoded
+func liteProblem(problem *pb.Problem) {
+	if problem == nil {
+		return
+	}
+	liteValidationCode(problem.ValidateCode)
+	if problem.Testcases != nil {
+		problem.Testcases.Submit = nil
+	}
+}
+
+// problemForView returns resp as the caller may see it. resp may be shared through the cache, so a lite copy is
+// returned rather than changing it.
+func (s *ProblemService) problemForView(ctx context.Context, traceID, method string, resp *pb.GetProblemResponse) (*pb.GetProblemResponse, error) {
+	view, err := s.problemView(ctx, traceID, method, resp.GetProblem().GetProblemId())
+	if err != nil || view == problemViewFull {
+		return resp, err
+	}
+	lite := proto.Clone(resp).(*pb.GetProblemResponse)
+	liteProblem(lite.Problem)
+	return lite, nil
+}
+
+// problemsForView is problemForView for a page of problems
+func (s *ProblemService) problemsForView(ctx context.Context, traceID, method string, resp *pb.ListProblemsResponse) (*pb.ListProblemsResponse, error) {
+	view, err := s.problemView(ctx, traceID, method, "")
+	if err != nil || view == problemViewFull {
+		return resp, err
+	}
+	lite := proto.Clone(resp).(*pb.ListProblemsResponse)
+	for _, problem := range lite.Problems {
+		liteProblem(problem)
+	}
+	return lite, nil
+}
+
+// languageSupportsForView is problemForView for the languages of problemID
+func (s *ProblemService) languageSupportsForView(ctx context.Context, traceID, problemID string, resp *pb.GetLanguageSupportsResponse) (*pb.GetLanguageSupportsResponse, error) {
+	view, err := s.problemView(ctx, traceID, "GetLanguageSupports", problemID)
+	if err != nil || view == problemViewFull {
+		return resp, err
+	}
+	lite := proto.Clone(resp).(*pb.GetLanguageSupportsResponse)
+	liteValidationCode(lite.ValidateCode)
+	return lite, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	userProfileCachePrefix  = "user_profile:"
+	userProfileFetchTimeout = 3 * time.Second
+)
+
+// resolveUserProfiles returns display profiles for the given users, reading Redis first and asking the user service for the rest.
+// Lookup failures never fail the caller; unresolved users simply get an empty profile.
+func (s *ProblemService) resolveUserProfiles(ctx context.Context, traceID string, userIDs []string) map[string]model.UserProfileSummary {
+	profiles := make(map[string]model.UserProfileSummary, len(userIDs))
+	var missing []string
+	seen := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		cached, err := s.RedisCacheClient.Get(ctx, userProfileCachePrefix+userID)
+		if err == nil && cached != nil {
+			var profile model.UserProfileSummary
+			if cachedStr, ok := cached.(string); ok && json.Unmarshal([]byte(cachedStr), &profile) == nil {
+				profiles[userID] = profile
+				continue
+			}
+		}
+		missing = append(missing, userID)
+	}
+
+	if len(missing) == 0 || s.UserClient == nil {
+		return profiles
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, userProfileFetchTimeout)
+	defer cancel()
+	metadata, err := s.UserClient.GetBulkUserMetadata(fetchCtx, missing)
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to fetch user profiles from user service", map[string]any{
+			"method":    "resolveUserProfiles",
+			"userCount": len(missing),
+			"errorType": "USER_SERVICE_ERROR",
+		}, "SERVICE", err)
+		return profiles
+	}
+
+	for _, userID := range missing {
+		meta, ok := metadata[userID]
+		if !ok || meta.Exists == "false" {
+			continue
+		}
+		profile := model.UserProfileSummary{
+			UserName:   meta.UserName,
+			FirstName:  meta.FirstName,
+			LastName:   meta.LastName,
+			AvatarData: meta.AvatarData,
+		}
+		profiles[userID] = profile
+
+		profileBytes, err := json.Marshal(profile)
+		if err != nil {
+			continue
+		}
+		if err := s.RedisCacheClient.Set(ctx, userProfileCachePrefix+userID, profileBytes, s.cacheTTLs().Profile); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache user profile", map[string]any{
+				"method":    "resolveUserProfiles",
+				"userId":    userID,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+	return profiles
+}
+
+func enrichUserScores(users []*pb.UserScore, profiles map[string]model.UserProfileSummary) []model.EnrichedUserScore {
+	enriched := make([]model.EnrichedUserScore, 0, len(users))
+	for _, user := range users {
+		enriched = append(enriched, model.EnrichedUserScore{
+			UserID:  user.UserId,
+			Score:   user.Score,
+			Entity:  user.Entity,
+			Profile: profiles[user.UserId],
+		})
+	}
+	return enriched
+}
+
+func userScoreIDs(lists ...[]*pb.UserScore) []string {
+	var ids []string
+	for _, users := range lists {
+		for _, user := range users {
+			ids = append(ids, user.UserId)
+		}
+	}
+	return ids
+}
+
+// GetTopKGlobalWithProfiles is GetTopKGlobal with display names and avatars resolved for every user
+func (s *ProblemService) GetTopKGlobalWithProfiles(ctx context.Context, req *pb.GetTopKGlobalRequest) (*model.GetTopKWithProfilesResponse, error) {
+	resp, err := s.GetTopKGlobal(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	profiles := s.resolveUserProfiles(ctx, traceIDFromContext(ctx), userScoreIDs(resp.Users))
+	return &model.GetTopKWithProfilesResponse{Users: enrichUserScores(resp.Users, profiles)}, nil
+}
+
+// GetTopKEntityWithProfiles is GetTopKEntity with display names and avatars resolved for every user
+func (s *ProblemService) GetTopKEntityWithProfiles(ctx context.Context, req *pb.GetTopKEntityRequest) (*model.GetTopKWithProfilesResponse, error) {
+	resp, err := s.GetTopKEntity(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	profiles := s.resolveUserProfiles(ctx, traceIDFromContext(ctx), userScoreIDs(resp.Users))
+	return &model.GetTopKWithProfilesResponse{Users: enrichUserScores(resp.Users, profiles)}, nil
+}
+
+// GetLeaderboardDataWithProfiles is GetLeaderboardData with profiles for the user and both top-K lists
+func (s *ProblemService) GetLeaderboardDataWithProfiles(ctx context.Context, req *pb.GetLeaderboardDataRequest) (*model.GetLeaderboardDataWithProfilesResponse, error) {
+	resp, err := s.GetLeaderboardData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	ids := append(userScoreIDs(resp.TopKGlobal, resp.TopKEntity), resp.UserId)
+	profiles := s.resolveUserProfiles(ctx, traceIDFromContext(ctx), ids)
+	return &model.GetLeaderboardDataWithProfilesResponse{
+		UserID:     resp.UserId,
+		Score:      resp.Score,
+		Entity:     resp.Entity,
+		GlobalRank: resp.GlobalRank,
+		EntityRank: resp.EntityRank,
+		Profile:    profiles[resp.UserId],
+		TopKGlobal: enrichUserScores(resp.TopKGlobal, profiles),
+		TopKEntity: enrichUserScores(resp.TopKEntity, profiles),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"cmp"
+	"context"
+
+	"xcode/customerrors"
+	"xcode/interceptor"
+	"xcode/model"
+	"xcode/repository"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// GetRandomProblem picks a problem for the "pick one for me" button, optionally of a difficulty, with tags, and not
+// yet solved by a user. Callers without the premium entitlement are only offered free problems, and problems the
+// caller, or else the ExcludeSolvedForUser user, hid are never offered.
+func (s *ProblemService) GetRandomProblem(ctx context.Context, req *model.GetRandomProblemRequest) (*model.GetRandomProblemResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetRandomProblem", map[string]any{
+		"method":               "GetRandomProblem",
+		"difficulty":           req.Difficulty,
+		"tags":                 req.Tags,
+		"excludeSolvedForUser": req.ExcludeSolvedForUser,
+	}, "SERVICE", nil)
+
+	userID := cmp.Or(interceptor.UserIDFromMetadata(ctx), req.ExcludeSolvedForUser)
+	problem, err := s.RepoConnInstance.GetRandomProblem(ctx, model.RandomProblemFilter{
+		Difficulty:      req.Difficulty,
+		Tags:            req.Tags,
+		ExcludeSolvedBy: req.ExcludeSolvedForUser,
+		ExcludeIDs:      s.hiddenProblemsFor(ctx, traceID, "GetRandomProblem", userID),
+		ExcludePremium:  !s.entitled(ctx, traceID),
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to pick a random problem", map[string]any{
+			"method":    "GetRandomProblem",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to pick a random problem")
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Random problem picked", map[string]any{
+		"method":    "GetRandomProblem",
+		"problemId": problem.ID.Hex(),
+	}, "SERVICE", nil)
+	return &model.GetRandomProblemResponse{Problem: repository.ToProblemMetadataLite(*problem)}, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const rankChangeSubject = "leaderboard.rank.changed"
+
+// rankThresholds are the board positions whose crossing is worth notifying a user about
+var rankThresholds = []int64{10, 100}
+
+// userRanks holds 1-based global and entity ranks, 0 when unranked
+type userRanks struct {
+	global int64
+	entity int64
+	scope  string
+}
+
+// currentUserRanks reads the user's position on the all-time board; lookup errors count as unranked
+func (s *ProblemService) currentUserRanks(userID string) userRanks {
+	ranks := userRanks{}
+	if rank, err := s.LB.GetRankGlobal(userID); err == nil && rank >= 0 {
+		ranks.global = int64(rank) + 1
+	}
+	if entity, err := s.LB.GetUserEntity(userID); err == nil && entity != "" {
+		ranks.scope = entity
+		if rank, err := s.LB.GetRankEntity(userID); err == nil && rank >= 0 {
+			ranks.entity = int64(rank) + 1
+		}
+	}
+	return ranks
+}
+
+// publishRankChanges compares the user's ranks before and after a score update and publishes an ENTERED event for every
+// threshold the user climbed into, plus a LEFT event for whoever got pushed just below it
+func (s *ProblemService) publishRankChanges(ctx context.Context, traceID, userID string, before, after userRanks) {
+	s.publishScopeRankChanges(ctx, traceID, userID, model.LeaderboardScopeGlobal, s.lbNamespace+":global", before.global, after.global)
+	if after.scope != "" {
+		s.publishScopeRankChanges(ctx, traceID, userID, after.scope, s.lbNamespace+":entity:"+after.scope, before.entity, after.entity)
+	}
+}
+
+func (s *ProblemService) publishScopeRankChanges(ctx context.Context, traceID, userID, scope, key string, before, after int64) {
+	if after == 0 {
+		return
+	}
+	for _, threshold := range rankThresholds {
+		if after > threshold || (before != 0 && before <= threshold) {
+			continue
+		}
+		s.publishRankChangeEvent(traceID, model.RankChangeEvent{
+			UserID:       userID,
+			Scope:        scope,
+			Event:        model.RankEventEnteredThreshold,
+			Threshold:    threshold,
+			PreviousRank: before,
+			NewRank:      after,
+			OccurredAt:   time.Now(),
+		})
+
+		// the user now sitting right below the threshold is the one who was pushed out
+		displaced, err := s.RedisCacheClient.ZRevRangeWithScores(ctx, key, threshold, threshold)
+		if err != nil || len(displaced) == 0 {
+			continue
+		}
+		displacedID, _ := displaced[0].Member.(string)
+		if displacedID == "" || displacedID == userID {
+			continue
+		}
+		s.publishRankChangeEvent(traceID, model.RankChangeEvent{
+			UserID:       displacedID,
+			Scope:        scope,
+			Event:        model.RankEventLeftThreshold,
+			Threshold:    threshold,
+			PreviousRank: threshold,
+			NewRank:      threshold + 1,
+			OccurredAt:   time.Now(),
+		})
+	}
+}
+
+func (s *ProblemService) publishRankChangeEvent(traceID string, event model.RankChangeEvent) {
+	eventBytes, err := json.Marshal(event)
+	if err == nil {
+		err = s.NatsClient.Publish(rankChangeSubject, eventBytes)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to publish rank change event", map[string]any{
+			"method":    "publishRankChangeEvent",
+			"userId":    event.UserID,
+			"scope":     event.Scope,
+			"threshold": event.Threshold,
+			"errorType": "NATS_ERROR",
+		}, "SERVICE", err)
+		return
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Published rank change event", map[string]any{
+		"method":    "publishRankChangeEvent",
+		"userId":    event.UserID,
+		"scope":     event.Scope,
+		"event":     event.Event,
+		"threshold": event.Threshold,
+	}, "SERVICE", nil)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// rankPercentile is the share of ranked users placed below rank, rounded to two decimals
+func rankPercentile(rank, total int64) float64 {
+	if rank < 1 || total < 1 {
+		return 0
+	}
+	return math.Round(float64(total-rank)/float64(total)*10000) / 100
+}
+
+// GetUserRankSummary returns a user's 1-based global and entity ranks together with score, entity, the number of
+// ranked users and the percentile, so clients don't need separate score and count lookups
+func (s *ProblemService) GetUserRankSummary(ctx context.Context, req *model.GetUserRankRequest) (*model.GetUserRankResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetUserRankSummary", map[string]any{
+		"method": "GetUserRankSummary",
+		"userId": req.UserID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "User ID is required", map[string]any{
+			"method":    "GetUserRankSummary",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	resp := &model.GetUserRankResponse{}
+	if req.Username != nil {
+		resp.Username = *req.Username
+	}
+
+	startRedis := time.Now()
+	total, err := s.RedisCacheClient.ZCard(ctx, s.lbNamespace+":global")
+	if err == nil && total > 0 {
+		globalRank, errGlobal := s.LB.GetRankGlobal(req.UserID)
+		entityRank, errEntity := s.LB.GetRankEntity(req.UserID)
+		entity, errEntityName := s.LB.GetUserEntity(req.UserID)
+		if errGlobal == nil && errEntity == nil && errEntityName == nil {
+			resp.TotalRankedUsers = total
+			resp.Entity = entity
+			if globalRank >= 0 {
+				resp.GlobalRank = int64(globalRank) + 1
+				resp.Score, _ = s.LB.GetUserScore(req.UserID)
+			}
+			if entityRank >= 0 {
+				resp.CountryRank = int64(entityRank) + 1
+			}
+			resp.Percentile = rankPercentile(resp.GlobalRank, total)
+			s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved user rank summary from Redis", map[string]any{
+				"method":     "GetUserRankSummary",
+				"userId":     req.UserID,
+				"globalRank": resp.GlobalRank,
+				"duration":   time.Since(startRedis).String(),
+			}, "SERVICE", nil)
+			return s.withRankUsername(ctx, traceID, req.UserID, resp), nil
+		}
+	}
+	s.logger.Log(zapcore.WarnLevel, traceID, "Redis miss for user rank summary", map[string]any{
+		"method":   "GetUserRankSummary",
+		"userId":   req.UserID,
+		"duration": time.Since(startRedis).String(),
+	}, "SERVICE", err)
+
+	startMongo := time.Now()
+	standing, entityRank, total, err := s.RepoConnInstance.GetUserStandingMongo(ctx, req.UserID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch user rank summary from MongoDB", map[string]any{
+			"method":    "GetUserRankSummary",
+			"userId":    req.UserID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, fmt.Errorf("failed to fetch user rank summary from MongoDB: %w", err)
+	}
+	resp.GlobalRank = standing.Rank
+	resp.CountryRank = entityRank
+	resp.Score = standing.Score
+	resp.Entity = standing.Entity
+	resp.TotalRankedUsers = total
+	resp.Percentile = rankPercentile(standing.Rank, total)
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved user rank summary from MongoDB", map[string]any{
+		"method":     "GetUserRankSummary",
+		"userId":     req.UserID,
+		"globalRank": resp.GlobalRank,
+		"duration":   time.Since(startMongo).String(),
+	}, "SERVICE", nil)
+	return s.withRankUsername(ctx, traceID, req.UserID, resp), nil
+}
+
+// withRankUsername fills the username from the user service when the caller did not pass one
+func (s *ProblemService) withRankUsername(ctx context.Context, traceID, userID string, resp *model.GetUserRankResponse) *model.GetUserRankResponse {
+	if resp.Username == "" {
+		resp.Username = s.resolveUserProfiles(ctx, traceID, []string{userID})[userID].UserName
+	}
+	return resp
+}
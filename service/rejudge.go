@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/metrics"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// rejudgeJobStaleAfter is how long a queued or running rejudge may go without finishing a batch before it is
+	// considered abandoned and a new rejudge of the problem may start
+	rejudgeJobStaleAfter = 15 * time.Minute
+
+	// rejudgeBatchSize is how many submissions are rerun between progress updates
+	rejudgeBatchSize = 50
+)
+
+// rejudgedSubmissions counts rerun submissions by outcome: unchanged, changed or errored
+var rejudgedSubmissions = metrics.NewCounterVec("rejudged_submissions_total", "outcome")
+
+// RejudgeProblem queues a rerun of the problem's stored submissions against its current test cases, for when test
+// cases were fixed and old verdicts are stale. It returns at once with the job to poll through GetRejudgeJob. Flipped
+// verdicts move the users' first successes and scores, and the leaderboards follow. A problem has at most one active
+// rejudge; asking again returns it.
+func (s *ProblemService) RejudgeProblem(ctx context.Context, req *model.RejudgeProblemRequest) (*model.RejudgeProblemResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RejudgeProblem", map[string]any{
+		"method":    "RejudgeProblem",
+		"problemId": req.ProblemID,
+		"since":     req.Since,
+	}, "SERVICE", nil)
+
+	adminID, err := s.requireAdmin(ctx, traceID, "RejudgeProblem")
+	if err != nil {
+		return nil, err
+	}
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.Since != nil && req.Since.After(time.Now()) {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Since cannot be in the future", "VALIDATION_ERROR", nil)
+	}
+
+	if _, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID}); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "RejudgeProblem",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+
+	job, created, err := s.RepoConnInstance.CreateRejudgeJob(ctx, model.RejudgeJob{
+		ProblemID: req.ProblemID,
+		AdminID:   adminID,
+		Since:     req.Since,
+		TraceID:   traceID,
+	}, time.Now().Add(-rejudgeJobStaleAfter))
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to queue rejudge job", map[string]any{
+			"method":    "RejudgeProblem",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to queue rejudge")
+	}
+
+	if created {
+		queued := *job
+		s.runInBackground(ctx, func(ctx context.Context) {
+			s.runRejudgeJob(ctx, traceID, queued)
+		})
+		s.recordAudit(ctx, traceID, model.AuditEntry{
+			Action:     model.AuditActionRejudgeProblem,
+			Actor:      adminID,
+			TargetType: model.AuditTargetProblem,
+			TargetID:   req.ProblemID,
+			After:      map[string]any{"jobId": job.ID.Hex(), "since": req.Since},
+		})
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Rejudge job queued", map[string]any{
+		"method":    "RejudgeProblem",
+		"problemId": req.ProblemID,
+		"jobId":     job.ID.Hex(),
+		"created":   created,
+	}, "SERVICE", nil)
+	return &model.RejudgeProblemResponse{Job: *job, Created: created}, nil
+}
+
+// GetRejudgeJob returns a rejudge job with its progress so far
+func (s *ProblemService) GetRejudgeJob(ctx context.Context, req *model.GetRejudgeJobRequest) (*model.GetRejudgeJobResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if req.JobID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Job ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	job, err := s.RepoConnInstance.GetRejudgeJob(ctx, req.JobID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch rejudge job", map[string]any{
+			"method":    "GetRejudgeJob",
+			"jobId":     req.JobID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch rejudge job")
+	}
+	return &model.GetRejudgeJobResponse{Job: *job}, nil
+}
+
+// runRejudgeJob reruns the job's submissions a batch at a time, at most ValidationParallelism at once so a rejudge
+// cannot crowd out live submissions, then moves the first successes of every user with a flipped verdict and
+// refreshes them on the leaderboards. Finalized contest standings are not recomputed.
+func (s *ProblemService) runRejudgeJob(ctx context.Context, traceID string, job model.RejudgeJob) {
+	logFailure := func(msg string, err error) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, msg, map[string]any{
+			"method":    "runRejudgeJob",
+			"jobId":     job.ID.Hex(),
+			"problemId": job.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+	}
+	finish := func(status, message string, usersRescored int64) {
+		if err := s.RepoConnInstance.FinishRejudgeJob(ctx, job.ID, status, message, usersRescored); err != nil {
+			logFailure("Failed to finish rejudge job", err)
+		}
+		s.logger.Log(zapcore.InfoLevel, traceID, "Rejudge job finished", map[string]any{
+			"method":        "runRejudgeJob",
+			"jobId":         job.ID.Hex(),
+			"problemId":     job.ProblemID,
+			"status":        status,
+			"usersRescored": usersRescored,
+		}, "SERVICE", nil)
+	}
+
+	if _, err := s.RepoConnInstance.StartRejudgeJob(ctx, job); err != nil {
+		logFailure("Failed to start rejudge job", err)
+		finish(model.RejudgeJobFailed, "Could not count the submissions to rejudge", 0)
+		return
+	}
+
+	// users whose verdict on the problem flipped, whose first success may have moved
+	affected := make(map[string]bool)
+	var after primitive.ObjectID
+	for {
+		submissions, err := s.RepoConnInstance.GetRejudgeSubmissions(ctx, job.ProblemID, job.Since, after, rejudgeBatchSize)
+		if err != nil {
+			logFailure("Failed to fetch submissions to rejudge", err)
+			finish(model.RejudgeJobFailed, "Could not fetch the submissions to rejudge", s.rescoreUsers(ctx, traceID, job.ProblemID, affected))
+			return
+		}
+		if len(submissions) == 0 {
+			break
+		}
+		after = submissions[len(submissions)-1].ID
+
+		verdicts := make([]string, len(submissions))
+		var g errgroup.Group
+		g.SetLimit(max(s.engine.ValidationParallelism, 1))
+		for i, submission := range submissions {
+			g.Go(func() error {
+				verdict, err := s.rejudgeVerdict(ctx, submission)
+				if err != nil {
+					s.logger.Log(zapcore.WarnLevel, traceID, "Failed to rejudge submission, keeping its verdict", map[string]any{
+						"method":       "runRejudgeJob",
+						"submissionId": submission.ID.Hex(),
+						"errorType":    customerrors.ErrorType(err),
+					}, "SERVICE", err)
+					return nil
+				}
+				verdicts[i] = verdict
+				return nil
+			})
+		}
+		g.Wait()
+
+		var changed, errored int64
+		for i, submission := range submissions {
+			switch verdict := verdicts[i]; {
+			case verdict == "":
+				errored++
+				rejudgedSubmissions.Add("errored", 1)
+			case verdict == submission.Status:
+				rejudgedSubmissions.Add("unchanged", 1)
+			default:
+				if err := s.RepoConnInstance.SetSubmissionStatus(ctx, submission.ID, verdict); err != nil {
+					logFailure("Failed to store rejudged verdict", err)
+					errored++
+					rejudgedSubmissions.Add("errored", 1)
+					continue
+				}
+				changed++
+				affected[submission.UserID] = true
+				rejudgedSubmissions.Add("changed", 1)
+			}
+		}
+		if err := s.RepoConnInstance.RecordRejudgeProgress(ctx, job.ID, int64(len(submissions)), changed, errored); err != nil {
+			logFailure("Failed to record rejudge progress", err)
+		}
+	}
+
+	finish(model.RejudgeJobSucceeded, "", s.rescoreUsers(ctx, traceID, job.ProblemID, affected))
+}
+
+// rejudgeVerdict reruns a submission through the execution path without storing a new one and returns SUCCESS or
+// FAILED. An error means the code could not be judged at all, e.g. the engine was unavailable or the language is no
+// longer supported, and the stored verdict should stand.
+func (s *ProblemService) rejudgeVerdict(ctx context.Context, submission model.Submission) (string, error) {
+	// without a user ID runUserCodeProblem judges the code and stores nothing, as validation relies on too
+	res, err := s.runUserCodeProblem(ctx, &pb.RunProblemRequest{
+		ProblemId:     submission.ProblemID,
+		UserCode:      submission.UserCode,
+		Language:      submission.Language,
+		IsRunTestcase: false,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !res.Success {
+		switch res.ErrorType {
+		case model.CompilerErrorCompilation, model.CompilerErrorRuntime, model.CompilerErrorTimeLimit, model.CompilerErrorMemoryLimit:
+			return "FAILED", nil
+		}
+		return "", fmt.Errorf("%s: %s", res.ErrorType, res.Message)
+	}
+
+	// unparseable output fails the submission, as it did when it was first judged
+	var execution model.ExecutionStatsResult
+	if err := json.Unmarshal([]byte(res.Message), &execution); err != nil || !execution.OverallPass {
+		return "FAILED", nil
+	}
+	return "SUCCESS", nil
+}
+
+// rescoreUsers moves the first success of every user in affected to their earliest accepted submission of the
+// problem and refreshes the ones whose score changed on the leaderboards. It returns how many were rescored;
+// failures are logged and left for the next leaderboard sync.
+func (s *ProblemService) rescoreUsers(ctx context.Context, traceID, problemID string, affected map[string]bool) int64 {
+	var rescored int64
+	for userID := range affected {
+		changed, err := s.RepoConnInstance.ReconcileFirstSuccess(ctx, userID, problemID)
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to reconcile first success after rejudge", map[string]any{
+				"method":    "rescoreUsers",
+				"userId":    userID,
+				"problemId": problemID,
+				"errorType": customerrors.Type(err),
+			}, "SERVICE", err)
+			continue
+		}
+		if changed {
+			rescored++
+			if err := s.refreshUserOnLeaderboards(ctx, userID); err != nil {
+				s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update leaderboards after rejudge", map[string]any{
+					"method":    "rescoreUsers",
+					"userId":    userID,
+					"errorType": "LEADERBOARD_ERROR",
+				}, "SERVICE", err)
+			}
+		}
+		s.invalidateUserSubmissionCaches(ctx, traceID, userID, problemID)
+	}
+	return rescored
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/metrics"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// softDeletePurgeBatchSize bounds how many problems one purge transaction removes
+const softDeletePurgeBatchSize = 200
+
+// softDeletesPurged counts soft deleted documents removed for good, by collection
+var softDeletesPurged = metrics.NewCounterVec("soft_deletes_purged_total", "collection")
+
+// EnableSoftDeletePurge makes the service remove problems soft deleted longer than retention ago, copying them to
+// the archive first when archive is set. Purging is off until this is called.
+func (s *ProblemService) EnableSoftDeletePurge(retention time.Duration, archive bool) {
+	s.purgeRetention = retention
+	s.purgeArchive = archive
+}
+
+// PurgeSoftDeletedProblems removes problems whose soft delete is older than the retention window, batch by batch
+func (s *ProblemService) PurgeSoftDeletedProblems(ctx context.Context) error {
+	if s.purgeRetention <= 0 {
+		return nil
+	}
+	traceID := traceIDFromContext(ctx)
+	deletedBefore := time.Now().Add(-s.purgeRetention)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting PurgeSoftDeletedProblems", map[string]any{
+		"method":        "PurgeSoftDeletedProblems",
+		"deletedBefore": deletedBefore,
+		"archive":       s.purgeArchive,
+	}, "SERVICE", nil)
+
+	start := time.Now()
+	var total int64
+	for {
+		purged, err := s.RepoConnInstance.PurgeDeletedProblems(ctx, deletedBefore, s.purgeArchive, softDeletePurgeBatchSize)
+		total += purged
+		softDeletesPurged.Add("problems", purged)
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to purge soft deleted problems", map[string]any{
+				"method":    "PurgeSoftDeletedProblems",
+				"purged":    total,
+				"errorType": "DB_ERROR",
+			}, "SERVICE", err)
+			return err
+		}
+		if purged < softDeletePurgeBatchSize {
+			break
+		}
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Soft deleted problems purged", map[string]any{
+		"method":   "PurgeSoftDeletedProblems",
+		"purged":   total,
+		"duration": time.Since(start).Seconds(),
+	}, "SERVICE", nil)
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"xcode/cache"
+	"xcode/model"
+	"xcode/utils"
+	"xcode/validation"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	runtimesRequestSubject = "problems.runtimes.request"
+
+	// engineRuntimesCacheKey holds the engine's last runtime list, asked again once it expires
+	engineRuntimesCacheKey = "engine_runtimes"
+	engineRuntimesTTL      = 10 * time.Minute
+	engineRuntimesTimeout  = 3 * time.Second
+)
+
+// fetchEngineRuntimes asks the execution engine which runtime versions it offers for each language
+func (s *ProblemService) fetchEngineRuntimes(ctx context.Context) ([]model.EngineRuntime, error) {
+	ctx, cancel := context.WithTimeout(ctx, engineRuntimesTimeout)
+	defer cancel()
+	msg, err := s.NatsClient.RequestWithContext(ctx, runtimesRequestSubject, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errEngineUnavailable, err)
+	}
+	var resp model.EngineRuntimesResponse
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidCompilerResponse, err)
+	}
+	if len(resp.Runtimes) == 0 {
+		return nil, fmt.Errorf("%w: no runtimes", model.ErrInvalidCompilerResponse)
+	}
+	return resp.Runtimes, nil
+}
+
+// GetSupportedRuntimes lists the languages problems can be written in with their accepted aliases, the runtime
+// versions the engine offers and the version runs currently use. Without an answer from the engine the languages
+// are still listed, without versions.
+func (s *ProblemService) GetSupportedRuntimes(ctx context.Context, req *model.GetSupportedRuntimesRequest) (*model.GetSupportedRuntimesResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	engineRuntimes, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, engineRuntimesCacheKey, engineRuntimesTTL, s.fetchEngineRuntimes)
+	if err != nil {
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to fetch runtimes from the execution engine", map[string]any{
+			"method":    "GetSupportedRuntimes",
+			"errorType": "ENGINE_UNAVAILABLE",
+		}, "SERVICE", err)
+	}
+	// engines may spell languages differently, so they are matched the way user input is
+	byLanguage := make(map[string]model.EngineRuntime, len(engineRuntimes))
+	for _, runtime := range engineRuntimes {
+		byLanguage[utils.NormalizeLanguage(runtime.Language)] = runtime
+	}
+	profiles := make(map[string]model.ExecutionProfile)
+	for _, profile := range s.executionProfiles(ctx, traceID) {
+		profiles[profile.Language] = profile
+	}
+
+	resp := &model.GetSupportedRuntimesResponse{
+		Runtimes:   make([]model.LanguageRuntime, len(validation.Languages)),
+		FromEngine: err == nil,
+	}
+	for i, language := range validation.Languages {
+		runtime := model.LanguageRuntime{
+			Language: language,
+			Aliases:  utils.LanguageAliases(language),
+			Versions: []string{},
+		}
+		if engineRuntime, ok := byLanguage[language]; ok {
+			if engineRuntime.Versions != nil {
+				runtime.Versions = engineRuntime.Versions
+			}
+			runtime.DefaultVersion = engineRuntime.Default
+			runtime.CurrentVersion = engineRuntime.Default
+		}
+		if profile, ok := profiles[language]; ok && profile.RuntimeVersion != "" {
+			runtime.CurrentVersion = profile.RuntimeVersion
+		}
+		resp.Runtimes[i] = runtime
+	}
+	return resp, nil
+}
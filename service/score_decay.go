@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	configs "xcode/config"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// EnableScoreDecay makes the scores on the leaderboards in decay, keyed by model.LeaderboardPeriod*, decay while
+// their users are inactive. Decay is applied by the leaderboard syncs and is off until this is called.
+func (s *ProblemService) EnableScoreDecay(decay map[string]configs.ScoreDecayConfig) error {
+	for period := range decay {
+		if _, ok := s.PeriodLBs[period]; !ok && period != model.LeaderboardPeriodAllTime {
+			return fmt.Errorf("unknown leaderboard period %q", period)
+		}
+	}
+	s.scoreDecay = decay
+	return nil
+}
+
+// decayFactor is how much of a score is left after inactive without a first success: all of it during the grace
+// period, then half per half-life, never less than the floor
+func decayFactor(decay configs.ScoreDecayConfig, inactive time.Duration) float64 {
+	if inactive <= decay.Grace {
+		return 1
+	}
+	factor := math.Exp2(-float64(inactive-decay.Grace) / float64(decay.HalfLife))
+	return math.Max(factor, decay.MinFactor)
+}
+
+// applyScoreDecay rewrites the scores of inactive users on every board with a decay. Users are put back at their
+// full total by the sync that follows their next first success, so only the inactive ones are written here.
+func (s *ProblemService) applyScoreDecay(ctx context.Context, traceID string) {
+	now := time.Now()
+	for period, decay := range s.scoreDecay {
+		namespace, since := s.lbNamespace, time.Time{}
+		if period != model.LeaderboardPeriodAllTime {
+			namespace, since = PeriodLeaderboardNamespace(period, s.lbNamespace), seasonStart(period, now)
+		}
+
+		users, err := s.RepoConnInstance.GetInactiveUserTotalsMongo(ctx, now.Add(-decay.Grace), since)
+		if err == nil {
+			decayed := make([]model.RankedUserScore, len(users))
+			for i, user := range users {
+				decayed[i] = user.RankedUserScore
+				decayed[i].Score *= decayFactor(decay, now.Sub(user.LastActiveAt))
+			}
+			err = s.writeLeaderboardBatch(ctx, namespace, decayed)
+		}
+		if err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to apply score decay", map[string]any{
+				"method":    "applyScoreDecay",
+				"period":    period,
+				"errorType": "LEADERBOARD_SYNC_FAILED",
+			}, "SERVICE", err)
+			continue
+		}
+		s.logger.Log(zapcore.InfoLevel, traceID, "Score decay applied", map[string]any{
+			"method":    "applyScoreDecay",
+			"period":    period,
+			"userCount": len(users),
+		}, "SERVICE", nil)
+	}
+}
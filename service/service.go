@@ -1,26 +1,41 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"xcode/cache"
+	"xcode/codetemplate"
+	configs "xcode/config"
+	"xcode/customerrors"
+	"xcode/interceptor"
 	"xcode/model"
 	"xcode/natsclient"
 	"xcode/repository"
+	"xcode/userclient"
+	"xcode/utils"
+	"xcode/validation"
 
 	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
 	redisboard "github.com/lijuuu/RedisBoard"
+	"github.com/nats-io/nats.go"
 	cron "github.com/robfig/cron/v3"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 
 	zap_betterstack "xcode/logger"
 
@@ -29,34 +44,69 @@ import (
 
 // ProblemService handles problem-related operations
 type ProblemService struct {
-	RepoConnInstance repository.Repository
-	NatsClient       *natsclient.NatsClient
-	RedisCacheClient cache.RedisCache
-	LB               *redisboard.Leaderboard
+	RepoConnInstance    repository.ProblemRepository
+	NatsClient          *natsclient.NatsClient
+	JetStream           *natsclient.JetStream // durable submission events, nil when JetStream is unavailable
+	RedisCacheClient    cache.Cache
+	cacheTTL            atomic.Pointer[configs.CacheTTLConfig] // replaced by ApplyReloadedConfig
+	features            atomic.Pointer[configs.FeatureFlags]   // set by SetFeatureFlags, replaced by ApplyReloadedConfig
+	environment         string
+	engine              configs.EngineConfig
+	engineDispatcher    *executionDispatcher
+	LB                  *redisboard.Leaderboard
+	PeriodLBs           map[string]*redisboard.Leaderboard // seasonal boards keyed by model.LeaderboardPeriod*
+	OrgLB               *redisboard.Leaderboard            // ranks users by organization, nil until EnableOrganizationLeaderboard
+	lbNamespace         string
+	scoreDecay          map[string]configs.ScoreDecayConfig // by period, set by EnableScoreDecay
+	warmPages           int                                 // first list pages to precompute, 0 disables warming
+	warmPageSize        int
+	purgeRetention      time.Duration // how long soft deleted problems are kept, 0 disables purging
+	purgeArchive        bool
+	premiumRoles        []string               // user service roles entitled to premium problems, set by SetPremiumRoles
+	enforceProblemAuthz bool                   // reject rather than log unauthorized problem changes, set by EnforceProblemAuthorization
+	UserClient          *userclient.UserClient // resolves display profiles for leaderboard rows
+	background          sync.WaitGroup         // work that outlives its request, waited for on shutdown
+	outboxWake          chan struct{}          // wakes the outbox relay when a request wrote events
+	webhookWake         chan struct{}          // wakes the webhook dispatcher when events were queued
+	cronMu              sync.Mutex             // guards cron, cronJobs and cronSchedules
+	cron                *cron.Cron
+	cronJobs            []*cronJob
+	cronSchedules       configs.CronConfig
 	pb.UnimplementedProblemsServiceServer
 	logger *zap_betterstack.BetterStackLogStreamer
 }
 
-func NewService(repo repository.Repository, natsClient *natsclient.NatsClient, redisCache cache.RedisCache, lb *redisboard.Leaderboard, logger *zap_betterstack.BetterStackLogStreamer) *ProblemService {
+func NewService(repo repository.ProblemRepository, natsClient *natsclient.NatsClient, jetStream *natsclient.JetStream, redisCache cache.Cache, cacheTTL configs.CacheTTLConfig, engine configs.EngineConfig, lb *redisboard.Leaderboard, periodLBs map[string]*redisboard.Leaderboard, lbNamespace string, userClient *userclient.UserClient, logger *zap_betterstack.BetterStackLogStreamer) *ProblemService {
 	svc := &ProblemService{
 		RepoConnInstance: repo,
 		NatsClient:       natsClient,
+		JetStream:        jetStream,
 		RedisCacheClient: redisCache,
+		engine:           engine,
+		engineDispatcher: newExecutionDispatcher(engine.MaxConcurrency),
 		LB:               lb,
+		PeriodLBs:        periodLBs,
+		lbNamespace:      lbNamespace,
+		UserClient:       userClient,
+		outboxWake:       make(chan struct{}, 1),
+		webhookWake:      make(chan struct{}, 1),
 		logger:           logger,
 	}
+	svc.cacheTTL.Store(&cacheTTL)
 
 	return svc
 }
 
 func (s *ProblemService) SyncLeaderboardFromMongo(ctx context.Context) error {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
+	ctx = interceptor.ContextWithTraceID(ctx, traceID)
 
 	//force clear redis leaderboard cache
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ForceClearLeaderBoardWithNamespacePrefix", map[string]any{
 		"method": "SyncLeaderboardFromMongo",
 	}, "SERVICE", nil)
 	clearTime := time.Now()
+	defer leaderboardSyncDuration.ObserveDuration(clearTime, "full")
 	s.LB.ForceClearLeaderBoardWithNamespacePrefix()
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting SyncLeaderboardFromMongo", map[string]any{
@@ -72,25 +122,104 @@ func (s *ProblemService) SyncLeaderboardFromMongo(ctx context.Context) error {
 		return err
 	}
 
+	s.syncPeriodLeaderboardsFromMongo(ctx, traceID)
+	s.rebuildOrganizationLeaderboard(ctx, traceID)
+	s.applyScoreDecay(ctx, traceID)
+
+	// everything committed before the clear is now on the board, incremental syncs continue from a window before it
+	s.setLeaderboardSyncMarker(ctx, traceID, clearTime.Add(-leaderboardSyncSafetyWindow))
+
 	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboard synced successfully", map[string]any{
 		"method": "SyncLeaderboardFromMongo",
 	}, "SERVICE", nil)
 	return nil
 }
 
-func (s *ProblemService) StartCronJob() {
+// StartCronJob schedules the periodic jobs on the given schedules, which must be valid (configs.LoadConfig checks
+// them); RescheduleCronJobs moves them later. Stop the returned cron on shutdown to let running jobs finish.
+func (s *ProblemService) StartCronJob(schedules configs.CronConfig) *cron.Cron {
 	c := cron.New()
 
-	// schedule leaderboard sync every hour
-	c.AddFunc("@every 1h", func() {
+	// incremental leaderboard sync, full rebuilds go through AdminResyncLeaderboard
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.LeaderboardSync }, schedules, func() {
 		ctx := context.Background()
 		s.logger.Log(zapcore.InfoLevel, "", "Syncing MongoDB Submissions and RedisBoard "+time.Now().String(), map[string]any{
 			"method": "SYNC LEADERBOARD CRON JOB",
 		}, "SERVICE", nil)
 
-		s.SyncLeaderboardFromMongo(ctx)
+		s.runSingleton(ctx, "leaderboard_sync", leaderboardSyncLockTTL, false, func(ctx context.Context) {
+			s.IncrementalSyncLeaderboard(ctx)
+		})
+	})
+
+	// close the weekly (Monday) and monthly seasons at UTC midnight
+	c.AddFunc("CRON_TZ=UTC 0 0 * * 1", func() {
+		s.runSingleton(context.Background(), "season_rollover:"+model.LeaderboardPeriodWeekly, seasonRolloverLockTTL, true, func(ctx context.Context) {
+			s.RolloverLeaderboardSeason(ctx, model.LeaderboardPeriodWeekly)
+		})
+	})
+	c.AddFunc("CRON_TZ=UTC 0 0 1 * *", func() {
+		s.runSingleton(context.Background(), "season_rollover:"+model.LeaderboardPeriodMonthly, seasonRolloverLockTTL, true, func(ctx context.Context) {
+			s.RolloverLeaderboardSeason(ctx, model.LeaderboardPeriodMonthly)
+		})
+	})
+
+	// snapshot top standings once a day for history charts
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.LeaderboardSnapshot }, schedules, func() {
+		s.runSingleton(context.Background(), "leaderboard_snapshot", dailySnapshotLockTTL, true, func(ctx context.Context) {
+			s.SnapshotLeaderboards(ctx)
+		})
+	})
+
+	// persist editor drafts from Redis
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.DraftFlush }, schedules, func() {
+		s.runSingleton(context.Background(), "draft_flush", draftFlushLockTTL, false, func(ctx context.Context) {
+			s.FlushCodeDrafts(ctx)
+		})
+	})
+
+	// retry leaderboard updates that did not reach Redis when their submission was stored
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.LeaderboardOutbox }, schedules, func() {
+		s.runSingleton(context.Background(), "leaderboard_outbox", leaderboardOutboxLockTTL, false, func(ctx context.Context) {
+			s.ApplyPendingLeaderboardUpdates(ctx)
+		})
+	})
+
+	// publish the official standings and ratings of contests that ended
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.ContestFinalize }, schedules, func() {
+		s.runSingleton(context.Background(), "contest_finalize", contestFinalizeLockTTL, false, func(ctx context.Context) {
+			s.FinalizeEndedContests(ctx)
+		})
 	})
 
+	// empirical difficulty of every problem, flagging the mislabeled ones
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.DifficultyRecalibration }, schedules, func() {
+		s.runSingleton(context.Background(), "difficulty_recalibration", difficultyRecalibrationLockTTL, true, func(ctx context.Context) {
+			s.RecalibrateDifficulties(ctx)
+		})
+	})
+
+	// repair first successes lost to partially failed submission writes
+	s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.FirstSuccessCheck }, schedules, func() {
+		s.runSingleton(context.Background(), "first_success_check", firstSuccessCheckLockTTL, true, func(ctx context.Context) {
+			s.CheckFirstSuccessConsistency(ctx, &model.CheckFirstSuccessConsistencyRequest{Since: time.Now().Add(-firstSuccessCheckWindow)})
+		})
+	})
+
+	// remove problems soft deleted beyond the retention window
+	if s.purgeRetention > 0 {
+		s.scheduleCronJob(c, func(schedules configs.CronConfig) string { return schedules.SoftDeletePurge }, schedules, func() {
+			s.runSingleton(context.Background(), "soft_delete_purge", softDeletePurgeLockTTL, true, func(ctx context.Context) {
+				s.PurgeSoftDeletedProblems(ctx)
+			})
+		})
+	}
+
+	s.cronMu.Lock()
+	s.cron = c
+	s.cronSchedules = schedules
+	s.cronMu.Unlock()
+
 	// manually trigger once now
 	go func() {
 		ctx := context.Background()
@@ -98,10 +227,13 @@ func (s *ProblemService) StartCronJob() {
 			"method": "INITIAL SYNC",
 		}, "SERVICE", nil)
 
-		s.SyncLeaderboardFromMongo(ctx)
+		s.runSingleton(ctx, "leaderboard_sync", leaderboardSyncLockTTL, false, func(ctx context.Context) {
+			s.IncrementalSyncLeaderboard(ctx)
+		})
 	}()
 
 	c.Start()
+	return c
 }
 
 // GetService returns the ProblemService instance
@@ -113,25 +245,71 @@ func (s *ProblemService) GetService() *ProblemService {
 	return s
 }
 
-// createGrpcError constructs a gRPC error
-func (s *ProblemService) createGrpcError(code codes.Code, message string, errorType string, cause error) error {
-	traceID := uuid.New().String()
-	details := message
-	if cause != nil {
-		details = cause.Error()
+// traceIDFromContext returns the trace ID the interceptor chain assigned to the RPC, or a fresh one outside an RPC
+func traceIDFromContext(ctx context.Context) string {
+	if traceID := interceptor.TraceIDFromContext(ctx); traceID != "" {
+		return traceID
+	}
+	return uuid.New().String()
+}
+
+// traceIDFromMessage returns the trace ID a NATS message was published under, from its x-trace-id or traceparent
+// header, or a fresh one when it carries neither
+func traceIDFromMessage(msg *nats.Msg) string {
+	if traceID := messageHeader(msg, interceptor.TraceIDHeader); traceID != "" {
+		return traceID
 	}
+	if traceID := interceptor.TraceIDFromTraceparent(messageHeader(msg, interceptor.TraceparentHeader)); traceID != "" {
+		return traceID
+	}
+	return uuid.New().String()
+}
+
+// messageHeader looks key up as written and in canonical form; NATS headers are case sensitive, but publishers that
+// go through http.Header, like the OpenTelemetry propagator, canonicalize them
+func messageHeader(msg *nats.Msg, key string) string {
+	if value := msg.Header.Get(key); value != "" {
+		return value
+	}
+	return msg.Header.Get(http.CanonicalHeaderKey(key))
+}
+
+// createGrpcError returns message to the caller with errorType as the ErrorInfo reason and the trace ID in its
+// metadata; the cause, when there is one, is only logged. details adds further error details such as a RetryInfo.
+func (s *ProblemService) createGrpcError(ctx context.Context, code codes.Code, message string, errorType string, cause error, details ...protoadapt.MessageV1) error {
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.ErrorLevel, traceID, "Creating gRPC error", map[string]any{
 		"method":    "createGrpcError",
 		"code":      code,
 		"errorType": errorType,
-		"details":   details,
-	}, "SERVICE", nil)
-	return status.Error(code, fmt.Sprintf("ErrorType: %s, Code: %d, Details: %s", errorType, code, details))
+		"details":   message,
+	}, "SERVICE", cause)
+	return customerrors.Status(code, errorType, message, map[string]string{"traceId": traceID}, details...)
+}
+
+// repoError turns an error from the repository into the gRPC error returned to the caller. The code and errorType
+// come from the error's sentinel (see customerrors.Code); fallback is shown instead of the error when its text is
+// not meant for the caller, e.g. a driver error.
+func (s *ProblemService) repoError(ctx context.Context, err error, fallback string) error {
+	return s.createGrpcError(ctx, customerrors.Code(err), customerrors.PublicMessage(err, fallback), customerrors.Type(err), err)
+}
+
+// checkRequest checks a request of package model against its rules in package validation and returns the
+// InvalidArgument error listing every field that broke one
+func (s *ProblemService) checkRequest(traceID, method string, req any) error {
+	err := validation.Model(req)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid request", map[string]any{
+			"method":    method,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", err)
+	}
+	return err
 }
 
 // CreateProblem creates a new problem
 func (s *ProblemService) CreateProblem(ctx context.Context, req *pb.CreateProblemRequest) (*pb.CreateProblemResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting CreateProblem", map[string]any{
 		"method":       "CreateProblem",
 		"problemTitle": req.Title,
@@ -142,27 +320,56 @@ func (s *ProblemService) CreateProblem(ctx context.Context, req *pb.CreateProble
 			"method":    "CreateProblem",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Title, description, and difficulty are required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Title, description, and difficulty are required", "VALIDATION_ERROR", nil)
 	}
 
-	resp, err := s.RepoConnInstance.CreateProblem(ctx, req)
+	// the creator becomes the problem's first maintainer; problems created without a caller are left to admins
+	author := interceptor.UserIDFromMetadata(ctx)
+
+	// the problem, its author and its created event are committed together, so the event cannot be lost or announce
+	// a problem that was never stored
+	var resp *pb.CreateProblemResponse
+	err := s.RepoConnInstance.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = s.RepoConnInstance.CreateProblem(ctx, req)
+		if err != nil {
+			return err
+		}
+		if author != "" {
+			if err := s.RepoConnInstance.SetProblemAuthor(ctx, resp.ProblemId, author); err != nil {
+				return err
+			}
+		}
+		return s.enqueueProblemEvent(ctx, traceID, model.ProblemEventCreated, model.ProblemEvent{
+			ProblemID:  resp.ProblemId,
+			Title:      req.Title,
+			Difficulty: req.Difficulty,
+			Tags:       req.Tags,
+		})
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to create problem", map[string]any{
 			"method":       "CreateProblem",
 			"problemTitle": req.Title,
-			"errorType":    "DB_ERROR",
+			"errorType":    customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to create problem")
 	}
 
-	cacheKey := "problems_list:*"
-	if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
-			"method":    "CreateProblem",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
-	}
+	s.wakeOutboxRelay()
+	s.invalidateProblemListCaches(ctx, traceID, "CreateProblem")
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionCreateProblem,
+		TargetType: model.AuditTargetProblem,
+		TargetID:   resp.ProblemId,
+		After: map[string]any{
+			"title":      req.Title,
+			"difficulty": req.Difficulty,
+			"tags":       req.Tags,
+			"createdBy":  author,
+		},
+	})
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem created successfully", map[string]any{
 		"method":       "CreateProblem",
@@ -171,9 +378,37 @@ func (s *ProblemService) CreateProblem(ctx context.Context, req *pb.CreateProble
 	return resp, nil
 }
 
+// problemListCacheKey keys a cached page of a problem list by its filters as well as its position, so filtered pages
+// are not served for one another. Tags are matched with $all, so their order and repeats are normalized away.
+func problemListCacheKey(prefix string, page, pageSize int32, tags []string, difficulty, searchQuery string) string {
+	filters, _ := json.Marshal(struct {
+		Tags        []string `json:"tags"`
+		Difficulty  string   `json:"difficulty"`
+		SearchQuery string   `json:"searchQuery"`
+	}{slices.Compact(slices.Sorted(slices.Values(tags))), difficulty, searchQuery})
+	sum := sha256.Sum256(filters)
+	return fmt.Sprintf("%s:%d:%d:%s", prefix, page, pageSize, hex.EncodeToString(sum[:8]))
+}
+
+// invalidateProblemListCaches drops every cached page of the problem lists, whatever page and page size it was cached under
+func (s *ProblemService) invalidateProblemListCaches(ctx context.Context, traceID, method string) {
+	for _, pattern := range []string{"problems_list:*", "problem_id_list:*"} {
+		if err := s.RedisCacheClient.DeletePattern(ctx, pattern); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    method,
+				"cacheKey":  pattern,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+
+	// the request context ends with the response, the warm-up outlives it
+	s.runInBackground(ctx, s.WarmProblemListCaches)
+}
+
 // UpdateProblem updates an existing problem
 func (s *ProblemService) UpdateProblem(ctx context.Context, req *pb.UpdateProblemRequest) (*pb.UpdateProblemResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting UpdateProblem", map[string]any{
 		"method":    "UpdateProblem",
 		"problemId": req.ProblemId,
@@ -184,26 +419,52 @@ func (s *ProblemService) UpdateProblem(ctx context.Context, req *pb.UpdateProble
 			"method":    "UpdateProblem",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "UpdateProblem", req.ProblemId); err != nil {
+		return nil, err
+	}
+
+	// a problem is published when an update makes it visible; webhooks are sent all of it, not just the change
+	var unpublished *model.Problem
+	if req.Visible != nil && *req.Visible {
+		if before := s.problemForAudit(ctx, traceID, "UpdateProblem", req.ProblemId); before != nil && !before.Visible {
+			unpublished = before
+		}
 	}
 
-	resp, err := s.RepoConnInstance.UpdateProblem(ctx, req)
+	updated := model.ProblemEvent{ProblemID: req.ProblemId, Tags: req.Tags, Visible: req.Visible}
+	if req.Title != nil {
+		updated.Title = *req.Title
+	}
+	if req.Difficulty != nil {
+		updated.Difficulty = *req.Difficulty
+	}
+	var resp *pb.UpdateProblemResponse
+	err := s.RepoConnInstance.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = s.RepoConnInstance.UpdateProblem(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.enqueueProblemEvent(ctx, traceID, model.ProblemEventUpdated, updated)
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update problem", map[string]any{
 			"method":    "UpdateProblem",
 			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to update problem")
 	}
 
 	cacheKeys := []string{
 		fmt.Sprintf("problem:%s", req.ProblemId),
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
 		fmt.Sprintf("problem_slug:%s", *req.Title),
-		"problems_list:*",
 	}
 	for _, cacheKey := range cacheKeys {
-		if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 				"method":    "UpdateProblem",
 				"cacheKey":  cacheKey,
@@ -212,6 +473,22 @@ func (s *ProblemService) UpdateProblem(ctx context.Context, req *pb.UpdateProble
 		}
 	}
 
+	s.wakeOutboxRelay()
+	s.invalidateProblemListCaches(ctx, traceID, "UpdateProblem")
+	if unpublished != nil {
+		published := model.ProblemEvent{
+			ProblemID:  req.ProblemId,
+			Title:      cmp.Or(updated.Title, unpublished.Title),
+			Difficulty: cmp.Or(updated.Difficulty, unpublished.Difficulty),
+			Tags:       unpublished.Tags,
+			Visible:    req.Visible,
+		}
+		if len(updated.Tags) > 0 {
+			published.Tags = updated.Tags
+		}
+		s.enqueueWebhookEvent(ctx, traceID, model.WebhookEventProblemPublished, published)
+	}
+
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem updated successfully", map[string]any{
 		"method":    "UpdateProblem",
 		"problemId": req.ProblemId,
@@ -221,7 +498,7 @@ func (s *ProblemService) UpdateProblem(ctx context.Context, req *pb.UpdateProble
 
 // DeleteProblem deletes a problem
 func (s *ProblemService) DeleteProblem(ctx context.Context, req *pb.DeleteProblemRequest) (*pb.DeleteProblemResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting DeleteProblem", map[string]any{
 		"method":    "DeleteProblem",
 		"problemId": req.ProblemId,
@@ -232,25 +509,37 @@ func (s *ProblemService) DeleteProblem(ctx context.Context, req *pb.DeleteProble
 			"method":    "DeleteProblem",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "DeleteProblem", req.ProblemId); err != nil {
+		return nil, err
 	}
 
-	resp, err := s.RepoConnInstance.DeleteProblem(ctx, req)
+	before := s.problemForAudit(ctx, traceID, "DeleteProblem", req.ProblemId)
+	var resp *pb.DeleteProblemResponse
+	err := s.RepoConnInstance.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = s.RepoConnInstance.DeleteProblem(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.enqueueProblemEvent(ctx, traceID, model.ProblemEventDeleted, model.ProblemEvent{ProblemID: req.ProblemId})
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete problem", map[string]any{
 			"method":    "DeleteProblem",
 			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to delete problem")
 	}
 
 	cacheKeys := []string{
 		fmt.Sprintf("problem:%s", req.ProblemId),
-		"problems_list:*",
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
 	}
 	for _, cacheKey := range cacheKeys {
-		if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 				"method":    "DeleteProblem",
 				"cacheKey":  cacheKey,
@@ -259,6 +548,16 @@ func (s *ProblemService) DeleteProblem(ctx context.Context, req *pb.DeleteProble
 		}
 	}
 
+	s.wakeOutboxRelay()
+	s.invalidateProblemListCaches(ctx, traceID, "DeleteProblem")
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionDeleteProblem,
+		TargetType: model.AuditTargetProblem,
+		TargetID:   req.ProblemId,
+		Before:     problemAuditSummary(before),
+	})
+
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem deleted successfully", map[string]any{
 		"method":    "DeleteProblem",
 		"problemId": req.ProblemId,
@@ -268,7 +567,7 @@ func (s *ProblemService) DeleteProblem(ctx context.Context, req *pb.DeleteProble
 
 // GetProblem retrieves a problem by ID
 func (s *ProblemService) GetProblem(ctx context.Context, req *pb.GetProblemRequest) (*pb.GetProblemResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetProblem", map[string]any{
 		"method":    "GetProblem",
 		"problemId": req.ProblemId,
@@ -279,54 +578,38 @@ func (s *ProblemService) GetProblem(ctx context.Context, req *pb.GetProblemReque
 			"method":    "GetProblem",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.requirePremiumAccess(ctx, traceID, "GetProblem", req.ProblemId); err != nil {
+		return nil, err
 	}
 
 	cacheKey := fmt.Sprintf("problem:%s", req.ProblemId)
-	cachedProblem, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedProblem != nil {
-		var problem pb.GetProblemResponse
-		cachedStr, ok := cachedProblem.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached problem to string", map[string]any{
-				"method":    "GetProblem",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &problem); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Problem retrieved from cache", map[string]any{
-				"method":    "GetProblem",
-				"problemId": req.ProblemId,
-				"cacheKey":  cacheKey,
-			}, "SERVICE", nil)
-			return &problem, nil
+	problemPB, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().Problem, func(ctx context.Context) (*pb.GetProblemResponse, error) {
+		problemRepoModel, err := s.RepoConnInstance.GetProblem(ctx, req)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	problemRepoModel, err := s.RepoConnInstance.GetProblem(ctx, req)
+		return repository.ToProblemResponse(*problemRepoModel), nil
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve problem from DB", map[string]any{
 			"method":    "GetProblem",
 			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to retrieve problem")
+	}
+	if problemPB, err = s.problemForView(ctx, traceID, "GetProblem", problemPB); err != nil {
 		return nil, err
 	}
-
-	problemPB := repository.ToProblemResponse(*problemRepoModel)
-	problemBytes, err := json.Marshal(problemPB)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal problem", map[string]any{
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Problem retrieved from cache", map[string]any{
 			"method":    "GetProblem",
 			"problemId": req.ProblemId,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, problemBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache problem", map[string]any{
-			"method":    "GetProblem",
 			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+		}, "SERVICE", nil)
+		return problemPB, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem retrieved successfully", map[string]any{
@@ -336,9 +619,14 @@ func (s *ProblemService) GetProblem(ctx context.Context, req *pb.GetProblemReque
 	return problemPB, nil
 }
 
-// ListProblems retrieves a paginated list of problems
+// ListProblems retrieves a paginated list of problems, without the ones the x-user-id caller hid
 func (s *ProblemService) ListProblems(ctx context.Context, req *pb.ListProblemsRequest) (*pb.ListProblemsResponse, error) {
-	traceID := uuid.New().String()
+	return s.listProblems(ctx, req, interceptor.UserIDFromMetadata(ctx))
+}
+
+// listProblems is ListProblems without the problems userID hid
+func (s *ProblemService) listProblems(ctx context.Context, req *pb.ListProblemsRequest, userID string) (*pb.ListProblemsResponse, error) {
+	traceID := traceIDFromContext(ctx)
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ListProblems", map[string]any{
 		"method":   "ListProblems",
@@ -353,53 +641,41 @@ func (s *ProblemService) ListProblems(ctx context.Context, req *pb.ListProblemsR
 		req.PageSize = 10
 	}
 
-	cacheKey := fmt.Sprintf("problems_list:%d:%d", req.Page, req.PageSize)
-	cachedProblems, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedProblems != nil {
-		var problems pb.ListProblemsResponse
-		cachedStr, ok := cachedProblems.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached problems to string", map[string]any{
-				"method":    "ListProblems",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &problems); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Problems list retrieved from cache", map[string]any{
-				"method":   "ListProblems",
-				"cacheKey": cacheKey,
-				"page":     req.Page,
-				"pageSize": req.PageSize,
-			}, "SERVICE", nil)
-			return &problems, nil
-		}
+	cacheKey := problemListCacheKey("problems_list", req.Page, req.PageSize, req.Tags, req.Difficulty, req.SearchQuery)
+	var resp *pb.ListProblemsResponse
+	var fromCache bool
+	var err error
+	if hidden := s.hiddenProblemsFor(ctx, traceID, "ListProblems", userID); len(hidden) > 0 {
+		// a page without the user's hidden problems is theirs alone, so it skips the shared cache
+		resp, err = s.RepoConnInstance.ListProblems(ctx, req, hidden)
+	} else {
+		resp, fromCache, err = cache.GetOrLoadStale(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().List, s.cacheTTLs().ListStale, func(ctx context.Context) (*pb.ListProblemsResponse, error) {
+			return s.RepoConnInstance.ListProblems(ctx, req, nil)
+		})
 	}
-
-	resp, err := s.RepoConnInstance.ListProblems(ctx, req)
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve problems list from DB", map[string]any{
 			"method":    "ListProblems",
 			"page":      req.Page,
 			"pageSize":  req.PageSize,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to retrieve problems list")
+	}
+	if resp, err = s.problemsForView(ctx, traceID, "ListProblems", resp); err != nil {
 		return nil, err
 	}
-
-	problemsBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal problems list", map[string]any{
-			"method":    "ListProblems",
-			"page":      req.Page,
-			"pageSize":  req.PageSize,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, problemsBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache problems list", map[string]any{
-			"method":    "ListProblems",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	if resp, err = s.lockPremiumProblemPage(ctx, traceID, "ListProblems", resp); err != nil {
+		return nil, err
+	}
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Problems list retrieved from cache", map[string]any{
+			"method":   "ListProblems",
+			"cacheKey": cacheKey,
+			"page":     req.Page,
+			"pageSize": req.PageSize,
+		}, "SERVICE", nil)
+		return resp, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problems list retrieved successfully", map[string]any{
@@ -412,7 +688,7 @@ func (s *ProblemService) ListProblems(ctx context.Context, req *pb.ListProblemsR
 
 // AddTestCases adds test cases to a problem
 func (s *ProblemService) AddTestCases(ctx context.Context, req *pb.AddTestCasesRequest) (*pb.AddTestCasesResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting AddTestCases", map[string]any{
 		"method":    "AddTestCases",
 		"problemId": req.ProblemId,
@@ -423,14 +699,17 @@ func (s *ProblemService) AddTestCases(ctx context.Context, req *pb.AddTestCasesR
 			"method":    "AddTestCases",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "AddTestCases", req.ProblemId); err != nil {
+		return nil, err
 	}
 	if len(req.Testcases.Run) == 0 && len(req.Testcases.Submit) == 0 {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "No test cases provided", map[string]any{
 			"method":    "AddTestCases",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "At least one test case is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "At least one test case is required", "VALIDATION_ERROR", nil)
 	}
 	for _, tc := range req.Testcases.Run {
 		if tc.Input == "" || tc.Expected == "" {
@@ -438,7 +717,7 @@ func (s *ProblemService) AddTestCases(ctx context.Context, req *pb.AddTestCasesR
 				"method":    "AddTestCases",
 				"errorType": "VALIDATION_ERROR",
 			}, "SERVICE", nil)
-			return nil, s.createGrpcError(codes.InvalidArgument, "Test case input and expected output are required", "VALIDATION_ERROR", nil)
+			return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Test case input and expected output are required", "VALIDATION_ERROR", nil)
 		}
 	}
 	for _, tc := range req.Testcases.Submit {
@@ -447,29 +726,46 @@ func (s *ProblemService) AddTestCases(ctx context.Context, req *pb.AddTestCasesR
 				"method":    "AddTestCases",
 				"errorType": "VALIDATION_ERROR",
 			}, "SERVICE", nil)
-			return nil, s.createGrpcError(codes.InvalidArgument, "Test case input and expected output are required", "VALIDATION_ERROR", nil)
+			return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Test case input and expected output are required", "VALIDATION_ERROR", nil)
 		}
 	}
 
+	before := s.problemForAudit(ctx, traceID, "AddTestCases", req.ProblemId)
 	resp, err := s.RepoConnInstance.AddTestCases(ctx, req)
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to add test cases", map[string]any{
 			"method":    "AddTestCases",
 			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to add test cases")
 	}
 
-	cacheKey := fmt.Sprintf("problem:%s", req.ProblemId)
-	if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
-			"method":    "AddTestCases",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	cacheKeys := []string{
+		fmt.Sprintf("problem:%s", req.ProblemId),
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
+	}
+	for _, cacheKey := range cacheKeys {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    "AddTestCases",
+				"cacheKey":  cacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
 	}
 
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionAddTestCases,
+		TargetType: model.AuditTargetProblem,
+		TargetID:   req.ProblemId,
+		Before:     problemAuditSummary(before),
+		After: map[string]any{
+			"runTestCasesAdded":    len(req.Testcases.Run),
+			"submitTestCasesAdded": len(req.Testcases.Submit),
+		},
+	})
+
 	s.logger.Log(zapcore.InfoLevel, traceID, "Test cases added successfully", map[string]any{
 		"method":    "AddTestCases",
 		"problemId": req.ProblemId,
@@ -479,7 +775,7 @@ func (s *ProblemService) AddTestCases(ctx context.Context, req *pb.AddTestCasesR
 
 // AddLanguageSupport adds language support to a problem
 func (s *ProblemService) AddLanguageSupport(ctx context.Context, req *pb.AddLanguageSupportRequest) (*pb.AddLanguageSupportResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting AddLanguageSupport", map[string]any{
 		"method":    "AddLanguageSupport",
 		"problemId": req.ProblemId,
@@ -491,14 +787,17 @@ func (s *ProblemService) AddLanguageSupport(ctx context.Context, req *pb.AddLang
 			"method":    "AddLanguageSupport",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "AddLanguageSupport", req.ProblemId); err != nil {
+		return nil, err
 	}
 	if req.ValidationCode == nil || req.ValidationCode.Code == "" || req.ValidationCode.Template == "" {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing validation code or template", map[string]any{
 			"method":    "AddLanguageSupport",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Validation code (code and template) is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Validation code (code and template) is required", "VALIDATION_ERROR", nil)
 	}
 
 	resp, err := s.RepoConnInstance.AddLanguageSupport(ctx, req)
@@ -507,17 +806,18 @@ func (s *ProblemService) AddLanguageSupport(ctx context.Context, req *pb.AddLang
 			"method":    "AddLanguageSupport",
 			"problemId": req.ProblemId,
 			"language":  req.Language,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to add language support")
 	}
 
 	cacheKeys := []string{
 		fmt.Sprintf("problem:%s", req.ProblemId),
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
 		fmt.Sprintf("language_supports:%s", req.ProblemId),
 	}
 	for _, cacheKey := range cacheKeys {
-		if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 				"method":    "AddLanguageSupport",
 				"cacheKey":  cacheKey,
@@ -536,7 +836,7 @@ func (s *ProblemService) AddLanguageSupport(ctx context.Context, req *pb.AddLang
 
 // UpdateLanguageSupport updates language support for a problem
 func (s *ProblemService) UpdateLanguageSupport(ctx context.Context, req *pb.UpdateLanguageSupportRequest) (*pb.UpdateLanguageSupportResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting UpdateLanguageSupport", map[string]any{
 		"method":    "UpdateLanguageSupport",
 		"problemId": req.ProblemId,
@@ -548,14 +848,17 @@ func (s *ProblemService) UpdateLanguageSupport(ctx context.Context, req *pb.Upda
 			"method":    "UpdateLanguageSupport",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "UpdateLanguageSupport", req.ProblemId); err != nil {
+		return nil, err
 	}
 	if req.ValidationCode == nil || req.ValidationCode.Code == "" || req.ValidationCode.Template == "" {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing validation code or template", map[string]any{
 			"method":    "UpdateLanguageSupport",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Validation code (code and template) is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Validation code (code and template) is required", "VALIDATION_ERROR", nil)
 	}
 
 	resp, err := s.RepoConnInstance.UpdateLanguageSupport(ctx, req)
@@ -564,17 +867,18 @@ func (s *ProblemService) UpdateLanguageSupport(ctx context.Context, req *pb.Upda
 			"method":    "UpdateLanguageSupport",
 			"problemId": req.ProblemId,
 			"language":  req.Language,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to update language support")
 	}
 
 	cacheKeys := []string{
 		fmt.Sprintf("problem:%s", req.ProblemId),
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
 		fmt.Sprintf("language_supports:%s", req.ProblemId),
 	}
 	for _, cacheKey := range cacheKeys {
-		if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 				"method":    "UpdateLanguageSupport",
 				"cacheKey":  cacheKey,
@@ -593,7 +897,7 @@ func (s *ProblemService) UpdateLanguageSupport(ctx context.Context, req *pb.Upda
 
 // RemoveLanguageSupport removes language support from a problem
 func (s *ProblemService) RemoveLanguageSupport(ctx context.Context, req *pb.RemoveLanguageSupportRequest) (*pb.RemoveLanguageSupportResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RemoveLanguageSupport", map[string]any{
 		"method":    "RemoveLanguageSupport",
 		"problemId": req.ProblemId,
@@ -605,7 +909,10 @@ func (s *ProblemService) RemoveLanguageSupport(ctx context.Context, req *pb.Remo
 			"method":    "RemoveLanguageSupport",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "RemoveLanguageSupport", req.ProblemId); err != nil {
+		return nil, err
 	}
 
 	resp, err := s.RepoConnInstance.RemoveLanguageSupport(ctx, req)
@@ -614,17 +921,18 @@ func (s *ProblemService) RemoveLanguageSupport(ctx context.Context, req *pb.Remo
 			"method":    "RemoveLanguageSupport",
 			"problemId": req.ProblemId,
 			"language":  req.Language,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to remove language support")
 	}
 
 	cacheKeys := []string{
 		fmt.Sprintf("problem:%s", req.ProblemId),
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
 		fmt.Sprintf("language_supports:%s", req.ProblemId),
 	}
 	for _, cacheKey := range cacheKeys {
-		if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 				"method":    "RemoveLanguageSupport",
 				"cacheKey":  cacheKey,
@@ -643,7 +951,7 @@ func (s *ProblemService) RemoveLanguageSupport(ctx context.Context, req *pb.Remo
 
 // DeleteTestCase deletes a test case from a problem
 func (s *ProblemService) DeleteTestCase(ctx context.Context, req *pb.DeleteTestCaseRequest) (*pb.DeleteTestCaseResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting DeleteTestCase", map[string]any{
 		"method":     "DeleteTestCase",
 		"problemId":  req.ProblemId,
@@ -655,7 +963,10 @@ func (s *ProblemService) DeleteTestCase(ctx context.Context, req *pb.DeleteTestC
 			"method":    "DeleteTestCase",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID and testcase ID are required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and testcase ID are required", "VALIDATION_ERROR", nil)
+	}
+	if err := s.authorizeProblemMutation(ctx, traceID, "DeleteTestCase", req.ProblemId); err != nil {
+		return nil, err
 	}
 
 	resp, err := s.RepoConnInstance.DeleteTestCase(ctx, req)
@@ -664,18 +975,23 @@ func (s *ProblemService) DeleteTestCase(ctx context.Context, req *pb.DeleteTestC
 			"method":     "DeleteTestCase",
 			"problemId":  req.ProblemId,
 			"testcaseId": req.TestcaseId,
-			"errorType":  "DB_ERROR",
+			"errorType":  customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to delete test case")
 	}
 
-	cacheKey := fmt.Sprintf("problem:%s", req.ProblemId)
-	if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
-			"method":    "DeleteTestCase",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	cacheKeys := []string{
+		fmt.Sprintf("problem:%s", req.ProblemId),
+		fmt.Sprintf("problem_slug_id:%s", req.ProblemId),
+	}
+	for _, cacheKey := range cacheKeys {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    "DeleteTestCase",
+				"cacheKey":  cacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Test case deleted successfully", map[string]any{
@@ -688,7 +1004,7 @@ func (s *ProblemService) DeleteTestCase(ctx context.Context, req *pb.DeleteTestC
 
 // GetLanguageSupports retrieves supported languages for a problem
 func (s *ProblemService) GetLanguageSupports(ctx context.Context, req *pb.GetLanguageSupportsRequest) (*pb.GetLanguageSupportsResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetLanguageSupports", map[string]any{
 		"method":    "GetLanguageSupports",
 		"problemId": req.ProblemId,
@@ -699,53 +1015,31 @@ func (s *ProblemService) GetLanguageSupports(ctx context.Context, req *pb.GetLan
 			"method":    "GetLanguageSupports",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
 	}
 
 	cacheKey := fmt.Sprintf("language_supports:%s", req.ProblemId)
-	cachedLangs, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedLangs != nil {
-		var langs pb.GetLanguageSupportsResponse
-		cachedStr, ok := cachedLangs.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached languages to string", map[string]any{
-				"method":    "GetLanguageSupports",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &langs); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Language supports retrieved from cache", map[string]any{
-				"method":    "GetLanguageSupports",
-				"problemId": req.ProblemId,
-				"cacheKey":  cacheKey,
-			}, "SERVICE", nil)
-			return &langs, nil
-		}
-	}
-
-	resp, err := s.RepoConnInstance.GetLanguageSupports(ctx, req)
+	resp, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().Problem, func(ctx context.Context) (*pb.GetLanguageSupportsResponse, error) {
+		return s.RepoConnInstance.GetLanguageSupports(ctx, req)
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve language supports from DB", map[string]any{
 			"method":    "GetLanguageSupports",
 			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to retrieve language supports")
+	}
+	if resp, err = s.languageSupportsForView(ctx, traceID, req.ProblemId, resp); err != nil {
 		return nil, err
 	}
-
-	langsBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal language supports", map[string]any{
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Language supports retrieved from cache", map[string]any{
 			"method":    "GetLanguageSupports",
 			"problemId": req.ProblemId,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, langsBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache language supports", map[string]any{
-			"method":    "GetLanguageSupports",
 			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+		}, "SERVICE", nil)
+		return resp, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Language supports retrieved successfully", map[string]any{
@@ -755,9 +1049,10 @@ func (s *ProblemService) GetLanguageSupports(ctx context.Context, req *pb.GetLan
 	return resp, nil
 }
 
-// FullValidationByProblemID validates a problem across all supported languages
+// FullValidationByProblemID validates a problem across all supported languages, see validateProblem. The response
+// names every language that failed; ValidateProblem returns the full per-language report.
 func (s *ProblemService) FullValidationByProblemID(ctx context.Context, req *pb.FullValidationByProblemIDRequest) (*pb.FullValidationByProblemIDResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting FullValidationByProblemID", map[string]any{
 		"method":    "FullValidationByProblemID",
 		"problemId": req.ProblemId,
@@ -772,143 +1067,27 @@ func (s *ProblemService) FullValidationByProblemID(ctx context.Context, req *pb.
 			Success:   false,
 			Message:   "Problem ID is required",
 			ErrorType: "VALIDATION_ERROR",
-		}, s.createGrpcError(codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+		}, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
 	}
-
-	data, problem, err := s.RepoConnInstance.BasicValidationByProblemID(ctx, req)
-	if err != nil || !data.Success {
-		errMsg := data.Message
-		if errMsg == "" {
-			errMsg = "Basic validation failed"
-		}
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Basic validation failed", map[string]any{
-			"method":    "FullValidationByProblemID",
-			"problemId": req.ProblemId,
-			"errorType": data.ErrorType,
-		}, "SERVICE", err)
-		s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, false)
-		return data, s.createGrpcError(codes.Unimplemented, errMsg, data.ErrorType, err)
-	}
-
-	// fmt.Println("supported problems ", problem.ValidateCode)
-	// fmt.Println("length and content of supported languages ",len(problem.SupportedLanguages),problem.SupportedLanguages )
-	for _, lang := range problem.SupportedLanguages {
-		validateCode, ok := problem.ValidateCode[lang]
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "No validation code found for language", map[string]any{
-				"method":    "FullValidationByProblemID",
-				"problemId": req.ProblemId,
-				"language":  lang,
-				"errorType": "CONFIGURATION_ERROR",
-			}, "SERVICE", nil)
-			s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, false)
-			return &pb.FullValidationByProblemIDResponse{
-				Success:   false,
-				Message:   fmt.Sprintf("No validation code found for language: %s", lang),
-				ErrorType: "CONFIGURATION_ERROR",
-			}, s.createGrpcError(codes.InvalidArgument, "Missing validation code", "CONFIGURATION_ERROR", nil)
-		}
-
-		res, err := s.RunUserCodeProblem(ctx, &pb.RunProblemRequest{
-			ProblemId:     req.ProblemId,
-			UserCode:      validateCode.Code,
-			Language:      lang,
-			IsRunTestcase: false,
-		})
-		if err != nil {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Execution failed for language", map[string]any{
-				"method":    "FullValidationByProblemID",
-				"problemId": req.ProblemId,
-				"language":  lang,
-				"errorType": "EXECUTION_ERROR",
-			}, "SERVICE", err)
-			s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, false)
-			return &pb.FullValidationByProblemIDResponse{
-				Success:   false,
-				Message:   fmt.Sprintf("Execution failed for language %s: %v", lang, err),
-				ErrorType: "EXECUTION_ERROR",
-			}, s.createGrpcError(codes.Internal, "Execution error", "EXECUTION_ERROR", err)
-		}
-
-		var result map[string]any
-		if err := json.Unmarshal([]byte(res.Message), &result); err != nil {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to parse execution result", map[string]any{
-				"method":    "FullValidationByProblemID",
-				"problemId": req.ProblemId,
-				"language":  lang,
-				"errorType": "EXECUTION_ERROR",
-			}, "SERVICE", err)
-			return nil, fmt.Errorf("failed to parse execution result: %w", err)
-		}
-
-		overallPass, ok := result["overallPass"].(bool)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "No output received for language", map[string]any{
-				"method":    "FullValidationByProblemID",
-				"problemId": req.ProblemId,
-				"language":  lang,
-				"errorType": "EXECUTION_ERROR",
-			}, "SERVICE", nil)
-			s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, false)
-			return &pb.FullValidationByProblemIDResponse{
-				Success:   false,
-				Message:   fmt.Sprintf("No output received for language %s", lang),
-				ErrorType: "EXECUTION_ERROR",
-			}, s.createGrpcError(codes.Internal, "Invalid execution result "+fmt.Sprintf("No output received for language %s", lang), "EXECUTION_ERROR", nil)
-		}
-
-		if !overallPass {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Validation failed for language", map[string]any{
-				"method":    "FullValidationByProblemID",
-				"problemId": req.ProblemId,
-				"language":  lang,
-				"errorType": "VALIDATION_FAILED",
-			}, "SERVICE", nil)
-			s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, false)
-			return &pb.FullValidationByProblemIDResponse{
-				Success:   false,
-				Message:   fmt.Sprintf("Validation failed for language %s", lang),
-				ErrorType: "VALIDATION_FAILED",
-			}, s.createGrpcError(codes.FailedPrecondition, "Validation failed", "VALIDATION_FAILED", nil)
-		}
+	if err := s.authorizeProblemMutation(ctx, traceID, "FullValidationByProblemID", req.ProblemId); err != nil {
+		return nil, err
 	}
 
-	status := s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, true)
-	message := "Full Validation Successful"
-	if !status {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to toggle validation status", map[string]any{
-			"method":    "FullValidationByProblemID",
-			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
-		}, "SERVICE", nil)
-		s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemId, false)
-		message = "Full Validation completed, but failed to toggle status"
+	report, err := s.validateProblem(ctx, traceID, req.ProblemId, nil)
+	resp := &pb.FullValidationByProblemIDResponse{
+		Success:   report.Validated,
+		Message:   report.Message,
+		ErrorType: report.ErrorType,
 	}
-
-	cacheKey := fmt.Sprintf("problem:%s", req.ProblemId)
-	if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
-			"method":    "FullValidationByProblemID",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	if err == nil && report.ErrorType == "VALIDATION_FAILED" {
+		err = s.createGrpcError(ctx, codes.FailedPrecondition, report.Message, report.ErrorType, nil)
 	}
-
-	s.logger.Log(zapcore.InfoLevel, traceID, "Full validation completed", map[string]any{
-		"method":    "FullValidationByProblemID",
-		"problemId": req.ProblemId,
-		"status":    status,
-	}, "SERVICE", nil)
-	return &pb.FullValidationByProblemIDResponse{
-		Success:   status,
-		Message:   message,
-		ErrorType: "",
-	}, nil
+	return resp, err
 }
 
 // GetSubmissionsByOptionalProblemID retrieves submissions
 func (s *ProblemService) GetSubmissionsByOptionalProblemID(ctx context.Context, req *pb.GetSubmissionsRequest) (*pb.GetSubmissionsResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetSubmissionsByOptionalProblemID", map[string]any{
 		"method":    "GetSubmissionsByOptionalProblemID",
 		"problemId": *req.ProblemId,
@@ -920,56 +1099,30 @@ func (s *ProblemService) GetSubmissionsByOptionalProblemID(ctx context.Context,
 			"method":    "GetSubmissionsByOptionalProblemID",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID and user ID are required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and user ID are required", "VALIDATION_ERROR", nil)
 	}
 
 	cacheKey := fmt.Sprintf("submissions:%s:%s", *req.ProblemId, req.UserId)
-	cachedSubmissions, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedSubmissions != nil {
-		var submissions pb.GetSubmissionsResponse
-		cachedStr, ok := cachedSubmissions.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached submissions to string", map[string]any{
-				"method":    "GetSubmissionsByOptionalProblemID",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &submissions); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Submissions retrieved from cache", map[string]any{
-				"method":    "GetSubmissionsByOptionalProblemID",
-				"problemId": *req.ProblemId,
-				"userId":    req.UserId,
-				"cacheKey":  cacheKey,
-			}, "SERVICE", nil)
-			return &submissions, nil
-		}
-	}
-
-	resp, err := s.RepoConnInstance.GetSubmissionsByOptionalProblemID(ctx, req)
+	resp, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().List, func(ctx context.Context) (*pb.GetSubmissionsResponse, error) {
+		return s.RepoConnInstance.GetSubmissionsByOptionalProblemID(ctx, req)
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve submissions from DB", map[string]any{
 			"method":    "GetSubmissionsByOptionalProblemID",
 			"problemId": *req.ProblemId,
 			"userId":    req.UserId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to retrieve submissions")
 	}
-
-	submissionsBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal submissions", map[string]any{
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Submissions retrieved from cache", map[string]any{
 			"method":    "GetSubmissionsByOptionalProblemID",
 			"problemId": *req.ProblemId,
 			"userId":    req.UserId,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, submissionsBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache submissions", map[string]any{
-			"method":    "GetSubmissionsByOptionalProblemID",
 			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+		}, "SERVICE", nil)
+		return resp, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Submissions retrieved successfully", map[string]any{
@@ -980,9 +1133,10 @@ func (s *ProblemService) GetSubmissionsByOptionalProblemID(ctx context.Context,
 	return resp, nil
 }
 
-// GetProblemByIDSlug retrieves a problem by ID or slug
+// GetProblemByIDSlug retrieves a problem by ID or slug, with its statement in the locale the accept-language metadata
+// asks for when the problem is translated into it. Premium problems are denied to callers without the entitlement.
 func (s *ProblemService) GetProblemByIDSlug(ctx context.Context, req *pb.GetProblemByIdSlugRequest) (*pb.GetProblemByIdSlugResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetProblemByIDSlug", map[string]any{
 		"method":    "GetProblemByIDSlug",
 		"problemId": req.ProblemId,
@@ -994,60 +1148,38 @@ func (s *ProblemService) GetProblemByIDSlug(ctx context.Context, req *pb.GetProb
 			"method":    "GetProblemByIDSlug",
 			"errorType": "VALIDATION_ERROR",
 		}, "SERVICE", nil)
-		return nil, s.createGrpcError(codes.InvalidArgument, "Problem ID or slug is required", "VALIDATION_ERROR", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID or slug is required", "VALIDATION_ERROR", nil)
 	}
 
-	cacheKey := fmt.Sprintf("problem:%s", req.ProblemId)
+	// not problem:%s, GetProblem caches a different response type there
+	cacheKey := fmt.Sprintf("problem_slug_id:%s", req.ProblemId)
 	if req.ProblemId == "" {
 		cacheKey = fmt.Sprintf("problem_slug:%s", *req.Slug)
 	}
 
-	cachedProblem, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedProblem != nil {
-		var problem pb.GetProblemByIdSlugResponse
-		cachedStr, ok := cachedProblem.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached problem to string", map[string]any{
-				"method":    "GetProblemByIDSlug",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &problem); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Problem retrieved from cache", map[string]any{
-				"method":    "GetProblemByIDSlug",
-				"problemId": req.ProblemId,
-				"slug":      req.Slug,
-				"cacheKey":  cacheKey,
-			}, "SERVICE", nil)
-			return &problem, nil
-		}
-	}
-
-	resp, err := s.RepoConnInstance.GetProblemByIDSlug(ctx, req)
+	resp, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().Problem, func(ctx context.Context) (*pb.GetProblemByIdSlugResponse, error) {
+		return s.RepoConnInstance.GetProblemByIDSlug(ctx, req)
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve problem from DB", map[string]any{
 			"method":    "GetProblemByIDSlug",
 			"problemId": req.ProblemId,
 			"slug":      req.Slug,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to retrieve problem")
+	}
+	if err := s.requirePremiumAccess(ctx, traceID, "GetProblemByIDSlug", resp.GetProblemmetdata().GetProblemId()); err != nil {
 		return nil, err
 	}
-
-	problemBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal problem", map[string]any{
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Problem retrieved from cache", map[string]any{
 			"method":    "GetProblemByIDSlug",
 			"problemId": req.ProblemId,
 			"slug":      req.Slug,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, problemBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache problem", map[string]any{
-			"method":    "GetProblemByIDSlug",
 			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+		}, "SERVICE", nil)
+		return s.localizeProblem(ctx, traceID, resp), nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem retrieved successfully", map[string]any{
@@ -1055,12 +1187,13 @@ func (s *ProblemService) GetProblemByIDSlug(ctx context.Context, req *pb.GetProb
 		"problemId": req.ProblemId,
 		"slug":      req.Slug,
 	}, "SERVICE", nil)
-	return resp, nil
+	return s.localizeProblem(ctx, traceID, resp), nil
 }
 
-// GetProblemMetadataList retrieves problems by ID list
+// GetProblemMetadataList retrieves problems by ID list; premium problems are listed locked to callers without the
+// entitlement
 func (s *ProblemService) GetProblemMetadataList(ctx context.Context, req *pb.GetProblemMetadataListRequest) (*pb.GetProblemMetadataListResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetProblemMetadataList", map[string]any{
 		"method":   "GetProblemMetadataList",
 		"page":     req.Page,
@@ -1074,53 +1207,30 @@ func (s *ProblemService) GetProblemMetadataList(ctx context.Context, req *pb.Get
 		req.PageSize = 10
 	}
 
-	cacheKey := fmt.Sprintf("problem_id_list:%d:%d", req.Page, req.PageSize)
-	cachedProblems, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedProblems != nil {
-		var problems pb.GetProblemMetadataListResponse
-		cachedStr, ok := cachedProblems.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached problems to string", map[string]any{
-				"method":    "GetProblemMetadataList",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &problems); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Problem metadata list retrieved from cache", map[string]any{
-				"method":   "GetProblemMetadataList",
-				"cacheKey": cacheKey,
-				"page":     req.Page,
-				"pageSize": req.PageSize,
-			}, "SERVICE", nil)
-			return &problems, nil
-		}
-	}
-
-	resp, err := s.RepoConnInstance.GetProblemByIDList(ctx, req)
+	cacheKey := problemListCacheKey("problem_id_list", req.Page, req.PageSize, req.Tags, req.Difficulty, req.SearchQuery)
+	resp, fromCache, err := cache.GetOrLoadStale(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().List, s.cacheTTLs().ListStale, func(ctx context.Context) (*pb.GetProblemMetadataListResponse, error) {
+		return s.RepoConnInstance.GetProblemByIDList(ctx, req)
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve problem metadata list from DB", map[string]any{
 			"method":    "GetProblemMetadataList",
 			"page":      req.Page,
 			"pageSize":  req.PageSize,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to retrieve problem metadata list")
+	}
+	if resp, err = s.lockPremiumProblems(ctx, traceID, "GetProblemMetadataList", resp); err != nil {
 		return nil, err
 	}
-
-	problemsBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal problem metadata list", map[string]any{
-			"method":    "GetProblemMetadataList",
-			"page":      req.Page,
-			"pageSize":  req.PageSize,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, problemsBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache problem metadata list", map[string]any{
-			"method":    "GetProblemMetadataList",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Problem metadata list retrieved from cache", map[string]any{
+			"method":   "GetProblemMetadataList",
+			"cacheKey": cacheKey,
+			"page":     req.Page,
+			"pageSize": req.PageSize,
+		}, "SERVICE", nil)
+		return resp, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem metadata list retrieved successfully", map[string]any{
@@ -1131,9 +1241,17 @@ func (s *ProblemService) GetProblemMetadataList(ctx context.Context, req *pb.Get
 	return resp, nil
 }
 
-// RunUserCodeProblem executes user code for a problem
+// RunUserCodeProblem executes user code for a problem. Callers must be entitled to premium problems to run them;
+// validation and rejudging call runUserCodeProblem, which judges any problem.
 func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
-	traceID := uuid.New().String()
+	if err := s.requirePremiumAccess(ctx, traceIDFromContext(ctx), "RunUserCodeProblem", req.ProblemId); err != nil {
+		return nil, err
+	}
+	return s.withRunIdempotency(ctx, req, s.runUserCodeProblem)
+}
+
+func (s *ProblemService) runUserCodeProblem(ctx context.Context, req *pb.RunProblemRequest) (*pb.RunProblemResponse, error) {
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RunUserCodeProblem", map[string]any{
 		"method":        "RunUserCodeProblem",
 		"problemId":     req.ProblemId,
@@ -1146,9 +1264,9 @@ func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProb
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
 			"method":    "RunUserCodeProblem",
 			"problemId": req.ProblemId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, fmt.Errorf("problem not found: %w", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
 	}
 
 	submitCase := !req.IsRunTestcase
@@ -1203,63 +1321,79 @@ func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProb
 		return nil, fmt.Errorf("failed to marshal test cases: %w", err)
 	}
 
-	tmpl := validateCode.Template
-	if req.Language == "python" || req.Language == "javascript" || req.Language == "py" || req.Language == "js" {
-		escaped := strings.ReplaceAll(string(testCasesJSON), `"`, `\"`)
-		tmpl = strings.Replace(tmpl, "{TESTCASE_PLACEHOLDER}", escaped, 1)
-	} else {
-		tmpl = strings.Replace(tmpl, "{TESTCASE_PLACEHOLDER}", string(testCasesJSON), 1)
+	values := codetemplate.Values{
+		TestCases: string(testCasesJSON),
+		Function:  req.UserCode,
+		ClassName: codetemplate.ClassNameOf(req.UserCode),
 	}
-	tmpl = strings.Replace(tmpl, "{FUNCTION_PLACEHOLDER}", req.UserCode, 1)
-
-	compilerRequest := map[string]any{
-		"code":     tmpl,
-		"language": req.Language,
+	if strings.Contains(validateCode.Template, codetemplate.Imports) {
+		values.Imports, values.Function = codetemplate.SplitImports(req.Language, req.UserCode)
 	}
-	compilerRequestBytes, err := json.Marshal(compilerRequest)
+	tmpl, err := codetemplate.Render(validateCode.Template, req.Language, values)
 	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to serialize compiler request", map[string]any{
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to render validation template", map[string]any{
 			"method":    "RunUserCodeProblem",
 			"problemId": req.ProblemId,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-		return nil, fmt.Errorf("failed to serialize compiler request: %w", err)
-	}
-
-	msg, err := s.NatsClient.Request("problems.execute.request", compilerRequestBytes, 10*time.Second)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to execute code", map[string]any{
-			"method":    "RunUserCodeProblem",
-			"problemId": req.ProblemId,
-			"errorType": "COMPILATION_ERROR",
+			"language":  req.Language,
+			"errorType": "TEMPLATE_ERROR",
 		}, "SERVICE", err)
 		return &pb.RunProblemResponse{
 			Success:       false,
-			ErrorType:     "COMPILATION_ERROR",
-			Message:       "Failed to execute code",
+			ErrorType:     "TEMPLATE_ERROR",
+			Message:       err.Error(),
 			ProblemId:     req.ProblemId,
 			Language:      req.Language,
 			IsRunTestcase: req.IsRunTestcase,
 		}, nil
 	}
 
-	var result map[string]any
-	if err := json.Unmarshal(msg.Data, &result); err != nil {
+	profile := s.executionProfile(ctx, traceID, req.Language)
+	compilerRequestBytes, err := json.Marshal(s.newCompilerRequest(tmpl, req.Language, profile))
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to serialize compiler request", map[string]any{
+			"method":    "RunUserCodeProblem",
+			"problemId": req.ProblemId,
+			"errorType": "MARSHAL_ERROR",
+		}, "SERVICE", err)
+		return nil, fmt.Errorf("failed to serialize compiler request: %w", err)
+	}
+
+	// identical code against identical test cases gets the verdict it got last time
+	resultKey := executionResultKey(req.ProblemId, req.Language, compilerRequestBytes)
+	resultData := s.cachedExecutionResult(ctx, traceID, resultKey)
+	memoized := resultData != nil
+	if !memoized {
+		msg, err := s.requestExecution(ctx, traceID, compilerRequestBytes)
+		if err != nil {
+			// the code never ran, so this is reported as an unavailable engine rather than a failed submission
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to reach execution engine", map[string]any{
+				"method":    "RunUserCodeProblem",
+				"problemId": req.ProblemId,
+				"errorType": "ENGINE_UNAVAILABLE",
+			}, "SERVICE", err)
+			return nil, s.createGrpcError(ctx, codes.Unavailable, "Code execution is temporarily unavailable, please retry", "ENGINE_UNAVAILABLE", err, customerrors.RetryAfter(s.engine.Backoff))
+		}
+		resultData = msg.Data
+	}
+
+	var result model.CompilerResponse
+	if err := json.Unmarshal(resultData, &result); err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to parse execution result", map[string]any{
 			"method":    "RunUserCodeProblem",
 			"problemId": req.ProblemId,
 			"errorType": "EXECUTION_ERROR",
 		}, "SERVICE", err)
+		s.deadLetter(ctx, traceID, model.DeadLetterSubjectExecutionResult, resultData, err, 1, false)
 		return nil, fmt.Errorf("failed to parse execution result: %w", err)
 	}
 
-	output, ok := result["output"].(string)
-	if !ok {
+	if err := result.Validate(); err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid execution result format", map[string]any{
 			"method":    "RunUserCodeProblem",
 			"problemId": req.ProblemId,
 			"errorType": "EXECUTION_ERROR",
-		}, "SERVICE", nil)
+		}, "SERVICE", err)
+		s.deadLetter(ctx, traceID, model.DeadLetterSubjectExecutionResult, resultData, err, 1, false)
 		return &pb.RunProblemResponse{
 			Success:       false,
 			ErrorType:     "EXECUTION_ERROR",
@@ -1269,18 +1403,41 @@ func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProb
 			IsRunTestcase: req.IsRunTestcase,
 		}, nil
 	}
+	if !memoized {
+		s.memoizeExecutionResult(ctx, traceID, resultKey, resultData, &result)
+	}
 
-	if strings.Contains(output, "syntax error") || strings.Contains(output, "# command-line-arguments") {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Compilation error in user code", map[string]any{
+	if errorType, message, ok := userCodeFailure(&result); ok {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "User code failed in execution engine", map[string]any{
 			"method":    "RunUserCodeProblem",
 			"problemId": req.ProblemId,
-			"errorType": "COMPILATION_ERROR",
+			"errorType": errorType,
 		}, "SERVICE", nil)
-		go s.processSubmission(ctx, req, "FAILED", submitCase, *problem, req.UserCode)
+		submissionVerdicts.Add(errorType, 1)
+		// the request context is cancelled once the response is sent, the submission must still be stored
+		s.runInBackground(ctx, func(ctx context.Context) {
+			s.processSubmission(ctx, req, "FAILED", submitCase, *problem, req.UserCode, 0)
+		})
 		return &pb.RunProblemResponse{
 			Success:       false,
-			ErrorType:     "COMPILATION_ERROR",
-			Message:       output,
+			ErrorType:     errorType,
+			Message:       message,
+			ProblemId:     req.ProblemId,
+			Language:      req.Language,
+			IsRunTestcase: req.IsRunTestcase,
+		}, nil
+	}
+	if result.Error != nil {
+		// the engine failed rather than the user's code, nothing is stored
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Execution engine reported an error", map[string]any{
+			"method":    "RunUserCodeProblem",
+			"problemId": req.ProblemId,
+			"errorType": result.Error.Code,
+		}, "SERVICE", errors.New(result.Error.Message))
+		return &pb.RunProblemResponse{
+			Success:       false,
+			ErrorType:     "EXECUTION_ERROR",
+			Message:       result.Error.Message,
 			ProblemId:     req.ProblemId,
 			Language:      req.Language,
 			IsRunTestcase: req.IsRunTestcase,
@@ -1288,7 +1445,7 @@ func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProb
 	}
 
 	var executionStatsResult model.ExecutionStatsResult
-	if err := json.Unmarshal([]byte(output), &executionStatsResult); err != nil {
+	if err := json.Unmarshal([]byte(result.Output), &executionStatsResult); err != nil {
 		executionStatsResult = model.ExecutionStatsResult{OverallPass: false}
 	}
 
@@ -1296,15 +1453,22 @@ func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProb
 	if executionStatsResult.OverallPass {
 		status = "SUCCESS"
 	}
+	submissionVerdicts.Add(status, 1)
+
+	// the executor reports wall time as a duration string, e.g. "1.230549718s"
+	var executionTime float64
+	if duration, err := time.ParseDuration(result.ExecutionTime); err == nil {
+		executionTime = duration.Seconds()
+	}
 
-	s.processSubmission(ctx, req, status, submitCase, *problem, req.UserCode)
+	s.processSubmission(ctx, req, status, submitCase, *problem, req.UserCode, executionTime)
 	if submitCase && req.UserId != "" {
 		cacheKeys := []string{
 			fmt.Sprintf("submissions:%s:%s", req.ProblemId, req.UserId),
 			fmt.Sprintf("stats:%s", req.UserId),
 		}
 		for _, cacheKey := range cacheKeys {
-			if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+			if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 				s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 					"method":    "RunUserCodeProblem",
 					"cacheKey":  cacheKey,
@@ -1320,19 +1484,20 @@ func (s *ProblemService) RunUserCodeProblem(ctx context.Context, req *pb.RunProb
 		"language":      req.Language,
 		"isRunTestcase": req.IsRunTestcase,
 		"status":        status,
+		"memoized":      memoized,
 	}, "SERVICE", nil)
 	return &pb.RunProblemResponse{
 		Success:       true,
 		ProblemId:     req.ProblemId,
 		Language:      req.Language,
 		IsRunTestcase: req.IsRunTestcase,
-		Message:       output,
+		Message:       result.Output,
 	}, nil
 }
 
 // processSubmission handles submission processing
-func (s *ProblemService) processSubmission(ctx context.Context, req *pb.RunProblemRequest, status string, submitCasePass bool, problem model.Problem, userCode string) {
-	traceID := uuid.New().String()
+func (s *ProblemService) processSubmission(ctx context.Context, req *pb.RunProblemRequest, status string, submitCasePass bool, problem model.Problem, userCode string, executionTime float64) {
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting processSubmission", map[string]any{
 		"method":    "processSubmission",
 		"problemId": req.ProblemId,
@@ -1350,12 +1515,16 @@ func (s *ProblemService) processSubmission(ctx context.Context, req *pb.RunProbl
 		return
 	}
 
+	// the request validator rejects countries that name none, so an unknown one here only comes from internal
+	// callers and is stored as no country rather than as a new entity
+	country, _ := utils.NormalizeCountry(req.GetCountry())
+
 	var submission model.Submission
 	if req != nil {
 		submission = model.Submission{
 			ID:            primitive.NewObjectID(),
 			UserID:        req.UserId,
-			Country:       *req.Country,
+			Country:       country,
 			ProblemID:     req.ProblemId,
 			ChallengeID:   nil,
 			Title:         problem.Title,
@@ -1364,27 +1533,38 @@ func (s *ProblemService) processSubmission(ctx context.Context, req *pb.RunProbl
 			Score:         0,
 			Language:      req.Language,
 			Status:        status,
-			ExecutionTime: 0,
+			ExecutionTime: executionTime,
 			Difficulty:    problem.Difficulty,
 		}
 	}
 
-	if err := s.RepoConnInstance.PushSubmissionData(ctx, &submission, status); err != nil {
+	ranksBefore := s.currentUserRanks(submission.UserID)
+
+	if err := s.RepoConnInstance.PushSubmissionData(ctx, &submission, status, s.submissionOutboxEvents(traceID)); err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to push submission data", map[string]any{
 			"method":    "processSubmission",
 			"problemId": req.ProblemId,
 			"userId":    req.UserId,
 			"errorType": "DB_ERROR",
 		}, "SERVICE", err)
+	} else {
+		if banned, _ := s.RepoConnInstance.IsLeaderboardBanned(ctx, submission.UserID); submission.IsFirst && !banned {
+			s.publishRankChanges(ctx, traceID, submission.UserID, ranksBefore, s.currentUserRanks(submission.UserID))
+		}
+		// seasonal boards are updated by the submission.accepted consumer once the relay publishes the event
+		s.publishSubmissionEvents(ctx, traceID, submission)
 	}
 
 	cacheKeys := []string{
 		fmt.Sprintf("submissions:%s:%s", req.ProblemId, req.UserId),
 		fmt.Sprintf("heatmap:%s:%d:%d", req.UserId, time.Now().Year(), time.Now().Month()),
 		fmt.Sprintf("stats:%s", req.UserId),
+		fmt.Sprintf("language_stats:%s", req.UserId),
+		fmt.Sprintf("heatmap_yearly:%s:%d", req.UserId, 0),
+		fmt.Sprintf("heatmap_yearly:%s:%d", req.UserId, time.Now().UTC().Year()),
 	}
 	for _, cacheKey := range cacheKeys {
-		if err := s.RedisCacheClient.Delete(cacheKey); err != nil {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
 			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
 				"method":    "processSubmission",
 				"cacheKey":  cacheKey,
@@ -1403,67 +1583,44 @@ func (s *ProblemService) processSubmission(ctx context.Context, req *pb.RunProbl
 
 // GetProblemsDoneStatistics retrieves problem completion stats
 func (s *ProblemService) GetProblemsDoneStatistics(ctx context.Context, req *pb.GetProblemsDoneStatisticsRequest) (*pb.GetProblemsDoneStatisticsResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetProblemsDoneStatistics", map[string]any{
 		"method": "GetProblemsDoneStatistics",
 		"userId": req.UserId,
 	}, "SERVICE", nil)
 
 	cacheKey := fmt.Sprintf("stats:%s", req.UserId)
-	cachedStats, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedStats != nil {
-		var stats pb.GetProblemsDoneStatisticsResponse
-		cachedStr, ok := cachedStats.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached stats to string", map[string]any{
-				"method":    "GetProblemsDoneStatistics",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &stats); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Problem stats retrieved from cache", map[string]any{
-				"method":   "GetProblemsDoneStatistics",
-				"userId":   req.UserId,
-				"cacheKey": cacheKey,
-			}, "SERVICE", nil)
-			return &stats, nil
+	resp, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().Stats, func(ctx context.Context) (*pb.GetProblemsDoneStatisticsResponse, error) {
+		data, err := s.RepoConnInstance.ProblemsDoneStatistics(ctx, req.UserId)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	data, err := s.RepoConnInstance.ProblemsDoneStatistics(req.UserId)
+		return &pb.GetProblemsDoneStatisticsResponse{
+			Data: &pb.ProblemsDoneStatistics{
+				MaxEasyCount:    data.MaxEasyCount,
+				DoneEasyCount:   data.DoneEasyCount,
+				MaxMediumCount:  data.MaxMediumCount,
+				DoneMediumCount: data.DoneMediumCount,
+				MaxHardCount:    data.MaxHardCount,
+				DoneHardCount:   data.DoneHardCount,
+			},
+		}, nil
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve problem stats from DB", map[string]any{
 			"method":    "GetProblemsDoneStatistics",
 			"userId":    req.UserId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to retrieve problem stats")
 	}
-
-	resp := &pb.GetProblemsDoneStatisticsResponse{
-		Data: &pb.ProblemsDoneStatistics{
-			MaxEasyCount:    data.MaxEasyCount,
-			DoneEasyCount:   data.DoneEasyCount,
-			MaxMediumCount:  data.MaxMediumCount,
-			DoneMediumCount: data.DoneMediumCount,
-			MaxHardCount:    data.MaxHardCount,
-			DoneHardCount:   data.DoneHardCount,
-		},
-	}
-
-	statsBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal problem stats", map[string]any{
-			"method":    "GetProblemsDoneStatistics",
-			"userId":    req.UserId,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, statsBytes, 5*time.Second); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache problem stats", map[string]any{
-			"method":    "GetProblemsDoneStatistics",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Problem stats retrieved from cache", map[string]any{
+			"method":   "GetProblemsDoneStatistics",
+			"userId":   req.UserId,
+			"cacheKey": cacheKey,
+		}, "SERVICE", nil)
+		return resp, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Problem stats retrieved successfully", map[string]any{
@@ -1475,7 +1632,7 @@ func (s *ProblemService) GetProblemsDoneStatistics(ctx context.Context, req *pb.
 
 // GetMonthlyActivityHeatmap retrieves monthly activity heatmap
 func (s *ProblemService) GetMonthlyActivityHeatmap(ctx context.Context, req *pb.GetMonthlyActivityHeatmapRequest) (*pb.GetMonthlyActivityHeatmapResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetMonthlyActivityHeatmap", map[string]any{
 		"method": "GetMonthlyActivityHeatmap",
 		"userId": req.UserID,
@@ -1484,66 +1641,44 @@ func (s *ProblemService) GetMonthlyActivityHeatmap(ctx context.Context, req *pb.
 	}, "SERVICE", nil)
 
 	cacheKey := fmt.Sprintf("heatmap:%s:%d:%d", req.UserID, req.Year, req.Month)
-	cachedData, err := s.RedisCacheClient.Get(cacheKey)
-	if err == nil && cachedData != nil {
-		var heatmap pb.GetMonthlyActivityHeatmapResponse
-		cachedStr, ok := cachedData.(string)
-		if !ok {
-			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to assert cached heatmap to string", map[string]any{
-				"method":    "GetMonthlyActivityHeatmap",
-				"cacheKey":  cacheKey,
-				"errorType": "CACHE_ERROR",
-			}, "SERVICE", nil)
-		} else if err := json.Unmarshal([]byte(cachedStr), &heatmap); err == nil {
-			s.logger.Log(zapcore.InfoLevel, traceID, "Heatmap retrieved from cache", map[string]any{
-				"method":   "GetMonthlyActivityHeatmap",
-				"userId":   req.UserID,
-				"cacheKey": cacheKey,
-			}, "SERVICE", nil)
-			return &heatmap, nil
-		}
-	}
+	now := time.Now()
+	nextMidnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	ttl := time.Until(nextMidnight)
 
-	data, err := s.RepoConnInstance.GetMonthlyContributionHistory(req.UserID, int(req.Month), int(req.Year))
+	resp, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, ttl, func(ctx context.Context) (*pb.GetMonthlyActivityHeatmapResponse, error) {
+		data, err := s.RepoConnInstance.GetMonthlyContributionHistory(ctx, req.UserID, int(req.Month), int(req.Year))
+		if err != nil {
+			return nil, err
+		}
+		resp := &pb.GetMonthlyActivityHeatmapResponse{
+			Data: make([]*pb.ActivityDay, len(data.Data)),
+		}
+		for i, day := range data.Data {
+			resp.Data[i] = &pb.ActivityDay{
+				Date:     day.Date,
+				Count:    int32(day.Count),
+				IsActive: day.IsActive,
+			}
+		}
+		return resp, nil
+	})
 	if err != nil {
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve heatmap from DB", map[string]any{
 			"method":    "GetMonthlyActivityHeatmap",
 			"userId":    req.UserID,
 			"year":      req.Year,
 			"month":     req.Month,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, err
+		return nil, s.repoError(ctx, err, "Failed to retrieve heatmap")
 	}
-
-	resp := &pb.GetMonthlyActivityHeatmapResponse{
-		Data: make([]*pb.ActivityDay, len(data.Data)),
-	}
-	for i, day := range data.Data {
-		resp.Data[i] = &pb.ActivityDay{
-			Date:     day.Date,
-			Count:    int32(day.Count),
-			IsActive: day.IsActive,
-		}
-	}
-
-	now := time.Now()
-	nextMidnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
-	ttl := time.Until(nextMidnight)
-
-	heatmapBytes, err := json.Marshal(resp)
-	if err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to marshal heatmap response", map[string]any{
-			"method":    "GetMonthlyActivityHeatmap",
-			"userId":    req.UserID,
-			"errorType": "MARSHAL_ERROR",
-		}, "SERVICE", err)
-	} else if err := s.RedisCacheClient.Set(cacheKey, heatmapBytes, ttl); err != nil {
-		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache heatmap", map[string]any{
-			"method":    "GetMonthlyActivityHeatmap",
-			"cacheKey":  cacheKey,
-			"errorType": "CACHE_ERROR",
-		}, "SERVICE", err)
+	if fromCache {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Heatmap retrieved from cache", map[string]any{
+			"method":   "GetMonthlyActivityHeatmap",
+			"userId":   req.UserID,
+			"cacheKey": cacheKey,
+		}, "SERVICE", nil)
+		return resp, nil
 	}
 
 	s.logger.Log(zapcore.InfoLevel, traceID, "Heatmap retrieved successfully", map[string]any{
@@ -1569,7 +1704,7 @@ func MapProblemStatistics(input model.ProblemsDoneStatistics) pb.ProblemsDoneSta
 
 // GetTopKGlobal retrieves top K global users
 func (s *ProblemService) GetTopKGlobal(ctx context.Context, req *pb.GetTopKGlobalRequest) (*pb.GetTopKGlobalResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetTopKGlobal", map[string]any{
 		"method": "GetTopKGlobal",
 		"k":      req.K,
@@ -1636,7 +1771,7 @@ func (s *ProblemService) GetTopKGlobal(ctx context.Context, req *pb.GetTopKGloba
 
 // GetTopKEntity retrieves top K users for an entity
 func (s *ProblemService) GetTopKEntity(ctx context.Context, req *pb.GetTopKEntityRequest) (*pb.GetTopKEntityResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetTopKEntity", map[string]any{
 		"method": "GetTopKEntity",
 		"entity": req.Entity,
@@ -1703,15 +1838,16 @@ func (s *ProblemService) GetTopKEntity(ctx context.Context, req *pb.GetTopKEntit
 
 // GetUserRank retrieves a user's rank
 func (s *ProblemService) GetUserRank(ctx context.Context, req *pb.GetUserRankRequest) (*pb.GetUserRankResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetUserRank", map[string]any{
 		"method": "GetUserRank",
 		"userId": req.UserId,
 	}, "SERVICE", nil)
 
+	// ZREVRANK is O(log n); MongoDB only answers for users missing from the board, e.g. while it is rebuilt
 	startRedis := time.Now()
 	globalRank, err := s.LB.GetRankGlobal(req.UserId)
-	if err == nil {
+	if err == nil && globalRank >= 0 {
 		entityRank, err := s.LB.GetRankEntity(req.UserId)
 		if err == nil {
 			s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved user rank from Redis", map[string]any{
@@ -1739,9 +1875,9 @@ func (s *ProblemService) GetUserRank(ctx context.Context, req *pb.GetUserRankReq
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch user rank from MongoDB", map[string]any{
 			"method":    "GetUserRank",
 			"userId":    req.UserId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, fmt.Errorf("failed to fetch user rank from MongoDB: %w", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch user rank")
 	}
 	s.logger.Log(zapcore.InfoLevel, traceID, "Retrieved user rank from MongoDB", map[string]any{
 		"method":     "GetUserRank",
@@ -1765,7 +1901,7 @@ func (s *ProblemService) GetUserRank(ctx context.Context, req *pb.GetUserRankReq
 
 // GetLeaderboardData retrieves leaderboard data for a user
 func (s *ProblemService) GetLeaderboardData(ctx context.Context, req *pb.GetLeaderboardDataRequest) (*pb.GetLeaderboardDataResponse, error) {
-	traceID := uuid.New().String()
+	traceID := traceIDFromContext(ctx)
 	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetLeaderboardData", map[string]any{
 		"method": "GetLeaderboardData",
 		"userId": req.UserId,
@@ -1835,9 +1971,9 @@ func (s *ProblemService) GetLeaderboardData(ctx context.Context, req *pb.GetLead
 		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch user ranks from MongoDB", map[string]any{
 			"method":    "GetLeaderboardData",
 			"userId":    req.UserId,
-			"errorType": "DB_ERROR",
+			"errorType": customerrors.Type(err),
 		}, "SERVICE", err)
-		return nil, fmt.Errorf("failed to fetch user ranks from MongoDB: %w", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch user ranks")
 	}
 	topKGlobal, err := s.RepoConnInstance.GetTopKGlobalMongo(ctx, 10)
 	if err != nil {
@@ -1898,8 +2034,26 @@ func (s *ProblemService) GetLeaderboardData(ctx context.Context, req *pb.GetLead
 }
 
 func (s *ProblemService) ForceChangeUserEntityInSubmission(ctx context.Context, req *pb.ForceChangeUserEntityInSubmissionRequest) (*pb.ForceChangeUserEntityInSubmissionResponse, error) {
-	s.LB.UpdateEntityByUserID(req.UserId, req.Entity)
-	s.RepoConnInstance.ForceChangeUserCountryInSubmission(ctx, req)
+	traceID := traceIDFromContext(ctx)
+	entity, ok := utils.NormalizeCountry(req.Entity)
+	if !ok {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Entity must be an ISO 3166-1 country code", "VALIDATION_ERROR", nil)
+	}
+	// same path as user.country.changed events, so a manual change also reaches the seasonal boards
+	if err := s.changeUserEntity(ctx, req.UserId, entity); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to change user entity", map[string]any{
+			"method":    "ForceChangeUserEntityInSubmission",
+			"userId":    req.UserId,
+			"errorType": "ENTITY_UPDATE_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to change user entity", "ENTITY_UPDATE_ERROR", err)
+	}
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionChangeUserEntity,
+		TargetType: model.AuditTargetUser,
+		TargetID:   req.UserId,
+		After:      map[string]any{"entity": entity},
+	})
 	return &pb.ForceChangeUserEntityInSubmissionResponse{}, nil
 }
 
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	defaultServiceStatsDays = 14
+	maxServiceStatsDays     = 90
+)
+
+// GetServiceStats returns the admin dashboard overview in one call: problem, submission, challenge and leaderboard
+// totals from MongoDB, and the cache hit rate and engine error rate of this replica
+func (s *ProblemService) GetServiceStats(ctx context.Context, req *model.GetServiceStatsRequest) (*model.GetServiceStatsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetServiceStats", map[string]any{
+		"method":  "GetServiceStats",
+		"adminId": req.AdminID,
+		"days":    req.Days,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "GetServiceStats",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	days := req.Days
+	if days <= 0 {
+		days = defaultServiceStatsDays
+	}
+	if days > maxServiceStatsDays {
+		days = maxServiceStatsDays
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	stats, err := s.RepoConnInstance.GetServiceStatsMongo(ctx, since)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to aggregate service stats", map[string]any{
+			"method":    "GetServiceStats",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch service stats")
+	}
+
+	// every day of the window is listed, days without submissions as 0
+	counts := make(map[string]int64, len(stats.SubmissionsPerDay))
+	for _, day := range stats.SubmissionsPerDay {
+		counts[day.Date] = day.Count
+	}
+	stats.SubmissionsPerDay = make([]model.DailyCount, 0, days)
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		stats.SubmissionsPerDay = append(stats.SubmissionsPerDay, model.DailyCount{Date: date, Count: counts[date]})
+	}
+
+	hits, misses := cache.LookupCounts()
+	stats.Cache = rateStats(hits, hits+misses)
+
+	var engineTotal, engineOK int64
+	for outcome, count := range engineRequestDuration.Counts() {
+		engineTotal += int64(count)
+		if outcome == "ok" {
+			engineOK += int64(count)
+		}
+	}
+	stats.Engine = rateStats(engineTotal-engineOK, engineTotal)
+	stats.GeneratedAt = now
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Service stats retrieved successfully", map[string]any{
+		"method":   "GetServiceStats",
+		"problems": stats.Problems.Total,
+		"days":     days,
+	}, "SERVICE", nil)
+	return &model.GetServiceStatsResponse{Stats: *stats}, nil
+}
+
+// rateStats is matched out of total, 0 when nothing was counted
+func rateStats(matched, total int64) model.RateStats {
+	if total == 0 {
+		return model.RateStats{}
+	}
+	return model.RateStats{Total: total, Rate: float64(matched) / float64(total)}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"xcode/cache"
+	configs "xcode/config"
+	"xcode/interceptor"
+	zap_betterstack "xcode/logger"
+	"xcode/repository"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestService returns a service over repo with an in-memory cache, a logger that drops everything and no NATS
+// or leaderboard
+func newTestService(t *testing.T, repo repository.ProblemRepository) *ProblemService {
+	t.Helper()
+	logger := zap_betterstack.NewBetterStackLogStreamer("", "test", "", zap.NewNop(), zap_betterstack.Options{
+		Level: zap.NewAtomicLevelAt(zapcore.FatalLevel),
+	})
+	ttl := configs.CacheTTLConfig{Problem: time.Minute, List: time.Minute, ListStale: time.Minute, Entitlement: time.Minute}
+	return NewService(repo, nil, nil, newMemoryCache(), ttl, configs.EngineConfig{MaxConcurrency: 1}, nil, nil, "test", nil, logger)
+}
+
+// memoryCache keeps string values the way Redis returns them; calls it does not implement panic through the nil
+// embedded Cache, so a test notices when the code under test starts depending on them
+type memoryCache struct {
+	cache.Cache
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: map[string]string{}}
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, ok := c.values[key]; ok {
+		return value, nil
+	}
+	return nil, nil
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch v := value.(type) {
+	case []byte:
+		c.values[key] = string(v)
+	case string:
+		c.values[key] = v
+	default:
+		return errors.New("memoryCache stores strings and bytes only")
+	}
+	return nil
+}
+
+func (c *memoryCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	c.mu.Lock()
+	_, exists := c.values[key]
+	c.mu.Unlock()
+	if exists {
+		return false, nil
+	}
+	return true, c.Set(ctx, key, value, expiration)
+}
+
+func (c *memoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryCache) DeletePattern(_ context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefix := strings.TrimSuffix(pattern, "*")
+	for key := range c.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.values, key)
+		}
+	}
+	return nil
+}
+
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("%v carries no ErrorInfo", err)
+	return nil
+}
+
+func TestCreateGrpcErrorHidesCause(t *testing.T) {
+	s := newTestService(t, nil)
+	cause := errors.New("connection(mongo-0:27017) socket was unexpectedly closed: EOF")
+
+	ctx := interceptor.ContextWithTraceID(context.Background(), "trace-1")
+	err := s.createGrpcError(ctx, codes.Internal, "Failed to fetch problem", "DB_ERROR", cause)
+
+	st := status.Convert(err)
+	if st.Code() != codes.Internal || st.Message() != "Failed to fetch problem" {
+		t.Fatalf("status = %v %q, want Internal with the public message", st.Code(), st.Message())
+	}
+	info := errorInfo(t, err)
+	if info.Reason != "DB_ERROR" {
+		t.Errorf("reason = %q, want DB_ERROR", info.Reason)
+	}
+	if info.Metadata["traceId"] != "trace-1" {
+		t.Errorf("traceId = %q, want the request's trace-1", info.Metadata["traceId"])
+	}
+	for key, value := range info.Metadata {
+		if key != "traceId" {
+			t.Errorf("ErrorInfo metadata has %s=%q, want only traceId", key, value)
+		}
+		if strings.Contains(value, "mongo-0") {
+			t.Errorf("ErrorInfo metadata %s leaks the cause: %q", key, value)
+		}
+	}
+}
+
+// GetProblemByIDSlug and GetProblem cache different response types for the same problem; neither may read the other's
+func TestGetProblemByIDSlugDoesNotShadowGetProblem(t *testing.T) {
+	s, repo := newPremiumTestService(t)
+	id := freeProblemID.Hex()
+	repo.EXPECT().GetProblemByIDSlug(gomock.Any(), gomock.Any()).Return(&pb.GetProblemByIdSlugResponse{
+		Problemmetdata: &pb.ProblemMetadataLite{ProblemId: id, Title: "Two Sum"},
+	}, nil)
+	repo.EXPECT().GetProblem(gomock.Any(), gomock.Any()).Return(premiumTestProblem(freeProblemID), nil).MinTimes(1)
+	ctx := premiumCallers[1].ctx
+
+	if _, err := s.GetProblemByIDSlug(ctx, &pb.GetProblemByIdSlugRequest{ProblemId: id}); err != nil {
+		t.Fatalf("GetProblemByIDSlug: %v", err)
+	}
+	for range 2 {
+		resp, err := s.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: id})
+		if err != nil {
+			t.Fatalf("GetProblem: %v", err)
+		}
+		if resp.GetProblem().GetDescription() != "Find two numbers adding up to target." {
+			t.Errorf("description = %q, want the statement", resp.GetProblem().GetDescription())
+		}
+	}
+	slug, err := s.GetProblemByIDSlug(ctx, &pb.GetProblemByIdSlugRequest{ProblemId: id})
+	if err != nil {
+		t.Fatalf("GetProblemByIDSlug: %v", err)
+	}
+	if slug.GetProblemmetdata().GetTitle() != "Two Sum" {
+		t.Errorf("title = %q, want the cached Two Sum", slug.GetProblemmetdata().GetTitle())
+	}
+}
+
+func TestListProblemsCachesEachFilterSet(t *testing.T) {
+	s, repo := newPremiumTestService(t)
+	repo.EXPECT().ListProblems(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *pb.ListProblemsRequest, _ []string) (*pb.ListProblemsResponse, error) {
+			return &pb.ListProblemsResponse{Problems: []*pb.Problem{{ProblemId: freeProblemID.Hex(), Title: req.Difficulty + " " + req.SearchQuery}}}, nil
+		}).Times(2)
+
+	list := func(req *pb.ListProblemsRequest) string {
+		t.Helper()
+		resp, err := s.ListProblems(context.Background(), req)
+		if err != nil {
+			t.Fatalf("ListProblems: %v", err)
+		}
+		return resp.GetProblems()[0].GetTitle()
+	}
+	easy := &pb.ListProblemsRequest{Page: 1, PageSize: 10, Tags: []string{"array", "hash"}, Difficulty: "easy", SearchQuery: "sum"}
+	hard := &pb.ListProblemsRequest{Page: 1, PageSize: 10, Tags: []string{"graph"}, Difficulty: "hard"}
+	if got := list(easy); got != "easy sum" {
+		t.Fatalf("easy page = %q", got)
+	}
+	if got := list(hard); got != "hard " {
+		t.Errorf("hard page = %q, want its own page rather than the cached easy one", got)
+	}
+	// tags are matched with $all, so their order does not make a different page
+	reordered := &pb.ListProblemsRequest{Page: 1, PageSize: 10, Tags: []string{"hash", "array", "hash"}, Difficulty: "easy", SearchQuery: "sum"}
+	if got := list(reordered); got != "easy sum" {
+		t.Errorf("reordered tags page = %q, want the cached easy page", got)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+)
+
+// runInBackground runs fn outside the request that triggered it, on a context that keeps the request's values, such
+// as the trace ID, but is not cancelled with it, and keeps track of it so WaitForBackgroundWork can let it finish on
+// shutdown
+func (s *ProblemService) runInBackground(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		fn(ctx)
+	}()
+}
+
+// WaitForBackgroundWork blocks until work started with runInBackground has finished or ctx is done
+func (s *ProblemService) WaitForBackgroundWork(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// SetFunctionSignature sets or, with a nil signature, removes the function signature editors show for a problem.
+// It is metadata only: the validation templates and the problem's validation status are left alone.
+func (s *ProblemService) SetFunctionSignature(ctx context.Context, req *model.SetFunctionSignatureRequest) (*model.SetFunctionSignatureResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting SetFunctionSignature", map[string]any{
+		"method":    "SetFunctionSignature",
+		"problemId": req.ProblemID,
+		"adminId":   req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "SetFunctionSignature",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.Signature != nil {
+		if err := req.Signature.Validate(); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid function signature", map[string]any{
+				"method":    "SetFunctionSignature",
+				"problemId": req.ProblemID,
+				"errorType": "VALIDATION_ERROR",
+			}, "SERVICE", err)
+			return nil, s.createGrpcError(ctx, codes.InvalidArgument, err.Error(), "VALIDATION_ERROR", err)
+		}
+	}
+
+	problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "SetFunctionSignature",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+	if err := s.RepoConnInstance.SetFunctionSignature(ctx, req.ProblemID, req.Signature); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to set function signature", map[string]any{
+			"method":    "SetFunctionSignature",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to set function signature")
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionSetSignature,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetProblem,
+		TargetID:   req.ProblemID,
+		Before:     signatureAuditSummary(problem.Signature),
+		After:      signatureAuditSummary(req.Signature),
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Function signature set", map[string]any{
+		"method":    "SetFunctionSignature",
+		"problemId": req.ProblemID,
+		"removed":   req.Signature == nil,
+	}, "SERVICE", nil)
+	return &model.SetFunctionSignatureResponse{Signature: req.Signature}, nil
+}
+
+// GetFunctionSignature returns the function signature of a problem, nil when none was set
+func (s *ProblemService) GetFunctionSignature(ctx context.Context, req *model.GetFunctionSignatureRequest) (*model.GetFunctionSignatureResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "GetFunctionSignature",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+	return &model.GetFunctionSignatureResponse{Signature: problem.Signature}, nil
+}
+
+func signatureAuditSummary(signature *model.FunctionSignature) map[string]any {
+	if signature == nil {
+		return nil
+	}
+	params := make([]string, len(signature.Params))
+	for i, param := range signature.Params {
+		params[i] = param.Name + " " + param.Type
+	}
+	return map[string]any{"name": signature.Name, "params": params, "returnType": signature.ReturnType}
+}
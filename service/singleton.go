@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"xcode/cache"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	leaderboardSyncLockTTL         = 30 * time.Minute
+	seasonRolloverLockTTL          = 10 * time.Minute
+	dailySnapshotLockTTL           = 10 * time.Minute
+	draftFlushLockTTL              = 4 * time.Minute
+	leaderboardOutboxLockTTL       = 50 * time.Second
+	outboxRelayLockTTL             = 30 * time.Second
+	webhookDispatchLockTTL         = 2 * time.Minute
+	softDeletePurgeLockTTL         = 30 * time.Minute
+	contestFinalizeLockTTL         = 5 * time.Minute
+	difficultyRecalibrationLockTTL = 30 * time.Minute
+	firstSuccessCheckLockTTL       = 30 * time.Minute
+)
+
+// runSingleton runs fn on at most one replica at a time, guarded by a Redis lock named after the job.
+// Scheduled jobs that must run once per tick keep the lock until it expires, so a replica whose cron fires
+// a little later does not repeat the work; jobs that are safe to repeat release it as soon as they finish.
+func (s *ProblemService) runSingleton(ctx context.Context, job string, ttl time.Duration, keepUntilExpiry bool, fn func(ctx context.Context)) {
+	traceID := traceIDFromContext(ctx)
+	lock, err := s.RedisCacheClient.AcquireLock(ctx, "lock:"+job, ttl)
+	if errors.Is(err, cache.ErrLockNotAcquired) {
+		s.logger.Log(zapcore.InfoLevel, traceID, "Skipping job, another replica holds the lock", map[string]any{
+			"method": "runSingleton",
+			"job":    job,
+		}, "SERVICE", nil)
+		return
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to acquire job lock", map[string]any{
+			"method":    "runSingleton",
+			"job":       job,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+		return
+	}
+
+	fn(ctx)
+
+	if !keepUntilExpiry {
+		lock.Release(ctx)
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	maxSolutionTitleLength = 150
+	maxSolutionBodyLength  = 20000
+
+	defaultSolutionPageSize = 20
+	maxSolutionPageSize     = 100
+
+	// a user may post a few solutions in a row, then one every ten minutes
+	solutionPostRate  = 1.0 / 600
+	solutionPostBurst = 3
+	// votes come faster, but not at the pace of a script
+	solutionVoteRate  = 1.0
+	solutionVoteBurst = 20
+)
+
+// solutionListCacheKey is the cache key of one page of a problem's gallery; every page of a problem shares the
+// prefix so a post or a vote can drop them all
+func solutionListCacheKey(problemID, sort string, page, limit int64) string {
+	return fmt.Sprintf("solutions:%s:%s:%d:%d", problemID, sort, page, limit)
+}
+
+// PostSolution posts a write-up of one of the user's accepted submissions to the problem's gallery. The code and
+// language are taken from the submission, so only solutions that passed are shown.
+func (s *ProblemService) PostSolution(ctx context.Context, req *model.PostSolutionRequest) (*model.PostSolutionResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting PostSolution", map[string]any{
+		"method":       "PostSolution",
+		"submissionId": req.SubmissionID,
+		"userId":       req.UserID,
+	}, "SERVICE", nil)
+
+	title := strings.TrimSpace(req.Title)
+	switch {
+	case req.SubmissionID == "" || req.UserID == "":
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Submission ID and user ID are required", "VALIDATION_ERROR", nil)
+	case title == "" || len(title) > maxSolutionTitleLength:
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("Title must be 1 to %d characters", maxSolutionTitleLength), "VALIDATION_ERROR", nil)
+	case len(req.Body) > maxSolutionBodyLength:
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("Body cannot be longer than %d characters", maxSolutionBodyLength), "VALIDATION_ERROR", nil)
+	}
+
+	submission, err := s.RepoConnInstance.GetSubmissionByID(ctx, req.SubmissionID)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && submission.UserID != req.UserID) {
+		// someone else's submission is reported as missing so IDs can't be probed
+		return nil, s.createGrpcError(ctx, codes.NotFound, "Submission not found", "NOT_FOUND", nil)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch submission", map[string]any{
+			"method":       "PostSolution",
+			"submissionId": req.SubmissionID,
+			"errorType":    "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch submission", "DB_ERROR", err)
+	}
+	if submission.Status != "SUCCESS" || submission.Invalidated {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Only accepted submissions can be posted", map[string]any{
+			"method":       "PostSolution",
+			"submissionId": req.SubmissionID,
+			"status":       submission.Status,
+			"errorType":    "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Only accepted submissions can be posted as solutions", "VALIDATION_ERROR", nil)
+	}
+
+	if err := s.throttle(ctx, traceID, "PostSolution", "solution_post:"+req.UserID, solutionPostRate, solutionPostBurst); err != nil {
+		return nil, err
+	}
+
+	solution, err := s.RepoConnInstance.CreateSolution(ctx, model.Solution{
+		ProblemID:    submission.ProblemID,
+		UserID:       req.UserID,
+		SubmissionID: req.SubmissionID,
+		Title:        title,
+		Body:         req.Body,
+		Language:     submission.Language,
+		Code:         submission.UserCode,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to post solution", map[string]any{
+			"method":       "PostSolution",
+			"submissionId": req.SubmissionID,
+			"errorType":    customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to post solution")
+	}
+	s.invalidateSolutionCaches(ctx, traceID, "PostSolution", solution.ProblemID)
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Solution posted", map[string]any{
+		"method":     "PostSolution",
+		"solutionId": solution.ID.Hex(),
+		"problemId":  solution.ProblemID,
+	}, "SERVICE", nil)
+	return &model.PostSolutionResponse{Solution: *solution}, nil
+}
+
+// ListSolutions returns a page of a problem's gallery, most voted first unless Sort asks for the newest
+func (s *ProblemService) ListSolutions(ctx context.Context, req *model.ListSolutionsRequest) (*model.ListSolutionsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	sort := req.Sort
+	switch sort {
+	case "":
+		sort = model.SolutionSortVotes
+	case model.SolutionSortVotes, model.SolutionSortRecent:
+	default:
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Sort must be votes or recent", "VALIDATION_ERROR", nil)
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSolutionPageSize
+	}
+	if limit > maxSolutionPageSize {
+		limit = maxSolutionPageSize
+	}
+
+	cacheKey := solutionListCacheKey(req.ProblemID, sort, page, limit)
+	resp, fromCache, err := cache.GetOrLoad(ctx, s.RedisCacheClient, cacheKey, s.cacheTTLs().List, func(ctx context.Context) (*model.ListSolutionsResponse, error) {
+		solutions, total, err := s.RepoConnInstance.ListSolutions(ctx, req.ProblemID, sort, (page-1)*limit, limit)
+		if err != nil {
+			return nil, err
+		}
+		return &model.ListSolutionsResponse{Solutions: solutions, Total: total}, nil
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list solutions", map[string]any{
+			"method":    "ListSolutions",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list solutions")
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Solutions listed", map[string]any{
+		"method":    "ListSolutions",
+		"problemId": req.ProblemID,
+		"count":     len(resp.Solutions),
+		"fromCache": fromCache,
+	}, "SERVICE", nil)
+	return resp, nil
+}
+
+// VoteSolution upvotes, downvotes or withdraws the user's vote on a solution. Users cannot vote on their own.
+func (s *ProblemService) VoteSolution(ctx context.Context, req *model.VoteSolutionRequest) (*model.VoteSolutionResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting VoteSolution", map[string]any{
+		"method":     "VoteSolution",
+		"solutionId": req.SolutionID,
+		"userId":     req.UserID,
+		"value":      req.Value,
+	}, "SERVICE", nil)
+
+	if req.SolutionID == "" || req.UserID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Solution ID and user ID are required", "VALIDATION_ERROR", nil)
+	}
+	if req.Value < -1 || req.Value > 1 {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Value must be 1, -1 or 0", "VALIDATION_ERROR", nil)
+	}
+
+	solution, err := s.RepoConnInstance.GetSolution(ctx, req.SolutionID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch solution", map[string]any{
+			"method":     "VoteSolution",
+			"solutionId": req.SolutionID,
+			"errorType":  customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch solution")
+	}
+	if solution.UserID == req.UserID {
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "You cannot vote on your own solution", "VALIDATION_ERROR", nil)
+	}
+
+	if err := s.throttle(ctx, traceID, "VoteSolution", "solution_vote:"+req.UserID, solutionVoteRate, solutionVoteBurst); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.RepoConnInstance.VoteSolution(ctx, req.SolutionID, req.UserID, req.Value)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to vote on solution", map[string]any{
+			"method":     "VoteSolution",
+			"solutionId": req.SolutionID,
+			"errorType":  customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to vote on solution")
+	}
+	s.invalidateSolutionCaches(ctx, traceID, "VoteSolution", updated.ProblemID)
+
+	return &model.VoteSolutionResponse{SolutionID: req.SolutionID, Votes: updated.Votes, Value: req.Value}, nil
+}
+
+// throttle takes a token from the bucket at key and returns a ResourceExhausted error when it is empty. Like the
+// rate limit interceptor it lets the request through when Redis is unavailable.
+func (s *ProblemService) throttle(ctx context.Context, traceID, method, key string, rate float64, burst int) error {
+	allowed, retryAfter, err := s.RedisCacheClient.TakeToken(ctx, key, rate, burst)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Throttle unavailable, allowing request", map[string]any{
+			"method":    method,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	s.logger.Log(zapcore.WarnLevel, traceID, "Request throttled", map[string]any{
+		"method":     method,
+		"key":        key,
+		"retryAfter": retryAfter.Milliseconds(),
+		"errorType":  "RATE_LIMITED",
+	}, "SERVICE", nil)
+	return s.createGrpcError(ctx, codes.ResourceExhausted, fmt.Sprintf("Too many requests, retry in %s", retryAfter.Round(time.Second)), "RATE_LIMITED", nil, customerrors.RetryAfter(retryAfter))
+}
+
+// invalidateSolutionCaches drops every cached page of a problem's gallery
+func (s *ProblemService) invalidateSolutionCaches(ctx context.Context, traceID, method, problemID string) {
+	pattern := fmt.Sprintf("solutions:%s:*", problemID)
+	if err := s.RedisCacheClient.DeletePattern(ctx, pattern); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    method,
+			"cacheKey":  pattern,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+}
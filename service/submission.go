@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/repository"
+	"xcode/utils"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const maxFirstSolversProblems = 100
+
+// GetFirstSolvers returns who solved each of the given problems first, globally or within a challenge
+func (s *ProblemService) GetFirstSolvers(ctx context.Context, req *model.GetFirstSolversRequest) (*model.GetFirstSolversResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	challengeID := ""
+	if req.ChallengeID != nil {
+		challengeID = *req.ChallengeID
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetFirstSolvers", map[string]any{
+		"method":       "GetFirstSolvers",
+		"problemCount": len(req.ProblemIDs),
+		"challengeId":  challengeID,
+	}, "SERVICE", nil)
+
+	if len(req.ProblemIDs) == 0 || len(req.ProblemIDs) > maxFirstSolversProblems {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid number of problem IDs", map[string]any{
+			"method":       "GetFirstSolvers",
+			"problemCount": len(req.ProblemIDs),
+			"errorType":    "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("Between 1 and %d problem IDs are required", maxFirstSolversProblems), "VALIDATION_ERROR", nil)
+	}
+
+	solvers, err := s.RepoConnInstance.GetFirstSolvers(ctx, req.ProblemIDs, challengeID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch first solvers", map[string]any{
+			"method":    "GetFirstSolvers",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch first solvers", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "First solvers retrieved successfully", map[string]any{
+		"method":      "GetFirstSolvers",
+		"solverCount": len(solvers),
+	}, "SERVICE", nil)
+	return &model.GetFirstSolversResponse{FirstSolvers: solvers}, nil
+}
+
+const maxSubmissionsPageSize = 100
+
+// ListSubmissions lists submissions newest first using an opaque cursor, so pages stay stable while new submissions arrive
+func (s *ProblemService) ListSubmissions(ctx context.Context, req *model.ListSubmissionsRequest) (*model.ListSubmissionsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	problemID := ""
+	if req.ProblemID != nil {
+		problemID = *req.ProblemID
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ListSubmissions", map[string]any{
+		"method":    "ListSubmissions",
+		"problemId": problemID,
+		"userId":    req.UserID,
+		"status":    req.Status,
+		"language":  req.Language,
+	}, "SERVICE", nil)
+
+	if problemID == "" && req.UserID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing problem ID and user ID", map[string]any{
+			"method":    "ListSubmissions",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID or user ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > maxSubmissionsPageSize {
+		req.Limit = maxSubmissionsPageSize
+	}
+
+	status := strings.ToUpper(req.Status)
+	if status != "" && status != "SUCCESS" && status != "FAILED" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid status filter", map[string]any{
+			"method":    "ListSubmissions",
+			"status":    req.Status,
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Status must be SUCCESS or FAILED", "VALIDATION_ERROR", nil)
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid date range", map[string]any{
+			"method":    "ListSubmissions",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "From must be before to", "VALIDATION_ERROR", nil)
+	}
+	language := ""
+	if req.Language != "" {
+		language = utils.NormalizeLanguage(req.Language)
+	}
+
+	filter := model.SubmissionFilter{
+		ProblemID: problemID,
+		UserID:    req.UserID,
+		Status:    status,
+		Language:  language,
+		From:      req.From,
+		To:        req.To,
+		IsFirst:   req.IsFirst,
+	}
+
+	submissions, nextCursor, err := s.RepoConnInstance.ListSubmissionsCursor(ctx, filter, req.Cursor, req.Limit)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Invalid submissions cursor", map[string]any{
+			"method":    "ListSubmissions",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Invalid cursor", "VALIDATION_ERROR", nil)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list submissions", map[string]any{
+			"method":    "ListSubmissions",
+			"problemId": problemID,
+			"userId":    req.UserID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to list submissions", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Submissions listed successfully", map[string]any{
+		"method":  "ListSubmissions",
+		"count":   len(submissions),
+		"hasMore": nextCursor != "",
+	}, "SERVICE", nil)
+	return &model.ListSubmissionsResponse{
+		Submissions: submissions,
+		NextCursor:  nextCursor,
+	}, nil
+}
+
+// GetBestSubmissions returns the fastest accepted submission per solved problem (optionally per language) for a user
+func (s *ProblemService) GetBestSubmissions(ctx context.Context, req *model.GetBestSubmissionsRequest) (*model.GetBestSubmissionsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetBestSubmissions", map[string]any{
+		"method":     "GetBestSubmissions",
+		"userId":     req.UserID,
+		"byLanguage": req.ByLanguage,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "User ID is required", map[string]any{
+			"method":    "GetBestSubmissions",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	submissions, err := s.RepoConnInstance.GetBestSubmissions(ctx, req.UserID, req.ByLanguage)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch best submissions", map[string]any{
+			"method":    "GetBestSubmissions",
+			"userId":    req.UserID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch best submissions", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Best submissions retrieved successfully", map[string]any{
+		"method": "GetBestSubmissions",
+		"userId": req.UserID,
+		"count":  len(submissions),
+	}, "SERVICE", nil)
+	return &model.GetBestSubmissionsResponse{Submissions: submissions}, nil
+}
+
+// defaultShareExpiry is how long a share link lasts when the request leaves it out; see
+// validation.MaxShareExpiryHours for the longest allowed
+const defaultShareExpiry = 7 * 24 * time.Hour
+
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ShareSubmission creates an expiring public token for one of the user's accepted submissions
+func (s *ProblemService) ShareSubmission(ctx context.Context, req *model.ShareSubmissionRequest) (*model.ShareSubmissionResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ShareSubmission", map[string]any{
+		"method":       "ShareSubmission",
+		"submissionId": req.SubmissionID,
+		"userId":       req.UserID,
+	}, "SERVICE", nil)
+
+	if err := s.checkRequest(traceID, "ShareSubmission", req); err != nil {
+		return nil, err
+	}
+	expiry := defaultShareExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	submission, err := s.RepoConnInstance.GetSubmissionByID(ctx, req.SubmissionID)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && submission.UserID != req.UserID) {
+		// someone else's submission is reported as missing so IDs can't be probed
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Submission not found", map[string]any{
+			"method":       "ShareSubmission",
+			"submissionId": req.SubmissionID,
+			"errorType":    "NOT_FOUND",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.NotFound, "Submission not found", "NOT_FOUND", nil)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch submission", map[string]any{
+			"method":       "ShareSubmission",
+			"submissionId": req.SubmissionID,
+			"errorType":    "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch submission", "DB_ERROR", err)
+	}
+	if submission.Status != "SUCCESS" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Only accepted submissions can be shared", map[string]any{
+			"method":       "ShareSubmission",
+			"submissionId": req.SubmissionID,
+			"status":       submission.Status,
+			"errorType":    "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Only accepted submissions can be shared", "VALIDATION_ERROR", nil)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to generate share token", map[string]any{
+			"method":    "ShareSubmission",
+			"errorType": "INTERNAL_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to generate share token", "INTERNAL_ERROR", err)
+	}
+	now := time.Now()
+	shared := model.SharedSubmission{
+		Token:        token,
+		SubmissionID: req.SubmissionID,
+		UserID:       req.UserID,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(expiry),
+	}
+	if err := s.RepoConnInstance.SaveSharedSubmission(ctx, shared); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save share link", map[string]any{
+			"method":    "ShareSubmission",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to save share link", "DB_ERROR", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Submission shared successfully", map[string]any{
+		"method":       "ShareSubmission",
+		"submissionId": req.SubmissionID,
+		"expiresAt":    shared.ExpiresAt,
+	}, "SERVICE", nil)
+	return &model.ShareSubmissionResponse{Token: token, ExpiresAt: shared.ExpiresAt}, nil
+}
+
+// GetSharedSubmission resolves a share token to the submission and the problem it solves
+func (s *ProblemService) GetSharedSubmission(ctx context.Context, req *model.GetSharedSubmissionRequest) (*model.GetSharedSubmissionResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetSharedSubmission", map[string]any{
+		"method": "GetSharedSubmission",
+	}, "SERVICE", nil)
+
+	if req.Token == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Share token is required", map[string]any{
+			"method":    "GetSharedSubmission",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Share token is required", "VALIDATION_ERROR", nil)
+	}
+
+	shared, err := s.RepoConnInstance.GetSharedSubmission(ctx, req.Token)
+	var submission *model.Submission
+	if err == nil {
+		submission, err = s.RepoConnInstance.GetSubmissionByID(ctx, shared.SubmissionID)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Share link not found or expired", map[string]any{
+			"method":    "GetSharedSubmission",
+			"errorType": "NOT_FOUND",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.NotFound, "Share link not found or expired", "NOT_FOUND", nil)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to resolve share link", map[string]any{
+			"method":    "GetSharedSubmission",
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to resolve share link", "DB_ERROR", err)
+	}
+
+	problem, err := s.RepoConnInstance.GetProblemByIDSlug(ctx, &pb.GetProblemByIdSlugRequest{ProblemId: submission.ProblemID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem for shared submission", map[string]any{
+			"method":    "GetSharedSubmission",
+			"problemId": submission.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+
+	pbSubmission := repository.ToPbSubmission(*submission)
+	pbSubmission.Id = ""
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Shared submission retrieved successfully", map[string]any{
+		"method":    "GetSharedSubmission",
+		"problemId": submission.ProblemID,
+	}, "SERVICE", nil)
+	return &model.GetSharedSubmissionResponse{
+		Submission: pbSubmission,
+		Problem:    problem.Problemmetdata,
+		ExpiresAt:  shared.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"xcode/model"
+	"xcode/natsclient"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	submissionEventsStream      = "SUBMISSION_EVENTS"
+	submissionEventsMaxAge      = 7 * 24 * time.Hour
+	submissionEventsDedupWindow = 10 * time.Minute
+
+	// leaderboardEventsConsumer moves accepted first solves onto the seasonal boards
+	leaderboardEventsConsumer = "problem-service-leaderboards"
+)
+
+// StartSubmissionEventConsumers makes sure the submission stream exists and starts the durable consumers.
+// It must run before the server starts. The returned context stops the consumers; it is nil when JetStream is not configured.
+func (s *ProblemService) StartSubmissionEventConsumers(ctx context.Context) (jetstream.ConsumeContext, error) {
+	if s.JetStream == nil {
+		return nil, nil
+	}
+	if err := s.JetStream.EnsureStream(ctx, submissionEventsStream, []string{"submission.>"}, submissionEventsMaxAge, submissionEventsDedupWindow); err != nil {
+		// the broker has no JetStream; publishing would fail on every submission, so process inline from now on
+		s.JetStream = nil
+		return nil, err
+	}
+	return s.JetStream.ConsumeDurable(ctx, submissionEventsStream, leaderboardEventsConsumer, model.SubmissionEventAccepted, s.handleSubmissionAccepted, s.deadLetterFromConsumer)
+}
+
+// submissionOutboxEvents returns the events announcing a stored submission, for PushSubmissionData to write to the
+// outbox with it. It is nil when JetStream is not configured; publishSubmissionEvents then refreshes the
+// leaderboards inline instead.
+func (s *ProblemService) submissionOutboxEvents(traceID string) func(submission model.Submission) ([]model.OutboxEvent, error) {
+	if s.JetStream == nil {
+		return nil
+	}
+	return func(submission model.Submission) ([]model.OutboxEvent, error) {
+		data, err := json.Marshal(submissionEvent(submission))
+		if err != nil {
+			return nil, err
+		}
+		subjects := []string{model.SubmissionEventCreated}
+		if submission.Status == "SUCCESS" {
+			subjects = append(subjects, model.SubmissionEventAccepted)
+		}
+		events := make([]model.OutboxEvent, 0, len(subjects))
+		for _, subject := range subjects {
+			events = append(events, model.OutboxEvent{
+				Subject: subject,
+				Data:    data,
+				// the subject is part of the ID so created and accepted events of one submission are not deduplicated together
+				MsgID:   subject + ":" + submission.ID.Hex(),
+				TraceID: traceID,
+			})
+		}
+		return events, nil
+	}
+}
+
+// publishSubmissionEvents runs once a submission is stored. Its events are already in the outbox, so the relay is
+// woken to publish them; without JetStream there are none and a first solve refreshes the leaderboards inline.
+// Accepted submissions are also sent to webhooks either way.
+func (s *ProblemService) publishSubmissionEvents(ctx context.Context, traceID string, submission model.Submission) {
+	if submission.Status == "SUCCESS" {
+		s.enqueueWebhookEvent(ctx, traceID, model.WebhookEventSubmissionAccepted, submissionEvent(submission))
+	}
+	if s.JetStream != nil {
+		s.wakeOutboxRelay()
+		return
+	}
+	if submission.Status == "SUCCESS" && submission.IsFirst {
+		s.refreshLeaderboardsInline(ctx, traceID, submission.UserID)
+	}
+}
+
+func submissionEvent(submission model.Submission) model.SubmissionEvent {
+	return model.SubmissionEvent{
+		SubmissionID:  submission.ID.Hex(),
+		UserID:        submission.UserID,
+		ProblemID:     submission.ProblemID,
+		Country:       submission.Country,
+		Language:      submission.Language,
+		Status:        submission.Status,
+		Score:         submission.Score,
+		IsFirst:       submission.IsFirst,
+		ExecutionTime: submission.ExecutionTime,
+		SubmittedAt:   submission.SubmittedAt,
+	}
+}
+
+func (s *ProblemService) refreshLeaderboardsInline(ctx context.Context, traceID, userID string) {
+	if err := s.refreshUserOnLeaderboards(ctx, userID); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update leaderboards inline", map[string]any{
+			"method":    "refreshLeaderboardsInline",
+			"userId":    userID,
+			"errorType": "LEADERBOARD_ERROR",
+		}, "SERVICE", err)
+	}
+}
+
+// handleSubmissionAccepted writes the user's absolute totals to the boards, so a redelivered event changes nothing
+func (s *ProblemService) handleSubmissionAccepted(ctx context.Context, data []byte) error {
+	var event model.SubmissionEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		// a malformed event will never succeed, it goes to the dead letters instead of being redelivered
+		return fmt.Errorf("%w: %v", natsclient.ErrPoisonEvent, err)
+	}
+	if !event.IsFirst {
+		return nil
+	}
+
+	traceID := uuid.New().String()
+	if err := s.refreshUserOnLeaderboards(ctx, event.UserID); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to update leaderboards from submission event", map[string]any{
+			"method":       "handleSubmissionAccepted",
+			"submissionId": event.SubmissionID,
+			"userId":       event.UserID,
+			"errorType":    "LEADERBOARD_ERROR",
+		}, "SERVICE", err)
+		return err
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "Leaderboards updated from submission event", map[string]any{
+		"method":       "handleSubmissionAccepted",
+		"submissionId": event.SubmissionID,
+		"userId":       event.UserID,
+	}, "SERVICE", nil)
+	return nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// acceptLanguageHeader is read from gRPC metadata since GetProblemByIdSlugRequest has no field for the locale; it
+// takes an HTTP Accept-Language value such as "pt-BR, pt;q=0.9, en;q=0.5"
+const acceptLanguageHeader = "accept-language"
+
+func problemTranslationsCacheKey(problemID string) string {
+	return "problem_translations:" + problemID
+}
+
+// UpsertProblemTranslation adds or replaces the statement of a problem in one locale
+func (s *ProblemService) UpsertProblemTranslation(ctx context.Context, req *model.UpsertProblemTranslationRequest) (*model.UpsertProblemTranslationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting UpsertProblemTranslation", map[string]any{
+		"method":    "UpsertProblemTranslation",
+		"problemId": req.ProblemID,
+		"locale":    req.Locale,
+		"adminId":   req.AdminID,
+	}, "SERVICE", nil)
+
+	if req.AdminID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing admin ID", map[string]any{
+			"method":    "UpsertProblemTranslation",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Admin ID is required", "VALIDATION_ERROR", nil)
+	}
+	if req.ProblemID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+	locale, err := model.NormalizeLocale(req.Locale)
+	if err != nil {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, err.Error(), "VALIDATION_ERROR", err)
+	}
+	if locale == model.DefaultProblemLocale {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, fmt.Sprintf("The problem itself is in %s, update it instead", model.DefaultProblemLocale), "VALIDATION_ERROR", nil)
+	}
+	translation := model.ProblemTranslation{
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Examples:    strings.TrimSpace(req.Examples),
+		UpdatedAt:   time.Now(),
+	}
+	if translation.Title == "" || translation.Description == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Title and description are required", "VALIDATION_ERROR", nil)
+	}
+
+	created, err := s.RepoConnInstance.UpsertProblemTranslation(ctx, req.ProblemID, locale, translation)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to store problem translation", map[string]any{
+			"method":    "UpsertProblemTranslation",
+			"problemId": req.ProblemID,
+			"locale":    locale,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to store problem translation")
+	}
+	cacheKey := problemTranslationsCacheKey(req.ProblemID)
+	if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    "UpsertProblemTranslation",
+			"cacheKey":  cacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionUpsertTranslation,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetProblem,
+		TargetID:   req.ProblemID,
+		After:      map[string]any{"locale": locale, "title": translation.Title, "created": created},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Problem translation stored", map[string]any{
+		"method":    "UpsertProblemTranslation",
+		"problemId": req.ProblemID,
+		"locale":    locale,
+		"created":   created,
+	}, "SERVICE", nil)
+	return &model.UpsertProblemTranslationResponse{ProblemID: req.ProblemID, Locale: locale, Translation: translation, Created: created}, nil
+}
+
+// localizeProblem returns resp with the statement in the caller's preferred locale, or resp itself when the caller
+// prefers the default language, has no preference or no translation matches. resp may be shared through the cache,
+// so a localized copy is returned rather than changing it. Failing to load translations serves the default language.
+func (s *ProblemService) localizeProblem(ctx context.Context, traceID string, resp *pb.GetProblemByIdSlugResponse) *pb.GetProblemByIdSlugResponse {
+	preferred := preferredLocales(acceptLanguageFromContext(ctx))
+	if len(preferred) == 0 || resp.GetProblemmetdata() == nil {
+		return resp
+	}
+	problemID := resp.Problemmetdata.ProblemId
+
+	translations, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, problemTranslationsCacheKey(problemID), s.cacheTTLs().Problem, func(ctx context.Context) (map[string]model.ProblemTranslation, error) {
+		return s.RepoConnInstance.GetProblemTranslations(ctx, problemID)
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to load problem translations, serving default language", map[string]any{
+			"method":    "GetProblemByIDSlug",
+			"problemId": problemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return resp
+	}
+	translation, ok := pickTranslation(translations, preferred)
+	if !ok {
+		return resp
+	}
+
+	localized := proto.Clone(resp).(*pb.GetProblemByIdSlugResponse)
+	localized.Problemmetdata.Title = translation.Title
+	localized.Problemmetdata.Description = translation.Description
+	if translation.Examples != "" {
+		localized.Problemmetdata.Description += "\n\n" + translation.Examples
+	}
+	return localized
+}
+
+func acceptLanguageFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return strings.Join(md.Get(acceptLanguageHeader), ",")
+}
+
+// preferredLocales parses an Accept-Language value into normalized locales, most preferred first. Wildcards,
+// malformed tags and q=0 are dropped; equal weights keep the order they were given in.
+func preferredLocales(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var locales []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, err := model.NormalizeLocale(tag)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			locales = append(locales, weighted{locale, q})
+		}
+	}
+	sort.SliceStable(locales, func(i, j int) bool { return locales[i].q > locales[j].q })
+
+	preferred := make([]string, len(locales))
+	for i, l := range locales {
+		preferred[i] = l.locale
+	}
+	return preferred
+}
+
+// pickTranslation returns the translation for the first preferred locale that has one, exactly or by its language
+// alone (es-mx takes es). It stops at the first locale in the default language, which the problem itself is in.
+func pickTranslation(translations map[string]model.ProblemTranslation, preferred []string) (model.ProblemTranslation, bool) {
+	for _, locale := range preferred {
+		language, _, _ := strings.Cut(locale, "-")
+		if language == model.DefaultProblemLocale {
+			return model.ProblemTranslation{}, false
+		}
+		if translation, ok := translations[locale]; ok {
+			return translation, true
+		}
+		if translation, ok := translations[language]; ok {
+			return translation, true
+		}
+	}
+	return model.ProblemTranslation{}, false
+}
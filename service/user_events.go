@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+	"xcode/utils"
+
+	redisboard "github.com/lijuuu/RedisBoard"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// userEventsQueue makes every instance share one subscription, so each event is handled once
+	userEventsQueue = "problem-service"
+
+	userEventAttempts     = 3
+	userEventRetryBackoff = 200 * time.Millisecond
+	userEventTimeout      = 30 * time.Second
+
+	// userEventProcessedTTL is how long a handled event ID is remembered to drop redeliveries
+	userEventProcessedTTL = 24 * time.Hour
+)
+
+// StartUserEventSubscriptions subscribes to the user service's events. Call Unsubscribe on the result to stop.
+func (s *ProblemService) StartUserEventSubscriptions() (*nats.Subscription, error) {
+	return s.NatsClient.QueueSubscribe(model.UserCountryChangedSubject, userEventsQueue, s.handleUserCountryChanged)
+}
+
+// handleUserCountryChanged moves a user's submissions and leaderboard entries to the new country. Events are
+// skipped when their ID was handled already; failures are retried a few times and then sent to the dead-letter subject.
+func (s *ProblemService) handleUserCountryChanged(msg *nats.Msg) {
+	traceID := traceIDFromMessage(msg)
+
+	var event model.UserCountryChangedEvent
+	err := json.Unmarshal(msg.Data, &event)
+	if err == nil && (event.UserID == "" || event.Country == "") {
+		err = errors.New("event is missing userId or country")
+	}
+	if _, ok := utils.NormalizeCountry(event.Country); err == nil && !ok {
+		err = fmt.Errorf("event country %q is not an ISO 3166-1 country", event.Country)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Dropping malformed user country event", map[string]any{
+			"method":    "handleUserCountryChanged",
+			"errorType": "UNMARSHAL_ERROR",
+		}, "SERVICE", err)
+		s.deadLetter(context.Background(), traceID, msg.Subject, msg.Data, err, 1, true)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), userEventTimeout)
+	defer cancel()
+
+	processedKey := ""
+	if event.EventID != "" {
+		processedKey = fmt.Sprintf("user_event:processed:%s", event.EventID)
+		if seen, err := s.RedisCacheClient.Exists(ctx, processedKey); err == nil && seen {
+			s.logger.Log(zapcore.InfoLevel, traceID, "Skipping already processed user country event", map[string]any{
+				"method":  "handleUserCountryChanged",
+				"eventId": event.EventID,
+				"userId":  event.UserID,
+			}, "SERVICE", nil)
+			return
+		}
+	}
+
+	for attempt := 1; attempt <= userEventAttempts; attempt++ {
+		if err = s.changeUserEntity(ctx, event.UserID, event.Country); err == nil {
+			break
+		}
+		s.logger.Log(zapcore.WarnLevel, traceID, "Failed to apply user country event", map[string]any{
+			"method":    "handleUserCountryChanged",
+			"eventId":   event.EventID,
+			"userId":    event.UserID,
+			"attempt":   attempt,
+			"errorType": "ENTITY_UPDATE_ERROR",
+		}, "SERVICE", err)
+		if attempt < userEventAttempts {
+			time.Sleep(userEventRetryBackoff * time.Duration(attempt))
+		}
+	}
+	if err != nil {
+		s.deadLetter(ctx, traceID, msg.Subject, msg.Data, err, userEventAttempts, true)
+		return
+	}
+
+	if processedKey != "" {
+		if err := s.RedisCacheClient.Set(ctx, processedKey, "1", userEventProcessedTTL); err != nil {
+			// the update is idempotent, so losing the marker only costs a repeated update on redelivery
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to mark user country event processed", map[string]any{
+				"method":    "handleUserCountryChanged",
+				"eventId":   event.EventID,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+	s.logger.Log(zapcore.InfoLevel, traceID, "User country event applied", map[string]any{
+		"method":  "handleUserCountryChanged",
+		"eventId": event.EventID,
+		"userId":  event.UserID,
+		"country": event.Country,
+	}, "SERVICE", nil)
+}
+
+// changeUserEntity moves a user to entity, a country normalized by utils.NormalizeCountry, in MongoDB and on every
+// leaderboard; running it twice changes nothing
+func (s *ProblemService) changeUserEntity(ctx context.Context, userID, entity string) error {
+	country, ok := utils.NormalizeCountry(entity)
+	if !ok {
+		return customerrors.Validation("entity %q is not an ISO 3166-1 country", entity)
+	}
+	entity = country
+	if _, err := s.RepoConnInstance.UpdateUserEntityMongo(ctx, userID, entity); err != nil {
+		return err
+	}
+
+	boards := []*redisboard.Leaderboard{s.LB}
+	for _, periodLB := range s.PeriodLBs {
+		boards = append(boards, periodLB)
+	}
+	for _, lb := range boards {
+		// users without solves are not on the board yet; the next sync places them under the new entity
+		rank, err := lb.GetRankGlobal(userID)
+		if err != nil {
+			return err
+		}
+		if rank < 0 {
+			continue
+		}
+		if err := lb.UpdateEntityByUserID(userID, entity); err != nil {
+			return fmt.Errorf("failed to update leaderboard entity for user %s: %w", userID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// GetUserLanguageStats returns solve counts and acceptance rates per language for a user's profile
+func (s *ProblemService) GetUserLanguageStats(ctx context.Context, req *model.GetUserLanguageStatsRequest) (*model.GetUserLanguageStatsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetUserLanguageStats", map[string]any{
+		"method": "GetUserLanguageStats",
+		"userId": req.UserID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "User ID is required", map[string]any{
+			"method":    "GetUserLanguageStats",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	cacheKey := fmt.Sprintf("language_stats:%s", req.UserID)
+	cached, err := s.RedisCacheClient.Get(ctx, cacheKey)
+	if err == nil && cached != nil {
+		var resp model.GetUserLanguageStatsResponse
+		if cachedStr, ok := cached.(string); ok && json.Unmarshal([]byte(cachedStr), &resp) == nil {
+			s.logger.Log(zapcore.InfoLevel, traceID, "Language stats retrieved from cache", map[string]any{
+				"method":   "GetUserLanguageStats",
+				"userId":   req.UserID,
+				"cacheKey": cacheKey,
+			}, "SERVICE", nil)
+			return &resp, nil
+		}
+	}
+
+	stats, err := s.RepoConnInstance.GetUserLanguageStats(ctx, req.UserID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch language stats", map[string]any{
+			"method":    "GetUserLanguageStats",
+			"userId":    req.UserID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to fetch language stats", "DB_ERROR", err)
+	}
+	resp := &model.GetUserLanguageStatsResponse{Languages: stats}
+
+	respBytes, err := json.Marshal(resp)
+	if err == nil {
+		err = s.RedisCacheClient.Set(ctx, cacheKey, respBytes, s.cacheTTLs().LanguageStats)
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache language stats", map[string]any{
+			"method":    "GetUserLanguageStats",
+			"cacheKey":  cacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Language stats retrieved successfully", map[string]any{
+		"method":        "GetUserLanguageStats",
+		"userId":        req.UserID,
+		"languageCount": len(stats),
+	}, "SERVICE", nil)
+	return resp, nil
+}
+
+// yearlyHeatmapRange returns the UTC [from, to) range of a calendar year, or of the trailing 365 days for year 0
+func yearlyHeatmapRange(year int, now time.Time) (time.Time, time.Time) {
+	if year == 0 {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return today.AddDate(0, 0, -364), today.AddDate(0, 0, 1)
+	}
+	return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// buildActivityHeatmap fills every day of [from, to) and computes the summary in one pass
+func buildActivityHeatmap(counts map[string]int, from, to, today time.Time) ([]model.ActivityDay, model.ActivitySummary) {
+	var days []model.ActivityDay
+	var summary model.ActivitySummary
+	streak := 0
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		count := counts[date]
+		days = append(days, model.ActivityDay{Date: date, Count: count, IsActive: count > 0})
+
+		summary.TotalSubmissions += count
+		if count == 0 {
+			// a quiet today (or a future day of the current year) doesn't break the streak yet
+			if day.Before(today) {
+				streak = 0
+			}
+			continue
+		}
+		summary.TotalActiveDays++
+		if count > summary.BusiestDay.Count {
+			summary.BusiestDay = days[len(days)-1]
+		}
+		streak++
+		if streak > summary.LongestStreak {
+			summary.LongestStreak = streak
+		}
+	}
+
+	// past years have no current streak
+	if !today.Before(from) && today.Before(to) {
+		summary.CurrentStreak = streak
+	}
+	return days, summary
+}
+
+// GetYearlyActivityHeatmap returns a year of daily submission counts with activity aggregates, cached until midnight
+func (s *ProblemService) GetYearlyActivityHeatmap(ctx context.Context, req *model.GetYearlyActivityHeatmapRequest) (*model.GetYearlyActivityHeatmapResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting GetYearlyActivityHeatmap", map[string]any{
+		"method": "GetYearlyActivityHeatmap",
+		"userId": req.UserID,
+		"year":   req.Year,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "User ID is required", map[string]any{
+			"method":    "GetYearlyActivityHeatmap",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	cacheKey := fmt.Sprintf("heatmap_yearly:%s:%d", req.UserID, req.Year)
+	cached, err := s.RedisCacheClient.Get(ctx, cacheKey)
+	if err == nil && cached != nil {
+		var resp model.GetYearlyActivityHeatmapResponse
+		if cachedStr, ok := cached.(string); ok && json.Unmarshal([]byte(cachedStr), &resp) == nil {
+			s.logger.Log(zapcore.InfoLevel, traceID, "Yearly heatmap retrieved from cache", map[string]any{
+				"method":   "GetYearlyActivityHeatmap",
+				"userId":   req.UserID,
+				"cacheKey": cacheKey,
+			}, "SERVICE", nil)
+			return &resp, nil
+		}
+	}
+
+	now := time.Now().UTC()
+	from, to := yearlyHeatmapRange(int(req.Year), now)
+	counts, err := s.RepoConnInstance.GetDailySubmissionCounts(ctx, req.UserID, from, to)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to retrieve yearly heatmap from DB", map[string]any{
+			"method":    "GetYearlyActivityHeatmap",
+			"userId":    req.UserID,
+			"year":      req.Year,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", err)
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to retrieve yearly heatmap", "DB_ERROR", err)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days, summary := buildActivityHeatmap(counts, from, to, today)
+	resp := &model.GetYearlyActivityHeatmapResponse{Data: days, Summary: summary}
+
+	respBytes, err := json.Marshal(resp)
+	if err == nil {
+		err = s.RedisCacheClient.Set(ctx, cacheKey, respBytes, time.Until(today.AddDate(0, 0, 1)))
+	}
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to cache yearly heatmap", map[string]any{
+			"method":    "GetYearlyActivityHeatmap",
+			"cacheKey":  cacheKey,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Yearly heatmap retrieved successfully", map[string]any{
+		"method":          "GetYearlyActivityHeatmap",
+		"userId":          req.UserID,
+		"totalActiveDays": summary.TotalActiveDays,
+	}, "SERVICE", nil)
+	return resp, nil
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validationHash identifies what a language is validated against: the problem's test cases and the language's
+// validation code, placeholder and template. Any change to them makes the language run again.
+func validationHash(problem model.Problem, language string) string {
+	content, _ := json.Marshal(struct {
+		TestCases model.TestCaseCollection
+		Code      model.CodeData
+	}{problem.TestCases, problem.ValidateCode[language]})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// saveValidationHash remembers that a language passed with hash. A failure only costs a rerun next time, so it is
+// logged and not returned.
+func (s *ProblemService) saveValidationHash(ctx context.Context, traceID, method, problemID, language, hash string) {
+	if err := s.RepoConnInstance.SetValidationHash(ctx, problemID, language, hash); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to save validation hash", map[string]any{
+			"method":    method,
+			"problemId": problemID,
+			"language":  language,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+	}
+}
+
+// ValidateProblem validates every supported language of a problem and reports each one. Languages that fail are
+// part of the report, not an error; errors are kept for problems that cannot be validated at all.
+func (s *ProblemService) ValidateProblem(ctx context.Context, req *model.ValidateProblemRequest) (*model.ValidateProblemResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ValidateProblem", map[string]any{
+		"method":    "ValidateProblem",
+		"problemId": req.ProblemID,
+	}, "SERVICE", nil)
+
+	if req.ProblemID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing problem ID", map[string]any{
+			"method":    "ValidateProblem",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	report, err := s.validateProblem(ctx, traceID, req.ProblemID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// validationProgress is told which languages a validation runs and each result as soon as it is known. finished
+// is called from several goroutines at once.
+type validationProgress struct {
+	started  func(languages []string)
+	finished func(result model.LanguageValidationResult)
+}
+
+// validateProblem checks the problem's test cases and validation code, then runs every supported language at once,
+// up to the configured parallelism, skipping languages unchanged since they last passed. The problem is marked
+// validated only when all of them pass. The returned report is never nil; the error is set when the problem failed
+// the basic checks and no language was run. progress, when not nil, hears about the languages as they run.
+func (s *ProblemService) validateProblem(ctx context.Context, traceID, problemID string, progress *validationProgress) (*model.ValidateProblemResponse, error) {
+	report := &model.ValidateProblemResponse{ProblemID: problemID, Languages: []model.LanguageValidationResult{}}
+
+	data, problem, err := s.RepoConnInstance.BasicValidationByProblemID(ctx, &pb.FullValidationByProblemIDRequest{ProblemId: problemID})
+	if err != nil || !data.Success {
+		report.Message = data.Message
+		if report.Message == "" {
+			report.Message = "Basic validation failed"
+		}
+		report.ErrorType = data.ErrorType
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Basic validation failed", map[string]any{
+			"method":    "validateProblem",
+			"problemId": problemID,
+			"errorType": data.ErrorType,
+		}, "SERVICE", err)
+		s.RepoConnInstance.ToggleProblemValidaition(ctx, problemID, false)
+		return report, s.createGrpcError(ctx, codes.Unimplemented, report.Message, data.ErrorType, err)
+	}
+
+	if progress != nil && progress.started != nil {
+		progress.started(problem.SupportedLanguages)
+	}
+	report.Languages = s.validateLanguages(ctx, traceID, problem, progress)
+
+	var failed []string
+	for _, result := range report.Languages {
+		if !result.Passed {
+			failed = append(failed, result.Language)
+		}
+	}
+	if len(failed) > 0 {
+		s.RepoConnInstance.ToggleProblemValidaition(ctx, problemID, false)
+		s.deleteProblemCache(ctx, traceID, "validateProblem", problemID)
+		report.Message = fmt.Sprintf("Validation failed for %s", strings.Join(failed, ", "))
+		report.ErrorType = "VALIDATION_FAILED"
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Validation failed", map[string]any{
+			"method":    "validateProblem",
+			"problemId": problemID,
+			"languages": failed,
+			"errorType": report.ErrorType,
+		}, "SERVICE", nil)
+		return report, nil
+	}
+
+	report.Validated = s.RepoConnInstance.ToggleProblemValidaition(ctx, problemID, true)
+	report.Message = "Full Validation Successful"
+	if !report.Validated {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to toggle validation status", map[string]any{
+			"method":    "validateProblem",
+			"problemId": problemID,
+			"errorType": "DB_ERROR",
+		}, "SERVICE", nil)
+		s.RepoConnInstance.ToggleProblemValidaition(ctx, problemID, false)
+		report.Message = "Full Validation completed, but failed to toggle status"
+	}
+
+	s.deleteProblemCache(ctx, traceID, "validateProblem", problemID)
+
+	if report.Validated {
+		validated := true
+		if err := s.enqueueProblemEvent(ctx, traceID, model.ProblemEventValidated, model.ProblemEvent{ProblemID: problemID, Validated: &validated}); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to enqueue problem event", map[string]any{
+				"method":    "validateProblem",
+				"eventType": model.ProblemEventValidated,
+				"problemId": problemID,
+				"errorType": "DB_ERROR",
+			}, "SERVICE", err)
+		} else {
+			s.wakeOutboxRelay()
+		}
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Full validation completed", map[string]any{
+		"method":    "validateProblem",
+		"problemId": problemID,
+		"status":    report.Validated,
+	}, "SERVICE", nil)
+	return report, nil
+}
+
+// validateLanguages runs validateLanguage for every supported language, at most ValidationParallelism at a time,
+// and returns the results in the order of the problem's languages
+func (s *ProblemService) validateLanguages(ctx context.Context, traceID string, problem model.Problem, progress *validationProgress) []model.LanguageValidationResult {
+	results := make([]model.LanguageValidationResult, len(problem.SupportedLanguages))
+	var g errgroup.Group
+	g.SetLimit(max(s.engine.ValidationParallelism, 1))
+	for i, lang := range problem.SupportedLanguages {
+		g.Go(func() error {
+			results[i] = s.validateLanguage(ctx, traceID, problem, lang)
+			if progress != nil && progress.finished != nil {
+				progress.finished(results[i])
+			}
+			return nil
+		})
+	}
+	g.Wait()
+	return results
+}
+
+// validateLanguage runs the language's validation code against all test cases and records its hash when it passes.
+// Every failure, including an unreachable execution engine, is reported in the result.
+func (s *ProblemService) validateLanguage(ctx context.Context, traceID string, problem model.Problem, lang string) model.LanguageValidationResult {
+	problemID := problem.ID.Hex()
+	result := model.LanguageValidationResult{Language: lang}
+	fail := func(errorType, message string, err error) model.LanguageValidationResult {
+		result.ErrorType, result.Message = errorType, message
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Validation failed for language", map[string]any{
+			"method":    "validateLanguage",
+			"problemId": problemID,
+			"language":  lang,
+			"errorType": errorType,
+		}, "SERVICE", err)
+		return result
+	}
+
+	validateCode, ok := problem.ValidateCode[lang]
+	if !ok || validateCode.Code == "" {
+		return fail("CONFIGURATION_ERROR", fmt.Sprintf("No validation code found for language: %s", lang), nil)
+	}
+
+	hash := validationHash(problem, lang)
+	if problem.ValidationHashes[lang] == hash {
+		result.Passed, result.Skipped = true, true
+		s.logger.Log(zapcore.InfoLevel, traceID, "Language unchanged since it last passed, skipping", map[string]any{
+			"method":    "validateLanguage",
+			"problemId": problemID,
+			"language":  lang,
+		}, "SERVICE", nil)
+		return result
+	}
+
+	start := time.Now()
+	res, err := s.runUserCodeProblem(ctx, &pb.RunProblemRequest{
+		ProblemId:     problemID,
+		UserCode:      validateCode.Code,
+		Language:      lang,
+		IsRunTestcase: false,
+	})
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		errorType := customerrors.ErrorType(err)
+		if errorType == "" {
+			errorType = "EXECUTION_ERROR"
+		}
+		return fail(errorType, status.Convert(err).Message(), err)
+	}
+	if !res.Success {
+		return fail(res.ErrorType, res.Message, nil)
+	}
+
+	var execution model.ExecutionResult
+	var reported struct {
+		OverallPass *bool `json:"overallPass"`
+	}
+	if err := json.Unmarshal([]byte(res.Message), &execution); err != nil {
+		return fail("EXECUTION_ERROR", "Invalid execution result", err)
+	}
+	if err := json.Unmarshal([]byte(res.Message), &reported); err != nil || reported.OverallPass == nil {
+		return fail("EXECUTION_ERROR", fmt.Sprintf("No output received for language %s", lang), err)
+	}
+	result.TotalTestCases = execution.TotalTestCases
+	result.PassedTestCases = execution.PassedTestCases
+
+	if !execution.OverallPass {
+		if execution.FailedTestCases > 0 {
+			result.FailedTestCase = &execution.FailedTestCase
+		}
+		return fail("VALIDATION_FAILED", fmt.Sprintf("Validation failed for language %s", lang), nil)
+	}
+
+	s.saveValidationHash(ctx, traceID, "validateLanguage", problemID, lang, hash)
+	result.Passed = true
+	s.logger.Log(zapcore.InfoLevel, traceID, "Language validated successfully", map[string]any{
+		"method":     "validateLanguage",
+		"problemId":  problemID,
+		"language":   lang,
+		"durationMs": result.DurationMs,
+	}, "SERVICE", nil)
+	return result
+}
+
+// ValidateSingleLanguage validates one language of a problem while it is being authored. It is skipped when the
+// language already passed with the same test cases and code. A failure marks the problem unvalidated; a pass does
+// not validate it, FullValidationByProblemID does that and only reruns the languages that changed.
+func (s *ProblemService) ValidateSingleLanguage(ctx context.Context, req *model.ValidateSingleLanguageRequest) (*model.ValidateSingleLanguageResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ValidateSingleLanguage", map[string]any{
+		"method":    "ValidateSingleLanguage",
+		"problemId": req.ProblemID,
+		"language":  req.Language,
+	}, "SERVICE", nil)
+
+	if req.ProblemID == "" || req.Language == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing problem ID or language", map[string]any{
+			"method":    "ValidateSingleLanguage",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID and language are required", "VALIDATION_ERROR", nil)
+	}
+
+	problem, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "ValidateSingleLanguage",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+
+	validateCode := problem.ValidateCode[req.Language]
+	if !slices.Contains(problem.SupportedLanguages, req.Language) || validateCode.Template == "" || validateCode.Placeholder == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Language is not supported or has incomplete validation code", map[string]any{
+			"method":    "ValidateSingleLanguage",
+			"problemId": req.ProblemID,
+			"language":  req.Language,
+			"errorType": "CONFIGURATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, fmt.Sprintf("Language %s is not supported or is missing its template or placeholder", req.Language), "CONFIGURATION_ERROR", nil)
+	}
+
+	result := s.validateLanguage(ctx, traceID, *problem, req.Language)
+	if !result.Passed && problem.Validated {
+		s.RepoConnInstance.ToggleProblemValidaition(ctx, req.ProblemID, false)
+		s.deleteProblemCache(ctx, traceID, "ValidateSingleLanguage", req.ProblemID)
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Single language validation completed", map[string]any{
+		"method":    "ValidateSingleLanguage",
+		"problemId": req.ProblemID,
+		"language":  req.Language,
+		"passed":    result.Passed,
+		"skipped":   result.Skipped,
+	}, "SERVICE", nil)
+	return &model.ValidateSingleLanguageResponse{ProblemID: req.ProblemID, Result: result}, nil
+}
+
+// deleteProblemCache drops the cached problem so readers see its new validation status
+func (s *ProblemService) deleteProblemCache(ctx context.Context, traceID, method, problemID string) {
+	for _, cacheKey := range []string{fmt.Sprintf("problem:%s", problemID), fmt.Sprintf("problem_slug_id:%s", problemID)} {
+		if err := s.RedisCacheClient.Delete(ctx, cacheKey); err != nil {
+			s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+				"method":    method,
+				"cacheKey":  cacheKey,
+				"errorType": "CACHE_ERROR",
+			}, "SERVICE", err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/metrics"
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// validationJobStaleAfter is how long a queued or running job may go without progress before it is considered
+// abandoned, e.g. by a replica that was killed, and a new validation of the problem may start
+const validationJobStaleAfter = 15 * time.Minute
+
+// validationJobsFinished counts background validations by their final status
+var validationJobsFinished = metrics.NewCounterVec("validation_jobs_finished_total", "status")
+
+// ValidateProblemAsync queues a full validation of the problem and returns at once with the job to poll through
+// GetValidationJob, for problems whose languages take longer to run than an RPC deadline allows. A problem has at
+// most one active job; asking again returns it.
+func (s *ProblemService) ValidateProblemAsync(ctx context.Context, req *model.ValidateProblemAsyncRequest) (*model.ValidateProblemAsyncResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting ValidateProblemAsync", map[string]any{
+		"method":    "ValidateProblemAsync",
+		"problemId": req.ProblemID,
+	}, "SERVICE", nil)
+
+	if req.ProblemID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing problem ID", map[string]any{
+			"method":    "ValidateProblemAsync",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Problem ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	if _, err := s.RepoConnInstance.GetProblem(ctx, &pb.GetProblemRequest{ProblemId: req.ProblemID}); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch problem", map[string]any{
+			"method":    "ValidateProblemAsync",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch problem")
+	}
+
+	job, created, err := s.RepoConnInstance.CreateValidationJob(ctx, model.ValidationJob{ProblemID: req.ProblemID, TraceID: traceID}, time.Now().Add(-validationJobStaleAfter))
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to queue validation job", map[string]any{
+			"method":    "ValidateProblemAsync",
+			"problemId": req.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to queue validation")
+	}
+
+	if created {
+		queued := *job
+		s.runInBackground(ctx, func(ctx context.Context) {
+			s.runValidationJob(ctx, traceID, queued)
+		})
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Validation job queued", map[string]any{
+		"method":    "ValidateProblemAsync",
+		"problemId": req.ProblemID,
+		"jobId":     job.ID.Hex(),
+		"created":   created,
+	}, "SERVICE", nil)
+	return &model.ValidateProblemAsyncResponse{Job: *job, Created: created}, nil
+}
+
+// GetValidationJob returns a validation job with the languages finished so far
+func (s *ProblemService) GetValidationJob(ctx context.Context, req *model.GetValidationJobRequest) (*model.GetValidationJobResponse, error) {
+	traceID := traceIDFromContext(ctx)
+
+	if req.JobID == "" {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Missing job ID", map[string]any{
+			"method":    "GetValidationJob",
+			"errorType": "VALIDATION_ERROR",
+		}, "SERVICE", nil)
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "Job ID is required", "VALIDATION_ERROR", nil)
+	}
+
+	job, err := s.RepoConnInstance.GetValidationJob(ctx, req.JobID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch validation job", map[string]any{
+			"method":    "GetValidationJob",
+			"jobId":     req.JobID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch validation job")
+	}
+	return &model.GetValidationJobResponse{Job: *job}, nil
+}
+
+// runValidationJob runs the job's validation, recording each language on the job as it finishes, then stores the
+// report. Failing to record progress is logged and does not stop the validation.
+func (s *ProblemService) runValidationJob(ctx context.Context, traceID string, job model.ValidationJob) {
+	logFailure := func(msg string, err error) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, msg, map[string]any{
+			"method":    "runValidationJob",
+			"jobId":     job.ID.Hex(),
+			"problemId": job.ProblemID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+	}
+
+	report, err := s.validateProblem(ctx, traceID, job.ProblemID, &validationProgress{
+		started: func(languages []string) {
+			if err := s.RepoConnInstance.StartValidationJob(ctx, job.ID, languages); err != nil {
+				logFailure("Failed to start validation job", err)
+			}
+		},
+		finished: func(result model.LanguageValidationResult) {
+			if err := s.RepoConnInstance.RecordValidationJobResult(ctx, job.ID, result); err != nil {
+				logFailure("Failed to record validation job progress", err)
+			}
+		},
+	})
+	if err != nil {
+		// the report still says why, the problem could not be validated at all
+		logFailure("Validation job failed basic validation", err)
+	}
+
+	if err := s.RepoConnInstance.FinishValidationJob(ctx, job.ID, *report); err != nil {
+		logFailure("Failed to finish validation job", err)
+	}
+
+	status := model.ValidationJobFailed
+	if report.Validated {
+		status = model.ValidationJobSucceeded
+	}
+	validationJobsFinished.Add(status, 1)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Validation job finished", map[string]any{
+		"method":    "runValidationJob",
+		"jobId":     job.ID.Hex(),
+		"problemId": job.ProblemID,
+		"status":    status,
+	}, "SERVICE", nil)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"xcode/customerrors"
+	"xcode/model"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+// StartVirtualParticipation starts a replay of a contest that ended for one user, who then has the contest's
+// duration to solve its problems. Users who registered for the contest itself cannot replay it. Starting again
+// returns the participation already started.
+func (s *ProblemService) StartVirtualParticipation(ctx context.Context, req *model.StartVirtualParticipationRequest) (*model.StartVirtualParticipationResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting StartVirtualParticipation", map[string]any{
+		"method":    "StartVirtualParticipation",
+		"contestId": req.ContestID,
+		"userId":    req.UserID,
+	}, "SERVICE", nil)
+
+	if req.UserID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+	contest, err := s.fetchContest(ctx, traceID, "StartVirtualParticipation", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	switch contest.Status(time.Now()) {
+	case model.ContestStatusScheduled, model.ContestStatusRunning:
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Contest has not ended", "CONTEST_NOT_ENDED", nil)
+	}
+
+	_, err = s.RepoConnInstance.GetContestRegistration(ctx, req.ContestID, req.UserID)
+	if err == nil {
+		return nil, s.createGrpcError(ctx, codes.FailedPrecondition, "Registered users cannot take part in the contest again", "ALREADY_PARTICIPATED", nil)
+	}
+	if !errors.Is(err, customerrors.ErrNotFound) {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch contest registration", map[string]any{
+			"method":    "StartVirtualParticipation",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch contest registration")
+	}
+
+	ratings, err := s.RepoConnInstance.GetUserRatings(ctx, []string{req.UserID})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch user rating", map[string]any{
+			"method":    "StartVirtualParticipation",
+			"userId":    req.UserID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch user rating")
+	}
+	rating := model.DefaultContestRating
+	if userRating, ok := ratings[req.UserID]; ok {
+		rating = userRating.Rating
+	}
+	// users no division takes are compared with the first one rather than turned away; nothing is at stake
+	division, ok := contestDivisionFor(contest.Divisions, rating)
+	if !ok && len(contest.Divisions) > 0 {
+		division = contest.Divisions[0].Name
+	}
+
+	now := time.Now()
+	participation, created, err := s.RepoConnInstance.StartVirtualParticipation(ctx, model.VirtualParticipation{
+		ContestID: req.ContestID,
+		UserID:    req.UserID,
+		Division:  division,
+		StartedAt: now,
+		EndsAt:    now.Add(contest.EndsAt.Sub(contest.StartsAt)),
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to start virtual participation", map[string]any{
+			"method":    "StartVirtualParticipation",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to start virtual participation")
+	}
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Virtual participation started", map[string]any{
+		"method":    "StartVirtualParticipation",
+		"contestId": req.ContestID,
+		"division":  participation.Division,
+		"endsAt":    participation.EndsAt,
+		"created":   created,
+	}, "SERVICE", nil)
+	return &model.StartVirtualParticipationResponse{Participation: *participation, Created: created}, nil
+}
+
+// GetVirtualStandings returns the private scoreboard of a virtual participant. It is computed on every call and
+// stored nowhere, so the contest's official standings stay as they were.
+func (s *ProblemService) GetVirtualStandings(ctx context.Context, req *model.GetVirtualStandingsRequest) (*model.GetVirtualStandingsResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if req.UserID == "" {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "User ID is required", "VALIDATION_ERROR", nil)
+	}
+	contest, err := s.fetchContest(ctx, traceID, "GetVirtualStandings", req.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	participation, err := s.RepoConnInstance.GetVirtualParticipation(ctx, req.ContestID, req.UserID)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch virtual participation", map[string]any{
+			"method":    "GetVirtualStandings",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to fetch virtual participation")
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultContestStandingsPageSize
+	}
+	if limit > maxContestStandingsPageSize {
+		limit = maxContestStandingsPageSize
+	}
+	skip := (page - 1) * limit
+
+	duration := contest.EndsAt.Sub(contest.StartsAt)
+	elapsed := min(time.Since(participation.StartedAt), duration)
+	standings, err := s.virtualStandings(ctx, *contest, *participation, elapsed)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to compute virtual standings", map[string]any{
+			"method":    "GetVirtualStandings",
+			"contestId": req.ContestID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to compute virtual standings")
+	}
+	return virtualStandingsPage(*participation, standings, elapsed, elapsed == duration, skip, limit), nil
+}
+
+// virtualStandings loads the original contest's first elapsed and the participant's own submissions since they
+// started, and ranks them together
+func (s *ProblemService) virtualStandings(ctx context.Context, contest model.Contest, participation model.VirtualParticipation, elapsed time.Duration) ([]model.ContestStanding, error) {
+	registrations, err := s.RepoConnInstance.GetContestRegistrations(ctx, participation.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.RepoConnInstance.GetContestSubmissions(ctx, contest.ProblemIDs, contest.StartsAt, contest.StartsAt.Add(elapsed))
+	if err != nil {
+		return nil, err
+	}
+	own, err := s.RepoConnInstance.GetUserContestSubmissions(ctx, participation.UserID, contest.ProblemIDs, participation.StartedAt, participation.StartedAt.Add(elapsed))
+	if err != nil {
+		return nil, err
+	}
+	return virtualContestStandings(contest, participation, registrations, history, own, elapsed), nil
+}
+
+func virtualStandingsPage(participation model.VirtualParticipation, standings []model.ContestStanding, elapsed time.Duration, finished bool, skip, limit int64) *model.GetVirtualStandingsResponse {
+	response := &model.GetVirtualStandingsResponse{
+		Participation:  participation,
+		ElapsedMinutes: int(elapsed / time.Minute),
+		Finished:       finished,
+		Standings:      []model.ContestStanding{},
+		Total:          int64(len(standings)),
+	}
+	for _, standing := range standings {
+		if standing.UserID == participation.UserID {
+			response.Participant = standing
+		}
+	}
+	if skip < response.Total {
+		response.Standings = standings[skip:min(skip+limit, response.Total)]
+	}
+	return response
+}
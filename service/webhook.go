@@ -0,0 +1,465 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"xcode/cache"
+	"xcode/customerrors"
+	"xcode/metrics"
+	"xcode/model"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	webhookSecretPrefix = "whsec_"
+	webhooksCachePrefix = "webhooks:"
+
+	// Headers of every delivery. The signature is the hex HMAC-SHA256, keyed with the webhook's secret, of the
+	// timestamp, a dot and the body, so a receiver can reject replays of old payloads.
+	webhookEventHeader     = "X-Xcode-Event"
+	webhookDeliveryHeader  = "X-Xcode-Delivery"
+	webhookTimestampHeader = "X-Xcode-Timestamp"
+	webhookSignatureHeader = "X-Xcode-Signature"
+
+	webhookDispatchBatchSize   = 50
+	webhookDispatchConcurrency = 8
+	webhookRequestTimeout      = 10 * time.Second
+	// a delivery is retried with exponential backoff from webhookRetryBase for about half a day, then given up on
+	webhookMaxAttempts = 12
+	webhookRetryBase   = 30 * time.Second
+	webhookRetryMax    = 6 * time.Hour
+	// how much of a failed response's body is kept in the delivery log
+	webhookErrorBodyLimit = 512
+
+	defaultWebhookDeliveryPageSize = 20
+	maxWebhookDeliveryPageSize     = 100
+)
+
+// webhookDeliveries counts delivery attempts by outcome: delivered, retrying or failed
+var webhookDeliveries = metrics.NewCounterVec("webhook_deliveries_total", "outcome")
+
+var webhookHTTPClient = &http.Client{Timeout: webhookRequestTimeout}
+
+// RegisterWebhook subscribes an http or https endpoint to events. The secret its payloads are signed with is
+// returned once.
+func (s *ProblemService) RegisterWebhook(ctx context.Context, req *model.RegisterWebhookRequest) (*model.RegisterWebhookResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting RegisterWebhook", map[string]any{
+		"method":     "RegisterWebhook",
+		"eventTypes": req.EventTypes,
+		"adminId":    req.AdminID,
+	}, "SERVICE", nil)
+
+	if err := s.checkRequest(traceID, "RegisterWebhook", req); err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil {
+		return nil, s.createGrpcError(ctx, codes.InvalidArgument, "URL must be an absolute http or https URL", "VALIDATION_ERROR", err)
+	}
+	var eventTypes []string
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	description := strings.TrimSpace(req.Description)
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, s.createGrpcError(ctx, codes.Internal, "Failed to generate webhook secret", "INTERNAL_ERROR", err)
+	}
+	secret := webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	created, err := s.RepoConnInstance.CreateWebhook(ctx, model.Webhook{
+		URL:         endpoint.String(),
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Description: description,
+		CreatedBy:   req.AdminID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to register webhook", map[string]any{
+			"method":    "RegisterWebhook",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to register webhook")
+	}
+	s.invalidateWebhookCaches(ctx, traceID, "RegisterWebhook")
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionRegisterWebhook,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetWebhook,
+		TargetID:   created.ID.Hex(),
+		After:      map[string]any{"url": created.URL, "eventTypes": created.EventTypes},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Webhook registered", map[string]any{
+		"method":    "RegisterWebhook",
+		"webhookId": created.ID.Hex(),
+		"host":      endpoint.Host,
+	}, "SERVICE", nil)
+	return &model.RegisterWebhookResponse{Webhook: *created, Secret: secret}, nil
+}
+
+// ListWebhooks returns the registered webhooks, newest first
+func (s *ProblemService) ListWebhooks(ctx context.Context, req *model.ListWebhooksRequest) (*model.ListWebhooksResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if err := s.checkRequest(traceID, "ListWebhooks", req); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.RepoConnInstance.ListWebhooks(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list webhooks", map[string]any{
+			"method":    "ListWebhooks",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list webhooks")
+	}
+	return &model.ListWebhooksResponse{Webhooks: webhooks}, nil
+}
+
+// DeleteWebhook stops sending events to a webhook and gives up on its pending deliveries
+func (s *ProblemService) DeleteWebhook(ctx context.Context, req *model.DeleteWebhookRequest) (*model.DeleteWebhookResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	s.logger.Log(zapcore.InfoLevel, traceID, "Starting DeleteWebhook", map[string]any{
+		"method":    "DeleteWebhook",
+		"webhookId": req.WebhookID,
+		"adminId":   req.AdminID,
+	}, "SERVICE", nil)
+
+	if err := s.checkRequest(traceID, "DeleteWebhook", req); err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.RepoConnInstance.DeleteWebhook(ctx, req.WebhookID, time.Now())
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete webhook", map[string]any{
+			"method":    "DeleteWebhook",
+			"webhookId": req.WebhookID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to delete webhook")
+	}
+	s.invalidateWebhookCaches(ctx, traceID, "DeleteWebhook")
+
+	s.recordAudit(ctx, traceID, model.AuditEntry{
+		Action:     model.AuditActionDeleteWebhook,
+		Actor:      req.AdminID,
+		TargetType: model.AuditTargetWebhook,
+		TargetID:   req.WebhookID,
+		Before:     map[string]any{"url": deleted.URL, "eventTypes": deleted.EventTypes},
+	})
+
+	s.logger.Log(zapcore.InfoLevel, traceID, "Webhook deleted", map[string]any{
+		"method":    "DeleteWebhook",
+		"webhookId": req.WebhookID,
+	}, "SERVICE", nil)
+	return &model.DeleteWebhookResponse{WebhookID: req.WebhookID}, nil
+}
+
+// ListWebhookDeliveries pages through the delivery log of a webhook, newest first
+func (s *ProblemService) ListWebhookDeliveries(ctx context.Context, req *model.ListWebhookDeliveriesRequest) (*model.ListWebhookDeliveriesResponse, error) {
+	traceID := traceIDFromContext(ctx)
+	if err := s.checkRequest(traceID, "ListWebhookDeliveries", req); err != nil {
+		return nil, err
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultWebhookDeliveryPageSize
+	}
+	if pageSize > maxWebhookDeliveryPageSize {
+		pageSize = maxWebhookDeliveryPageSize
+	}
+
+	deliveries, total, err := s.RepoConnInstance.ListWebhookDeliveries(ctx, req.WebhookID, req.Status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to list webhook deliveries", map[string]any{
+			"method":    "ListWebhookDeliveries",
+			"webhookId": req.WebhookID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return nil, s.repoError(ctx, err, "Failed to list webhook deliveries")
+	}
+	return &model.ListWebhookDeliveriesResponse{Deliveries: deliveries, Total: total}, nil
+}
+
+// invalidateWebhookCaches drops the cached subscribers of every event
+func (s *ProblemService) invalidateWebhookCaches(ctx context.Context, traceID, method string) {
+	pattern := webhooksCachePrefix + "*"
+	if err := s.RedisCacheClient.DeletePattern(ctx, pattern); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to delete cache", map[string]any{
+			"method":    method,
+			"cacheKey":  pattern,
+			"errorType": "CACHE_ERROR",
+		}, "SERVICE", err)
+	}
+}
+
+// enqueueWebhookEvent queues a delivery of the event to every webhook subscribed to eventType and wakes the
+// dispatcher. Webhooks are a side channel: a failure is logged and never fails the change that caused the event.
+func (s *ProblemService) enqueueWebhookEvent(ctx context.Context, traceID, eventType string, data any) {
+	webhooks, _, err := cache.GetOrLoad(ctx, s.RedisCacheClient, webhooksCachePrefix+eventType, s.cacheTTLs().List, func(ctx context.Context) ([]model.Webhook, error) {
+		return s.RepoConnInstance.GetWebhooksForEvent(ctx, eventType)
+	})
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to load webhooks", map[string]any{
+			"method":    "enqueueWebhookEvent",
+			"eventType": eventType,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to encode webhook event", map[string]any{
+			"method":    "enqueueWebhookEvent",
+			"eventType": eventType,
+			"errorType": "ENCODING_ERROR",
+		}, "SERVICE", err)
+		return
+	}
+	now := time.Now()
+	envelope := model.EventEnvelope{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Version:    model.EventEnvelopeVersion,
+		Source:     eventSource,
+		OccurredAt: now,
+		TraceID:    traceID,
+		Data:       raw,
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to encode webhook event", map[string]any{
+			"method":    "enqueueWebhookEvent",
+			"eventType": eventType,
+			"errorType": "ENCODING_ERROR",
+		}, "SERVICE", err)
+		return
+	}
+
+	deliveries := make([]model.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = model.WebhookDelivery{
+			WebhookID:     webhook.ID.Hex(),
+			EventID:       envelope.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+	if err := s.RepoConnInstance.SaveWebhookDeliveries(ctx, deliveries...); err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to queue webhook deliveries", map[string]any{
+			"method":    "enqueueWebhookEvent",
+			"eventType": eventType,
+			"eventId":   envelope.ID,
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return
+	}
+	s.wakeWebhookDispatcher()
+}
+
+// StartWebhookDispatcher sends due webhook deliveries every interval, and right away when events were just queued.
+// The returned stop ends the dispatcher after the batch in progress.
+func (s *ProblemService) StartWebhookDispatcher(interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			// requests in flight are not cut off by stop, so their outcome is recorded
+			s.runSingleton(context.WithoutCancel(ctx), "webhook_dispatcher", webhookDispatchLockTTL, false, s.dispatchWebhooks)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.webhookWake:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// wakeWebhookDispatcher asks the dispatcher to run now instead of at its next tick; it never blocks
+func (s *ProblemService) wakeWebhookDispatcher() {
+	select {
+	case s.webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchWebhooks sends one batch of due deliveries, a few at a time so one slow endpoint does not hold up the
+// rest. Whatever is left is due at the next run.
+func (s *ProblemService) dispatchWebhooks(ctx context.Context) {
+	traceID := traceIDFromContext(ctx)
+	deliveries, err := s.RepoConnInstance.GetDueWebhookDeliveries(ctx, time.Now(), webhookDispatchBatchSize)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch due webhook deliveries", map[string]any{
+			"method":    "dispatchWebhooks",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	webhooks, err := s.RepoConnInstance.ListWebhooks(ctx)
+	if err != nil {
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to fetch webhooks", map[string]any{
+			"method":    "dispatchWebhooks",
+			"errorType": customerrors.Type(err),
+		}, "SERVICE", err)
+		return
+	}
+	byID := make(map[string]model.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		byID[webhook.ID.Hex()] = webhook
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, webhookDispatchConcurrency)
+	for _, delivery := range deliveries {
+		webhook, ok := byID[delivery.WebhookID]
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.attemptWebhookDelivery(ctx, traceID, webhook, ok, delivery)
+		}()
+	}
+	wg.Wait()
+}
+
+// attemptWebhookDelivery sends delivery once and records the outcome. Any 2xx response delivers it; anything else
+// schedules a retry until webhookMaxAttempts, after which the delivery is failed.
+func (s *ProblemService) attemptWebhookDelivery(ctx context.Context, traceID string, webhook model.Webhook, found bool, delivery model.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	if !found {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.LastError = "webhook deleted"
+	} else {
+		statusCode, err := sendWebhook(ctx, webhook, delivery, now)
+		delivery.LastStatusCode = statusCode
+		switch {
+		case err == nil:
+			delivery.Status = model.WebhookDeliveryDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+		case delivery.Attempts >= webhookMaxAttempts:
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.LastError = err.Error()
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = now.Add(webhookRetryDelay(delivery.Attempts))
+		}
+	}
+
+	switch delivery.Status {
+	case model.WebhookDeliveryDelivered:
+		webhookDeliveries.Add("delivered", 1)
+	case model.WebhookDeliveryFailed:
+		webhookDeliveries.Add("failed", 1)
+		s.logger.Log(zapcore.WarnLevel, traceID, "Giving up on webhook delivery", map[string]any{
+			"method":     "dispatchWebhooks",
+			"webhookId":  delivery.WebhookID,
+			"deliveryId": delivery.ID.Hex(),
+			"eventType":  delivery.EventType,
+			"attempts":   delivery.Attempts,
+			"lastError":  delivery.LastError,
+			"errorType":  "WEBHOOK_FAILED",
+		}, "SERVICE", nil)
+	default:
+		webhookDeliveries.Add("retrying", 1)
+	}
+
+	if err := s.RepoConnInstance.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		// the delivery stays due and is sent again, which receivers must tolerate anyway
+		s.logger.Log(zapcore.ErrorLevel, traceID, "Failed to record webhook delivery", map[string]any{
+			"method":     "dispatchWebhooks",
+			"deliveryId": delivery.ID.Hex(),
+			"errorType":  customerrors.Type(err),
+		}, "SERVICE", err)
+	}
+}
+
+// sendWebhook POSTs the delivery's payload, signed, and returns the response status. A response outside 2xx is an
+// error carrying the start of its body.
+func sendWebhook(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery, now time.Time) (int, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, delivery.EventType)
+	req.Header.Set(webhookDeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return resp.StatusCode, nil
+}
+
+func signWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay doubles from webhookRetryBase with every failed attempt, up to webhookRetryMax
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookRetryMax)
+}
@@ -0,0 +1,55 @@
+package userclient
+
+import (
+	"context"
+	"log"
+
+	authUserAdminService "github.com/lijuuu/GlobalProtoXcode/AuthUserAdminService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type UserClient struct {
+	conn   *grpc.ClientConn
+	Client authUserAdminService.AuthUserAdminServiceClient
+}
+
+func NewUserClient(addr string) (*UserClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("user service client created, %s ", addr)
+	return &UserClient{
+		conn:   conn,
+		Client: authUserAdminService.NewAuthUserAdminServiceClient(conn),
+	}, nil
+}
+
+func (u *UserClient) Close() {
+	if u.conn != nil {
+		u.conn.Close()
+	}
+}
+
+// GetBulkUserMetadata fetches profile metadata for the given users in one call, keyed by user ID
+func (u *UserClient) GetBulkUserMetadata(ctx context.Context, userIDs []string) (map[string]*authUserAdminService.UserProfileMetadata, error) {
+	resp, err := u.Client.GetBulkUserMetadata(ctx, &authUserAdminService.GetBulkUserMetadataRequest{UserIDs: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[string]*authUserAdminService.UserProfileMetadata, len(resp.UserProfileMetadata))
+	for _, profile := range resp.UserProfileMetadata {
+		profiles[profile.UserID] = profile
+	}
+	return profiles, nil
+}
+
+// GetUserRole returns the role the user service has on record for a user
+func (u *UserClient) GetUserRole(ctx context.Context, userID string) (string, error) {
+	resp, err := u.Client.GetUserProfile(ctx, &authUserAdminService.GetUserProfileRequest{UserID: userID})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetUserProfile().GetRole(), nil
+}
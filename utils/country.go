@@ -0,0 +1,68 @@
+package utils
+
+import "strings"
+
+// isoCountries holds the officially assigned ISO 3166-1 alpha-2 codes, the canonical form of a country or entity
+var isoCountries = map[string]bool{}
+
+func init() {
+	for _, code := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY
+		BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK
+		FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR
+		IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK
+		ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM
+		PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF
+		TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`) {
+		isoCountries[code] = true
+	}
+}
+
+// countryAliases maps the alpha-3 codes and names clients are known to send to their alpha-2 code
+var countryAliases = map[string]string{
+	"ARG": "AR", "ARGENTINA": "AR",
+	"AUS": "AU", "AUSTRALIA": "AU",
+	"BGD": "BD", "BANGLADESH": "BD",
+	"BRA": "BR", "BRAZIL": "BR",
+	"CAN": "CA", "CANADA": "CA",
+	"CHN": "CN", "CHINA": "CN",
+	"DEU": "DE", "GERMANY": "DE",
+	"EGY": "EG", "EGYPT": "EG",
+	"ESP": "ES", "SPAIN": "ES",
+	"FRA": "FR", "FRANCE": "FR",
+	"GBR": "GB", "UK": "GB", "UNITED KINGDOM": "GB", "GREAT BRITAIN": "GB", "ENGLAND": "GB",
+	"IDN": "ID", "INDONESIA": "ID",
+	"IND": "IN", "INDIA": "IN", "BHARAT": "IN",
+	"ITA": "IT", "ITALY": "IT",
+	"JPN": "JP", "JAPAN": "JP",
+	"KEN": "KE", "KENYA": "KE",
+	"KOR": "KR", "SOUTH KOREA": "KR", "KOREA": "KR",
+	"LKA": "LK", "SRI LANKA": "LK",
+	"MEX": "MX", "MEXICO": "MX",
+	"NGA": "NG", "NIGERIA": "NG",
+	"NLD": "NL", "NETHERLANDS": "NL",
+	"NPL": "NP", "NEPAL": "NP",
+	"PAK": "PK", "PAKISTAN": "PK",
+	"PHL": "PH", "PHILIPPINES": "PH",
+	"POL": "PL", "POLAND": "PL",
+	"RUS": "RU", "RUSSIA": "RU",
+	"SAU": "SA", "SAUDI ARABIA": "SA",
+	"SGP": "SG", "SINGAPORE": "SG",
+	"TUR": "TR", "TURKEY": "TR", "TURKIYE": "TR",
+	"UAE": "AE", "ARE": "AE", "UNITED ARAB EMIRATES": "AE",
+	"UKR": "UA", "UKRAINE": "UA",
+	"USA": "US", "UNITED STATES": "US", "UNITED STATES OF AMERICA": "US", "AMERICA": "US",
+	"VNM": "VN", "VIETNAM": "VN", "VIET NAM": "VN",
+	"ZAF": "ZA", "SOUTH AFRICA": "ZA",
+}
+
+// NormalizeCountry returns the ISO 3166-1 alpha-2 code of country, given as a code in any case or as one of the
+// known alpha-3 codes and names; ok is false when it names no country
+func NormalizeCountry(country string) (code string, ok bool) {
+	country = strings.Join(strings.Fields(strings.ToUpper(country)), " ")
+	if isoCountries[country] {
+		return country, true
+	}
+	code, ok = countryAliases[country]
+	return code, ok
+}
@@ -1,52 +1,69 @@
 package utils
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
+
+// languageAliases maps the spellings users and engines send, misspellings included, to language codes
+var languageAliases = map[string]string{
+
+	"js":          "js",
+	"jscript":     "js",
+	"javscript":   "js",
+	"javsscript":  "js",
+	"javascipt":   "js",
+	"javasript":   "js",
+	"javascript":  "js",
+	"java script": "js",
+	"jscipt":      "js",
+
+	"python":  "python",
+	"pyt":     "python",
+	"pyn":     "python",
+	"pythn":   "python",
+	"phyton":  "python",
+	"py":      "python",
+	"py thon": "python",
+	"pthon":   "python",
+
+	"go":      "go",
+	"golang":  "go",
+	"gol":     "go",
+	"goo":     "go",
+	"g o":     "go",
+	"golangg": "go",
+
+	"cpp":    "cpp",
+	"c++":    "cpp",
+	"cp":     "cpp",
+	"cppp":   "cpp",
+	"c plus": "cpp",
+	"cxx":    "cpp",
+	"cc":     "cpp",
+	"cpp ":   "cpp",
+}
 
 func NormalizeLanguage(lang string) string {
 
 	lang = strings.ToLower(lang)
 
-	languageMap := map[string]string{
-
-		"js":          "js",
-		"jscript":     "js",
-		"javscript":   "js",
-		"javsscript":  "js",
-		"javascipt":   "js",
-		"javasript":   "js",
-		"javascript":  "js",
-		"java script": "js",
-		"jscipt":      "js",
-
-		"python":  "python",
-		"pyt":     "python",
-		"pyn":     "python",
-		"pythn":   "python",
-		"phyton":  "python",
-		"py":      "python",
-		"py thon": "python",
-		"pthon":   "python",
-
-		"go":      "go",
-		"golang":  "go",
-		"gol":     "go",
-		"goo":     "go",
-		"g o":     "go",
-		"golangg": "go",
-
-		"cpp":    "cpp",
-		"c++":    "cpp",
-		"cp":     "cpp",
-		"cppp":   "cpp",
-		"c plus": "cpp",
-		"cxx":    "cpp",
-		"cc":     "cpp",
-		"cpp ":   "cpp",
-	}
-
-	if normalized, ok := languageMap[lang]; ok {
+	if normalized, ok := languageAliases[lang]; ok {
 		return normalized
 	}
 
 	return lang
 }
+
+// LanguageAliases returns the spellings NormalizeLanguage turns into language, other than language itself (padded with
+// spaces or not), sorted
+func LanguageAliases(language string) []string {
+	aliases := []string{}
+	for alias, normalized := range languageAliases {
+		if normalized == language && strings.TrimSpace(alias) != language {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
@@ -0,0 +1,327 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+)
+
+const maxLeaderboardK = 100
+
+// rules holds, per RPC, the checks its request must pass. RPCs without an entry are not checked here; the challenge
+// RPCs are not implemented by this service.
+var rules = map[string]func(*Violations, any){
+	pb.ProblemsService_CreateProblem_FullMethodName: rule(func(v *Violations, req *pb.CreateProblemRequest) {
+		v.Text("title", req.GetTitle(), MaxTitleLength)
+		v.MaxLength("description", req.GetDescription(), MaxDescriptionLength)
+		if v.Required("difficulty", req.GetDifficulty()) {
+			v.Difficulty("difficulty", req.GetDifficulty())
+		}
+		v.Tags("tags", req.GetTags())
+	}),
+	pb.ProblemsService_UpdateProblem_FullMethodName: rule(func(v *Violations, req *pb.UpdateProblemRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		if req.Title != nil {
+			v.Text("title", req.GetTitle(), MaxTitleLength)
+		}
+		v.MaxLength("description", req.GetDescription(), MaxDescriptionLength)
+		v.Difficulty("difficulty", req.GetDifficulty())
+		v.Tags("tags", req.GetTags())
+	}),
+	pb.ProblemsService_DeleteProblem_FullMethodName: rule(func(v *Violations, req *pb.DeleteProblemRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+	}),
+	pb.ProblemsService_GetProblem_FullMethodName: rule(func(v *Violations, req *pb.GetProblemRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+	}),
+	pb.ProblemsService_ListProblems_FullMethodName: rule(func(v *Violations, req *pb.ListProblemsRequest) {
+		v.Page("page", int64(req.GetPage()), "page_size", int64(req.GetPageSize()), MaxPageSize)
+		v.Tags("tags", req.GetTags())
+		v.Difficulty("difficulty", req.GetDifficulty())
+		v.MaxLength("search_query", req.GetSearchQuery(), MaxSearchQueryLength)
+	}),
+	pb.ProblemsService_GetProblemByIDSlug_FullMethodName: rule(func(v *Violations, req *pb.GetProblemByIdSlugRequest) {
+		if req.ProblemId == "" && req.Slug == nil {
+			v.Add("problem_id", "problem_id or slug is required")
+			return
+		}
+		v.OptionalObjectID("problem_id", req.GetProblemId())
+		v.MaxLength("slug", req.GetSlug(), MaxTitleLength)
+	}),
+	pb.ProblemsService_GetProblemMetadataList_FullMethodName: rule(func(v *Violations, req *pb.GetProblemMetadataListRequest) {
+		v.Page("page", int64(req.GetPage()), "page_size", int64(req.GetPageSize()), MaxPageSize)
+		v.Tags("tags", req.GetTags())
+		v.Difficulty("difficulty", req.GetDifficulty())
+		v.MaxLength("search_query", req.GetSearchQuery(), MaxSearchQueryLength)
+	}),
+	pb.ProblemsService_AddTestCases_FullMethodName: rule(func(v *Violations, req *pb.AddTestCasesRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		testCases := req.GetTestcases()
+		count := len(testCases.GetRun()) + len(testCases.GetSubmit())
+		switch {
+		case count == 0:
+			v.Add("testcases", "is required")
+		case count > MaxTestCasesPerCall:
+			v.Add("testcases", fmt.Sprintf("must have at most %d test cases", MaxTestCasesPerCall))
+		default:
+			testCasePayloads(v, "testcases.run", testCases.GetRun())
+			testCasePayloads(v, "testcases.submit", testCases.GetSubmit())
+		}
+	}),
+	pb.ProblemsService_DeleteTestCase_FullMethodName: rule(func(v *Violations, req *pb.DeleteTestCaseRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		v.Text("testcase_id", req.GetTestcaseId(), MaxIDLength)
+	}),
+	pb.ProblemsService_GetLanguageSupports_FullMethodName: rule(func(v *Violations, req *pb.GetLanguageSupportsRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+	}),
+	pb.ProblemsService_AddLanguageSupport_FullMethodName: rule(func(v *Violations, req *pb.AddLanguageSupportRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		v.Language("language", req.GetLanguage())
+		validationCode(v, req.GetValidationCode())
+	}),
+	pb.ProblemsService_UpdateLanguageSupport_FullMethodName: rule(func(v *Violations, req *pb.UpdateLanguageSupportRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		v.Language("language", req.GetLanguage())
+		validationCode(v, req.GetValidationCode())
+	}),
+	pb.ProblemsService_RemoveLanguageSupport_FullMethodName: rule(func(v *Violations, req *pb.RemoveLanguageSupportRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		v.Language("language", req.GetLanguage())
+	}),
+	pb.ProblemsService_FullValidationByProblemID_FullMethodName: rule(func(v *Violations, req *pb.FullValidationByProblemIDRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+	}),
+	pb.ProblemsService_RunUserCodeProblem_FullMethodName: rule(func(v *Violations, req *pb.RunProblemRequest) {
+		v.ObjectID("problem_id", req.GetProblemId())
+		v.Language("language", req.GetLanguage())
+		v.Text("user_code", req.GetUserCode(), MaxCodeLength)
+		v.MaxLength("user_id", req.GetUserId(), MaxIDLength)
+		v.OptionalCountry("country", req.GetCountry())
+	}),
+	pb.ProblemsService_GetSubmissionsByOptionalProblemID_FullMethodName: rule(func(v *Violations, req *pb.GetSubmissionsRequest) {
+		v.OptionalObjectID("problem_id", req.GetProblemId())
+		v.Page("page", int64(req.GetPage()), "limit", int64(req.GetLimit()), MaxPageSize)
+		v.MaxLength("user_id", req.GetUserId(), MaxIDLength)
+	}),
+	pb.ProblemsService_GetSubmissionsByID_FullMethodName: rule(func(v *Violations, req *pb.GetSubmissionsByIDRequest) {
+		v.ObjectID("submission_id", req.GetSubmissionId())
+	}),
+	pb.ProblemsService_GetProblemsDoneStatistics_FullMethodName: rule(func(v *Violations, req *pb.GetProblemsDoneStatisticsRequest) {
+		v.MaxLength("user_id", req.GetUserId(), MaxIDLength)
+	}),
+	pb.ProblemsService_ForceChangeUserEntityInSubmission_FullMethodName: rule(func(v *Violations, req *pb.ForceChangeUserEntityInSubmissionRequest) {
+		v.Text("user_id", req.GetUserId(), MaxIDLength)
+		v.Country("entity", req.GetEntity())
+	}),
+	pb.ProblemsService_GetMonthlyActivityHeatmap_FullMethodName: rule(func(v *Violations, req *pb.GetMonthlyActivityHeatmapRequest) {
+		v.Text("userID", req.GetUserID(), MaxIDLength)
+		// zero month or year asks for the current month
+		if month := req.GetMonth(); month < 0 || month > 12 {
+			v.Add("month", "must be between 1 and 12")
+		}
+		if year := req.GetYear(); year != 0 && (year < 1970 || year > 9999) {
+			v.Add("year", "must be between 1970 and 9999")
+		}
+	}),
+	pb.ProblemsService_GetTopKGlobal_FullMethodName: rule(func(v *Violations, req *pb.GetTopKGlobalRequest) {
+		leaderboardK(v, req.GetK())
+	}),
+	pb.ProblemsService_GetTopKEntity_FullMethodName: rule(func(v *Violations, req *pb.GetTopKEntityRequest) {
+		v.Country("entity", req.GetEntity())
+	}),
+	pb.ProblemsService_GetUserRank_FullMethodName: rule(func(v *Violations, req *pb.GetUserRankRequest) {
+		v.Text("user_id", req.GetUserId(), MaxIDLength)
+	}),
+	pb.ProblemsService_GetLeaderboardData_FullMethodName: rule(func(v *Violations, req *pb.GetLeaderboardDataRequest) {
+		v.Text("user_id", req.GetUserId(), MaxIDLength)
+	}),
+	pb.ProblemsService_GetBulkProblemMetadata_FullMethodName: rule(func(v *Violations, req *pb.GetBulkProblemMetadataRequest) {
+		v.ObjectIDs("problem_ids", req.GetProblemIds(), MaxBulkIDs)
+	}),
+	pb.ProblemsService_CheckProblemExistenceBulk_FullMethodName: rule(func(v *Violations, req *pb.CheckProblemExistenceBulkRequest) {
+		v.ObjectIDs("problem_ids", req.GetProblemIds(), MaxBulkIDs)
+	}),
+	pb.ProblemsService_ProblemIDsDoneByUserID_FullMethodName: rule(func(v *Violations, req *pb.ProblemIDsDoneByUserIDRequest) {
+		v.Text("user_id", req.GetUserId(), MaxIDLength)
+	}),
+}
+
+// modelRules holds, per request type, the checks of the requests of package model. Most of their methods are not
+// served over gRPC, so the interceptor never sees them and their handlers call Model instead. Fields are named as
+// in the JSON of the request.
+var modelRules = map[reflect.Type]func(*Violations, any){
+	reflect.TypeFor[*model.ShareSubmissionRequest](): rule(func(v *Violations, req *model.ShareSubmissionRequest) {
+		v.ObjectID("submissionId", req.SubmissionID)
+		v.Text("userId", req.UserID, MaxIDLength)
+		// zero leaves the expiry to the handler's default
+		if req.ExpiresInHours < 0 || req.ExpiresInHours > MaxShareExpiryHours {
+			v.Add("expiresInHours", fmt.Sprintf("must be between 1 and %d", MaxShareExpiryHours))
+		}
+	}),
+	reflect.TypeFor[*model.CreateContestRequest](): rule(func(v *Violations, req *model.CreateContestRequest) {
+		v.Text("adminId", req.AdminID, MaxIDLength)
+		contest(v, "contest", req.Contest)
+	}),
+	reflect.TypeFor[*model.SaveCodeDraftRequest](): rule(func(v *Violations, req *model.SaveCodeDraftRequest) {
+		v.Text("userId", req.UserID, MaxIDLength)
+		v.ObjectID("problemId", req.ProblemID)
+		v.Language("language", req.Language)
+		v.MaxLength("code", req.Code, MaxCodeLength)
+	}),
+	reflect.TypeFor[*model.GetCodeDraftRequest](): rule(func(v *Violations, req *model.GetCodeDraftRequest) {
+		v.Text("userId", req.UserID, MaxIDLength)
+		v.ObjectID("problemId", req.ProblemID)
+		v.Language("language", req.Language)
+	}),
+	reflect.TypeFor[*model.RegisterWebhookRequest](): rule(func(v *Violations, req *model.RegisterWebhookRequest) {
+		v.Text("adminId", req.AdminID, MaxIDLength)
+		if v.Required("url", req.URL) {
+			endpoint, err := url.Parse(strings.TrimSpace(req.URL))
+			if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+				v.Add("url", "must be an absolute http or https URL")
+			}
+		}
+		if len(req.EventTypes) == 0 {
+			v.Add("eventTypes", "is required")
+		}
+		for i, eventType := range req.EventTypes {
+			if !slices.Contains(model.WebhookEventTypes, eventType) {
+				v.Add(fmt.Sprintf("eventTypes[%d]", i), "must be one of "+strings.Join(model.WebhookEventTypes, ", "))
+			}
+		}
+		v.MaxLength("description", strings.TrimSpace(req.Description), MaxWebhookDescriptionLength)
+	}),
+	reflect.TypeFor[*model.ListWebhooksRequest](): rule(func(v *Violations, req *model.ListWebhooksRequest) {
+		v.Text("adminId", req.AdminID, MaxIDLength)
+	}),
+	reflect.TypeFor[*model.DeleteWebhookRequest](): rule(func(v *Violations, req *model.DeleteWebhookRequest) {
+		v.Text("adminId", req.AdminID, MaxIDLength)
+		v.ObjectID("webhookId", req.WebhookID)
+	}),
+	reflect.TypeFor[*model.ListWebhookDeliveriesRequest](): rule(func(v *Violations, req *model.ListWebhookDeliveriesRequest) {
+		v.Text("adminId", req.AdminID, MaxIDLength)
+		v.ObjectID("webhookId", req.WebhookID)
+		switch req.Status {
+		case "", model.WebhookDeliveryPending, model.WebhookDeliveryDelivered, model.WebhookDeliveryFailed:
+		default:
+			v.Add("status", "must be PENDING, DELIVERED or FAILED")
+		}
+	}),
+}
+
+// rule adapts a check of one request type to the rules map; a request of another type is left unchecked
+func rule[T any](check func(*Violations, T)) func(*Violations, any) {
+	return func(v *Violations, req any) {
+		if typed, ok := req.(T); ok {
+			check(v, typed)
+		}
+	}
+}
+
+// Request checks req against the rules of fullMethod and returns every violation as one InvalidArgument error, or
+// nil when the request is valid or the RPC has no rules
+func Request(fullMethod string, req any) error {
+	check, ok := rules[fullMethod]
+	if !ok {
+		return nil
+	}
+	var v Violations
+	check(&v, req)
+	return v.Err()
+}
+
+// Model checks a request of package model against the rules of its type and returns every violation as one
+// InvalidArgument error, or nil when the request is valid or its type has no rules
+func Model(req any) error {
+	check, ok := modelRules[reflect.TypeOf(req)]
+	if !ok {
+		return nil
+	}
+	var v Violations
+	check(&v, req)
+	return v.Err()
+}
+
+func testCasePayloads(v *Violations, field string, testCases []*pb.TestCase) {
+	for i, testCase := range testCases {
+		name := fmt.Sprintf("%s[%d]", field, i)
+		v.Text(name+".input", testCase.GetInput(), MaxTestCaseLength)
+		v.Text(name+".expected", testCase.GetExpected(), MaxTestCaseLength)
+	}
+}
+
+func validationCode(v *Violations, code *pb.ValidationCode) {
+	if code == nil {
+		v.Add("validation_code", "is required")
+		return
+	}
+	v.Text("validation_code.code", code.GetCode(), MaxCodeLength)
+	v.Text("validation_code.template", code.GetTemplate(), MaxCodeLength)
+	v.MaxLength("validation_code.placeholder", code.GetPlaceholder(), MaxCodeLength)
+}
+
+func leaderboardK(v *Violations, k int32) {
+	if k < 0 || k > maxLeaderboardK {
+		v.Add("k", fmt.Sprintf("must be between 1 and %d", maxLeaderboardK))
+	}
+}
+
+// contest checks the schedule, the problem set and the divisions of a new contest. No divisions is fine, the
+// handler puts everyone in the default one.
+func contest(v *Violations, field string, contest model.Contest) {
+	v.Text(field+".title", contest.Title, MaxTitleLength)
+	v.MaxLength(field+".description", contest.Description, MaxDescriptionLength)
+
+	times := []struct {
+		name string
+		at   time.Time
+	}{
+		{"registrationOpensAt", contest.RegistrationOpensAt},
+		{"registrationClosesAt", contest.RegistrationClosesAt},
+		{"startsAt", contest.StartsAt},
+		{"endsAt", contest.EndsAt},
+	}
+	scheduled := true
+	for _, t := range times {
+		if t.at.IsZero() {
+			v.Add(field+"."+t.name, "is required")
+			scheduled = false
+		}
+	}
+	if scheduled {
+		if !contest.RegistrationOpensAt.Before(contest.RegistrationClosesAt) {
+			v.Add(field+".registrationClosesAt", "must be after registrationOpensAt")
+		}
+		if contest.RegistrationClosesAt.After(contest.StartsAt) {
+			v.Add(field+".registrationClosesAt", "must not be after startsAt")
+		}
+		if !contest.StartsAt.Before(contest.EndsAt) {
+			v.Add(field+".endsAt", "must be after startsAt")
+		}
+	}
+
+	v.ObjectIDs(field+".problemIds", contest.ProblemIDs, MaxContestProblems)
+	for i, problemID := range contest.ProblemIDs {
+		if slices.Index(contest.ProblemIDs, problemID) < i {
+			v.Add(fmt.Sprintf("%s.problemIds[%d]", field, i), "repeats an earlier problem")
+		}
+	}
+
+	for i, division := range contest.Divisions {
+		name := fmt.Sprintf("%s.divisions[%d]", field, i)
+		if v.Required(name+".name", division.Name) && slices.IndexFunc(contest.Divisions, func(d model.ContestDivision) bool { return d.Name == division.Name }) < i {
+			v.Add(name+".name", "repeats an earlier division")
+		}
+		if division.MinRating < 0 || (division.MaxRating != 0 && division.MaxRating <= division.MinRating) {
+			v.Add(name, "has an empty rating range")
+		}
+	}
+}
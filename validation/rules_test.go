@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"xcode/model"
+
+	pb "github.com/lijuuu/GlobalProtoXcode/ProblemsService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testObjectID = "64b7f0c2a1b2c3d4e5f60718"
+
+// violatedFields returns the fields named in the BadRequest details of err, nil when err is nil
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", code)
+	}
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+func TestRules(t *testing.T) {
+	opens := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	contest := func(edit func(*model.Contest)) *model.CreateContestRequest {
+		c := model.Contest{
+			Title:                "Weekly 1",
+			RegistrationOpensAt:  opens,
+			RegistrationClosesAt: opens.Add(time.Hour),
+			StartsAt:             opens.Add(time.Hour),
+			EndsAt:               opens.Add(3 * time.Hour),
+			ProblemIDs:           []string{testObjectID},
+		}
+		if edit != nil {
+			edit(&c)
+		}
+		return &model.CreateContestRequest{AdminID: "admin", Contest: c}
+	}
+	webhook := func(edit func(*model.RegisterWebhookRequest)) *model.RegisterWebhookRequest {
+		req := &model.RegisterWebhookRequest{AdminID: "admin", URL: "https://example.com/hook", EventTypes: []string{model.WebhookEventTypes[0]}}
+		if edit != nil {
+			edit(req)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		fullMethod string // empty for the requests of package model, checked with Model
+		req        any
+		wantFields []string
+	}{
+		{name: "rpc valid", fullMethod: pb.ProblemsService_GetProblem_FullMethodName, req: &pb.GetProblemRequest{ProblemId: testObjectID}},
+		{name: "rpc bad ID", fullMethod: pb.ProblemsService_GetProblem_FullMethodName, req: &pb.GetProblemRequest{ProblemId: "42"}, wantFields: []string{"problem_id"}},
+		{
+			name:       "rpc page too large",
+			fullMethod: pb.ProblemsService_ListProblems_FullMethodName,
+			req:        &pb.ListProblemsRequest{PageSize: MaxPageSize + 1, Difficulty: "impossible"},
+			wantFields: []string{"page_size", "difficulty"},
+		},
+		{name: "rpc without rules", fullMethod: "/unknown/Method", req: &pb.GetProblemRequest{}},
+
+		{name: "share valid", req: &model.ShareSubmissionRequest{SubmissionID: testObjectID, UserID: "user-1"}},
+		{name: "share missing fields", req: &model.ShareSubmissionRequest{}, wantFields: []string{"submissionId", "userId"}},
+		{
+			name:       "share expiry too long",
+			req:        &model.ShareSubmissionRequest{SubmissionID: testObjectID, UserID: "user-1", ExpiresInHours: MaxShareExpiryHours + 1},
+			wantFields: []string{"expiresInHours"},
+		},
+		{
+			name:       "share negative expiry",
+			req:        &model.ShareSubmissionRequest{SubmissionID: testObjectID, UserID: "user-1", ExpiresInHours: -1},
+			wantFields: []string{"expiresInHours"},
+		},
+
+		{name: "contest valid", req: contest(nil)},
+		{
+			name:       "contest without admin or schedule",
+			req:        &model.CreateContestRequest{Contest: model.Contest{Title: "Weekly 1", ProblemIDs: []string{testObjectID}}},
+			wantFields: []string{"adminId", "contest.registrationOpensAt", "contest.registrationClosesAt", "contest.startsAt", "contest.endsAt"},
+		},
+		{
+			name: "contest out of order",
+			req: contest(func(c *model.Contest) {
+				c.RegistrationClosesAt = c.EndsAt
+				c.EndsAt = c.StartsAt
+			}),
+			wantFields: []string{"contest.registrationClosesAt", "contest.endsAt"},
+		},
+		{
+			name:       "contest repeats a problem",
+			req:        contest(func(c *model.Contest) { c.ProblemIDs = []string{testObjectID, testObjectID} }),
+			wantFields: []string{"contest.problemIds[1]"},
+		},
+		{
+			name:       "contest without problems",
+			req:        contest(func(c *model.Contest) { c.ProblemIDs = nil }),
+			wantFields: []string{"contest.problemIds"},
+		},
+		{
+			name: "contest divisions",
+			req: contest(func(c *model.Contest) {
+				c.Divisions = []model.ContestDivision{{Name: "div1", MinRating: 1900}, {Name: "div1"}, {Name: "div2", MinRating: 1400, MaxRating: 1400}}
+			}),
+			wantFields: []string{"contest.divisions[1].name", "contest.divisions[2]"},
+		},
+
+		{name: "draft valid", req: &model.SaveCodeDraftRequest{UserID: "user-1", ProblemID: testObjectID, Language: "go", Code: "package main"}},
+		{
+			name:       "draft bad language and too long",
+			req:        &model.SaveCodeDraftRequest{UserID: "user-1", ProblemID: testObjectID, Language: "golang", Code: strings.Repeat("x", MaxCodeLength+1)},
+			wantFields: []string{"language", "code"},
+		},
+		{name: "get draft missing fields", req: &model.GetCodeDraftRequest{}, wantFields: []string{"userId", "problemId", "language"}},
+
+		{name: "webhook valid", req: webhook(nil)},
+		{
+			name: "webhook bad URL and event",
+			req: webhook(func(r *model.RegisterWebhookRequest) {
+				r.URL = "ftp://example.com"
+				r.EventTypes = []string{"problem.eaten"}
+			}),
+			wantFields: []string{"url", "eventTypes[0]"},
+		},
+		{
+			name:       "webhook without events",
+			req:        webhook(func(r *model.RegisterWebhookRequest) { r.EventTypes = nil }),
+			wantFields: []string{"eventTypes"},
+		},
+		{
+			name: "webhook description too long",
+			req: webhook(func(r *model.RegisterWebhookRequest) {
+				r.Description = strings.Repeat("x", MaxWebhookDescriptionLength+1)
+			}),
+			wantFields: []string{"description"},
+		},
+		{name: "delete webhook bad ID", req: &model.DeleteWebhookRequest{AdminID: "admin", WebhookID: "hook"}, wantFields: []string{"webhookId"}},
+		{
+			name:       "webhook deliveries bad status",
+			req:        &model.ListWebhookDeliveriesRequest{AdminID: "admin", WebhookID: testObjectID, Status: "LOST"},
+			wantFields: []string{"status"},
+		},
+		{name: "model request without rules", req: &model.GetContestRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.fullMethod != "" {
+				err = Request(tt.fullMethod, tt.req)
+			} else {
+				err = Model(tt.req)
+			}
+			if fields := violatedFields(t, err); !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("violated fields = %q, want %q", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Package validation checks requests before they reach a handler. Every rule that fails is collected, so the caller
+// gets one InvalidArgument error whose BadRequest details name each violating field instead of the first one found.
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"xcode/customerrors"
+	"xcode/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// Limits on request fields. Text limits are in bytes, which is what MongoDB and the execution engine pay for.
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 20000
+	MaxSearchQueryLength = 100
+	MaxTags              = 10
+	MaxTagLength         = 32
+	MaxIDLength          = 128 // user IDs, test case IDs and anything else minted outside this service
+	MaxPageSize          = 100
+	MaxBulkIDs           = 100
+	MaxCodeLength        = 64 << 10 // user code and every part of a language's validation code
+	MaxTestCaseLength    = 64 << 10 // the input and the expected output of one test case
+	MaxTestCasesPerCall  = 100
+
+	MaxShareExpiryHours         = 30 * 24 // a shared submission link lasts at most 30 days
+	MaxContestProblems          = 26      // one per letter, A to Z
+	MaxWebhookDescriptionLength = 200
+)
+
+// Languages are the language codes problems are written in. Handlers look templates up by code, so aliases such as
+// "golang" are rejected rather than normalized.
+var Languages = []string{"cpp", "go", "java", "js", "python"}
+
+// Difficulties are the problem difficulties, compared case-insensitively. Problems were stored with both the short
+// and the long spelling, so both are accepted.
+var Difficulties = []string{"EASY", "MEDIUM", "HARD", "E", "M", "H"}
+
+// Violations collects the fields of one request that break a rule
+type Violations struct {
+	fields []*errdetails.BadRequest_FieldViolation
+}
+
+// Add records that field breaks the rule in description, e.g. Add("page_size", "must be at most 100")
+func (v *Violations) Add(field, description string) {
+	v.fields = append(v.fields, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// Required records a violation when value is blank and reports whether it was set
+func (v *Violations) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// MaxLength records a violation when value is longer than max bytes
+func (v *Violations) MaxLength(field, value string, max int) {
+	if len(value) > max {
+		v.Add(field, fmt.Sprintf("must be at most %d bytes", max))
+	}
+}
+
+// Text requires value and caps its length
+func (v *Violations) Text(field, value string, max int) {
+	if v.Required(field, value) {
+		v.MaxLength(field, value, max)
+	}
+}
+
+// ObjectID requires value to be a 24 character hex MongoDB ID
+func (v *Violations) ObjectID(field, value string) {
+	if v.Required(field, value) {
+		v.OptionalObjectID(field, value)
+	}
+}
+
+// OptionalObjectID checks value is a MongoDB ID when it is set
+func (v *Violations) OptionalObjectID(field, value string) {
+	if value != "" && !primitive.IsValidObjectID(value) {
+		v.Add(field, "must be a 24 character hex ID")
+	}
+}
+
+// ObjectIDs checks every ID and caps how many there are; at least one is required
+func (v *Violations) ObjectIDs(field string, values []string, max int) {
+	switch {
+	case len(values) == 0:
+		v.Add(field, "is required")
+	case len(values) > max:
+		v.Add(field, fmt.Sprintf("must have at most %d entries", max))
+	default:
+		for i, value := range values {
+			v.ObjectID(fmt.Sprintf("%s[%d]", field, i), value)
+		}
+	}
+}
+
+// Language requires value to be one of Languages
+func (v *Violations) Language(field, value string) {
+	if v.Required(field, value) && !slices.Contains(Languages, value) {
+		v.Add(field, "must be one of "+strings.Join(Languages, ", "))
+	}
+}
+
+// Difficulty checks value is one of Difficulties when it is set
+func (v *Violations) Difficulty(field, value string) {
+	if value != "" && !slices.Contains(Difficulties, strings.ToUpper(value)) {
+		v.Add(field, "must be one of EASY, MEDIUM, HARD")
+	}
+}
+
+// Country requires value to name a country, see utils.NormalizeCountry
+func (v *Violations) Country(field, value string) {
+	if v.Required(field, value) {
+		v.OptionalCountry(field, value)
+	}
+}
+
+// OptionalCountry checks value names a country when it is set
+func (v *Violations) OptionalCountry(field, value string) {
+	if _, ok := utils.NormalizeCountry(value); value != "" && !ok {
+		v.Add(field, "must be an ISO 3166-1 country code")
+	}
+}
+
+// Tags caps how many tags there are and how long each is
+func (v *Violations) Tags(field string, tags []string) {
+	if len(tags) > MaxTags {
+		v.Add(field, fmt.Sprintf("must have at most %d entries", MaxTags))
+		return
+	}
+	for i, tag := range tags {
+		v.Text(fmt.Sprintf("%s[%d]", field, i), tag, MaxTagLength)
+	}
+}
+
+// Page checks a page number and size. Zero leaves either to the handler's default; negative values and sizes above
+// maxSize are rejected.
+func (v *Violations) Page(pageField string, page int64, sizeField string, size, maxSize int64) {
+	if page < 0 {
+		v.Add(pageField, "must not be negative")
+	}
+	switch {
+	case size < 0:
+		v.Add(sizeField, "must not be negative")
+	case size > maxSize:
+		v.Add(sizeField, fmt.Sprintf("must be at most %d", maxSize))
+	}
+}
+
+// Err returns nil when nothing was violated, or else an InvalidArgument error naming the violating fields, with
+// each field and the rule it broke in its BadRequest details
+func (v *Violations) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(v.fields))
+	for _, field := range v.fields {
+		if !slices.Contains(names, field.Field) {
+			names = append(names, field.Field)
+		}
+	}
+	return customerrors.Status(codes.InvalidArgument, "VALIDATION_ERROR",
+		fmt.Sprintf("invalid fields: %s", strings.Join(names, ", ")), nil,
+		&errdetails.BadRequest{FieldViolations: v.fields})
+}